@@ -1,8 +1,17 @@
+// Package bfloat implements encoding and decoding of bfloat16 floating-point
+// numbers.
+//
+// https://en.wikipedia.org/wiki/Bfloat16_floating-point_format
 package bfloat
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/rounding"
 )
 
 const (
@@ -13,6 +22,22 @@ const (
 	bias = 127
 )
 
+// Positive and negative Not-a-Number, infinity and zero.
+var (
+	// +NaN
+	NaN = Float{bits: 0x7FC0}
+	// -NaN
+	NegNaN = Float{bits: 0xFFC0}
+	// +Inf
+	Inf = Float{bits: 0x7F80}
+	// -Inf
+	NegInf = Float{bits: 0xFF80}
+	// +zero
+	Zero = Float{bits: 0x0000}
+	// -zero
+	NegZero = Float{bits: 0x8000}
+)
+
 // Float is a floating-point number in bfloat16 floating-point format.
 type Float struct {
 	// Sign, exponent and fraction.
@@ -23,10 +48,308 @@ type Float struct {
 	bits uint16
 }
 
+// NewFromBits returns the floating-point number corresponding to the bfloat16
+// binary representation.
 func NewFromBits(bits uint16) Float {
 	return Float{bits: bits}
 }
 
+// NewFromFloat32 returns the nearest bfloat16 floating-point number for x and
+// the accuracy of the conversion, using round half to even. Not-a-Number values
+// are converted to a quiet NaN with the same sign, keeping the most significant
+// bits of the payload.
+func NewFromFloat32(x float32) (Float, big.Accuracy) {
+	// +-NaN
+	if x != x {
+		return newNaN(payload.FromFloat32(x)), big.Exact
+	}
+	// bfloat16 has the same exponent range as float32, so rounding the upper
+	// 16 bits of the float32 representation also handles denormalized numbers,
+	// and a carry out of the fraction into the exponent overflows to +-Inf.
+	bits := math.Float32bits(x)
+	lsb := bits >> 16 & 1
+	rounded := (bits + 0x7FFF + lsb) &^ 0xFFFF
+	return Float{bits: uint16(rounded >> 16)}, accuracy(bits, rounded)
+}
+
+// NewFromFloat32Trunc returns the bfloat16 floating-point number for x with
+// the lower 16 bits of the float32 representation discarded (rounding toward
+// zero), and the accuracy of the conversion. This is the conversion commonly
+// used by machine learning frameworks where speed matters more than accuracy.
+// Not-a-Number values are converted to a quiet NaN with the same sign, keeping
+// the most significant bits of the payload.
+func NewFromFloat32Trunc(x float32) (Float, big.Accuracy) {
+	// +-NaN
+	if x != x {
+		return newNaN(payload.FromFloat32(x)), big.Exact
+	}
+	bits := math.Float32bits(x)
+	truncated := bits &^ 0xFFFF
+	return Float{bits: uint16(truncated >> 16)}, accuracy(bits, truncated)
+}
+
+// accuracy returns the accuracy of rounding the non-NaN float32 representation
+// bits to result.
+func accuracy(bits, result uint32) big.Accuracy {
+	acc := big.Exact
+	switch {
+	case result&0x7FFFFFFF > bits&0x7FFFFFFF:
+		acc = big.Above
+	case result&0x7FFFFFFF < bits&0x7FFFFFFF:
+		acc = big.Below
+	}
+	if bits&0x80000000 != 0 {
+		return -acc
+	}
+	return acc
+}
+
+// NewFromFloat64 returns the nearest bfloat16 floating-point number for x and
+// the accuracy of the conversion. Not-a-Number values are converted to a quiet
+// NaN with the same sign, keeping the most significant bits of the payload.
+func NewFromFloat64(x float64) (Float, big.Accuracy) {
+	return NewFromFloat64Mode(x, big.ToNearestEven)
+}
+
+// NewFromBig returns the nearest bfloat16 floating-point number for x and the
+// accuracy of the conversion.
+func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromFloat32Mode returns the bfloat16 floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion.
+func NewFromFloat32Mode(x float32, mode big.RoundingMode) (Float, big.Accuracy) {
+	// +-NaN, with the payload of the binary representation of x; see package
+	// payload.
+	if x != x {
+		return newNaN(payload.FromFloat32(x)), big.Exact
+	}
+	return NewFromFloat64Mode(float64(x), mode)
+}
+
+// NewFromFloat64Mode returns the bfloat16 floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion.
+func NewFromFloat64Mode(x float64, mode big.RoundingMode) (Float, big.Accuracy) {
+	// +-NaN
+	if math.IsNaN(x) {
+		return newNaN(payload.FromFloat64(x)), big.Exact
+	}
+	// Rounding through float32 would round twice, so use big.Float.
+	return NewFromBigMode(big.NewFloat(x), mode)
+}
+
+// NewFromBigMode returns the bfloat16 floating-point number for x rounded in
+// the given rounding mode, and the accuracy of the conversion. Values too large
+// in magnitude are converted to +-Inf, or to the largest finite number of the
+// same sign if the mode rounds them towards zero.
+func NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
+	// +-Inf
+	zero := big.NewFloat(0)
+	switch {
+	case x.IsInf():
+		if x.Signbit() {
+			// -Inf
+			return NegInf, big.Exact
+		}
+		// +Inf
+		return Inf, big.Exact
+	// +-zero
+	case x.Cmp(zero) == 0:
+		if x.Signbit() {
+			// -zero
+			return NegZero, big.Exact
+		}
+		// +zero
+		return Zero, big.Exact
+	}
+
+	// Sign
+	var bits uint16
+	if x.Signbit() {
+		bits |= 0x8000
+	}
+
+	// Round to the precision available at the exponent of x; denormalized
+	// numbers have fewer significant bits.
+	const emin = 1 - bias
+	exp := x.MantExp(nil) - 1
+	prec := precision
+	if exp < emin {
+		prec -= emin - exp
+	}
+	abs := new(big.Float).Abs(x)
+	if prec < 1 {
+		// |x| is less than the smallest denormalized number, 2^(-133); round
+		// to zero or to the smallest denormalized number.
+		half := big.NewFloat(math.Ldexp(1, emin-precision))
+		if rounding.Up(mode, x.Signbit(), false, abs.Cmp(half)) {
+			bits |= 0x0001
+			return Float{bits: bits}, signAcc(x.Signbit(), big.Above)
+		}
+		return Float{bits: bits}, signAcc(x.Signbit(), big.Below)
+	}
+	// Round the magnitude of x.
+	y := new(big.Float).SetMode(rounding.Mode(mode, x.Signbit())).SetPrec(uint(prec))
+	y.Set(abs)
+	acc := signAcc(x.Signbit(), y.Acc())
+
+	// Exponent and mantissa.
+	mant := new(big.Float)
+	exp = y.MantExp(mant) - 1
+	switch {
+	case exp > bias:
+		// Overflow to +-Inf, or to the largest finite number in directed
+		// rounding modes.
+		if !rounding.Inf(mode, x.Signbit()) {
+			bits |= 0x7F7F
+			return Float{bits: bits}, signAcc(x.Signbit(), big.Below)
+		}
+		bits |= 0x7F80
+		return Float{bits: bits}, signAcc(x.Signbit(), big.Above)
+	case exp < emin:
+		// Denormalized number.
+		//
+		//    2^(-126) * 0.mant_2
+		mant.SetMantExp(mant, exp-emin+precision)
+	default:
+		// Normalized number; the lead bit is carried into the exponent.
+		//
+		//    2^(exp-127) * 1.mant_2
+		mant.SetMantExp(mant, precision)
+		bits |= uint16(exp+bias-1) << 7
+	}
+	mantissa, _ := mant.Uint64()
+	bits += uint16(mantissa)
+	return Float{bits: bits}, acc
+}
+
+// signAcc returns the accuracy acc of the magnitude of a number, adjusted for
+// the sign of the number.
+func signAcc(signbit bool, acc big.Accuracy) big.Accuracy {
+	if signbit {
+		return -acc
+	}
+	return acc
+}
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised. Conversions
+// from bfloat16 are exact, and are performed without a context.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// NewFromFloat32 returns the nearest bfloat16 floating-point number for x and
+// the accuracy of the conversion, as for NewFromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewFromFloat32(x float32) (Float, big.Accuracy) {
+	f, acc := NewFromFloat32(x)
+	ctx.float32Flags(x, acc, big.ToNearestEven)
+	return f, acc
+}
+
+// NewFromFloat32Trunc returns the bfloat16 floating-point number for x rounded
+// toward zero and the accuracy of the conversion, as for NewFromFloat32Trunc.
+// The exception flags raised are recorded in ctx; a signaling NaN raises the
+// invalid operation exception.
+func (ctx *Context) NewFromFloat32Trunc(x float32) (Float, big.Accuracy) {
+	f, acc := NewFromFloat32Trunc(x)
+	ctx.float32Flags(x, acc, big.ToZero)
+	return f, acc
+}
+
+// NewFromFloat64 returns the nearest bfloat16 floating-point number for x and
+// the accuracy of the conversion. The exception flags raised are recorded in
+// ctx; a signaling NaN raises the invalid operation exception.
+func (ctx *Context) NewFromFloat64(x float64) (Float, big.Accuracy) {
+	return ctx.NewFromFloat64Mode(x, big.ToNearestEven)
+}
+
+// NewFromBig returns the nearest bfloat16 floating-point number for x and the
+// accuracy of the conversion. The exception flags raised are recorded in ctx.
+func (ctx *Context) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return ctx.NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromFloat32Mode returns the bfloat16 floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion. The exception
+// flags raised are recorded in ctx; a signaling NaN raises the invalid
+// operation exception.
+func (ctx *Context) NewFromFloat32Mode(x float32, mode big.RoundingMode) (Float, big.Accuracy) {
+	f, acc := NewFromFloat32Mode(x, mode)
+	ctx.float32Flags(x, acc, mode)
+	return f, acc
+}
+
+// NewFromFloat64Mode returns the bfloat16 floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion. The exception
+// flags raised are recorded in ctx; a signaling NaN raises the invalid
+// operation exception.
+func (ctx *Context) NewFromFloat64Mode(x float64, mode big.RoundingMode) (Float, big.Accuracy) {
+	if math.IsNaN(x) && math.Float64bits(x)&0x0008000000000000 == 0 {
+		// Signaling NaN.
+		ctx.Flags |= fenv.Invalid
+	}
+	f, acc := NewFromFloat64Mode(x, mode)
+	if acc != big.Exact {
+		ctx.Flags |= rounding.Flags(big.NewFloat(x), acc, precision, 1-bias, bias, mode, ctx.Tininess)
+	}
+	return f, acc
+}
+
+// NewFromBigMode returns the bfloat16 floating-point number for x rounded in
+// the given rounding mode, and the accuracy of the conversion, as for
+// NewFromBigMode. The exception flags raised are recorded in ctx.
+func (ctx *Context) NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
+	f, acc := NewFromBigMode(x, mode)
+	ctx.Flags |= rounding.Flags(x, acc, precision, 1-bias, bias, mode, ctx.Tininess)
+	return f, acc
+}
+
+// float32Flags records in ctx the exception flags raised by the conversion of
+// x in the given rounding mode, with the accuracy acc.
+func (ctx *Context) float32Flags(x float32, acc big.Accuracy, mode big.RoundingMode) {
+	switch {
+	case math.IsNaN(float64(x)):
+		if payload.Signaling32(x) {
+			ctx.Flags |= fenv.Invalid
+		}
+	case acc != big.Exact:
+		ctx.Flags |= rounding.Flags(big.NewFloat(float64(x)), acc, precision, 1-bias, bias, mode, ctx.Tininess)
+	}
+}
+
+// Bits returns the bfloat16 binary representation of f.
+func (f Float) Bits() uint16 {
+	return f.bits
+}
+
+// Float32 returns the float32 representation of f. The conversion is always
+// exact, as bfloat16 numbers are float32 numbers with the lower 16 bits of the
+// fraction cleared; Not-a-Number values keep their sign and payload.
+func (f Float) Float32() (float32, big.Accuracy) {
+	return math.Float32frombits(uint32(f.bits) << 16), big.Exact
+}
+
+// Float64 returns the float64 representation of f. The conversion is always
+// exact; Not-a-Number values are converted to a quiet NaN with the same sign
+// and payload.
+func (f Float) Float64() (float64, big.Accuracy) {
+	if f.isNaN() {
+		return payload.Float64(f.Signbit(), uint64(f.Frac())<<57), big.Exact
+	}
+	x, _ := f.Float32()
+	return float64(x), big.Exact
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number.
 func (f Float) Big() (x *big.Float, nan bool) {
 	signbit := f.Signbit()
 	exp := f.Exp()
@@ -2,7 +2,11 @@ package bfloat
 
 import (
 	"math"
+	"math/big"
+	"math/rand"
 	"testing"
+
+	"github.com/mewmew/float/fenv"
 )
 
 func TestNewFromBits(t *testing.T) {
@@ -45,3 +49,285 @@ func TestNewFromBits(t *testing.T) {
 		}
 	}
 }
+
+func TestNewFromFloat32(t *testing.T) {
+	golden := []struct {
+		in    float32
+		want  uint16
+		acc   big.Accuracy
+		trunc uint16
+	}{
+		// Special numbers.
+		{in: float32(math.NaN()), want: 0x7FC0, acc: big.Exact, trunc: 0x7FC0},
+		{in: float32(math.Inf(1)), want: 0x7F80, acc: big.Exact, trunc: 0x7F80},
+		{in: float32(math.Inf(-1)), want: 0xFF80, acc: big.Exact, trunc: 0xFF80},
+		{in: float32(math.Copysign(0, -1)), want: 0x8000, acc: big.Exact, trunc: 0x8000},
+		// 1 = 0x3F800000
+		{in: 1, want: 0x3F80, acc: big.Exact, trunc: 0x3F80},
+		// pi = 0x40490FDB
+		{in: math.Pi, want: 0x4049, acc: big.Below, trunc: 0x4049},
+		// -1/3 = 0xBEAAAAAB
+		{in: -1. / 3, want: 0xBEAB, acc: big.Below, trunc: 0xBEAA},
+		// 1 + 2^(-8) = 0x3F808000 (tie, round to even)
+		{in: 1 + 1./256, want: 0x3F80, acc: big.Below, trunc: 0x3F80},
+		// 1 + 3*2^(-8) = 0x3F818000 (tie, round to even)
+		{in: 1 + 3./256, want: 0x3F82, acc: big.Above, trunc: 0x3F81},
+		// max float32 = 0x7F7FFFFF
+		{in: math.MaxFloat32, want: 0x7F80, acc: big.Above, trunc: 0x7F7F},
+		// -max float32 = 0xFF7FFFFF
+		{in: -math.MaxFloat32, want: 0xFF80, acc: big.Below, trunc: 0xFF7F},
+		// smallest denormalized float32 = 0x00000001
+		{in: math.SmallestNonzeroFloat32, want: 0x0000, acc: big.Below, trunc: 0x0000},
+		// largest denormalized float32 = 0x007FFFFF
+		{in: math.Float32frombits(0x007FFFFF), want: 0x0080, acc: big.Above, trunc: 0x007F},
+		// 2^(-133) = 0x00010000 (smallest denormalized bfloat16)
+		{in: math.Float32frombits(0x00010000), want: 0x0001, acc: big.Exact, trunc: 0x0001},
+	}
+	for _, g := range golden {
+		got, acc := NewFromFloat32(g.in)
+		if g.want != got.Bits() {
+			t.Errorf("%v: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+		got, _ = NewFromFloat32Trunc(g.in)
+		if g.trunc != got.Bits() {
+			t.Errorf("%v: truncated bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.trunc, got.Bits())
+		}
+	}
+}
+
+func TestNewFromFloat64(t *testing.T) {
+	// Every float32 is converted the same way from float32, from float64 and
+	// from big.Float.
+	for bits := uint64(0); bits <= math.MaxUint32; bits += 0x1235 {
+		x := math.Float32frombits(uint32(bits))
+		if x != x {
+			continue
+		}
+		want, wantAcc := NewFromFloat32(x)
+		got, acc := NewFromFloat64(float64(x))
+		if want != got || wantAcc != acc {
+			t.Errorf("%v: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, want.Bits(), wantAcc, got.Bits(), acc)
+		}
+	}
+	golden := []struct {
+		in   float64
+		want uint16
+		acc  big.Accuracy
+	}{
+		// 1 + 2^(-8) + 2^(-40) rounds up, but would round to even through
+		// float32.
+		{in: 1 + 1./256 + math.Ldexp(1, -40), want: 0x3F81, acc: big.Above},
+		// 2^(-134) = +0 (tie, round to even)
+		{in: math.Ldexp(1, -134), want: 0x0000, acc: big.Below},
+		// -2^(-134) * 1.5 = -2^(-133)
+		{in: -math.Ldexp(1.5, -134), want: 0x8001, acc: big.Below},
+		// 2^(-200) = +0
+		{in: math.Ldexp(1, -200), want: 0x0000, acc: big.Below},
+		// 2^128 = +Inf
+		{in: math.Ldexp(1, 128), want: 0x7F80, acc: big.Above},
+	}
+	for _, g := range golden {
+		got, acc := NewFromFloat64(g.in)
+		if g.want != got.Bits() {
+			t.Errorf("%v: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for bits := 0; bits <= 0xFFFF; bits++ {
+		f := NewFromBits(uint16(bits))
+		x, _ := f.Float64()
+		if math.IsNaN(x) {
+			continue
+		}
+		y, nan := f.Big()
+		if nan {
+			t.Errorf("0x%04X: unexpected NaN", bits)
+			continue
+		}
+		if z, _ := y.Float64(); z != x || math.Signbit(z) != math.Signbit(x) {
+			t.Errorf("0x%04X: Big and Float64 mismatch; %v != %v", bits, z, x)
+		}
+		got, acc := NewFromBig(y)
+		if f != got || acc != big.Exact {
+			t.Errorf("0x%04X: round-trip mismatch; got 0x%04X (%v)", bits, got.Bits(), acc)
+		}
+	}
+}
+
+func TestNewFromFloat32Mode(t *testing.T) {
+	golden := []struct {
+		in   float32
+		mode big.RoundingMode
+		want uint16
+		acc  big.Accuracy
+	}{
+		// 1 + 2^(-8), halfway between 1 and the next number.
+		{in: 1 + 0x1p-8, mode: big.ToNearestEven, want: 0x3F80, acc: big.Below},
+		{in: 1 + 0x1p-8, mode: big.ToNearestAway, want: 0x3F81, acc: big.Above},
+		{in: 1 + 0x1p-8, mode: big.ToZero, want: 0x3F80, acc: big.Below},
+		{in: -1 - 0x1p-8, mode: big.AwayFromZero, want: 0xBF81, acc: big.Below},
+		{in: -1 - 0x1p-8, mode: big.ToNegativeInf, want: 0xBF81, acc: big.Below},
+		{in: -1 - 0x1p-8, mode: big.ToPositiveInf, want: 0xBF80, acc: big.Above},
+		// Overflow.
+		{in: math.MaxFloat32, mode: big.ToNearestEven, want: 0x7F80, acc: big.Above},
+		{in: math.MaxFloat32, mode: big.ToZero, want: 0x7F7F, acc: big.Below},
+		{in: -math.MaxFloat32, mode: big.ToPositiveInf, want: 0xFF7F, acc: big.Above},
+		// Underflow.
+		{in: 0x1p-134, mode: big.ToNearestEven, want: 0x0000, acc: big.Below},
+		{in: 0x1p-134, mode: big.ToNearestAway, want: 0x0001, acc: big.Above},
+		{in: -0x1p-140, mode: big.ToNegativeInf, want: 0x8001, acc: big.Below},
+		{in: -0x1p-140, mode: big.ToZero, want: 0x8000, acc: big.Above},
+	}
+	for _, g := range golden {
+		got, acc := NewFromFloat32Mode(g.in, g.mode)
+		if g.want != got.Bits() {
+			t.Errorf("%v %v: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.mode, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%v %v: accuracy mismatch; expected %v, got %v", g.in, g.mode, g.acc, acc)
+		}
+	}
+}
+
+func TestNewFromFloat32Modes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		x := math.Float32frombits(r.Uint32())
+		if x != x || math.IsInf(float64(x), 0) || x == 0 {
+			continue
+		}
+		// Truncation of the float32 representation rounds towards zero, and
+		// the fast conversion rounds to nearest even.
+		want, wantAcc := NewFromFloat32Trunc(x)
+		if got, acc := NewFromFloat32Mode(x, big.ToZero); want != got || wantAcc != acc {
+			t.Errorf("%v: truncation mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, want.Bits(), wantAcc, got.Bits(), acc)
+		}
+		want, wantAcc = NewFromFloat32(x)
+		if got, acc := NewFromFloat32Mode(x, big.ToNearestEven); want != got || wantAcc != acc {
+			t.Errorf("%v: rounding mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, want.Bits(), wantAcc, got.Bits(), acc)
+		}
+		testModes(t, big.NewFloat(float64(x)))
+	}
+}
+
+// testModes checks the conversion of the finite non-zero x in each rounding
+// mode against its neighbours in bfloat16.
+func testModes(t *testing.T, x *big.Float) {
+	t.Helper()
+	down, downAcc := NewFromBigMode(x, big.ToNegativeInf)
+	up, upAcc := NewFromBigMode(x, big.ToPositiveInf)
+	d, _ := down.Big()
+	u, _ := up.Big()
+	if downAcc == big.Exact || upAcc == big.Exact {
+		if down != up || downAcc != upAcc {
+			t.Errorf("%v: exact mismatch; got 0x%04X (%v) and 0x%04X (%v)", x, down.Bits(), downAcc, up.Bits(), upAcc)
+		}
+		return
+	}
+	// Successor of down.
+	next := down.Bits() + 1
+	if down.Signbit() {
+		next = down.Bits() - 1
+	}
+	if next != up.Bits() || downAcc != big.Below || upAcc != big.Above || d.Cmp(x) >= 0 || u.Cmp(x) <= 0 {
+		t.Errorf("%v: neighbours mismatch; got 0x%04X (%v) and 0x%04X (%v)", x, down.Bits(), downAcc, up.Bits(), upAcc)
+		return
+	}
+	toward, away := down, up
+	if x.Signbit() {
+		toward, away = up, down
+	}
+	// Round to nearest, with infinities replaced by 2^128.
+	if d.IsInf() {
+		d = new(big.Float).SetMantExp(big.NewFloat(-1), 128)
+	}
+	if u.IsInf() {
+		u = new(big.Float).SetMantExp(big.NewFloat(1), 128)
+	}
+	mid := new(big.Float).SetPrec(128).Add(d, u)
+	mid.SetMantExp(mid, -1)
+	c := x.Cmp(mid)
+	nearestEven, nearestAway := down, down
+	switch {
+	case c > 0:
+		nearestEven, nearestAway = up, up
+	case c == 0:
+		nearestAway = away
+		if down.Bits()&1 != 0 {
+			nearestEven = up
+		}
+	}
+	wants := map[big.RoundingMode]Float{
+		big.ToNearestEven: nearestEven,
+		big.ToNearestAway: nearestAway,
+		big.ToZero:        toward,
+		big.AwayFromZero:  away,
+	}
+	for mode, want := range wants {
+		got, acc := NewFromBigMode(x, mode)
+		wantAcc := big.Below
+		if want == up {
+			wantAcc = big.Above
+		}
+		if want != got || wantAcc != acc {
+			t.Errorf("%v %v: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, mode, want.Bits(), wantAcc, got.Bits(), acc)
+		}
+	}
+}
+
+func TestConversionFlags(t *testing.T) {
+	golden := []struct {
+		in     float32
+		mode   big.RoundingMode
+		want   uint16
+		after  fenv.Flags
+		before fenv.Flags
+	}{
+		{in: 1, mode: big.ToNearestEven, want: 0x3F80},
+		{in: 1 + 0x1p-8, mode: big.ToNearestEven, want: 0x3F80, after: fenv.Inexact, before: fenv.Inexact},
+		// Overflow.
+		{in: math.MaxFloat32, mode: big.ToNearestEven, want: 0x7F80, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{in: math.MaxFloat32, mode: big.ToZero, want: 0x7F7F, after: fenv.Inexact, before: fenv.Inexact},
+		// Underflow.
+		{in: 0x1p-134, mode: big.ToNearestEven, want: 0x0000, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: 0x1p-133, mode: big.ToNearestEven, want: 0x0001},
+		// 2^(-126) - 2^(-136), tiny only before rounding.
+		{in: 0x1p-126 - 0x1p-136, mode: big.ToNearestEven, want: 0x0080, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: 0x1p-126 - 0x1p-136, mode: big.ToZero, want: 0x007F, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// Signaling and quiet NaN.
+		{in: math.Float32frombits(0x7F800001), mode: big.ToNearestEven, want: 0x7FC0, after: fenv.Invalid, before: fenv.Invalid},
+		{in: math.Float32frombits(0x7FC00000), mode: big.ToNearestEven, want: 0x7FC0},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			got, _ := ctx.NewFromFloat32Mode(g.in, g.mode)
+			if g.want != got.Bits() {
+				t.Errorf("%v %v: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.mode, g.want, got.Bits())
+			}
+			if want != ctx.Flags {
+				t.Errorf("%v %v (%v): flags mismatch; expected %v, got %v", g.in, g.mode, tininess, want, ctx.Flags)
+			}
+			// The fast conversion agrees with the conversion of float64.
+			if g.mode == big.ToNearestEven {
+				ctx32 := &Context{Tininess: tininess}
+				ctx32.NewFromFloat32(g.in)
+				if want != ctx32.Flags {
+					t.Errorf("%v (%v): fast flags mismatch; expected %v, got %v", g.in, tininess, want, ctx32.Flags)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package bfloat
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// layout specifies the sign bit and exponent of the binary representation.
+var layout = order.Layout{Sign: 0x8000, ExpBits: 8}
+
+// repr is the binary representation of a floating-point number, as accessed by
+// layout.
+type repr Float
+
+// Words returns the binary representation of r as words from the most
+// significant.
+func (r repr) Words() []uint64 {
+	return []uint64{uint64(r.bits)}
+}
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0. A NaN is considered less than any non-NaN and equal to a NaN,
+// as by cmp.Compare.
+func (f Float) Cmp(g Float) int {
+	return layout.Cmp(repr(f), repr(g))
+}
+
+// Less reports whether f < g. It is false if f or g is NaN.
+func (f Float) Less(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is NaN, and true for -0
+// and +0.
+func (f Float) Equal(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder):
+//
+//	-qNaN < -sNaN < -Inf < -x < -0 < +0 < +x < +Inf < +sNaN < +qNaN
+//
+// NaNs of the same kind and sign are ordered by payload, in reverse for
+// negative NaNs.
+func (f Float) TotalOrder(g Float) bool {
+	return layout.TotalOrder(repr(f), repr(g))
+}
+
+// Class returns the class of f.
+func (f Float) Class() float.Class {
+	return layout.Class(repr(f))
+}
+
+// IsNaN reports whether f is Not-a-Number.
+func (f Float) IsNaN() bool {
+	return layout.IsNaN(repr(f))
+}
+
+// IsInf reports whether f is +Inf or -Inf.
+func (f Float) IsInf() bool {
+	return layout.IsInf(repr(f))
+}
+
+// IsSubnormal reports whether f is a subnormal number.
+func (f Float) IsSubnormal() bool {
+	return layout.IsSubnormal(repr(f))
+}
+
+// IsFinite reports whether f is neither infinite nor NaN.
+func (f Float) IsFinite() bool {
+	return layout.IsFinite(repr(f))
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f Float) IsZero() bool {
+	return layout.IsZero(repr(f))
+}
@@ -0,0 +1,74 @@
+package bfloat
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClass(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint16
+		class float.Class
+	}{
+		{bits: 0xFFFF, class: float.QuietNaN},
+		{bits: 0xFFC0, class: float.QuietNaN},
+		{bits: 0xFFBF, class: float.SignalingNaN},
+		{bits: 0xFF81, class: float.SignalingNaN},
+		{bits: 0xFF80, class: float.NegativeInfinity},
+		{bits: 0xFF7F, class: float.NegativeNormal},
+		{bits: 0xBF80, class: float.NegativeNormal},
+		{bits: 0x8080, class: float.NegativeNormal},
+		{bits: 0x807F, class: float.NegativeSubnormal},
+		{bits: 0x8001, class: float.NegativeSubnormal},
+		{bits: 0x8000, class: float.NegativeZero},
+		{bits: 0x0000, class: float.PositiveZero},
+		{bits: 0x0001, class: float.PositiveSubnormal},
+		{bits: 0x007F, class: float.PositiveSubnormal},
+		{bits: 0x0080, class: float.PositiveNormal},
+		{bits: 0x3F80, class: float.PositiveNormal},
+		{bits: 0x7F7F, class: float.PositiveNormal},
+		{bits: 0x7F80, class: float.PositiveInfinity},
+		{bits: 0x7F81, class: float.SignalingNaN},
+		{bits: 0x7FBF, class: float.SignalingNaN},
+		{bits: 0x7FC0, class: float.QuietNaN},
+		{bits: 0x7FFF, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%04X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%04X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding.
+	golden := []struct {
+		x, y  uint16
+		cmp   int
+		total bool
+	}{
+		// Largest subnormal and smallest normal number.
+		{x: 0x007F, y: 0x0080, cmp: -1, total: true},
+		{x: 0x8080, y: 0x807F, cmp: -1, total: true},
+		// Positive and negative zero.
+		{x: 0x0000, y: 0x8000, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{x: 0x7FBF, y: 0x7FC0, cmp: 0, total: true},
+		{x: 0xFFBF, y: 0xFFC0, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.x), NewFromBits(g.y)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%04X.Cmp(0x%04X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%04X.TotalOrder(0x%04X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package bfloat
+
+import (
+	"fmt"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary for Parse to return f exactly.
+func (f Float) Text(fmt byte, prec int) string {
+	x, nan := f.Big()
+	if nan {
+		return "NaN"
+	}
+	return strconv.FormatBig(x, fmt, prec, precision-1, 1-bias)
+}
+
+// String returns the shortest decimal representation of f which Parse converts
+// back to f, as for f.Text('g', -1).
+func (f Float) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Float) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
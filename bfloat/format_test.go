@@ -0,0 +1,91 @@
+package bfloat
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	golden := []struct {
+		bits uint16
+		fmt  byte
+		prec int
+		want string
+	}{
+		// Special numbers.
+		{bits: 0x0000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x8000, fmt: 'e', prec: 3, want: "-0.000e+00"},
+		{bits: 0x7F80, fmt: 'g', prec: -1, want: "+Inf"},
+		{bits: 0xFF80, fmt: 'f', prec: 2, want: "-Inf"},
+		{bits: 0x7FC0, fmt: 'g', prec: -1, want: "NaN"},
+
+		{bits: 0x3F80, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0x3F80, fmt: 'b', prec: -1, want: "128p-7"},
+		{bits: 0x4049, fmt: 'g', prec: -1, want: "3.14"},
+		{bits: 0x4049, fmt: 'f', prec: -1, want: "3.14"},
+		{bits: 0x4049, fmt: 'f', prec: 6, want: "3.140625"},
+		{bits: 0x4049, fmt: 'x', prec: -1, want: "0x1.92p+01"},
+		// max
+		{bits: 0x7F7F, fmt: 'g', prec: -1, want: "3.39e+38"},
+		{bits: 0x7F7F, fmt: 'E', prec: 5, want: "3.38953E+38"},
+		// min normal
+		{bits: 0x0080, fmt: 'g', prec: -1, want: "1.18e-38"},
+		// min denormal
+		{bits: 0x0001, fmt: 'g', prec: -1, want: "1e-40"},
+		{bits: 0x8001, fmt: 'b', prec: -1, want: "-1p-133"},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.bits)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("0x%04X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	for bits := 0; bits <= 0xFFFF; bits++ {
+		f := NewFromBits(uint16(bits))
+		if f.Exp() == 0xFF && f.Frac() != 0 {
+			// NaN
+			continue
+		}
+		s := f.String()
+		got, _, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if f != got {
+			t.Errorf("%q: round-trip mismatch; expected 0x%04X, got 0x%04X", s, f.Bits(), got.Bits())
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		in     uint16
+		want   string
+	}{
+		{format: "%v", in: 0x3F80, want: "1"},
+		{format: "%+.3e", in: 0x3F80, want: "+1.000e+00"},
+		{format: "%.6f", in: 0x4049, want: "3.140625"},
+		{format: "%#v", in: 0x3F80, want: "1"},
+		{format: "%#g", in: 0x3F80, want: "1.00000"},
+		{format: "%8.2f|", in: 0xBF80, want: "   -1.00|"},
+		{format: "%-8.2f|", in: 0xBF80, want: "-1.00   |"},
+		{format: "%08.2f", in: 0xBF80, want: "-0001.00"},
+		{format: "% x", in: 0x3F80, want: " 0x1p+00"},
+		{format: "%F", in: 0x3F80, want: "1.000000"},
+		{format: "%v", in: 0x7F80, want: "+Inf"},
+		{format: "%6v", in: 0x7FC0, want: "   NaN"},
+		{format: "%s", in: 0x3F80, want: "%!s(bfloat.Float=1)"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, NewFromBits(g.in))
+		if g.want != got {
+			t.Errorf("%q %v: output mismatch; expected %q, got %q", g.format, g.in, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package bfloat
+
+import (
+	"fmt"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// ParseLLVM parses s as an LLVM IR hexadecimal floating-point literal of type
+// bfloat; "0xR" followed by the 4 hexadecimal digits of the binary
+// representation, and returns the corresponding floating-point number. If s is
+// syntactically invalid, the error wraps strconv.ErrSyntax.
+func ParseLLVM(s string) (Float, error) {
+	words, err := strconv.ParseLLVMHex(s, 'R', 16)
+	if err != nil {
+		return Float{}, fmt.Errorf("bfloat.ParseLLVM: parsing %q: %w", s, err)
+	}
+	return NewFromBits(uint16(words[0])), nil
+}
+
+// LLVMString returns the LLVM IR hexadecimal floating-point literal of f, as
+// accepted by ParseLLVM; e.g. "0xR3F80" for 1.0.
+func (f Float) LLVMString() string {
+	return fmt.Sprintf("0xR%04X", f.Bits())
+}
@@ -0,0 +1,48 @@
+package bfloat
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLLVM(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "0xR3F80", want: "1"},
+		{in: "0xRBFC0", want: "-1.5"},
+		{in: "0xR4049", want: "3.14"},
+		{in: "0xR7f7f", want: "3.39e+38"},
+		{in: "0xR0001", want: "1e-40"},
+		{in: "0xR7F80", want: "+Inf"},
+		{in: "0xR7FC0", want: "NaN"},
+		{in: "0xR8000", want: "-0"},
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "0xR3F8", err: strconv.ErrSyntax},
+		{in: "0xR3F800", err: strconv.ErrSyntax},
+		{in: "0xH3F80", err: strconv.ErrSyntax},
+		{in: "0xR3F8X", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, err := ParseLLVM(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err != nil {
+			continue
+		}
+		if s := got.String(); g.want != s {
+			t.Errorf("%q: value mismatch; expected %v, got %v", g.in, g.want, s)
+		}
+		// Round-trip.
+		if s := got.LLVMString(); strings.ToUpper(g.in[3:]) != s[3:] || g.in[:3] != s[:3] {
+			t.Errorf("%q: round-trip mismatch; got %q", g.in, s)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package bfloat
+
+// quiet specifies the quiet bit of Not-a-Number values.
+const quiet = 0x0040
+
+// NewNaN returns a Not-a-Number value with the given payload, which is quiet
+// unless signaling is set. The payload is the fraction without the quiet bit,
+// truncated to 6 bits. A signaling NaN with a zero payload has payload 1, as
+// its encoding would otherwise be infinity.
+func NewNaN(signaling bool, payload uint16) Float {
+	payload &= 0x003F
+	if !signaling {
+		return Float{bits: 0x7F80 | quiet | payload}
+	}
+	if payload == 0 {
+		payload = 1
+	}
+	return Float{bits: 0x7F80 | payload}
+}
+
+// IsSignaling reports whether f is a signaling Not-a-Number value.
+func (f Float) IsSignaling() bool {
+	return f.isNaN() && f.bits&quiet == 0
+}
+
+// Payload returns the payload of the Not-a-Number value f, i.e. its fraction
+// without the quiet bit, or 0 if f is not NaN.
+func (f Float) Payload() uint16 {
+	if !f.isNaN() {
+		return 0
+	}
+	return f.bits & 0x003F
+}
+
+// Quiet returns f with the quiet bit set if f is a signaling Not-a-Number value,
+// and f otherwise. The sign and payload are preserved.
+func (f Float) Quiet() Float {
+	if !f.isNaN() {
+		return f
+	}
+	return Float{bits: f.bits | quiet}
+}
+
+// newNaN returns the quiet NaN with the given sign and the fraction frac aligned
+// at the most significant bit, keeping the 7 most significant bits of the
+// fraction.
+func newNaN(sign bool, frac uint64) Float {
+	f := Float{bits: 0x7F80 | quiet | uint16(frac>>57)}
+	if sign {
+		f.bits |= 0x8000
+	}
+	return f
+}
+
+// isNaN reports whether f is Not-a-Number.
+func (f Float) isNaN() bool {
+	return f.Exp() == 0xFF && f.Frac() != 0
+}
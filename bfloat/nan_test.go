@@ -0,0 +1,113 @@
+package bfloat
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewNaN(t *testing.T) {
+	golden := []struct {
+		signaling bool
+		payload   uint16
+		want      uint16
+	}{
+		{signaling: false, payload: 0, want: 0x7FC0},
+		{signaling: false, payload: 0x0015, want: 0x7FD5},
+		{signaling: true, payload: 0x0015, want: 0x7F95},
+		// Zero payload of signaling NaN.
+		{signaling: true, payload: 0, want: 0x7F81},
+		// Payload truncated to 6 bits.
+		{signaling: false, payload: 0xFFFF, want: 0x7FFF},
+		{signaling: true, payload: 0x0040, want: 0x7F81},
+	}
+	for _, g := range golden {
+		f := NewNaN(g.signaling, g.payload)
+		if g.want != f.Bits() {
+			t.Errorf("NewNaN(%v, 0x%04X): bits mismatch; expected 0x%04X, got 0x%04X", g.signaling, g.payload, g.want, f.Bits())
+			continue
+		}
+		if f.IsSignaling() != g.signaling {
+			t.Errorf("0x%04X: signaling mismatch; expected %v, got %v", f.Bits(), g.signaling, f.IsSignaling())
+		}
+		if want := g.want & 0x003F; want != f.Payload() {
+			t.Errorf("0x%04X: payload mismatch; expected 0x%04X, got 0x%04X", f.Bits(), want, f.Payload())
+		}
+		if q := f.Quiet(); q.Bits() != g.want|0x0040 || q.IsSignaling() {
+			t.Errorf("0x%04X: quiet mismatch; expected 0x%04X, got 0x%04X", f.Bits(), g.want|0x0040, q.Bits())
+		}
+	}
+	// Numbers other than NaN.
+	for _, f := range []Float{Inf, NegInf, Zero, NegZero, NewFromBits(0x3F80)} {
+		if f.IsSignaling() || f.Payload() != 0 || f.Quiet() != f {
+			t.Errorf("0x%04X: unexpected NaN", f.Bits())
+		}
+	}
+}
+
+func TestNaNConversion(t *testing.T) {
+	golden32 := []struct {
+		in    uint32
+		want  uint16
+		flags fenv.Flags
+	}{
+		// The 7 most significant bits of the fraction are kept.
+		{in: 0x7FC00000, want: 0x7FC0},
+		{in: 0xFFC00000, want: 0xFFC0},
+		{in: 0x7FD55555, want: 0x7FD5},
+		{in: 0x7FFFFFFF, want: 0x7FFF},
+		// Signaling NaN is quieted.
+		{in: 0x7F955555, want: 0x7FD5, flags: fenv.Invalid},
+		{in: 0xFF800001, want: 0xFFC0, flags: fenv.Invalid},
+	}
+	for _, g := range golden32 {
+		x := math.Float32frombits(g.in)
+		ctx := new(Context)
+		f, _ := ctx.NewFromFloat32(x)
+		if g.want != f.Bits() || g.flags != ctx.Flags {
+			t.Errorf("0x%08X: float32 conversion mismatch; expected 0x%04X (%v), got 0x%04X (%v)", g.in, g.want, g.flags, f.Bits(), ctx.Flags)
+		}
+		if f, _ := NewFromFloat32Trunc(x); g.want != f.Bits() {
+			t.Errorf("0x%08X: truncated conversion mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, f.Bits())
+		}
+		if f, _ := NewFromFloat32Mode(x, big.ToZero); g.want != f.Bits() {
+			t.Errorf("0x%08X: conversion mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, f.Bits())
+		}
+	}
+	golden64 := []struct {
+		in   uint64
+		want uint16
+	}{
+		{in: 0x7FF8000000000001, want: 0x7FC0},
+		{in: 0x7FFAAAAAAAAAAAAA, want: 0x7FD5},
+		{in: 0xFFF0000000000001, want: 0xFFC0},
+	}
+	for _, g := range golden64 {
+		if f, _ := NewFromFloat64(math.Float64frombits(g.in)); g.want != f.Bits() {
+			t.Errorf("0x%016X: float64 conversion mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, f.Bits())
+		}
+	}
+
+	// The float32 representation keeps signaling NaN, and the payload is
+	// extended with zero bits.
+	golden := []struct {
+		in     uint16
+		want32 uint32
+		want64 uint64
+	}{
+		{in: 0x7FC0, want32: 0x7FC00000, want64: 0x7FF8000000000000},
+		{in: 0xFFD5, want32: 0xFFD50000, want64: 0xFFFAA00000000000},
+		{in: 0x7F81, want32: 0x7F810000, want64: 0x7FF8200000000000},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.in)
+		if x, _ := f.Float32(); math.Float32bits(x) != g.want32 {
+			t.Errorf("0x%04X: Float32 mismatch; expected 0x%08X, got 0x%08X", g.in, g.want32, math.Float32bits(x))
+		}
+		if x, _ := f.Float64(); math.Float64bits(x) != g.want64 {
+			t.Errorf("0x%04X: Float64 mismatch; expected 0x%016X, got 0x%016X", g.in, g.want64, math.Float64bits(x))
+		}
+	}
+}
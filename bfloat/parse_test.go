@@ -0,0 +1,81 @@
+package bfloat
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	golden := []struct {
+		in   string
+		want uint16
+		acc  big.Accuracy
+		err  error
+	}{
+		// Special numbers.
+		{in: "0", want: 0x0000, acc: big.Exact},
+		{in: "-0", want: 0x8000, acc: big.Exact},
+		{in: "inf", want: 0x7F80, acc: big.Exact},
+		{in: "-Inf", want: 0xFF80, acc: big.Exact},
+		{in: "nan", want: 0x7FC0, acc: big.Exact},
+		{in: "-nan", want: 0xFFC0, acc: big.Exact},
+
+		{in: "3.140625", want: 0x4049, acc: big.Exact},
+		{in: "3.14159", want: 0x4049, acc: big.Below},
+		{in: "-0x1.56p-2", want: 0xBEAB, acc: big.Exact},
+		// 1 + 2^(-8) = 1 (tie, round to even)
+		{in: "1.00390625", want: 0x3F80, acc: big.Below},
+		{in: "1.00390625000000000000000000000000001", want: 0x3F81, acc: big.Above},
+		// 3.3895313892515355e+38 (max bfloat16)
+		{in: "3.39e38", want: 0x7F7F, acc: big.Below},
+		{in: "3.4e38", want: 0x7F80, acc: big.Above, err: strconv.ErrRange},
+		// 2^(-133) ~= 9.183549615799121e-41 (minimum positive denormalized)
+		{in: "9.2e-41", want: 0x0001, acc: big.Below},
+		{in: "4.5e-41", want: 0x0000, acc: big.Below},
+
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "0x1.8", err: strconv.ErrSyntax},
+		{in: "1e1e1", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if g.want != got.Bits() {
+			t.Errorf("%q: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	for bits := 0; bits <= 0xFFFF; bits++ {
+		f := NewFromBits(uint16(bits))
+		x, nan := f.Big()
+		if nan || x.IsInf() {
+			continue
+		}
+		// Four significant decimal digits are enough to identify a bfloat16
+		// floating-point number; the hexadecimal representation is exact.
+		for _, s := range []string{x.Text('e', 3), x.Text('p', 0)} {
+			got, _, err := Parse(s)
+			if err != nil {
+				t.Errorf("%q: unexpected error; %v", s, err)
+				continue
+			}
+			if f != got {
+				t.Errorf("%q: bits mismatch; expected 0x%04X, got 0x%04X", s, f.Bits(), got.Bits())
+			}
+		}
+	}
+}
@@ -0,0 +1,582 @@
+package binary128
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+	"github.com/mewmew/float/internal/wideint"
+)
+
+const (
+	// emin specifies the exponent of the smallest normalized number.
+	emin = 1 - bias
+	// emax specifies the exponent of the largest normalized number.
+	emax = bias
+	// quiet specifies the quiet bit of Not-a-Number values.
+	quiet = 0x0000800000000000
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the arithmetic operations and conversions performed
+// in the context. The zero value detects tininess after rounding and has no
+// flags raised.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// Add returns the sum f+g, rounded to nearest even, and the accuracy of the
+// result.
+func (f Float) Add(g Float) (Float, big.Accuracy) {
+	return new(Context).Add(f, g)
+}
+
+// Sub returns the difference f-g, rounded to nearest even, and the accuracy of
+// the result.
+func (f Float) Sub(g Float) (Float, big.Accuracy) {
+	return new(Context).Sub(f, g)
+}
+
+// Mul returns the product f*g, rounded to nearest even, and the accuracy of the
+// result.
+func (f Float) Mul(g Float) (Float, big.Accuracy) {
+	return new(Context).Mul(f, g)
+}
+
+// Div returns the quotient f/g, rounded to nearest even, and the accuracy of
+// the result.
+func (f Float) Div(g Float) (Float, big.Accuracy) {
+	return new(Context).Div(f, g)
+}
+
+// Sqrt returns the square root of f, rounded to nearest even, and the accuracy
+// of the result.
+func (f Float) Sqrt() (Float, big.Accuracy) {
+	return new(Context).Sqrt(f)
+}
+
+// FMA returns the fused multiply-add f*g+h, computed with only one rounding
+// (to nearest even), and the accuracy of the result.
+func (f Float) FMA(g, h Float) (Float, big.Accuracy) {
+	return new(Context).FMA(f, g, h)
+}
+
+// Rem returns the IEEE 754 remainder f-n*g, where n is the integer nearest the
+// exact value of f/g (ties to even). The result is always exact.
+func (f Float) Rem(g Float) Float {
+	return new(Context).Rem(f, g)
+}
+
+// Add returns the sum x+y, rounded to nearest even, and the accuracy of the
+// result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Add(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	return ctx.add(x, y)
+}
+
+// Sub returns the difference x-y, rounded to nearest even, and the accuracy of
+// the result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Sub(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	return ctx.add(x, y.neg())
+}
+
+// Mul returns the product x*y, rounded to nearest even, and the accuracy of the
+// result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Mul(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf() || y.isInf():
+		// Inf*0 is invalid.
+		if x.isZero() || y.isZero() {
+			return ctx.invalid(), big.Exact
+		}
+		return inf(sign), big.Exact
+	}
+	a, b := x.unpack(), y.unpack()
+	mant, s := a.mant.Mul(b.mant).Narrow()
+	return ctx.round(unpacked{sign: sign, mant: mant, exp: a.exp + b.exp + s})
+}
+
+// Div returns the quotient x/y, rounded to nearest even, and the accuracy of
+// the result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Div(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf():
+		// Inf/Inf is invalid.
+		if y.isInf() {
+			return ctx.invalid(), big.Exact
+		}
+		return inf(sign), big.Exact
+	case y.isInf():
+		return zero(sign), big.Exact
+	case y.isZero():
+		// 0/0 is invalid.
+		if x.isZero() {
+			return ctx.invalid(), big.Exact
+		}
+		// Division by zero.
+		ctx.Flags |= fenv.DivByZero
+		return inf(sign), big.Exact
+	case x.isZero():
+		return zero(sign), big.Exact
+	}
+	a, b := x.unpack().normalize(), y.unpack().normalize()
+	// Make the dividend larger than the divisor, so that each step of the long
+	// division produces a quotient bit.
+	exp := a.exp - b.exp
+	rem := a.mant
+	if rem.Cmp(b.mant) < 0 {
+		rem = rem.Lsh(1)
+		exp--
+	}
+	// Compute precision+2 quotient bits using long division, and jam the
+	// remainder into a sticky bit.
+	var q wideint.Uint128
+	for i := 0; i < precision+2; i++ {
+		q = q.Lsh(1)
+		if rem.Cmp(b.mant) >= 0 {
+			rem = rem.Sub(b.mant)
+			q.Lo |= 1
+		}
+		rem = rem.Lsh(1)
+	}
+	if !rem.IsZero() {
+		q.Lo |= 1
+	}
+	return ctx.round(unpacked{sign: sign, mant: q, exp: exp - (precision + 1)})
+}
+
+// Sqrt returns the square root of x, rounded to nearest even, and the accuracy
+// of the result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Sqrt(x Float) (Float, big.Accuracy) {
+	switch {
+	case x.isNaN():
+		return ctx.propagate(x), big.Exact
+	case x.isZero():
+		// sqrt(+-0) = +-0
+		return x, big.Exact
+	case x.Signbit():
+		// Square root of negative number is invalid.
+		return ctx.invalid(), big.Exact
+	case x.isInf():
+		return Inf, big.Exact
+	}
+	a := x.unpack().normalize()
+	// Make the exponent even, so that the radicand holds 2*57 bits.
+	if a.exp&1 != 0 {
+		a.mant = a.mant.Lsh(1)
+		a.exp--
+	}
+	// Compute precision+3 root bits using the digit-by-digit method, consuming
+	// two bits of the radicand (followed by zeros) per step, and jam the
+	// remainder into a sticky bit.
+	var root, rem wideint.Uint128
+	for i := 0; i < precision+3; i++ {
+		rem = rem.Lsh(2)
+		if i < 57 {
+			rem.Lo |= a.mant.Rsh(uint(2*(56-i))).Lo & 3
+		}
+		trial := root.Lsh(2)
+		trial.Lo |= 1
+		root = root.Lsh(1)
+		if rem.Cmp(trial) >= 0 {
+			rem = rem.Sub(trial)
+			root.Lo |= 1
+		}
+	}
+	if !rem.IsZero() {
+		root.Lo |= 1
+	}
+	// The radicand 2^exp * mant is scaled by 2^(2*(precision+3-57)).
+	return ctx.round(unpacked{mant: root, exp: a.exp/2 - (precision + 3 - 57)})
+}
+
+// FMA returns the fused multiply-add x*y+z, computed with only one rounding
+// (to nearest even), and the accuracy of the result. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) FMA(x, y, z Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() || z.isNaN() {
+		return ctx.propagate(x, y, z), big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf() || y.isInf():
+		// Inf*0 and Inf-Inf are invalid.
+		if x.isZero() || y.isZero() || (z.isInf() && z.Signbit() != sign) {
+			return ctx.invalid(), big.Exact
+		}
+		return inf(sign), big.Exact
+	case z.isInf():
+		return z, big.Exact
+	}
+	a, b, c := x.unpack(), y.unpack(), z.unpack()
+	p := wideUnpacked{sign: sign, mant: a.mant.Mul(b.mant), exp: a.exp + b.exp}
+	sum := addWide(p, wideUnpacked{sign: c.sign, mant: c.mant.Wide(), exp: c.exp})
+	mant, s := sum.mant.Narrow()
+	return ctx.round(unpacked{sign: sum.sign, mant: mant, exp: sum.exp + s})
+}
+
+// Rem returns the IEEE 754 remainder x-n*y, where n is the integer nearest the
+// exact value of x/y (ties to even). The result is always exact. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) Rem(x, y Float) Float {
+	switch {
+	case x.isNaN() || y.isNaN():
+		return ctx.propagate(x, y)
+	case x.isInf() || y.isZero():
+		// rem(Inf, y) and rem(x, 0) are invalid.
+		return ctx.invalid()
+	case y.isInf() || x.isZero():
+		return x
+	}
+	a, b := x.unpack().normalize(), y.unpack().normalize()
+	d := a.exp - b.exp
+	if d < -1 {
+		// |x| < |y|/2
+		return x
+	}
+	// Compute the remainder r = a.mant*2^d mod b.mant (in units of 2^b.exp) and
+	// the least significant bit q of the quotient using long division.
+	r := a.mant
+	var q uint64
+	if d == -1 {
+		// Work in units of 2^(b.exp-1).
+		b.mant = b.mant.Lsh(1)
+		b.exp--
+		d = 0
+	}
+	for i := d; i >= 0; i-- {
+		q = 0
+		if r.Cmp(b.mant) >= 0 {
+			r = r.Sub(b.mant)
+			q = 1
+		}
+		if i > 0 {
+			r = r.Lsh(1)
+		}
+	}
+	// Round the quotient to nearest even; if the quotient is rounded up, the
+	// remainder is r-b.mant.
+	sign := a.sign
+	if c := r.Lsh(1).Cmp(b.mant); c > 0 || (c == 0 && q == 1) {
+		r = b.mant.Sub(r)
+		sign = !sign
+	}
+	z, _ := ctx.round(unpacked{sign: sign, mant: r, exp: b.exp})
+	if z.isZero() {
+		return zero(a.sign)
+	}
+	return z
+}
+
+// add returns the sum x+y of the non-NaN operands x and y, rounded to nearest
+// even, and the accuracy of the result.
+func (ctx *Context) add(x, y Float) (Float, big.Accuracy) {
+	switch {
+	case x.isInf():
+		// Inf-Inf is invalid.
+		if y.isInf() && y.Signbit() != x.Signbit() {
+			return ctx.invalid(), big.Exact
+		}
+		return x, big.Exact
+	case y.isInf():
+		return y, big.Exact
+	}
+	a, b := x.unpack(), y.unpack()
+	sum := addWide(wideUnpacked{sign: a.sign, mant: a.mant.Wide(), exp: a.exp}, wideUnpacked{sign: b.sign, mant: b.mant.Wide(), exp: b.exp})
+	mant, s := sum.mant.Narrow()
+	return ctx.round(unpacked{sign: sum.sign, mant: mant, exp: sum.exp + s})
+}
+
+// ### [ Helper functions ] ####################################################
+
+// unpacked is a finite floating-point number in unpacked form, representing
+// the value (-1)^sign * mant * 2^exp.
+type unpacked struct {
+	sign bool
+	mant wideint.Uint128
+	exp  int
+}
+
+// unpack returns the unpacked form of the finite floating-point number f.
+func (f Float) unpack() unpacked {
+	sign := f.Signbit()
+	frac1, frac2 := f.Frac()
+	mant := wideint.Uint128{Hi: frac1, Lo: frac2}
+	exp := f.Exp()
+	if exp == 0 {
+		// Denormalized number.
+		//
+		//    (-1)^signbit * 2^(-16382) * 0.mant_2
+		return unpacked{sign: sign, mant: mant, exp: emin - (precision - 1)}
+	}
+	// Normalized number.
+	//
+	//    (-1)^signbit * 2^(exp-16383) * 1.mant_2
+	mant.Hi |= 1 << (precision - 1 - 64)
+	return unpacked{sign: sign, mant: mant, exp: exp - bias - (precision - 1)}
+}
+
+// unpackBig returns the unpacked form of the finite non-zero x, truncated to 126
+// bits with the bits shifted out jammed into the least significant bit.
+func unpackBig(x *big.Float) unpacked {
+	const prec = 126
+	y := new(big.Float).SetMode(big.ToZero).SetPrec(prec).Set(x)
+	mant := new(big.Float)
+	exp := y.MantExp(mant)
+	m, _ := mant.Abs(mant).SetMantExp(mant, prec).Int(nil)
+	lo := new(big.Int).And(m, new(big.Int).SetUint64(math.MaxUint64))
+	u := wideint.Uint128{Hi: m.Rsh(m, 64).Uint64(), Lo: lo.Uint64()}
+	if y.Acc() != big.Exact {
+		u.Lo |= 1
+	}
+	return unpacked{sign: x.Signbit(), mant: u, exp: exp - prec}
+}
+
+// normalize returns x with the most significant bit of the non-zero mantissa
+// shifted to the position of the implicit lead bit.
+func (x unpacked) normalize() unpacked {
+	s := precision - x.mant.BitLen()
+	x.mant = x.mant.Lsh(uint(s))
+	x.exp -= s
+	return x
+}
+
+// wideUnpacked is a finite floating-point number in unpacked form with a 256-bit
+// mantissa, representing the value (-1)^sign * mant * 2^exp.
+type wideUnpacked struct {
+	sign bool
+	mant wideint.Uint256
+	exp  int
+}
+
+// addWide returns the sum x+y. The mantissas of x and y must be at most 2*113
+// bits wide. Bits shifted out when aligning the operands are jammed into the
+// least significant bit of the sum, which holds at least precision+2
+// significant bits when inexact.
+func addWide(x, y wideUnpacked) wideUnpacked {
+	switch {
+	case x.mant.IsZero() && y.mant.IsZero():
+		// +0 + -0 = +0, in round to nearest.
+		return wideUnpacked{sign: x.sign && y.sign}
+	case x.mant.IsZero():
+		return y
+	case y.mant.IsZero():
+		return x
+	}
+	// Let x be the operand of larger magnitude.
+	if x.exp+x.mant.BitLen() < y.exp+y.mant.BitLen() {
+		x, y = y, x
+	}
+	// Align the operands.
+	if d := x.exp - y.exp; d > 0 {
+		// Shift x left as far as possible, and jam the bits of y shifted out on
+		// the right into a sticky bit.
+		s := 254 - x.mant.BitLen()
+		if s > d {
+			s = d
+		}
+		x.mant = x.mant.Lsh(uint(s))
+		x.exp -= s
+		y.mant = y.mant.Jam(uint(d - s))
+	} else {
+		y.mant = y.mant.Lsh(uint(-d))
+	}
+	switch c := x.mant.Cmp(y.mant); {
+	case x.sign == y.sign:
+		return wideUnpacked{sign: x.sign, mant: x.mant.Add(y.mant), exp: x.exp}
+	case c > 0:
+		return wideUnpacked{sign: x.sign, mant: x.mant.Sub(y.mant), exp: x.exp}
+	case c < 0:
+		return wideUnpacked{sign: y.sign, mant: y.mant.Sub(x.mant), exp: x.exp}
+	}
+	// x + -x = +0, in round to nearest.
+	return wideUnpacked{exp: x.exp}
+}
+
+// round returns the nearest quadruple precision floating-point number to x,
+// using round half to even, and the accuracy of the result. The exception flags
+// raised are recorded in ctx. The least significant bit of x.mant is a sticky
+// bit when x is inexact.
+func (ctx *Context) round(x unpacked) (Float, big.Accuracy) {
+	return ctx.roundMode(x, big.ToNearestEven)
+}
+
+// roundMode returns x rounded to a quadruple precision floating-point number in
+// the given rounding mode, and the accuracy of the result. The exception flags
+// raised are recorded in ctx. The least significant bit of x.mant is a sticky
+// bit when x is inexact.
+func (ctx *Context) roundMode(x unpacked, mode big.RoundingMode) (Float, big.Accuracy) {
+	z, acc, flags := roundMode(x, mode, ctx.Tininess)
+	ctx.Flags |= flags
+	return z, acc
+}
+
+// roundMode returns x rounded to a quadruple precision floating-point number in
+// the given rounding mode, the accuracy of the result and the exception flags
+// raised, with tininess detected as specified by tininess. The least
+// significant bit of x.mant is a sticky bit when x is inexact.
+func roundMode(x unpacked, mode big.RoundingMode, tininess fenv.Tininess) (Float, big.Accuracy, fenv.Flags) {
+	if x.mant.IsZero() {
+		return zero(x.sign), big.Exact, 0
+	}
+	// Exponent of the most significant bit of x, and of the least significant
+	// bit of the result.
+	top := x.exp + x.mant.BitLen() - 1
+	lsb := top - (precision - 1)
+	if lsb < emin-(precision-1) {
+		lsb = emin - (precision - 1)
+	}
+	mant := x.mant
+	acc := big.Exact
+	if s := lsb - x.exp; s > 0 {
+		rem := mant.Sub(mant.Rsh(uint(s)).Lsh(uint(s)))
+		mant = mant.Rsh(uint(s))
+		if !rem.IsZero() {
+			acc = big.Below
+			// half is zero if s > 128, in which case rem < 2^128 <= 2^(s-1).
+			half := wideint.Uint128{Lo: 1}.Lsh(uint(s - 1))
+			c := -1
+			if !half.IsZero() {
+				c = rem.Cmp(half)
+			}
+			if rounding.Up(mode, x.sign, mant.Lo&1 != 0, c) {
+				mant = mant.Add(wideint.Uint128{Lo: 1})
+				acc = big.Above
+			}
+			if x.sign {
+				acc = -acc
+			}
+		}
+		if mant.BitLen() > precision {
+			mant = mant.Rsh(1)
+			lsb++
+		}
+	} else {
+		mant = mant.Lsh(uint(-s))
+	}
+	var flags fenv.Flags
+	if acc != big.Exact {
+		flags = fenv.Inexact
+		if top < emin && !(tininess == fenv.AfterRounding && top == emin-1 && carries(x, mode)) {
+			flags |= fenv.Underflow
+		}
+	}
+	// Exponent of the most significant bit of the result.
+	exp := lsb + precision - 1
+	if exp > emax {
+		// Overflow to +-Inf, or to the largest finite number of the same sign
+		// in directed rounding modes.
+		flags |= fenv.Overflow | fenv.Inexact
+		if !rounding.Inf(mode, x.sign) {
+			if x.sign {
+				return Float{a: 0xFFFEFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF}, big.Above, flags
+			}
+			return Float{a: 0x7FFEFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF}, big.Below, flags
+		}
+		if x.sign {
+			return NegInf, big.Below, flags
+		}
+		return Inf, big.Above, flags
+	}
+	// The implicit lead bit of normalized numbers is added to the exponent.
+	a := uint64(exp+bias-1)<<48 + mant.Hi
+	if x.sign {
+		a |= 0x8000000000000000
+	}
+	return Float{a: a, b: mant.Lo}, acc, flags
+}
+
+// carries reports whether x, with the most significant bit at exponent emin-1,
+// is rounded up to 2^emin in magnitude when rounded to the precision of
+// normalized numbers in the given rounding mode; i.e. whether x is not tiny
+// when tininess is detected after rounding.
+func carries(x unpacked, mode big.RoundingMode) bool {
+	// Scale x into the range of normalized numbers.
+	x.exp++
+	z, _, _ := roundMode(x, mode, fenv.AfterRounding)
+	return z.Exp() > 1
+}
+
+// propagate returns the first NaN of the given floating-point numbers, with its
+// quiet bit set. The invalid operation exception is raised if any of the
+// numbers is a signaling NaN.
+func (ctx *Context) propagate(fs ...Float) Float {
+	var nan Float
+	found := false
+	for _, f := range fs {
+		if !f.isNaN() {
+			continue
+		}
+		if f.a&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		if !found {
+			nan, found = f, true
+		}
+	}
+	if !found {
+		panic("binary128.propagate: no NaN operand")
+	}
+	return Float{a: nan.a | quiet, b: nan.b}
+}
+
+// invalid raises the invalid operation exception and returns the default NaN.
+func (ctx *Context) invalid() Float {
+	ctx.Flags |= fenv.Invalid
+	return NaN
+}
+
+// inf returns +Inf if sign is false and -Inf otherwise.
+func inf(sign bool) Float {
+	if sign {
+		return NegInf
+	}
+	return Inf
+}
+
+// zero returns +0 if sign is false and -0 otherwise.
+func zero(sign bool) Float {
+	if sign {
+		return NegZero
+	}
+	return Zero
+}
+
+// neg returns -f.
+func (f Float) neg() Float {
+	return Float{a: f.a ^ 0x8000000000000000, b: f.b}
+}
+
+// isNaN reports whether f is Not-a-Number.
+func (f Float) isNaN() bool {
+	frac1, frac2 := f.Frac()
+	return f.Exp() == 0x7FFF && (frac1 != 0 || frac2 != 0)
+}
+
+// isInf reports whether f is +Inf or -Inf.
+func (f Float) isInf() bool {
+	frac1, frac2 := f.Frac()
+	return f.Exp() == 0x7FFF && frac1 == 0 && frac2 == 0
+}
+
+// isZero reports whether f is +0 or -0.
+func (f Float) isZero() bool {
+	return f.a&0x7FFFFFFFFFFFFFFF == 0 && f.b == 0
+}
@@ -0,0 +1,239 @@
+package binary128
+
+import (
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+// Test cases computed using GCC's __float128 (libgcc soft-fp) and libquadmath.
+func TestArith(t *testing.T) {
+	golden := []struct {
+		op      string
+		x, y, z string
+		want    string
+	}{
+		// 1 + 2^(-113) = 1 (tie, round to even)
+		{op: "+", x: "3FFF0000000000000000000000000000", y: "3F8E0000000000000000000000000000", want: "3FFF0000000000000000000000000000"},
+		// (1 + 2^(-112)) + 2^(-113) = 1 + 2^(-111) (tie, round to even)
+		{op: "+", x: "3FFF0000000000000000000000000001", y: "3F8E0000000000000000000000000000", want: "3FFF0000000000000000000000000002"},
+		// max + max = +Inf
+		{op: "+", x: "7FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", y: "7FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", want: "7FFF0000000000000000000000000000"},
+		{op: "+", x: "CF7EB0906A12452D70DAAED6F1BE9D96", y: "CF7FB0906A12452D70DAAED6F1BE9D96", want: "CF80446C4F8DB3E214A40321354EF630"},
+		{op: "+", x: "800024A50638158B49485601C1253627", y: "E3ECBC75A19EFE51960391DF39F53FAE", want: "E3ECBC75A19EFE51960391DF39F53FAE"},
+		{op: "+", x: "00001E869A389AA5B1091F6CB0EFE835", y: "80005B93CEA9CFF1131B5E4612CFB89F", want: "80003D0D3471354B62123ED961DFD06A"},
+		// 1 - (1 - 2^(-113)) = 2^(-113)
+		{op: "-", x: "3FFF0000000000000000000000000000", y: "3FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", want: "3F8E0000000000000000000000000000"},
+		// 1 - 1 = +0
+		{op: "-", x: "3FFF0000000000000000000000000000", y: "3FFF0000000000000000000000000000", want: "00000000000000000000000000000000"},
+		{op: "-", x: "CF7EB0906A12452D70DAAED6F1BE9D96", y: "CF7FB0906A12452D70DAAED6F1BE9D96", want: "4F7EB0906A12452D70DAAED6F1BE9D96"},
+		{op: "-", x: "800066018B4AA94227A3132A5026A70C", y: "8000CC03169552844F462654A04D4E18", want: "000066018B4AA94227A3132A5026A70C"},
+		// 2^(-16494) * 0.5 = +0 (tie, round to even)
+		{op: "*", x: "00000000000000000000000000000001", y: "3FFE0000000000000000000000000000", want: "00000000000000000000000000000000"},
+		// 2^(-16494) * 1.5 = 2^(-16493) (tie, round to even)
+		{op: "*", x: "00000000000000000000000000000001", y: "3FFF8000000000000000000000000000", want: "00000000000000000000000000000002"},
+		// pi * e
+		{op: "*", x: "4000921FB54442D18469898CC51701B8", y: "40005BF0A8B1457695355FB8AC404E7A", want: "4002114580B45D4749E6108579A2D0CA"},
+		{op: "*", x: "CF7EB0906A12452D70DAAED6F1BE9D96", y: "CF7FB0906A12452D70DAAED6F1BE9D96", want: "5EFF6D73DBBA94FD294C3952501250CC"},
+		{op: "*", x: "8000132D4CBC9F369776ACB4D2AB18C7", y: "3FFE797E9354E25FF728891251FAE05F", want: "80000E2398AB751836A752490167127C"},
+		{op: "*", x: "0000BE130FF3C8895EBBE40081C377E6", y: "3FFCF6C48D596988983142BE2F360502", want: "00002EA96A1A6494FEDA16AA58F3FAB3"},
+		// 1 / 3
+		{op: "/", x: "3FFF0000000000000000000000000000", y: "40008000000000000000000000000000", want: "3FFD5555555555555555555555555555"},
+		// pi / e
+		{op: "/", x: "4000921FB54442D18469898CC51701B8", y: "40005BF0A8B1457695355FB8AC404E7A", want: "3FFF27DDBF6271DBDEFDC3B8933CC9F1"},
+		{op: "/", x: "3FFB87D75C7FD3352BB6AC4D0D51C967", y: "07210CC64EFBA3DA3E9A38F9644476F7", want: "78D97537A49981CF4B56AF13F29A4F66"},
+		{op: "/", x: "8000132D4CBC9F369776ACB4D2AB18C7", y: "3FFE797E9354E25FF728891251FAE05F", want: "80001A028A04E8DC550E8EC09FA7D407"},
+		{op: "/", x: "8000204FA8B6E5F3E53F4E12962AD71D", y: "BFFD28FA6295F2860A5CAAF44BD68BD1", want: "00006F693A1CCD87F78F4F3486369C85"},
+		// fma(pi, e, -(pi*e)) = rounding error of pi*e
+		{op: "fma", x: "4000921FB54442D18469898CC51701B8", y: "40005BF0A8B1457695355FB8AC404E7A", z: "C002114580B45D4749E6108579A2D0CA", want: "BF8E6C2CE5CD00E907527DA81418F280"},
+		// fma(1/3, 3, -1) = -2^(-114)
+		{op: "fma", x: "3FFD5555555555555555555555555555", y: "40008000000000000000000000000000", z: "BFFF0000000000000000000000000000", want: "BF8D0000000000000000000000000000"},
+		{op: "fma", x: "800066018B4AA94227A3132A5026A70C", y: "8000CC03169552844F462654A04D4E18", z: "80009C0A063F70C95BB84E17405CF26D", want: "80009C0A063F70C95BB84E17405CF26D"},
+		// rem(5, 2) = 1
+		{op: "rem", x: "40014000000000000000000000000000", y: "40000000000000000000000000000000", want: "3FFF0000000000000000000000000000"},
+		// rem(7, 2) = -1
+		{op: "rem", x: "4001C000000000000000000000000000", y: "40000000000000000000000000000000", want: "BFFF0000000000000000000000000000"},
+		// rem(-7, 2) = 1
+		{op: "rem", x: "C001C000000000000000000000000000", y: "40000000000000000000000000000000", want: "3FFF0000000000000000000000000000"},
+		// rem(-4, 2) = -0
+		{op: "rem", x: "C0010000000000000000000000000000", y: "40000000000000000000000000000000", want: "80000000000000000000000000000000"},
+		// rem(pi*e*10^100, pi)
+		{op: "rem", x: "414E38588B277CFF552C85F32A673A3E", y: "4000921FB54442D18469898CC51701B8", want: "BFFF407E2B8DCC44FC94FE33B2C5DBD0"},
+		{op: "rem", x: "3FFB87D75C7FD3352BB6AC4D0D51C967", y: "07210CC64EFBA3DA3E9A38F9644476F7", want: "071FFB95C546F7E8DC3F42D4195A2E84"},
+		{op: "rem", x: "1495111E080044559C966F3CE489FCAC", y: "0000DAA2BCD13A5486A84D38CE0FA014", want: "0000284481C252D41A3267F98AD3E1A8"},
+	}
+	for _, g := range golden {
+		x, y, z := fromHex(g.x), fromHex(g.y), fromHex(g.z)
+		var got Float
+		switch g.op {
+		case "+":
+			got, _ = x.Add(y)
+		case "-":
+			got, _ = x.Sub(y)
+		case "*":
+			got, _ = x.Mul(y)
+		case "/":
+			got, _ = x.Div(y)
+		case "fma":
+			got, _ = x.FMA(y, z)
+		case "rem":
+			got = x.Rem(y)
+		}
+		if want := fromHex(g.want); want != got {
+			t.Errorf("%s(0x%s, 0x%s, 0x%s): bits mismatch; expected 0x%s, got 0x%016X%016X", g.op, g.x, g.y, g.z, g.want, got.a, got.b)
+		}
+	}
+}
+
+func TestArithSpecial(t *testing.T) {
+	one := NewFromBits(0x3FFF000000000000, 0)
+	golden := []struct {
+		op   string
+		got  Float
+		want Float
+	}{
+		{op: "Inf-Inf", got: first(Inf.Sub(Inf)), want: NaN},
+		{op: "Inf*0", got: first(Inf.Mul(NegZero)), want: NaN},
+		{op: "0/0", got: first(Zero.Div(Zero)), want: NaN},
+		{op: "1/-0", got: first(one.Div(NegZero)), want: NegInf},
+		{op: "sqrt(-1)", got: first(one.neg().Sqrt()), want: NaN},
+		{op: "sqrt(-0)", got: first(NegZero.Sqrt()), want: NegZero},
+		{op: "-0+-0", got: first(NegZero.Add(NegZero)), want: NegZero},
+		{op: "1-1", got: first(one.Sub(one)), want: Zero},
+		{op: "fma(Inf,1,-Inf)", got: first(Inf.FMA(one, NegInf)), want: NaN},
+		{op: "rem(Inf,1)", got: Inf.Rem(one), want: NaN},
+		{op: "rem(1,Inf)", got: one.Rem(Inf), want: one},
+		// Signaling NaN is quieted, and its payload is preserved.
+		{op: "sNaN+1", got: first(NewFromBits(0xFFFF000000000000, 1).Add(one)), want: NewFromBits(0xFFFF800000000000, 1)},
+	}
+	for _, g := range golden {
+		if g.want != g.got {
+			t.Errorf("%s: bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.op, g.want.a, g.want.b, g.got.a, g.got.b)
+		}
+	}
+}
+
+func TestArithFlags(t *testing.T) {
+	golden := []struct {
+		op   string
+		x, y string
+		want string
+		// Exception flags raised with tininess detected after and before
+		// rounding.
+		after, before fenv.Flags
+	}{
+		// 1 + 2^(-113) = 1 (tie, round to even)
+		{op: "+", x: "3FFF0000000000000000000000000000", y: "3F8E0000000000000000000000000000", want: "3FFF0000000000000000000000000000", after: fenv.Inexact, before: fenv.Inexact},
+		// 1 - (1 - 2^(-113)) = 2^(-113)
+		{op: "-", x: "3FFF0000000000000000000000000000", y: "3FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", want: "3F8E0000000000000000000000000000"},
+		// max + max = +Inf
+		{op: "+", x: "7FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", y: "7FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", want: "7FFF0000000000000000000000000000", after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// 2^(-16382) - 2^(-16494) (largest denormal) + 2^(-16494) = 2^(-16382)
+		// (tiny but exact)
+		{op: "+", x: "0000FFFFFFFFFFFFFFFFFFFFFFFFFFFF", y: "00000000000000000000000000000001", want: "00010000000000000000000000000000"},
+		// 2^(-16494) * 0.5 = +0 (tie, round to even)
+		{op: "*", x: "00000000000000000000000000000001", y: "3FFE0000000000000000000000000000", want: "00000000000000000000000000000000", after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// (2^(-16382) - 2^(-16494)) * (1 + 2^(-112)) = 2^(-16382) - 2^(-16606),
+		// which is rounded to 2^(-16382) both with and without bounded exponent
+		// range.
+		{op: "*", x: "0000FFFFFFFFFFFFFFFFFFFFFFFFFFFF", y: "3FFF0000000000000000000000000001", want: "00010000000000000000000000000000", after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-16382) * (1 - 2^(-113)) = 2^(-16382) - 2^(-16495) (tie, round to
+		// even), which is tiny also when rounded to 113 bits.
+		{op: "*", x: "00010000000000000000000000000000", y: "3FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", want: "00010000000000000000000000000000", after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// +Inf * -0 = NaN
+		{op: "*", x: "7FFF0000000000000000000000000000", y: "80000000000000000000000000000000", want: "7FFF8000000000000000000000000000", after: fenv.Invalid, before: fenv.Invalid},
+		// 1 / 3
+		{op: "/", x: "3FFF0000000000000000000000000000", y: "40008000000000000000000000000000", want: "3FFD5555555555555555555555555555", after: fenv.Inexact, before: fenv.Inexact},
+		// 1 / -0 = -Inf
+		{op: "/", x: "3FFF0000000000000000000000000000", y: "80000000000000000000000000000000", want: "FFFF0000000000000000000000000000", after: fenv.DivByZero, before: fenv.DivByZero},
+		// 0 / 0 = NaN
+		{op: "/", x: "00000000000000000000000000000000", y: "00000000000000000000000000000000", want: "7FFF8000000000000000000000000000", after: fenv.Invalid, before: fenv.Invalid},
+		// sqrt(-1) = NaN
+		{op: "sqrt", x: "BFFF0000000000000000000000000000", want: "7FFF8000000000000000000000000000", after: fenv.Invalid, before: fenv.Invalid},
+		// sNaN + 1 = qNaN
+		{op: "+", x: "7FFF0000000000000000000000000001", y: "3FFF0000000000000000000000000000", want: "7FFF8000000000000000000000000001", after: fenv.Invalid, before: fenv.Invalid},
+		// qNaN + 1 = qNaN
+		{op: "+", x: "7FFF8000000000000000000000000001", y: "3FFF0000000000000000000000000000", want: "7FFF8000000000000000000000000001"},
+		// rem(1, 0) = NaN
+		{op: "rem", x: "3FFF0000000000000000000000000000", y: "00000000000000000000000000000000", want: "7FFF8000000000000000000000000000", after: fenv.Invalid, before: fenv.Invalid},
+		// rem(2^(-16382) + 2^(-16494), 2^(-16382)) = 2^(-16494) (tiny but exact)
+		{op: "rem", x: "00010000000000000000000000000001", y: "00010000000000000000000000000000", want: "00000000000000000000000000000001"},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx := &Context{Tininess: tininess}
+			x, y := fromHex(g.x), fromHex(g.y)
+			var got Float
+			switch g.op {
+			case "+":
+				got, _ = ctx.Add(x, y)
+			case "-":
+				got, _ = ctx.Sub(x, y)
+			case "*":
+				got, _ = ctx.Mul(x, y)
+			case "/":
+				got, _ = ctx.Div(x, y)
+			case "sqrt":
+				got, _ = ctx.Sqrt(x)
+			case "rem":
+				got = ctx.Rem(x, y)
+			}
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if fromHex(g.want) != got {
+				t.Errorf("0x%s %s 0x%s: bits mismatch; expected 0x%s, got 0x%016X%016X", g.x, g.op, g.y, g.want, got.a, got.b)
+			}
+			if want != ctx.Flags {
+				t.Errorf("0x%s %s 0x%s with tininess detected %v: flags mismatch; expected %v, got %v", g.x, g.op, g.y, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	// Compare against big.Float, which rounds the square root correctly.
+	r := rand.New(rand.NewSource(1234))
+	for i := 0; i < 10000; i++ {
+		f := NewFromBits(r.Uint64()&0x7FFFFFFFFFFFFFFF, r.Uint64())
+		if i%4 == 0 {
+			// Denormalized number.
+			f.a &= 0x0000FFFFFFFFFFFF
+		}
+		if f.isNaN() || f.isInf() {
+			continue
+		}
+		x, _ := f.Big()
+		want, _ := NewFromBig(new(big.Float).SetPrec(precision).Sqrt(x))
+		got, _ := f.Sqrt()
+		if want != got {
+			t.Errorf("sqrt(0x%016X%016X): bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", f.a, f.b, want.a, want.b, got.a, got.b)
+		}
+	}
+}
+
+// fromHex returns the floating-point number with the given 32 digit hexadecimal
+// representation, or +0 if s is empty.
+func fromHex(s string) Float {
+	if len(s) == 0 {
+		return Zero
+	}
+	a, err := strconv.ParseUint(s[:16], 16, 64)
+	if err != nil {
+		panic(err)
+	}
+	b, err := strconv.ParseUint(s[16:], 16, 64)
+	if err != nil {
+		panic(err)
+	}
+	return NewFromBits(a, b)
+}
+
+// first returns the floating-point number of a result and accuracy pair.
+func first(f Float, acc big.Accuracy) Float {
+	return f
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"math/rand"
 	"testing"
+
+	"github.com/mewmew/float/fenv"
 )
 
 func TestNewFromBits(t *testing.T) {
@@ -90,11 +93,11 @@ func TestNewFromFloat64(t *testing.T) {
 		acc  big.Accuracy
 	}{
 		// Special numbers.
-		// 0x7FFF 8000000000000000000000000000 = +NaN
-		{in: math.NaN(), a: 0x7FFF800000000000, b: 0x0000000000000000, acc: big.Exact},
+		// 0x7FFF 8000000000001000000000000000 = +NaN (payload 1)
+		{in: math.NaN(), a: 0x7FFF800000000000, b: 0x1000000000000000, acc: big.Exact},
 		// -NaN
-		// 0xFFFF 8000000000000000000000000000 = -NaN
-		{in: -math.NaN(), a: 0xFFFF800000000000, b: 0x0000000000000000, acc: big.Exact},
+		// 0xFFFF 8000000000001000000000000000 = -NaN (payload 1)
+		{in: -math.NaN(), a: 0xFFFF800000000000, b: 0x1000000000000000, acc: big.Exact},
 		// +inf
 		// 0x7FFF0000000000000000000000000000 = +inf
 		{in: math.Inf(+1), a: 0x7FFF000000000000, b: 0x0000000000000000, acc: big.Exact},
@@ -190,3 +193,258 @@ func div(x, y *big.Float) *big.Float {
 func newFloat(x float64) *big.Float {
 	return big.NewFloat(0).SetPrec(precision).SetFloat64(x)
 }
+
+func TestNewFromFloat64Mode(t *testing.T) {
+	// Every float32 and float64 is exactly representable, so the result is
+	// independent of the rounding mode.
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	xs := []float64{0, math.Copysign(0, -1), 1.0 / 3, -1.0 / 3, math.MaxFloat64, -math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1)}
+	for _, x := range xs {
+		want, _ := NewFromFloat64(x)
+		want32, _ := NewFromFloat32(float32(x))
+		for _, mode := range modes {
+			if got, acc := NewFromFloat64Mode(x, mode); got != want || acc != big.Exact {
+				t.Errorf("%v %v: float64 mismatch; expected %v (%v), got %v (%v)", x, mode, want, big.Exact, got, acc)
+			}
+			if got, acc := NewFromFloat32Mode(float32(x), mode); got != want32 || acc != big.Exact {
+				t.Errorf("%v %v: float32 mismatch; expected %v (%v), got %v (%v)", float32(x), mode, want32, big.Exact, got, acc)
+			}
+		}
+	}
+}
+
+func TestNewFromBigMode(t *testing.T) {
+	golden := []struct {
+		in   string
+		mode big.RoundingMode
+		a, b uint64
+		acc  big.Accuracy
+	}{
+		// 1 + 2^(-113), halfway between 1 and the next number.
+		{in: "0x1.00000000000000000000000000008p0", mode: big.ToNearestEven, a: 0x3FFF000000000000, b: 0x0000000000000000, acc: big.Below},
+		{in: "0x1.00000000000000000000000000008p0", mode: big.ToNearestAway, a: 0x3FFF000000000000, b: 0x0000000000000001, acc: big.Above},
+		{in: "-0x1.00000000000000000000000000008p0", mode: big.ToZero, a: 0xBFFF000000000000, b: 0x0000000000000000, acc: big.Above},
+		{in: "-0x1.00000000000000000000000000008p0", mode: big.ToNegativeInf, a: 0xBFFF000000000000, b: 0x0000000000000001, acc: big.Below},
+		// 1/3
+		{in: "0.333333333333333333333333333333333333333333", mode: big.ToNearestEven, a: 0x3FFD555555555555, b: 0x5555555555555555, acc: big.Below},
+		{in: "0.333333333333333333333333333333333333333333", mode: big.AwayFromZero, a: 0x3FFD555555555555, b: 0x5555555555555556, acc: big.Above},
+		// Overflow.
+		{in: "1e5000", mode: big.ToNearestEven, a: 0x7FFF000000000000, b: 0x0000000000000000, acc: big.Above},
+		{in: "1e5000", mode: big.ToZero, a: 0x7FFEFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, acc: big.Below},
+		{in: "-1e5000", mode: big.ToPositiveInf, a: 0xFFFEFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, acc: big.Above},
+		{in: "-1e5000", mode: big.ToNegativeInf, a: 0xFFFF000000000000, b: 0x0000000000000000, acc: big.Below},
+		// Underflow.
+		{in: "1e-5000", mode: big.ToNearestEven, a: 0x0000000000000000, b: 0x0000000000000000, acc: big.Below},
+		{in: "1e-5000", mode: big.ToPositiveInf, a: 0x0000000000000000, b: 0x0000000000000001, acc: big.Above},
+		{in: "-1e-5000", mode: big.AwayFromZero, a: 0x8000000000000000, b: 0x0000000000000001, acc: big.Below},
+	}
+	for _, g := range golden {
+		x, _, err := big.ParseFloat(g.in, 0, 1000, big.ToNearestEven)
+		if err != nil {
+			panic(err)
+		}
+		got, acc := NewFromBigMode(x, g.mode)
+		if a, b := got.Bits(); a != g.a || b != g.b {
+			t.Errorf("%q %v: bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.in, g.mode, g.a, g.b, a, b)
+		}
+		if g.acc != acc {
+			t.Errorf("%q %v: accuracy mismatch; expected %v, got %v", g.in, g.mode, g.acc, acc)
+		}
+	}
+}
+
+func TestNewFromBigModes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		a, b := r.Uint64(), r.Uint64()
+		if i%4 == 0 {
+			// Denormalized number.
+			a &= 0x8000FFFFFFFFFFFF
+		}
+		f := NewFromBits(a, b)
+		if f.isNaN() || f.isInf() {
+			continue
+		}
+		// The number, and numbers halfway and a quarter of the way to the
+		// next number of larger magnitude.
+		x, _ := f.Big()
+		y, _ := NewFromBits(inc128(a, b)).Big()
+		if y.IsInf() {
+			y.SetMantExp(big.NewFloat(float64(y.Sign())), 16384)
+		}
+		x.SetPrec(256)
+		half := new(big.Float).Add(x, y)
+		half.SetMantExp(half, -1)
+		quarter := new(big.Float).Add(x, half)
+		quarter.SetMantExp(quarter, -1)
+		for _, x := range []*big.Float{x, half, quarter} {
+			if x.Sign() != 0 {
+				testModes(t, x)
+			}
+		}
+	}
+}
+
+func TestConversionFlags(t *testing.T) {
+	golden := []struct {
+		in   string
+		mode big.RoundingMode
+		a, b uint64
+		// Exception flags raised with tininess detected after and before
+		// rounding.
+		after, before fenv.Flags
+	}{
+		// 1
+		{in: "1", mode: big.ToNearestEven, a: 0x3FFF000000000000, b: 0x0000000000000000},
+		// 1/3
+		{in: "0.333333333333333333333333333333333333333333", mode: big.ToNearestEven, a: 0x3FFD555555555555, b: 0x5555555555555555, after: fenv.Inexact, before: fenv.Inexact},
+		// Overflow.
+		{in: "1e5000", mode: big.ToNearestEven, a: 0x7FFF000000000000, b: 0x0000000000000000, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{in: "1e5000", mode: big.ToZero, a: 0x7FFEFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// Underflow.
+		{in: "1e-5000", mode: big.ToNearestEven, a: 0x0000000000000000, b: 0x0000000000000000, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: "-1e-5000", mode: big.AwayFromZero, a: 0x8000000000000000, b: 0x0000000000000001, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-16382) - 2^(-16499), which is rounded to 2^(-16382) with 113
+		// bits.
+		{in: "0x1.fffffffffffffffffffffffffffffp-16383", mode: big.ToNearestEven, a: 0x0001000000000000, b: 0x0000000000000000, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: "0x1.fffffffffffffffffffffffffffffp-16383", mode: big.ToPositiveInf, a: 0x0001000000000000, b: 0x0000000000000000, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: "0x1.fffffffffffffffffffffffffffffp-16383", mode: big.ToZero, a: 0x0000FFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		x, _, err := big.ParseFloat(g.in, 0, 1000, big.ToNearestEven)
+		if err != nil {
+			panic(err)
+		}
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx := &Context{Tininess: tininess}
+			got, _ := ctx.NewFromBigMode(x, g.mode)
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if a, b := got.Bits(); a != g.a || b != g.b {
+				t.Errorf("%q %v: bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.in, g.mode, g.a, g.b, a, b)
+			}
+			if want != ctx.Flags {
+				t.Errorf("%q %v with tininess detected %v: flags mismatch; expected %v, got %v", g.in, g.mode, tininess, want, ctx.Flags)
+			}
+		}
+	}
+	// Conversions to float64.
+	float64Golden := []struct {
+		a, b uint64
+		want float64
+		// Exception flags raised with tininess detected after and before
+		// rounding.
+		after, before fenv.Flags
+	}{
+		// 1
+		{a: 0x3FFF000000000000, b: 0x0000000000000000, want: 1},
+		// 1 + 2^(-60)
+		{a: 0x3FFF000000000000, b: 0x0010000000000000, want: 1, after: fenv.Inexact, before: fenv.Inexact},
+		// 2^1024
+		{a: 0x43FF000000000000, b: 0x0000000000000000, want: math.Inf(1), after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// 2^(-1074) (tiny but exact)
+		{a: 0x3BCD000000000000, b: 0x0000000000000000, want: 0x1p-1074},
+		// 2^(-1075) (tie, round to even)
+		{a: 0x3BCC000000000000, b: 0x0000000000000000, want: 0, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-1022) - 2^(-1135), which is rounded to 2^(-1022) with 53 bits.
+		{a: 0x3C00FFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, want: 0x1p-1022, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// sNaN
+		{a: 0x7FFF000000000000, b: 0x0000000000000001, want: math.NaN(), after: fenv.Invalid, before: fenv.Invalid},
+	}
+	for _, g := range float64Golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx := &Context{Tininess: tininess}
+			got, _ := ctx.Float64(NewFromBits(g.a, g.b))
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if got != g.want && !(math.IsNaN(got) && math.IsNaN(g.want)) {
+				t.Errorf("0x%016X%016X: float64 mismatch; expected %v, got %v", g.a, g.b, g.want, got)
+			}
+			if want != ctx.Flags {
+				t.Errorf("0x%016X%016X to float64 with tininess detected %v: flags mismatch; expected %v, got %v", g.a, g.b, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+// testModes checks the conversion of the finite non-zero x in each rounding
+// mode against its neighbours in quadruple precision.
+func testModes(t *testing.T, x *big.Float) {
+	t.Helper()
+	down, downAcc := NewFromBigMode(x, big.ToNegativeInf)
+	up, upAcc := NewFromBigMode(x, big.ToPositiveInf)
+	d, _ := down.Big()
+	u, _ := up.Big()
+	if downAcc == big.Exact || upAcc == big.Exact {
+		if down != up || downAcc != upAcc {
+			t.Errorf("%v: exact mismatch; got %v (%v) and %v (%v)", x, down, downAcc, up, upAcc)
+		}
+		return
+	}
+	// Successor of down.
+	a, b := down.Bits()
+	switch {
+	case !down.Signbit():
+		a, b = inc128(a, b)
+	case b == 0:
+		a, b = a-1, math.MaxUint64
+	default:
+		b--
+	}
+	if next := NewFromBits(a, b); next != up || downAcc != big.Below || upAcc != big.Above || d.Cmp(x) >= 0 || u.Cmp(x) <= 0 {
+		t.Errorf("%v: neighbours mismatch; got %v (%v) and %v (%v)", x, down, downAcc, up, upAcc)
+		return
+	}
+	toward, away := down, up
+	if x.Signbit() {
+		toward, away = up, down
+	}
+	// Round to nearest, with infinities replaced by 2^16384.
+	if d.IsInf() {
+		d.SetMantExp(big.NewFloat(-1), 16384)
+	}
+	if u.IsInf() {
+		u.SetMantExp(big.NewFloat(1), 16384)
+	}
+	mid := new(big.Float).SetPrec(256).Add(d, u)
+	mid.SetMantExp(mid, -1)
+	c := x.Cmp(mid)
+	nearestEven, nearestAway := down, down
+	switch {
+	case c > 0:
+		nearestEven, nearestAway = up, up
+	case c == 0:
+		nearestAway = away
+		if _, b := down.Bits(); b&1 != 0 {
+			nearestEven = up
+		}
+	}
+	wants := map[big.RoundingMode]Float{
+		big.ToNearestEven: nearestEven,
+		big.ToNearestAway: nearestAway,
+		big.ToZero:        toward,
+		big.AwayFromZero:  away,
+	}
+	for mode, want := range wants {
+		got, acc := NewFromBigMode(x, mode)
+		wantAcc := big.Below
+		if want == up {
+			wantAcc = big.Above
+		}
+		if want != got || wantAcc != acc {
+			t.Errorf("%v %v: mismatch; expected %v (%v), got %v (%v)", x, mode, want, wantAcc, got, acc)
+		}
+	}
+}
+
+// inc128 returns the 128-bit integer a:b incremented by one.
+func inc128(a, b uint64) (uint64, uint64) {
+	if b == math.MaxUint64 {
+		return a + 1, 0
+	}
+	return a, b + 1
+}
@@ -0,0 +1,78 @@
+package binary128
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClass(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		a, b  uint64
+		class float.Class
+	}{
+		{a: 0xFFFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.QuietNaN},
+		{a: 0xFFFF800000000000, b: 0x0000000000000000, class: float.QuietNaN},
+		{a: 0xFFFF7FFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.SignalingNaN},
+		{a: 0xFFFF000000000000, b: 0x0000000000000001, class: float.SignalingNaN},
+		{a: 0xFFFF000000000000, b: 0x0000000000000000, class: float.NegativeInfinity},
+		{a: 0xFFFEFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{a: 0xBFFF000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x8001000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x8000FFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.NegativeSubnormal},
+		{a: 0x8000000000000000, b: 0x0000000000000001, class: float.NegativeSubnormal},
+		{a: 0x8000000000000000, b: 0x0000000000000000, class: float.NegativeZero},
+		{a: 0x0000000000000000, b: 0x0000000000000000, class: float.PositiveZero},
+		{a: 0x0000000000000000, b: 0x0000000000000001, class: float.PositiveSubnormal},
+		{a: 0x0000000000000000, b: 0xFFFFFFFFFFFFFFFF, class: float.PositiveSubnormal},
+		{a: 0x0000000000000001, b: 0x0000000000000000, class: float.PositiveSubnormal},
+		{a: 0x0000FFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.PositiveSubnormal},
+		{a: 0x0001000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x3FFF000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x7FFEFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.PositiveNormal},
+		{a: 0x7FFF000000000000, b: 0x0000000000000000, class: float.PositiveInfinity},
+		{a: 0x7FFF000000000000, b: 0x0000000000000001, class: float.SignalingNaN},
+		{a: 0x7FFF7FFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.SignalingNaN},
+		{a: 0x7FFF800000000000, b: 0x0000000000000000, class: float.QuietNaN},
+		{a: 0x7FFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewFromBits(a.a, a.b)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X%016X: class mismatch; expected %v, got %v", a.a, a.b, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X%016X: predicate mismatch for class %v", a.a, a.b, a.class)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding.
+	golden := []struct {
+		xa, xb, ya, yb uint64
+		cmp            int
+		total          bool
+	}{
+		// Subnormal numbers spanning both words.
+		{xa: 0x0000000000000000, xb: 0xFFFFFFFFFFFFFFFF, ya: 0x0000000000000001, yb: 0x0000000000000000, cmp: -1, total: true},
+		{xa: 0x8000000000000001, xb: 0x0000000000000000, ya: 0x8000000000000000, yb: 0xFFFFFFFFFFFFFFFF, cmp: -1, total: true},
+		// Largest subnormal and smallest normal number.
+		{xa: 0x0000FFFFFFFFFFFF, xb: 0xFFFFFFFFFFFFFFFF, ya: 0x0001000000000000, yb: 0x0000000000000000, cmp: -1, total: true},
+		// Positive and negative zero.
+		{xa: 0x0000000000000000, xb: 0x0000000000000000, ya: 0x8000000000000000, yb: 0x0000000000000000, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{xa: 0x7FFF7FFFFFFFFFFF, xb: 0xFFFFFFFFFFFFFFFF, ya: 0x7FFF800000000000, yb: 0x0000000000000000, cmp: 0, total: true},
+		{xa: 0xFFFF7FFFFFFFFFFF, xb: 0xFFFFFFFFFFFFFFFF, ya: 0xFFFF800000000000, yb: 0x0000000000000000, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.xa, g.xb), NewFromBits(g.ya, g.yb)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%016X%016X.Cmp(0x%016X%016X): mismatch; expected %d, got %d", g.xa, g.xb, g.ya, g.yb, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%016X%016X.TotalOrder(0x%016X%016X): mismatch; expected %v, got %v", g.xa, g.xb, g.ya, g.yb, g.total, got)
+		}
+	}
+}
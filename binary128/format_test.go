@@ -0,0 +1,125 @@
+package binary128
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	golden := []struct {
+		in   string
+		fmt  byte
+		prec int
+		want string
+	}{
+		// Special numbers.
+		{in: "00000000000000000000000000000000", fmt: 'g', prec: -1, want: "0"},
+		{in: "80000000000000000000000000000000", fmt: 'e', prec: 3, want: "-0.000e+00"},
+		{in: "7FFF0000000000000000000000000000", fmt: 'g', prec: -1, want: "+Inf"},
+		{in: "FFFF0000000000000000000000000000", fmt: 'f', prec: 2, want: "-Inf"},
+		{in: "7FFF8000000000000000000000000000", fmt: 'g', prec: -1, want: "NaN"},
+
+		{in: "3FFF0000000000000000000000000000", fmt: 'g', prec: -1, want: "1"},
+		{in: "3FFF0000000000000000000000000000", fmt: 'e', prec: 5, want: "1.00000e+00"},
+		{in: "3FFF0000000000000000000000000000", fmt: 'b', prec: -1, want: "5192296858534827628530496329220096p-112"},
+		{in: "3FFB999999999999999999999999999A", fmt: 'g', prec: -1, want: "0.1"},
+		{in: "3FFB999999999999999999999999999A", fmt: 'G', prec: 40, want: "0.1000000000000000000000000000000000048148"},
+		{in: "3FFB999999999999999999999999999A", fmt: 'x', prec: -1, want: "0x1.999999999999999999999999999ap-04"},
+		{in: "3FFB999999999999999999999999999A", fmt: 'X', prec: 3, want: "0X1.99AP-04"},
+		{in: "4000921FB54442D18469898CC51701B8", fmt: 'g', prec: -1, want: "3.1415926535897932384626433832795028"},
+		{in: "4000921FB54442D18469898CC51701B8", fmt: 'f', prec: 3, want: "3.142"},
+		{in: "40340000000000000000000000000000", fmt: 'g', prec: -1, want: "9.007199254740992e+15"},
+		{in: "40340000000000000000000000000000", fmt: 'f', prec: -1, want: "9007199254740992"},
+		// max
+		{in: "7FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", fmt: 'g', prec: -1, want: "1.189731495357231765085759326628007e+4932"},
+		{in: "7FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", fmt: 'E', prec: 5, want: "1.18973E+4932"},
+		// min normal
+		{in: "00010000000000000000000000000000", fmt: 'g', prec: -1, want: "3.3621031431120935062626778173217526e-4932"},
+		// min denormal
+		{in: "00000000000000000000000000000001", fmt: 'g', prec: -1, want: "6e-4966"},
+		{in: "00000000000000000000000000000001", fmt: 'e', prec: 10, want: "6.4751751194e-4966"},
+		{in: "80000000000000000000000000000001", fmt: 'b', prec: -1, want: "-1p-16494"},
+	}
+	for _, g := range golden {
+		f := fromHex(g.in)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("%s %c %d: text mismatch; expected %q, got %q", g.in, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		a, b := r.Uint64(), r.Uint64()
+		if i%2 == 1 {
+			// Denormalized number.
+			a &= 0x8000FFFFFFFFFFFF
+		}
+		f := NewFromBits(a, b)
+		if f.isNaN() || f.isInf() {
+			continue
+		}
+		s := f.String()
+		got, _, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if f != got {
+			t.Errorf("%q: round-trip mismatch; expected %016X%016X, got %016X%016X", s, f.a, f.b, got.a, got.b)
+			continue
+		}
+		// The nearest decimal with one digit less does not round-trip.
+		e := f.Text('e', -1)
+		nd := strings.IndexByte(e, 'e') - 1
+		if f.Signbit() {
+			nd--
+		}
+		if nd < 2 {
+			continue
+		}
+		if s := f.Text('e', nd-2); mustParse(s) == f {
+			t.Errorf("%q: not shortest; %q also round-trips", e, s)
+		}
+	}
+}
+
+func mustParse(s string) Float {
+	f, _, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		in     string
+		want   string
+	}{
+		{format: "%v", in: "3FFF0000000000000000000000000000", want: "1"},
+		{format: "%+.3e", in: "3FFF0000000000000000000000000000", want: "+1.000e+00"},
+		{format: "%.40G", in: "3FFB999999999999999999999999999A", want: "0.1000000000000000000000000000000000048148"},
+		{format: "%#v", in: "3FFF0000000000000000000000000000", want: "1"},
+		{format: "%#g", in: "3FFF0000000000000000000000000000", want: "1.00000"},
+		{format: "%8.2f|", in: "BFFF0000000000000000000000000000", want: "   -1.00|"},
+		{format: "%-8.2f|", in: "BFFF0000000000000000000000000000", want: "-1.00   |"},
+		{format: "%08.2f", in: "BFFF0000000000000000000000000000", want: "-0001.00"},
+		{format: "% x", in: "3FFF0000000000000000000000000000", want: " 0x1p+00"},
+		{format: "%F", in: "3FFF0000000000000000000000000000", want: "1.000000"},
+		{format: "%v", in: "7FFF0000000000000000000000000000", want: "+Inf"},
+		{format: "%6v", in: "7FFF8000000000000000000000000000", want: "   NaN"},
+		{format: "%s", in: "3FFF0000000000000000000000000000", want: "%!s(binary128.Float=1)"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, fromHex(g.in))
+		if g.want != got {
+			t.Errorf("%q %v: output mismatch; expected %q, got %q", g.format, g.in, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package binary128
+
+import (
+	"fmt"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// ParseLLVM parses s as an LLVM IR hexadecimal floating-point literal of type
+// fp128; "0xL" followed by the 32 hexadecimal digits of the binary
+// representation, with the least significant 64 bits first, and returns the
+// corresponding floating-point number. If s is syntactically invalid, the error
+// wraps strconv.ErrSyntax.
+func ParseLLVM(s string) (Float, error) {
+	words, err := strconv.ParseLLVMHex(s, 'L', 64, 64)
+	if err != nil {
+		return Float{}, fmt.Errorf("binary128.ParseLLVM: parsing %q: %w", s, err)
+	}
+	return NewFromBits(words[1], words[0]), nil
+}
+
+// LLVMString returns the LLVM IR hexadecimal floating-point literal of f, as
+// accepted by ParseLLVM; e.g. "0xL00000000000000003FFF000000000000" for 1.0.
+func (f Float) LLVMString() string {
+	a, b := f.Bits()
+	return fmt.Sprintf("0xL%016X%016X", b, a)
+}
@@ -0,0 +1,48 @@
+package binary128
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLLVM(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "0xL00000000000000003FFF000000000000", want: "1"},
+		{in: "0xL0000000000000000BFFF800000000000", want: "-1.5"},
+		{in: "0xL8469898CC51701B84000921FB54442D1", want: "3.1415926535897932384626433832795028"},
+		{in: "0xL999999999999999a3ffb999999999999", want: "0.1"},
+		{in: "0xL00000000000000010000000000000000", want: "6e-4966"},
+		{in: "0xL00000000000000007FFF000000000000", want: "+Inf"},
+		{in: "0xL00000000000000007FFF800000000000", want: "NaN"},
+		{in: "0xL00000000000000008000000000000000", want: "-0"},
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "0xL00000000000000003FFF00000000000", err: strconv.ErrSyntax},
+		{in: "0xL00000000000000003FFF0000000000000", err: strconv.ErrSyntax},
+		{in: "0xM00000000000000003FFF000000000000", err: strconv.ErrSyntax},
+		{in: "0xL0000000000000000 3FFF00000000000", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, err := ParseLLVM(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err != nil {
+			continue
+		}
+		if s := got.String(); g.want != s {
+			t.Errorf("%q: value mismatch; expected %v, got %v", g.in, g.want, s)
+		}
+		// Round-trip.
+		if s := got.LLVMString(); strings.ToUpper(g.in[3:]) != s[3:] || g.in[:3] != s[:3] {
+			t.Errorf("%q: round-trip mismatch; got %q", g.in, s)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package binary128
+
+import (
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+)
+
+// NewNaN returns a Not-a-Number value with the given payload, which is quiet
+// unless signaling is set. The payload is the fraction without the quiet bit,
+// given as the upper 47 bits and the lower 64 bits, and truncated to 111 bits.
+// A signaling NaN with a zero payload has payload 1, as its encoding would
+// otherwise be infinity.
+func NewNaN(signaling bool, hi, lo uint64) Float {
+	hi &= 0x00007FFFFFFFFFFF
+	if !signaling {
+		return Float{a: 0x7FFF000000000000 | quiet | hi, b: lo}
+	}
+	if hi == 0 && lo == 0 {
+		lo = 1
+	}
+	return Float{a: 0x7FFF000000000000 | hi, b: lo}
+}
+
+// IsSignaling reports whether f is a signaling Not-a-Number value.
+func (f Float) IsSignaling() bool {
+	return f.isNaN() && f.a&quiet == 0
+}
+
+// Payload returns the payload of the Not-a-Number value f, i.e. its fraction
+// without the quiet bit, as the upper 47 bits and the lower 64 bits; or 0 if f
+// is not NaN.
+func (f Float) Payload() (hi, lo uint64) {
+	if !f.isNaN() {
+		return 0, 0
+	}
+	return f.a & 0x00007FFFFFFFFFFF, f.b
+}
+
+// Quiet returns f with the quiet bit set if f is a signaling Not-a-Number value,
+// and f otherwise. The sign and payload are preserved.
+func (f Float) Quiet() Float {
+	if !f.isNaN() {
+		return f
+	}
+	return Float{a: f.a | quiet, b: f.b}
+}
+
+// newNaN returns the quiet NaN with the given sign and the fraction frac aligned
+// at the most significant bit, extended with zero bits. The invalid operation
+// exception is raised if frac is the fraction of a signaling NaN.
+func (ctx *Context) newNaN(sign bool, frac uint64) Float {
+	if frac&payload.Quiet == 0 {
+		ctx.Flags |= fenv.Invalid
+	}
+	f := Float{a: 0x7FFF000000000000 | quiet | frac>>16, b: frac << 48}
+	if sign {
+		return f.neg()
+	}
+	return f
+}
+
+// nanFrac returns the fraction of the Not-a-Number value f aligned at the most
+// significant bit, truncated to 64 bits.
+func (f Float) nanFrac() uint64 {
+	hi, lo := f.Frac()
+	return hi<<16 | lo>>48
+}
@@ -0,0 +1,107 @@
+package binary128
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewNaN(t *testing.T) {
+	golden := []struct {
+		signaling bool
+		hi, lo    uint64
+		a, b      uint64
+	}{
+		{signaling: false, a: 0x7FFF800000000000},
+		{signaling: false, hi: 0x0000555555555555, lo: 0xAAAAAAAAAAAAAAAA, a: 0x7FFFD55555555555, b: 0xAAAAAAAAAAAAAAAA},
+		{signaling: true, hi: 0x0000555555555555, lo: 0xAAAAAAAAAAAAAAAA, a: 0x7FFF555555555555, b: 0xAAAAAAAAAAAAAAAA},
+		// Zero payload of signaling NaN.
+		{signaling: true, a: 0x7FFF000000000000, b: 1},
+		// Payload truncated to 111 bits.
+		{signaling: false, hi: math.MaxUint64, lo: math.MaxUint64, a: 0x7FFFFFFFFFFFFFFF, b: math.MaxUint64},
+		{signaling: true, hi: 0x0000800000000000, a: 0x7FFF000000000000, b: 1},
+	}
+	for _, g := range golden {
+		f := NewNaN(g.signaling, g.hi, g.lo)
+		if a, b := f.Bits(); g.a != a || g.b != b {
+			t.Errorf("NewNaN(%v, 0x%016X, 0x%016X): bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.signaling, g.hi, g.lo, g.a, g.b, a, b)
+			continue
+		}
+		if f.IsSignaling() != g.signaling {
+			t.Errorf("0x%016X%016X: signaling mismatch; expected %v, got %v", g.a, g.b, g.signaling, f.IsSignaling())
+		}
+		if hi, lo := f.Payload(); hi != g.a&0x00007FFFFFFFFFFF || lo != g.b {
+			t.Errorf("0x%016X%016X: payload mismatch; got 0x%016X%016X", g.a, g.b, hi, lo)
+		}
+		if q := f.Quiet(); q != NewFromBits(g.a|0x0000800000000000, g.b) || q.IsSignaling() {
+			t.Errorf("0x%016X%016X: quiet mismatch; got %v", g.a, g.b, q)
+		}
+	}
+	// Numbers other than NaN.
+	for _, f := range []Float{Inf, NegInf, Zero, NegZero, NewFromBits(0x3FFF000000000000, 0)} {
+		if hi, lo := f.Payload(); f.IsSignaling() || hi != 0 || lo != 0 || f.Quiet() != f {
+			t.Errorf("%v: unexpected NaN", f)
+		}
+	}
+}
+
+func TestNaNConversion(t *testing.T) {
+	golden32 := []struct {
+		in    uint32
+		a, b  uint64
+		flags fenv.Flags
+	}{
+		{in: 0x7FC00000, a: 0x7FFF800000000000},
+		{in: 0xFFD55555, a: 0xFFFFAAAAAA000000},
+		// Signaling NaN is quieted.
+		{in: 0x7F800001, a: 0x7FFF800002000000, flags: fenv.Invalid},
+	}
+	for _, g := range golden32 {
+		ctx := new(Context)
+		f, _ := ctx.NewFromFloat32(math.Float32frombits(g.in))
+		if a, b := f.Bits(); g.a != a || g.b != b || g.flags != ctx.Flags {
+			t.Errorf("0x%08X: float32 conversion mismatch; expected 0x%016X%016X (%v), got 0x%016X%016X (%v)", g.in, g.a, g.b, g.flags, a, b, ctx.Flags)
+		}
+	}
+	golden64 := []struct {
+		in    uint64
+		a, b  uint64
+		flags fenv.Flags
+	}{
+		{in: 0x7FFAAAAAAAAAAAAA, a: 0x7FFFAAAAAAAAAAAA, b: 0xA000000000000000},
+		// Signaling NaN is quieted.
+		{in: 0xFFF0000000000001, a: 0xFFFF800000000000, b: 0x1000000000000000, flags: fenv.Invalid},
+	}
+	for _, g := range golden64 {
+		ctx := new(Context)
+		f, _ := ctx.NewFromFloat64(math.Float64frombits(g.in))
+		if a, b := f.Bits(); g.a != a || g.b != b || g.flags != ctx.Flags {
+			t.Errorf("0x%016X: float64 conversion mismatch; expected 0x%016X%016X (%v), got 0x%016X%016X (%v)", g.in, g.a, g.b, g.flags, a, b, ctx.Flags)
+		}
+		// Round-trip.
+		if x, _ := f.Float64(); math.Float64bits(x) != g.in|0x0008000000000000 {
+			t.Errorf("0x%016X: round-trip mismatch; got 0x%016X", g.in, math.Float64bits(x))
+		}
+	}
+
+	// The most significant bits of the payload are kept.
+	golden := []struct {
+		a, b   uint64
+		want32 uint32
+		want64 uint64
+	}{
+		{a: 0x7FFF800000000000, want32: 0x7FC00000, want64: 0x7FF8000000000000},
+		{a: 0xFFFFAAAAAAAAAAAA, b: 0xAAAAAAAAAAAAAAAA, want32: 0xFFD55555, want64: 0xFFFAAAAAAAAAAAAA},
+		{a: 0x7FFF000000000000, b: 1, want32: 0x7FC00000, want64: 0x7FF8000000000000},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.a, g.b)
+		if x, _ := f.Float32(); math.Float32bits(x) != g.want32 {
+			t.Errorf("0x%016X%016X: Float32 mismatch; expected 0x%08X, got 0x%08X", g.a, g.b, g.want32, math.Float32bits(x))
+		}
+		if x, _ := f.Float64(); math.Float64bits(x) != g.want64 {
+			t.Errorf("0x%016X%016X: Float64 mismatch; expected 0x%016X, got 0x%016X", g.a, g.b, g.want64, math.Float64bits(x))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package binary128
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	// Expected results computed using glibc's strtof128.
+	golden := []struct {
+		in   string
+		want string
+		acc  big.Accuracy
+		err  error
+	}{
+		// Special numbers.
+		{in: "0", want: "00000000000000000000000000000000", acc: big.Exact},
+		{in: "-0.0e10", want: "80000000000000000000000000000000", acc: big.Exact},
+		{in: "inf", want: "7FFF0000000000000000000000000000", acc: big.Exact},
+		{in: "-Infinity", want: "FFFF0000000000000000000000000000", acc: big.Exact},
+		{in: "+NaN", want: "7FFF8000000000000000000000000000", acc: big.Exact},
+		{in: "-nan", want: "FFFF8000000000000000000000000000", acc: big.Exact},
+
+		{in: "1", want: "3FFF0000000000000000000000000000", acc: big.Exact},
+		{in: "0.1", want: "3FFB999999999999999999999999999A", acc: big.Above},
+		{in: "-2.5e-3", want: "BFF647AE147AE147AE147AE147AE147B", acc: big.Below},
+		{in: "3.14159265358979323846264338327950288", want: "4000921FB54442D18469898CC51701B8", acc: big.Below},
+		{in: "0x1.8p-3", want: "3FFC8000000000000000000000000000", acc: big.Exact},
+		{in: "0X1.8P-3", want: "3FFC8000000000000000000000000000", acc: big.Exact},
+		// max + half ulp = +Inf (tie, round to even)
+		{in: "0x1.ffffffffffffffffffffffffffff8p16383", want: "7FFF0000000000000000000000000000", acc: big.Above, err: strconv.ErrRange},
+		// max
+		{in: "1.18973149535723176508575932662800703e4932", want: "7FFEFFFFFFFFFFFFFFFFFFFFFFFFFFFF", acc: big.Below},
+		{in: "1.2e4932", want: "7FFF0000000000000000000000000000", acc: big.Above, err: strconv.ErrRange},
+		{in: "-1e5000", want: "FFFF0000000000000000000000000000", acc: big.Below, err: strconv.ErrRange},
+		// Denormalized numbers.
+		{in: "-0x1p-16494", want: "80000000000000000000000000000001", acc: big.Exact},
+		{in: "6.475175119438025110924438958227646552e-4966", want: "00000000000000000000000000000001", acc: big.Above},
+		// 3.2e-4966 is below half of the smallest denormalized number.
+		{in: "3.2e-4966", want: "00000000000000000000000000000000", acc: big.Below},
+		{in: "3.3e-4966", want: "00000000000000000000000000000001", acc: big.Above},
+		{in: "1.9e-4951", want: "000000000000000000010ADF1D047805", acc: big.Above},
+		{in: "1e-5000", want: "00000000000000000000000000000000", acc: big.Below},
+
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "1e", err: strconv.ErrSyntax},
+		{in: "0x1.8", err: strconv.ErrSyntax},
+		{in: "1.2.3", err: strconv.ErrSyntax},
+		{in: " 1", err: strconv.ErrSyntax},
+		{in: "infinit", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if want := fromHex(g.want); want != got {
+			t.Errorf("%q: bits mismatch; expected 0x%s, got 0x%016X%016X", g.in, g.want, got.a, got.b)
+		}
+		if g.acc != acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
@@ -0,0 +1,534 @@
+package binary16
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+const (
+	// emin specifies the exponent of the smallest normalized number.
+	emin = 1 - bias
+	// emax specifies the exponent of the largest normalized number.
+	emax = bias
+	// quiet specifies the quiet bit of Not-a-Number values.
+	quiet = 0x0200
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the arithmetic operations and conversions performed
+// in the context. The zero value detects tininess after rounding and has no
+// flags raised.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// Add returns the sum f+g, rounded to nearest even, and the accuracy of the
+// result.
+func (f Float) Add(g Float) (Float, big.Accuracy) {
+	return new(Context).Add(f, g)
+}
+
+// Sub returns the difference f-g, rounded to nearest even, and the accuracy of
+// the result.
+func (f Float) Sub(g Float) (Float, big.Accuracy) {
+	return new(Context).Sub(f, g)
+}
+
+// Mul returns the product f*g, rounded to nearest even, and the accuracy of the
+// result.
+func (f Float) Mul(g Float) (Float, big.Accuracy) {
+	return new(Context).Mul(f, g)
+}
+
+// Div returns the quotient f/g, rounded to nearest even, and the accuracy of
+// the result.
+func (f Float) Div(g Float) (Float, big.Accuracy) {
+	return new(Context).Div(f, g)
+}
+
+// Sqrt returns the square root of f, rounded to nearest even, and the accuracy
+// of the result.
+func (f Float) Sqrt() (Float, big.Accuracy) {
+	return new(Context).Sqrt(f)
+}
+
+// FMA returns the fused multiply-add f*g+h, computed with only one rounding
+// (to nearest even), and the accuracy of the result.
+func (f Float) FMA(g, h Float) (Float, big.Accuracy) {
+	return new(Context).FMA(f, g, h)
+}
+
+// Add returns the sum x+y, rounded to nearest even, and the accuracy of the
+// result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Add(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	return ctx.add(x, y)
+}
+
+// Sub returns the difference x-y, rounded to nearest even, and the accuracy of
+// the result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Sub(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	return ctx.add(x, y.neg())
+}
+
+// Mul returns the product x*y, rounded to nearest even, and the accuracy of the
+// result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Mul(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf() || y.isInf():
+		// Inf*0 is invalid.
+		if x.isZero() || y.isZero() {
+			return ctx.invalid(), big.Exact
+		}
+		return inf(sign), big.Exact
+	}
+	return ctx.round(mul(x.unpack(), y.unpack()))
+}
+
+// Div returns the quotient x/y, rounded to nearest even, and the accuracy of
+// the result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Div(x, y Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() {
+		return ctx.propagate(x, y), big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf():
+		// Inf/Inf is invalid.
+		if y.isInf() {
+			return ctx.invalid(), big.Exact
+		}
+		return inf(sign), big.Exact
+	case y.isInf():
+		return zero(sign), big.Exact
+	case y.isZero():
+		// 0/0 is invalid.
+		if x.isZero() {
+			return ctx.invalid(), big.Exact
+		}
+		// Division by zero.
+		ctx.Flags |= fenv.DivByZero
+		return inf(sign), big.Exact
+	}
+	a, b := x.unpack(), y.unpack()
+	// Scale the dividend so that the quotient holds at least precision+2
+	// significant bits, and jam the remainder into a sticky bit.
+	const shift = 32
+	q := a.mant << shift / b.mant
+	if q*b.mant != a.mant<<shift {
+		q |= 1
+	}
+	return ctx.round(unpacked{sign: sign, mant: q, exp: a.exp - b.exp - shift})
+}
+
+// Sqrt returns the square root of x, rounded to nearest even, and the accuracy
+// of the result. The exception flags raised are recorded in ctx.
+func (ctx *Context) Sqrt(x Float) (Float, big.Accuracy) {
+	switch {
+	case x.isNaN():
+		return ctx.propagate(x), big.Exact
+	case x.isZero():
+		// sqrt(+-0) = +-0
+		return x, big.Exact
+	case x.Signbit():
+		// Square root of negative number is invalid.
+		return ctx.invalid(), big.Exact
+	case x.isInf():
+		return Inf, big.Exact
+	}
+	a := x.unpack()
+	// Make the exponent even and scale the radicand so that the root holds at
+	// least precision+2 significant bits, and jam the remainder into a sticky
+	// bit.
+	if a.exp&1 != 0 {
+		a.mant <<= 1
+		a.exp--
+	}
+	const shift = 40
+	a.mant <<= shift
+	a.exp -= shift
+	r := isqrt(a.mant)
+	if r*r != a.mant {
+		r |= 1
+	}
+	return ctx.round(unpacked{mant: r, exp: a.exp / 2})
+}
+
+// FMA returns the fused multiply-add x*y+z, computed with only one rounding
+// (to nearest even), and the accuracy of the result. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) FMA(x, y, z Float) (Float, big.Accuracy) {
+	if x.isNaN() || y.isNaN() || z.isNaN() {
+		return ctx.propagate(x, y, z), big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf() || y.isInf():
+		// Inf*0 and Inf-Inf are invalid.
+		if x.isZero() || y.isZero() || (z.isInf() && z.Signbit() != sign) {
+			return ctx.invalid(), big.Exact
+		}
+		return inf(sign), big.Exact
+	case z.isInf():
+		return z, big.Exact
+	}
+	return ctx.round(addUnpacked(mul(x.unpack(), y.unpack()), z.unpack()))
+}
+
+// add returns the sum x+y of the non-NaN operands x and y, rounded to nearest
+// even, and the accuracy of the result.
+func (ctx *Context) add(x, y Float) (Float, big.Accuracy) {
+	switch {
+	case x.isInf():
+		// Inf-Inf is invalid.
+		if y.isInf() && y.Signbit() != x.Signbit() {
+			return ctx.invalid(), big.Exact
+		}
+		return x, big.Exact
+	case y.isInf():
+		return y, big.Exact
+	}
+	return ctx.round(addUnpacked(x.unpack(), y.unpack()))
+}
+
+// ### [ Helper functions ] ####################################################
+
+// unpacked is a finite floating-point number in unpacked form, representing
+// the value (-1)^sign * mant * 2^exp.
+type unpacked struct {
+	sign bool
+	mant uint64
+	exp  int
+}
+
+// unpack returns the unpacked form of the finite floating-point number f.
+func (f Float) unpack() unpacked {
+	sign := f.Signbit()
+	mant := uint64(f.Frac())
+	exp := f.Exp()
+	if exp == 0 {
+		// Denormalized number.
+		//
+		//    (-1)^signbit * 2^(-14) * 0.mant_2
+		return unpacked{sign: sign, mant: mant, exp: emin - (precision - 1)}
+	}
+	// Normalized number.
+	//
+	//    (-1)^signbit * 2^(exp-15) * 1.mant_2
+	mant |= 1 << (precision - 1)
+	return unpacked{sign: sign, mant: mant, exp: exp - bias - (precision - 1)}
+}
+
+// unpackBig returns the unpacked form of the finite non-zero x, truncated to 62
+// bits with the bits shifted out jammed into the least significant bit.
+func unpackBig(x *big.Float) unpacked {
+	const prec = 62
+	y := new(big.Float).SetMode(big.ToZero).SetPrec(prec).Set(x)
+	mant := new(big.Float)
+	exp := y.MantExp(mant)
+	m, _ := mant.Abs(mant).SetMantExp(mant, prec).Uint64()
+	if y.Acc() != big.Exact {
+		m |= 1
+	}
+	return unpacked{sign: x.Signbit(), mant: m, exp: exp - prec}
+}
+
+// unpackFloat64 returns the unpacked form of the finite non-zero x.
+func unpackFloat64(x float64) unpacked {
+	bits := math.Float64bits(x)
+	mant := bits & 0x000FFFFFFFFFFFFF
+	exp := int(bits >> 52 & 0x7FF)
+	if exp == 0 {
+		// Denormalized number.
+		//
+		//    (-1)^signbit * 2^(-1022) * 0.mant_2
+		exp = 1
+	} else {
+		// Normalized number.
+		//
+		//    (-1)^signbit * 2^(exp-1023) * 1.mant_2
+		mant |= 1 << 52
+	}
+	return unpacked{sign: bits&0x8000000000000000 != 0, mant: mant, exp: exp - 1023 - 52}
+}
+
+// mul returns the exact product x*y. The mantissas of x and y must be at most
+// 32 bits wide.
+func mul(x, y unpacked) unpacked {
+	return unpacked{sign: x.sign != y.sign, mant: x.mant * y.mant, exp: x.exp + y.exp}
+}
+
+// addUnpacked returns the sum x+y. The mantissas of x and y must be at most 32
+// bits wide. Bits shifted out when aligning the operands are jammed into the
+// least significant bit of the sum, which holds at least precision+2
+// significant bits when inexact.
+func addUnpacked(x, y unpacked) unpacked {
+	switch {
+	case x.mant == 0 && y.mant == 0:
+		// +0 + -0 = +0, in round to nearest.
+		return unpacked{sign: x.sign && y.sign}
+	case x.mant == 0:
+		return y
+	case y.mant == 0:
+		return x
+	}
+	// Let x be the operand of larger magnitude.
+	if x.exp+bits.Len64(x.mant) < y.exp+bits.Len64(y.mant) {
+		x, y = y, x
+	}
+	// Align the operands.
+	if d := x.exp - y.exp; d > 0 {
+		// Shift x left as far as possible, and jam the bits of y shifted out on
+		// the right into a sticky bit.
+		s := 62 - bits.Len64(x.mant)
+		if s > d {
+			s = d
+		}
+		x.mant <<= uint(s)
+		x.exp -= s
+		y.mant = jam(y.mant, uint(d-s))
+	} else {
+		y.mant <<= uint(-d)
+	}
+	switch {
+	case x.sign == y.sign:
+		return unpacked{sign: x.sign, mant: x.mant + y.mant, exp: x.exp}
+	case x.mant > y.mant:
+		return unpacked{sign: x.sign, mant: x.mant - y.mant, exp: x.exp}
+	case x.mant < y.mant:
+		return unpacked{sign: y.sign, mant: y.mant - x.mant, exp: x.exp}
+	}
+	// x + -x = +0, in round to nearest.
+	return unpacked{exp: x.exp}
+}
+
+// jam returns x shifted right by s bits, with the bits shifted out jammed into
+// the least significant bit of the result.
+func jam(x uint64, s uint) uint64 {
+	if s >= 64 {
+		if x != 0 {
+			return 1
+		}
+		return 0
+	}
+	if x&(1<<s-1) != 0 {
+		return x>>s | 1
+	}
+	return x >> s
+}
+
+// round returns the nearest half precision floating-point number to x, using
+// round half to even, and the accuracy of the result. The exception flags
+// raised are recorded in ctx. The least significant bit of x.mant is a sticky
+// bit when x is inexact.
+func (ctx *Context) round(x unpacked) (Float, big.Accuracy) {
+	return ctx.roundMode(x, big.ToNearestEven)
+}
+
+// roundMode returns x rounded to a half precision floating-point number in the
+// given rounding mode, and the accuracy of the result. The exception flags
+// raised are recorded in ctx. The least significant bit of x.mant is a sticky
+// bit when x is inexact.
+func (ctx *Context) roundMode(x unpacked, mode big.RoundingMode) (Float, big.Accuracy) {
+	z, acc, flags := roundMode(x, mode, ctx.Tininess)
+	ctx.Flags |= flags
+	return z, acc
+}
+
+// roundMode returns x rounded to a half precision floating-point number in the
+// given rounding mode, the accuracy of the result and the exception flags
+// raised, with tininess detected as specified by tininess. The least
+// significant bit of x.mant is a sticky bit when x is inexact.
+func roundMode(x unpacked, mode big.RoundingMode, tininess fenv.Tininess) (Float, big.Accuracy, fenv.Flags) {
+	if x.mant == 0 {
+		return zero(x.sign), big.Exact, 0
+	}
+	// Exponent of the most significant bit of x, and of the least significant
+	// bit of the result.
+	top := x.exp + bits.Len64(x.mant) - 1
+	lsb := top - (precision - 1)
+	if lsb < emin-(precision-1) {
+		lsb = emin - (precision - 1)
+	}
+	mant := x.mant
+	acc := big.Exact
+	if s := lsb - x.exp; s > 0 {
+		// Comparison of the remainder with half a unit in the last place; the
+		// remainder is less than half if s > 64.
+		var rem uint64
+		half := -1
+		switch {
+		case s > 64:
+			rem, mant = mant, 0
+		case s == 64:
+			rem, mant = mant, 0
+			half = cmp(rem, 1<<63)
+		default:
+			rem = mant & (1<<uint(s) - 1)
+			half = cmp(rem, 1<<uint(s-1))
+			mant >>= uint(s)
+		}
+		if rem != 0 {
+			acc = big.Below
+			if rounding.Up(mode, x.sign, mant&1 != 0, half) {
+				mant++
+				acc = big.Above
+			}
+			if x.sign {
+				acc = -acc
+			}
+		}
+		if mant == 1<<precision {
+			mant >>= 1
+			lsb++
+		}
+	} else {
+		mant <<= uint(-s)
+	}
+	var flags fenv.Flags
+	if acc != big.Exact {
+		flags = fenv.Inexact
+		if top < emin && !(tininess == fenv.AfterRounding && top == emin-1 && carries(x, mode)) {
+			flags |= fenv.Underflow
+		}
+	}
+	// Exponent of the most significant bit of the result.
+	exp := lsb + precision - 1
+	if exp > emax {
+		// Overflow to +-Inf, or to the largest finite number of the same sign
+		// in directed rounding modes.
+		flags |= fenv.Overflow | fenv.Inexact
+		if !rounding.Inf(mode, x.sign) {
+			if x.sign {
+				return Float{bits: 0xFBFF}, big.Above, flags
+			}
+			return Float{bits: 0x7BFF}, big.Below, flags
+		}
+		if x.sign {
+			return NegInf, big.Below, flags
+		}
+		return Inf, big.Above, flags
+	}
+	// The implicit lead bit of normalized numbers is added to the exponent.
+	v := uint16(exp+bias-1)<<10 + uint16(mant)
+	if x.sign {
+		v |= 0x8000
+	}
+	return Float{bits: v}, acc, flags
+}
+
+// carries reports whether x, with the most significant bit at exponent emin-1,
+// is rounded up to 2^emin in magnitude when rounded to the precision of
+// normalized numbers in the given rounding mode; i.e. whether x is not tiny
+// when tininess is detected after rounding.
+func carries(x unpacked, mode big.RoundingMode) bool {
+	// Scale x into the range of normalized numbers.
+	x.exp++
+	z, _, _ := roundMode(x, mode, fenv.AfterRounding)
+	return z.Exp() > 1
+}
+
+// cmp returns -1 if x < y, 0 if x == y and +1 if x > y.
+func cmp(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// isqrt returns the integer square root of x, rounded down. x must be less than
+// 2^53.
+func isqrt(x uint64) uint64 {
+	r := uint64(math.Sqrt(float64(x)))
+	for r*r > x {
+		r--
+	}
+	for (r+1)*(r+1) <= x {
+		r++
+	}
+	return r
+}
+
+// propagate returns the first NaN of the given floating-point numbers, with its
+// quiet bit set. The invalid operation exception is raised if any of the
+// numbers is a signaling NaN.
+func (ctx *Context) propagate(fs ...Float) Float {
+	var nan Float
+	found := false
+	for _, f := range fs {
+		if !f.isNaN() {
+			continue
+		}
+		if f.bits&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		if !found {
+			nan, found = f, true
+		}
+	}
+	if !found {
+		panic("binary16.propagate: no NaN operand")
+	}
+	return Float{bits: nan.bits | quiet}
+}
+
+// invalid raises the invalid operation exception and returns the default NaN.
+func (ctx *Context) invalid() Float {
+	ctx.Flags |= fenv.Invalid
+	return NaN
+}
+
+// inf returns +Inf if sign is false and -Inf otherwise.
+func inf(sign bool) Float {
+	if sign {
+		return NegInf
+	}
+	return Inf
+}
+
+// zero returns +0 if sign is false and -0 otherwise.
+func zero(sign bool) Float {
+	if sign {
+		return NegZero
+	}
+	return Zero
+}
+
+// neg returns -f.
+func (f Float) neg() Float {
+	return Float{bits: f.bits ^ 0x8000}
+}
+
+// isNaN reports whether f is Not-a-Number.
+func (f Float) isNaN() bool {
+	return f.Exp() == 0x1F && f.Frac() != 0
+}
+
+// isInf reports whether f is +Inf or -Inf.
+func (f Float) isInf() bool {
+	return f.Exp() == 0x1F && f.Frac() == 0
+}
+
+// isZero reports whether f is +0 or -0.
+func (f Float) isZero() bool {
+	return f.bits&0x7FFF == 0
+}
@@ -0,0 +1,373 @@
+package binary16
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+func TestArith(t *testing.T) {
+	golden := []struct {
+		op   string
+		x, y uint16
+		want uint16
+		acc  big.Accuracy
+	}{
+		// 1 + 1 = 2
+		{op: "+", x: 0x3C00, y: 0x3C00, want: 0x4000, acc: big.Exact},
+		// 1 + 2^(-11) = 1 (tie, round to even)
+		{op: "+", x: 0x3C00, y: 0x1000, want: 0x3C00, acc: big.Below},
+		// 1.0009765625 + 2^(-11) = 1.001953125 (tie, round to even)
+		{op: "+", x: 0x3C01, y: 0x1000, want: 0x3C02, acc: big.Above},
+		// 65504 + 16 = +Inf (tie, round to even)
+		{op: "+", x: 0x7BFF, y: 0x4C00, want: 0x7C00, acc: big.Above},
+		// -65504 - 16 = -Inf
+		{op: "-", x: 0xFBFF, y: 0x4C00, want: 0xFC00, acc: big.Below},
+		// 65504 + 8 = 65504
+		{op: "+", x: 0x7BFF, y: 0x4800, want: 0x7BFF, acc: big.Below},
+		// 1 - 1 = +0
+		{op: "-", x: 0x3C00, y: 0x3C00, want: 0x0000, acc: big.Exact},
+		// -0 + -0 = -0
+		{op: "+", x: 0x8000, y: 0x8000, want: 0x8000, acc: big.Exact},
+		// -0 - +0 = -0
+		{op: "-", x: 0x8000, y: 0x0000, want: 0x8000, acc: big.Exact},
+		// +0 + -0 = +0
+		{op: "+", x: 0x0000, y: 0x8000, want: 0x0000, acc: big.Exact},
+		// 2^(-14) - 2^(-24) = 0x03FF (largest subnormal)
+		{op: "-", x: 0x0400, y: 0x0001, want: 0x03FF, acc: big.Exact},
+		// +Inf - +Inf = NaN
+		{op: "-", x: 0x7C00, y: 0x7C00, want: 0x7E00, acc: big.Exact},
+		// +Inf + 1 = +Inf
+		{op: "+", x: 0x7C00, y: 0x3C00, want: 0x7C00, acc: big.Exact},
+		// sNaN + 1 = qNaN (payload preserved)
+		{op: "+", x: 0x7C01, y: 0x3C00, want: 0x7E01, acc: big.Exact},
+		// 1 + -qNaN = -qNaN
+		{op: "+", x: 0x3C00, y: 0xFE05, want: 0xFE05, acc: big.Exact},
+		// 1 - qNaN = qNaN (sign preserved)
+		{op: "-", x: 0x3C00, y: 0x7E05, want: 0x7E05, acc: big.Exact},
+
+		// 2^(-14) * 2^(-1) = 2^(-15) (subnormal)
+		{op: "*", x: 0x0400, y: 0x3800, want: 0x0200, acc: big.Exact},
+		// 2^(-24) * 0.5 = +0 (tie, round to even)
+		{op: "*", x: 0x0001, y: 0x3800, want: 0x0000, acc: big.Below},
+		// 2^(-24) * 1.5 = 2^(-23) (tie, round to even)
+		{op: "*", x: 0x0001, y: 0x3E00, want: 0x0002, acc: big.Above},
+		// -2^(-24) * 0.75 = -2^(-24)
+		{op: "*", x: 0x8001, y: 0x3A00, want: 0x8001, acc: big.Below},
+		// 256 * 256 = +Inf
+		{op: "*", x: 0x5C00, y: 0x5C00, want: 0x7C00, acc: big.Above},
+		// +Inf * -0 = NaN
+		{op: "*", x: 0x7C00, y: 0x8000, want: 0x7E00, acc: big.Exact},
+		// -1 * +0 = -0
+		{op: "*", x: 0xBC00, y: 0x0000, want: 0x8000, acc: big.Exact},
+
+		// 1 / 3 = 0.333251953125
+		{op: "/", x: 0x3C00, y: 0x4200, want: 0x3555, acc: big.Below},
+		// 1 / -0 = -Inf
+		{op: "/", x: 0x3C00, y: 0x8000, want: 0xFC00, acc: big.Exact},
+		// 0 / 0 = NaN
+		{op: "/", x: 0x0000, y: 0x0000, want: 0x7E00, acc: big.Exact},
+		// -1 / +Inf = -0
+		{op: "/", x: 0xBC00, y: 0x7C00, want: 0x8000, acc: big.Exact},
+		// Inf / Inf = NaN
+		{op: "/", x: 0x7C00, y: 0xFC00, want: 0x7E00, acc: big.Exact},
+		// 2^(-24) / 2 = +0 (tie, round to even)
+		{op: "/", x: 0x0001, y: 0x4000, want: 0x0000, acc: big.Below},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.x), NewFromBits(g.y)
+		var got Float
+		var acc big.Accuracy
+		switch g.op {
+		case "+":
+			got, acc = x.Add(y)
+		case "-":
+			got, acc = x.Sub(y)
+		case "*":
+			got, acc = x.Mul(y)
+		case "/":
+			got, acc = x.Div(y)
+		}
+		if g.want != got.Bits() {
+			t.Errorf("0x%04X %s 0x%04X: bits mismatch; expected 0x%04X, got 0x%04X", g.x, g.op, g.y, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("0x%04X %s 0x%04X: accuracy mismatch; expected %v, got %v", g.x, g.op, g.y, g.acc, acc)
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	golden := []struct {
+		in   uint16
+		want uint16
+		acc  big.Accuracy
+	}{
+		// sqrt(4) = 2
+		{in: 0x4400, want: 0x4000, acc: big.Exact},
+		// sqrt(2) = 1.4140625
+		{in: 0x4000, want: 0x3DA8, acc: big.Below},
+		// sqrt(-0) = -0
+		{in: 0x8000, want: 0x8000, acc: big.Exact},
+		// sqrt(-1) = NaN
+		{in: 0xBC00, want: 0x7E00, acc: big.Exact},
+		// sqrt(+Inf) = +Inf
+		{in: 0x7C00, want: 0x7C00, acc: big.Exact},
+		// sqrt(2^(-24)) = 2^(-12)
+		{in: 0x0001, want: 0x0C00, acc: big.Exact},
+	}
+	for _, g := range golden {
+		got, acc := NewFromBits(g.in).Sqrt()
+		if g.want != got.Bits() {
+			t.Errorf("sqrt(0x%04X): bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("sqrt(0x%04X): accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+	// Exhaustive check against float64, which has enough precision for the
+	// square root to be rounded twice without error.
+	for bits := 0; bits < 0x7C00; bits++ {
+		f := NewFromBits(uint16(bits))
+		x, _ := f.Float64()
+		want, wantAcc := nearest(big.NewFloat(math.Sqrt(x)))
+		got, acc := f.Sqrt()
+		if want != got || wantAcc != acc {
+			t.Errorf("sqrt(0x%04X): mismatch; expected 0x%04X (%v), got 0x%04X (%v)", bits, want.Bits(), wantAcc, got.Bits(), acc)
+		}
+	}
+}
+
+func TestArithRandom(t *testing.T) {
+	// Use deterministic source for pseudo-random numbers.
+	r := rand.New(rand.NewSource(1234))
+	for i := 0; i < 20000; i++ {
+		f, g, h := randFloat(r), randFloat(r), randFloat(r)
+		x, _ := f.Float64()
+		y, _ := g.Float64()
+		// The sum, difference and product are exact in float64, and the quotient
+		// has enough precision to be rounded twice without error.
+		check(t, "+", f, g, x+y, f.Add)
+		check(t, "-", f, g, x-y, f.Sub)
+		check(t, "*", f, g, x*y, f.Mul)
+		check(t, "/", f, g, x/y, f.Div)
+		// The fused multiply-add is computed exactly using big.Float.
+		got, acc := f.FMA(g, h)
+		z, _ := h.Float64()
+		if math.IsInf(x*y, 0) || math.IsInf(z, 0) || math.IsNaN(x*y+z) {
+			continue
+		}
+		exact := new(big.Float).SetPrec(200).SetFloat64(x * y)
+		exact.Add(exact, big.NewFloat(z))
+		want, wantAcc := nearest(exact)
+		if exact.Sign() == 0 {
+			want, wantAcc = NewFromFloat64(x*y + z)
+		}
+		if want != got || wantAcc != acc {
+			t.Errorf("0x%04X * 0x%04X + 0x%04X: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", f.Bits(), g.Bits(), h.Bits(), want.Bits(), wantAcc, got.Bits(), acc)
+		}
+	}
+}
+
+func TestArithFlags(t *testing.T) {
+	golden := []struct {
+		op   string
+		x, y uint16
+		want uint16
+		// Exception flags raised with tininess detected after and before
+		// rounding.
+		after, before fenv.Flags
+	}{
+		// 1 + 1 = 2
+		{op: "+", x: 0x3C00, y: 0x3C00, want: 0x4000},
+		// 1 + 2^(-11) = 1
+		{op: "+", x: 0x3C00, y: 0x1000, want: 0x3C00, after: fenv.Inexact, before: fenv.Inexact},
+		// 65504 + 16 = +Inf
+		{op: "+", x: 0x7BFF, y: 0x4C00, want: 0x7C00, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// 65504 + 8 = 65504
+		{op: "+", x: 0x7BFF, y: 0x4800, want: 0x7BFF, after: fenv.Inexact, before: fenv.Inexact},
+		// +Inf - +Inf = NaN
+		{op: "-", x: 0x7C00, y: 0x7C00, want: 0x7E00, after: fenv.Invalid, before: fenv.Invalid},
+		// sNaN + 1 = qNaN
+		{op: "+", x: 0x7C01, y: 0x3C00, want: 0x7E01, after: fenv.Invalid, before: fenv.Invalid},
+		// qNaN + 1 = qNaN
+		{op: "+", x: 0x7E01, y: 0x3C00, want: 0x7E01},
+		// 2^(-14) * 2^(-1) = 2^(-15) (tiny but exact)
+		{op: "*", x: 0x0400, y: 0x3800, want: 0x0200},
+		// 2^(-24) * 0.5 = +0
+		{op: "*", x: 0x0001, y: 0x3800, want: 0x0000, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// (2^(-14) - 2^(-24)) * (1 + 2^(-10)) = 2^(-14) - 2^(-34), which is
+		// rounded to 2^(-14) both with and without bounded exponent range.
+		{op: "*", x: 0x03FF, y: 0x3C01, want: 0x0400, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// (2^(-14) - 2^(-24)) * (1 - 2^(-11)) = 2^(-14) - 1.5*2^(-24) + 2^(-35),
+		// which is tiny also after rounding.
+		{op: "*", x: 0x03FF, y: 0x3BFF, want: 0x03FF, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 256 * 256 = +Inf
+		{op: "*", x: 0x5C00, y: 0x5C00, want: 0x7C00, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// +Inf * -0 = NaN
+		{op: "*", x: 0x7C00, y: 0x8000, want: 0x7E00, after: fenv.Invalid, before: fenv.Invalid},
+		// 1 / 0 = +Inf
+		{op: "/", x: 0x3C00, y: 0x0000, want: 0x7C00, after: fenv.DivByZero, before: fenv.DivByZero},
+		// 0 / 0 = NaN
+		{op: "/", x: 0x0000, y: 0x0000, want: 0x7E00, after: fenv.Invalid, before: fenv.Invalid},
+		// +Inf / 0 = +Inf
+		{op: "/", x: 0x7C00, y: 0x0000, want: 0x7C00},
+		// 1 / 3 = 0.33325195
+		{op: "/", x: 0x3C00, y: 0x4200, want: 0x3555, after: fenv.Inexact, before: fenv.Inexact},
+		// sqrt(-1) = NaN
+		{op: "sqrt", x: 0xBC00, want: 0x7E00, after: fenv.Invalid, before: fenv.Invalid},
+		// sqrt(-0) = -0
+		{op: "sqrt", x: 0x8000, want: 0x8000},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx := &Context{Tininess: tininess}
+			x, y := NewFromBits(g.x), NewFromBits(g.y)
+			var got Float
+			switch g.op {
+			case "+":
+				got, _ = ctx.Add(x, y)
+			case "-":
+				got, _ = ctx.Sub(x, y)
+			case "*":
+				got, _ = ctx.Mul(x, y)
+			case "/":
+				got, _ = ctx.Div(x, y)
+			case "sqrt":
+				got, _ = ctx.Sqrt(x)
+			}
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if g.want != got.Bits() {
+				t.Errorf("0x%04X %s 0x%04X: bits mismatch; expected 0x%04X, got 0x%04X", g.x, g.op, g.y, g.want, got.Bits())
+			}
+			if want != ctx.Flags {
+				t.Errorf("0x%04X %s 0x%04X with tininess detected %v: flags mismatch; expected %v, got %v", g.x, g.op, g.y, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+func TestArithFlagsRandom(t *testing.T) {
+	// Use deterministic source for pseudo-random numbers.
+	r := rand.New(rand.NewSource(1234))
+	for i := 0; i < 20000; i++ {
+		f, g := randFloat(r), randFloat(r)
+		if f.isInf() || g.isInf() {
+			continue
+		}
+		x, _ := f.Big()
+		y, _ := g.Big()
+		// The sum and product are exact with 64 bits of precision.
+		sum := new(big.Float).SetPrec(64).Add(x, y)
+		prod := new(big.Float).SetPrec(64).Mul(x, y)
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx := &Context{Tininess: tininess}
+			_, acc := ctx.Add(f, g)
+			if want := rounding.Flags(sum, acc, precision, emin, emax, big.ToNearestEven, tininess); want != ctx.Flags {
+				t.Errorf("0x%04X + 0x%04X with tininess detected %v: flags mismatch; expected %v, got %v", f.Bits(), g.Bits(), tininess, want, ctx.Flags)
+			}
+			ctx = &Context{Tininess: tininess}
+			_, acc = ctx.Mul(f, g)
+			if want := rounding.Flags(prod, acc, precision, emin, emax, big.ToNearestEven, tininess); want != ctx.Flags {
+				t.Errorf("0x%04X * 0x%04X with tininess detected %v: flags mismatch; expected %v, got %v", f.Bits(), g.Bits(), tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+// check compares the result of the operation op on f and g against the nearest
+// half precision floating-point number to the float64 result z.
+func check(t *testing.T, op string, f, g Float, z float64, fn func(Float) (Float, big.Accuracy)) {
+	t.Helper()
+	got, acc := fn(g)
+	var want Float
+	var wantAcc big.Accuracy
+	switch {
+	case math.IsNaN(z):
+		if !got.isNaN() {
+			t.Errorf("0x%04X %s 0x%04X: expected NaN, got 0x%04X", f.Bits(), op, g.Bits(), got.Bits())
+		}
+		return
+	case math.IsInf(z, 0), z == 0:
+		want, wantAcc = NewFromFloat64(z)
+	default:
+		want, wantAcc = nearest(big.NewFloat(z))
+	}
+	if want != got || wantAcc != acc {
+		t.Errorf("0x%04X %s 0x%04X: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", f.Bits(), op, g.Bits(), want.Bits(), wantAcc, got.Bits(), acc)
+	}
+}
+
+// randFloat returns a pseudo-random finite half precision floating-point
+// number or an infinity.
+func randFloat(r *rand.Rand) Float {
+	for {
+		f := NewFromBits(uint16(r.Intn(0x10000)))
+		if !f.isNaN() {
+			return f
+		}
+	}
+}
+
+// nearest returns the nearest half precision floating-point number to the
+// finite non-zero value x, using round half to even, and the accuracy of the
+// result. The result is located using binary search over the ordered bit
+// patterns of positive numbers.
+func nearest(x *big.Float) (Float, big.Accuracy) {
+	neg := x.Signbit()
+	abs := new(big.Float).Abs(x)
+	// value returns the value of the positive bit pattern.
+	value := func(bits uint16) *big.Float {
+		return big.NewFloat(values[bits])
+	}
+	var bits uint16
+	if abs.Cmp(value(0x7C00)) >= 0 {
+		bits = 0x7C00
+	} else {
+		// Locate the largest bit pattern not greater than abs.
+		lo, hi := 0, 0x7C00
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if value(uint16(mid)).Cmp(abs) <= 0 {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		bits = uint16(lo)
+		if value(bits).Cmp(abs) != 0 {
+			// Compare against the midpoint of bits and bits+1.
+			mid := new(big.Float).SetPrec(200).Add(value(bits), value(bits+1))
+			mid.Quo(mid, big.NewFloat(2))
+			if c := abs.Cmp(mid); c > 0 || (c == 0 && bits&1 != 0) {
+				bits++
+			}
+		}
+	}
+	acc := big.Accuracy(value(bits).Cmp(abs))
+	if bits == 0x7C00 {
+		acc = big.Above
+	}
+	if neg {
+		return NewFromBits(bits | 0x8000), -acc
+	}
+	return NewFromBits(bits), acc
+}
+
+// values maps from positive bit patterns to float64 values, where +Inf is
+// treated as 2^16 for the purpose of rounding.
+var values = func() []float64 {
+	vs := make([]float64, 0x7C01)
+	for bits := range vs {
+		vs[bits], _ = NewFromBits(uint16(bits)).Float64()
+	}
+	vs[0x7C00] = 65536
+	return vs
+}()
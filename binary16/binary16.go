@@ -7,9 +7,11 @@
 package binary16
 
 import (
-	"fmt"
 	"math"
 	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
 )
 
 const (
@@ -53,38 +55,105 @@ func NewFromBits(bits uint16) Float {
 }
 
 // NewFromFloat32 returns the nearest half precision floating-point number for x
-// and the accuracy of the conversion.
+// and the accuracy of the conversion. Not-a-Number values are converted to a
+// quiet NaN with the same sign, keeping the most significant bits of the
+// payload.
 func NewFromFloat32(x float32) (Float, big.Accuracy) {
-	f, acc := NewFromFloat64(float64(x))
-	if acc == big.Exact {
-		_, acc = f.Float32()
-	}
-	return f, acc
+	return new(Context).NewFromFloat32(x)
 }
 
 // NewFromFloat64 returns the nearest half precision floating-point number for x
-// and the accuracy of the conversion.
+// and the accuracy of the conversion. Not-a-Number values are converted to a
+// quiet NaN with the same sign, keeping the most significant bits of the
+// payload.
 func NewFromFloat64(x float64) (Float, big.Accuracy) {
-	// +-NaN
-	switch {
-	case math.IsNaN(x):
-		if math.Signbit(x) {
-			// -NaN
-			return NegNaN, big.Exact
-		}
-		// +NaN
-		return NaN, big.Exact
-	}
-	y := big.NewFloat(x)
-	y.SetPrec(precision)
-	y.SetMode(big.ToNearestEven)
-	// TODO: check accuracy after setting precision?
-	return NewFromBig(y)
+	return NewFromFloat64Mode(x, big.ToNearestEven)
 }
 
 // NewFromBig returns the nearest half precision floating-point number for x and
 // the accuracy of the conversion.
 func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromFloat32Mode returns the half precision floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion.
+func NewFromFloat32Mode(x float32, mode big.RoundingMode) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat32Mode(x, mode)
+}
+
+// NewFromFloat64Mode returns the half precision floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion.
+func NewFromFloat64Mode(x float64, mode big.RoundingMode) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat64Mode(x, mode)
+}
+
+// NewFromBigMode returns the half precision floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion. Values too
+// large in magnitude are converted to +-Inf, or to the largest finite number of
+// the same sign if the mode rounds them towards zero.
+func NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
+	return new(Context).NewFromBigMode(x, mode)
+}
+
+// NewFromFloat32 returns the nearest half precision floating-point number for x
+// and the accuracy of the conversion. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) NewFromFloat32(x float32) (Float, big.Accuracy) {
+	return ctx.NewFromFloat32Mode(x, big.ToNearestEven)
+}
+
+// NewFromFloat64 returns the nearest half precision floating-point number for x
+// and the accuracy of the conversion. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) NewFromFloat64(x float64) (Float, big.Accuracy) {
+	return ctx.NewFromFloat64Mode(x, big.ToNearestEven)
+}
+
+// NewFromBig returns the nearest half precision floating-point number for x and
+// the accuracy of the conversion. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return ctx.NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromFloat32Mode returns the half precision floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion. The
+// exception flags raised are recorded in ctx; a signaling NaN raises the
+// invalid operation exception.
+func (ctx *Context) NewFromFloat32Mode(x float32, mode big.RoundingMode) (Float, big.Accuracy) {
+	// +-NaN, with the payload of the binary representation of x; see package
+	// payload.
+	if math.IsNaN(float64(x)) {
+		return ctx.newNaN(payload.FromFloat32(x)), big.Exact
+	}
+	return ctx.NewFromFloat64Mode(float64(x), mode)
+}
+
+// NewFromFloat64Mode returns the half precision floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion. The
+// exception flags raised are recorded in ctx; a signaling NaN raises the
+// invalid operation exception.
+func (ctx *Context) NewFromFloat64Mode(x float64, mode big.RoundingMode) (Float, big.Accuracy) {
+	switch {
+	// +-NaN
+	case math.IsNaN(x):
+		return ctx.newNaN(payload.FromFloat64(x)), big.Exact
+	// +-Inf
+	case math.IsInf(x, 0):
+		return inf(math.Signbit(x)), big.Exact
+	// +-zero
+	case x == 0:
+		return zero(math.Signbit(x)), big.Exact
+	}
+	// The float64 mantissa is rounded directly, without the use of big.Float.
+	return ctx.roundMode(unpackFloat64(x), mode)
+}
+
+// NewFromBigMode returns the half precision floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion, as for
+// NewFromBigMode. The exception flags raised are recorded in ctx.
+func (ctx *Context) NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
 	// +-Inf
 	zero := big.NewFloat(0)
 	switch {
@@ -105,60 +174,7 @@ func NewFromBig(x *big.Float) (Float, big.Accuracy) {
 		return Zero, big.Exact
 	}
 
-	// Sign
-	var bits uint16
-	if x.Signbit() {
-		bits |= 0x8000
-	}
-
-	// Exponent and mantissa.
-	mant := new(big.Float)
-	exponent := x.MantExp(mant)
-	// Remove 1 from the exponent as big.Float has an no lead bit.
-	exp := exponent - 1 + bias
-
-	// Handle denormalized values.
-	// TODO: validate implementation of denormalized values.
-	if exp <= 0 {
-		acc := big.Exact
-		if exp <= -(precision - 1) {
-			exp = precision - 1
-			acc = big.Below
-		}
-		mant.SetMantExp(mant, exp+precision-1)
-		if mant.Signbit() {
-			mant.Neg(mant)
-		}
-		mantissa, _ := mant.Uint64()
-		// TODO: calculate acc based on if mantissa&^0x7FF != 0 {}
-		bits |= uint16(mantissa & 0x7FF)
-		return Float{bits: bits}, acc
-	}
-
-	// exponent mask (5 bits): 0b11111
-	acc := big.Exact
-	if (exp &^ 0x1F) != 0 {
-		acc = big.Above
-	}
-	bits |= uint16(exp&0x1F) << 10
-
-	if mant.Signbit() {
-		mant.Neg(mant)
-	}
-	mant.SetMantExp(mant, precision)
-	if !mant.IsInt() {
-		acc = big.Below
-	}
-	mantissa, _ := mant.Uint64()
-	mantissa &^= 0x400 // clear implicit lead bit; 2^10
-
-	// mantissa mask (11 bits, including implicit lead bit): 0b11111111111
-	if acc == big.Exact && (mantissa&^0x7FF) != 0 {
-		acc = big.Below
-	}
-	mantissa &= 0x7FF
-	bits |= uint16(mantissa)
-	return Float{bits: bits}, acc
+	return ctx.roundMode(unpackBig(x), mode)
 }
 
 // Bits returns the IEEE 754 half precision binary representation of f.
@@ -170,32 +186,46 @@ func (f Float) Bits() uint16 {
 // represented by a float32 (|f| < math.SmallestNonzeroFloat32), the result is
 // (0, Below) or (-0, Above), respectively, depending on the sign of f. If f is
 // too large to be represented by a float32 (|f| > math.MaxFloat32), the result
-// is (+Inf, Above) or (-Inf, Below), depending on the sign of f.
+// is (+Inf, Above) or (-Inf, Below), depending on the sign of f. Not-a-Number
+// values are converted to a quiet NaN with the same sign and payload.
 func (f Float) Float32() (float32, big.Accuracy) {
-	x, nan := f.Big()
-	if nan {
-		if x.Signbit() {
-			return float32(-math.NaN()), big.Exact
-		}
-		return float32(math.NaN()), big.Exact
-	}
-	return x.Float32()
+	return new(Context).Float32(f)
 }
 
 // Float64 returns the float64 value nearest to f. If f is too small to be
 // represented by a float64 (|f| < math.SmallestNonzeroFloat64), the result is
 // (0, Below) or (-0, Above), respectively, depending on the sign of f. If f is
 // too large to be represented by a float64 (|f| > math.MaxFloat64), the result
-// is (+Inf, Above) or (-Inf, Below), depending on the sign of f.
+// is (+Inf, Above) or (-Inf, Below), depending on the sign of f. Not-a-Number
+// values are converted to a quiet NaN with the same sign and payload.
 func (f Float) Float64() (float64, big.Accuracy) {
-	x, nan := f.Big()
-	if nan {
-		if x.Signbit() {
-			return -math.NaN(), big.Exact
+	return new(Context).Float64(f)
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float32(f Float) (float32, big.Accuracy) {
+	if f.isNaN() {
+		if f.bits&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		return payload.Float32(f.Signbit(), uint64(f.Frac())<<54), big.Exact
+	}
+	return float32(f.float64()), big.Exact
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float64(f Float) (float64, big.Accuracy) {
+	if f.isNaN() {
+		if f.bits&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
 		}
-		return math.NaN(), big.Exact
+		return payload.Float64(f.Signbit(), uint64(f.Frac())<<54), big.Exact
 	}
-	return x.Float64()
+	return f.float64(), big.Exact
 }
 
 // Big returns the multi-precision floating-point number representation of f and
@@ -209,14 +239,6 @@ func (f Float) Big() (x *big.Float, nan bool) {
 	x.SetMode(big.ToNearestEven)
 
 	// ref: https://en.wikipedia.org/wiki/Half-precision_floating-point_format#Exponent_encoding
-	//
-	// 0b00001 - 0b11110
-	// Normalized number.
-	//
-	//    (-1)^signbit * 2^(exp-15) * 1.mant_2
-	lead := 1
-	exponent := exp - bias
-
 	switch exp {
 	// 0b11111
 	case 0x1F:
@@ -240,25 +262,36 @@ func (f Float) Big() (x *big.Float, nan bool) {
 			}
 			return x, false
 		}
-		// Denormalized number.
-		//
-		//    (-1)^signbit * 2^(-14) * 0.mant_2
-		lead = 0
-		exponent = -14
 	}
 
-	// number = [ sign ] [ prefix ] mantissa [ exponent ] | infinity .
-	sign := "+"
+	// Normalized or denormalized number, exactly representable with the
+	// precision of x.
+	u := f.unpack()
+	x.SetInt64(int64(u.mant))
+	x.SetMantExp(x, u.exp)
 	if signbit {
-		sign = "-"
-	}
-	s := fmt.Sprintf("%s0b%d.%010bp%d", sign, lead, frac, exponent)
-	if _, _, err := x.Parse(s, 0); err != nil {
-		panic(err)
+		x.Neg(x)
 	}
 	return x, false
 }
 
+// float64 returns the value of the non-NaN f as a float64, which represents
+// every half precision number exactly.
+func (f Float) float64() float64 {
+	if f.isInf() {
+		if f.Signbit() {
+			return math.Inf(-1)
+		}
+		return math.Inf(1)
+	}
+	u := f.unpack()
+	x := math.Ldexp(float64(u.mant), u.exp)
+	if u.sign {
+		return math.Copysign(x, -1)
+	}
+	return x
+}
+
 // Signbit reports whether f is negative or negative 0.
 func (f Float) Signbit() bool {
 	// first bit is sign bit: 0b1000000000000000
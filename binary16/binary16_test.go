@@ -3,7 +3,10 @@ package binary16
 import (
 	"math"
 	"math/big"
+	"math/rand"
 	"testing"
+
+	"github.com/mewmew/float/fenv"
 )
 
 func TestNewFromBits(t *testing.T) {
@@ -13,10 +16,10 @@ func TestNewFromBits(t *testing.T) {
 	}{
 		// Special numbers.
 		// 0 11111 1000000000 = +NaN
-		{bits: 0x7E00, want: math.NaN()},
+		{bits: 0x7E00, want: math.Float64frombits(0x7FF8000000000000)},
 		// -NaN
 		// 1 11111 1000000000 = -NaN
-		{bits: 0xFE00, want: -math.NaN()},
+		{bits: 0xFE00, want: math.Float64frombits(0xFFF8000000000000)},
 
 		// from: https://en.wikipedia.org/wiki/Half-precision_floating-point_format#Half_precision_examples
 
@@ -232,3 +235,338 @@ func TestNewFromFloat64(t *testing.T) {
 		}
 	}
 }
+
+func TestNewFromFloat64Mode(t *testing.T) {
+	golden := []struct {
+		in   float64
+		mode big.RoundingMode
+		want uint16
+		acc  big.Accuracy
+	}{
+		// 1 + 2^(-11), halfway between 1 and the next number.
+		{in: 1 + 0x1p-11, mode: big.ToNearestEven, want: 0x3C00, acc: big.Below},
+		{in: 1 + 0x1p-11, mode: big.ToNearestAway, want: 0x3C01, acc: big.Above},
+		{in: 1 + 0x1p-11, mode: big.ToZero, want: 0x3C00, acc: big.Below},
+		{in: 1 + 0x1p-11, mode: big.AwayFromZero, want: 0x3C01, acc: big.Above},
+		{in: 1 + 0x1p-11, mode: big.ToNegativeInf, want: 0x3C00, acc: big.Below},
+		{in: 1 + 0x1p-11, mode: big.ToPositiveInf, want: 0x3C01, acc: big.Above},
+		{in: -1 - 0x1p-11, mode: big.ToNearestAway, want: 0xBC01, acc: big.Below},
+		{in: -1 - 0x1p-11, mode: big.ToZero, want: 0xBC00, acc: big.Above},
+		{in: -1 - 0x1p-11, mode: big.ToNegativeInf, want: 0xBC01, acc: big.Below},
+		{in: -1 - 0x1p-11, mode: big.ToPositiveInf, want: 0xBC00, acc: big.Above},
+		// 1/3
+		{in: 1.0 / 3, mode: big.ToNearestEven, want: 0x3555, acc: big.Below},
+		{in: 1.0 / 3, mode: big.ToPositiveInf, want: 0x3556, acc: big.Above},
+		{in: -1.0 / 3, mode: big.AwayFromZero, want: 0xB556, acc: big.Below},
+		// Overflow.
+		{in: 65520, mode: big.ToNearestEven, want: 0x7C00, acc: big.Above},
+		{in: 65520, mode: big.ToZero, want: 0x7BFF, acc: big.Below},
+		{in: 65505, mode: big.AwayFromZero, want: 0x7C00, acc: big.Above},
+		{in: 1e10, mode: big.ToNegativeInf, want: 0x7BFF, acc: big.Below},
+		{in: -1e10, mode: big.ToNegativeInf, want: 0xFC00, acc: big.Below},
+		{in: -1e10, mode: big.ToPositiveInf, want: 0xFBFF, acc: big.Above},
+		// Underflow.
+		{in: 0x1p-26, mode: big.ToNearestEven, want: 0x0000, acc: big.Below},
+		{in: 0x1p-26, mode: big.AwayFromZero, want: 0x0001, acc: big.Above},
+		{in: 0x1p-25, mode: big.ToNearestAway, want: 0x0001, acc: big.Above},
+		{in: -0x1p-26, mode: big.ToZero, want: 0x8000, acc: big.Above},
+		{in: -0x1p-26, mode: big.ToNegativeInf, want: 0x8001, acc: big.Below},
+		{in: -0x1p-100, mode: big.ToPositiveInf, want: 0x8000, acc: big.Above},
+		// Exact.
+		{in: -2.5, mode: big.ToZero, want: 0xC100, acc: big.Exact},
+		{in: math.Inf(1), mode: big.ToZero, want: 0x7C00, acc: big.Exact},
+	}
+	for _, g := range golden {
+		got, acc := NewFromFloat64Mode(g.in, g.mode)
+		if g.want != got.Bits() {
+			t.Errorf("%v %v: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.mode, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%v %v: accuracy mismatch; expected %v, got %v", g.in, g.mode, g.acc, acc)
+		}
+	}
+}
+
+func TestNewFromBigMode(t *testing.T) {
+	// Half precision numbers, and numbers halfway and a quarter of the way
+	// between them, of either sign.
+	var xs []*big.Float
+	for bits := 0; bits < 0x7C00; bits++ {
+		x, _ := NewFromBits(uint16(bits)).Big()
+		y, _ := NewFromBits(uint16(bits + 1)).Big()
+		if y.IsInf() {
+			y = big.NewFloat(65536)
+		}
+		x.SetPrec(64)
+		half := new(big.Float).Add(x, y)
+		half.SetMantExp(half, -1)
+		quarter := new(big.Float).Add(x, half)
+		quarter.SetMantExp(quarter, -1)
+		for _, x := range []*big.Float{x, half, quarter} {
+			xs = append(xs, x, new(big.Float).Neg(x))
+		}
+	}
+	xs = append(xs, big.NewFloat(1e10), big.NewFloat(-1e10), big.NewFloat(0x1p-30), big.NewFloat(-0x1p-30))
+	for _, x := range xs {
+		if x.Sign() == 0 {
+			continue
+		}
+		testModes(t, x)
+	}
+}
+
+func TestNewFromFloat64Flags(t *testing.T) {
+	golden := []struct {
+		in   float64
+		mode big.RoundingMode
+		want uint16
+		// Exception flags raised with tininess detected after and before
+		// rounding.
+		after, before fenv.Flags
+	}{
+		// 1
+		{in: 1, mode: big.ToNearestEven, want: 0x3C00},
+		// 1 + 2^(-12) (tie, round to even)
+		{in: 1 + 0x1p-12, mode: big.ToNearestEven, want: 0x3C00, after: fenv.Inexact, before: fenv.Inexact},
+		// 65519 (below halfway to 2^16)
+		{in: 65519, mode: big.ToNearestEven, want: 0x7BFF, after: fenv.Inexact, before: fenv.Inexact},
+		// 65520 (halfway to 2^16)
+		{in: 65520, mode: big.ToNearestEven, want: 0x7C00, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// 65536 (overflow to the largest finite number)
+		{in: 65536, mode: big.ToZero, want: 0x7BFF, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// -65536
+		{in: -65536, mode: big.ToPositiveInf, want: 0xFBFF, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// +Inf
+		{in: math.Inf(1), mode: big.ToNearestEven, want: 0x7C00},
+		// 2^(-24) (smallest denormal)
+		{in: 0x1p-24, mode: big.ToNearestEven, want: 0x0001},
+		// 2^(-25) (tie, round to even)
+		{in: 0x1p-25, mode: big.ToNearestEven, want: 0x0000, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-14) - 2^(-26), which is rounded to 2^(-14) with 11 bits.
+		{in: 0x1p-14 - 0x1p-26, mode: big.ToNearestEven, want: 0x0400, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-14) - 2^(-26) rounded towards zero.
+		{in: 0x1p-14 - 0x1p-26, mode: big.ToZero, want: 0x03FF, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-14) - 2^(-25) (tie, round to even), which is tiny also when
+		// rounded to 11 bits.
+		{in: 0x1p-14 - 0x1p-25, mode: big.ToNearestEven, want: 0x0400, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// qNaN
+		{in: math.NaN(), mode: big.ToNearestEven, want: 0x7E00},
+		// sNaN
+		{in: math.Float64frombits(0x7FF0000000000001), mode: big.ToNearestEven, want: 0x7E00, after: fenv.Invalid, before: fenv.Invalid},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx := &Context{Tininess: tininess}
+			got, _ := ctx.NewFromFloat64Mode(g.in, g.mode)
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if g.want != got.Bits() {
+				t.Errorf("%v %v: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.mode, g.want, got.Bits())
+			}
+			if want != ctx.Flags {
+				t.Errorf("%v %v with tininess detected %v: flags mismatch; expected %v, got %v", g.in, g.mode, tininess, want, ctx.Flags)
+			}
+		}
+	}
+	// Signaling NaN operands of conversions to float32 and float64.
+	ctx := new(Context)
+	if _, acc := ctx.Float64(NewFromBits(0x7E01)); acc != big.Exact || ctx.Flags != 0 {
+		t.Errorf("qNaN to float64: expected exact with no flags, got %v with %v", acc, ctx.Flags)
+	}
+	if _, acc := ctx.Float32(NewFromBits(0x7C01)); acc != big.Exact || ctx.Flags != fenv.Invalid {
+		t.Errorf("sNaN to float32: expected exact with %v, got %v with %v", fenv.Invalid, acc, ctx.Flags)
+	}
+}
+
+func TestNewFromFloat64Big(t *testing.T) {
+	// Half precision numbers, the float64 numbers adjacent to them and to the
+	// numbers halfway between them, of either sign, and pseudo-random numbers
+	// of magnitude between 2^(-30) and 2^20.
+	var xs []float64
+	for bits := 0; bits < 0x7C00; bits++ {
+		x, _ := NewFromBits(uint16(bits)).Float64()
+		y, _ := NewFromBits(uint16(bits + 1)).Float64()
+		if math.IsInf(y, 0) {
+			y = 65536
+		}
+		half := (x + y) / 2
+		for _, x := range []float64{x, half} {
+			for _, x := range []float64{math.Nextafter(x, 0), x, math.Nextafter(x, math.Inf(1))} {
+				xs = append(xs, x, -x)
+			}
+		}
+	}
+	// Use deterministic source for pseudo-random numbers.
+	r := rand.New(rand.NewSource(1234))
+	for i := 0; i < 100000; i++ {
+		x := math.Ldexp(r.Float64(), r.Intn(50)-30)
+		xs = append(xs, x, -x)
+	}
+	xs = append(xs, math.MaxFloat64, math.SmallestNonzeroFloat64, 1e10, 0x1p-30)
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	for _, x := range xs {
+		if x == 0 {
+			continue
+		}
+		for _, mode := range modes {
+			for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+				ctx := &Context{Tininess: tininess}
+				got, acc := ctx.NewFromFloat64Mode(x, mode)
+				wantCtx := &Context{Tininess: tininess}
+				want, wantAcc := wantCtx.NewFromBigMode(big.NewFloat(x), mode)
+				if want != got || wantAcc != acc || wantCtx.Flags != ctx.Flags {
+					t.Errorf("%v %v with tininess detected %v: mismatch; expected 0x%04X (%v, %v), got 0x%04X (%v, %v)", x, mode, tininess, want.Bits(), wantAcc, wantCtx.Flags, got.Bits(), acc, ctx.Flags)
+				}
+			}
+		}
+	}
+}
+
+func TestFloat64Big(t *testing.T) {
+	for bits := 0; bits < 0x10000; bits++ {
+		f := NewFromBits(uint16(bits))
+		x, nan := f.Big()
+		if nan {
+			continue
+		}
+		if x.Prec() != precision || x.Mode() != big.ToNearestEven || x.Acc() != big.Exact {
+			t.Errorf("0x%04X: big.Float mismatch; got precision %d, mode %v and accuracy %v", bits, x.Prec(), x.Mode(), x.Acc())
+		}
+		want, wantAcc := x.Float64()
+		got, acc := f.Float64()
+		if math.Float64bits(want) != math.Float64bits(got) || wantAcc != acc {
+			t.Errorf("0x%04X: float64 mismatch; expected %v (%v), got %v (%v)", bits, want, wantAcc, got, acc)
+		}
+		want32, wantAcc := x.Float32()
+		got32, acc := f.Float32()
+		if math.Float32bits(want32) != math.Float32bits(got32) || wantAcc != acc {
+			t.Errorf("0x%04X: float32 mismatch; expected %v (%v), got %v (%v)", bits, want32, wantAcc, got32, acc)
+		}
+	}
+}
+
+// testModes checks the conversion of the finite non-zero x in each rounding
+// mode against its neighbours in half precision.
+func testModes(t *testing.T, x *big.Float) {
+	t.Helper()
+	down, downAcc := NewFromBigMode(x, big.ToNegativeInf)
+	up, upAcc := NewFromBigMode(x, big.ToPositiveInf)
+	d, _ := down.Big()
+	u, _ := up.Big()
+	if downAcc == big.Exact || upAcc == big.Exact {
+		if down != up || downAcc != upAcc {
+			t.Errorf("%v: exact mismatch; got 0x%04X (%v) and 0x%04X (%v)", x, down.Bits(), downAcc, up.Bits(), upAcc)
+		}
+		return
+	}
+	// Successor of down.
+	next := down.Bits() + 1
+	if down.Signbit() {
+		next = down.Bits() - 1
+	}
+	if next != up.Bits() || downAcc != big.Below || upAcc != big.Above || d.Cmp(x) >= 0 || u.Cmp(x) <= 0 {
+		t.Errorf("%v: neighbours mismatch; got 0x%04X (%v) and 0x%04X (%v)", x, down.Bits(), downAcc, up.Bits(), upAcc)
+		return
+	}
+	toward, away := down, up
+	if x.Signbit() {
+		toward, away = up, down
+	}
+	// Round to nearest, with infinities replaced by 2^16.
+	if d.IsInf() {
+		d = big.NewFloat(-65536)
+	}
+	if u.IsInf() {
+		u = big.NewFloat(65536)
+	}
+	mid := new(big.Float).SetPrec(128).Add(d, u)
+	mid.SetMantExp(mid, -1)
+	c := x.Cmp(mid)
+	nearestEven, nearestAway := down, down
+	switch {
+	case c > 0:
+		nearestEven, nearestAway = up, up
+	case c == 0:
+		nearestAway = away
+		if down.Bits()&1 != 0 {
+			nearestEven = up
+		}
+	}
+	wants := map[big.RoundingMode]Float{
+		big.ToNearestEven: nearestEven,
+		big.ToNearestAway: nearestAway,
+		big.ToZero:        toward,
+		big.AwayFromZero:  away,
+	}
+	for mode, want := range wants {
+		got, acc := NewFromBigMode(x, mode)
+		wantAcc := big.Below
+		if want == up {
+			wantAcc = big.Above
+		}
+		if want != got || wantAcc != acc {
+			t.Errorf("%v %v: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, mode, want.Bits(), wantAcc, got.Bits(), acc)
+		}
+	}
+}
+
+// ### [ Benchmarks ] ##########################################################
+
+// benchFloat64s holds float64 numbers of magnitude between 2^(-30) and 2^20,
+// of either sign.
+var benchFloat64s = func() []float64 {
+	// Use deterministic source for pseudo-random numbers.
+	r := rand.New(rand.NewSource(1234))
+	xs := make([]float64, 1024)
+	for i := range xs {
+		xs[i] = math.Ldexp(r.NormFloat64(), r.Intn(50)-30)
+	}
+	return xs
+}()
+
+// sink prevents the results of benchmarked functions from being optimized
+// away.
+var sink Float
+
+func BenchmarkNewFromFloat64(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sink, _ = NewFromFloat64(benchFloat64s[i%len(benchFloat64s)])
+	}
+}
+
+func BenchmarkNewFromFloat32(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sink, _ = NewFromFloat32(float32(benchFloat64s[i%len(benchFloat64s)]))
+	}
+}
+
+func BenchmarkNewFromBig(b *testing.B) {
+	// Conversion through big.Float, for comparison with NewFromFloat64.
+	for i := 0; i < b.N; i++ {
+		sink, _ = NewFromBig(big.NewFloat(benchFloat64s[i%len(benchFloat64s)]))
+	}
+}
+
+func BenchmarkFloat64(b *testing.B) {
+	var x float64
+	for i := 0; i < b.N; i++ {
+		x, _ = NewFromBits(uint16(i)).Float64()
+	}
+	_ = x
+}
+
+func BenchmarkFloat32(b *testing.B) {
+	var x float32
+	for i := 0; i < b.N; i++ {
+		x, _ = NewFromBits(uint16(i)).Float32()
+	}
+	_ = x
+}
+
+func BenchmarkBig(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewFromBits(uint16(i)).Big()
+	}
+}
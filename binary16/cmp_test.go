@@ -0,0 +1,74 @@
+package binary16
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClass(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint16
+		class float.Class
+	}{
+		{bits: 0xFFFF, class: float.QuietNaN},
+		{bits: 0xFE00, class: float.QuietNaN},
+		{bits: 0xFDFF, class: float.SignalingNaN},
+		{bits: 0xFC01, class: float.SignalingNaN},
+		{bits: 0xFC00, class: float.NegativeInfinity},
+		{bits: 0xFBFF, class: float.NegativeNormal},
+		{bits: 0xBC00, class: float.NegativeNormal},
+		{bits: 0x8400, class: float.NegativeNormal},
+		{bits: 0x83FF, class: float.NegativeSubnormal},
+		{bits: 0x8001, class: float.NegativeSubnormal},
+		{bits: 0x8000, class: float.NegativeZero},
+		{bits: 0x0000, class: float.PositiveZero},
+		{bits: 0x0001, class: float.PositiveSubnormal},
+		{bits: 0x03FF, class: float.PositiveSubnormal},
+		{bits: 0x0400, class: float.PositiveNormal},
+		{bits: 0x3C00, class: float.PositiveNormal},
+		{bits: 0x7BFF, class: float.PositiveNormal},
+		{bits: 0x7C00, class: float.PositiveInfinity},
+		{bits: 0x7C01, class: float.SignalingNaN},
+		{bits: 0x7DFF, class: float.SignalingNaN},
+		{bits: 0x7E00, class: float.QuietNaN},
+		{bits: 0x7FFF, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%04X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%04X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding.
+	golden := []struct {
+		x, y  uint16
+		cmp   int
+		total bool
+	}{
+		// Largest subnormal and smallest normal number.
+		{x: 0x03FF, y: 0x0400, cmp: -1, total: true},
+		{x: 0x8400, y: 0x83FF, cmp: -1, total: true},
+		// Positive and negative zero.
+		{x: 0x0000, y: 0x8000, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{x: 0x7DFF, y: 0x7E00, cmp: 0, total: true},
+		{x: 0xFDFF, y: 0xFE00, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.x), NewFromBits(g.y)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%04X.Cmp(0x%04X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%04X.TotalOrder(0x%04X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package binary16
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+func TestText(t *testing.T) {
+	golden := []struct {
+		bits uint16
+		fmt  byte
+		prec int
+		want string
+	}{
+		// Special numbers.
+		{bits: 0x0000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x8000, fmt: 'e', prec: 3, want: "-0.000e+00"},
+		{bits: 0x7C00, fmt: 'g', prec: -1, want: "+Inf"},
+		{bits: 0xFC00, fmt: 'f', prec: 2, want: "-Inf"},
+		{bits: 0x7E00, fmt: 'g', prec: -1, want: "NaN"},
+
+		{bits: 0x3C00, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0x3C00, fmt: 'b', prec: -1, want: "1024p-10"},
+		{bits: 0x2E66, fmt: 'g', prec: -1, want: "0.1"},
+		{bits: 0x2E66, fmt: 'e', prec: 10, want: "9.9975585938e-02"},
+		{bits: 0x2E66, fmt: 'x', prec: -1, want: "0x1.998p-04"},
+		{bits: 0x4248, fmt: 'g', prec: -1, want: "3.14"},
+		{bits: 0x4248, fmt: 'f', prec: 5, want: "3.14062"},
+		// max
+		{bits: 0x7BFF, fmt: 'g', prec: -1, want: "65500"},
+		{bits: 0x7BFF, fmt: 'G', prec: 3, want: "6.55E+04"},
+		// 2^(-13) ~= 0.0001220703125
+		{bits: 0x0800, fmt: 'g', prec: -1, want: "0.0001221"},
+		// min normal
+		{bits: 0x0400, fmt: 'g', prec: -1, want: "6.104e-05"},
+		// min denormal
+		{bits: 0x0001, fmt: 'g', prec: -1, want: "6e-08"},
+		{bits: 0x8001, fmt: 'b', prec: -1, want: "-1p-24"},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.bits)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("0x%04X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextFormatFloat(t *testing.T) {
+	for bits := 0; bits <= 0xFFFF; bits++ {
+		f := NewFromBits(uint16(bits))
+		x, _ := f.Float64()
+		for _, fmt := range []byte{'b', 'e', 'g'} {
+			want := strconv.FormatFloat(x, fmt, -1, 16)
+			got := f.Text(fmt, -1)
+			if want != got {
+				t.Errorf("0x%04X %c: text mismatch; expected %q, got %q", bits, fmt, want, got)
+			}
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		bits   uint16
+		want   string
+	}{
+		{format: "%v", bits: 0x2E66, want: "0.1"},
+		{format: "%g", bits: 0xAE66, want: "-0.1"},
+		{format: "%+v", bits: 0x2E66, want: "+0.1"},
+		{format: "%8v|", bits: 0x2E66, want: "     0.1|"},
+		{format: "%-8v|", bits: 0x2E66, want: "0.1     |"},
+		{format: "%08v", bits: 0xAE66, want: "-00000.1"},
+		{format: "%#g", bits: 0x2E66, want: "0.100000"},
+		{format: "%G", bits: 0x7BFF, want: "65500"},
+		{format: "%b", bits: 0x3C00, want: "1024p-10"},
+		{format: "%v", bits: 0x7C00, want: "+Inf"},
+		{format: "%07v", bits: 0xFC00, want: "   -Inf"},
+		{format: "%v", bits: 0x7E00, want: "NaN"},
+		{format: "%+v", bits: 0x7E00, want: "+NaN"},
+		{format: "%d", bits: 0x2E66, want: "%!d(binary16.Float=0.1)"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, NewFromBits(g.bits))
+		if g.want != got {
+			t.Errorf("%q 0x%04X: output mismatch; expected %q, got %q", g.format, g.bits, g.want, got)
+		}
+	}
+
+	// Formats which do not depend on the precision of the floating-point
+	// format give the same output as for float64 values.
+	formats := []string{"%e", "%E", "%f", "%F", "%.3e", "%10.2f", "%-10.2f|", "%+.1e", "% .2f", "%010.3f", "%+010.3f", "%#.0f", "%#.0e", "%.3g", "%#.3g", "%x", "%X", "%#x", "%.2x", "%12.1X"}
+	for bits := 0; bits <= 0xFFFF; bits += 7 {
+		f := NewFromBits(uint16(bits))
+		x, _ := f.Float64()
+		for _, format := range formats {
+			want := fmt.Sprintf(format, x)
+			got := fmt.Sprintf(format, f)
+			if want != got {
+				t.Errorf("%q 0x%04X: output mismatch; expected %q, got %q", format, bits, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package binary16
+
+import (
+	"errors"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLLVM(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "0xH3C00", want: "1"},
+		{in: "0xHBE00", want: "-1.5"},
+		{in: "0xH4248", want: "3.14"},
+		{in: "0xH7bff", want: "65500"},
+		{in: "0xH0001", want: "6e-08"},
+		{in: "0xH7C00", want: "+Inf"},
+		{in: "0xH7E00", want: "NaN"},
+		{in: "0xH8000", want: "-0"},
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "0xH3C0", err: strconv.ErrSyntax},
+		{in: "0xH3C000", err: strconv.ErrSyntax},
+		{in: "0xR3C00", err: strconv.ErrSyntax},
+		{in: "0xH3G00", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, err := ParseLLVM(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err != nil {
+			continue
+		}
+		if s := got.String(); g.want != s {
+			t.Errorf("%q: value mismatch; expected %v, got %v", g.in, g.want, s)
+		}
+		// Round-trip.
+		if s := got.LLVMString(); strings.ToUpper(g.in[3:]) != s[3:] || g.in[:3] != s[:3] {
+			t.Errorf("%q: round-trip mismatch; got %q", g.in, s)
+		}
+	}
+}
+
+func TestParseLLVMTestdata(t *testing.T) {
+	// Values of the C source, keyed by global variable name.
+	src, err := ioutil.ReadFile("testdata/binary16.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]string)
+	for _, m := range regexp.MustCompile(`_Float16 (\w+) = ([^;]+);`).FindAllStringSubmatch(string(src), -1) {
+		s := strings.NewReplacer("NAN", "nan", "INFINITY", "inf").Replace(m[2])
+		values[m[1]] = s
+	}
+	// Literals of the LLVM IR output of clang.
+	ll, err := ioutil.ReadFile("testdata/binary16.ll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms := regexp.MustCompile(`@(\w+) = global half (\w+)`).FindAllStringSubmatch(string(ll), -1)
+	if len(ms) != len(values) {
+		t.Fatalf("number of globals mismatch; expected %d, got %d", len(values), len(ms))
+	}
+	for _, m := range ms {
+		name, lit := m[1], m[2]
+		got, err := ParseLLVM(lit)
+		if err != nil {
+			t.Errorf("%s: unexpected error; %v", name, err)
+			continue
+		}
+		want, _, err := Parse(values[name])
+		if err != nil {
+			t.Errorf("%s: unexpected error; %v", name, err)
+			continue
+		}
+		if want != got {
+			t.Errorf("%s: bits mismatch; expected 0x%04X, got 0x%04X", name, want.Bits(), got.Bits())
+		}
+		if s := got.LLVMString(); lit != s {
+			t.Errorf("%s: literal mismatch; expected %q, got %q", name, lit, s)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package binary16
+
+import (
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+)
+
+// NewNaN returns a Not-a-Number value with the given payload, which is quiet
+// unless signaling is set. The payload is the fraction without the quiet bit,
+// truncated to 9 bits. A signaling NaN with a zero payload has payload 1, as
+// its encoding would otherwise be infinity.
+func NewNaN(signaling bool, payload uint16) Float {
+	payload &= 0x01FF
+	if !signaling {
+		return Float{bits: 0x7C00 | quiet | payload}
+	}
+	if payload == 0 {
+		payload = 1
+	}
+	return Float{bits: 0x7C00 | payload}
+}
+
+// IsSignaling reports whether f is a signaling Not-a-Number value.
+func (f Float) IsSignaling() bool {
+	return f.isNaN() && f.bits&quiet == 0
+}
+
+// Payload returns the payload of the Not-a-Number value f, i.e. its fraction
+// without the quiet bit, or 0 if f is not NaN.
+func (f Float) Payload() uint16 {
+	if !f.isNaN() {
+		return 0
+	}
+	return f.bits & 0x01FF
+}
+
+// Quiet returns f with the quiet bit set if f is a signaling Not-a-Number value,
+// and f otherwise. The sign and payload are preserved.
+func (f Float) Quiet() Float {
+	if !f.isNaN() {
+		return f
+	}
+	return Float{bits: f.bits | quiet}
+}
+
+// newNaN returns the quiet NaN with the given sign and the fraction frac aligned
+// at the most significant bit, keeping the 10 most significant bits of the
+// fraction. The invalid operation exception is raised if frac is the fraction
+// of a signaling NaN.
+func (ctx *Context) newNaN(sign bool, frac uint64) Float {
+	if frac&payload.Quiet == 0 {
+		ctx.Flags |= fenv.Invalid
+	}
+	f := Float{bits: 0x7C00 | quiet | uint16(frac>>54)}
+	if sign {
+		return f.neg()
+	}
+	return f
+}
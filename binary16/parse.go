@@ -0,0 +1,34 @@
+package binary16
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Parse returns the nearest half precision floating-point number to the value
+// of s (round half to even) and the accuracy of the conversion. s may be a
+// decimal or hexadecimal floating-point literal, or "inf", "infinity" or "nan"
+// (case-insensitive), with an optional sign.
+//
+// If s is syntactically invalid, the error wraps strconv.ErrSyntax. If s is
+// too large in magnitude, the result is +-Inf and the error wraps
+// strconv.ErrRange.
+func Parse(s string) (Float, big.Accuracy, error) {
+	x, nan, err := strconv.ParseBig(s, precision+2)
+	if err != nil {
+		return Float{}, big.Exact, fmt.Errorf("binary16.Parse: parsing %q: %w", s, err)
+	}
+	if nan {
+		if x.Signbit() {
+			return NegNaN, big.Exact, nil
+		}
+		return NaN, big.Exact, nil
+	}
+	f, acc := NewFromBig(x)
+	if f.isInf() && !x.IsInf() {
+		return f, acc, fmt.Errorf("binary16.Parse: parsing %q: %w", s, strconv.ErrRange)
+	}
+	return f, acc, nil
+}
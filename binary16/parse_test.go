@@ -0,0 +1,99 @@
+package binary16
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	golden := []struct {
+		in   string
+		want uint16
+		acc  big.Accuracy
+		err  error
+	}{
+		// Special numbers.
+		{in: "0", want: 0x0000, acc: big.Exact},
+		{in: "-0", want: 0x8000, acc: big.Exact},
+		{in: "+Inf", want: 0x7C00, acc: big.Exact},
+		{in: "-infinity", want: 0xFC00, acc: big.Exact},
+		{in: "NaN", want: 0x7E00, acc: big.Exact},
+		{in: "-NAN", want: 0xFE00, acc: big.Exact},
+
+		// 2^(-14) ~= 6.103515625e-05 (minimum positive normal)
+		{in: "6.1035156e-05", want: 0x0400, acc: big.Above},
+		{in: "6.103515625E-05", want: 0x0400, acc: big.Exact},
+		{in: "0x1.8p-3", want: 0x3200, acc: big.Exact},
+		{in: "-0x.8P1", want: 0xBC00, acc: big.Exact},
+		{in: ".5", want: 0x3800, acc: big.Exact},
+		{in: "5.", want: 0x4500, acc: big.Exact},
+		// 1/3 = 0.333251953125
+		{in: "0.3333333333333333333333333333333", want: 0x3555, acc: big.Below},
+		// 1 + 2^(-11) = 1 (tie, round to even)
+		{in: "1.00048828125", want: 0x3C00, acc: big.Below},
+		{in: "1.000488281250000000000000000001", want: 0x3C01, acc: big.Above},
+		// 65504 (max half precision)
+		{in: "65504", want: 0x7BFF, acc: big.Exact},
+		{in: "65519.99", want: 0x7BFF, acc: big.Below},
+		// 65520 = +Inf (tie, round to even)
+		{in: "65520", want: 0x7C00, acc: big.Above, err: strconv.ErrRange},
+		{in: "-1e100000000", want: 0xFC00, acc: big.Below, err: strconv.ErrRange},
+		// 2^(-25) = +0 (tie, round to even)
+		{in: "2.98023223876953125e-8", want: 0x0000, acc: big.Below},
+		{in: "2.98023223876953125000001e-8", want: 0x0001, acc: big.Above},
+		{in: "-1e-100000000", want: 0x8000, acc: big.Above},
+		{in: "0x1p-100000000", want: 0x0000, acc: big.Below},
+
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "+", err: strconv.ErrSyntax},
+		{in: ".", err: strconv.ErrSyntax},
+		{in: "1e+", err: strconv.ErrSyntax},
+		{in: "0x", err: strconv.ErrSyntax},
+		{in: "0x1", err: strconv.ErrSyntax},
+		{in: "1p-3", err: strconv.ErrSyntax},
+		{in: "1/3", err: strconv.ErrSyntax},
+		{in: "--1", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if g.want != got.Bits() {
+			t.Errorf("%q: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	for bits := 0; bits <= 0xFFFF; bits++ {
+		f := NewFromBits(uint16(bits))
+		x, nan := f.Big()
+		if nan || x.IsInf() {
+			continue
+		}
+		// Five significant decimal digits are enough to identify a half
+		// precision floating-point number; the hexadecimal representation is
+		// exact.
+		for _, s := range []string{x.Text('e', 4), x.Text('p', 0)} {
+			got, _, err := Parse(s)
+			if err != nil {
+				t.Errorf("%q: unexpected error; %v", s, err)
+				continue
+			}
+			if f != got {
+				t.Errorf("%q: bits mismatch; expected 0x%04X, got 0x%04X", s, f.Bits(), got.Bits())
+			}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Package binary256 implements encoding and decoding of IEEE 754 octuple
+// precision floating-point numbers.
+//
+// https://en.wikipedia.org/wiki/Octuple-precision_floating-point_format
+package binary256
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/ieee754"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+const (
+	// precision specifies the number of bits in the mantissa (including the
+	// implicit lead bit).
+	precision = 237
+	// exponent bias.
+	bias = 262143
+	// emin specifies the exponent of the smallest normalized number.
+	emin = 1 - bias
+	// quiet specifies the quiet bit of Not-a-Number values, in the most
+	// significant word.
+	quiet = 0x0000080000000000
+)
+
+// format is the IEEE 754 octuple precision format.
+var format = ieee754.Binary256
+
+// Positive and negative Not-a-Number, infinity and zero.
+var (
+	// +NaN
+	NaN = Float{a: 0x7FFFF80000000000, b: 0, c: 0, d: 0}
+	// -NaN
+	NegNaN = Float{a: 0xFFFFF80000000000, b: 0, c: 0, d: 0}
+	// +Inf
+	Inf = Float{a: 0x7FFFF00000000000, b: 0, c: 0, d: 0}
+	// -Inf
+	NegInf = Float{a: 0xFFFFF00000000000, b: 0, c: 0, d: 0}
+	// +zero
+	Zero = Float{a: 0x0000000000000000, b: 0, c: 0, d: 0}
+	// -zero
+	NegZero = Float{a: 0x8000000000000000, b: 0, c: 0, d: 0}
+)
+
+// Float is a floating-point number in IEEE 754 octuple precision format.
+type Float struct {
+	// Sign, exponent and fraction, from the most significant word.
+	//
+	//    1 bit:    sign
+	//    19 bits:  exponent
+	//    236 bits: fraction
+	a uint64
+	b uint64
+	c uint64
+	d uint64
+}
+
+// NewFromBits returns the floating-point number corresponding to the IEEE 754
+// octuple precision binary representation, given as four 64-bit words from the
+// most significant word.
+func NewFromBits(a, b, c, d uint64) Float {
+	return Float{a: a, b: b, c: c, d: d}
+}
+
+// NewFromFloat32 returns the octuple precision floating-point number for x and
+// the accuracy of the conversion. The conversion is always exact; Not-a-Number
+// values are converted to a quiet NaN with the same sign and payload.
+func NewFromFloat32(x float32) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat32(x)
+}
+
+// NewFromFloat64 returns the octuple precision floating-point number for x and
+// the accuracy of the conversion. The conversion is always exact; Not-a-Number
+// values are converted to a quiet NaN with the same sign and payload.
+func NewFromFloat64(x float64) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat64(x)
+}
+
+// NewFromBig returns the nearest octuple precision floating-point number for x
+// and the accuracy of the conversion, using round half to even.
+func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return new(Context).NewFromBig(x)
+}
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// NewFromFloat32 returns the octuple precision floating-point number for x and
+// the accuracy of the conversion. The conversion is always exact. The exception
+// flags raised are recorded in ctx; a signaling NaN raises the invalid
+// operation exception.
+func (ctx *Context) NewFromFloat32(x float32) (Float, big.Accuracy) {
+	// +-NaN, with the payload of the binary representation of x; see package
+	// payload.
+	if math.IsNaN(float64(x)) {
+		return ctx.newNaN(payload.FromFloat32(x)), big.Exact
+	}
+	return ctx.NewFromFloat64(float64(x))
+}
+
+// NewFromFloat64 returns the octuple precision floating-point number for x and
+// the accuracy of the conversion. The conversion is always exact. The exception
+// flags raised are recorded in ctx; a signaling NaN raises the invalid
+// operation exception.
+func (ctx *Context) NewFromFloat64(x float64) (Float, big.Accuracy) {
+	// +-NaN
+	if math.IsNaN(x) {
+		return ctx.newNaN(payload.FromFloat64(x)), big.Exact
+	}
+	return ctx.NewFromBig(big.NewFloat(x))
+}
+
+// NewFromBig returns the nearest octuple precision floating-point number for x
+// and the accuracy of the conversion, using round half to even. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	bits, acc := format.Encode(x)
+	ctx.Flags |= rounding.Flags(x, acc, precision, emin, bias, big.ToNearestEven, ctx.Tininess)
+	return newFromInt(bits), acc
+}
+
+// Bits returns the IEEE 754 octuple precision binary representation of f, as
+// four 64-bit words from the most significant word.
+func (f Float) Bits() (a, b, c, d uint64) {
+	return f.a, f.b, f.c, f.d
+}
+
+// Float32 returns the float32 value nearest to f. If f is too small to be
+// represented by a float32 (|f| < math.SmallestNonzeroFloat32), the result is
+// (0, Below) or (-0, Above), respectively, depending on the sign of f. If f is
+// too large to be represented by a float32 (|f| > math.MaxFloat32), the result
+// is (+Inf, Above) or (-Inf, Below), depending on the sign of f. Not-a-Number
+// values are converted to a quiet NaN with the same sign, keeping the most
+// significant bits of the payload.
+func (f Float) Float32() (float32, big.Accuracy) {
+	return new(Context).Float32(f)
+}
+
+// Float64 returns the float64 value nearest to f. If f is too small to be
+// represented by a float64 (|f| < math.SmallestNonzeroFloat64), the result is
+// (0, Below) or (-0, Above), respectively, depending on the sign of f. If f is
+// too large to be represented by a float64 (|f| > math.MaxFloat64), the result
+// is (+Inf, Above) or (-Inf, Below), depending on the sign of f. Not-a-Number
+// values are converted to a quiet NaN with the same sign, keeping the most
+// significant bits of the payload.
+func (f Float) Float64() (float64, big.Accuracy) {
+	return new(Context).Float64(f)
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float32(f Float) (float32, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if f.a&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		return payload.Float32(f.Signbit(), f.nanFrac()), big.Exact
+	}
+	y, acc := x.Float32()
+	ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+	return y, acc
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float64(f Float) (float64, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if f.a&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		return payload.Float64(f.Signbit(), f.nanFrac()), big.Exact
+	}
+	y, acc := x.Float64()
+	ctx.Flags |= rounding.Flags(x, acc, 53, -1022, 1023, big.ToNearestEven, ctx.Tininess)
+	return y, acc
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number.
+func (f Float) Big() (x *big.Float, nan bool) {
+	return format.Decode(f.toInt())
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f Float) Signbit() bool {
+	// first bit is sign bit
+	return f.a&0x8000000000000000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f Float) Exp() int {
+	// 19 bit exponent
+	return int(f.a&0x7FFFF00000000000) >> 44
+}
+
+// Frac returns the fraction of f, as four 64-bit words from the most
+// significant word; the first word contains the upper 44 bits.
+func (f Float) Frac() (uint64, uint64, uint64, uint64) {
+	return f.a & 0x00000FFFFFFFFFFF, f.b, f.c, f.d
+}
+
+// isInf reports whether f is +Inf or -Inf.
+func (f Float) isInf() bool {
+	frac1, frac2, frac3, frac4 := f.Frac()
+	return f.Exp() == 0x7FFFF && frac1 == 0 && frac2 == 0 && frac3 == 0 && frac4 == 0
+}
+
+// toInt returns the binary representation of f as an integer.
+func (f Float) toInt() *big.Int {
+	x := new(big.Int)
+	for _, w := range [...]uint64{f.a, f.b, f.c, f.d} {
+		x.Lsh(x, 64)
+		x.Or(x, new(big.Int).SetUint64(w))
+	}
+	return x
+}
+
+// newFromInt returns the floating-point number of the binary representation
+// bits.
+func newFromInt(bits *big.Int) Float {
+	var w [4]uint64
+	x := new(big.Int).Set(bits)
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	word := new(big.Int)
+	for i := len(w) - 1; i >= 0; i-- {
+		w[i] = word.And(x, mask).Uint64()
+		x.Rsh(x, 64)
+	}
+	return Float{a: w[0], b: w[1], c: w[2], d: w[3]}
+}
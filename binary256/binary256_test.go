@@ -0,0 +1,240 @@
+package binary256
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/float/binary128"
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewFromBits(t *testing.T) {
+	const rawpi = "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798"
+	golden := []struct {
+		a, b, c, d uint64
+		want       *big.Float
+		nan        bool
+	}{
+		// Special numbers.
+		// +NaN
+		{a: 0x7FFFF80000000000, d: 0x0000000000000001, want: newFloat("0"), nan: true},
+		// -NaN
+		{a: 0xFFFFF80000000000, want: newFloat("-0"), nan: true},
+		// +Inf
+		{a: 0x7FFFF00000000000, want: newFloat("+Inf")},
+		// -Inf
+		{a: 0xFFFFF00000000000, want: newFloat("-Inf")},
+		// +0
+		{a: 0x0000000000000000, want: newFloat("0")},
+		// -0
+		{a: 0x8000000000000000, want: newFloat("-0")},
+
+		// from: https://en.wikipedia.org/wiki/Octuple-precision_floating-point_format
+
+		// smallest positive subnormal number = 2^{-262378}
+		{d: 0x0000000000000001, want: newFloat("0x1p-262378")},
+		// largest subnormal number = 2^{-262142} * (1 - 2^{-236})
+		{a: 0x00000FFFFFFFFFFF, b: math.MaxUint64, c: math.MaxUint64, d: math.MaxUint64, want: newFloat("0x0.fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffp-262142")},
+		// smallest positive normal number = 2^{-262142}
+		{a: 0x0000100000000000, want: newFloat("0x1p-262142")},
+		// largest normal number = 2^{262143} * (2 - 2^{-236})
+		{a: 0x7FFFEFFFFFFFFFFF, b: math.MaxUint64, c: math.MaxUint64, d: math.MaxUint64, want: newFloat("0x1.fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffp262143")},
+		// largest number less than one = 1 - 2^{-237}
+		{a: 0x3FFFEFFFFFFFFFFF, b: math.MaxUint64, c: math.MaxUint64, d: math.MaxUint64, want: newFloat("0x1.fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffp-1")},
+		// one
+		{a: 0x3FFFF00000000000, want: newFloat("1")},
+		// smallest number larger than one = 1 + 2^{-236}
+		{a: 0x3FFFF00000000000, d: 0x0000000000000001, want: newFloat("0x1.00000000000000000000000000000000000000000000000000000000001p0")},
+		// -2
+		{a: 0xC000000000000000, want: newFloat("-2")},
+		// pi
+		{a: 0x40000921FB54442D, b: 0x18469898CC51701B, c: 0x839A252049C1114C, d: 0xF98E804177D4C762, want: new(big.Float).SetPrec(precision).Set(newFloat(rawpi))},
+		// 1/3
+		{a: 0x3FFFD55555555555, b: 0x5555555555555555, c: 0x5555555555555555, d: 0x5555555555555555, want: new(big.Float).SetPrec(precision).SetRat(big.NewRat(1, 3))},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.a, g.b, g.c, g.d)
+		got, nan := f.Big()
+		if g.want.Cmp(got) != 0 || g.want.Signbit() != got.Signbit() {
+			t.Errorf("0x%016X%016X%016X%016X: floating-point number mismatch; expected %v, got %v", g.a, g.b, g.c, g.d, g.want, got)
+		}
+		if g.nan != nan {
+			t.Errorf("0x%016X%016X%016X%016X: floating-point Not-a-Number indicator mismatch; expected %v, got %v", g.a, g.b, g.c, g.d, g.nan, nan)
+		}
+		if nan {
+			continue
+		}
+		h, acc := NewFromBig(got)
+		if h != f || acc != big.Exact {
+			a, b, c, d := h.Bits()
+			t.Errorf("0x%016X%016X%016X%016X: round-trip mismatch; got 0x%016X%016X%016X%016X (%v)", g.a, g.b, g.c, g.d, a, b, c, d, acc)
+		}
+	}
+}
+
+func TestNewFromFloat64(t *testing.T) {
+	golden := []struct {
+		in         float64
+		a, b, c, d uint64
+	}{
+		// Special numbers.
+		{in: math.NaN(), a: 0x7FFFF80000000000, b: 0x0100000000000000},
+		{in: -math.NaN(), a: 0xFFFFF80000000000, b: 0x0100000000000000},
+		{in: math.Inf(1), a: 0x7FFFF00000000000},
+		{in: math.Inf(-1), a: 0xFFFFF00000000000},
+		{in: 0, a: 0x0000000000000000},
+		{in: math.Copysign(0, -1), a: 0x8000000000000000},
+
+		{in: 1, a: 0x3FFFF00000000000},
+		{in: -2, a: 0xC000000000000000},
+		// pi = 0x400921FB54442D18
+		{in: math.Pi, a: 0x40000921FB54442D, b: 0x1800000000000000},
+		// max float64 = 0x7FEFFFFFFFFFFFFF
+		{in: math.MaxFloat64, a: 0x403FEFFFFFFFFFFF, b: 0xFF00000000000000},
+		// smallest denormalized float64 = 2^{-1074}
+		{in: math.SmallestNonzeroFloat64, a: 0x3FBCD00000000000},
+	}
+	for _, g := range golden {
+		f, acc := NewFromFloat64(g.in)
+		a, b, c, d := f.Bits()
+		if g.a != a || g.b != b || g.c != c || g.d != d {
+			t.Errorf("%v: bits mismatch; expected 0x%016X%016X%016X%016X, got 0x%016X%016X%016X%016X", g.in, g.a, g.b, g.c, g.d, a, b, c, d)
+		}
+		if acc != big.Exact {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, big.Exact, acc)
+		}
+		x, acc := f.Float64()
+		if math.Float64bits(x) != math.Float64bits(g.in) && !math.IsNaN(g.in) || acc != big.Exact {
+			t.Errorf("%v: Float64 mismatch; got %v (%v)", g.in, x, acc)
+		}
+	}
+}
+
+func TestNewFromBig(t *testing.T) {
+	golden := []struct {
+		in         string
+		a, b, c, d uint64
+		acc        big.Accuracy
+	}{
+		// 0.1 rounds up.
+		{in: "0.1", a: 0x3FFFB99999999999, b: 0x9999999999999999, c: 0x9999999999999999, d: 0x999999999999999A, acc: big.Above},
+		// 1 + 2^{-237} = 1 (tie, round to even)
+		{in: "0x1.000000000000000000000000000000000000000000000000000000000008p0", a: 0x3FFFF00000000000, acc: big.Below},
+		// 1 + 3*2^{-237} = 1 + 2^{-235} (tie, round to even)
+		{in: "-0x1.000000000000000000000000000000000000000000000000000000000018p0", a: 0xBFFFF00000000000, d: 0x0000000000000002, acc: big.Below},
+		// 2^{262144} = +Inf
+		{in: "0x1p262144", a: 0x7FFFF00000000000, acc: big.Above},
+		// -2^{-262379} = -0 (tie, round to even)
+		{in: "-0x1p-262379", a: 0x8000000000000000, acc: big.Above},
+		// 2^{-262379} * 3 = 2^{-262377}
+		{in: "0x3p-262379", d: 0x0000000000000002, acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc := NewFromBig(newFloat(g.in))
+		a, b, c, d := f.Bits()
+		if g.a != a || g.b != b || g.c != c || g.d != d {
+			t.Errorf("%v: bits mismatch; expected 0x%016X%016X%016X%016X, got 0x%016X%016X%016X%016X", g.in, g.a, g.b, g.c, g.d, a, b, c, d)
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestConversionFlags(t *testing.T) {
+	golden := []struct {
+		in     string
+		after  fenv.Flags
+		before fenv.Flags
+	}{
+		{in: "0x1p-262142"},
+		{in: "0.1", after: fenv.Inexact, before: fenv.Inexact},
+		// 2^{-262142} * (1 - 2^{-240}), tiny only before rounding.
+		{in: "0x0.ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffp-262142", after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: "0x1p262144", after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{in: "-0x1p-262379", after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			ctx.NewFromBig(newFloat(g.in))
+			if want != ctx.Flags {
+				t.Errorf("%v (%v): flags mismatch; expected %v, got %v", g.in, tininess, want, ctx.Flags)
+			}
+		}
+	}
+
+	float64Golden := []struct {
+		in   string
+		want fenv.Flags
+	}{
+		{in: "1"},
+		{in: "0x1.000000000000000001p0", want: fenv.Inexact},
+		{in: "0x1p1024", want: fenv.Overflow | fenv.Inexact},
+		{in: "0x1p-1075", want: fenv.Underflow | fenv.Inexact},
+	}
+	for _, g := range float64Golden {
+		f, _ := NewFromBig(newFloat(g.in))
+		ctx := new(Context)
+		ctx.Float64(f)
+		if g.want != ctx.Flags {
+			t.Errorf("%v: Float64 flags mismatch; expected %v, got %v", g.in, g.want, ctx.Flags)
+		}
+	}
+
+	// Signaling NaN.
+	ctx := new(Context)
+	if f, _ := ctx.NewFromFloat64(math.Float64frombits(0x7FF0000000000001)); f != NewFromBits(0x7FFFF80000000000, 0x0100000000000000, 0, 0) || ctx.Flags != fenv.Invalid {
+		t.Errorf("signaling NaN: mismatch; got %v (%v)", f, ctx.Flags)
+	}
+	ctx = new(Context)
+	if ctx.Float64(NewFromBits(0x7FFFF00000000000, 0, 0, 1)); ctx.Flags != fenv.Invalid {
+		t.Errorf("signaling NaN: Float64 flags mismatch; expected %v, got %v", fenv.Invalid, ctx.Flags)
+	}
+	ctx = new(Context)
+	if ctx.Float32(NaN); ctx.Flags != 0 {
+		t.Errorf("quiet NaN: Float32 flags mismatch; expected none, got %v", ctx.Flags)
+	}
+}
+
+func TestBinary128(t *testing.T) {
+	// Every binary128 number is exactly representable, and converted back
+	// exactly.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		want := binary128.NewFromBits(r.Uint64(), r.Uint64())
+		x, nan := want.Big()
+		if nan {
+			continue
+		}
+		f, acc := NewFromBig(x)
+		if acc != big.Exact {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", want, big.Exact, acc)
+		}
+		y, nan := f.Big()
+		if nan {
+			t.Errorf("%v: unexpected NaN", want)
+			continue
+		}
+		got, acc := binary128.NewFromBig(y)
+		if got != want || acc != big.Exact {
+			t.Errorf("%v: round-trip mismatch; got %v (%v)", want, got, acc)
+		}
+	}
+}
+
+// newFloat returns the value of s, as parsed by big.Float.Parse with a
+// precision of 1000 bits.
+func newFloat(s string) *big.Float {
+	x, _, err := new(big.Float).SetPrec(1000).Parse(s, 0)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
@@ -0,0 +1,78 @@
+package binary256
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClass(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		a, b, c, d uint64
+		class      float.Class
+	}{
+		{a: 0xFFFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.QuietNaN},
+		{a: 0xFFFFF80000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.QuietNaN},
+		{a: 0xFFFFF7FFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.SignalingNaN},
+		{a: 0xFFFFF00000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000001, class: float.SignalingNaN},
+		{a: 0xFFFFF00000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.NegativeInfinity},
+		{a: 0xFFFFEFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{a: 0xBFFFF00000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x8000100000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x80000FFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.NegativeSubnormal},
+		{a: 0x8000000000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000001, class: float.NegativeSubnormal},
+		{a: 0x8000000000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.NegativeZero},
+		{a: 0x0000000000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.PositiveZero},
+		{a: 0x0000000000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000001, class: float.PositiveSubnormal},
+		{a: 0x0000000000000000, b: 0x0000000000000000, c: 0x0000000000000001, d: 0x0000000000000000, class: float.PositiveSubnormal},
+		{a: 0x0000000000000000, b: 0x0000000000000001, c: 0x0000000000000000, d: 0x0000000000000000, class: float.PositiveSubnormal},
+		{a: 0x00000FFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.PositiveSubnormal},
+		{a: 0x0000100000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x3FFFF00000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x7FFFEFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.PositiveNormal},
+		{a: 0x7FFFF00000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.PositiveInfinity},
+		{a: 0x7FFFF00000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000001, class: float.SignalingNaN},
+		{a: 0x7FFFF7FFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.SignalingNaN},
+		{a: 0x7FFFF80000000000, b: 0x0000000000000000, c: 0x0000000000000000, d: 0x0000000000000000, class: float.QuietNaN},
+		{a: 0x7FFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, c: 0xFFFFFFFFFFFFFFFF, d: 0xFFFFFFFFFFFFFFFF, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewFromBits(a.a, a.b, a.c, a.d)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X%016X%016X%016X: class mismatch; expected %v, got %v", a.a, a.b, a.c, a.d, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X%016X%016X%016X: predicate mismatch for class %v", a.a, a.b, a.c, a.d, a.class)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding.
+	golden := []struct {
+		x, y  [4]uint64
+		cmp   int
+		total bool
+	}{
+		// Subnormal numbers spanning several words.
+		{x: [4]uint64{0x0000000000000000, 0x0000000000000000, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}, y: [4]uint64{0x0000000000000000, 0x0000000000000001, 0x0000000000000000, 0x0000000000000000}, cmp: -1, total: true},
+		{x: [4]uint64{0x8000000000000000, 0x0000000000000001, 0x0000000000000000, 0x0000000000000000}, y: [4]uint64{0x8000000000000000, 0x0000000000000000, 0x0000000000000000, 0xFFFFFFFFFFFFFFFF}, cmp: -1, total: true},
+		// Largest subnormal and smallest normal number.
+		{x: [4]uint64{0x00000FFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}, y: [4]uint64{0x0000100000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000}, cmp: -1, total: true},
+		// Positive and negative zero.
+		{x: [4]uint64{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000}, y: [4]uint64{0x8000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000}, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{x: [4]uint64{0x7FFFF7FFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}, y: [4]uint64{0x7FFFF80000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000}, cmp: 0, total: true},
+		{x: [4]uint64{0xFFFFF7FFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}, y: [4]uint64{0xFFFFF80000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000}, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.x[0], g.x[1], g.x[2], g.x[3]), NewFromBits(g.y[0], g.y[1], g.y[2], g.y[3])
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%016X.Cmp(0x%016X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%016X.TotalOrder(0x%016X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
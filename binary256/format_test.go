@@ -0,0 +1,107 @@
+package binary256
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	golden := []struct {
+		in   string
+		fmt  byte
+		prec int
+		want string
+	}{
+		// Special numbers.
+		{in: "0", fmt: 'g', prec: -1, want: "0"},
+		{in: "8", fmt: 'e', prec: 3, want: "-0.000e+00"},
+		{in: "7FFFF", fmt: 'g', prec: -1, want: "+Inf"},
+		{in: "FFFFF", fmt: 'f', prec: 2, want: "-Inf"},
+		{in: "7FFFF8", fmt: 'g', prec: -1, want: "NaN"},
+
+		{in: "3FFFF", fmt: 'g', prec: -1, want: "1"},
+		{in: "3FFFF", fmt: 'e', prec: 5, want: "1.00000e+00"},
+		{in: "3FFFF", fmt: 'b', prec: -1, want: "110427941548649020598956093796432407239217743554726184882600387580788736p-236"},
+		{in: "3FFFB" + strings.Repeat("9", 58) + "A", fmt: 'g', prec: -1, want: "0.1"},
+		{in: "3FFFB" + strings.Repeat("9", 58) + "A", fmt: 'x', prec: -1, want: "0x1." + strings.Repeat("9", 58) + "ap-04"},
+		{in: "3FFFB" + strings.Repeat("9", 58) + "A", fmt: 'X', prec: 3, want: "0X1.99AP-04"},
+		{in: "40000921FB54442D18469898CC51701B839A252049C1114CF98E804177D4C762", fmt: 'f', prec: 3, want: "3.142"},
+		// max
+		{in: "7FFFE" + strings.Repeat("F", 59), fmt: 'e', prec: 5, want: "1.61133e+78913"},
+		// min normal
+		{in: "00001", fmt: 'e', prec: 5, want: "2.48243e-78913"},
+		// min denormal
+		{in: strings.Repeat("0", 63) + "1", fmt: 'e', prec: 3, want: "2.248e-78984"},
+		{in: "8" + strings.Repeat("0", 62) + "1", fmt: 'b', prec: -1, want: "-1p-262378"},
+	}
+	for _, g := range golden {
+		f := fromHex(g.in)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("%s %c %d: text mismatch; expected %q, got %q", g.in, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		a, b, c, d := r.Uint64(), r.Uint64(), r.Uint64(), r.Uint64()
+		if i%2 == 1 {
+			// Denormalized number.
+			a &= 0x80000FFFFFFFFFFF
+		}
+		f := NewFromBits(a, b, c, d)
+		if _, nan := f.Big(); nan || f.isInf() {
+			continue
+		}
+		s := f.String()
+		got, _, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if f != got {
+			t.Errorf("%q: round-trip mismatch; expected %016X%016X%016X%016X, got %016X%016X%016X%016X", s, f.a, f.b, f.c, f.d, got.a, got.b, got.c, got.d)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		in     string
+		want   string
+	}{
+		{format: "%v", in: "3FFFF", want: "1"},
+		{format: "%+.3e", in: "3FFFF", want: "+1.000e+00"},
+		{format: "%8.2f|", in: "BFFFF", want: "   -1.00|"},
+		{format: "%v", in: "7FFFF", want: "+Inf"},
+		{format: "%6v", in: "7FFFF8", want: "   NaN"},
+		{format: "%s", in: "3FFFF", want: "%!s(binary256.Float=1)"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, fromHex(g.in))
+		if g.want != got {
+			t.Errorf("%q %v: output mismatch; expected %q, got %q", g.format, g.in, g.want, got)
+		}
+	}
+}
+
+// fromHex returns the floating-point number of the hexadecimal binary
+// representation s, padded with trailing zeros to 64 digits.
+func fromHex(s string) Float {
+	s += strings.Repeat("0", 64-len(s))
+	var w [4]uint64
+	for i := range w {
+		x, err := strconv.ParseUint(s[16*i:16*i+16], 16, 64)
+		if err != nil {
+			panic(err)
+		}
+		w[i] = x
+	}
+	return NewFromBits(w[0], w[1], w[2], w[3])
+}
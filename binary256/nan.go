@@ -0,0 +1,74 @@
+package binary256
+
+import (
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+)
+
+// NewNaN returns a Not-a-Number value with the given payload, which is quiet
+// unless signaling is set. The payload is the fraction without the quiet bit,
+// given as four 64-bit words from the most significant word, and truncated to
+// 235 bits; the first word contains the upper 43 bits. A signaling NaN with a
+// zero payload has payload 1, as its encoding would otherwise be infinity.
+func NewNaN(signaling bool, a, b, c, d uint64) Float {
+	a &= 0x000007FFFFFFFFFF
+	if !signaling {
+		return Float{a: 0x7FFFF00000000000 | quiet | a, b: b, c: c, d: d}
+	}
+	if a == 0 && b == 0 && c == 0 && d == 0 {
+		d = 1
+	}
+	return Float{a: 0x7FFFF00000000000 | a, b: b, c: c, d: d}
+}
+
+// IsSignaling reports whether f is a signaling Not-a-Number value.
+func (f Float) IsSignaling() bool {
+	return f.isNaN() && f.a&quiet == 0
+}
+
+// Payload returns the payload of the Not-a-Number value f, i.e. its fraction
+// without the quiet bit, as four 64-bit words from the most significant word;
+// or 0 if f is not NaN. The first word contains the upper 43 bits.
+func (f Float) Payload() (a, b, c, d uint64) {
+	if !f.isNaN() {
+		return 0, 0, 0, 0
+	}
+	return f.a & 0x000007FFFFFFFFFF, f.b, f.c, f.d
+}
+
+// Quiet returns f with the quiet bit set if f is a signaling Not-a-Number value,
+// and f otherwise. The sign and payload are preserved.
+func (f Float) Quiet() Float {
+	if !f.isNaN() {
+		return f
+	}
+	f.a |= quiet
+	return f
+}
+
+// newNaN returns the quiet NaN with the given sign and the fraction frac aligned
+// at the most significant bit, extended with zero bits. The invalid operation
+// exception is raised if frac is the fraction of a signaling NaN.
+func (ctx *Context) newNaN(sign bool, frac uint64) Float {
+	if frac&payload.Quiet == 0 {
+		ctx.Flags |= fenv.Invalid
+	}
+	f := Float{a: 0x7FFFF00000000000 | quiet | frac>>20, b: frac << 44}
+	if sign {
+		f.a |= 0x8000000000000000
+	}
+	return f
+}
+
+// nanFrac returns the fraction of the Not-a-Number value f aligned at the most
+// significant bit, truncated to 64 bits.
+func (f Float) nanFrac() uint64 {
+	a, b, _, _ := f.Frac()
+	return a<<20 | b>>44
+}
+
+// isNaN reports whether f is Not-a-Number.
+func (f Float) isNaN() bool {
+	a, b, c, d := f.Frac()
+	return f.Exp() == 0x7FFFF && (a != 0 || b != 0 || c != 0 || d != 0)
+}
@@ -0,0 +1,77 @@
+package binary256
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewNaN(t *testing.T) {
+	golden := []struct {
+		signaling  bool
+		payload    [4]uint64
+		a, b, c, d uint64
+	}{
+		{signaling: false, a: 0x7FFFF80000000000},
+		{signaling: false, payload: [4]uint64{0x0000055555555555, 1, 2, 3}, a: 0x7FFFFD5555555555, b: 1, c: 2, d: 3},
+		{signaling: true, payload: [4]uint64{0x0000055555555555, 1, 2, 3}, a: 0x7FFFF55555555555, b: 1, c: 2, d: 3},
+		// Zero payload of signaling NaN.
+		{signaling: true, a: 0x7FFFF00000000000, d: 1},
+		// Payload truncated to 235 bits.
+		{signaling: true, payload: [4]uint64{0x0000080000000000, 0, 0, 0}, a: 0x7FFFF00000000000, d: 1},
+	}
+	for _, g := range golden {
+		f := NewNaN(g.signaling, g.payload[0], g.payload[1], g.payload[2], g.payload[3])
+		if a, b, c, d := f.Bits(); g.a != a || g.b != b || g.c != c || g.d != d {
+			t.Errorf("NewNaN(%v, %X): bits mismatch; expected 0x%016X%016X%016X%016X, got 0x%016X%016X%016X%016X", g.signaling, g.payload, g.a, g.b, g.c, g.d, a, b, c, d)
+			continue
+		}
+		if f.IsSignaling() != g.signaling {
+			t.Errorf("%X: signaling mismatch; expected %v, got %v", g.payload, g.signaling, f.IsSignaling())
+		}
+		if a, b, c, d := f.Payload(); a != g.a&0x000007FFFFFFFFFF || b != g.b || c != g.c || d != g.d {
+			t.Errorf("%X: payload mismatch; got 0x%016X%016X%016X%016X", g.payload, a, b, c, d)
+		}
+		if q := f.Quiet(); q != NewFromBits(g.a|0x0000080000000000, g.b, g.c, g.d) || q.IsSignaling() {
+			t.Errorf("%X: quiet mismatch; got %v", g.payload, q)
+		}
+	}
+	// Numbers other than NaN.
+	for _, f := range []Float{Inf, NegInf, Zero, NegZero, NewFromBits(0x3FFFF00000000000, 0, 0, 0)} {
+		if a, b, c, d := f.Payload(); f.IsSignaling() || a != 0 || b != 0 || c != 0 || d != 0 || f.Quiet() != f {
+			t.Errorf("%v: unexpected NaN", f)
+		}
+	}
+}
+
+func TestNaNConversion(t *testing.T) {
+	golden := []struct {
+		in    uint64
+		a, b  uint64
+		flags fenv.Flags
+	}{
+		{in: 0x7FFAAAAAAAAAAAAA, a: 0x7FFFFAAAAAAAAAAA, b: 0xAA00000000000000},
+		// Signaling NaN is quieted.
+		{in: 0xFFF0000000000001, a: 0xFFFFF80000000000, b: 0x0100000000000000, flags: fenv.Invalid},
+	}
+	for _, g := range golden {
+		ctx := new(Context)
+		f, _ := ctx.NewFromFloat64(math.Float64frombits(g.in))
+		if a, b, c, d := f.Bits(); g.a != a || g.b != b || c != 0 || d != 0 || g.flags != ctx.Flags {
+			t.Errorf("0x%016X: float64 conversion mismatch; expected 0x%016X%016X (%v), got 0x%016X%016X%016X%016X (%v)", g.in, g.a, g.b, g.flags, a, b, c, d, ctx.Flags)
+		}
+		// Round-trip.
+		if x, _ := f.Float64(); math.Float64bits(x) != g.in|0x0008000000000000 {
+			t.Errorf("0x%016X: round-trip mismatch; got 0x%016X", g.in, math.Float64bits(x))
+		}
+	}
+	ctx := new(Context)
+	f, _ := ctx.NewFromFloat32(math.Float32frombits(0xFF955555))
+	if a, b, _, _ := f.Bits(); a != 0xFFFFFAAAAAA00000 || b != 0 || ctx.Flags != fenv.Invalid {
+		t.Errorf("0xFF955555: float32 conversion mismatch; got 0x%016X%016X (%v)", a, b, ctx.Flags)
+	}
+	if x, _ := f.Float32(); math.Float32bits(x) != 0xFFD55555 {
+		t.Errorf("0xFF955555: round-trip mismatch; got 0x%08X", math.Float32bits(x))
+	}
+}
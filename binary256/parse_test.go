@@ -0,0 +1,63 @@
+package binary256
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		acc  big.Accuracy
+		err  error
+	}{
+		// Special numbers.
+		{in: "0", want: "0", acc: big.Exact},
+		{in: "-0.0e10", want: "8", acc: big.Exact},
+		{in: "inf", want: "7FFFF", acc: big.Exact},
+		{in: "-Infinity", want: "FFFFF", acc: big.Exact},
+		{in: "+NaN", want: "7FFFF8", acc: big.Exact},
+		{in: "-nan", want: "FFFFF8", acc: big.Exact},
+
+		{in: "1", want: "3FFFF", acc: big.Exact},
+		{in: "0.1", want: "3FFFB" + strings.Repeat("9", 58) + "A", acc: big.Above},
+		{in: "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798", want: "40000921FB54442D18469898CC51701B839A252049C1114CF98E804177D4C762", acc: big.Below},
+		{in: "0x1.8p-3", want: "3FFFC8", acc: big.Exact},
+		// max + half ulp = +Inf (tie, round to even)
+		{in: "0x1." + strings.Repeat("f", 59) + "8p262143", want: "7FFFF", acc: big.Above, err: strconv.ErrRange},
+		{in: "1.7e78913", want: "7FFFF", acc: big.Above, err: strconv.ErrRange},
+		{in: "-1e80000", want: "FFFFF", acc: big.Below, err: strconv.ErrRange},
+		// Denormalized numbers.
+		{in: "-0x1p-262378", want: "8" + strings.Repeat("0", 62) + "1", acc: big.Exact},
+		// 1.1e-78984 is below half of the smallest denormalized number.
+		{in: "1.1e-78984", want: "0", acc: big.Below},
+		{in: "1.2e-78984", want: strings.Repeat("0", 63) + "1", acc: big.Above},
+		{in: "1e-80000", want: "0", acc: big.Below},
+
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "1e", err: strconv.ErrSyntax},
+		{in: "0x1.8", err: strconv.ErrSyntax},
+		{in: "infinit", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if want := fromHex(g.want); want != got {
+			t.Errorf("%q: bits mismatch; expected 0x%s, got 0x%016X%016X%016X%016X", g.in, g.want, got.a, got.b, got.c, got.d)
+		}
+		if g.acc != acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package float
+
+import (
+	"fmt"
+)
+
+// Class specifies the class of a floating-point number, as by the class
+// operation of IEEE 754.
+type Class uint8
+
+// Classes of floating-point numbers, in the order of IEEE 754.
+const (
+	// Signaling Not-a-Number.
+	SignalingNaN Class = iota
+	// Quiet Not-a-Number.
+	QuietNaN
+	// -Inf
+	NegativeInfinity
+	// Negative normal number.
+	NegativeNormal
+	// Negative subnormal number.
+	NegativeSubnormal
+	// -0
+	NegativeZero
+	// +0
+	PositiveZero
+	// Positive subnormal number.
+	PositiveSubnormal
+	// Positive normal number.
+	PositiveNormal
+	// +Inf
+	PositiveInfinity
+)
+
+// classNames maps from class to name, as in IEEE 754.
+var classNames = [...]string{
+	SignalingNaN:      "signalingNaN",
+	QuietNaN:          "quietNaN",
+	NegativeInfinity:  "negativeInfinity",
+	NegativeNormal:    "negativeNormal",
+	NegativeSubnormal: "negativeSubnormal",
+	NegativeZero:      "negativeZero",
+	PositiveZero:      "positiveZero",
+	PositiveSubnormal: "positiveSubnormal",
+	PositiveNormal:    "positiveNormal",
+	PositiveInfinity:  "positiveInfinity",
+}
+
+// String returns the IEEE 754 name of the class c; e.g. "positiveNormal".
+func (c Class) String() string {
+	if int(c) < len(classNames) {
+		return classNames[c]
+	}
+	return fmt.Sprintf("Class(%d)", uint8(c))
+}
+
+// IsNaN reports whether c is the class of Not-a-Number values.
+func (c Class) IsNaN() bool {
+	return c == SignalingNaN || c == QuietNaN
+}
+
+// IsInf reports whether c is the class of +Inf or -Inf.
+func (c Class) IsInf() bool {
+	return c == NegativeInfinity || c == PositiveInfinity
+}
+
+// IsSubnormal reports whether c is the class of subnormal numbers.
+func (c Class) IsSubnormal() bool {
+	return c == NegativeSubnormal || c == PositiveSubnormal
+}
+
+// IsFinite reports whether c is the class of finite numbers; i.e. neither
+// infinite nor Not-a-Number.
+func (c Class) IsFinite() bool {
+	return c >= NegativeNormal && c <= PositiveNormal
+}
+
+// IsZero reports whether c is the class of +0 or -0.
+func (c Class) IsZero() bool {
+	return c == NegativeZero || c == PositiveZero
+}
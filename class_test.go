@@ -0,0 +1,43 @@
+package float
+
+import "testing"
+
+func TestClass(t *testing.T) {
+	golden := []struct {
+		in                                  Class
+		want                                string
+		nan, inf, subnormal, finite, isZero bool
+	}{
+		{in: SignalingNaN, want: "signalingNaN", nan: true},
+		{in: QuietNaN, want: "quietNaN", nan: true},
+		{in: NegativeInfinity, want: "negativeInfinity", inf: true},
+		{in: NegativeNormal, want: "negativeNormal", finite: true},
+		{in: NegativeSubnormal, want: "negativeSubnormal", subnormal: true, finite: true},
+		{in: NegativeZero, want: "negativeZero", finite: true, isZero: true},
+		{in: PositiveZero, want: "positiveZero", finite: true, isZero: true},
+		{in: PositiveSubnormal, want: "positiveSubnormal", subnormal: true, finite: true},
+		{in: PositiveNormal, want: "positiveNormal", finite: true},
+		{in: PositiveInfinity, want: "positiveInfinity", inf: true},
+		{in: 10, want: "Class(10)"},
+	}
+	for _, g := range golden {
+		if got := g.in.String(); g.want != got {
+			t.Errorf("%d: string mismatch; expected %q, got %q", uint8(g.in), g.want, got)
+		}
+		if got := g.in.IsNaN(); g.nan != got {
+			t.Errorf("%v: IsNaN mismatch; expected %v, got %v", g.in, g.nan, got)
+		}
+		if got := g.in.IsInf(); g.inf != got {
+			t.Errorf("%v: IsInf mismatch; expected %v, got %v", g.in, g.inf, got)
+		}
+		if got := g.in.IsSubnormal(); g.subnormal != got {
+			t.Errorf("%v: IsSubnormal mismatch; expected %v, got %v", g.in, g.subnormal, got)
+		}
+		if got := g.in.IsFinite(); g.finite != got {
+			t.Errorf("%v: IsFinite mismatch; expected %v, got %v", g.in, g.finite, got)
+		}
+		if got := g.in.IsZero(); g.isZero != got {
+			t.Errorf("%v: IsZero mismatch; expected %v, got %v", g.in, g.isZero, got)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package decimal128
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestEncoding(t *testing.T) {
+	golden := []struct {
+		neg   bool
+		coeff string
+		exp   int
+		bid   [2]uint64
+		dpd   [2]uint64
+	}{
+		// 1
+		{coeff: "1", exp: 0, bid: [2]uint64{0x3040000000000000, 0x0000000000000001}, dpd: [2]uint64{0x2208000000000000, 0x0000000000000001}},
+		// -7.50
+		{neg: true, coeff: "750", exp: -2, bid: [2]uint64{0xB03C000000000000, 0x00000000000002EE}, dpd: [2]uint64{0xA207800000000000, 0x00000000000003D0}},
+		// 9.999999999999999999999999999999999E+6144 (largest finite number)
+		{coeff: strings.Repeat("9", 34), exp: 6111, bid: [2]uint64{0x5FFFED09BEAD87C0, 0x378D8E63FFFFFFFF}, dpd: [2]uint64{0x77FFCFF3FCFF3FCF, 0xF3FCFF3FCFF3FCFF}},
+		// 1E-6176 (smallest subnormal number)
+		{coeff: "1", exp: -6176, bid: [2]uint64{0, 1}, dpd: [2]uint64{0, 1}},
+		// -0E+0
+		{neg: true, coeff: "0", exp: 0, bid: [2]uint64{0xB040000000000000, 0}, dpd: [2]uint64{0xA208000000000000, 0}},
+	}
+	for _, g := range golden {
+		coeff, _ := new(big.Int).SetString(g.coeff, 10)
+		f, acc := New(g.neg, coeff, g.exp)
+		if acc != big.Exact {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", f, big.Exact, acc)
+		}
+		if a, b := f.BID(); a != g.bid[0] || b != g.bid[1] {
+			t.Errorf("%v: BID mismatch; expected 0x%016X%016X, got 0x%016X%016X", f, g.bid[0], g.bid[1], a, b)
+		}
+		if a, b := f.DPD(); a != g.dpd[0] || b != g.dpd[1] {
+			t.Errorf("%v: DPD mismatch; expected 0x%016X%016X, got 0x%016X%016X", f, g.dpd[0], g.dpd[1], a, b)
+		}
+		if got := NewFromDPD(g.dpd[0], g.dpd[1]); got != f {
+			t.Errorf("0x%016X%016X: DPD decoding mismatch; expected %v, got %v", g.dpd[0], g.dpd[1], f, got)
+		}
+		if f.Signbit() != g.neg || f.Coeff().Cmp(coeff) != 0 || f.Exp() != g.exp {
+			t.Errorf("%v: decoding mismatch; expected (%v, %v, %v), got (%v, %v, %v)", f, g.neg, coeff, g.exp, f.Signbit(), f.Coeff(), f.Exp())
+		}
+	}
+}
+
+func TestSpecial(t *testing.T) {
+	golden := []struct {
+		f   Float
+		dpd [2]uint64
+		s   string
+	}{
+		{f: NaN, dpd: [2]uint64{0x7C00000000000000, 0}, s: "NaN"},
+		{f: NegNaN, dpd: [2]uint64{0xFC00000000000000, 0}, s: "-NaN"},
+		{f: Inf, dpd: [2]uint64{0x7800000000000000, 0}, s: "+Inf"},
+		{f: NegInf, dpd: [2]uint64{0xF800000000000000, 0}, s: "-Inf"},
+		{f: Zero, dpd: [2]uint64{0x2208000000000000, 0}, s: "0"},
+		{f: NegZero, dpd: [2]uint64{0xA208000000000000, 0}, s: "-0"},
+		// sNaN
+		{f: NewFromBID(0x7E00000000000000, 0), dpd: [2]uint64{0x7E00000000000000, 0}, s: "sNaN"},
+		// NaN with payload 123456
+		{f: NewFromBID(0x7C00000000000000, 0x1E240), dpd: [2]uint64{0x7C00000000000000, 0x28E56}, s: "NaN123456"},
+	}
+	for _, g := range golden {
+		if a, b := g.f.DPD(); a != g.dpd[0] || b != g.dpd[1] {
+			t.Errorf("%v: DPD mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.f, g.dpd[0], g.dpd[1], a, b)
+		}
+		if got := NewFromDPD(g.dpd[0], g.dpd[1]); got != g.f {
+			t.Errorf("0x%016X%016X: DPD decoding mismatch; expected %v, got %v", g.dpd[0], g.dpd[1], g.f, got)
+		}
+		if got := g.f.String(); got != g.s {
+			t.Errorf("%v: string mismatch; expected %q, got %q", g.f, g.s, got)
+		}
+	}
+}
+
+func TestNewFromFloat64(t *testing.T) {
+	golden := []struct {
+		in  float64
+		s   string
+		acc big.Accuracy
+	}{
+		{in: math.Copysign(0, -1), s: "-0", acc: big.Exact},
+		{in: 0.5, s: "0.5", acc: big.Exact},
+		// 0.1 = 0.1000000000000000055511151231257827021181583404541015625
+		{in: 0.1, s: "0.1000000000000000055511151231257827", acc: big.Below},
+		{in: 0.125, s: "0.125", acc: big.Exact},
+		{in: 1 << 60, s: "1152921504606846976", acc: big.Exact},
+		{in: math.MaxFloat64, s: "1.797693134862315708145274237317044E+308", acc: big.Above},
+		{in: math.Inf(1), s: "+Inf", acc: big.Exact},
+	}
+	for _, g := range golden {
+		f, acc := NewFromFloat64(g.in)
+		if got := f.String(); got != g.s {
+			t.Errorf("%v: string mismatch; expected %q, got %q", g.in, g.s, got)
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	almost := new(big.Int).Exp(big.NewInt(10), big.NewInt(35), nil)
+	almost.Sub(almost, big.NewInt(5))
+	huge, _ := New(false, big.NewInt(1), 400)
+	golden := []struct {
+		name          string
+		op            func(ctx *Context)
+		after, before fenv.Flags
+	}{
+		{name: "New(1, 6111)", op: func(ctx *Context) { ctx.New(false, big.NewInt(1), 6111) }},
+		{name: "New(1, 6145)", op: func(ctx *Context) { ctx.New(false, big.NewInt(1), 6145) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "New(15, -6177)", op: func(ctx *Context) { ctx.New(false, big.NewInt(15), -6177) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 10^-6143 - 5 * 10^-6178 rounds to 1E-6143, and is tiny only before
+		// rounding.
+		{name: "New(10^35-5, -6178)", op: func(ctx *Context) { ctx.New(false, almost, -6178) }, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "Float64(1E+400)", op: func(ctx *Context) { ctx.Float64(huge) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "Float64(1E-6176)", op: func(ctx *Context) { ctx.Float64(NewFromBID(0, 1)) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: `Parse("1E+6145")`, op: func(ctx *Context) { ctx.Parse("1E+6145") }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			g.op(ctx)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(34), nil)
+	for i := 0; i < 2000; i++ {
+		// Random finite numbers, in canonical BID encoding.
+		f, _ := New(r.Intn(2) == 0, new(big.Int).Rand(r, max), r.Intn(12288)-6176)
+		if got := NewFromDPD(f.DPD()); got != f {
+			t.Errorf("%v: DPD round-trip mismatch; got %v", f, got)
+		}
+		x, nan := f.Big()
+		if nan {
+			t.Errorf("%v: unexpected NaN", f)
+			continue
+		}
+		// Big has sufficient precision to round-trip the value of f.
+		g, _ := NewFromBig(x)
+		if g.decimal().Rat().Cmp(f.decimal().Rat()) != 0 {
+			t.Errorf("%v: Big round-trip mismatch; got %v", f, g)
+		}
+		// String is parsed back to f.
+		h, acc, err := Parse(f.String())
+		if h != f || acc != big.Exact || err != nil {
+			t.Errorf("%v: Parse round-trip mismatch; got %v (%v, %v)", f, h, acc, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package decimal32
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestCmp(t *testing.T) {
+	// Numbers in increasing total order; eq specifies whether the number is
+	// equal in value to the previous number.
+	golden := []struct {
+		in    string
+		class float.Class
+		eq    bool
+	}{
+		{in: "-NaN2", class: float.QuietNaN},
+		{in: "-NaN", class: float.QuietNaN},
+		{in: "-sNaN2", class: float.SignalingNaN},
+		{in: "-sNaN", class: float.SignalingNaN},
+		{in: "-Inf", class: float.NegativeInfinity},
+		{in: "-9.999999E+96", class: float.NegativeNormal},
+		{in: "-1", class: float.NegativeNormal},
+		{in: "-1.0", class: float.NegativeNormal, eq: true},
+		{in: "-1E-95", class: float.NegativeNormal},
+		{in: "-1.000000E-95", class: float.NegativeNormal, eq: true},
+		{in: "-9.99999E-96", class: float.NegativeSubnormal},
+		{in: "-1E-101", class: float.NegativeSubnormal},
+		{in: "-0", class: float.NegativeZero},
+		{in: "-0E-101", class: float.NegativeZero, eq: true},
+		{in: "0E-101", class: float.PositiveZero, eq: true},
+		{in: "0", class: float.PositiveZero, eq: true},
+		{in: "1E-101", class: float.PositiveSubnormal},
+		{in: "9.99999E-96", class: float.PositiveSubnormal},
+		{in: "1.000000E-95", class: float.PositiveNormal},
+		{in: "1E-95", class: float.PositiveNormal, eq: true},
+		{in: "1.0", class: float.PositiveNormal},
+		{in: "1", class: float.PositiveNormal, eq: true},
+		{in: "9.999999E+96", class: float.PositiveNormal},
+		{in: "Inf", class: float.PositiveInfinity},
+		{in: "sNaN", class: float.SignalingNaN},
+		{in: "sNaN2", class: float.SignalingNaN},
+		{in: "NaN", class: float.QuietNaN},
+		{in: "NaN2", class: float.QuietNaN},
+	}
+	// Rank of the values of the numbers.
+	rank := make([]int, len(golden))
+	for i := range golden {
+		if i > 0 {
+			rank[i] = rank[i-1]
+			if !golden[i].eq {
+				rank[i]++
+			}
+		}
+	}
+	for i, a := range golden {
+		f, _, err := Parse(a.in)
+		if err != nil {
+			t.Errorf("%q: unable to parse number; %v", a.in, err)
+			continue
+		}
+		if got := f.Class(); a.class != got {
+			t.Errorf("%q: class mismatch; expected %v, got %v", a.in, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("%q: predicate mismatch for class %v", a.in, a.class)
+		}
+		for j, b := range golden {
+			g, _, _ := Parse(b.in)
+			var want int
+			switch {
+			case a.class.IsNaN() && b.class.IsNaN():
+				want = 0
+			case a.class.IsNaN():
+				want = -1
+			case b.class.IsNaN():
+				want = +1
+			case rank[i] < rank[j]:
+				want = -1
+			case rank[i] > rank[j]:
+				want = +1
+			}
+			if got := f.Cmp(g); want != got {
+				t.Errorf("%q.Cmp(%q): mismatch; expected %d, got %d", a.in, b.in, want, got)
+			}
+			nan := a.class.IsNaN() || b.class.IsNaN()
+			if got := f.Less(g); got != (!nan && want < 0) {
+				t.Errorf("%q.Less(%q): unexpected %v", a.in, b.in, got)
+			}
+			if got := f.Equal(g); got != (!nan && want == 0) {
+				t.Errorf("%q.Equal(%q): unexpected %v", a.in, b.in, got)
+			}
+			if got := f.TotalOrder(g); got != (i <= j) {
+				t.Errorf("%q.TotalOrder(%q): mismatch; expected %v, got %v", a.in, b.in, i <= j, got)
+			}
+		}
+	}
+}
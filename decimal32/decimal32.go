@@ -0,0 +1,255 @@
+// Package decimal32 implements encoding and decoding of IEEE 754 decimal32
+// floating-point numbers, in both the binary integer decimal (BID) and the
+// densely packed decimal (DPD) encodings.
+//
+// A finite decimal32 number is (-1)^sign * coeff * 10^exp, with a coefficient of
+// at most 7 digits and an exponent between -101 and 90. Numbers with the same
+// value but different exponents (e.g. 1.0 and 1.00) are members of the same
+// cohort, and are kept distinct by every conversion which is exact.
+//
+// https://en.wikipedia.org/wiki/Decimal32_floating-point_format
+package decimal32
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/decimal"
+	"github.com/mewmew/float/internal/payload"
+)
+
+const (
+	// precision specifies the number of bits of the binary floating-point
+	// numbers returned by Big; sufficient for NewFromBig to return a number
+	// of the same value.
+	precision = 25
+)
+
+// format is the decimal32 format.
+var format = decimal.Decimal32
+
+// Positive and negative Not-a-Number, infinity and zero, in BID encoding.
+var (
+	// +NaN
+	NaN = Float{bits: 0x7C000000}
+	// -NaN
+	NegNaN = Float{bits: 0xFC000000}
+	// +Inf
+	Inf = Float{bits: 0x78000000}
+	// -Inf
+	NegInf = Float{bits: 0xF8000000}
+	// +zero; 0 * 10^0
+	Zero = Float{bits: 0x32800000}
+	// -zero; -0 * 10^0
+	NegZero = Float{bits: 0xB2800000}
+)
+
+// Float is a floating-point number in IEEE 754 decimal32 format.
+type Float struct {
+	// Binary integer decimal (BID) encoding.
+	//
+	//    1 bit:   sign
+	//    11 bits: combination field
+	//    20 bits: trailing significand field
+	bits uint32
+}
+
+// New returns the decimal32 number nearest to (-1)^neg * coeff * 10^exp (round
+// half to even) and the accuracy of the conversion. The exponent of the result
+// is exp if the value is representable with exponent exp, and otherwise the
+// exponent closest to exp; e.g. New(false, 1, 93) is 1000 * 10^90.
+func New(neg bool, coeff uint32, exp int) (Float, big.Accuracy) {
+	return new(Context).New(neg, coeff, exp)
+}
+
+// NewFromBID returns the floating-point number corresponding to the decimal32
+// binary integer decimal (BID) encoding.
+func NewFromBID(bits uint32) Float {
+	return Float{bits: bits}
+}
+
+// NewFromDPD returns the floating-point number corresponding to the decimal32
+// densely packed decimal (DPD) encoding.
+func NewFromDPD(bits uint32) Float {
+	return newFromDecimal(format.DecodeDPD(new(big.Int).SetUint64(uint64(bits))))
+}
+
+// NewFromFloat32 returns the nearest decimal32 floating-point number for x and
+// the accuracy of the conversion, as for NewFromBig.
+func NewFromFloat32(x float32) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat32(x)
+}
+
+// NewFromFloat64 returns the nearest decimal32 floating-point number for x and
+// the accuracy of the conversion, as for NewFromBig.
+func NewFromFloat64(x float64) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat64(x)
+}
+
+// NewFromBig returns the nearest decimal32 floating-point number for x and the
+// accuracy of the conversion, using round half to even. Exact results use the
+// exponent closest to zero; e.g. 0.5 is 5 * 10^-1 and 100 is 100 * 10^0.
+func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return new(Context).NewFromBig(x)
+}
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// New returns the decimal32 number nearest to (-1)^neg * coeff * 10^exp and
+// the accuracy of the conversion, as for New. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) New(neg bool, coeff uint32, exp int) (Float, big.Accuracy) {
+	c := &decimal.Context{Tininess: ctx.Tininess}
+	d, acc := c.Round(format, neg, new(big.Int).SetUint64(uint64(coeff)), exp)
+	ctx.Flags |= c.Flags
+	return newFromDecimal(d), acc
+}
+
+// NewFromFloat32 returns the nearest decimal32 floating-point number for x and
+// the accuracy of the conversion, as for NewFromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewFromFloat32(x float32) (Float, big.Accuracy) {
+	if payload.Signaling32(x) {
+		ctx.Flags |= fenv.Invalid
+	}
+	return ctx.NewFromFloat64(float64(x))
+}
+
+// NewFromFloat64 returns the nearest decimal32 floating-point number for x and
+// the accuracy of the conversion, as for NewFromFloat64. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewFromFloat64(x float64) (Float, big.Accuracy) {
+	// +-NaN
+	if math.IsNaN(x) {
+		if math.Float64bits(x)&0x0008000000000000 == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		if math.Signbit(x) {
+			// -NaN
+			return NegNaN, big.Exact
+		}
+		// +NaN
+		return NaN, big.Exact
+	}
+	return ctx.NewFromBig(big.NewFloat(x))
+}
+
+// NewFromBig returns the nearest decimal32 floating-point number for x and the
+// accuracy of the conversion, as for NewFromBig. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	c := &decimal.Context{Tininess: ctx.Tininess}
+	d, acc := c.FromBig(format, x)
+	ctx.Flags |= c.Flags
+	return newFromDecimal(d), acc
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion. The exception flags raised are recorded in ctx; a signaling NaN
+// raises the invalid operation exception.
+func (ctx *Context) Float32(f Float) (float32, big.Accuracy) {
+	d := f.decimal()
+	switch d.Kind {
+	case decimal.SNaN:
+		ctx.Flags |= fenv.Invalid
+		fallthrough
+	case decimal.NaN:
+		if d.Neg {
+			return float32(-math.NaN()), big.Exact
+		}
+		return float32(math.NaN()), big.Exact
+	}
+	c := &decimal.Context{Tininess: ctx.Tininess}
+	x, acc := c.Float32(d)
+	ctx.Flags |= c.Flags
+	return x, acc
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion. The exception flags raised are recorded in ctx; a signaling NaN
+// raises the invalid operation exception.
+func (ctx *Context) Float64(f Float) (float64, big.Accuracy) {
+	d := f.decimal()
+	switch d.Kind {
+	case decimal.SNaN:
+		ctx.Flags |= fenv.Invalid
+		fallthrough
+	case decimal.NaN:
+		if d.Neg {
+			return -math.NaN(), big.Exact
+		}
+		return math.NaN(), big.Exact
+	}
+	c := &decimal.Context{Tininess: ctx.Tininess}
+	x, acc := c.Float64(d)
+	ctx.Flags |= c.Flags
+	return x, acc
+}
+
+// BID returns the decimal32 binary integer decimal (BID) encoding of f.
+func (f Float) BID() uint32 {
+	return f.bits
+}
+
+// DPD returns the decimal32 densely packed decimal (DPD) encoding of f.
+func (f Float) DPD() uint32 {
+	return uint32(format.EncodeDPD(f.decimal()).Uint64())
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion.
+func (f Float) Float32() (float32, big.Accuracy) {
+	return new(Context).Float32(f)
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion.
+func (f Float) Float64() (float64, big.Accuracy) {
+	return new(Context).Float64(f)
+}
+
+// Big returns the multi-precision floating-point number representation of f,
+// rounded to 25 bits (round half to even), and a boolean indicating whether f is
+// Not-a-Number. The accuracy of the conversion is given by x.Acc().
+func (f Float) Big() (x *big.Float, nan bool) {
+	return f.decimal().Big(precision)
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f Float) Signbit() bool {
+	return f.bits&0x80000000 != 0
+}
+
+// Exp returns the exponent of the finite number f; i.e. the exponent of its
+// coefficient.
+func (f Float) Exp() int {
+	return f.decimal().Exp
+}
+
+// Coeff returns the coefficient of the finite number f, or the payload of the
+// NaN f.
+func (f Float) Coeff() uint32 {
+	return uint32(f.decimal().Coeff.Uint64())
+}
+
+// decimal returns the decimal floating-point number of f.
+func (f Float) decimal() decimal.Decimal {
+	return format.DecodeBID(new(big.Int).SetUint64(uint64(f.bits)))
+}
+
+// newFromDecimal returns the decimal32 number of the decimal floating-point
+// number d.
+func newFromDecimal(d decimal.Decimal) Float {
+	return Float{bits: uint32(format.EncodeBID(d).Uint64())}
+}
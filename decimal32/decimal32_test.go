@@ -0,0 +1,199 @@
+package decimal32
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestEncoding(t *testing.T) {
+	golden := []struct {
+		neg   bool
+		coeff uint32
+		exp   int
+		bid   uint32
+		dpd   uint32
+	}{
+		// 1
+		{coeff: 1, exp: 0, bid: 0x32800001, dpd: 0x22500001},
+		// -7.50
+		{neg: true, coeff: 750, exp: -2, bid: 0xB18002EE, dpd: 0xA23003D0},
+		// 9.999999E+96 (largest finite number)
+		{coeff: 9999999, exp: 90, bid: 0x77F8967F, dpd: 0x77F3FCFF},
+		// 1E-101 (smallest subnormal number)
+		{coeff: 1, exp: -101, bid: 0x00000001, dpd: 0x00000001},
+		// 0E-101
+		{coeff: 0, exp: -101, bid: 0x00000000, dpd: 0x00000000},
+		// -0E+0
+		{neg: true, coeff: 0, exp: 0, bid: 0xB2800000, dpd: 0xA2500000},
+		// 1000000E+0
+		{coeff: 1000000, exp: 0, bid: 0x328F4240, dpd: 0x26500000},
+	}
+	for _, g := range golden {
+		f, acc := New(g.neg, g.coeff, g.exp)
+		if acc != big.Exact {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", f, big.Exact, acc)
+		}
+		if f.BID() != g.bid {
+			t.Errorf("%v: BID mismatch; expected 0x%08X, got 0x%08X", f, g.bid, f.BID())
+		}
+		if f.DPD() != g.dpd {
+			t.Errorf("%v: DPD mismatch; expected 0x%08X, got 0x%08X", f, g.dpd, f.DPD())
+		}
+		if got := NewFromDPD(g.dpd); got != f {
+			t.Errorf("0x%08X: DPD decoding mismatch; expected %v, got %v", g.dpd, f, got)
+		}
+		if f.Signbit() != g.neg || f.Coeff() != g.coeff || f.Exp() != g.exp {
+			t.Errorf("%v: decoding mismatch; expected (%v, %v, %v), got (%v, %v, %v)", f, g.neg, g.coeff, g.exp, f.Signbit(), f.Coeff(), f.Exp())
+		}
+	}
+}
+
+func TestSpecial(t *testing.T) {
+	golden := []struct {
+		f   Float
+		dpd uint32
+		s   string
+	}{
+		{f: NaN, dpd: 0x7C000000, s: "NaN"},
+		{f: NegNaN, dpd: 0xFC000000, s: "-NaN"},
+		{f: Inf, dpd: 0x78000000, s: "+Inf"},
+		{f: NegInf, dpd: 0xF8000000, s: "-Inf"},
+		{f: Zero, dpd: 0x22500000, s: "0"},
+		{f: NegZero, dpd: 0xA2500000, s: "-0"},
+		// sNaN
+		{f: NewFromBID(0x7E000000), dpd: 0x7E000000, s: "sNaN"},
+		// NaN with payload 123456
+		{f: NewFromBID(0x7C01E240), dpd: 0x7C028E56, s: "NaN123456"},
+	}
+	for _, g := range golden {
+		if got := g.f.DPD(); got != g.dpd {
+			t.Errorf("%v: DPD mismatch; expected 0x%08X, got 0x%08X", g.f, g.dpd, got)
+		}
+		if got := NewFromDPD(g.dpd); got != g.f {
+			t.Errorf("0x%08X: DPD decoding mismatch; expected 0x%08X, got 0x%08X", g.dpd, g.f.BID(), got.BID())
+		}
+		if got := g.f.String(); got != g.s {
+			t.Errorf("0x%08X: string mismatch; expected %q, got %q", g.f.BID(), g.s, got)
+		}
+	}
+}
+
+func TestNewFromFloat64(t *testing.T) {
+	golden := []struct {
+		in  float64
+		s   string
+		acc big.Accuracy
+	}{
+		{in: 0, s: "0", acc: big.Exact},
+		{in: math.Copysign(0, -1), s: "-0", acc: big.Exact},
+		{in: math.Inf(-1), s: "-Inf", acc: big.Exact},
+		{in: 0.5, s: "0.5", acc: big.Exact},
+		{in: -1.25, s: "-1.25", acc: big.Exact},
+		// 0.1 = 0.1000000000000000055511151231257827...
+		{in: 0.1, s: "0.1000000", acc: big.Below},
+		// 2/3 = 0.66666666666666662965923251249478198587894439697265625
+		{in: 2. / 3, s: "0.6666667", acc: big.Above},
+		// 2^30 = 1073741824
+		{in: 1 << 30, s: "1.073742E+9", acc: big.Above},
+		// max float32 = 340282346638528859811704183484516925440
+		{in: math.MaxFloat32, s: "3.402823E+38", acc: big.Below},
+		{in: math.MaxFloat64, s: "+Inf", acc: big.Above},
+		{in: -1e-102, s: "-0E-101", acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc := NewFromFloat64(g.in)
+		if got := f.String(); got != g.s {
+			t.Errorf("%v: string mismatch; expected %q, got %q", g.in, g.s, got)
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	half, _ := New(false, 5, -1)
+	tenth, _ := New(false, 1, -1)
+	largest, _ := New(false, 9999999, 90)
+	golden := []struct {
+		name          string
+		op            func(ctx *Context)
+		after, before fenv.Flags
+	}{
+		{name: "New(1234567, 0)", op: func(ctx *Context) { ctx.New(false, 1234567, 0) }},
+		{name: "New(12345678, 0)", op: func(ctx *Context) { ctx.New(false, 12345678, 0) }, after: fenv.Inexact, before: fenv.Inexact},
+		// Clamped and overflowing exponents.
+		{name: "New(1, 96)", op: func(ctx *Context) { ctx.New(false, 1, 96) }},
+		{name: "New(1, 97)", op: func(ctx *Context) { ctx.New(false, 1, 97) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// Subnormal numbers.
+		{name: "New(1, -101)", op: func(ctx *Context) { ctx.New(false, 1, -101) }},
+		{name: "New(15, -102)", op: func(ctx *Context) { ctx.New(true, 15, -102) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 9.9999995E-96 rounds to 1E-95, and is tiny only before rounding.
+		{name: "New(99999995, -103)", op: func(ctx *Context) { ctx.New(false, 99999995, -103) }, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "New(99999994, -103)", op: func(ctx *Context) { ctx.New(false, 99999994, -103) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// Conversions from float64.
+		{name: "NewFromFloat64(0.5)", op: func(ctx *Context) { ctx.NewFromFloat64(0.5) }},
+		{name: "NewFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewFromFloat64(0.1) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "NewFromFloat64(1e300)", op: func(ctx *Context) { ctx.NewFromFloat64(1e300) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "NewFromFloat64(-1e-300)", op: func(ctx *Context) { ctx.NewFromFloat64(-1e-300) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "NewFromFloat64(sNaN)", op: func(ctx *Context) { ctx.NewFromFloat64(math.Float64frombits(0x7FF0000000000001)) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "NewFromFloat32(sNaN)", op: func(ctx *Context) { ctx.NewFromFloat32(math.Float32frombits(0x7F800001)) }, after: fenv.Invalid, before: fenv.Invalid},
+		// Conversions to binary floating-point.
+		{name: "Float64(0.5)", op: func(ctx *Context) { ctx.Float64(half) }},
+		{name: "Float64(0.1)", op: func(ctx *Context) { ctx.Float64(tenth) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "Float32(9.999999E+96)", op: func(ctx *Context) { ctx.Float32(largest) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "Float32(1E-101)", op: func(ctx *Context) { ctx.Float32(NewFromBID(0x00000001)) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "Float64(sNaN)", op: func(ctx *Context) { ctx.Float64(NewFromBID(0x7E000000)) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "Float32(NaN)", op: func(ctx *Context) { ctx.Float32(NaN) }},
+		// Parsing.
+		{name: `Parse("1.5")`, op: func(ctx *Context) { ctx.Parse("1.5") }},
+		{name: `Parse("1.23456789")`, op: func(ctx *Context) { ctx.Parse("1.23456789") }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: `Parse("1E+97")`, op: func(ctx *Context) { ctx.Parse("1E+97") }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: `Parse("sNaN")`, op: func(ctx *Context) { ctx.Parse("sNaN") }},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			g.op(ctx)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Random finite numbers, in canonical BID encoding.
+		f, _ := New(r.Intn(2) == 0, uint32(r.Intn(1e7)), r.Intn(192)-101)
+		if got := NewFromDPD(f.DPD()); got != f {
+			t.Errorf("%v: DPD round-trip mismatch; got %v", f, got)
+		}
+		x, nan := f.Big()
+		if nan {
+			t.Errorf("%v: unexpected NaN", f)
+			continue
+		}
+		// Big has sufficient precision to round-trip the value of f.
+		g, _ := NewFromBig(x)
+		if g.decimal().Rat().Cmp(f.decimal().Rat()) != 0 {
+			t.Errorf("%v: Big round-trip mismatch; got %v", f, g)
+		}
+		// Float32 rounds the exact value of f.
+		exact, _ := f.decimal().Big(500)
+		want, wantAcc := exact.Float32()
+		y, acc := f.Float32()
+		if y != want || acc != wantAcc {
+			t.Errorf("%v: Float32 mismatch; expected %v (%v), got %v (%v)", f, want, wantAcc, y, acc)
+		}
+	}
+}
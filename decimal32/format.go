@@ -0,0 +1,9 @@
+package decimal32
+
+// String returns the exact decimal representation of f, which Parse converts
+// back to f, preserving its exponent; e.g. "1.50", "1.5E+7" and "1E-101".
+// Infinities are represented as "+Inf" and "-Inf", and NaN as "NaN" or "sNaN"
+// followed by its payload, if non-zero.
+func (f Float) String() string {
+	return f.decimal().String()
+}
@@ -0,0 +1,91 @@
+package decimal32
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+func TestParse(t *testing.T) {
+	golden := []struct {
+		in  string
+		bid uint32
+		acc big.Accuracy
+		err error
+	}{
+		// Members of the cohort of 1.5 are distinct.
+		{in: "1.5", bid: 0x3200000F, acc: big.Exact},
+		{in: "1.50", bid: 0x31800096, acc: big.Exact},
+		{in: "-7.50", bid: 0xB18002EE, acc: big.Exact},
+		{in: "9.999999E+96", bid: 0x77F8967F, acc: big.Exact},
+		{in: "1E-101", bid: 0x00000001, acc: big.Exact},
+		// 8 digits, round half to even.
+		{in: "12345665", bid: 0x3312D686, acc: big.Below},
+		{in: "12345675", bid: 0x3312D688, acc: big.Above},
+		// Clamped exponent.
+		{in: "1E+96", bid: 0x5F8F4240, acc: big.Exact},
+		// Underflow.
+		{in: "5E-102", bid: 0x00000000, acc: big.Below},
+		// Overflow.
+		{in: "1E+97", bid: 0x78000000, acc: big.Above, err: strconv.ErrRange},
+		{in: "-1E+97", bid: 0xF8000000, acc: big.Below, err: strconv.ErrRange},
+		// Special values.
+		{in: "Inf", bid: 0x78000000, acc: big.Exact},
+		{in: "-nan", bid: 0xFC000000, acc: big.Exact},
+		{in: "snan12", bid: 0x7E00000C, acc: big.Exact},
+		// Invalid syntax.
+		{in: "1e+", err: strconv.ErrSyntax},
+		{in: "--1", err: strconv.ErrSyntax},
+		// Payload of more than 6 digits.
+		{in: "nan1000000", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		f, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if f.BID() != g.bid {
+			t.Errorf("%q: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.bid, f.BID())
+		}
+		if acc != g.acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	golden := []string{
+		"0",
+		"-0.00",
+		"0E+90",
+		"1.50",
+		"1.2E+3",
+		"0.000012",
+		"9.999999E+96",
+		"1E-101",
+		"+Inf",
+		"NaN",
+		"sNaN12",
+	}
+	// The string representation of a number is parsed back to the same number,
+	// including its exponent.
+	for _, s := range golden {
+		f, acc, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", s, err)
+			continue
+		}
+		if acc != big.Exact {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", s, big.Exact, acc)
+		}
+		if got := f.String(); got != s {
+			t.Errorf("%q: string mismatch; got %q", s, got)
+		}
+	}
+}
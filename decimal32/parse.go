@@ -0,0 +1,40 @@
+package decimal32
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/decimal"
+)
+
+// Parse returns the nearest decimal32 floating-point number to the value of s
+// (round half to even) and the accuracy of the conversion. s is a decimal
+// literal with an optional sign and exponent, e.g. "1.50" or "-12E-3", or
+// "inf", "infinity", "nan" or "snan" (case-insensitive) with an optional sign,
+// the latter two optionally followed by the decimal payload of NaN.
+//
+// The exponent of the result is the exponent of the literal if possible; e.g.
+// "1.50" is 150 * 10^-2 and "1.5" is 15 * 10^-1.
+//
+// If s is syntactically invalid, the error wraps strconv.ErrSyntax. If s is
+// too large in magnitude, the result is +-Inf and the error wraps
+// strconv.ErrRange.
+func Parse(s string) (Float, big.Accuracy, error) {
+	return new(Context).Parse(s)
+}
+
+// Parse returns the nearest decimal32 floating-point number to the value of s
+// and the accuracy of the conversion, as for Parse. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) Parse(s string) (Float, big.Accuracy, error) {
+	c := &decimal.Context{Tininess: ctx.Tininess}
+	d, acc, err := c.Parse(format, s)
+	ctx.Flags |= c.Flags
+	if err != nil {
+		err = fmt.Errorf("decimal32.Parse: parsing %q: %w", s, err)
+		if d.Coeff == nil {
+			return Float{}, acc, err
+		}
+	}
+	return newFromDecimal(d), acc, err
+}
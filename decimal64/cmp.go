@@ -0,0 +1,73 @@
+package decimal64
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/decimal"
+	"github.com/mewmew/float/internal/order"
+)
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g;
+// members of a cohort are equal, and so are -0 and +0. A NaN is considered less
+// than any non-NaN and equal to a NaN, as by cmp.Compare.
+func (f Float) Cmp(g Float) int {
+	d, e := f.decimal(), g.decimal()
+	if d.IsNaN() || e.IsNaN() {
+		return order.NaN(d.IsNaN(), e.IsNaN())
+	}
+	return d.Cmp(e)
+}
+
+// Less reports whether f < g. It is false if f or g is NaN.
+func (f Float) Less(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is NaN, and true for
+// members of a cohort and for -0 and +0.
+func (f Float) Equal(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder):
+//
+//	-qNaN < -sNaN < -Inf < -x < -0 < +0 < +x < +Inf < +sNaN < +qNaN
+//
+// NaNs of the same kind and sign are ordered by payload, in reverse for
+// negative NaNs. Members of a cohort are ordered by exponent; in increasing
+// order for positive numbers (e.g. 1.00 < 1.0 < 1), and in decreasing order for
+// negative numbers.
+func (f Float) TotalOrder(g Float) bool {
+	return format.TotalOrder(f.decimal(), g.decimal())
+}
+
+// Class returns the class of f.
+func (f Float) Class() float.Class {
+	return format.Class(f.decimal())
+}
+
+// IsNaN reports whether f is Not-a-Number.
+func (f Float) IsNaN() bool {
+	return f.decimal().IsNaN()
+}
+
+// IsInf reports whether f is +Inf or -Inf.
+func (f Float) IsInf() bool {
+	return f.decimal().Kind == decimal.Inf
+}
+
+// IsSubnormal reports whether f is a subnormal number.
+func (f Float) IsSubnormal() bool {
+	return f.Class().IsSubnormal()
+}
+
+// IsFinite reports whether f is neither infinite nor NaN.
+func (f Float) IsFinite() bool {
+	return f.decimal().Kind == decimal.Finite
+}
+
+// IsZero reports whether f is +0 or -0, with any exponent.
+func (f Float) IsZero() bool {
+	d := f.decimal()
+	return d.Kind == decimal.Finite && d.Coeff.Sign() == 0
+}
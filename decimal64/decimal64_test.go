@@ -0,0 +1,213 @@
+package decimal64
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestEncoding(t *testing.T) {
+	golden := []struct {
+		neg   bool
+		coeff uint64
+		exp   int
+		bid   uint64
+		dpd   uint64
+	}{
+		// 1
+		{coeff: 1, exp: 0, bid: 0x31C0000000000001, dpd: 0x2238000000000001},
+		// -7.50
+		{neg: true, coeff: 750, exp: -2, bid: 0xB1800000000002EE, dpd: 0xA2300000000003D0},
+		// 9.999999999999999E+384 (largest finite number)
+		{coeff: 9999999999999999, exp: 369, bid: 0x77FB86F26FC0FFFF, dpd: 0x77FCFF3FCFF3FCFF},
+		// 1E-398 (smallest subnormal number)
+		{coeff: 1, exp: -398, bid: 0x0000000000000001, dpd: 0x0000000000000001},
+		// 0E-398
+		{coeff: 0, exp: -398, bid: 0x0000000000000000, dpd: 0x0000000000000000},
+		// -0E+0
+		{neg: true, coeff: 0, exp: 0, bid: 0xB1C0000000000000, dpd: 0xA238000000000000},
+		// 1000000000000000E+0
+		{coeff: 1000000000000000, exp: 0, bid: 0x31C38D7EA4C68000, dpd: 0x2638000000000000},
+	}
+	for _, g := range golden {
+		f, acc := New(g.neg, g.coeff, g.exp)
+		if acc != big.Exact {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", f, big.Exact, acc)
+		}
+		if f.BID() != g.bid {
+			t.Errorf("%v: BID mismatch; expected 0x%016X, got 0x%016X", f, g.bid, f.BID())
+		}
+		if f.DPD() != g.dpd {
+			t.Errorf("%v: DPD mismatch; expected 0x%016X, got 0x%016X", f, g.dpd, f.DPD())
+		}
+		if got := NewFromDPD(g.dpd); got != f {
+			t.Errorf("0x%016X: DPD decoding mismatch; expected %v, got %v", g.dpd, f, got)
+		}
+		if f.Signbit() != g.neg || f.Coeff() != g.coeff || f.Exp() != g.exp {
+			t.Errorf("%v: decoding mismatch; expected (%v, %v, %v), got (%v, %v, %v)", f, g.neg, g.coeff, g.exp, f.Signbit(), f.Coeff(), f.Exp())
+		}
+	}
+}
+
+func TestSpecial(t *testing.T) {
+	golden := []struct {
+		f   Float
+		dpd uint64
+		s   string
+	}{
+		{f: NaN, dpd: 0x7C00000000000000, s: "NaN"},
+		{f: NegNaN, dpd: 0xFC00000000000000, s: "-NaN"},
+		{f: Inf, dpd: 0x7800000000000000, s: "+Inf"},
+		{f: NegInf, dpd: 0xF800000000000000, s: "-Inf"},
+		{f: Zero, dpd: 0x2238000000000000, s: "0"},
+		{f: NegZero, dpd: 0xA238000000000000, s: "-0"},
+		// sNaN
+		{f: NewFromBID(0x7E00000000000000), dpd: 0x7E00000000000000, s: "sNaN"},
+		// NaN with payload 123456
+		{f: NewFromBID(0x7C0000000001E240), dpd: 0x7C00000000028E56, s: "NaN123456"},
+	}
+	for _, g := range golden {
+		if got := g.f.DPD(); got != g.dpd {
+			t.Errorf("%v: DPD mismatch; expected 0x%016X, got 0x%016X", g.f, g.dpd, got)
+		}
+		if got := NewFromDPD(g.dpd); got != g.f {
+			t.Errorf("0x%016X: DPD decoding mismatch; expected 0x%016X, got 0x%016X", g.dpd, g.f.BID(), got.BID())
+		}
+		if got := g.f.String(); got != g.s {
+			t.Errorf("0x%016X: string mismatch; expected %q, got %q", g.f.BID(), g.s, got)
+		}
+	}
+}
+
+func TestNewFromFloat64(t *testing.T) {
+	golden := []struct {
+		in  float64
+		s   string
+		acc big.Accuracy
+	}{
+		{in: 0, s: "0", acc: big.Exact},
+		{in: math.Copysign(0, -1), s: "-0", acc: big.Exact},
+		{in: math.Inf(1), s: "+Inf", acc: big.Exact},
+		{in: 0.5, s: "0.5", acc: big.Exact},
+		{in: 100, s: "100", acc: big.Exact},
+		{in: -1.25, s: "-1.25", acc: big.Exact},
+		// 0.1 = 0.1000000000000000055511151231257827...
+		{in: 0.1, s: "0.1000000000000000", acc: big.Below},
+		// 2/3 = 0.66666666666666662965923251249478198587894439697265625
+		{in: 2. / 3, s: "0.6666666666666666", acc: big.Below},
+		// -1/3 = -0.333333333333333314829616256247390992939472198486328125
+		{in: -1. / 3, s: "-0.3333333333333333", acc: big.Above},
+		// 2^60 = 1152921504606846976
+		{in: 1 << 60, s: "1.152921504606847E+18", acc: big.Above},
+		{in: math.MaxFloat64, s: "1.797693134862316E+308", acc: big.Above},
+		{in: math.SmallestNonzeroFloat64, s: "4.940656458412465E-324", acc: big.Below},
+	}
+	for _, g := range golden {
+		f, acc := NewFromFloat64(g.in)
+		if got := f.String(); got != g.s {
+			t.Errorf("%v: string mismatch; expected %q, got %q", g.in, g.s, got)
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+	f, _ := NewFromFloat64(math.NaN())
+	if f != NaN {
+		t.Errorf("NaN: mismatch; expected NaN, got %v", f)
+	}
+}
+
+func TestBigRange(t *testing.T) {
+	golden := []struct {
+		in  *big.Float
+		s   string
+		acc big.Accuracy
+	}{
+		// 10^385 overflows.
+		{in: pow10(385), s: "+Inf", acc: big.Above},
+		{in: new(big.Float).Neg(pow10(385)), s: "-Inf", acc: big.Below},
+		// 10^384 = 1000000000000000E+369 (clamped exponent)
+		{in: pow10(384), s: "1.000000000000000E+384", acc: big.Exact},
+		// 10^-399 underflows.
+		{in: new(big.Float).Quo(big.NewFloat(1), pow10(399)), s: "0E-398", acc: big.Below},
+		// 6 * 10^-399 rounds up to the smallest subnormal number.
+		{in: new(big.Float).Quo(big.NewFloat(6), pow10(399)), s: "1E-398", acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc := NewFromBig(g.in)
+		if got := f.String(); got != g.s {
+			t.Errorf("%v: string mismatch; expected %q, got %q", g.in, g.s, got)
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	golden := []struct {
+		name          string
+		op            func(ctx *Context)
+		after, before fenv.Flags
+	}{
+		{name: "New(1, 369)", op: func(ctx *Context) { ctx.New(false, 1, 369) }},
+		{name: "New(1, 385)", op: func(ctx *Context) { ctx.New(false, 1, 385) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "New(15, -399)", op: func(ctx *Context) { ctx.New(false, 15, -399) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 9.9999999999999995E-384 rounds to 1E-383, and is tiny only before
+		// rounding.
+		{name: "New(99999999999999995, -400)", op: func(ctx *Context) { ctx.New(false, 99999999999999995, -400) }, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "NewFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewFromFloat64(0.1) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "Float64(1E-398)", op: func(ctx *Context) { ctx.Float64(NewFromBID(0x0000000000000001)) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: `Parse("1E+385")`, op: func(ctx *Context) { ctx.Parse("1E+385") }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			g.op(ctx)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Random finite numbers, in canonical BID encoding.
+		f, _ := New(r.Intn(2) == 0, uint64(r.Int63n(1e16)), r.Intn(768)-398)
+		if got := NewFromDPD(f.DPD()); got != f {
+			t.Errorf("%v: DPD round-trip mismatch; got %v", f, got)
+		}
+		x, nan := f.Big()
+		if nan {
+			t.Errorf("%v: unexpected NaN", f)
+			continue
+		}
+		// Big has sufficient precision to round-trip the value of f.
+		g, _ := NewFromBig(x)
+		if g.decimal().Rat().Cmp(f.decimal().Rat()) != 0 {
+			t.Errorf("%v: Big round-trip mismatch; got %v", f, g)
+		}
+		// Float64 rounds the exact value of f.
+		exact, _ := f.decimal().Big(1500)
+		want, wantAcc := exact.Float64()
+		y, acc := f.Float64()
+		if y != want || acc != wantAcc {
+			t.Errorf("%v: Float64 mismatch; expected %v (%v), got %v (%v)", f, want, wantAcc, y, acc)
+		}
+	}
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Float {
+	x := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	return new(big.Float).SetInt(x)
+}
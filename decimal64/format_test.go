@@ -0,0 +1,110 @@
+package decimal64
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+func TestParse(t *testing.T) {
+	golden := []struct {
+		in  string
+		bid uint64
+		acc big.Accuracy
+		err error
+	}{
+		// Members of the cohort of 1.5 are distinct.
+		{in: "1.5", bid: 0x31A000000000000F, acc: big.Exact},
+		{in: "1.50", bid: 0x3180000000000096, acc: big.Exact},
+		{in: "15E-1", bid: 0x31A000000000000F, acc: big.Exact},
+		{in: "-7.50", bid: 0xB1800000000002EE, acc: big.Exact},
+		{in: "+0.00", bid: 0x3180000000000000, acc: big.Exact},
+		{in: "-0", bid: 0xB1C0000000000000, acc: big.Exact},
+		{in: "9.999999999999999E+384", bid: 0x77FB86F26FC0FFFF, acc: big.Exact},
+		{in: "1E-398", bid: 0x0000000000000001, acc: big.Exact},
+		// 17 digits, round half to even.
+		{in: "12345678901234565", bid: 0x31E462D53C8ABAC0, acc: big.Below},
+		{in: "12345678901234575", bid: 0x31E462D53C8ABAC2, acc: big.Above},
+		{in: "-12345678901234576", bid: 0xB1E462D53C8ABAC2, acc: big.Below},
+		// Clamped exponent.
+		{in: "1E+384", bid: 0x5FE38D7EA4C68000, acc: big.Exact},
+		// Underflow.
+		{in: "5E-399", bid: 0x0000000000000000, acc: big.Below},
+		{in: "-1E-100000000", bid: 0x8000000000000000, acc: big.Above},
+		// Overflow.
+		{in: "1E+385", bid: 0x7800000000000000, acc: big.Above, err: strconv.ErrRange},
+		{in: "-1E+100000000", bid: 0xF800000000000000, acc: big.Below, err: strconv.ErrRange},
+		// Special values.
+		{in: "inf", bid: 0x7800000000000000, acc: big.Exact},
+		{in: "-Infinity", bid: 0xF800000000000000, acc: big.Exact},
+		{in: "NaN", bid: 0x7C00000000000000, acc: big.Exact},
+		{in: "-sNaN", bid: 0xFE00000000000000, acc: big.Exact},
+		{in: "nan123456", bid: 0x7C0000000001E240, acc: big.Exact},
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "1.2.3", err: strconv.ErrSyntax},
+		{in: "1E", err: strconv.ErrSyntax},
+		{in: ".", err: strconv.ErrSyntax},
+		{in: "0x10", err: strconv.ErrSyntax},
+		// Payload of more than 15 digits.
+		{in: "nan1000000000000000", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		f, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if f.BID() != g.bid {
+			t.Errorf("%q: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.bid, f.BID())
+		}
+		if acc != g.acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	golden := []string{
+		"0",
+		"-0",
+		"0.00",
+		"0E+3",
+		"0E-398",
+		"1",
+		"1.50",
+		"-7.50",
+		"123",
+		"1.23E+3",
+		"0.000012",
+		"1.2E-7",
+		"9.999999999999999E+384",
+		"1.000000000000000E+384",
+		"1E-398",
+		"+Inf",
+		"-Inf",
+		"NaN",
+		"-sNaN",
+		"NaN123456",
+	}
+	// The string representation of a number is parsed back to the same number,
+	// including its exponent.
+	for _, s := range golden {
+		f, acc, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", s, err)
+			continue
+		}
+		if acc != big.Exact {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", s, big.Exact, acc)
+		}
+		if got := f.String(); got != s {
+			t.Errorf("%q: string mismatch; got %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package fenv defines the floating-point exception flags of IEEE 754, and the
+// detection of tininess for the underflow exception, as raised by the
+// arithmetic and conversions of the floating-point formats of this module.
+//
+// Exceptions are handled as specified by IEEE 754 for the default
+// (non-trapping) exception handling; the operation delivers its default result
+// and raises the corresponding status flag.
+//
+// https://en.wikipedia.org/wiki/IEEE_754#Exception_handling
+package fenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Flags is a set of floating-point exception flags.
+type Flags uint8
+
+// Exception flags, with the bit assignment of Berkeley SoftFloat.
+const (
+	// The result was rounded; i.e. differs from the exact result.
+	Inexact Flags = 1 << iota
+	// The result is tiny (see Tininess) and inexact.
+	Underflow
+	// The result rounded with an unbounded exponent range is too large in
+	// magnitude for the largest finite number.
+	Overflow
+	// An infinite result was produced exactly from finite operands; e.g. 1/0.
+	DivByZero
+	// The operation has no usefully definable result; e.g. 0/0, Inf-Inf, or an
+	// operand is a signaling NaN.
+	Invalid
+)
+
+// flagNames maps from exception flag to name, in the order of the bits.
+var flagNames = [...]string{
+	"inexact",
+	"underflow",
+	"overflow",
+	"divbyzero",
+	"invalid",
+}
+
+// String returns the names of the exception flags of f separated by '|'; e.g.
+// "underflow|inexact", or "none" if no flag is raised.
+func (f Flags) String() string {
+	if f == 0 {
+		return "none"
+	}
+	var names []string
+	for i := len(flagNames) - 1; i >= 0; i-- {
+		if f&(1<<uint(i)) != 0 {
+			names = append(names, flagNames[i])
+		}
+	}
+	if rest := f &^ (1<<uint(len(flagNames)) - 1); rest != 0 {
+		names = append(names, fmt.Sprintf("%#02x", uint8(rest)))
+	}
+	return strings.Join(names, "|")
+}
+
+// Tininess specifies when a non-zero result is detected to be tiny, i.e. below
+// the smallest normalized number in magnitude, for the underflow exception.
+// IEEE 754 leaves the choice to the implementation.
+type Tininess uint8
+
+// Detection of tininess.
+const (
+	// Tininess is detected after rounding; the result is tiny if the exact
+	// result rounded to the precision of the format with an unbounded exponent
+	// range is below the smallest normalized number in magnitude (e.g. x86).
+	AfterRounding Tininess = iota
+	// Tininess is detected before rounding; the result is tiny if the exact
+	// result is below the smallest normalized number in magnitude (e.g. ARM).
+	BeforeRounding
+)
+
+// String returns a description of the detection of tininess t.
+func (t Tininess) String() string {
+	switch t {
+	case AfterRounding:
+		return "after rounding"
+	case BeforeRounding:
+		return "before rounding"
+	}
+	return fmt.Sprintf("Tininess(%d)", uint8(t))
+}
@@ -0,0 +1,37 @@
+package fenv
+
+import "testing"
+
+func TestFlagsString(t *testing.T) {
+	golden := []struct {
+		in   Flags
+		want string
+	}{
+		{in: 0, want: "none"},
+		{in: Inexact, want: "inexact"},
+		{in: Underflow | Inexact, want: "underflow|inexact"},
+		{in: Invalid | DivByZero | Overflow, want: "invalid|divbyzero|overflow"},
+		{in: 0x80 | Invalid, want: "invalid|0x80"},
+	}
+	for _, g := range golden {
+		if got := g.in.String(); g.want != got {
+			t.Errorf("%#02x: string mismatch; expected %q, got %q", uint8(g.in), g.want, got)
+		}
+	}
+}
+
+func TestTininessString(t *testing.T) {
+	golden := []struct {
+		in   Tininess
+		want string
+	}{
+		{in: AfterRounding, want: "after rounding"},
+		{in: BeforeRounding, want: "before rounding"},
+		{in: 2, want: "Tininess(2)"},
+	}
+	for _, g := range golden {
+		if got := g.in.String(); g.want != got {
+			t.Errorf("%d: string mismatch; expected %q, got %q", uint8(g.in), g.want, got)
+		}
+	}
+}
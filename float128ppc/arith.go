@@ -0,0 +1,348 @@
+package float128ppc
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+const (
+	// emin specifies the exponent of the smallest normalized number; of the
+	// high part.
+	emin = -1022
+	// sumPrec specifies a precision sufficient for the exact sum of two
+	// double-double numbers, with bits from 2^1024 to 2^(-1074).
+	sumPrec = 2100
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the arithmetic operations and conversions performed
+// in the context. The zero value detects tininess after rounding and has no
+// flags raised. Conversions from float32 and float64 are exact, and are
+// performed without a context.
+//
+// Double-double arithmetic is not correctly rounded; the inexact exception is
+// raised if the result differs from the exact result, and the result is tiny
+// if the exact result is below 2^(-1022) in magnitude, before or after
+// rounding to 106 bits.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// Add returns the sum f+g, computed using double-double arithmetic as for the
+// IBM long double format on PowerPC (__gcc_qadd).
+func (f Float) Add(g Float) Float {
+	return addFloat(f.high, f.low, g.high, g.low)
+}
+
+// Sub returns the difference f-g, computed using double-double arithmetic as
+// for the IBM long double format on PowerPC (__gcc_qsub).
+func (f Float) Sub(g Float) Float {
+	return addFloat(f.high, f.low, -g.high, -g.low)
+}
+
+// Mul returns the product f*g, computed using double-double arithmetic as for
+// the IBM long double format on PowerPC (__gcc_qmul).
+func (f Float) Mul(g Float) Float {
+	a, b, c, d := f.high, f.low, g.high, g.low
+	// Highest order term.
+	t := float64(a * c)
+	if t == 0 || !isFinite(t) {
+		// Preserve -0.
+		return Float{high: t}
+	}
+	// Sum terms of the two highest orders, using the rounding error of a*c
+	// computed by a fused multiply-subtract.
+	tau := math.FMA(a, c, -t)
+	v := float64(a * d)
+	w := float64(b * c)
+	tau += v + w
+	u := t + tau
+	if !isFinite(u) {
+		return Float{high: u}
+	}
+	return Float{high: u, low: (t - u) + tau}
+}
+
+// Div returns the quotient f/g, computed using double-double arithmetic as for
+// the IBM long double format on PowerPC (__gcc_qdiv).
+func (f Float) Div(g Float) Float {
+	a, b, c, d := f.high, f.low, g.high, g.low
+	// Highest order term.
+	t := a / c
+	if t == 0 || !isFinite(t) {
+		// Preserve -0.
+		return Float{high: t}
+	}
+	// The corrections to the highest order term require the rounding error of
+	// c*t to be exactly representable.
+	if math.Abs(a) <= 0x1p-969 {
+		a *= 0x1p106
+		b *= 0x1p106
+		c *= 0x1p106
+		d *= 0x1p106
+	}
+	// (s, sigma) = c*t exactly.
+	s, sigma := twoProd(c, t)
+	// b - d*t, computed by a fused negative multiply-subtract.
+	w := math.FMA(-d, t, b)
+	v := a - s
+	// Correction to t.
+	tau := ((v - sigma) + w) / c
+	u := t + tau
+	if !isFinite(u) {
+		return Float{high: u}
+	}
+	return Float{high: u, low: (t - u) + tau}
+}
+
+// Sqrt returns the square root of f, computed using double-double arithmetic
+// with one correction step of Dekker's algorithm.
+func (f Float) Sqrt() Float {
+	a, aa := f.high, f.low
+	switch {
+	case math.IsNaN(a) || math.IsNaN(aa):
+		return Float{high: a + aa}
+	case a == 0:
+		// sqrt(+-0) = +-0
+		return Float{high: a}
+	case a < 0:
+		return NaN
+	case math.IsInf(a, 1):
+		return Inf
+	}
+	// Scale f by an even power of two, to prevent underflow of the residual
+	// and overflow of c*c, and scale the square root back by half the power.
+	scale := 1.0
+	switch {
+	case a < 0x1p-900:
+		a *= 0x1p600
+		aa *= 0x1p600
+		scale = 0x1p-300
+	case a > 0x1p900:
+		a *= 0x1p-600
+		aa *= 0x1p-600
+		scale = 0x1p300
+	}
+	c := math.Sqrt(a)
+	// Correct c using the exact residual f - c*c.
+	u, uu := twoProd(c, c)
+	cc := ((a - u) - uu + aa) * 0.5 / c
+	y := c + cc
+	return Float{high: y * scale, low: ((c - y) + cc) * scale}
+}
+
+// FMA returns the fused multiply-add f*g+h. As for the IBM long double format
+// on PowerPC (fmal), the product is rounded before the addition.
+func (f Float) FMA(g, h Float) Float {
+	return f.Mul(g).Add(h)
+}
+
+// Add returns the sum x+y, as for x.Add. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) Add(x, y Float) Float {
+	z := x.Add(y)
+	ctx.addFlags(x, y, z)
+	return z
+}
+
+// Sub returns the difference x-y, as for x.Sub. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) Sub(x, y Float) Float {
+	z := x.Sub(y)
+	ctx.addFlags(x, Float{high: -y.high, low: -y.low}, z)
+	return z
+}
+
+// Mul returns the product x*y, as for x.Mul. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) Mul(x, y Float) Float {
+	z := x.Mul(y)
+	if ctx.invalid(x, y) {
+		return z
+	}
+	xb, _ := x.Big()
+	yb, _ := y.Big()
+	switch {
+	case xb.IsInf() || yb.IsInf():
+		if z.IsNaN() {
+			// 0 * Inf
+			ctx.Flags |= fenv.Invalid
+		}
+		return z
+	}
+	exact := new(big.Float).SetPrec(xb.Prec()+yb.Prec()).Mul(xb, yb)
+	ctx.roundFlags(z, exact, z.equals(exact))
+	return z
+}
+
+// Div returns the quotient x/y, as for x.Div. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) Div(x, y Float) Float {
+	z := x.Div(y)
+	if ctx.invalid(x, y) {
+		return z
+	}
+	xb, _ := x.Big()
+	yb, _ := y.Big()
+	switch {
+	case xb.IsInf() && yb.IsInf(), xb.Sign() == 0 && yb.Sign() == 0:
+		// Inf / Inf or 0 / 0
+		ctx.Flags |= fenv.Invalid
+		return z
+	case xb.IsInf() || yb.IsInf():
+		return z
+	case yb.Sign() == 0:
+		ctx.Flags |= fenv.DivByZero
+		return z
+	}
+	// z is exact if z*y = x. The quotient is rounded to a precision sufficient
+	// to detect tininess and its rounding to 106 bits.
+	exact := false
+	if zb, nan := z.Big(); !nan && !zb.IsInf() {
+		zy := new(big.Float).SetPrec(zb.Prec()+yb.Prec()).Mul(zb, yb)
+		exact = zy.Cmp(xb) == 0
+	}
+	q := new(big.Float).SetPrec(xb.Prec()+yb.Prec()+precision).Quo(xb, yb)
+	ctx.roundFlags(z, q, exact)
+	return z
+}
+
+// Sqrt returns the square root of x, as for x.Sqrt. The exception flags raised
+// are recorded in ctx; the square root of a number less than zero raises the
+// invalid operation exception.
+func (ctx *Context) Sqrt(x Float) Float {
+	z := x.Sqrt()
+	if ctx.invalid(x) {
+		return z
+	}
+	xb, _ := x.Big()
+	switch {
+	case xb.Sign() < 0:
+		ctx.Flags |= fenv.Invalid
+		return z
+	case xb.Sign() == 0 || xb.IsInf():
+		return z
+	}
+	// The square root of a double-double number is neither tiny nor too large;
+	// z is exact if z*z = x.
+	zb, _ := z.Big()
+	if new(big.Float).SetPrec(2*zb.Prec()).Mul(zb, zb).Cmp(xb) != 0 {
+		ctx.Flags |= fenv.Inexact
+	}
+	return z
+}
+
+// FMA returns the fused multiply-add x*y+z, as for x.FMA. The exception flags
+// raised by the product and the addition are recorded in ctx.
+func (ctx *Context) FMA(x, y, z Float) Float {
+	return ctx.Add(ctx.Mul(x, y), z)
+}
+
+// addFlags records in ctx the exception flags raised by the sum z of x and y.
+func (ctx *Context) addFlags(x, y, z Float) {
+	if ctx.invalid(x, y) {
+		return
+	}
+	xb, _ := x.Big()
+	yb, _ := y.Big()
+	if xb.IsInf() || yb.IsInf() {
+		if z.IsNaN() {
+			// Inf - Inf
+			ctx.Flags |= fenv.Invalid
+		}
+		return
+	}
+	exact := new(big.Float).SetPrec(sumPrec).Add(xb, yb)
+	ctx.roundFlags(z, exact, z.equals(exact))
+}
+
+// roundFlags records in ctx the exception flags raised by the result z of an
+// operation on finite operands, with the exact result x or an approximation of
+// x sufficient to round it to 106 bits; exact reports whether z is exact.
+func (ctx *Context) roundFlags(z Float, x *big.Float, exact bool) {
+	switch {
+	case math.IsInf(z.high, 0):
+		ctx.Flags |= fenv.Overflow | fenv.Inexact
+	case !exact:
+		// Overflow is reported by the infinite result, so the exponent range
+		// is unbounded above; the direction of rounding is irrelevant.
+		ctx.Flags |= rounding.Flags(x, big.Below, precision, emin, math.MaxInt32, big.ToNearestEven, ctx.Tininess)
+	}
+}
+
+// invalid records in ctx the invalid operation exception if any of fs is a
+// signaling NaN, and reports whether any of fs is NaN.
+func (ctx *Context) invalid(fs ...Float) bool {
+	nan := false
+	for _, f := range fs {
+		if f.IsSignaling() {
+			ctx.Flags |= fenv.Invalid
+		}
+		nan = nan || f.IsNaN()
+	}
+	return nan
+}
+
+// equals reports whether f is the finite value x.
+func (f Float) equals(x *big.Float) bool {
+	y, nan := f.Big()
+	return !nan && !y.IsInf() && y.Cmp(x) == 0
+}
+
+// addFloat returns the sum (a+aa) + (c+cc) of the double-double pairs, which
+// need not be canonical.
+func addFloat(a, aa, c, cc float64) Float {
+	z := a + c
+	if !isFinite(z) {
+		if !math.IsInf(z, 0) {
+			return Float{high: z}
+		}
+		// The sum of the high parts overflows, but the sum of all parts may
+		// still be finite.
+		z = cc + aa + c + a
+		if !isFinite(z) {
+			return Float{high: z}
+		}
+		// z is always the largest finite float64 here.
+		zz := aa + cc
+		var xl float64
+		if math.Abs(a) > math.Abs(c) {
+			xl = a - z + c + zz
+		} else {
+			xl = c - z + a + zz
+		}
+		return Float{high: z, low: xl}
+	}
+	q := a - z
+	zz := q + c + (a - (q + z)) + aa + cc
+	if zz == 0 {
+		// Preserve -0.
+		return Float{high: z}
+	}
+	xh := z + zz
+	if !isFinite(xh) {
+		return Float{high: xh}
+	}
+	return Float{high: xh, low: z - xh + zz}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// twoProd returns the product a*b rounded to float64 and the rounding error of
+// the product, computed by a fused multiply-subtract, such that p+e = a*b
+// exactly unless the error underflows.
+func twoProd(a, b float64) (p, e float64) {
+	p = float64(a * b)
+	return p, math.FMA(a, b, -p)
+}
+
+// isFinite reports whether x is neither infinite nor NaN.
+func isFinite(x float64) bool {
+	return !math.IsInf(x, 0) && !math.IsNaN(x)
+}
@@ -0,0 +1,204 @@
+package float128ppc
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+// Test cases computed using the libgcc implementation of IBM long double
+// arithmetic (__gcc_qadd, __gcc_qsub, __gcc_qmul and __gcc_qdiv).
+func TestArith(t *testing.T) {
+	golden := []struct {
+		x, y string
+		// Results of x+y, x-y, x*y and x/y.
+		want [4]string
+	}{
+		// non-canonical x
+		{x: "4527A586E2899496C45C6E87CCD7A36B", y: "42C20DB8F7DF9E2AC2C0910042A4DB12", want: [4]string{"4527A4A36E4B33CCC1BC400000000000", "4527A4A36E4B27E6416D000000000000", "47C18D5CC88561200000000000000000", "42641791E97978580000000000000000"}},
+		{x: "3BD78635294D20EEB83649BB77C4EB58", y: "C4C180115BE46B75C0E741226BB1A696", want: [4]string{"C4C180115BE46B75C0E741226BB1A696", "44C180115BE46B7540E741226BB1A696", "C0A9BAE3AADFFCB4BD3CC662112EEF73", "B70581EF627D02873387FC145092DFAD"}},
+		// non-canonical y
+		{x: "3B8730F1F8F5DC7AB73613EFDC517EE5", y: "C020501C16288F2B40164B1409DE0B6E", want: [4]string{"C004AA4844E625D00000000000000000", "4004AA4844E625D00000000000000000", "BB9DF3FF424702F80000000000000000", "BB6324FA935689923810000000000000"}},
+		{x: "4485221D42C2D1D640F7CC1C6594353D", y: "BB06A724F5A71F32B791D4AFC3A08138", want: [4]string{"4485221D42C2D1D640F7CC1C6594353D", "4485221D42C2D1D640F7CC1C6594353D", "BF9DEBAD1E6DA57F3C25801A122DF39A", "C96DDA728E884BA145FDE52AE6722AA7"}},
+		{x: "C174516DDE9600D93D5C40BADFC0A0E4", y: "40FF724C67EB89053D756A8BBCB580BD", want: [4]string{"C17431FB922E15503DD471EAE9D296A4", "C17470E02AFDEC62BDD4396974131562", "C283F7750A6ADE7D3F26B4032AE48C3D", "C064ACFC1F658E883CF6C641F018F826"}},
+		// tiny products
+		{x: "00D15187B373CAFC800000000000030D", y: "BF8F17909B3B6C433C1E291A3E1BC848", want: [4]string{"BF8F17909B3B6C433C1E291A3E1BC848", "3F8F17909B3B6C43BC1E291A3E1BC848", "8070D3BC5246E19E0000000000000018", "8131D2FF7BBBEFC4800000000000CFD2"}},
+		{x: "83716CE7C2647B14800001A92AB292E5", y: "BF546DAF36C737C0BB4F62206F5735DA", want: [4]string{"BF546DAF36C737C0BB4F62206F5735DA", "3F546DAF36C737C03B4F62206F5735DA", "02D63F9670A949F5800007911394AAA8", "040B4BC06FDBB29E8091BC9A5A51D0F3"}},
+		{x: "19DAED3E2EFD2E1816369B8A68031842", y: "A4FE180FECCD75D0A0B3CFA510C81D98", want: [4]string{"A4FE180FECCD75D0A0B3CFA510C81D98", "24FE180FECCD75D020B3CFA510C81D98", "800000032A5534250000000000000000", "B4CCA1D3CD3A4EF0B16390F922425340"}},
+		{x: "044291E06EA85D160049B2D4FDD887F7", y: "C19556AE1DE8068F3E1110CA2E5EFC90", want: [4]string{"C19556AE1DE8068F3E1110CA2E5EFC90", "419556AE1DE8068FBE1110CA2E5EFC90", "85E8C410BCAE28240289DEE6D29B6658", "829BD90E941F11AC0000007B2AF26CF5"}},
+		// extreme exponents
+		{x: "63B597024EBF1E3C5FB05BF4C97A51E2", y: "2502EF65C3976744A0EA2775F19BE446", want: [4]string{"63B597024EBF1E3C5FB05BF4C97A51E2", "63B597024EBF1E3C5FB05BF4C97A51E2", "48C98CEB9C3A928BC53F93A1B5293051", "7EA23E43BB7F67B9FB47DF419A6EE0F9"}},
+	}
+	ops := []string{"+", "-", "*", "/"}
+	for _, g := range golden {
+		x, y := fromHex(g.x), fromHex(g.y)
+		got := [4]Float{x.Add(y), x.Sub(y), x.Mul(y), x.Div(y)}
+		for i, op := range ops {
+			a, b := got[i].Bits()
+			wantA, wantB := fromHex(g.want[i]).Bits()
+			if wantA != a || wantB != b {
+				t.Errorf("0xM%s %s 0xM%s: bits mismatch; expected 0xM%s, got 0xM%016X%016X", g.x, op, g.y, g.want[i], a, b)
+			}
+		}
+	}
+}
+
+func TestArithSpecial(t *testing.T) {
+	one := Float{high: 1}
+	golden := []struct {
+		op   string
+		got  Float
+		want Float
+	}{
+		{op: "Inf-Inf", got: Inf.Sub(Inf), want: NaN},
+		{op: "Inf*0", got: Inf.Mul(Zero), want: NaN},
+		{op: "1/-0", got: one.Div(NegZero), want: NegInf},
+		{op: "-1*0", got: one.Sub(Float{high: 2}).Mul(Zero), want: NegZero},
+		{op: "-0+-0", got: NegZero.Add(NegZero), want: NegZero},
+		{op: "1-1", got: one.Sub(one), want: Zero},
+		{op: "sqrt(-1)", got: Float{high: -1}.Sqrt(), want: NaN},
+		{op: "sqrt(-0)", got: NegZero.Sqrt(), want: NegZero},
+		{op: "sqrt(+Inf)", got: Inf.Sqrt(), want: Inf},
+		// The sum of the high parts overflows, but the sum of all parts does
+		// not.
+		{op: "max+2^970+2^970-2^971", got: Float{high: math.MaxFloat64, low: 0x1p970}.Add(Float{high: 0x1p970, low: -0x1p971}), want: Float{high: math.MaxFloat64}},
+	}
+	for _, g := range golden {
+		gotA, gotB := g.got.Bits()
+		wantA, wantB := g.want.Bits()
+		if g.want.IsNaN() {
+			if !g.got.IsNaN() {
+				t.Errorf("%s: expected NaN, got 0xM%016X%016X", g.op, gotA, gotB)
+			}
+			continue
+		}
+		if wantA != gotA || wantB != gotB {
+			t.Errorf("%s: bits mismatch; expected 0xM%016X%016X, got 0xM%016X%016X", g.op, wantA, wantB, gotA, gotB)
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	// Compare against big.Float; the result of Dekker's algorithm is within a
+	// few units in the last place of the 106-bit precision.
+	// The exponent of the high part ranges over all float64 numbers, including
+	// denormalized numbers.
+	r := rand.New(rand.NewSource(1234))
+	for i := 0; i < 10000; i++ {
+		high := math.Ldexp(r.Float64()+0.5, r.Intn(2096)-1074)
+		if high == 0 {
+			continue
+		}
+		low := math.Ldexp(r.Float64()-0.5, math.Ilogb(high)-53)
+		f := Float{high: high, low: low}
+		x, _ := f.Big()
+		want := new(big.Float).SetPrec(200).Sqrt(x)
+		got, _ := f.Sqrt().Big()
+		diff := new(big.Float).Sub(got, want)
+		diff.Quo(diff, want)
+		if e, _ := diff.Float64(); math.Abs(e) > 0x1p-103 {
+			high, low := f.Bits()
+			t.Errorf("sqrt(0xM%016X%016X): relative error %g too large", high, low, e)
+		}
+	}
+}
+
+func TestFMA(t *testing.T) {
+	// As for fmal on PowerPC, the product is rounded before the addition.
+	x, y, z := Float{high: 1.0 / 3, low: 1.0 / 3 * 0x1p-54}, Float{high: 3}, Float{high: -1}
+	got, want := x.FMA(y, z), x.Mul(y).Add(z)
+	if got != want {
+		gotA, gotB := got.Bits()
+		wantA, wantB := want.Bits()
+		t.Errorf("fma: bits mismatch; expected 0xM%016X%016X, got 0xM%016X%016X", wantA, wantB, gotA, gotB)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	one := Float{high: 1}
+	sNaN := NewNaN(true, 1)
+	golden := []struct {
+		op            string
+		x, y          Float
+		after, before fenv.Flags
+	}{
+		{op: "+", x: one, y: Float{high: 0x1p-200}},
+		// 1 + 2^(-60) + 2^(-170) has three non-adjacent parts.
+		{op: "+", x: Float{high: 1, low: 0x1p-60}, y: Float{high: 0x1p-170}, after: fenv.Inexact, before: fenv.Inexact},
+		{op: "+", x: Float{high: math.MaxFloat64}, y: Float{high: math.MaxFloat64}, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{op: "-", x: Inf, y: Inf, after: fenv.Invalid, before: fenv.Invalid},
+		{op: "-", x: Inf, y: NegInf},
+		{op: "+", x: sNaN, y: one, after: fenv.Invalid, before: fenv.Invalid},
+		{op: "+", x: NaN, y: one},
+		{op: "*", x: Float{high: 3}, y: Float{high: 1.0 / 3, low: 1.0 / 3 * 0x1p-54}, after: fenv.Inexact, before: fenv.Inexact},
+		// Exact denormalized product.
+		{op: "*", x: Float{high: 0x1p-1000}, y: Float{high: 0x1p-30}},
+		{op: "*", x: Float{high: 0x1p-600}, y: Float{high: 0x1p-600}, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-1022) - 2^(-1129), tiny only before rounding.
+		{op: "*", x: Float{high: 1, low: -0x1p-107}, y: Float{high: 0x1p-1022}, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{op: "*", x: Float{high: 1e200}, y: Float{high: 1e200}, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{op: "*", x: Inf, y: Zero, after: fenv.Invalid, before: fenv.Invalid},
+		{op: "/", x: one, y: Float{high: 4}},
+		{op: "/", x: one, y: Float{high: 3}, after: fenv.Inexact, before: fenv.Inexact},
+		{op: "/", x: one, y: Zero, after: fenv.DivByZero, before: fenv.DivByZero},
+		{op: "/", x: Zero, y: Zero, after: fenv.Invalid, before: fenv.Invalid},
+		{op: "/", x: Inf, y: Inf, after: fenv.Invalid, before: fenv.Invalid},
+		{op: "/", x: Float{high: math.SmallestNonzeroFloat64}, y: Float{high: 3}, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{op: "sqrt", x: Float{high: 4}},
+		{op: "sqrt", x: Float{high: 2}, after: fenv.Inexact, before: fenv.Inexact},
+		{op: "sqrt", x: Float{high: -1}, after: fenv.Invalid, before: fenv.Invalid},
+		{op: "sqrt", x: NegZero},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			var got, z Float
+			switch g.op {
+			case "+":
+				got, z = ctx.Add(g.x, g.y), g.x.Add(g.y)
+			case "-":
+				got, z = ctx.Sub(g.x, g.y), g.x.Sub(g.y)
+			case "*":
+				got, z = ctx.Mul(g.x, g.y), g.x.Mul(g.y)
+			case "/":
+				got, z = ctx.Div(g.x, g.y), g.x.Div(g.y)
+			case "sqrt":
+				got, z = ctx.Sqrt(g.x), g.x.Sqrt()
+			}
+			if got.IsNaN() != z.IsNaN() || !got.IsNaN() && got != z {
+				t.Errorf("%v %s %v: result mismatch; expected %v, got %v", g.x, g.op, g.y, z, got)
+			}
+			if want != ctx.Flags {
+				t.Errorf("%v %s %v (%v): flags mismatch; expected %v, got %v", g.x, g.op, g.y, tininess, want, ctx.Flags)
+			}
+		}
+	}
+	// The product and the addition of FMA both raise flags.
+	ctx := new(Context)
+	ctx.FMA(Float{high: 1e200}, Float{high: 1e200}, NegInf)
+	if want := fenv.Overflow | fenv.Inexact | fenv.Invalid; ctx.Flags != want {
+		t.Errorf("fma: flags mismatch; expected %v, got %v", want, ctx.Flags)
+	}
+}
+
+// fromHex returns the floating-point number with the given 32 digit hexadecimal
+// representation.
+func fromHex(s string) Float {
+	a, err := strconv.ParseUint(s[:16], 16, 64)
+	if err != nil {
+		panic(err)
+	}
+	b, err := strconv.ParseUint(s[16:], 16, 64)
+	if err != nil {
+		panic(err)
+	}
+	return NewFromBits(a, b)
+}
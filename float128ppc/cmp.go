@@ -0,0 +1,95 @@
+package float128ppc
+
+import (
+	"math"
+
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0. A NaN is considered less than any non-NaN and equal to a NaN,
+// as by cmp.Compare.
+func (f Float) Cmp(g Float) int {
+	x, xnan := f.Big()
+	y, ynan := g.Big()
+	return order.Cmp(x, xnan, y, ynan)
+}
+
+// Less reports whether f < g. It is false if f or g is NaN.
+func (f Float) Less(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is NaN, and true for -0
+// and +0.
+func (f Float) Equal(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder):
+//
+//	-qNaN < -sNaN < -Inf < -x < -0 < +0 < +x < +Inf < +sNaN < +qNaN
+//
+// NaNs of the same kind and sign are ordered by the payload of the high part,
+// or of the low part if the high part is not NaN, in reverse for negative NaNs.
+// Numbers of the same value but different representations are ordered as
+// equal.
+func (f Float) TotalOrder(g Float) bool {
+	x, xnan := f.Big()
+	y, ynan := g.Big()
+	cmp := 0
+	switch {
+	case xnan && ynan:
+		cmp = order.Payload(f.Payload(), g.Payload())
+	case !xnan && !ynan:
+		cmp = x.Cmp(y)
+	}
+	return order.Total(f.Class(), g.Class(), f.signbit(), g.signbit(), cmp)
+}
+
+// Class returns the class of f. Whether a NaN is signaling is given by the high
+// part, or by the low part if the high part is not NaN.
+func (f Float) Class() float.Class {
+	if f.IsNaN() {
+		if f.nanBits()&quiet == 0 {
+			return float.SignalingNaN
+		}
+		return float.QuietNaN
+	}
+	x, _ := f.Big()
+	return order.Class(x, -1022)
+}
+
+// IsInf reports whether f is +Inf or -Inf.
+func (f Float) IsInf() bool {
+	return !f.IsNaN() && (math.IsInf(f.high, 0) || math.IsInf(f.low, 0))
+}
+
+// IsSubnormal reports whether f is a subnormal number; i.e. a non-zero number
+// of magnitude below the smallest normalized float64.
+func (f Float) IsSubnormal() bool {
+	return f.Class().IsSubnormal()
+}
+
+// IsFinite reports whether f is neither infinite nor NaN.
+func (f Float) IsFinite() bool {
+	return !f.IsNaN() && !f.IsInf()
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f Float) IsZero() bool {
+	return f.high == 0 && f.low == 0
+}
+
+// signbit reports whether f is negative or negative 0, or whether the sign bit
+// of the Not-a-Number value f is set; in the high part, or in the low part if
+// the high part is not NaN.
+func (f Float) signbit() bool {
+	if f.IsNaN() {
+		return f.nanBits()&0x8000000000000000 != 0
+	}
+	x, _ := f.Big()
+	return x.Signbit()
+}
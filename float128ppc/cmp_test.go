@@ -0,0 +1,83 @@
+package float128ppc
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClass(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		a     uint64
+		b     uint64
+		class float.Class
+	}{
+		{a: 0xFFFFFFFFFFFFFFFF, b: 0x0000000000000000, class: float.QuietNaN},
+		{a: 0xFFF8000000000000, b: 0x0000000000000000, class: float.QuietNaN},
+		{a: 0xFFF7FFFFFFFFFFFF, b: 0x0000000000000000, class: float.SignalingNaN},
+		{a: 0xFFF0000000000001, b: 0x0000000000000000, class: float.SignalingNaN},
+		{a: 0xFFF0000000000000, b: 0x0000000000000000, class: float.NegativeInfinity},
+		{a: 0xFFEFFFFFFFFFFFFF, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0xBFF0000000000000, b: 0xBC30000000000000, class: float.NegativeNormal},
+		{a: 0xBFF0000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0xBFF0000000000000, b: 0x3C30000000000000, class: float.NegativeNormal},
+		{a: 0x8010000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x800FFFFFFFFFFFFF, b: 0x0000000000000000, class: float.NegativeSubnormal},
+		{a: 0x8000000000000001, b: 0x0000000000000000, class: float.NegativeSubnormal},
+		{a: 0x8000000000000000, b: 0x0000000000000000, class: float.NegativeZero},
+		{a: 0x0000000000000000, b: 0x0000000000000000, class: float.PositiveZero},
+		{a: 0x0000000000000001, b: 0x0000000000000000, class: float.PositiveSubnormal},
+		{a: 0x000FFFFFFFFFFFFF, b: 0x0000000000000000, class: float.PositiveSubnormal},
+		{a: 0x0010000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x3FF0000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x3FF0000000000000, b: 0x3C30000000000000, class: float.PositiveNormal},
+		{a: 0x7FEFFFFFFFFFFFFF, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x7FF0000000000000, b: 0x0000000000000000, class: float.PositiveInfinity},
+		{a: 0x7FF0000000000001, b: 0x0000000000000000, class: float.SignalingNaN},
+		{a: 0x7FF7FFFFFFFFFFFF, b: 0x0000000000000000, class: float.SignalingNaN},
+		{a: 0x7FF8000000000000, b: 0x0000000000000000, class: float.QuietNaN},
+		{a: 0x7FFFFFFFFFFFFFFF, b: 0x0000000000000000, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewFromBits(a.a, a.b)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X 0x%016X: class mismatch; expected %v, got %v", a.a, a.b, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X 0x%016X: predicate mismatch for class %v", a.a, a.b, a.class)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	// Comparison of double-double pairs with the same high part, ordered by
+	// their low part.
+	golden := []struct {
+		xa, xb, ya, yb uint64
+		cmp            int
+		total          bool
+	}{
+		// 1 - 2^-60, 1 and 1 + 2^-60.
+		{xa: 0x3FF0000000000000, xb: 0xBC30000000000000, ya: 0x3FF0000000000000, yb: 0x0000000000000000, cmp: -1, total: true},
+		{xa: 0x3FF0000000000000, xb: 0x3C30000000000000, ya: 0x3FF0000000000000, yb: 0x0000000000000000, cmp: +1, total: false},
+		// -1 - 2^-60, -1 and -1 + 2^-60.
+		{xa: 0xBFF0000000000000, xb: 0xBC30000000000000, ya: 0xBFF0000000000000, yb: 0x0000000000000000, cmp: -1, total: true},
+		{xa: 0xBFF0000000000000, xb: 0x3C30000000000000, ya: 0xBFF0000000000000, yb: 0x0000000000000000, cmp: +1, total: false},
+		// Largest subnormal and smallest normal number.
+		{xa: 0x000FFFFFFFFFFFFF, xb: 0x0000000000000000, ya: 0x0010000000000000, yb: 0x0000000000000000, cmp: -1, total: true},
+		// Positive and negative zero.
+		{xa: 0x0000000000000000, xb: 0x0000000000000000, ya: 0x8000000000000000, yb: 0x0000000000000000, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{xa: 0x7FF7FFFFFFFFFFFF, xb: 0x0000000000000000, ya: 0x7FF8000000000000, yb: 0x0000000000000000, cmp: 0, total: true},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.xa, g.xb), NewFromBits(g.ya, g.yb)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%016X 0x%016X.Cmp(0x%016X 0x%016X): mismatch; expected %d, got %d", g.xa, g.xb, g.ya, g.yb, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%016X 0x%016X.TotalOrder(0x%016X 0x%016X): mismatch; expected %v, got %v", g.xa, g.xb, g.ya, g.yb, g.total, got)
+		}
+	}
+}
@@ -7,6 +7,10 @@ package float128ppc
 import (
 	"math"
 	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/rounding"
 )
 
 const (
@@ -24,7 +28,7 @@ var (
 	// +Inf
 	Inf = Float{high: math.Inf(1), low: 0}
 	// -Inf
-	NegInf = Float{high: -math.Inf(-1), low: 0}
+	NegInf = Float{high: math.Inf(-1), low: 0}
 	// +zero
 	Zero = Float{high: 0, low: 0}
 	// -zero
@@ -50,8 +54,19 @@ func NewFromBits(a, b uint64) Float {
 }
 
 // NewFromFloat32 returns the nearest double-double precision floating-point
-// number for x and the accuracy of the conversion.
+// number for x and the accuracy of the conversion. Not-a-Number values are
+// converted to a NaN with the same sign, payload and quiet bit, as on PowerPC.
 func NewFromFloat32(x float32) (Float, big.Accuracy) {
+	// +-NaN, with the payload of the binary representation of x; see package
+	// payload.
+	if x != x {
+		sign, frac := payload.FromFloat32(x)
+		bits := 0x7FF0000000000000 | frac>>12
+		if sign {
+			bits |= 0x8000000000000000
+		}
+		return Float{high: math.Float64frombits(bits), low: 0}, big.Exact
+	}
 	f, acc := NewFromFloat64(float64(x))
 	if acc == big.Exact {
 		_, acc = f.Float32()
@@ -60,17 +75,12 @@ func NewFromFloat32(x float32) (Float, big.Accuracy) {
 }
 
 // NewFromFloat64 returns the nearest double-double precision floating-point
-// number for x and the accuracy of the conversion.
+// number for x and the accuracy of the conversion. Not-a-Number values are
+// kept as the high part, as on PowerPC.
 func NewFromFloat64(x float64) (Float, big.Accuracy) {
 	// +-NaN
-	switch {
-	case math.IsNaN(x):
-		if math.Signbit(x) {
-			// -NaN
-			return NegNaN, big.Exact
-		}
-		// +NaN
-		return NaN, big.Exact
+	if math.IsNaN(x) {
+		return Float{high: x, low: 0}, big.Exact
 	}
 	r := Float{high: x, low: 0}
 	br, _ := r.Big()
@@ -80,6 +90,31 @@ func NewFromFloat64(x float64) (Float, big.Accuracy) {
 // NewFromBig returns the nearest double-double floating-point number for x and
 // the accuracy of the conversion.
 func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromFloat32Mode returns the double-double floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion. Every
+// float32 is exactly representable, so the result is independent of the
+// rounding mode.
+func NewFromFloat32Mode(x float32, mode big.RoundingMode) (Float, big.Accuracy) {
+	return NewFromFloat32(x)
+}
+
+// NewFromFloat64Mode returns the double-double floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion. Every
+// float64 is exactly representable, so the result is independent of the
+// rounding mode.
+func NewFromFloat64Mode(x float64, mode big.RoundingMode) (Float, big.Accuracy) {
+	return NewFromFloat64(x)
+}
+
+// NewFromBigMode returns the double-double floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion. The high part
+// is x rounded to nearest even, and the low part the remainder rounded in the
+// given mode. Values too large in magnitude are converted to +-Inf, or to the
+// largest finite number of the same sign if the mode rounds them towards zero.
+func NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
 	// +-Inf
 	zero := big.NewFloat(0).SetPrec(precision)
 	switch {
@@ -100,24 +135,134 @@ func NewFromBig(x *big.Float) (Float, big.Accuracy) {
 		return Zero, big.Exact
 	}
 
-	// set precision of x.
-	x.SetPrec(precision).SetMode(big.ToNearestEven)
-
 	// get high part of the double-double floating-point value.
 	high, _ := x.Float64()
-	h := big.NewFloat(high).SetPrec(precision).SetMode(big.ToNearestEven)
+	if math.IsInf(high, 0) {
+		return overflow(x.Signbit(), mode)
+	}
+	h := big.NewFloat(high)
 
-	// compute low part by subtracting high from x.
-	l := big.NewFloat(0).SetPrec(precision).SetMode(big.ToNearestEven)
+	// compute low part by subtracting high from x; the difference is exact
+	// using the precision of x.
+	l := new(big.Float).SetPrec(x.Prec() + 1)
 	l.Sub(x, h)
-	low, _ := l.Float64()
+	low := roundLow(l, mode, x.Signbit())
+
+	// renormalize, so that the high part is the sum of both parts rounded to
+	// nearest even.
+	if sum := high + low; sum != high {
+		if math.IsInf(sum, 0) {
+			return overflow(x.Signbit(), mode)
+		}
+		low -= sum - high
+		high = sum
+	}
 
 	// check accuracy of results.
-	result := big.NewFloat(0).SetPrec(precision).SetMode(big.ToNearestEven)
-	result.Add(h, l)
-	acc := big.Accuracy(x.Cmp(result))
+	r := Float{high: high, low: low}
+	result, _ := r.Big()
+	acc := big.Accuracy(result.Cmp(x))
 
-	return Float{high: high, low: low}, acc
+	return r, acc
+}
+
+// NewFromBig returns the nearest double-double floating-point number for x and
+// the accuracy of the conversion. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return ctx.NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromBigMode returns the double-double floating-point number for x rounded
+// in the given rounding mode, and the accuracy of the conversion, as for
+// NewFromBigMode. The exception flags raised are recorded in ctx.
+func (ctx *Context) NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
+	f, acc := NewFromBigMode(x, mode)
+	if acc == big.Exact {
+		return f, acc
+	}
+	max, _ := overflow(false, big.ToZero)
+	maxBig, _ := max.Big()
+	if math.IsInf(f.high, 0) || new(big.Float).Abs(x).Cmp(maxBig) > 0 && !rounding.Inf(mode, x.Signbit()) {
+		ctx.Flags |= fenv.Overflow | fenv.Inexact
+		return f, acc
+	}
+	ctx.Flags |= rounding.Flags(x, acc, precision, emin, math.MaxInt32, mode, ctx.Tininess)
+	return f, acc
+}
+
+// overflow returns the result of rounding a value too large in magnitude, with
+// the sign bit set if neg is set, in the given rounding mode, and the accuracy
+// of the result.
+func overflow(neg bool, mode big.RoundingMode) (Float, big.Accuracy) {
+	if rounding.Inf(mode, neg) {
+		if neg {
+			return NegInf, big.Below
+		}
+		return Inf, big.Above
+	}
+	// Largest finite number; the low part is the largest float64 which leaves
+	// the high part unchanged when added to it.
+	const low = 0x1.fffffffffffffp969
+	if neg {
+		return Float{high: -math.MaxFloat64, low: -low}, big.Above
+	}
+	return Float{high: math.MaxFloat64, low: low}, big.Below
+}
+
+// roundLow returns the low part l of a double-double number rounded to a
+// float64 in the given rounding mode, where the direction of rounding is
+// relative to the double-double number, of sign neg, rather than to l.
+func roundLow(l *big.Float, mode big.RoundingMode, neg bool) float64 {
+	switch mode {
+	case big.ToZero, big.AwayFromZero:
+		// Round towards or away from zero of the double-double number.
+		if (mode == big.ToZero) == neg {
+			return float64Mode(l, big.ToPositiveInf)
+		}
+		return float64Mode(l, big.ToNegativeInf)
+	case big.ToNearestAway:
+		if l.Signbit() != neg {
+			// Ties of l are rounded towards zero, which is away from zero of
+			// the double-double number.
+			lo, hi := float64Mode(l, big.ToZero), float64Mode(l, big.AwayFromZero)
+			mid := new(big.Float).SetPrec(54).SetFloat64(lo)
+			mid.Add(mid, big.NewFloat(hi)).SetMantExp(mid, -1)
+			if l.Cmp(mid) == 0 {
+				return lo
+			}
+		}
+	}
+	return float64Mode(l, mode)
+}
+
+// float64Mode returns x rounded to a float64 in the given rounding mode. The
+// magnitude of x must be less than math.MaxFloat64.
+func float64Mode(x *big.Float, mode big.RoundingMode) float64 {
+	// Round to the precision available at the exponent of x; denormalized
+	// numbers have fewer significant bits.
+	const emin = -1022
+	prec := 53
+	if exp := x.MantExp(nil) - 1; x.Sign() != 0 && exp < emin {
+		prec -= emin - exp
+	}
+	if prec < 1 {
+		// |x| is less than the smallest denormalized number, 2^(-1074); round
+		// to zero or to the smallest denormalized number.
+		abs := new(big.Float).Abs(x)
+		half := new(big.Float).SetMantExp(big.NewFloat(1), -1075)
+		f := 0.0
+		if rounding.Up(mode, x.Signbit(), false, abs.Cmp(half)) {
+			f = math.SmallestNonzeroFloat64
+		}
+		if x.Signbit() {
+			return -f
+		}
+		return f
+	}
+	y := new(big.Float).SetMode(mode).SetPrec(uint(prec)).Set(x)
+	f, _ := y.Float64()
+	return f
 }
 
 // Bits returns the double-double binary representation of f.
@@ -125,30 +270,61 @@ func (f Float) Bits() (a, b uint64) {
 	return math.Float64bits(f.high), math.Float64bits(f.low)
 }
 
-// Float32 returns the float32 representation of f.
+// Float32 returns the float32 representation of f. Not-a-Number values are
+// converted to a quiet NaN with the sign and the most significant bits of the
+// payload of the high part, or of the low part if the high part is not NaN.
 func (f Float) Float32() (float32, big.Accuracy) {
 	x, nan := f.Big()
 	if nan {
-		if x.Signbit() {
-			return float32(-math.NaN()), big.Exact
-		}
-		return float32(math.NaN()), big.Exact
+		bits := f.nanBits()
+		return payload.Float32(bits&0x8000000000000000 != 0, bits<<12), big.Exact
 	}
 	return x.Float32()
 }
 
-// Float64 returns the float64 representation of f.
+// Float64 returns the float64 representation of f. Not-a-Number values are
+// converted to the high part, or to the low part if the high part is not NaN,
+// with the quiet bit set.
 func (f Float) Float64() (float64, big.Accuracy) {
 	x, nan := f.Big()
 	if nan {
-		if x.Signbit() {
-			return -math.NaN(), big.Exact
-		}
-		return math.NaN(), big.Exact
+		return math.Float64frombits(f.nanBits() | quiet), big.Exact
 	}
 	return x.Float64()
 }
 
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float32(f Float) (float32, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if f.IsSignaling() {
+			ctx.Flags |= fenv.Invalid
+		}
+		return f.Float32()
+	}
+	y, acc := x.Float32()
+	ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+	return y, acc
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float64(f Float) (float64, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if f.IsSignaling() {
+			ctx.Flags |= fenv.Invalid
+		}
+		return f.Float64()
+	}
+	y, acc := x.Float64()
+	ctx.Flags |= rounding.Flags(x, acc, 53, -1022, 1023, big.ToNearestEven, ctx.Tininess)
+	return y, acc
+}
+
 // Big returns the multi-precision floating-point number representation of f and
 // a boolean indicating whether f is Not-a-Number.
 func (f Float) Big() (x *big.Float, nan bool) {
@@ -158,8 +334,20 @@ func (f Float) Big() (x *big.Float, nan bool) {
 	if f.IsNaN() {
 		return x, true
 	}
-	h := big.NewFloat(f.high).SetPrec(precision)
-	l := big.NewFloat(f.low).SetPrec(precision)
+	// The high and low parts of a double-double need not be adjacent, so use
+	// enough precision to represent their sum exactly.
+	if f.high != 0 && f.low != 0 && !math.IsInf(f.high, 0) && !math.IsInf(f.low, 0) {
+		_, eh := math.Frexp(f.high)
+		_, el := math.Frexp(f.low)
+		if eh < el {
+			eh, el = el, eh
+		}
+		if prec := eh - el + 54; prec > precision {
+			x.SetPrec(uint(prec))
+		}
+	}
+	h := big.NewFloat(f.high)
+	l := big.NewFloat(f.low)
 	x.Add(h, l)
 
 	zero := big.NewFloat(0).SetPrec(precision)
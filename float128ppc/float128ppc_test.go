@@ -1,8 +1,12 @@
 package float128ppc
 
 import (
+	"math"
 	"math/big"
+	"math/rand"
 	"testing"
+
+	"github.com/mewmew/float/fenv"
 )
 
 func TestRoundTrip(t *testing.T) {
@@ -42,3 +46,252 @@ func TestRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestNewFromFloat64Mode(t *testing.T) {
+	// Every float32 and float64 is exactly representable, so the result is
+	// independent of the rounding mode.
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	xs := []float64{0, math.Copysign(0, -1), 1.0 / 3, -1.0 / 3, math.MaxFloat64, -math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1)}
+	for _, x := range xs {
+		want, _ := NewFromFloat64(x)
+		want32, _ := NewFromFloat32(float32(x))
+		for _, mode := range modes {
+			if got, acc := NewFromFloat64Mode(x, mode); got != want || acc != big.Exact {
+				t.Errorf("%v %v: float64 mismatch; expected %v (%v), got %v (%v)", x, mode, want, big.Exact, got, acc)
+			}
+			if got, acc := NewFromFloat32Mode(float32(x), mode); got != want32 || acc != big.Exact {
+				t.Errorf("%v %v: float32 mismatch; expected %v (%v), got %v (%v)", float32(x), mode, want32, big.Exact, got, acc)
+			}
+		}
+	}
+}
+
+func TestNewFromBigMode(t *testing.T) {
+	third, _, err := big.ParseFloat("0.333333333333333333333333333333333333333333", 10, 300, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	negThird := new(big.Float).Neg(third)
+	golden := []struct {
+		in   *big.Float
+		mode big.RoundingMode
+		want string
+		acc  big.Accuracy
+	}{
+		// 1/3
+		{in: third, mode: big.ToNearestEven, want: "3FD55555555555553C75555555555555", acc: big.Below},
+		{in: third, mode: big.ToPositiveInf, want: "3FD55555555555553C75555555555556", acc: big.Above},
+		{in: negThird, mode: big.ToZero, want: "BFD5555555555555BC75555555555555", acc: big.Above},
+		{in: negThird, mode: big.AwayFromZero, want: "BFD5555555555555BC75555555555556", acc: big.Below},
+		{in: negThird, mode: big.ToNegativeInf, want: "BFD5555555555555BC75555555555556", acc: big.Below},
+		// 1 + 2^(-60) + 2^(-113); the low part is halfway between two numbers.
+		{in: sum(1, 0x1p-60, 0x1p-113), mode: big.ToNearestEven, want: "3FF00000000000003C30000000000000", acc: big.Below},
+		{in: sum(1, 0x1p-60, 0x1p-113), mode: big.ToNearestAway, want: "3FF00000000000003C30000000000001", acc: big.Above},
+		// 1 - 2^(-60) - 3*2^(-113); ties of the negative low part are rounded
+		// towards zero to round the positive number away from zero.
+		{in: sum(1, -0x1p-60, -0x3p-113), mode: big.ToNearestEven, want: "3FF0000000000000BC30000000000002", acc: big.Below},
+		{in: sum(1, -0x1p-60, -0x3p-113), mode: big.ToNearestAway, want: "3FF0000000000000BC30000000000001", acc: big.Above},
+		{in: sum(1, -0x1p-60, -0x3p-113), mode: big.ToZero, want: "3FF0000000000000BC30000000000002", acc: big.Below},
+		// Overflow.
+		{in: sum(0x1p1023, 0x1p1023), mode: big.ToNearestEven, want: "7FF00000000000000000000000000000", acc: big.Above},
+		{in: sum(0x1p1023, 0x1p1023), mode: big.ToZero, want: "7FEFFFFFFFFFFFFF7C8FFFFFFFFFFFFF", acc: big.Below},
+		{in: sum(-0x1p1023, -0x1p1023), mode: big.ToPositiveInf, want: "FFEFFFFFFFFFFFFFFC8FFFFFFFFFFFFF", acc: big.Above},
+		// Underflow.
+		{in: ldexp(1, -1100), mode: big.ToNearestEven, want: "00000000000000000000000000000000", acc: big.Below},
+		{in: ldexp(1, -1100), mode: big.AwayFromZero, want: "00000000000000010000000000000000", acc: big.Above},
+		{in: ldexp(-1, -1100), mode: big.ToNegativeInf, want: "80000000000000010000000000000000", acc: big.Below},
+		{in: ldexp(-1, -1100), mode: big.ToPositiveInf, want: "80000000000000000000000000000000", acc: big.Above},
+	}
+	for _, g := range golden {
+		got, acc := NewFromBigMode(g.in, g.mode)
+		if want := fromHex(g.want); want != got {
+			a, b := got.Bits()
+			t.Errorf("%v %v: bits mismatch; expected 0xM%s, got 0xM%016X%016X", g.in, g.mode, g.want, a, b)
+		}
+		if g.acc != acc {
+			t.Errorf("%v %v: accuracy mismatch; expected %v, got %v", g.in, g.mode, g.acc, acc)
+		}
+	}
+}
+
+func TestNewFromBigModes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Random numbers of 160 bits.
+		x := new(big.Float).SetPrec(160)
+		for j := 0; j < 5; j++ {
+			x.SetMantExp(x, 32)
+			x.Add(x, big.NewFloat(float64(r.Uint32())))
+		}
+		x.SetMantExp(x, r.Intn(2100)-1200)
+		if i%2 == 1 {
+			x.Neg(x)
+		}
+		nearest, acc := NewFromBig(x)
+		if got, gotAcc := NewFromBigMode(x, big.ToNearestEven); got != nearest || gotAcc != acc {
+			t.Errorf("%v: rounding mismatch; expected %v (%v), got %v (%v)", x, nearest, acc, got, gotAcc)
+		}
+		results := make(map[big.RoundingMode]Float)
+		for _, mode := range []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf} {
+			f, acc := NewFromBigMode(x, mode)
+			results[mode] = f
+			// The high part is the sum of both parts rounded to nearest even.
+			if f.high+f.low != f.high {
+				t.Errorf("%v %v: not normalized; got %v", x, mode, f)
+			}
+			y, _ := f.Big()
+			if c := big.Accuracy(y.Cmp(x)); c != acc {
+				t.Errorf("%v %v: accuracy mismatch; expected %v, got %v", x, mode, c, acc)
+			}
+			switch mode {
+			case big.ToNegativeInf:
+				if acc == big.Above {
+					t.Errorf("%v %v: rounded up", x, mode)
+				}
+			case big.ToPositiveInf:
+				if acc == big.Below {
+					t.Errorf("%v %v: rounded down", x, mode)
+				}
+			}
+		}
+		toward, away := results[big.ToNegativeInf], results[big.ToPositiveInf]
+		if x.Signbit() {
+			toward, away = away, toward
+		}
+		if results[big.ToZero] != toward || results[big.AwayFromZero] != away {
+			t.Errorf("%v: directed rounding mismatch", x)
+		}
+	}
+}
+
+func TestSpecial(t *testing.T) {
+	golden := []struct {
+		in   Float
+		want float64
+	}{
+		{in: Inf, want: math.Inf(1)},
+		// NegInf was +Inf, as -math.Inf(-1) is +Inf.
+		{in: NegInf, want: math.Inf(-1)},
+		{in: Zero, want: 0},
+		{in: NegZero, want: math.Copysign(0, -1)},
+	}
+	for _, g := range golden {
+		got, acc := g.in.Float64()
+		if math.Float64bits(g.want) != math.Float64bits(got) || acc != big.Exact {
+			t.Errorf("%v: mismatch; expected %v (%v), got %v (%v)", g.in, g.want, big.Exact, got, acc)
+		}
+	}
+}
+
+func TestConversionFlags(t *testing.T) {
+	huge := new(big.Float).SetMantExp(big.NewFloat(1), 1100)
+	tiny := new(big.Float).SetMantExp(big.NewFloat(1), -1100)
+	golden := []struct {
+		in   *big.Float
+		mode big.RoundingMode
+		want fenv.Flags
+	}{
+		{in: sum(1, 0x1p-200), mode: big.ToNearestEven},
+		{in: sum(1, 0x1p-60, 0x1p-200), mode: big.ToNearestEven, want: fenv.Inexact},
+		{in: huge, mode: big.ToNearestEven, want: fenv.Overflow | fenv.Inexact},
+		{in: huge, mode: big.ToZero, want: fenv.Overflow | fenv.Inexact},
+		{in: tiny, mode: big.ToNearestEven, want: fenv.Underflow | fenv.Inexact},
+		{in: tiny, mode: big.AwayFromZero, want: fenv.Underflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		ctx := new(Context)
+		ctx.NewFromBigMode(g.in, g.mode)
+		if g.want != ctx.Flags {
+			t.Errorf("%v %v: flags mismatch; expected %v, got %v", g.in.Text('p', 0), g.mode, g.want, ctx.Flags)
+		}
+	}
+	// Conversions to float32 and float64.
+	ctx := new(Context)
+	ctx.Float64(Float{high: 1, low: 0x1p-60})
+	if ctx.Flags != fenv.Inexact {
+		t.Errorf("float64: flags mismatch; expected %v, got %v", fenv.Inexact, ctx.Flags)
+	}
+	ctx = new(Context)
+	ctx.Float32(Float{high: 1e300})
+	if want := fenv.Overflow | fenv.Inexact; ctx.Flags != want {
+		t.Errorf("float32: flags mismatch; expected %v, got %v", want, ctx.Flags)
+	}
+	ctx = new(Context)
+	ctx.Float32(NewNaN(true, 1))
+	if ctx.Flags != fenv.Invalid {
+		t.Errorf("float32: flags mismatch; expected %v, got %v", fenv.Invalid, ctx.Flags)
+	}
+}
+
+func TestNewFromBigArgument(t *testing.T) {
+	// NewFromBig must not change the precision, nor round the value, of its
+	// argument.
+	x := sum(1, 0x1p-150)
+	prec := x.Prec()
+	NewFromBig(x)
+	if x.Prec() != prec {
+		t.Errorf("precision of argument changed; expected %d, got %d", prec, x.Prec())
+	}
+	if want := sum(1, 0x1p-150); x.Cmp(want) != 0 {
+		t.Errorf("value of argument changed; expected %v, got %v", want.Text('p', 0), x.Text('p', 0))
+	}
+}
+
+func TestNewFromBigAccuracy(t *testing.T) {
+	// The accuracy is that of the result relative to x, and not of x relative
+	// to the result.
+	third := new(big.Float).SetPrec(200).Quo(big.NewFloat(1), big.NewFloat(3))
+	golden := []struct {
+		in   *big.Float
+		want big.Accuracy
+	}{
+		{in: third, want: big.Below},
+		{in: new(big.Float).Neg(third), want: big.Above},
+		{in: sum(1, 0x1p-60, 0x1p-200), want: big.Below},
+		{in: sum(1, 0x1p-60, -0x1p-200), want: big.Above},
+		{in: sum(-1, -0x1p-60, 0x1p-200), want: big.Below},
+		{in: sum(1, 0x1p-100), want: big.Exact},
+	}
+	for _, g := range golden {
+		if _, acc := NewFromBig(g.in); g.want != acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in.Text('p', 0), g.want, acc)
+		}
+	}
+}
+
+func TestBigPrecision(t *testing.T) {
+	// The high and low parts need not be adjacent, and Big represents their
+	// sum exactly, using more than 106 bits of precision if needed.
+	golden := []struct {
+		high, low float64
+	}{
+		{high: 1, low: 0x1p-60},
+		{high: 1, low: 0x1p-200},
+		{high: 0x1p1000, low: -0x1p-1000},
+		{high: 0x1p-1000, low: 0x1p-1074},
+	}
+	for _, g := range golden {
+		x, _ := Float{high: g.high, low: g.low}.Big()
+		if want := sum(g.high, g.low); x.Cmp(want) != 0 {
+			t.Errorf("%v + %v: value mismatch; expected %v, got %v", g.high, g.low, want.Text('p', 0), x.Text('p', 0))
+		}
+		if x.Prec() < precision {
+			t.Errorf("%v + %v: precision mismatch; expected at least %d, got %d", g.high, g.low, precision, x.Prec())
+		}
+	}
+}
+
+// sum returns the exact sum of the given numbers.
+func sum(xs ...float64) *big.Float {
+	z := new(big.Float).SetPrec(2200)
+	for _, x := range xs {
+		z.Add(z, big.NewFloat(x))
+	}
+	return z
+}
+
+// ldexp returns x * 2^exp.
+func ldexp(x float64, exp int) *big.Float {
+	z := big.NewFloat(x)
+	return z.SetMantExp(z, exp)
+}
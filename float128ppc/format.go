@@ -0,0 +1,86 @@
+package float128ppc
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary for Parse to return f exactly; i.e. such that the
+// high part is the float64 nearest to the decimal value and the low part the
+// float64 nearest to the remainder. The 'b' format uses the smallest integer
+// mantissa representing f exactly.
+func (f Float) Text(fmt byte, prec int) string {
+	x, nan := f.Big()
+	if nan {
+		return "NaN"
+	}
+	if x.IsInf() || x.Sign() == 0 {
+		return strconv.FormatBigRange(x, x, x, true, fmt, prec)
+	}
+	// The decimal value must round to the high part, and its remainder to the
+	// low part.
+	hlo, hhi := halfway(f.high)
+	llo, lhi := halfway(f.low)
+	h := big.NewFloat(f.high)
+	lower := new(big.Float).SetPrec(parsePrec).Add(h, llo)
+	if lower.Cmp(hlo) < 0 {
+		lower = hlo
+	}
+	upper := new(big.Float).SetPrec(parsePrec).Add(h, lhi)
+	if upper.Cmp(hhi) > 0 {
+		upper = hhi
+	}
+	// The bounds round to the low part only if it is even, using round half to
+	// even.
+	inclusive := math.Float64bits(f.low)&1 == 0
+	return strconv.FormatBigRange(x, lower, upper, inclusive, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which Parse converts
+// back to f, as for f.Text('g', -1).
+func (f Float) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Float) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// halfway returns the halfway points between the finite x and its neighbouring
+// float64 values.
+func halfway(x float64) (lower, upper *big.Float) {
+	// Half the distance to each neighbour. The neighbours of +-math.MaxFloat64
+	// beyond the range of float64 are at the same distance as the other
+	// neighbour.
+	down := halfGap(x, math.Nextafter(x, math.Inf(-1)))
+	up := halfGap(x, math.Nextafter(x, math.Inf(1)))
+	switch {
+	case down.IsInf():
+		down = up
+	case up.IsInf():
+		up = down
+	}
+	v := big.NewFloat(x)
+	lower = new(big.Float).SetPrec(parsePrec).Sub(v, down)
+	upper = new(big.Float).SetPrec(parsePrec).Add(v, up)
+	return lower, upper
+}
+
+// halfGap returns |x-y|/2, exactly.
+func halfGap(x, y float64) *big.Float {
+	z := new(big.Float).SetPrec(parsePrec).Sub(big.NewFloat(x), big.NewFloat(y))
+	if z.IsInf() {
+		return z
+	}
+	z.Abs(z)
+	return z.SetMantExp(z, -1)
+}
@@ -0,0 +1,117 @@
+package float128ppc
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	golden := []struct {
+		in   string
+		fmt  byte
+		prec int
+		want string
+	}{
+		// Special numbers.
+		{in: "00000000000000000000000000000000", fmt: 'g', prec: -1, want: "0"},
+		{in: "80000000000000000000000000000000", fmt: 'e', prec: 3, want: "-0.000e+00"},
+		{in: "7FF00000000000000000000000000000", fmt: 'g', prec: -1, want: "+Inf"},
+		{in: "FFF00000000000000000000000000000", fmt: 'f', prec: 2, want: "-Inf"},
+		{in: "7FF80000000000000000000000000000", fmt: 'g', prec: -1, want: "NaN"},
+
+		{in: "3FF00000000000000000000000000000", fmt: 'g', prec: -1, want: "1"},
+		{in: "3FF00000000000000000000000000000", fmt: 'b', prec: -1, want: "1p+0"},
+		{in: "BFF0000000000000BC90000000000000", fmt: 'g', prec: -1, want: "-1.00000000000000005551115123125783"},
+		{in: "3FB999999999999ABC5999999999999A", fmt: 'g', prec: -1, want: "0.1"},
+		{in: "3FB999999999999ABC5999999999999A", fmt: 'G', prec: 40, want: "0.09999999999999999999999999999999969185121"},
+		{in: "3FB999999999999ABC5999999999999A", fmt: 'x', prec: -1, want: "0x1.999999999999999999999999998p-04"},
+		// The float64 value nearest to 0.1 is only represented exactly.
+		{in: "3FB999999999999A0000000000000000", fmt: 'g', prec: -1, want: "0.1000000000000000055511151231257827021181583404541015625"},
+		{in: "3FB999999999999A0000000000000000", fmt: 'e', prec: 5, want: "1.00000e-01"},
+		{in: "400921FB54442D183CA1A62633145C07", fmt: 'g', prec: -1, want: "3.1415926535897932384626433832795"},
+		{in: "7FEFFFFFFFFFFFFF7C9FFFFFFFFFFFFF", fmt: 'g', prec: -1, want: "1.797693134862315907729305190789e+308"},
+		{in: "00000000000000010000000000000000", fmt: 'g', prec: -1, want: "5e-324"},
+		{in: "00000000000000010000000000000000", fmt: 'b', prec: -1, want: "1p-1074"},
+	}
+	for _, g := range golden {
+		f := fromHex(g.in)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("%s %c %d: text mismatch; expected %q, got %q", g.in, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		// Double-double quotients are canonical.
+		x := Float{high: math.Float64frombits(r.Uint64() >> 1)}
+		y := Float{high: math.Float64frombits(r.Uint64())}
+		f := x.Div(y)
+		if f.IsNaN() || math.IsInf(f.high, 0) {
+			continue
+		}
+		s := f.String()
+		got, _, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if f != got {
+			t.Errorf("%q: round-trip mismatch; expected %v+%v, got %v+%v", s, f.high, f.low, got.high, got.low)
+			continue
+		}
+		// The nearest decimal with one digit less does not round-trip.
+		e := f.Text('e', -1)
+		nd := strings.IndexByte(e, 'e') - 1
+		if math.Signbit(f.high) {
+			nd--
+		}
+		if nd < 2 {
+			continue
+		}
+		if s := f.Text('e', nd-2); mustParse(s) == f {
+			t.Errorf("%q: not shortest; %q also round-trips", e, s)
+		}
+	}
+}
+
+func mustParse(s string) Float {
+	f, _, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		in     string
+		want   string
+	}{
+		{format: "%v", in: "3FF00000000000000000000000000000", want: "1"},
+		{format: "%+.3e", in: "3FF00000000000000000000000000000", want: "+1.000e+00"},
+		{format: "%.40g", in: "3FB999999999999ABC5999999999999A", want: "0.09999999999999999999999999999999969185121"},
+		{format: "%#v", in: "3FF00000000000000000000000000000", want: "1"},
+		{format: "%#g", in: "3FF00000000000000000000000000000", want: "1.00000"},
+		{format: "%8.2f|", in: "BFF00000000000000000000000000000", want: "   -1.00|"},
+		{format: "%-8.2f|", in: "BFF00000000000000000000000000000", want: "-1.00   |"},
+		{format: "%08.2f", in: "BFF00000000000000000000000000000", want: "-0001.00"},
+		{format: "% x", in: "3FF00000000000000000000000000000", want: " 0x1p+00"},
+		{format: "%F", in: "3FF00000000000000000000000000000", want: "1.000000"},
+		{format: "%v", in: "7FF00000000000000000000000000000", want: "+Inf"},
+		{format: "%6v", in: "7FF80000000000000000000000000000", want: "   NaN"},
+		{format: "%s", in: "3FF00000000000000000000000000000", want: "%!s(float128ppc.Float=1)"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, fromHex(g.in))
+		if g.want != got {
+			t.Errorf("%q %v: output mismatch; expected %q, got %q", g.format, g.in, g.want, got)
+		}
+	}
+}
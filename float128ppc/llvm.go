@@ -0,0 +1,27 @@
+package float128ppc
+
+import (
+	"fmt"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// ParseLLVM parses s as an LLVM IR hexadecimal floating-point literal of type
+// ppc_fp128; "0xM" followed by the 16 hexadecimal digits of the binary
+// representation of the high part and of the low part, and returns the
+// corresponding floating-point number. If s is syntactically invalid, the error
+// wraps strconv.ErrSyntax.
+func ParseLLVM(s string) (Float, error) {
+	words, err := strconv.ParseLLVMHex(s, 'M', 64, 64)
+	if err != nil {
+		return Float{}, fmt.Errorf("float128ppc.ParseLLVM: parsing %q: %w", s, err)
+	}
+	return NewFromBits(words[0], words[1]), nil
+}
+
+// LLVMString returns the LLVM IR hexadecimal floating-point literal of f, as
+// accepted by ParseLLVM; e.g. "0xM3FF00000000000000000000000000000" for 1.0.
+func (f Float) LLVMString() string {
+	a, b := f.Bits()
+	return fmt.Sprintf("0xM%016X%016X", a, b)
+}
@@ -0,0 +1,48 @@
+package float128ppc
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLLVM(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "0xM3FF00000000000000000000000000000", want: "1"},
+		{in: "0xMBFF80000000000000000000000000000", want: "-1.5"},
+		{in: "0xM400921FB54442D183CA1A62633145C07", want: "3.1415926535897932384626433832795"},
+		{in: "0xM3fb999999999999abc5999999999999a", want: "0.1"},
+		{in: "0xM00000000000000010000000000000000", want: "5e-324"},
+		{in: "0xM7FF00000000000000000000000000000", want: "+Inf"},
+		{in: "0xM7FF80000000000000000000000000000", want: "NaN"},
+		{in: "0xM80000000000000000000000000000000", want: "-0"},
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "0xM3FF0000000000000000000000000000", err: strconv.ErrSyntax},
+		{in: "0xM3FF000000000000000000000000000000", err: strconv.ErrSyntax},
+		{in: "0xL3FF00000000000000000000000000000", err: strconv.ErrSyntax},
+		{in: "0xM3FF0000000000000+000000000000000", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, err := ParseLLVM(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err != nil {
+			continue
+		}
+		if s := got.String(); g.want != s {
+			t.Errorf("%q: value mismatch; expected %v, got %v", g.in, g.want, s)
+		}
+		// Round-trip.
+		if s := got.LLVMString(); strings.ToUpper(g.in[3:]) != s[3:] || g.in[:3] != s[:3] {
+			t.Errorf("%q: round-trip mismatch; got %q", g.in, s)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package float128ppc
+
+import (
+	"math"
+)
+
+// quiet specifies the quiet bit of Not-a-Number values, in the float64
+// representation of the high and low parts.
+const quiet = 0x0008000000000000
+
+// NewNaN returns a Not-a-Number value with the given payload in the high part,
+// which is quiet unless signaling is set. The payload is the fraction of the
+// high part without the quiet bit, truncated to 51 bits. A signaling NaN with a
+// zero payload has payload 1, as its encoding would otherwise be infinity.
+func NewNaN(signaling bool, payload uint64) Float {
+	bits := 0x7FF0000000000000 | payload&0x0007FFFFFFFFFFFF
+	switch {
+	case !signaling:
+		bits |= quiet
+	case payload&0x0007FFFFFFFFFFFF == 0:
+		bits |= 1
+	}
+	return Float{high: math.Float64frombits(bits), low: 0}
+}
+
+// IsSignaling reports whether f is a signaling Not-a-Number value; i.e. whether
+// the high part, or the low part if the high part is not NaN, is a signaling
+// NaN.
+func (f Float) IsSignaling() bool {
+	return f.IsNaN() && f.nanBits()&quiet == 0
+}
+
+// Payload returns the payload of the Not-a-Number value f, i.e. the fraction
+// without the quiet bit of the high part, or of the low part if the high part
+// is not NaN; or 0 if f is not NaN.
+func (f Float) Payload() uint64 {
+	if !f.IsNaN() {
+		return 0
+	}
+	return f.nanBits() & 0x0007FFFFFFFFFFFF
+}
+
+// Quiet returns f with the quiet bit set in the parts which are signaling
+// Not-a-Number values. The signs and payloads are preserved.
+func (f Float) Quiet() Float {
+	if math.IsNaN(f.high) {
+		f.high = math.Float64frombits(math.Float64bits(f.high) | quiet)
+	}
+	if math.IsNaN(f.low) {
+		f.low = math.Float64frombits(math.Float64bits(f.low) | quiet)
+	}
+	return f
+}
+
+// nanBits returns the float64 representation of the high part of the
+// Not-a-Number value f, or of the low part if the high part is not NaN.
+func (f Float) nanBits() uint64 {
+	if math.IsNaN(f.high) {
+		return math.Float64bits(f.high)
+	}
+	return math.Float64bits(f.low)
+}
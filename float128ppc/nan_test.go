@@ -0,0 +1,87 @@
+package float128ppc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewNaN(t *testing.T) {
+	golden := []struct {
+		signaling bool
+		payload   uint64
+		want      uint64
+	}{
+		{signaling: false, want: 0x7FF8000000000000},
+		{signaling: false, payload: 0x0005555555555555, want: 0x7FFD555555555555},
+		{signaling: true, payload: 0x0005555555555555, want: 0x7FF5555555555555},
+		// Zero payload of signaling NaN.
+		{signaling: true, want: 0x7FF0000000000001},
+		// Payload truncated to 51 bits.
+		{signaling: false, payload: math.MaxUint64, want: 0x7FFFFFFFFFFFFFFF},
+		{signaling: true, payload: 0x0008000000000000, want: 0x7FF0000000000001},
+	}
+	for _, g := range golden {
+		f := NewNaN(g.signaling, g.payload)
+		if a, b := f.Bits(); g.want != a || b != 0 {
+			t.Errorf("NewNaN(%v, 0x%016X): bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.signaling, g.payload, g.want, uint64(0), a, b)
+			continue
+		}
+		if f.IsSignaling() != g.signaling {
+			t.Errorf("0x%016X: signaling mismatch; expected %v, got %v", g.want, g.signaling, f.IsSignaling())
+		}
+		if want := g.want & 0x0007FFFFFFFFFFFF; want != f.Payload() {
+			t.Errorf("0x%016X: payload mismatch; expected 0x%016X, got 0x%016X", g.want, want, f.Payload())
+		}
+		if q := f.Quiet(); q.IsSignaling() {
+			t.Errorf("0x%016X: quiet mismatch; got signaling NaN", g.want)
+		} else if a, _ := q.Bits(); a != g.want|0x0008000000000000 {
+			t.Errorf("0x%016X: quiet mismatch; expected 0x%016X, got 0x%016X", g.want, g.want|0x0008000000000000, a)
+		}
+	}
+	// The low part is NaN.
+	f := NewFromBits(0x3FF0000000000000, 0xFFF0000000000002)
+	if !f.IsSignaling() || f.Payload() != 2 {
+		t.Errorf("low part NaN: mismatch; got %v with payload 0x%016X", f.IsSignaling(), f.Payload())
+	}
+	if _, b := f.Quiet().Bits(); b != 0xFFF8000000000002 {
+		t.Errorf("low part NaN: quiet mismatch; expected 0x%016X, got 0x%016X", uint64(0xFFF8000000000002), b)
+	}
+	// Numbers other than NaN.
+	for _, f := range []Float{Inf, NegInf, Zero, NegZero, {high: 1}} {
+		if f.IsSignaling() || f.Payload() != 0 || f.Quiet() != f {
+			t.Errorf("%v: unexpected NaN", f)
+		}
+	}
+}
+
+func TestNaNConversion(t *testing.T) {
+	// Signaling NaN is kept, as on PowerPC.
+	golden32 := []struct {
+		in   uint32
+		want uint64
+	}{
+		{in: 0x7FC00000, want: 0x7FF8000000000000},
+		{in: 0xFFD55555, want: 0xFFFAAAAAA0000000},
+		{in: 0x7F800001, want: 0x7FF0000020000000},
+	}
+	for _, g := range golden32 {
+		f, _ := NewFromFloat32(math.Float32frombits(g.in))
+		if a, b := f.Bits(); g.want != a || b != 0 {
+			t.Errorf("0x%08X: float32 conversion mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.in, g.want, uint64(0), a, b)
+		}
+		// Round-trip, with the quiet bit set.
+		if x, _ := f.Float32(); math.Float32bits(x) != g.in|0x00400000 {
+			t.Errorf("0x%08X: round-trip mismatch; got 0x%08X", g.in, math.Float32bits(x))
+		}
+	}
+	for _, in := range []uint64{0x7FF8000000000001, 0xFFFAAAAAAAAAAAAA, 0x7FF0000000000001} {
+		f, _ := NewFromFloat64(math.Float64frombits(in))
+		if a, b := f.Bits(); in != a || b != 0 {
+			t.Errorf("0x%016X: float64 conversion mismatch; got 0x%016X%016X", in, a, b)
+		}
+		// Round-trip, with the quiet bit set.
+		if x, _ := f.Float64(); math.Float64bits(x) != in|0x0008000000000000 {
+			t.Errorf("0x%016X: round-trip mismatch; got 0x%016X", in, math.Float64bits(x))
+		}
+	}
+}
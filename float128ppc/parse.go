@@ -0,0 +1,43 @@
+package float128ppc
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// parsePrec specifies the number of bits to which parsed values are rounded
+// before conversion. As the low part of a double-double may be as small as the
+// smallest denormalized float64 and the high part as large as the largest
+// float64, this is enough for both parts to be rounded correctly.
+const parsePrec = 1024 + 1074 + 55
+
+// Parse returns the double-double floating-point number nearest to the value of
+// s and the accuracy of the conversion. The high part is the float64 nearest to
+// the value of s and the low part is the float64 nearest to the remainder
+// (round half to even). s may be a decimal or hexadecimal floating-point
+// literal, or "inf", "infinity" or "nan" (case-insensitive), with an optional
+// sign.
+//
+// If s is syntactically invalid, the error wraps strconv.ErrSyntax. If s is
+// too large in magnitude, the result is +-Inf and the error wraps
+// strconv.ErrRange.
+func Parse(s string) (Float, big.Accuracy, error) {
+	x, nan, err := strconv.ParseBig(s, parsePrec)
+	if err != nil {
+		return Float{}, big.Exact, fmt.Errorf("float128ppc.Parse: parsing %q: %w", s, err)
+	}
+	if nan {
+		if x.Signbit() {
+			return NegNaN, big.Exact, nil
+		}
+		return NaN, big.Exact, nil
+	}
+	f, acc := NewFromBig(x)
+	if math.IsInf(f.high, 0) && !x.IsInf() {
+		return f, acc, fmt.Errorf("float128ppc.Parse: parsing %q: %w", s, strconv.ErrRange)
+	}
+	return f, acc, nil
+}
@@ -0,0 +1,67 @@
+package float128ppc
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	golden := []struct {
+		in   string
+		want [2]uint64
+		acc  big.Accuracy
+		err  error
+	}{
+		// Special numbers.
+		{in: "0", want: [2]uint64{0x0000000000000000, 0x0000000000000000}, acc: big.Exact},
+		{in: "-0", want: [2]uint64{0x8000000000000000, 0x0000000000000000}, acc: big.Exact},
+		{in: "infinity", want: [2]uint64{0x7FF0000000000000, 0x0000000000000000}, acc: big.Exact},
+		{in: "-inf", want: [2]uint64{0xFFF0000000000000, 0x0000000000000000}, acc: big.Exact},
+
+		{in: "1", want: [2]uint64{0x3FF0000000000000, 0x0000000000000000}, acc: big.Exact},
+		// 1 + 2^(-200)
+		{in: "0x1.00000000000000000000000000000000000000000000000001p0", want: [2]uint64{0x3FF0000000000000, 0x3370000000000000}, acc: big.Exact},
+		{in: "0.1", want: [2]uint64{0x3FB999999999999A, 0xBC5999999999999A}, acc: big.Below},
+		{in: "-0.1", want: [2]uint64{0xBFB999999999999A, 0x3C5999999999999A}, acc: big.Above},
+		{in: "3.141592653589793238462643383279502884197", want: [2]uint64{0x400921FB54442D18, 0x3CA1A62633145C07}, acc: big.Above},
+		{in: "1.7976931348623157e308", want: [2]uint64{0x7FEFFFFFFFFFFFFF, 0xFC54E53663A912B6}, acc: big.Above},
+		{in: "1e309", want: [2]uint64{0x7FF0000000000000, 0x0000000000000000}, acc: big.Above, err: strconv.ErrRange},
+		{in: "-1e309", want: [2]uint64{0xFFF0000000000000, 0x0000000000000000}, acc: big.Below, err: strconv.ErrRange},
+		// Denormalized.
+		{in: "1e-310", want: [2]uint64{0x000012688B70E62B, 0x0000000000000000}, acc: big.Below},
+		{in: "1e-320", want: [2]uint64{0x00000000000007E8, 0x0000000000000000}, acc: big.Below},
+
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "+", err: strconv.ErrSyntax},
+		{in: "1.5e", err: strconv.ErrSyntax},
+		{in: "0x1p", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		a, b := got.Bits()
+		if g.want != [2]uint64{a, b} {
+			t.Errorf("%q: bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.in, g.want[0], g.want[1], a, b)
+		}
+		if g.acc != acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+
+	// NaN.
+	for _, s := range []string{"nan", "-NaN"} {
+		got, acc, err := Parse(s)
+		if err != nil || acc != big.Exact || !got.IsNaN() {
+			t.Errorf("%q: expected NaN, got %v (%v, %v)", s, got, acc, err)
+		}
+	}
+}
@@ -0,0 +1,608 @@
+package float80x86
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+	"github.com/mewmew/float/internal/wideint"
+)
+
+const (
+	// emin specifies the exponent of the smallest normalized number.
+	emin = 1 - bias
+	// emax specifies the exponent of the largest normalized number.
+	emax = bias
+	// quiet specifies the quiet bit of Not-a-Number values.
+	quiet = 0x4000000000000000
+)
+
+// Indefinite is the QNaN floating-point indefinite, returned by the x87 FPU for
+// invalid operations.
+var Indefinite = Float{se: 0xFFFF, m: 0xC000000000000000}
+
+// Precision specifies the precision control setting of the x87 FPU, which
+// determines the number of bits in the significand of rounded results of
+// arithmetic operations. The exponent range of results is not affected.
+type Precision uint8
+
+// Precision control settings.
+const (
+	// 64-bit significand (PC field 11 of the x87 control word).
+	PrecisionExtended Precision = iota
+	// 53-bit significand (PC field 10 of the x87 control word).
+	PrecisionDouble
+	// 24-bit significand (PC field 00 of the x87 control word).
+	PrecisionSingle
+)
+
+// bits returns the number of bits in the significand of rounded results.
+func (p Precision) bits() int {
+	switch p {
+	case PrecisionExtended:
+		return 64
+	case PrecisionDouble:
+		return 53
+	case PrecisionSingle:
+		return 24
+	}
+	panic("float80x86: invalid precision control setting")
+}
+
+// Context specifies the x87 FPU control settings used by arithmetic operations,
+// and accumulates the exception flags raised by the arithmetic operations and
+// conversions performed in the context, as recorded in the x87 FPU status word
+// with all exceptions masked. The zero value specifies 64-bit significands, as
+// set by FINIT, and detects tininess after rounding, as the x87 FPU does.
+type Context struct {
+	// Precision control.
+	Precision Precision
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// Add returns the sum f+g, rounded to nearest even, and the accuracy of the
+// result.
+func (f Float) Add(g Float) (Float, big.Accuracy) {
+	return new(Context).Add(f, g)
+}
+
+// Sub returns the difference f-g, rounded to nearest even, and the accuracy of
+// the result.
+func (f Float) Sub(g Float) (Float, big.Accuracy) {
+	return new(Context).Sub(f, g)
+}
+
+// Mul returns the product f*g, rounded to nearest even, and the accuracy of the
+// result.
+func (f Float) Mul(g Float) (Float, big.Accuracy) {
+	return new(Context).Mul(f, g)
+}
+
+// Div returns the quotient f/g, rounded to nearest even, and the accuracy of
+// the result.
+func (f Float) Div(g Float) (Float, big.Accuracy) {
+	return new(Context).Div(f, g)
+}
+
+// Sqrt returns the square root of f, rounded to nearest even, and the accuracy
+// of the result.
+func (f Float) Sqrt() (Float, big.Accuracy) {
+	return new(Context).Sqrt(f)
+}
+
+// Add returns the sum x+y (FADD), rounded to nearest even with the precision of
+// ctx, and the accuracy of the result. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) Add(x, y Float) (Float, big.Accuracy) {
+	if z, ok := ctx.invalid(x, y); ok {
+		return z, big.Exact
+	}
+	return ctx.add(x, y)
+}
+
+// Sub returns the difference x-y (FSUB), rounded to nearest even with the
+// precision of ctx, and the accuracy of the result. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) Sub(x, y Float) (Float, big.Accuracy) {
+	if z, ok := ctx.invalid(x, y); ok {
+		return z, big.Exact
+	}
+	return ctx.add(x, y.neg())
+}
+
+// Mul returns the product x*y (FMUL), rounded to nearest even with the
+// precision of ctx, and the accuracy of the result. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) Mul(x, y Float) (Float, big.Accuracy) {
+	if z, ok := ctx.invalid(x, y); ok {
+		return z, big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf() || y.isInf():
+		// Inf*0 is invalid.
+		if x.isZero() || y.isZero() {
+			return ctx.indefinite(), big.Exact
+		}
+		return inf(sign), big.Exact
+	}
+	a, b := x.unpack(), y.unpack()
+	hi, lo := bits.Mul64(a.mant.Lo, b.mant.Lo)
+	return ctx.round(unpacked{sign: sign, mant: wideint.Uint128{Hi: hi, Lo: lo}, exp: a.exp + b.exp})
+}
+
+// Div returns the quotient x/y (FDIV), rounded to nearest even with the
+// precision of ctx, and the accuracy of the result. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) Div(x, y Float) (Float, big.Accuracy) {
+	if z, ok := ctx.invalid(x, y); ok {
+		return z, big.Exact
+	}
+	sign := x.Signbit() != y.Signbit()
+	switch {
+	case x.isInf():
+		// Inf/Inf is invalid.
+		if y.isInf() {
+			return ctx.indefinite(), big.Exact
+		}
+		return inf(sign), big.Exact
+	case y.isInf():
+		return zero(sign), big.Exact
+	case y.isZero():
+		// 0/0 is invalid.
+		if x.isZero() {
+			return ctx.indefinite(), big.Exact
+		}
+		// Division by zero.
+		ctx.Flags |= fenv.DivByZero
+		return inf(sign), big.Exact
+	case x.isZero():
+		return zero(sign), big.Exact
+	}
+	a, b := x.unpack().normalize(), y.unpack().normalize()
+	// Make the dividend larger than the divisor, so that each step of the long
+	// division produces a quotient bit.
+	exp := a.exp - b.exp
+	rem := a.mant
+	if rem.Cmp(b.mant) < 0 {
+		rem = rem.Lsh(1)
+		exp--
+	}
+	// Compute 64+2 quotient bits using long division, and jam the remainder
+	// into a sticky bit.
+	var q wideint.Uint128
+	for i := 0; i < precision+2; i++ {
+		q = q.Lsh(1)
+		if rem.Cmp(b.mant) >= 0 {
+			rem = rem.Sub(b.mant)
+			q.Lo |= 1
+		}
+		rem = rem.Lsh(1)
+	}
+	if !rem.IsZero() {
+		q.Lo |= 1
+	}
+	return ctx.round(unpacked{sign: sign, mant: q, exp: exp - (precision + 1)})
+}
+
+// Sqrt returns the square root of x (FSQRT), rounded to nearest even with the
+// precision of ctx, and the accuracy of the result. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) Sqrt(x Float) (Float, big.Accuracy) {
+	if z, ok := ctx.invalid(x); ok {
+		return z, big.Exact
+	}
+	switch {
+	case x.isZero():
+		// sqrt(+-0) = +-0
+		return x, big.Exact
+	case x.Signbit():
+		// Square root of negative number is invalid.
+		return ctx.indefinite(), big.Exact
+	case x.isInf():
+		return Inf, big.Exact
+	}
+	a := x.unpack().normalize()
+	// Make the exponent even, so that the radicand holds 2*33 bits.
+	if a.exp&1 != 0 {
+		a.mant = a.mant.Lsh(1)
+		a.exp--
+	}
+	// Compute 64+3 root bits using the digit-by-digit method, consuming two bits
+	// of the radicand (followed by zeros) per step, and jam the remainder into a
+	// sticky bit.
+	var root, rem wideint.Uint128
+	for i := 0; i < precision+3; i++ {
+		rem = rem.Lsh(2)
+		if i < 33 {
+			rem.Lo |= a.mant.Rsh(uint(2*(32-i))).Lo & 3
+		}
+		trial := root.Lsh(2)
+		trial.Lo |= 1
+		root = root.Lsh(1)
+		if rem.Cmp(trial) >= 0 {
+			rem = rem.Sub(trial)
+			root.Lo |= 1
+		}
+	}
+	if !rem.IsZero() {
+		root.Lo |= 1
+	}
+	// The radicand 2^exp * mant is scaled by 2^(2*(64+3-33)).
+	return ctx.round(unpacked{mant: root, exp: a.exp/2 - (precision + 3 - 33)})
+}
+
+// Rem returns the IEEE 754 remainder f-n*g (FPREM1), where n is the integer
+// nearest the exact value of f/g (ties to even). The result is always exact.
+func (f Float) Rem(g Float) Float {
+	return new(Context).Rem(f, g)
+}
+
+// Rem returns the IEEE 754 remainder x-n*y (FPREM1), where n is the integer
+// nearest the exact value of x/y (ties to even). The result is always exact,
+// regardless of the precision of ctx. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) Rem(x, y Float) Float {
+	if z, ok := ctx.invalid(x, y); ok {
+		return z
+	}
+	switch {
+	case x.isInf() || y.isZero():
+		// rem(Inf, y) and rem(x, 0) are invalid.
+		return ctx.indefinite()
+	case x.isZero():
+		return x
+	}
+	// Pseudo-denormals are normalized by the round trip through unpacked form.
+	a := x.unpack().normalize()
+	if y.isInf() {
+		z, _, _ := roundMode(a, precision, big.ToNearestEven, ctx.Tininess)
+		return z
+	}
+	b := y.unpack().normalize()
+	d := a.exp - b.exp
+	if d < -1 {
+		// |x| < |y|/2
+		z, _, _ := roundMode(a, precision, big.ToNearestEven, ctx.Tininess)
+		return z
+	}
+	// Compute the remainder r = a.mant*2^d mod b.mant (in units of 2^b.exp) and
+	// the least significant bit q of the quotient using long division.
+	r := a.mant
+	var q uint64
+	if d == -1 {
+		// Work in units of 2^(b.exp-1).
+		b.mant = b.mant.Lsh(1)
+		b.exp--
+		d = 0
+	}
+	for i := d; i >= 0; i-- {
+		q = 0
+		if r.Cmp(b.mant) >= 0 {
+			r = r.Sub(b.mant)
+			q = 1
+		}
+		if i > 0 {
+			r = r.Lsh(1)
+		}
+	}
+	// Round the quotient to nearest even; if the quotient is rounded up, the
+	// remainder is r-b.mant.
+	sign := a.sign
+	if c := r.Lsh(1).Cmp(b.mant); c > 0 || (c == 0 && q == 1) {
+		r = b.mant.Sub(r)
+		sign = !sign
+	}
+	z, _, _ := roundMode(unpacked{sign: sign, mant: r, exp: b.exp}, precision, big.ToNearestEven, ctx.Tininess)
+	if z.isZero() {
+		return zero(a.sign)
+	}
+	return z
+}
+
+// add returns the sum x+y of the valid non-NaN operands x and y, rounded to
+// nearest even with the precision of ctx, and the accuracy of the result.
+func (ctx *Context) add(x, y Float) (Float, big.Accuracy) {
+	switch {
+	case x.isInf():
+		// Inf-Inf is invalid.
+		if y.isInf() && y.Signbit() != x.Signbit() {
+			return ctx.indefinite(), big.Exact
+		}
+		return x, big.Exact
+	case y.isInf():
+		return y, big.Exact
+	}
+	a, b := x.unpack(), y.unpack()
+	switch {
+	case a.mant.IsZero() && b.mant.IsZero():
+		// +0 + -0 = +0, in round to nearest.
+		return zero(a.sign && b.sign), big.Exact
+	case a.mant.IsZero():
+		return ctx.round(b)
+	case b.mant.IsZero():
+		return ctx.round(a)
+	}
+	// Let a be the operand of larger magnitude.
+	if a.exp+a.mant.BitLen() < b.exp+b.mant.BitLen() {
+		a, b = b, a
+	}
+	// Align the operands.
+	if d := a.exp - b.exp; d > 0 {
+		// Shift a left as far as possible, and jam the bits of b shifted out on
+		// the right into a sticky bit.
+		s := 126 - a.mant.BitLen()
+		if s > d {
+			s = d
+		}
+		a.mant = a.mant.Lsh(uint(s))
+		a.exp -= s
+		b.mant = b.mant.Jam(uint(d - s))
+	} else {
+		b.mant = b.mant.Lsh(uint(-d))
+	}
+	switch c := a.mant.Cmp(b.mant); {
+	case a.sign == b.sign:
+		return ctx.round(unpacked{sign: a.sign, mant: a.mant.Add(b.mant), exp: a.exp})
+	case c > 0:
+		return ctx.round(unpacked{sign: a.sign, mant: a.mant.Sub(b.mant), exp: a.exp})
+	case c < 0:
+		return ctx.round(unpacked{sign: b.sign, mant: b.mant.Sub(a.mant), exp: a.exp})
+	}
+	// x + -x = +0, in round to nearest.
+	return Zero, big.Exact
+}
+
+// ### [ Helper functions ] ####################################################
+
+// unpacked is a finite floating-point number in unpacked form, representing
+// the value (-1)^sign * mant * 2^exp.
+type unpacked struct {
+	sign bool
+	mant wideint.Uint128
+	exp  int
+}
+
+// unpack returns the unpacked form of the finite valid floating-point number f.
+func (f Float) unpack() unpacked {
+	sign := f.Signbit()
+	mant := wideint.Uint128{Lo: f.m}
+	exp := f.Exp()
+	if exp == 0 {
+		// Denormalized and pseudo-denormalized number.
+		//
+		//    (-1)^signbit * 2^(-16382) * lead.mant_2
+		return unpacked{sign: sign, mant: mant, exp: emin - (precision - 1)}
+	}
+	// Normalized number.
+	//
+	//    (-1)^signbit * 2^(exp-16383) * 1.mant_2
+	return unpacked{sign: sign, mant: mant, exp: exp - bias - (precision - 1)}
+}
+
+// unpackBig returns the unpacked form of the finite non-zero x, truncated to 126
+// bits with the bits shifted out jammed into the least significant bit.
+func unpackBig(x *big.Float) unpacked {
+	const prec = 126
+	y := new(big.Float).SetMode(big.ToZero).SetPrec(prec).Set(x)
+	mant := new(big.Float)
+	exp := y.MantExp(mant)
+	m, _ := mant.Abs(mant).SetMantExp(mant, prec).Int(nil)
+	lo := new(big.Int).And(m, new(big.Int).SetUint64(math.MaxUint64))
+	u := wideint.Uint128{Hi: m.Rsh(m, 64).Uint64(), Lo: lo.Uint64()}
+	if y.Acc() != big.Exact {
+		u.Lo |= 1
+	}
+	return unpacked{sign: x.Signbit(), mant: u, exp: exp - prec}
+}
+
+// normalize returns x with the most significant bit of the non-zero mantissa
+// shifted to the position of the explicit lead bit.
+func (x unpacked) normalize() unpacked {
+	s := precision - x.mant.BitLen()
+	x.mant = x.mant.Lsh(uint(s))
+	x.exp -= s
+	return x
+}
+
+// round returns the nearest x86 extended precision floating-point number to x,
+// with the significand rounded to the precision of ctx using round half to
+// even, and the accuracy of the result. The exception flags raised are
+// recorded in ctx. The least significant bit of x.mant is a sticky bit when x
+// is inexact.
+//
+// As with the x87 FPU, denormalized results are rounded at the same bit
+// position of the significand as normalized results.
+func (ctx *Context) round(x unpacked) (Float, big.Accuracy) {
+	z, acc, flags := roundMode(x, ctx.Precision.bits(), big.ToNearestEven, ctx.Tininess)
+	ctx.Flags |= flags
+	return z, acc
+}
+
+// roundMode returns x rounded to an x86 extended precision floating-point
+// number, with the significand rounded to prec bits in the given rounding
+// mode, the accuracy of the result and the exception flags raised, with
+// tininess detected as specified by tininess. The least significant bit of
+// x.mant is a sticky bit when x is inexact.
+func roundMode(x unpacked, prec int, mode big.RoundingMode, tininess fenv.Tininess) (Float, big.Accuracy, fenv.Flags) {
+	if x.mant.IsZero() {
+		return zero(x.sign), big.Exact, 0
+	}
+	// Exponent of the most significant bit of x.
+	msb := x.exp + x.mant.BitLen() - 1
+	// Exponent of the most significant bit of the significand, and of the least
+	// significant bit of the result.
+	top := msb
+	if top < emin {
+		top = emin
+	}
+	lsb := top - (prec - 1)
+	mant := x.mant
+	acc := big.Exact
+	if s := lsb - x.exp; s > 0 {
+		rem := mant.Sub(mant.Rsh(uint(s)).Lsh(uint(s)))
+		mant = mant.Rsh(uint(s))
+		if !rem.IsZero() {
+			acc = big.Below
+			// half is zero if s > 128, in which case rem < 2^128 <= 2^(s-1).
+			half := wideint.Uint128{Lo: 1}.Lsh(uint(s - 1))
+			c := -1
+			if !half.IsZero() {
+				c = rem.Cmp(half)
+			}
+			if rounding.Up(mode, x.sign, mant.Lo&1 != 0, c) {
+				mant = mant.Add(wideint.Uint128{Lo: 1})
+				acc = big.Above
+			}
+			if x.sign {
+				acc = -acc
+			}
+		}
+	} else {
+		mant = mant.Lsh(uint(-s))
+	}
+	if mant.BitLen() > prec {
+		// Rounding carried into the next power of two; mant is 2^prec.
+		mant = mant.Rsh(1)
+		lsb++
+	}
+	var flags fenv.Flags
+	if acc != big.Exact {
+		flags = fenv.Inexact
+		if msb < emin && !(tininess == fenv.AfterRounding && msb == emin-1 && carries(x, prec, mode)) {
+			flags |= fenv.Underflow
+		}
+	}
+	// Exponent of the most significant bit of the result.
+	exp := lsb + mant.BitLen() - 1
+	if exp > emax {
+		// Overflow to +-Inf, or to the largest finite number of the same sign
+		// and precision in directed rounding modes.
+		flags |= fenv.Overflow | fenv.Inexact
+		if !rounding.Inf(mode, x.sign) {
+			m := uint64(math.MaxUint64) << uint(precision-prec)
+			if x.sign {
+				return Float{se: 0xFFFE, m: m}, big.Above, flags
+			}
+			return Float{se: 0x7FFE, m: m}, big.Below, flags
+		}
+		if x.sign {
+			return NegInf, big.Below, flags
+		}
+		return Inf, big.Above, flags
+	}
+	// Position the result in the 64-bit significand, with the explicit lead bit
+	// set for normalized numbers.
+	var se uint16
+	if exp >= emin {
+		se = uint16(exp + bias)
+		top = exp
+	}
+	m := mant.Lsh(uint(lsb - (top - (precision - 1)))).Lo
+	if x.sign {
+		se |= 0x8000
+	}
+	return Float{se: se, m: m}, acc, flags
+}
+
+// carries reports whether x, with the most significant bit at exponent emin-1,
+// is rounded up to 2^emin in magnitude when the significand is rounded to prec
+// bits in the given rounding mode; i.e. whether x is not tiny when tininess is
+// detected after rounding.
+func carries(x unpacked, prec int, mode big.RoundingMode) bool {
+	// Scale x into the range of normalized numbers.
+	x.exp++
+	z, _, _ := roundMode(x, prec, mode, fenv.AfterRounding)
+	return z.Exp() > 1
+}
+
+// invalid reports whether the operation on the given operands has a NaN result
+// and returns the result. Operands in unsupported formats (pseudo-NaN,
+// pseudo-infinity and unnormal) are invalid, and otherwise NaN operands are
+// propagated following the rules of the x87 FPU; a QNaN takes precedence over
+// an SNaN, and otherwise the NaN with the larger significand is returned as a
+// QNaN. The invalid operation exception is raised for unsupported operands and
+// SNaN operands.
+func (ctx *Context) invalid(fs ...Float) (Float, bool) {
+	var nan Float
+	found := false
+	for _, f := range fs {
+		if f.isNaN() && f.m&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		switch {
+		case f.isUnsupported():
+			return ctx.indefinite(), true
+		case !f.isNaN():
+			continue
+		case !found:
+			nan, found = f, true
+		case (nan.m&quiet != 0) != (f.m&quiet != 0):
+			if f.m&quiet != 0 {
+				nan = f
+			}
+		case f.m > nan.m || (f.m == nan.m && f.se < nan.se):
+			nan = f
+		}
+	}
+	if !found {
+		return Float{}, false
+	}
+	nan.m |= quiet
+	return nan, true
+}
+
+// indefinite raises the invalid operation exception and returns the QNaN
+// floating-point indefinite.
+func (ctx *Context) indefinite() Float {
+	ctx.Flags |= fenv.Invalid
+	return Indefinite
+}
+
+// inf returns +Inf if sign is false and -Inf otherwise.
+func inf(sign bool) Float {
+	if sign {
+		return NegInf
+	}
+	return Inf
+}
+
+// zero returns +0 if sign is false and -0 otherwise.
+func zero(sign bool) Float {
+	if sign {
+		return NegZero
+	}
+	return Zero
+}
+
+// neg returns -f.
+func (f Float) neg() Float {
+	return Float{se: f.se ^ 0x8000, m: f.m}
+}
+
+// isNaN reports whether f is Not-a-Number (with the explicit lead bit set).
+func (f Float) isNaN() bool {
+	return f.Exp() == 0x7FFF && f.Lead() == 1 && f.Frac() != 0
+}
+
+// isInf reports whether f is +Inf or -Inf (with the explicit lead bit set).
+func (f Float) isInf() bool {
+	return f.Exp() == 0x7FFF && f.m == 0x8000000000000000
+}
+
+// isZero reports whether f is +0 or -0.
+func (f Float) isZero() bool {
+	return f.Exp() == 0 && f.m == 0
+}
+
+// isUnsupported reports whether f is in a format not supported as operand by
+// the x87 FPU since the 80387; i.e. pseudo-NaN, pseudo-infinity or unnormal
+// (non-zero exponent with the explicit lead bit clear).
+func (f Float) isUnsupported() bool {
+	return f.Exp() != 0 && f.Lead() == 0
+}
@@ -0,0 +1,229 @@
+package float80x86
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+// Test cases computed using the x87 FPU (FADD, FSUB, FMUL, FDIV, FSQRT and
+// FPREM1), with the precision control set to 64, 53 and 24 bits.
+func TestArith(t *testing.T) {
+	golden := []struct {
+		x, y string
+		// Results of x+y, x-y, x*y, x/y and sqrt(x) for each precision control
+		// setting.
+		want [3][5]string
+		// Result of rem(x, y).
+		rem string
+	}{
+		// normalized x and y
+		{x: "9ACDE98569B68E000000", y: "45C2FC937326126A7648", want: [3][5]string{{"45C2FC937326126A7648", "C5C2FC937326126A7648", "A091E665D4B510B2BB75", "9509ECAFD59F30414C34", "FFFFC000000000000000"}, {"45C2FC937326126A7800", "C5C2FC937326126A7800", "A091E665D4B510B2B800", "9509ECAFD59F30415000", "FFFFC000000000000000"}, {"45C2FC93730000000000", "C5C2FC93730000000000", "A091E665D50000000000", "9509ECAFD60000000000", "FFFFC000000000000000"}}, rem: "9ACDE98569B68E000000"},
+		// x + -x = +0
+		{x: "887480E3A76FE0000000", y: "087480E3A76FE0000000", want: [3][5]string{{"00000000000000000000", "887580E3A76FE0000000", "80000000000000000000", "BFFF8000000000000000", "FFFFC000000000000000"}, {"00000000000000000000", "887580E3A76FE0000000", "80000000000000000000", "BFFF8000000000000000", "FFFFC000000000000000"}, {"00000000000000000000", "887580E3A70000000000", "80000000000000000000", "BFFF8000000000000000", "FFFFC000000000000000"}}, rem: "80000000000000000000"},
+		// pseudo-denormal x
+		{x: "00008B7C4F27AB86CD34", y: "9EC7C8B758E99DED2C59", want: [3][5]string{{"9EC7C8B758E99DED2C59", "1EC7C8B758E99DED2C59", "80000000000000000000", "A138B1E78B535E241E1C", "2000859E93957D432334"}, {"9EC7C8B758E99DED3000", "1EC7C8B758E99DED3000", "80000000000000000000", "A138B1E78B535E242000", "2000859E93957D432000"}, {"9EC7C8B7590000000000", "1EC7C8B7590000000000", "80000000000000000000", "A138B1E78B0000000000", "2000859E940000000000"}}, rem: "00018B7C4F27AB86CD34"},
+		// denormal x
+		{x: "800059245B65F6BC9BCC", y: "BFFCE523EDE55E77D4E4", want: [3][5]string{{"BFFCE523EDE55E77D4E4", "3FFCE523EDE55E77D4E4", "000013F282244EEBEB98", "0002C72EAEBF8F5D6EEF", "FFFFC000000000000000"}, {"BFFCE523EDE55E77D800", "3FFCE523EDE55E77D800", "000013F282244EEBE800", "0002C72EAEBF8F5D7000", "FFFFC000000000000000"}, {"BFFCE523EE0000000000", "3FFCE523EE0000000000", "000013F2820000000000", "0002C72EAF0000000000", "FFFFC000000000000000"}}, rem: "800059245B65F6BC9BCC"},
+		// unnormal x
+		{x: "BC3A530DF282A0E729BB", y: "3B6D99460FF1C0EB979D", want: [3][5]string{{"FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000"}, {"FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000"}, {"FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000", "FFFFC000000000000000"}}, rem: "FFFFC000000000000000"},
+		// QNaN x, SNaN y
+		{x: "7FFFD00B24F6D11F8D7C", y: "FFFFADE492191EC42968", want: [3][5]string{{"7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C"}, {"7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C"}, {"7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C", "7FFFD00B24F6D11F8D7C"}}, rem: "7FFFD00B24F6D11F8D7C"},
+		// denormal y
+		{x: "BFFEDCA6008BB845AD6D", y: "800071718DFAC95AD6BA", want: [3][5]string{{"BFFEDCA6008BB845AD6D", "BFFEDCA6008BB845AD6D", "000061C725E37FF9371C", "7FFCF8F5F618A02F14B4", "FFFFC000000000000000"}, {"BFFEDCA6008BB845B000", "BFFEDCA6008BB845B000", "000061C725E37FF93800", "7FFCF8F5F618A02F1800", "FFFFC000000000000000"}, {"BFFEDCA6010000000000", "BFFEDCA6010000000000", "000061C7260000000000", "7FFCF8F5F60000000000", "FFFFC000000000000000"}}, rem: "000024ABE7671C4F92D4"},
+		// denormal x and y
+		{x: "8000309D986FC8A4A27C", y: "0000309D986FC8A4A27C", want: [3][5]string{{"00000000000000000000", "8000613B30DF914944F8", "80000000000000000000", "BFFF8000000000000000", "FFFFC000000000000000"}, {"00000000000000000000", "8000613B30DF91494800", "80000000000000000000", "BFFF8000000000000000", "FFFFC000000000000000"}, {"00000000000000000000", "8000613B310000000000", "80000000000000000000", "BFFF8000000000000000", "FFFFC000000000000000"}}, rem: "80000000000000000000"},
+	}
+	ctxs := []Context{{Precision: PrecisionExtended}, {Precision: PrecisionDouble}, {Precision: PrecisionSingle}}
+	ops := []string{"+", "-", "*", "/", "sqrt"}
+	for _, g := range golden {
+		x, y := fromHex(g.x), fromHex(g.y)
+		for i, ctx := range ctxs {
+			var got [5]Float
+			got[0], _ = ctx.Add(x, y)
+			got[1], _ = ctx.Sub(x, y)
+			got[2], _ = ctx.Mul(x, y)
+			got[3], _ = ctx.Div(x, y)
+			got[4], _ = ctx.Sqrt(x)
+			for j, op := range ops {
+				if want := fromHex(g.want[i][j]); want != got[j] {
+					t.Errorf("%s(0x%s, 0x%s) with precision %d: bits mismatch; expected 0x%s, got 0x%04X%016X", op, g.x, g.y, ctx.Precision.bits(), g.want[i][j], got[j].se, got[j].m)
+				}
+			}
+		}
+		if got, want := x.Rem(y), fromHex(g.rem); want != got {
+			t.Errorf("rem(0x%s, 0x%s): bits mismatch; expected 0x%s, got 0x%04X%016X", g.x, g.y, g.rem, got.se, got.m)
+		}
+	}
+}
+
+func TestArithSpecial(t *testing.T) {
+	one := NewFromBits(0x3FFF, 0x8000000000000000)
+	golden := []struct {
+		op   string
+		got  Float
+		want Float
+	}{
+		{op: "Inf-Inf", got: first(Inf.Sub(Inf)), want: Indefinite},
+		{op: "Inf*0", got: first(Inf.Mul(NegZero)), want: Indefinite},
+		{op: "0/0", got: first(Zero.Div(Zero)), want: Indefinite},
+		{op: "1/-0", got: first(one.Div(NegZero)), want: NegInf},
+		{op: "sqrt(-1)", got: first(one.neg().Sqrt()), want: Indefinite},
+		{op: "sqrt(-0)", got: first(NegZero.Sqrt()), want: NegZero},
+		{op: "rem(Inf,1)", got: Inf.Rem(one), want: Indefinite},
+		{op: "rem(1,Inf)", got: one.Rem(Inf), want: one},
+		// Pseudo-infinity and pseudo-NaN operands are invalid.
+		{op: "pseudo-Inf+1", got: first(NewFromBits(0x7FFF, 0).Add(one)), want: Indefinite},
+		{op: "pseudo-NaN+1", got: first(NewFromBits(0x7FFF, 0x0000000000000001).Add(one)), want: Indefinite},
+		// Pseudo-denormals are valid, and the result is normalized.
+		{op: "pseudo-denormal+0", got: first(NewFromBits(0x0000, 0x8000000000000000).Add(Zero)), want: NewFromBits(0x0001, 0x8000000000000000)},
+		// Signaling NaN is quieted, and its payload is preserved.
+		{op: "sNaN+1", got: first(NewFromBits(0xFFFF, 0x8000000000000001).Add(one)), want: NewFromBits(0xFFFF, 0xC000000000000001)},
+	}
+	for _, g := range golden {
+		if g.want != g.got {
+			t.Errorf("%s: bits mismatch; expected 0x%04X%016X, got 0x%04X%016X", g.op, g.want.se, g.want.m, g.got.se, g.got.m)
+		}
+	}
+}
+
+func TestArithCarry(t *testing.T) {
+	// Results whose significand is rounded up to the next power of two.
+	golden := []struct {
+		op        string
+		precision Precision
+		x, y      string
+		want      string
+		acc       big.Accuracy
+	}{
+		// (2 - 2^(-63)) + 2^(-64) = 2 (tie, round to even)
+		{op: "+", precision: PrecisionExtended, x: "3FFFFFFFFFFFFFFFFFFF", y: "3FBF8000000000000000", want: "40008000000000000000", acc: big.Above},
+		{op: "-", precision: PrecisionExtended, x: "BFFFFFFFFFFFFFFFFFFF", y: "3FBF8000000000000000", want: "C0008000000000000000", acc: big.Below},
+		// (2 - 2^(-52)) + 2^(-53) = 2
+		{op: "+", precision: PrecisionDouble, x: "3FFFFFFFFFFFFFFFF800", y: "3FCA8000000000000000", want: "40008000000000000000", acc: big.Above},
+		// (2 - 2^(-23)) + 2^(-24) = 2
+		{op: "+", precision: PrecisionSingle, x: "3FFFFFFFFF0000000000", y: "3FE78000000000000000", want: "40008000000000000000", acc: big.Above},
+		// 1 - 2^(-16382) = 1
+		{op: "-", precision: PrecisionExtended, x: "3FFF8000000000000000", y: "00018000000000000000", want: "3FFF8000000000000000", acc: big.Above},
+		{op: "-", precision: PrecisionDouble, x: "3FFF8000000000000000", y: "00018000000000000000", want: "3FFF8000000000000000", acc: big.Above},
+		{op: "-", precision: PrecisionSingle, x: "3FFF8000000000000000", y: "00018000000000000000", want: "3FFF8000000000000000", acc: big.Above},
+		// (2 - 2^(-63)) * (1 + 2^(-63)) = 2 + 2^(-63) - 2^(-126), which is
+		// rounded to 2 with 64-bit significands.
+		{op: "*", precision: PrecisionExtended, x: "3FFFFFFFFFFFFFFFFFFF", y: "3FFF8000000000000001", want: "40008000000000000000", acc: big.Below},
+	}
+	for _, g := range golden {
+		ctx := &Context{Precision: g.precision}
+		x, y := fromHex(g.x), fromHex(g.y)
+		var got Float
+		var acc big.Accuracy
+		switch g.op {
+		case "+":
+			got, acc = ctx.Add(x, y)
+		case "-":
+			got, acc = ctx.Sub(x, y)
+		case "*":
+			got, acc = ctx.Mul(x, y)
+		}
+		if want := fromHex(g.want); want != got || g.acc != acc {
+			t.Errorf("0x%s %s 0x%s with precision %d: mismatch; expected 0x%s (%v), got 0x%04X%016X (%v)", g.x, g.op, g.y, ctx.Precision.bits(), g.want, g.acc, got.se, got.m, acc)
+		}
+	}
+}
+
+func TestArithFlags(t *testing.T) {
+	golden := []struct {
+		op        string
+		precision Precision
+		x, y      string
+		want      string
+		// Exception flags raised with tininess detected after and before
+		// rounding.
+		after, before fenv.Flags
+	}{
+		// 1 + 2^(-63)
+		{op: "+", precision: PrecisionExtended, x: "3FFF8000000000000000", y: "3FC08000000000000000", want: "3FFF8000000000000001"},
+		{op: "+", precision: PrecisionDouble, x: "3FFF8000000000000000", y: "3FC08000000000000000", want: "3FFF8000000000000000", after: fenv.Inexact, before: fenv.Inexact},
+		// max + max = +Inf
+		{op: "+", precision: PrecisionExtended, x: "7FFEFFFFFFFFFFFFFFFF", y: "7FFEFFFFFFFFFFFFFFFF", want: "7FFF8000000000000000", after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// 2^(-16445) * 0.5 = +0 (tie, round to even)
+		{op: "*", precision: PrecisionExtended, x: "00000000000000000001", y: "3FFE8000000000000000", want: "00000000000000000000", after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// (2^(-16382) - 2^(-16445)) * (1 + 2^(-63)) = 2^(-16382) - 2^(-16508),
+		// which is rounded to 2^(-16382) both with and without bounded exponent
+		// range.
+		{op: "*", precision: PrecisionExtended, x: "00007FFFFFFFFFFFFFFF", y: "3FFF8000000000000001", want: "00018000000000000000", after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// (2^(-16382) - 2^(-16445)) * 1, exact with 64-bit significands and
+		// rounded to 2^(-16382) with 24-bit significands.
+		{op: "*", precision: PrecisionExtended, x: "00007FFFFFFFFFFFFFFF", y: "3FFF8000000000000000", want: "00007FFFFFFFFFFFFFFF"},
+		{op: "*", precision: PrecisionSingle, x: "00007FFFFFFFFFFFFFFF", y: "3FFF8000000000000000", want: "00018000000000000000", after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 1 / -0 = -Inf
+		{op: "/", precision: PrecisionExtended, x: "3FFF8000000000000000", y: "80000000000000000000", want: "FFFF8000000000000000", after: fenv.DivByZero, before: fenv.DivByZero},
+		// 0 / 0 = indefinite
+		{op: "/", precision: PrecisionExtended, x: "00000000000000000000", y: "00000000000000000000", want: "FFFFC000000000000000", after: fenv.Invalid, before: fenv.Invalid},
+		// sqrt(2)
+		{op: "sqrt", precision: PrecisionExtended, x: "40008000000000000000", want: "3FFFB504F333F9DE6484", after: fenv.Inexact, before: fenv.Inexact},
+		// QNaN + SNaN = QNaN
+		{op: "+", precision: PrecisionExtended, x: "7FFFD00B24F6D11F8D7C", y: "FFFFADE492191EC42968", want: "7FFFD00B24F6D11F8D7C", after: fenv.Invalid, before: fenv.Invalid},
+		// QNaN + 1 = QNaN
+		{op: "+", precision: PrecisionExtended, x: "7FFFD00B24F6D11F8D7C", y: "3FFF8000000000000000", want: "7FFFD00B24F6D11F8D7C"},
+		// unnormal + 1 = indefinite
+		{op: "+", precision: PrecisionExtended, x: "3FFF0000000000000000", y: "3FFF8000000000000000", want: "FFFFC000000000000000", after: fenv.Invalid, before: fenv.Invalid},
+		// rem(1, 0) = indefinite
+		{op: "rem", precision: PrecisionExtended, x: "3FFF8000000000000000", y: "00000000000000000000", want: "FFFFC000000000000000", after: fenv.Invalid, before: fenv.Invalid},
+		// rem(2^(-16382) - 2^(-16445), 1) is exact, regardless of precision.
+		{op: "rem", precision: PrecisionSingle, x: "00007FFFFFFFFFFFFFFF", y: "3FFF8000000000000000", want: "00007FFFFFFFFFFFFFFF"},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx := &Context{Precision: g.precision, Tininess: tininess}
+			x := fromHex(g.x)
+			var y Float
+			if len(g.y) > 0 {
+				y = fromHex(g.y)
+			}
+			var got Float
+			switch g.op {
+			case "+":
+				got, _ = ctx.Add(x, y)
+			case "*":
+				got, _ = ctx.Mul(x, y)
+			case "/":
+				got, _ = ctx.Div(x, y)
+			case "sqrt":
+				got, _ = ctx.Sqrt(x)
+			case "rem":
+				got = ctx.Rem(x, y)
+			}
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if fromHex(g.want) != got {
+				t.Errorf("0x%s %s 0x%s with precision %d: bits mismatch; expected 0x%s, got 0x%04X%016X", g.x, g.op, g.y, g.precision.bits(), g.want, got.se, got.m)
+			}
+			if want != ctx.Flags {
+				t.Errorf("0x%s %s 0x%s with precision %d and tininess detected %v: flags mismatch; expected %v, got %v", g.x, g.op, g.y, g.precision.bits(), tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+// fromHex returns the floating-point number with the given 20 digit hexadecimal
+// representation.
+func fromHex(s string) Float {
+	se, err := strconv.ParseUint(s[:4], 16, 16)
+	if err != nil {
+		panic(err)
+	}
+	m, err := strconv.ParseUint(s[4:], 16, 64)
+	if err != nil {
+		panic(err)
+	}
+	return NewFromBits(uint16(se), m)
+}
+
+// first returns the floating-point number of a result and accuracy pair.
+func first(f Float, acc big.Accuracy) Float {
+	return f
+}
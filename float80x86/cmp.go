@@ -0,0 +1,89 @@
+package float80x86
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0. A NaN is considered less than any non-NaN and equal to a NaN,
+// as by cmp.Compare.
+func (f Float) Cmp(g Float) int {
+	x, xnan := f.Big()
+	y, ynan := g.Big()
+	return order.Cmp(x, xnan, y, ynan)
+}
+
+// Less reports whether f < g. It is false if f or g is NaN.
+func (f Float) Less(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is NaN, and true for -0
+// and +0.
+func (f Float) Equal(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder):
+//
+//	-qNaN < -sNaN < -Inf < -x < -0 < +0 < +x < +Inf < +sNaN < +qNaN
+//
+// NaNs of the same kind and sign are ordered by payload, in reverse for
+// negative NaNs. Numbers of the same value but different representations
+// (e.g. pseudo-denormal and unnormal numbers) are ordered as equal.
+func (f Float) TotalOrder(g Float) bool {
+	x, xnan := f.Big()
+	y, ynan := g.Big()
+	cmp := 0
+	switch {
+	case xnan && ynan:
+		cmp = order.Payload(f.nanFrac(), g.nanFrac())
+	case !xnan && !ynan:
+		cmp = x.Cmp(y)
+	}
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), cmp)
+}
+
+// Class returns the class of f. Pseudo-NaN and pseudo-infinity values, which
+// are invalid operands of the x87 FPU since the 80387, are signaling NaNs, and
+// pseudo-denormal and unnormal numbers are classified by value.
+func (f Float) Class() float.Class {
+	x, nan := f.Big()
+	switch {
+	case !nan:
+		return order.Class(x, 1-bias)
+	case f.isNaN() && f.m&quiet != 0:
+		return float.QuietNaN
+	}
+	return float.SignalingNaN
+}
+
+// IsNaN reports whether f is Not-a-Number, including pseudo-NaN and
+// pseudo-infinity values.
+func (f Float) IsNaN() bool {
+	return f.Exp() == 0x7FFF && !f.isInf()
+}
+
+// IsInf reports whether f is +Inf or -Inf.
+func (f Float) IsInf() bool {
+	return f.isInf()
+}
+
+// IsSubnormal reports whether f is a subnormal number; i.e. a non-zero number
+// of magnitude below the smallest normalized number.
+func (f Float) IsSubnormal() bool {
+	return f.Class().IsSubnormal()
+}
+
+// IsFinite reports whether f is neither infinite nor NaN.
+func (f Float) IsFinite() bool {
+	return f.Exp() != 0x7FFF
+}
+
+// IsZero reports whether f is +0 or -0, including pseudo-zero values with a
+// non-zero exponent.
+func (f Float) IsZero() bool {
+	return f.Exp() != 0x7FFF && f.m == 0
+}
@@ -0,0 +1,111 @@
+package float80x86
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClass(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		se    uint16
+		m     uint64
+		class float.Class
+	}{
+		{se: 0xFFFF, m: 0xFFFFFFFFFFFFFFFF, class: float.QuietNaN},
+		{se: 0xFFFF, m: 0xC000000000000000, class: float.QuietNaN},
+		{se: 0xFFFF, m: 0xBFFFFFFFFFFFFFFF, class: float.SignalingNaN},
+		{se: 0xFFFF, m: 0x8000000000000001, class: float.SignalingNaN},
+		{se: 0xFFFF, m: 0x8000000000000000, class: float.NegativeInfinity},
+		{se: 0xFFFE, m: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{se: 0xBFFF, m: 0x8000000000000000, class: float.NegativeNormal},
+		{se: 0x8001, m: 0x8000000000000000, class: float.NegativeNormal},
+		{se: 0x8000, m: 0x7FFFFFFFFFFFFFFF, class: float.NegativeSubnormal},
+		{se: 0x8000, m: 0x0000000000000001, class: float.NegativeSubnormal},
+		{se: 0x8000, m: 0x0000000000000000, class: float.NegativeZero},
+		{se: 0x0000, m: 0x0000000000000000, class: float.PositiveZero},
+		{se: 0x0000, m: 0x0000000000000001, class: float.PositiveSubnormal},
+		{se: 0x0000, m: 0x7FFFFFFFFFFFFFFF, class: float.PositiveSubnormal},
+		{se: 0x0001, m: 0x8000000000000000, class: float.PositiveNormal},
+		{se: 0x3FFF, m: 0x8000000000000000, class: float.PositiveNormal},
+		{se: 0x7FFE, m: 0xFFFFFFFFFFFFFFFF, class: float.PositiveNormal},
+		{se: 0x7FFF, m: 0x8000000000000000, class: float.PositiveInfinity},
+		{se: 0x7FFF, m: 0x8000000000000001, class: float.SignalingNaN},
+		{se: 0x7FFF, m: 0xBFFFFFFFFFFFFFFF, class: float.SignalingNaN},
+		{se: 0x7FFF, m: 0xC000000000000000, class: float.QuietNaN},
+		{se: 0x7FFF, m: 0xFFFFFFFFFFFFFFFF, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewFromBits(a.se, a.m)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%04X 0x%016X: class mismatch; expected %v, got %v", a.se, a.m, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%04X 0x%016X: predicate mismatch for class %v", a.se, a.m, a.class)
+		}
+	}
+}
+
+func TestCmpNonCanonical(t *testing.T) {
+	golden := []struct {
+		f, g  Float
+		class float.Class
+	}{
+		// Pseudo-denormal; 2^-16382.
+		{f: NewFromBits(0x0000, 0x8000000000000000), g: NewFromBits(0x0001, 0x8000000000000000), class: float.PositiveNormal},
+		// Unnormal; 0.5.
+		{f: NewFromBits(0x3FFF, 0x4000000000000000), g: NewFromBits(0x3FFE, 0x8000000000000000), class: float.PositiveNormal},
+		// Unnormal; 2^-16384.
+		{f: NewFromBits(0x0001, 0x2000000000000000), g: NewFromBits(0x0000, 0x2000000000000000), class: float.PositiveSubnormal},
+		// Pseudo-zero.
+		{f: NewFromBits(0xBFFF, 0x0000000000000000), g: NegZero, class: float.NegativeZero},
+	}
+	for _, g := range golden {
+		if got := g.f.Class(); g.class != got {
+			t.Errorf("%v: class mismatch; expected %v, got %v", g.f, g.class, got)
+		}
+		if !g.f.Equal(g.g) || !g.f.TotalOrder(g.g) || !g.g.TotalOrder(g.f) {
+			t.Errorf("%v: expected equal to %v", g.f, g.g)
+		}
+	}
+	// Pseudo-NaN and pseudo-infinity.
+	for _, f := range []Float{NewFromBits(0x7FFF, 0x4000000000000000), NewFromBits(0xFFFF, 0x0000000000000000)} {
+		if got := f.Class(); got != float.SignalingNaN {
+			t.Errorf("%v: class mismatch; expected %v, got %v", f, float.SignalingNaN, got)
+		}
+		if !f.IsNaN() || f.Equal(f) || f.Cmp(NegInf) >= 0 {
+			t.Errorf("%v: expected NaN", f)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding.
+	golden := []struct {
+		xse   uint16
+		xm    uint64
+		yse   uint16
+		ym    uint64
+		cmp   int
+		total bool
+	}{
+		// Largest subnormal and smallest normal number.
+		{xse: 0x0000, xm: 0x7FFFFFFFFFFFFFFF, yse: 0x0001, ym: 0x8000000000000000, cmp: -1, total: true},
+		{xse: 0x8001, xm: 0x8000000000000000, yse: 0x8000, ym: 0x7FFFFFFFFFFFFFFF, cmp: -1, total: true},
+		// Positive and negative zero.
+		{xse: 0x0000, xm: 0x0000000000000000, yse: 0x8000, ym: 0x0000000000000000, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{xse: 0x7FFF, xm: 0xBFFFFFFFFFFFFFFF, yse: 0x7FFF, ym: 0xC000000000000000, cmp: 0, total: true},
+		{xse: 0xFFFF, xm: 0xBFFFFFFFFFFFFFFF, yse: 0xFFFF, ym: 0xC000000000000000, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.xse, g.xm), NewFromBits(g.yse, g.ym)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%04X 0x%016X.Cmp(0x%04X 0x%016X): mismatch; expected %d, got %d", g.xse, g.xm, g.yse, g.ym, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%04X 0x%016X.TotalOrder(0x%04X 0x%016X): mismatch; expected %v, got %v", g.xse, g.xm, g.yse, g.ym, g.total, got)
+		}
+	}
+}
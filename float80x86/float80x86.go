@@ -10,6 +10,10 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/rounding"
 )
 
 const (
@@ -57,9 +61,59 @@ func NewFromBits(se uint16, m uint64) Float {
 }
 
 // NewFromFloat32 returns the nearest x86 extended precision floating-point
-// number for x and the accuracy of the conversion.
+// number for x and the accuracy of the conversion. Not-a-Number values are
+// converted to a quiet NaN with the same sign and payload, as by the x87 FPU.
 func NewFromFloat32(x float32) (Float, big.Accuracy) {
-	f, acc := NewFromFloat64(float64(x))
+	return new(Context).NewFromFloat32(x)
+}
+
+// NewFromFloat64 returns the nearest x86 extended precision floating-point
+// number for x and the accuracy of the conversion. Not-a-Number values are
+// converted to a quiet NaN with the same sign and payload, as by the x87 FPU.
+func NewFromFloat64(x float64) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat64(x)
+}
+
+// NewFromBig returns the nearest x86 extended precision floating-point number
+// for x and the accuracy of the conversion.
+func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromFloat32Mode returns the x86 extended precision floating-point number for
+// x rounded in the given rounding mode, and the accuracy of the conversion.
+// Every float32 is exactly representable, so the result is independent of the
+// rounding mode.
+func NewFromFloat32Mode(x float32, mode big.RoundingMode) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat32Mode(x, mode)
+}
+
+// NewFromFloat64Mode returns the x86 extended precision floating-point number for
+// x rounded in the given rounding mode, and the accuracy of the conversion.
+// Every float64 is exactly representable, so the result is independent of the
+// rounding mode.
+func NewFromFloat64Mode(x float64, mode big.RoundingMode) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat64Mode(x, mode)
+}
+
+// NewFromBigMode returns the x86 extended precision floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion.
+// Values too large in magnitude are converted to +-Inf, or to the largest
+// finite number of the same sign if the mode rounds them towards zero.
+func NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
+	return new(Context).NewFromBigMode(x, mode)
+}
+
+// NewFromFloat32 returns the nearest x86 extended precision floating-point
+// number for x and the accuracy of the conversion. The exception flags raised
+// are recorded in ctx; a signaling NaN raises the invalid operation exception.
+func (ctx *Context) NewFromFloat32(x float32) (Float, big.Accuracy) {
+	// +-NaN, with the payload of the binary representation of x; see package
+	// payload.
+	if math.IsNaN(float64(x)) {
+		return ctx.newNaN(payload.FromFloat32(x)), big.Exact
+	}
+	f, acc := ctx.NewFromFloat64(float64(x))
 	if acc == big.Exact {
 		_, acc = f.Float32()
 	}
@@ -67,34 +121,48 @@ func NewFromFloat32(x float32) (Float, big.Accuracy) {
 }
 
 // NewFromFloat64 returns the nearest x86 extended precision floating-point
-// number for x and the accuracy of the conversion.
-func NewFromFloat64(x float64) (Float, big.Accuracy) {
+// number for x and the accuracy of the conversion. The exception flags raised
+// are recorded in ctx; a signaling NaN raises the invalid operation exception.
+func (ctx *Context) NewFromFloat64(x float64) (Float, big.Accuracy) {
 	// +-NaN
-	switch {
-	case math.IsNaN(x):
-		if math.Signbit(x) {
-			// -NaN
-			//    sign: 1
-			//    exp:  all ones
-			//    mant: 10 non-zero
-			return NegNaN, big.Exact
-		}
-		// +NaN
-		//    sign: 0
-		//    exp:  all ones
-		//    mant: 10 non-zero
-		return NaN, big.Exact
+	if math.IsNaN(x) {
+		return ctx.newNaN(payload.FromFloat64(x)), big.Exact
 	}
 	y := big.NewFloat(x)
 	y.SetPrec(precision)
 	y.SetMode(big.ToNearestEven)
 	// TODO: check accuracy after setting precision?
-	return NewFromBig(y)
+	return ctx.NewFromBig(y)
 }
 
 // NewFromBig returns the nearest x86 extended precision floating-point number
-// for x and the accuracy of the conversion.
-func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+// for x and the accuracy of the conversion. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return ctx.NewFromBigMode(x, big.ToNearestEven)
+}
+
+// NewFromFloat32Mode returns the x86 extended precision floating-point number for
+// x rounded in the given rounding mode, and the accuracy of the conversion, as
+// for NewFromFloat32Mode. The exception flags raised are recorded in ctx; a
+// signaling NaN raises the invalid operation exception.
+func (ctx *Context) NewFromFloat32Mode(x float32, mode big.RoundingMode) (Float, big.Accuracy) {
+	return ctx.NewFromFloat32(x)
+}
+
+// NewFromFloat64Mode returns the x86 extended precision floating-point number for
+// x rounded in the given rounding mode, and the accuracy of the conversion, as
+// for NewFromFloat64Mode. The exception flags raised are recorded in ctx; a
+// signaling NaN raises the invalid operation exception.
+func (ctx *Context) NewFromFloat64Mode(x float64, mode big.RoundingMode) (Float, big.Accuracy) {
+	return ctx.NewFromFloat64(x)
+}
+
+// NewFromBigMode returns the x86 extended precision floating-point number for x
+// rounded in the given rounding mode, and the accuracy of the conversion, as
+// for NewFromBigMode. The significand is rounded to 64 bits, regardless of the
+// precision of ctx. The exception flags raised are recorded in ctx.
+func (ctx *Context) NewFromBigMode(x *big.Float, mode big.RoundingMode) (Float, big.Accuracy) {
 	// +-Inf
 	zero := big.NewFloat(0)
 	switch {
@@ -127,59 +195,9 @@ func NewFromBig(x *big.Float) (Float, big.Accuracy) {
 		return Zero, big.Exact
 	}
 
-	// Sign
-	var se uint16
-	if x.Signbit() {
-		se |= 0x8000
-	}
-
-	// Exponent and mantissa.
-	var m uint64
-	mant := &big.Float{}
-	exponent := x.MantExp(mant)
-	// TODO: verify, as float80x86 also has an explicit lead bit.
-	// Remove 1 from the exponent as big.Float has an no lead bit.
-	exp := exponent - 1 + bias
-
-	// Handle denormalized values.
-	// TODO: validate implementation of denormalized values.
-	if exp <= 0 {
-		acc := big.Exact
-		if exp <= -(precision - 1) {
-			exp = precision - 1
-			acc = big.Below
-		}
-		mant.SetMantExp(mant, exp+precision-1)
-		if mant.Signbit() {
-			mant.Neg(mant)
-		}
-		v, _ := mant.Uint64()
-		// TODO: calculate acc based on if v&^0x7FFFFFFFFFFFFFFF != 0 {}
-		m |= v & 0x7FFFFFFFFFFFFFFF
-		return Float{se: se, m: m}, acc
-	}
-
-	// 0b111111111111111
-	acc := big.Exact
-	if (exp &^ 0x7FFF) != 0 {
-		acc = big.Above
-	}
-	se |= uint16(exp & 0x7FFF)
-
-	if mant.Signbit() {
-		mant.Neg(mant)
-	}
-	mant.SetMantExp(mant, precision)
-	if !mant.IsInt() {
-		acc = big.Below
-	}
-	// mantissa, including explicit lead bit
-	mantissa, acc2 := mant.Uint64()
-	if acc == big.Exact {
-		acc = acc2
-	}
-	m |= mantissa
-	return Float{se: se, m: m}, acc
+	z, acc, flags := roundMode(unpackBig(x), precision, mode, ctx.Tininess)
+	ctx.Flags |= flags
+	return z, acc
 }
 
 // Bits returns the x86 extended precision binary representation of f.
@@ -191,32 +209,54 @@ func (f Float) Bits() (se uint16, m uint64) {
 // represented by a float32 (|f| < math.SmallestNonzeroFloat32), the result is
 // (0, Below) or (-0, Above), respectively, depending on the sign of f. If f is
 // too large to be represented by a float32 (|f| > math.MaxFloat32), the result
-// is (+Inf, Above) or (-Inf, Below), depending on the sign of f.
+// is (+Inf, Above) or (-Inf, Below), depending on the sign of f. Not-a-Number
+// values are converted to a quiet NaN with the same sign, keeping the most
+// significant bits of the payload.
 func (f Float) Float32() (float32, big.Accuracy) {
-	x, nan := f.Big()
-	if nan {
-		if x.Signbit() {
-			return float32(-math.NaN()), big.Exact
-		}
-		return float32(math.NaN()), big.Exact
-	}
-	return x.Float32()
+	return new(Context).Float32(f)
 }
 
 // Float64 returns the float64 value nearest to f. If f is too small to be
 // represented by a float64 (|f| < math.SmallestNonzeroFloat64), the result is
 // (0, Below) or (-0, Above), respectively, depending on the sign of f. If f is
 // too large to be represented by a float64 (|f| > math.MaxFloat64), the result
-// is (+Inf, Above) or (-Inf, Below), depending on the sign of f.
+// is (+Inf, Above) or (-Inf, Below), depending on the sign of f. Not-a-Number
+// values are converted to a quiet NaN with the same sign, keeping the most
+// significant bits of the payload.
 func (f Float) Float64() (float64, big.Accuracy) {
+	return new(Context).Float64(f)
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float32(f Float) (float32, big.Accuracy) {
 	x, nan := f.Big()
 	if nan {
-		if x.Signbit() {
-			return -math.NaN(), big.Exact
+		if f.m&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		return payload.Float32(f.Signbit(), f.nanFrac()), big.Exact
+	}
+	y, acc := x.Float32()
+	ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+	return y, acc
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float64(f Float) (float64, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if f.m&quiet == 0 {
+			ctx.Flags |= fenv.Invalid
 		}
-		return math.NaN(), big.Exact
+		return payload.Float64(f.Signbit(), f.nanFrac()), big.Exact
 	}
-	return x.Float64()
+	y, acc := x.Float64()
+	ctx.Flags |= rounding.Flags(x, acc, 53, -1022, 1023, big.ToNearestEven, ctx.Tininess)
+	return y, acc
 }
 
 // Big returns the multi-precision floating-point number representation of f and
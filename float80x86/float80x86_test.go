@@ -1,9 +1,14 @@
 package float80x86
 
 import (
+	"fmt"
 	"math"
 	"math/big"
+	"math/rand"
 	"testing"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
 )
 
 func TestNewFromBits(t *testing.T) {
@@ -13,11 +18,11 @@ func TestNewFromBits(t *testing.T) {
 		want float64
 	}{
 		// Special numbers.
-		// 0 111111111111111 10 non-zero = +NaN
-		{se: 0x7FFF, m: 0xBFFFFFFFFFFFFFFF, want: math.NaN()},
+		// 0 111111111111111 10 non-zero = +NaN (quieted, payload kept)
+		{se: 0x7FFF, m: 0xBFFFFFFFFFFFFFFF, want: math.Float64frombits(0x7FFFFFFFFFFFFFFF)},
 		// -NaN
-		// 1 111111111111111 10 non-zero = -NaN
-		{se: 0xFFFF, m: 0xBFFFFFFFFFFFFFFF, want: -math.NaN()},
+		// 1 111111111111111 10 non-zero = -NaN (quieted, payload kept)
+		{se: 0xFFFF, m: 0xBFFFFFFFFFFFFFFF, want: math.Float64frombits(0xFFFFFFFFFFFFFFFF)},
 
 		// from: https://docs.oracle.com/cd/E19957-01/806-3568/ncg_math.html#960
 
@@ -65,11 +70,11 @@ func TestNewFromFloat64(t *testing.T) {
 		acc big.Accuracy
 	}{
 		// Special numbers.
-		// 0 111111111111111 10 non-zero = +NaN
-		{in: math.NaN(), se: 0x7FFF, m: 0xBFFFFFFFFFFFFFFF, acc: big.Exact},
+		// 0 111111111111111 11 non-zero = +NaN (payload 1)
+		{in: math.NaN(), se: 0x7FFF, m: 0xC000000000000800, acc: big.Exact},
 		// -NaN
-		// 1 111111111111111 10 non-zero = -NaN
-		{in: -math.NaN(), se: 0xFFFF, m: 0xBFFFFFFFFFFFFFFF, acc: big.Exact},
+		// 1 111111111111111 11 non-zero = -NaN (payload 1)
+		{in: -math.NaN(), se: 0xFFFF, m: 0xC000000000000800, acc: big.Exact},
 
 		// from: https://docs.oracle.com/cd/E19957-01/806-3568/ncg_math.html#960
 
@@ -110,3 +115,266 @@ func TestNewFromFloat64(t *testing.T) {
 		}
 	}
 }
+
+func TestNewFromFloat64Mode(t *testing.T) {
+	// Every float32 and float64 is exactly representable, so the result is
+	// independent of the rounding mode.
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	xs := []float64{0, math.Copysign(0, -1), 1.0 / 3, -1.0 / 3, math.MaxFloat64, -math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1)}
+	for _, x := range xs {
+		want, _ := NewFromFloat64(x)
+		want32, _ := NewFromFloat32(float32(x))
+		for _, mode := range modes {
+			if got, acc := NewFromFloat64Mode(x, mode); got != want || acc != big.Exact {
+				t.Errorf("%v %v: float64 mismatch; expected %v (%v), got %v (%v)", x, mode, want, big.Exact, got, acc)
+			}
+			if got, acc := NewFromFloat32Mode(float32(x), mode); got != want32 || acc != big.Exact {
+				t.Errorf("%v %v: float32 mismatch; expected %v (%v), got %v (%v)", float32(x), mode, want32, big.Exact, got, acc)
+			}
+		}
+	}
+}
+
+func TestNewFromBigMode(t *testing.T) {
+	golden := []struct {
+		in   string
+		mode big.RoundingMode
+		want string
+		acc  big.Accuracy
+	}{
+		// 1 + 2^(-64), halfway between 1 and the next number.
+		{in: "0x1.0000000000000001p0", mode: big.ToNearestEven, want: "3FFF8000000000000000", acc: big.Below},
+		{in: "0x1.0000000000000001p0", mode: big.ToNearestAway, want: "3FFF8000000000000001", acc: big.Above},
+		{in: "-0x1.0000000000000001p0", mode: big.ToZero, want: "BFFF8000000000000000", acc: big.Above},
+		{in: "-0x1.0000000000000001p0", mode: big.ToNegativeInf, want: "BFFF8000000000000001", acc: big.Below},
+		// pi
+		{in: "3.14159265358979323846264338327950288", mode: big.ToNearestEven, want: "4000C90FDAA22168C235", acc: big.Above},
+		{in: "3.14159265358979323846264338327950288", mode: big.ToZero, want: "4000C90FDAA22168C234", acc: big.Below},
+		{in: "-3.14159265358979323846264338327950288", mode: big.ToPositiveInf, want: "C000C90FDAA22168C234", acc: big.Above},
+		// Rounded up to a power of two.
+		{in: "0x0.fffffffffffffffffcp0", mode: big.ToNearestEven, want: "3FFF8000000000000000", acc: big.Above},
+		{in: "-0x1.ffffffffffffffffp0", mode: big.ToNearestEven, want: "C0008000000000000000", acc: big.Below},
+		{in: "0x1.ffffffffffffffffp0", mode: big.ToPositiveInf, want: "40008000000000000000", acc: big.Above},
+		// Largest denormalized number rounded up to the smallest normalized
+		// number.
+		{in: "0x0.fffffffffffffffffp-16382", mode: big.AwayFromZero, want: "00018000000000000000", acc: big.Above},
+		// Overflow.
+		{in: "1e5000", mode: big.AwayFromZero, want: "7FFF8000000000000000", acc: big.Above},
+		{in: "1e5000", mode: big.ToNegativeInf, want: "7FFEFFFFFFFFFFFFFFFF", acc: big.Below},
+		{in: "-1e5000", mode: big.ToZero, want: "FFFEFFFFFFFFFFFFFFFF", acc: big.Above},
+		// Underflow.
+		{in: "1e-5000", mode: big.ToNearestAway, want: "00000000000000000000", acc: big.Below},
+		{in: "1e-5000", mode: big.AwayFromZero, want: "00000000000000000001", acc: big.Above},
+		{in: "-1e-5000", mode: big.ToPositiveInf, want: "80000000000000000000", acc: big.Above},
+	}
+	for _, g := range golden {
+		x, _, err := big.ParseFloat(g.in, 0, 1000, big.ToNearestEven)
+		if err != nil {
+			panic(err)
+		}
+		got, acc := NewFromBigMode(x, g.mode)
+		if want := fromHex(g.want); want != got {
+			t.Errorf("%q %v: bits mismatch; expected 0x%s, got 0x%04X%016X", g.in, g.mode, g.want, got.se, got.m)
+		}
+		if g.acc != acc {
+			t.Errorf("%q %v: accuracy mismatch; expected %v, got %v", g.in, g.mode, g.acc, acc)
+		}
+	}
+}
+
+func TestNewFromBigModes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		se, m := uint16(r.Uint32()), r.Uint64()|1<<63
+		if i%4 == 0 {
+			// Denormalized number.
+			se &= 0x8000
+			m &^= 1 << 63
+		}
+		f := NewFromBits(se, m)
+		if f.isNaN() || f.isInf() {
+			continue
+		}
+		// The number, and numbers halfway and a quarter of the way to the
+		// next number of larger magnitude.
+		x, _ := f.Big()
+		y, _ := NewFromBits(nextMag(se, m)).Big()
+		if y.IsInf() {
+			y.SetMantExp(big.NewFloat(float64(y.Sign())), 16384)
+		}
+		x.SetPrec(256)
+		half := new(big.Float).Add(x, y)
+		half.SetMantExp(half, -1)
+		quarter := new(big.Float).Add(x, half)
+		quarter.SetMantExp(quarter, -1)
+		for _, x := range []*big.Float{x, half, quarter} {
+			if x.Sign() != 0 {
+				testModes(t, x)
+			}
+		}
+	}
+}
+
+func TestConversionFlags(t *testing.T) {
+	golden := []struct {
+		in   string
+		mode big.RoundingMode
+		want string
+		// Exception flags raised with tininess detected after and before
+		// rounding.
+		after, before fenv.Flags
+	}{
+		// 1 + 2^(-64) (tie, round to even)
+		{in: "0x1.0000000000000001p0", mode: big.ToNearestEven, want: "3FFF8000000000000000", after: fenv.Inexact, before: fenv.Inexact},
+		// Overflow.
+		{in: "1e5000", mode: big.ToNearestEven, want: "7FFF8000000000000000", after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{in: "1e5000", mode: big.ToZero, want: "7FFEFFFFFFFFFFFFFFFF", after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// Underflow.
+		{in: "1e-5000", mode: big.ToNearestEven, want: "00000000000000000000", after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^(-16382) - 2^(-16447), which is rounded to 2^(-16382) with 64 bits.
+		{in: "0x1.ffffffffffffffffp-16383", mode: big.ToNearestEven, want: "00018000000000000000", after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: "0x1.ffffffffffffffffp-16383", mode: big.ToNegativeInf, want: "00007FFFFFFFFFFFFFFF", after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		x, _, err := big.ParseFloat(g.in, 0, 1000, big.ToNearestEven)
+		if err != nil {
+			panic(err)
+		}
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			// The precision control setting does not affect conversions.
+			ctx := &Context{Precision: PrecisionSingle, Tininess: tininess}
+			got, _ := ctx.NewFromBigMode(x, g.mode)
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if se, m := got.Bits(); fmt.Sprintf("%04X%016X", se, m) != g.want {
+				t.Errorf("%q %v: bits mismatch; expected 0x%s, got 0x%04X%016X", g.in, g.mode, g.want, se, m)
+			}
+			if want != ctx.Flags {
+				t.Errorf("%q %v with tininess detected %v: flags mismatch; expected %v, got %v", g.in, g.mode, tininess, want, ctx.Flags)
+			}
+		}
+	}
+	// Compare against the flags of rounding with big.Float, for numbers near
+	// the smallest normalized number and numbers halfway and a quarter of the
+	// way to the next number.
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		se, m := uint16(0x0001), r.Uint64()|1<<63
+		if i%2 == 0 {
+			// Largest denormalized numbers.
+			se, m = 0x0000, ^uint64(0)>>1-uint64(r.Intn(1000))
+		}
+		x, _ := NewFromBits(se, m).Big()
+		y, _ := NewFromBits(nextMag(se, m)).Big()
+		x.SetPrec(256)
+		half := new(big.Float).Add(x, y)
+		half.SetMantExp(half, -1)
+		quarter := new(big.Float).Add(x, half)
+		quarter.SetMantExp(quarter, -1)
+		for _, x := range []*big.Float{half, quarter, new(big.Float).Neg(half)} {
+			for _, mode := range modes {
+				for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+					ctx := &Context{Tininess: tininess}
+					_, acc := ctx.NewFromBigMode(x, mode)
+					if want := rounding.Flags(x, acc, precision, emin, emax, mode, tininess); want != ctx.Flags {
+						t.Errorf("%v %v with tininess detected %v: flags mismatch; expected %v, got %v", x, mode, tininess, want, ctx.Flags)
+					}
+				}
+			}
+		}
+	}
+}
+
+// testModes checks the conversion of the finite non-zero x in each rounding
+// mode against its neighbours in x86 extended precision.
+func testModes(t *testing.T, x *big.Float) {
+	t.Helper()
+	down, downAcc := NewFromBigMode(x, big.ToNegativeInf)
+	up, upAcc := NewFromBigMode(x, big.ToPositiveInf)
+	d, _ := down.Big()
+	u, _ := up.Big()
+	if downAcc == big.Exact || upAcc == big.Exact {
+		if down != up || downAcc != upAcc {
+			t.Errorf("%v: exact mismatch; got %v (%v) and %v (%v)", x, down, downAcc, up, upAcc)
+		}
+		return
+	}
+	// Successor of down.
+	next := NewFromBits(nextMag(down.se, down.m))
+	if down.Signbit() {
+		next = NewFromBits(prevMag(down.se, down.m))
+	}
+	if next != up || downAcc != big.Below || upAcc != big.Above || d.Cmp(x) >= 0 || u.Cmp(x) <= 0 {
+		t.Errorf("%v: neighbours mismatch; got %v (%v) and %v (%v)", x, down, downAcc, up, upAcc)
+		return
+	}
+	toward, away := down, up
+	if x.Signbit() {
+		toward, away = up, down
+	}
+	// Round to nearest, with infinities replaced by 2^16384.
+	if d.IsInf() {
+		d.SetMantExp(big.NewFloat(-1), 16384)
+	}
+	if u.IsInf() {
+		u.SetMantExp(big.NewFloat(1), 16384)
+	}
+	mid := new(big.Float).SetPrec(256).Add(d, u)
+	mid.SetMantExp(mid, -1)
+	c := x.Cmp(mid)
+	nearestEven, nearestAway := down, down
+	switch {
+	case c > 0:
+		nearestEven, nearestAway = up, up
+	case c == 0:
+		nearestAway = away
+		if down.m&1 != 0 {
+			nearestEven = up
+		}
+	}
+	wants := map[big.RoundingMode]Float{
+		big.ToNearestEven: nearestEven,
+		big.ToNearestAway: nearestAway,
+		big.ToZero:        toward,
+		big.AwayFromZero:  away,
+	}
+	for mode, want := range wants {
+		got, acc := NewFromBigMode(x, mode)
+		wantAcc := big.Below
+		if want == up {
+			wantAcc = big.Above
+		}
+		if want != got || wantAcc != acc {
+			t.Errorf("%v %v: mismatch; expected %v (%v), got %v (%v)", x, mode, want, wantAcc, got, acc)
+		}
+	}
+}
+
+// nextMag returns the binary representation of the next number of larger
+// magnitude than the finite number se:m.
+func nextMag(se uint16, m uint64) (uint16, uint64) {
+	switch {
+	case m == math.MaxUint64:
+		return se + 1, 1 << 63
+	case se&0x7FFF == 0 && m == 1<<63-1:
+		// Largest denormalized number.
+		return se + 1, 1 << 63
+	}
+	return se, m + 1
+}
+
+// prevMag returns the binary representation of the next number of smaller
+// magnitude than the finite non-zero number se:m.
+func prevMag(se uint16, m uint64) (uint16, uint64) {
+	switch {
+	case m == 1<<63 && se&0x7FFF > 1:
+		return se - 1, math.MaxUint64
+	case m == 1<<63 && se&0x7FFF == 1:
+		// Smallest normalized number.
+		return se - 1, 1<<63 - 1
+	}
+	return se, m - 1
+}
@@ -0,0 +1,125 @@
+package float80x86
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	golden := []struct {
+		in   string
+		fmt  byte
+		prec int
+		want string
+	}{
+		// Special numbers.
+		{in: "00000000000000000000", fmt: 'g', prec: -1, want: "0"},
+		{in: "80000000000000000000", fmt: 'e', prec: 3, want: "-0.000e+00"},
+		{in: "7FFF8000000000000000", fmt: 'g', prec: -1, want: "+Inf"},
+		{in: "FFFF8000000000000000", fmt: 'f', prec: 2, want: "-Inf"},
+		{in: "7FFFC000000000000000", fmt: 'g', prec: -1, want: "NaN"},
+
+		{in: "3FFF8000000000000000", fmt: 'g', prec: -1, want: "1"},
+		{in: "3FFF8000000000000000", fmt: 'b', prec: -1, want: "9223372036854775808p-63"},
+		{in: "3FFBCCCCCCCCCCCCCCCD", fmt: 'g', prec: -1, want: "0.1"},
+		{in: "3FFBCCCCCCCCCCCCCCCD", fmt: 'G', prec: 25, want: "0.1000000000000000000013553"},
+		{in: "3FFBCCCCCCCCCCCCCCCD", fmt: 'x', prec: -1, want: "0x1.999999999999999ap-04"},
+		{in: "4000C90FDAA22168C235", fmt: 'g', prec: -1, want: "3.1415926535897932385"},
+		{in: "4000C90FDAA22168C235", fmt: 'e', prec: 5, want: "3.14159e+00"},
+		{in: "403DFFFFFFFFFFFFFFFF", fmt: 'f', prec: -1, want: "9223372036854775807.5"},
+		{in: "403DFFFFFFFFFFFFFFFF", fmt: 'f', prec: 0, want: "9223372036854775808"},
+		// max
+		{in: "7FFEFFFFFFFFFFFFFFFF", fmt: 'g', prec: -1, want: "1.189731495357231765e+4932"},
+		// min normal
+		{in: "00018000000000000000", fmt: 'g', prec: -1, want: "3.3621031431120935063e-4932"},
+		// pseudo-denormal of the same value
+		{in: "00008000000000000000", fmt: 'g', prec: -1, want: "3.3621031431120935063e-4932"},
+		// min denormal
+		{in: "00000000000000000001", fmt: 'g', prec: -1, want: "4e-4951"},
+		{in: "00000000000000000001", fmt: 'E', prec: 5, want: "3.64520E-4951"},
+		{in: "80000000000000000001", fmt: 'b', prec: -1, want: "-1p-16445"},
+	}
+	for _, g := range golden {
+		f := fromHex(g.in)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("%s %c %d: text mismatch; expected %q, got %q", g.in, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		se, m := uint16(r.Uint32()), r.Uint64()
+		if i%2 == 1 {
+			// Denormalized number.
+			se &= 0x8000
+			m &^= 1 << 63
+		}
+		f := NewFromBits(se, m)
+		if f.isNaN() || f.isInf() || f.isUnsupported() {
+			continue
+		}
+		s := f.String()
+		got, _, err := Parse(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if f != got {
+			t.Errorf("%q: round-trip mismatch; expected %04X%016X, got %04X%016X", s, f.se, f.m, got.se, got.m)
+			continue
+		}
+		// The nearest decimal with one digit less does not round-trip.
+		e := f.Text('e', -1)
+		nd := strings.IndexByte(e, 'e') - 1
+		if f.Signbit() {
+			nd--
+		}
+		if nd < 2 {
+			continue
+		}
+		if s := f.Text('e', nd-2); mustParse(s) == f {
+			t.Errorf("%q: not shortest; %q also round-trips", e, s)
+		}
+	}
+}
+
+func mustParse(s string) Float {
+	f, _, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		in     string
+		want   string
+	}{
+		{format: "%v", in: "3FFF8000000000000000", want: "1"},
+		{format: "%+.3e", in: "3FFF8000000000000000", want: "+1.000e+00"},
+		{format: "%.25g", in: "3FFBCCCCCCCCCCCCCCCD", want: "0.1000000000000000000013553"},
+		{format: "%#v", in: "3FFF8000000000000000", want: "1"},
+		{format: "%#g", in: "3FFF8000000000000000", want: "1.00000"},
+		{format: "%8.2f|", in: "BFFF8000000000000000", want: "   -1.00|"},
+		{format: "%-8.2f|", in: "BFFF8000000000000000", want: "-1.00   |"},
+		{format: "%08.2f", in: "BFFF8000000000000000", want: "-0001.00"},
+		{format: "% x", in: "3FFF8000000000000000", want: " 0x1p+00"},
+		{format: "%F", in: "3FFF8000000000000000", want: "1.000000"},
+		{format: "%v", in: "7FFF8000000000000000", want: "+Inf"},
+		{format: "%6v", in: "7FFFC000000000000000", want: "   NaN"},
+		{format: "%s", in: "3FFF8000000000000000", want: "%!s(float80x86.Float=1)"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, fromHex(g.in))
+		if g.want != got {
+			t.Errorf("%q %v: output mismatch; expected %q, got %q", g.format, g.in, g.want, got)
+		}
+	}
+}
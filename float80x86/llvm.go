@@ -0,0 +1,27 @@
+package float80x86
+
+import (
+	"fmt"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// ParseLLVM parses s as an LLVM IR hexadecimal floating-point literal of type
+// x86_fp80; "0xK" followed by the 4 hexadecimal digits of the sign and exponent
+// and the 16 hexadecimal digits of the mantissa, and returns the corresponding
+// floating-point number. If s is syntactically invalid, the error wraps
+// strconv.ErrSyntax.
+func ParseLLVM(s string) (Float, error) {
+	words, err := strconv.ParseLLVMHex(s, 'K', 16, 64)
+	if err != nil {
+		return Float{}, fmt.Errorf("float80x86.ParseLLVM: parsing %q: %w", s, err)
+	}
+	return NewFromBits(uint16(words[0]), words[1]), nil
+}
+
+// LLVMString returns the LLVM IR hexadecimal floating-point literal of f, as
+// accepted by ParseLLVM; e.g. "0xK3FFF8000000000000000" for 1.0.
+func (f Float) LLVMString() string {
+	se, m := f.Bits()
+	return fmt.Sprintf("0xK%04X%016X", se, m)
+}
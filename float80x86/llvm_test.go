@@ -0,0 +1,48 @@
+package float80x86
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLLVM(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{in: "0xK3FFF8000000000000000", want: "1"},
+		{in: "0xKBFFFC000000000000000", want: "-1.5"},
+		{in: "0xK4000C90FDAA22168C235", want: "3.1415926535897932385"},
+		{in: "0xK3ffbcccccccccccccccd", want: "0.1"},
+		{in: "0xK00000000000000000001", want: "4e-4951"},
+		{in: "0xK7FFF8000000000000000", want: "+Inf"},
+		{in: "0xK7FFFC000000000000000", want: "NaN"},
+		{in: "0xK80000000000000000000", want: "-0"},
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "0xK3FFF800000000000000", err: strconv.ErrSyntax},
+		{in: "0xK3FFF80000000000000000", err: strconv.ErrSyntax},
+		{in: "0xL3FFF8000000000000000", err: strconv.ErrSyntax},
+		{in: "0xK3FFF80000000000000-0", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, err := ParseLLVM(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err != nil {
+			continue
+		}
+		if s := got.String(); g.want != s {
+			t.Errorf("%q: value mismatch; expected %v, got %v", g.in, g.want, s)
+		}
+		// Round-trip.
+		if s := got.LLVMString(); strings.ToUpper(g.in[3:]) != s[3:] || g.in[:3] != s[:3] {
+			t.Errorf("%q: round-trip mismatch; got %q", g.in, s)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package float80x86
+
+import (
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+)
+
+// NewNaN returns a Not-a-Number value with the given payload, which is quiet
+// unless signaling is set. The payload is the fraction without the quiet bit,
+// truncated to 62 bits. A signaling NaN with a zero payload has payload 1, as
+// its encoding would otherwise be infinity.
+func NewNaN(signaling bool, payload uint64) Float {
+	payload &= 0x3FFFFFFFFFFFFFFF
+	if !signaling {
+		return Float{se: 0x7FFF, m: 0x8000000000000000 | quiet | payload}
+	}
+	if payload == 0 {
+		payload = 1
+	}
+	return Float{se: 0x7FFF, m: 0x8000000000000000 | payload}
+}
+
+// IsSignaling reports whether f is a signaling Not-a-Number value (SNaN).
+// Pseudo-NaN values, with the explicit lead bit clear, are not NaN.
+func (f Float) IsSignaling() bool {
+	return f.isNaN() && f.m&quiet == 0
+}
+
+// Payload returns the payload of the Not-a-Number value f, i.e. its fraction
+// without the quiet bit, or 0 if f is not NaN.
+func (f Float) Payload() uint64 {
+	if !f.isNaN() {
+		return 0
+	}
+	return f.m & 0x3FFFFFFFFFFFFFFF
+}
+
+// Quiet returns f with the quiet bit set if f is a signaling Not-a-Number value,
+// and f otherwise. The sign and payload are preserved.
+func (f Float) Quiet() Float {
+	if !f.isNaN() {
+		return f
+	}
+	return Float{se: f.se, m: f.m | quiet}
+}
+
+// newNaN returns the quiet NaN with the given sign and the fraction frac aligned
+// at the most significant bit, keeping the 63 most significant bits of the
+// fraction. The invalid operation exception is raised if frac is the fraction
+// of a signaling NaN.
+func (ctx *Context) newNaN(sign bool, frac uint64) Float {
+	if frac&payload.Quiet == 0 {
+		ctx.Flags |= fenv.Invalid
+	}
+	f := Float{se: 0x7FFF, m: 0x8000000000000000 | quiet | frac>>1}
+	if sign {
+		return f.neg()
+	}
+	return f
+}
+
+// nanFrac returns the fraction of the Not-a-Number value f aligned at the most
+// significant bit.
+func (f Float) nanFrac() uint64 {
+	return f.m << 1
+}
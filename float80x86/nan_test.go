@@ -0,0 +1,93 @@
+package float80x86
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewNaN(t *testing.T) {
+	golden := []struct {
+		signaling bool
+		payload   uint64
+		m         uint64
+	}{
+		{signaling: false, m: 0xC000000000000000},
+		{signaling: false, payload: 0x1555555555555555, m: 0xD555555555555555},
+		{signaling: true, payload: 0x1555555555555555, m: 0x9555555555555555},
+		// Zero payload of signaling NaN.
+		{signaling: true, m: 0x8000000000000001},
+		// Payload truncated to 62 bits.
+		{signaling: false, payload: math.MaxUint64, m: 0xFFFFFFFFFFFFFFFF},
+		{signaling: true, payload: 0x4000000000000000, m: 0x8000000000000001},
+	}
+	for _, g := range golden {
+		f := NewNaN(g.signaling, g.payload)
+		if se, m := f.Bits(); se != 0x7FFF || g.m != m {
+			t.Errorf("NewNaN(%v, 0x%016X): bits mismatch; expected 0x7FFF %016X, got 0x%04X %016X", g.signaling, g.payload, g.m, se, m)
+			continue
+		}
+		if f.IsSignaling() != g.signaling {
+			t.Errorf("0x%016X: signaling mismatch; expected %v, got %v", g.m, g.signaling, f.IsSignaling())
+		}
+		if want := g.m & 0x3FFFFFFFFFFFFFFF; want != f.Payload() {
+			t.Errorf("0x%016X: payload mismatch; expected 0x%016X, got 0x%016X", g.m, want, f.Payload())
+		}
+		if q := f.Quiet(); q != NewFromBits(0x7FFF, g.m|0x4000000000000000) || q.IsSignaling() {
+			t.Errorf("0x%016X: quiet mismatch; got %v", g.m, q)
+		}
+	}
+	// Numbers other than NaN, including pseudo-NaN.
+	for _, f := range []Float{Inf, NegInf, Zero, NegZero, NewFromBits(0x3FFF, 0x8000000000000000), NewFromBits(0x7FFF, 0x0000000000000001)} {
+		if f.IsSignaling() || f.Payload() != 0 || f.Quiet() != f {
+			t.Errorf("%v: unexpected NaN", f)
+		}
+	}
+}
+
+func TestNaNConversion(t *testing.T) {
+	golden32 := []struct {
+		in    uint32
+		se    uint16
+		m     uint64
+		flags fenv.Flags
+	}{
+		{in: 0x7FC00000, se: 0x7FFF, m: 0xC000000000000000},
+		{in: 0xFFD55555, se: 0xFFFF, m: 0xD555550000000000},
+		// Signaling NaN is quieted.
+		{in: 0x7F800001, se: 0x7FFF, m: 0xC000010000000000, flags: fenv.Invalid},
+	}
+	for _, g := range golden32 {
+		ctx := new(Context)
+		f, _ := ctx.NewFromFloat32(math.Float32frombits(g.in))
+		if se, m := f.Bits(); g.se != se || g.m != m || g.flags != ctx.Flags {
+			t.Errorf("0x%08X: float32 conversion mismatch; expected 0x%04X %016X (%v), got 0x%04X %016X (%v)", g.in, g.se, g.m, g.flags, se, m, ctx.Flags)
+		}
+		// Round-trip.
+		if x, _ := f.Float32(); math.Float32bits(x) != g.in|0x00400000 {
+			t.Errorf("0x%08X: round-trip mismatch; got 0x%08X", g.in, math.Float32bits(x))
+		}
+	}
+	golden64 := []struct {
+		in    uint64
+		se    uint16
+		m     uint64
+		flags fenv.Flags
+	}{
+		{in: 0x7FFAAAAAAAAAAAAA, se: 0x7FFF, m: 0xD555555555555000},
+		// Signaling NaN is quieted.
+		{in: 0xFFF0000000000001, se: 0xFFFF, m: 0xC000000000000800, flags: fenv.Invalid},
+	}
+	for _, g := range golden64 {
+		ctx := new(Context)
+		f, _ := ctx.NewFromFloat64(math.Float64frombits(g.in))
+		if se, m := f.Bits(); g.se != se || g.m != m || g.flags != ctx.Flags {
+			t.Errorf("0x%016X: float64 conversion mismatch; expected 0x%04X %016X (%v), got 0x%04X %016X (%v)", g.in, g.se, g.m, g.flags, se, m, ctx.Flags)
+		}
+		// Round-trip.
+		if x, _ := f.Float64(); math.Float64bits(x) != g.in|0x0008000000000000 {
+			t.Errorf("0x%016X: round-trip mismatch; got 0x%016X", g.in, math.Float64bits(x))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package float80x86
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	// Expected results computed using glibc's strtold.
+	golden := []struct {
+		in   string
+		want string
+		acc  big.Accuracy
+		err  error
+	}{
+		// Special numbers.
+		{in: "0", want: "00000000000000000000", acc: big.Exact},
+		{in: "-0.0e10", want: "80000000000000000000", acc: big.Exact},
+		{in: "inf", want: "7FFF8000000000000000", acc: big.Exact},
+		{in: "-Infinity", want: "FFFF8000000000000000", acc: big.Exact},
+		{in: "+NaN", want: "7FFFBFFFFFFFFFFFFFFF", acc: big.Exact},
+		{in: "-nan", want: "FFFFBFFFFFFFFFFFFFFF", acc: big.Exact},
+
+		{in: "1", want: "3FFF8000000000000000", acc: big.Exact},
+		{in: "0.1", want: "3FFBCCCCCCCCCCCCCCCD", acc: big.Above},
+		{in: "-2.5e-3", want: "BFF6A3D70A3D70A3D70A", acc: big.Above},
+		{in: "3.14159265358979323846264338327950288", want: "4000C90FDAA22168C235", acc: big.Above},
+		{in: "0x1.8p-3", want: "3FFCC000000000000000", acc: big.Exact},
+		// Rounded up to a power of two.
+		{in: "0.99999999999999999999999999", want: "3FFF8000000000000000", acc: big.Above},
+		{in: "-1.99999999999999999999999999", want: "C0008000000000000000", acc: big.Below},
+		// max + half ulp = +Inf (tie, round to even)
+		{in: "0x1.ffffffffffffffffp16383", want: "7FFF8000000000000000", acc: big.Above, err: strconv.ErrRange},
+		// max
+		{in: "1.18973149535723176502e4932", want: "7FFEFFFFFFFFFFFFFFFF", acc: big.Above},
+		{in: "1.2e4932", want: "7FFF8000000000000000", acc: big.Above, err: strconv.ErrRange},
+		{in: "-1e5000", want: "FFFF8000000000000000", acc: big.Below, err: strconv.ErrRange},
+		// Denormalized numbers.
+		{in: "-0x1p-16445", want: "80000000000000000001", acc: big.Exact},
+		{in: "3.645199531882474602528e-4951", want: "00000000000000000001", acc: big.Above},
+		{in: "1.9e-4951", want: "00000000000000000001", acc: big.Above},
+		{in: "1.8e-4951", want: "00000000000000000000", acc: big.Below},
+		{in: "1e-5000", want: "00000000000000000000", acc: big.Below},
+
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "1e", err: strconv.ErrSyntax},
+		{in: "0x1.8", err: strconv.ErrSyntax},
+		{in: "1.2.3", err: strconv.ErrSyntax},
+		{in: "nan(1)", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := Parse(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if want := fromHex(g.want); want != got {
+			t.Errorf("%q: bits mismatch; expected 0x%s, got 0x%04X%016X", g.in, g.want, got.se, got.m)
+		}
+		if g.acc != acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
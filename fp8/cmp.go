@@ -0,0 +1,152 @@
+package fp8
+
+import (
+	"math/big"
+
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// ### [ E4M3 ] ################################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0. A NaN is considered less than any non-NaN and equal to a NaN,
+// as by cmp.Compare.
+func (f E4M3) Cmp(g E4M3) int {
+	fnan, gnan := f.IsNaN(), g.IsNaN()
+	switch {
+	case fnan || gnan:
+		return order.NaN(fnan, gnan)
+	case f.IsZero() && g.IsZero():
+		return 0
+	}
+	return total(f.bits, g.bits)
+}
+
+// Less reports whether f < g. It is false if f or g is NaN.
+func (f E4M3) Less(g E4M3) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is NaN, and true for -0
+// and +0.
+func (f E4M3) Equal(g E4M3) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder):
+//
+//	-NaN < -x < -0 < +0 < +x < +NaN
+func (f E4M3) TotalOrder(g E4M3) bool {
+	return total(f.bits, g.bits) <= 0
+}
+
+// Class returns the class of f. The NaN of E4M3 is quiet.
+func (f E4M3) Class() float.Class {
+	return e4m3.Class(big.NewInt(int64(f.bits)))
+}
+
+// IsNaN reports whether f is Not-a-Number.
+func (f E4M3) IsNaN() bool {
+	return f.bits&0x7F == 0x7F
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as E4M3 has no
+// infinities.
+func (f E4M3) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number.
+func (f E4M3) IsSubnormal() bool {
+	return f.Exp() == 0 && !f.IsZero()
+}
+
+// IsFinite reports whether f is not NaN.
+func (f E4M3) IsFinite() bool {
+	return !f.IsNaN()
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f E4M3) IsZero() bool {
+	return f.bits&0x7F == 0
+}
+
+// ### [ E5M2 ] ################################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0. A NaN is considered less than any non-NaN and equal to a NaN,
+// as by cmp.Compare.
+func (f E5M2) Cmp(g E5M2) int {
+	fnan, gnan := f.IsNaN(), g.IsNaN()
+	switch {
+	case fnan || gnan:
+		return order.NaN(fnan, gnan)
+	case f.IsZero() && g.IsZero():
+		return 0
+	}
+	return total(f.bits, g.bits)
+}
+
+// Less reports whether f < g. It is false if f or g is NaN.
+func (f E5M2) Less(g E5M2) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is NaN, and true for -0
+// and +0.
+func (f E5M2) Equal(g E5M2) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder):
+//
+//	-qNaN < -sNaN < -Inf < -x < -0 < +0 < +x < +Inf < +sNaN < +qNaN
+//
+// NaNs of the same kind and sign are ordered by payload, in reverse for
+// negative NaNs.
+func (f E5M2) TotalOrder(g E5M2) bool {
+	return total(f.bits, g.bits) <= 0
+}
+
+// Class returns the class of f. NaNs with the most significant bit of the
+// fraction set are quiet, and other NaNs signaling, as in IEEE 754.
+func (f E5M2) Class() float.Class {
+	return e5m2.Class(big.NewInt(int64(f.bits)))
+}
+
+// IsNaN reports whether f is Not-a-Number.
+func (f E5M2) IsNaN() bool {
+	return f.Exp() == 0x1F && f.Frac() != 0
+}
+
+// IsInf reports whether f is +Inf or -Inf.
+func (f E5M2) IsInf() bool {
+	return f.Exp() == 0x1F && f.Frac() == 0
+}
+
+// IsSubnormal reports whether f is a subnormal number.
+func (f E5M2) IsSubnormal() bool {
+	return f.Exp() == 0 && !f.IsZero()
+}
+
+// IsFinite reports whether f is neither infinite nor NaN.
+func (f E5M2) IsFinite() bool {
+	return f.Exp() != 0x1F
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f E5M2) IsZero() bool {
+	return f.bits&0x7F == 0
+}
+
+// ### [ Helper functions ] ####################################################
+
+// total returns the comparison in the total order of the 8-bit floating-point
+// numbers with the binary representations x and y; -1 if x is ordered before
+// y, 0 if x and y are identical and +1 otherwise.
+func total(x, y uint8) int {
+	return order.Bits([]uint64{uint64(x)}, []uint64{uint64(y)}, 0x80)
+}
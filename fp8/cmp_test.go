@@ -0,0 +1,133 @@
+package fp8
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClassE4M3(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint8
+		class float.Class
+	}{
+		{bits: 0xFF, class: float.QuietNaN},
+		{bits: 0xFE, class: float.NegativeNormal},
+		{bits: 0xB8, class: float.NegativeNormal},
+		{bits: 0x88, class: float.NegativeNormal},
+		{bits: 0x87, class: float.NegativeSubnormal},
+		{bits: 0x81, class: float.NegativeSubnormal},
+		{bits: 0x80, class: float.NegativeZero},
+		{bits: 0x00, class: float.PositiveZero},
+		{bits: 0x01, class: float.PositiveSubnormal},
+		{bits: 0x07, class: float.PositiveSubnormal},
+		{bits: 0x08, class: float.PositiveNormal},
+		{bits: 0x38, class: float.PositiveNormal},
+		{bits: 0x7E, class: float.PositiveNormal},
+		{bits: 0x7F, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewE4M3FromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%02X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%02X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestClassE5M2(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint8
+		class float.Class
+	}{
+		{bits: 0xFF, class: float.QuietNaN},
+		{bits: 0xFE, class: float.QuietNaN},
+		{bits: 0xFD, class: float.SignalingNaN},
+		{bits: 0xFC, class: float.NegativeInfinity},
+		{bits: 0xFB, class: float.NegativeNormal},
+		{bits: 0xBC, class: float.NegativeNormal},
+		{bits: 0x84, class: float.NegativeNormal},
+		{bits: 0x83, class: float.NegativeSubnormal},
+		{bits: 0x81, class: float.NegativeSubnormal},
+		{bits: 0x80, class: float.NegativeZero},
+		{bits: 0x00, class: float.PositiveZero},
+		{bits: 0x01, class: float.PositiveSubnormal},
+		{bits: 0x03, class: float.PositiveSubnormal},
+		{bits: 0x04, class: float.PositiveNormal},
+		{bits: 0x3C, class: float.PositiveNormal},
+		{bits: 0x7B, class: float.PositiveNormal},
+		{bits: 0x7C, class: float.PositiveInfinity},
+		{bits: 0x7D, class: float.SignalingNaN},
+		{bits: 0x7E, class: float.QuietNaN},
+		{bits: 0x7F, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewE5M2FromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%02X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%02X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestCmpE4M3(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding; E4M3 has no
+	// infinities and no signaling NaN.
+	golden := []struct {
+		x, y  uint8
+		cmp   int
+		total bool
+	}{
+		// Largest subnormal and smallest normal number.
+		{x: 0x07, y: 0x08, cmp: -1, total: true},
+		// Largest finite number and NaN.
+		{x: 0x7E, y: 0x7F, cmp: +1, total: true},
+		{x: 0xFF, y: 0xFE, cmp: -1, total: true},
+		// Positive and negative zero.
+		{x: 0x00, y: 0x80, cmp: 0, total: false},
+		// Positive and negative NaN.
+		{x: 0x7F, y: 0xFF, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewE4M3FromBits(g.x), NewE4M3FromBits(g.y)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%02X.Cmp(0x%02X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%02X.TotalOrder(0x%02X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
+
+func TestCmpE5M2(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding.
+	golden := []struct {
+		x, y  uint8
+		cmp   int
+		total bool
+	}{
+		// Largest subnormal and smallest normal number.
+		{x: 0x03, y: 0x04, cmp: -1, total: true},
+		{x: 0x84, y: 0x83, cmp: -1, total: true},
+		// Positive and negative zero.
+		{x: 0x00, y: 0x80, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{x: 0x7D, y: 0x7E, cmp: 0, total: true},
+		{x: 0xFD, y: 0xFE, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewE5M2FromBits(g.x), NewE5M2FromBits(g.y)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%02X.Cmp(0x%02X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%02X.TotalOrder(0x%02X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package fp8
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/ieee754"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// e4m3 is the E4M3 format; with a 4-bit exponent with bias 7, a 3-bit fraction,
+// no infinities and NaN encoded only by an exponent and fraction of all ones.
+var e4m3 = ieee754.Format{ExpBits: 4, FracBits: 3, Bias: 7, Special: ieee754.NaNOnly}
+
+// Positive and negative Not-a-Number, largest finite number and zero of the
+// E4M3 format.
+var (
+	// +NaN
+	E4M3NaN = E4M3{bits: 0x7F}
+	// -NaN
+	E4M3NegNaN = E4M3{bits: 0xFF}
+	// +448
+	E4M3Max = E4M3{bits: 0x7E}
+	// -448
+	E4M3NegMax = E4M3{bits: 0xFE}
+	// +zero
+	E4M3Zero = E4M3{bits: 0x00}
+	// -zero
+	E4M3NegZero = E4M3{bits: 0x80}
+)
+
+// E4M3 is a floating-point number in OCP 8-bit E4M3 format.
+type E4M3 struct {
+	// Sign, exponent and fraction.
+	//
+	//    1 bit:   sign
+	//    4 bits:  exponent
+	//    3 bits:  fraction
+	bits uint8
+}
+
+// NewE4M3FromBits returns the floating-point number corresponding to the E4M3
+// binary representation.
+func NewE4M3FromBits(bits uint8) E4M3 {
+	return E4M3{bits: bits}
+}
+
+// NewE4M3FromFloat32 returns the nearest E4M3 floating-point number for x and
+// the accuracy of the conversion, using round half to even and the given
+// saturation mode.
+func NewE4M3FromFloat32(x float32, sat Saturation) (E4M3, big.Accuracy) {
+	return new(Context).NewE4M3FromFloat32(x, sat)
+}
+
+// NewE4M3FromFloat64 returns the nearest E4M3 floating-point number for x and
+// the accuracy of the conversion, using round half to even and the given
+// saturation mode.
+func NewE4M3FromFloat64(x float64, sat Saturation) (E4M3, big.Accuracy) {
+	return new(Context).NewE4M3FromFloat64(x, sat)
+}
+
+// NewE4M3FromBig returns the nearest E4M3 floating-point number for x and the
+// accuracy of the conversion, using round half to even and the given
+// saturation mode.
+func NewE4M3FromBig(x *big.Float, sat Saturation) (E4M3, big.Accuracy) {
+	return new(Context).NewE4M3FromBig(x, sat)
+}
+
+// NewE4M3FromFloat32 returns the nearest E4M3 floating-point number for x and
+// the accuracy of the conversion, as for NewE4M3FromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewE4M3FromFloat32(x float32, sat Saturation) (E4M3, big.Accuracy) {
+	ctx.signaling32(x)
+	return ctx.newE4M3FromFloat64(float64(x), sat)
+}
+
+// NewE4M3FromFloat64 returns the nearest E4M3 floating-point number for x and
+// the accuracy of the conversion, as for NewE4M3FromFloat64. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewE4M3FromFloat64(x float64, sat Saturation) (E4M3, big.Accuracy) {
+	ctx.signaling64(x)
+	return ctx.newE4M3FromFloat64(x, sat)
+}
+
+// NewE4M3FromBig returns the nearest E4M3 floating-point number for x and the
+// accuracy of the conversion, as for NewE4M3FromBig. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) NewE4M3FromBig(x *big.Float, sat Saturation) (E4M3, big.Accuracy) {
+	bits, acc := ctx.encode(e4m3, x, sat)
+	return E4M3{bits: bits}, acc
+}
+
+// newE4M3FromFloat64 returns the nearest E4M3 floating-point number for x and
+// the accuracy of the conversion. The exception flags raised by rounding are
+// recorded in ctx.
+func (ctx *Context) newE4M3FromFloat64(x float64, sat Saturation) (E4M3, big.Accuracy) {
+	// +-NaN
+	if math.IsNaN(x) {
+		if math.Signbit(x) {
+			// -NaN
+			return E4M3NegNaN, big.Exact
+		}
+		// +NaN
+		return E4M3NaN, big.Exact
+	}
+	return ctx.NewE4M3FromBig(big.NewFloat(x), sat)
+}
+
+// Bits returns the E4M3 binary representation of f.
+func (f E4M3) Bits() uint8 {
+	return f.bits
+}
+
+// Float32 returns the float32 value of f, which is exact unless f is
+// Not-a-Number.
+func (f E4M3) Float32() (float32, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if x.Signbit() {
+			return float32(-math.NaN()), big.Exact
+		}
+		return float32(math.NaN()), big.Exact
+	}
+	return x.Float32()
+}
+
+// Float64 returns the float64 value of f, which is exact unless f is
+// Not-a-Number.
+func (f E4M3) Float64() (float64, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if x.Signbit() {
+			return -math.NaN(), big.Exact
+		}
+		return math.NaN(), big.Exact
+	}
+	return x.Float64()
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number.
+func (f E4M3) Big() (x *big.Float, nan bool) {
+	return decode(e4m3, f.bits)
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f E4M3) Signbit() bool {
+	// first bit is sign bit: 0b10000000
+	return f.bits&0x80 != 0
+}
+
+// Exp returns the exponent of f.
+func (f E4M3) Exp() int {
+	// 4 bit exponent: 0b01111000
+	return int(f.bits & 0x78 >> 3)
+}
+
+// Frac returns the fraction of f.
+func (f E4M3) Frac() uint8 {
+	// 3 bit fraction: 0b00000111
+	return f.bits & 0x07
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary to identify f uniquely.
+func (f E4M3) Text(fmt byte, prec int) string {
+	x, nan := f.Big()
+	return text(e4m3, x, nan, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which identifies f
+// uniquely, as for f.Text('g', -1).
+func (f E4M3) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f E4M3) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
@@ -0,0 +1,186 @@
+package fp8
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewE4M3FromBits(t *testing.T) {
+	golden := []struct {
+		bits uint8
+		want float64
+	}{
+		// Special numbers.
+		// 0 0000 000 = 0
+		{bits: 0x00, want: 0},
+		// 1 0000 000 = -0
+		{bits: 0x80, want: math.Copysign(0, -1)},
+		// 0 1111 111 = +NaN
+		{bits: 0x7F, want: math.NaN()},
+		// 1 1111 111 = -NaN
+		{bits: 0xFF, want: -math.NaN()},
+
+		// from: OCP 8-bit Floating Point Specification (OFP8), table 2.
+		// 0 1111 110 = 448 (largest normal number)
+		{bits: 0x7E, want: 448},
+		// 1 1111 110 = -448
+		{bits: 0xFE, want: -448},
+		// 0 1111 000 = 256
+		{bits: 0x78, want: 256},
+		// 0 0001 000 = 2^-6 (smallest normal number)
+		{bits: 0x08, want: 0x1p-6},
+		// 0 0000 111 = 0.875 * 2^-6 (largest subnormal number)
+		{bits: 0x07, want: 0x1.cp-7},
+		// 0 0000 001 = 2^-9 (smallest subnormal number)
+		{bits: 0x01, want: 0x1p-9},
+		// 0 0111 000 = 1
+		{bits: 0x38, want: 1},
+		// 1 1000 100 = -3
+		{bits: 0xC4, want: -3},
+	}
+	for _, g := range golden {
+		f := NewE4M3FromBits(g.bits)
+		got, acc := f.Float64()
+		if math.Float64bits(got) != math.Float64bits(g.want) && !(math.IsNaN(got) && math.IsNaN(g.want) && math.Signbit(got) == math.Signbit(g.want)) {
+			t.Errorf("0x%02X: number mismatch; expected %v, got %v", g.bits, g.want, got)
+		}
+		if acc != big.Exact {
+			t.Errorf("0x%02X: accuracy mismatch; expected %v, got %v", g.bits, big.Exact, acc)
+		}
+	}
+}
+
+func TestNewE4M3FromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		sat  Saturation
+		want uint8
+		acc  big.Accuracy
+	}{
+		// Special numbers.
+		{in: math.NaN(), sat: NonSaturating, want: 0x7F, acc: big.Exact},
+		{in: -math.NaN(), sat: Saturating, want: 0xFF, acc: big.Exact},
+		{in: math.Inf(1), sat: NonSaturating, want: 0x7F, acc: big.Exact},
+		{in: math.Inf(-1), sat: NonSaturating, want: 0xFF, acc: big.Exact},
+		{in: math.Inf(1), sat: Saturating, want: 0x7E, acc: big.Below},
+		{in: math.Inf(-1), sat: Saturating, want: 0xFE, acc: big.Above},
+		{in: 0, sat: NonSaturating, want: 0x00, acc: big.Exact},
+		{in: math.Copysign(0, -1), sat: NonSaturating, want: 0x80, acc: big.Exact},
+
+		// Rounding.
+		{in: 1, sat: NonSaturating, want: 0x38, acc: big.Exact},
+		// 1.0625 is halfway between 1 and 1.125; ties to even.
+		{in: 1.0625, sat: NonSaturating, want: 0x38, acc: big.Below},
+		// 1.1875 is halfway between 1.125 and 1.25; ties to even.
+		{in: 1.1875, sat: NonSaturating, want: 0x3A, acc: big.Above},
+		{in: -1.1, sat: NonSaturating, want: 0xB9, acc: big.Below},
+
+		// Subnormal numbers and underflow.
+		{in: 0x1p-9, sat: NonSaturating, want: 0x01, acc: big.Exact},
+		{in: 0x1p-10, sat: NonSaturating, want: 0x00, acc: big.Below},
+		{in: 0x1.8p-10, sat: NonSaturating, want: 0x01, acc: big.Above},
+		{in: -0x1p-11, sat: NonSaturating, want: 0x80, acc: big.Above},
+		{in: 0x1.ep-7, sat: NonSaturating, want: 0x08, acc: big.Above},
+
+		// Overflow; 464 is halfway between 448 and 480, which would be NaN.
+		{in: 448, sat: NonSaturating, want: 0x7E, acc: big.Exact},
+		{in: 464, sat: NonSaturating, want: 0x7E, acc: big.Below},
+		{in: 465, sat: NonSaturating, want: 0x7F, acc: big.Exact},
+		{in: -1e6, sat: NonSaturating, want: 0xFF, acc: big.Exact},
+		{in: 465, sat: Saturating, want: 0x7E, acc: big.Below},
+		{in: -1e6, sat: Saturating, want: 0xFE, acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc := NewE4M3FromFloat64(g.in, g.sat)
+		if f.Bits() != g.want || acc != g.acc {
+			t.Errorf("%v (saturation %d): mismatch; expected 0x%02X (%v), got 0x%02X (%v)", g.in, g.sat, g.want, g.acc, f.Bits(), acc)
+		}
+	}
+}
+
+func TestE4M3Flags(t *testing.T) {
+	golden := []struct {
+		in            float64
+		sat           Saturation
+		after, before fenv.Flags
+	}{
+		{in: 1, sat: NonSaturating},
+		{in: 1.0625, sat: NonSaturating, after: fenv.Inexact, before: fenv.Inexact},
+		// Overflow to NaN, and saturated overflow.
+		{in: 465, sat: NonSaturating, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{in: -1e6, sat: Saturating, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// Infinities have no representation.
+		{in: math.Inf(1), sat: NonSaturating, after: fenv.Invalid, before: fenv.Invalid},
+		{in: math.Inf(-1), sat: Saturating, after: fenv.Invalid, before: fenv.Invalid},
+		// Underflow; 2^(-6) - 2^(-11) is tiny only before rounding.
+		{in: 0x1p-10, sat: NonSaturating, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: 0x1.fp-7, sat: NonSaturating, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// Signaling and quiet NaN.
+		{in: math.Float64frombits(0x7FF0000000000001), sat: NonSaturating, after: fenv.Invalid, before: fenv.Invalid},
+		{in: math.NaN(), sat: NonSaturating},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			ctx.NewE4M3FromFloat64(g.in, g.sat)
+			if want != ctx.Flags {
+				t.Errorf("%v (saturation %d, %v): flags mismatch; expected %v, got %v", g.in, g.sat, tininess, want, ctx.Flags)
+			}
+		}
+	}
+	ctx := new(Context)
+	ctx.NewE4M3FromFloat32(math.Float32frombits(0x7F800001), NonSaturating)
+	if ctx.Flags != fenv.Invalid {
+		t.Errorf("float32 signaling NaN: flags mismatch; expected %v, got %v", fenv.Invalid, ctx.Flags)
+	}
+}
+
+func TestE4M3RoundTrip(t *testing.T) {
+	for bits := 0; bits <= math.MaxUint8; bits++ {
+		f := NewE4M3FromBits(uint8(bits))
+		x, nan := f.Big()
+		if nan {
+			continue
+		}
+		for _, sat := range []Saturation{NonSaturating, Saturating} {
+			if got, acc := NewE4M3FromBig(x, sat); got != f || acc != big.Exact {
+				t.Errorf("0x%02X: round trip mismatch; expected 0x%02X (Exact), got 0x%02X (%v)", bits, bits, got.Bits(), acc)
+			}
+		}
+		y, _ := f.Float32()
+		if got, acc := NewE4M3FromFloat32(y, NonSaturating); got != f || acc != big.Exact {
+			t.Errorf("0x%02X: round trip mismatch; expected 0x%02X (Exact), got 0x%02X (%v)", bits, bits, got.Bits(), acc)
+		}
+	}
+}
+
+func TestE4M3Text(t *testing.T) {
+	golden := []struct {
+		bits uint8
+		want string
+	}{
+		{bits: 0x7E, want: "450"},
+		{bits: 0x01, want: "0.002"},
+		{bits: 0x07, want: "0.014"},
+		{bits: 0xB9, want: "-1.1"},
+		{bits: 0x80, want: "-0"},
+		{bits: 0xFF, want: "NaN"},
+	}
+	for _, g := range golden {
+		f := NewE4M3FromBits(g.bits)
+		if got := f.String(); got != g.want {
+			t.Errorf("0x%02X: string mismatch; expected %q, got %q", g.bits, g.want, got)
+		}
+	}
+	if got, want := fmt.Sprintf("%+08.3f", E4M3NegMax), "-448.000"; got != want {
+		t.Errorf("format mismatch; expected %q, got %q", want, got)
+	}
+}
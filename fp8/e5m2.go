@@ -0,0 +1,191 @@
+package fp8
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/ieee754"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// e5m2 is the E5M2 format; an IEEE 754 style format with a 5-bit exponent with
+// bias 15 and a 2-bit fraction.
+var e5m2 = ieee754.Binary(5, 2)
+
+// Positive and negative Not-a-Number, infinity, largest finite number and zero
+// of the E5M2 format.
+var (
+	// +NaN
+	E5M2NaN = E5M2{bits: 0x7E}
+	// -NaN
+	E5M2NegNaN = E5M2{bits: 0xFE}
+	// +Inf
+	E5M2Inf = E5M2{bits: 0x7C}
+	// -Inf
+	E5M2NegInf = E5M2{bits: 0xFC}
+	// +57344
+	E5M2Max = E5M2{bits: 0x7B}
+	// -57344
+	E5M2NegMax = E5M2{bits: 0xFB}
+	// +zero
+	E5M2Zero = E5M2{bits: 0x00}
+	// -zero
+	E5M2NegZero = E5M2{bits: 0x80}
+)
+
+// E5M2 is a floating-point number in OCP 8-bit E5M2 format.
+type E5M2 struct {
+	// Sign, exponent and fraction.
+	//
+	//    1 bit:   sign
+	//    5 bits:  exponent
+	//    2 bits:  fraction
+	bits uint8
+}
+
+// NewE5M2FromBits returns the floating-point number corresponding to the E5M2
+// binary representation.
+func NewE5M2FromBits(bits uint8) E5M2 {
+	return E5M2{bits: bits}
+}
+
+// NewE5M2FromFloat32 returns the nearest E5M2 floating-point number for x and
+// the accuracy of the conversion, using round half to even and the given
+// saturation mode.
+func NewE5M2FromFloat32(x float32, sat Saturation) (E5M2, big.Accuracy) {
+	return new(Context).NewE5M2FromFloat32(x, sat)
+}
+
+// NewE5M2FromFloat64 returns the nearest E5M2 floating-point number for x and
+// the accuracy of the conversion, using round half to even and the given
+// saturation mode.
+func NewE5M2FromFloat64(x float64, sat Saturation) (E5M2, big.Accuracy) {
+	return new(Context).NewE5M2FromFloat64(x, sat)
+}
+
+// NewE5M2FromBig returns the nearest E5M2 floating-point number for x and the
+// accuracy of the conversion, using round half to even and the given
+// saturation mode.
+func NewE5M2FromBig(x *big.Float, sat Saturation) (E5M2, big.Accuracy) {
+	return new(Context).NewE5M2FromBig(x, sat)
+}
+
+// NewE5M2FromFloat32 returns the nearest E5M2 floating-point number for x and
+// the accuracy of the conversion, as for NewE5M2FromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewE5M2FromFloat32(x float32, sat Saturation) (E5M2, big.Accuracy) {
+	ctx.signaling32(x)
+	return ctx.newE5M2FromFloat64(float64(x), sat)
+}
+
+// NewE5M2FromFloat64 returns the nearest E5M2 floating-point number for x and
+// the accuracy of the conversion, as for NewE5M2FromFloat64. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewE5M2FromFloat64(x float64, sat Saturation) (E5M2, big.Accuracy) {
+	ctx.signaling64(x)
+	return ctx.newE5M2FromFloat64(x, sat)
+}
+
+// NewE5M2FromBig returns the nearest E5M2 floating-point number for x and the
+// accuracy of the conversion, as for NewE5M2FromBig. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) NewE5M2FromBig(x *big.Float, sat Saturation) (E5M2, big.Accuracy) {
+	bits, acc := ctx.encode(e5m2, x, sat)
+	return E5M2{bits: bits}, acc
+}
+
+// newE5M2FromFloat64 returns the nearest E5M2 floating-point number for x and
+// the accuracy of the conversion. The exception flags raised by rounding are
+// recorded in ctx.
+func (ctx *Context) newE5M2FromFloat64(x float64, sat Saturation) (E5M2, big.Accuracy) {
+	// +-NaN
+	if math.IsNaN(x) {
+		if math.Signbit(x) {
+			// -NaN
+			return E5M2NegNaN, big.Exact
+		}
+		// +NaN
+		return E5M2NaN, big.Exact
+	}
+	return ctx.NewE5M2FromBig(big.NewFloat(x), sat)
+}
+
+// Bits returns the E5M2 binary representation of f.
+func (f E5M2) Bits() uint8 {
+	return f.bits
+}
+
+// Float32 returns the float32 value of f, which is exact unless f is
+// Not-a-Number.
+func (f E5M2) Float32() (float32, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if x.Signbit() {
+			return float32(-math.NaN()), big.Exact
+		}
+		return float32(math.NaN()), big.Exact
+	}
+	return x.Float32()
+}
+
+// Float64 returns the float64 value of f, which is exact unless f is
+// Not-a-Number.
+func (f E5M2) Float64() (float64, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if x.Signbit() {
+			return -math.NaN(), big.Exact
+		}
+		return math.NaN(), big.Exact
+	}
+	return x.Float64()
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number.
+func (f E5M2) Big() (x *big.Float, nan bool) {
+	return decode(e5m2, f.bits)
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f E5M2) Signbit() bool {
+	// first bit is sign bit: 0b10000000
+	return f.bits&0x80 != 0
+}
+
+// Exp returns the exponent of f.
+func (f E5M2) Exp() int {
+	// 5 bit exponent: 0b01111100
+	return int(f.bits & 0x7C >> 2)
+}
+
+// Frac returns the fraction of f.
+func (f E5M2) Frac() uint8 {
+	// 2 bit fraction: 0b00000011
+	return f.bits & 0x03
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary to identify f uniquely.
+func (f E5M2) Text(fmt byte, prec int) string {
+	x, nan := f.Big()
+	return text(e5m2, x, nan, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which identifies f
+// uniquely, as for f.Text('g', -1).
+func (f E5M2) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f E5M2) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
@@ -0,0 +1,138 @@
+package fp8
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float/binary16"
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewE5M2FromBits(t *testing.T) {
+	golden := []struct {
+		bits uint8
+		want float64
+	}{
+		// Special numbers.
+		// 0 00000 00 = 0
+		{bits: 0x00, want: 0},
+		// 1 00000 00 = -0
+		{bits: 0x80, want: math.Copysign(0, -1)},
+		// 0 11111 00 = +Inf
+		{bits: 0x7C, want: math.Inf(1)},
+		// 1 11111 00 = -Inf
+		{bits: 0xFC, want: math.Inf(-1)},
+		// 0 11111 01 = +NaN
+		{bits: 0x7D, want: math.NaN()},
+		// 1 11111 11 = -NaN
+		{bits: 0xFF, want: -math.NaN()},
+
+		// from: OCP 8-bit Floating Point Specification (OFP8), table 2.
+		// 0 11110 11 = 57344 (largest normal number)
+		{bits: 0x7B, want: 57344},
+		// 0 00001 00 = 2^-14 (smallest normal number)
+		{bits: 0x04, want: 0x1p-14},
+		// 0 00000 11 = 0.75 * 2^-14 (largest subnormal number)
+		{bits: 0x03, want: 0x1.8p-15},
+		// 0 00000 01 = 2^-16 (smallest subnormal number)
+		{bits: 0x01, want: 0x1p-16},
+		// 0 01111 00 = 1
+		{bits: 0x3C, want: 1},
+		// 1 10000 10 = -3
+		{bits: 0xC2, want: -3},
+	}
+	for _, g := range golden {
+		f := NewE5M2FromBits(g.bits)
+		got, acc := f.Float64()
+		if math.Float64bits(got) != math.Float64bits(g.want) && !(math.IsNaN(got) && math.IsNaN(g.want) && math.Signbit(got) == math.Signbit(g.want)) {
+			t.Errorf("0x%02X: number mismatch; expected %v, got %v", g.bits, g.want, got)
+		}
+		if acc != big.Exact {
+			t.Errorf("0x%02X: accuracy mismatch; expected %v, got %v", g.bits, big.Exact, acc)
+		}
+	}
+}
+
+func TestNewE5M2FromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		sat  Saturation
+		want uint8
+		acc  big.Accuracy
+	}{
+		// Special numbers.
+		{in: math.NaN(), sat: NonSaturating, want: 0x7E, acc: big.Exact},
+		{in: -math.NaN(), sat: Saturating, want: 0xFE, acc: big.Exact},
+		{in: math.Inf(1), sat: NonSaturating, want: 0x7C, acc: big.Exact},
+		{in: math.Inf(-1), sat: NonSaturating, want: 0xFC, acc: big.Exact},
+		{in: math.Inf(1), sat: Saturating, want: 0x7B, acc: big.Below},
+		{in: math.Inf(-1), sat: Saturating, want: 0xFB, acc: big.Above},
+
+		// Rounding.
+		// 1.125 is halfway between 1 and 1.25; ties to even.
+		{in: 1.125, sat: NonSaturating, want: 0x3C, acc: big.Below},
+		// 1.375 is halfway between 1.25 and 1.5; ties to even.
+		{in: 1.375, sat: NonSaturating, want: 0x3E, acc: big.Above},
+
+		// Overflow; 61440 is halfway between 57344 and 65536, which would be
+		// +Inf.
+		{in: 61439, sat: NonSaturating, want: 0x7B, acc: big.Below},
+		{in: 61440, sat: NonSaturating, want: 0x7C, acc: big.Above},
+		{in: -61440, sat: NonSaturating, want: 0xFC, acc: big.Below},
+		{in: 61440, sat: Saturating, want: 0x7B, acc: big.Below},
+		{in: -1e6, sat: Saturating, want: 0xFB, acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc := NewE5M2FromFloat64(g.in, g.sat)
+		if f.Bits() != g.want || acc != g.acc {
+			t.Errorf("%v (saturation %d): mismatch; expected 0x%02X (%v), got 0x%02X (%v)", g.in, g.sat, g.want, g.acc, f.Bits(), acc)
+		}
+	}
+}
+
+func TestE5M2Flags(t *testing.T) {
+	golden := []struct {
+		in   float64
+		sat  Saturation
+		want fenv.Flags
+	}{
+		{in: math.Inf(1), sat: NonSaturating},
+		{in: 1e6, sat: NonSaturating, want: fenv.Overflow | fenv.Inexact},
+		{in: -1e6, sat: Saturating, want: fenv.Overflow | fenv.Inexact},
+		// Saturated infinity.
+		{in: math.Inf(-1), sat: Saturating, want: fenv.Invalid},
+		{in: 0x1p-18, sat: NonSaturating, want: fenv.Underflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		ctx := new(Context)
+		ctx.NewE5M2FromFloat64(g.in, g.sat)
+		if g.want != ctx.Flags {
+			t.Errorf("%v (saturation %d): flags mismatch; expected %v, got %v", g.in, g.sat, g.want, ctx.Flags)
+		}
+	}
+}
+
+func TestE5M2Binary16(t *testing.T) {
+	// E5M2 has the exponent range of binary16, and its values are the upper
+	// 8 bits of their binary16 representation.
+	for bits := 0; bits <= math.MaxUint16; bits++ {
+		h := binary16.NewFromBits(uint16(bits))
+		x, nan := h.Big()
+		if nan {
+			continue
+		}
+		f, acc := NewE5M2FromBig(x, NonSaturating)
+		if bits&0xFF == 0 {
+			if f.Bits() != uint8(bits>>8) || acc != big.Exact {
+				t.Errorf("0x%04X: mismatch; expected 0x%02X (Exact), got 0x%02X (%v)", bits, bits>>8, f.Bits(), acc)
+			}
+			continue
+		}
+		// Round the lower 8 bits to nearest even.
+		want := uint8((bits + 0x7F + bits>>8&1) >> 8)
+		if f.Bits() != want {
+			t.Errorf("0x%04X: mismatch; expected 0x%02X, got 0x%02X (%v)", bits, want, f.Bits(), acc)
+		}
+	}
+}
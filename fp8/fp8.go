@@ -0,0 +1,100 @@
+// Package fp8 implements encoding and decoding of the 8-bit floating-point
+// formats E4M3 and E5M2 of the OCP 8-bit Floating Point Specification (OFP8).
+//
+// https://www.opencompute.org/documents/ocp-8-bit-floating-point-specification-ofp8-revision-1-0-2023-12-01-pdf-1
+package fp8
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/ieee754"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Saturation specifies the handling of values too large in magnitude for the
+// destination format, on conversion to an 8-bit floating-point number.
+type Saturation uint8
+
+// Saturation modes.
+const (
+	// NonSaturating converts values too large in magnitude to +-Inf for E5M2
+	// and to NaN for E4M3, which has no infinities.
+	NonSaturating Saturation = iota
+	// Saturating converts values too large in magnitude, including +-Inf, to
+	// the largest finite number of the same sign.
+	Saturating
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised. Conversions
+// from 8-bit floating-point numbers are exact, and are performed without a
+// context.
+//
+// Values too large in magnitude raise the overflow exception also when
+// saturated. Infinities converted to NaN (E4M3) or saturated to the largest
+// finite number raise the invalid operation exception.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// encode returns the binary representation in the 8-bit floating-point format
+// of the non-NaN x, rounded to nearest even, and the accuracy of the
+// conversion. The exception flags raised are recorded in ctx.
+func (ctx *Context) encode(format ieee754.Format, x *big.Float, sat Saturation) (uint8, big.Accuracy) {
+	c := &ieee754.Context{Tininess: ctx.Tininess}
+	bits, acc := c.Encode(format, x)
+	if sat == Saturating {
+		if y, nan := format.Decode(bits); nan || y.IsInf() {
+			bits, _ = format.Encode(format.MaxValue())
+			acc = big.Below
+			if x.Signbit() {
+				bits.SetBit(bits, 7, 1)
+				acc = big.Above
+			}
+			if x.IsInf() {
+				c.Flags |= fenv.Invalid
+			}
+		}
+	}
+	ctx.Flags |= c.Flags
+	return uint8(bits.Uint64()), acc
+}
+
+// signaling32 records in ctx the invalid operation exception if x is a
+// signaling NaN.
+func (ctx *Context) signaling32(x float32) {
+	if payload.Signaling32(x) {
+		ctx.Flags |= fenv.Invalid
+	}
+}
+
+// signaling64 records in ctx the invalid operation exception if x is a
+// signaling NaN.
+func (ctx *Context) signaling64(x float64) {
+	if math.IsNaN(x) && math.Float64bits(x)&0x0008000000000000 == 0 {
+		ctx.Flags |= fenv.Invalid
+	}
+}
+
+// decode returns the multi-precision floating-point number representation of
+// the binary representation bits in the 8-bit floating-point format, and a
+// boolean indicating whether it is Not-a-Number.
+func decode(format ieee754.Format, bits uint8) (x *big.Float, nan bool) {
+	return format.Decode(big.NewInt(int64(bits)))
+}
+
+// text converts the floating-point number x of the 8-bit floating-point format
+// to a string, as for strconv.FormatFloat.
+func text(format ieee754.Format, x *big.Float, nan bool, fmt byte, prec int) string {
+	if nan {
+		return "NaN"
+	}
+	return strconv.FormatBig(x, fmt, prec, format.FracBits, format.Emin())
+}
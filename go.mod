@@ -1,3 +1,3 @@
 module github.com/mewmew/float
 
-go 1.13
+go 1.14
@@ -0,0 +1,184 @@
+package hfp
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// emin specifies the exponent of the smallest normalized number;
+// 0.1_16 * 16^-64 = 2^emin.
+const emin = -4*bias - 4
+
+// ### [ Short ] ###############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0.
+func (f Short) Cmp(g Short) int {
+	return f.Big().Cmp(g.Big())
+}
+
+// Less reports whether f < g.
+func (f Short) Less(g Short) bool {
+	return f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is true for -0 and +0.
+func (f Short) Equal(g Short) bool {
+	return f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with -0 ordered before +0. Numbers
+// of the same value but different representations (e.g. unnormalized numbers)
+// are ordered as equal.
+func (f Short) TotalOrder(g Short) bool {
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), f.Cmp(g))
+}
+
+// Class returns the class of f.
+func (f Short) Class() float.Class {
+	return order.Class(f.Big(), emin)
+}
+
+// IsNaN reports whether f is Not-a-Number; always false, as HFP has no NaN.
+func (f Short) IsNaN() bool {
+	return false
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as HFP has no
+// infinities.
+func (f Short) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; i.e. a non-zero number of
+// magnitude below the smallest normalized number, which is not normalized.
+func (f Short) IsSubnormal() bool {
+	return f.Class().IsSubnormal()
+}
+
+// IsFinite reports whether f is neither infinite nor NaN; always true.
+func (f Short) IsFinite() bool {
+	return true
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f Short) IsZero() bool {
+	return f.Frac() == 0
+}
+
+// ### [ Long ] ################################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0.
+func (f Long) Cmp(g Long) int {
+	return f.Big().Cmp(g.Big())
+}
+
+// Less reports whether f < g.
+func (f Long) Less(g Long) bool {
+	return f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is true for -0 and +0.
+func (f Long) Equal(g Long) bool {
+	return f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with -0 ordered before +0. Numbers
+// of the same value but different representations (e.g. unnormalized numbers)
+// are ordered as equal.
+func (f Long) TotalOrder(g Long) bool {
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), f.Cmp(g))
+}
+
+// Class returns the class of f.
+func (f Long) Class() float.Class {
+	return order.Class(f.Big(), emin)
+}
+
+// IsNaN reports whether f is Not-a-Number; always false, as HFP has no NaN.
+func (f Long) IsNaN() bool {
+	return false
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as HFP has no
+// infinities.
+func (f Long) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; i.e. a non-zero number of
+// magnitude below the smallest normalized number, which is not normalized.
+func (f Long) IsSubnormal() bool {
+	return f.Class().IsSubnormal()
+}
+
+// IsFinite reports whether f is neither infinite nor NaN; always true.
+func (f Long) IsFinite() bool {
+	return true
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f Long) IsZero() bool {
+	return f.Frac() == 0
+}
+
+// ### [ Extended ] ############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g; -0
+// is equal to +0.
+func (f Extended) Cmp(g Extended) int {
+	return f.Big().Cmp(g.Big())
+}
+
+// Less reports whether f < g.
+func (f Extended) Less(g Extended) bool {
+	return f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is true for -0 and +0.
+func (f Extended) Equal(g Extended) bool {
+	return f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with -0 ordered before +0. Numbers
+// of the same value but different representations (e.g. unnormalized numbers)
+// are ordered as equal.
+func (f Extended) TotalOrder(g Extended) bool {
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), f.Cmp(g))
+}
+
+// Class returns the class of f.
+func (f Extended) Class() float.Class {
+	return order.Class(f.Big(), emin)
+}
+
+// IsNaN reports whether f is Not-a-Number; always false, as HFP has no NaN.
+func (f Extended) IsNaN() bool {
+	return false
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as HFP has no
+// infinities.
+func (f Extended) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; i.e. a non-zero number of
+// magnitude below the smallest normalized number, which is not normalized.
+func (f Extended) IsSubnormal() bool {
+	return f.Class().IsSubnormal()
+}
+
+// IsFinite reports whether f is neither infinite nor NaN; always true.
+func (f Extended) IsFinite() bool {
+	return true
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f Extended) IsZero() bool {
+	return f.frac().Sign() == 0
+}
@@ -0,0 +1,117 @@
+package hfp
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClassShort(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint32
+		class float.Class
+	}{
+		{bits: 0xFFFFFFFF, class: float.NegativeNormal},
+		{bits: 0xC1100000, class: float.NegativeNormal},
+		{bits: 0x80100000, class: float.NegativeNormal},
+		{bits: 0x80000001, class: float.NegativeSubnormal},
+		{bits: 0x80000000, class: float.NegativeZero},
+		{bits: 0x00000000, class: float.PositiveZero},
+		{bits: 0x00000001, class: float.PositiveSubnormal},
+		{bits: 0x000FFFFF, class: float.PositiveSubnormal},
+		{bits: 0x00100000, class: float.PositiveNormal},
+		{bits: 0x41100000, class: float.PositiveNormal},
+		{bits: 0x7FFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewShortFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%08X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%08X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestClassLong(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint64
+		class float.Class
+	}{
+		{bits: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{bits: 0xC110000000000000, class: float.NegativeNormal},
+		{bits: 0x8010000000000000, class: float.NegativeNormal},
+		{bits: 0x8000000000000001, class: float.NegativeSubnormal},
+		{bits: 0x8000000000000000, class: float.NegativeZero},
+		{bits: 0x0000000000000000, class: float.PositiveZero},
+		{bits: 0x0000000000000001, class: float.PositiveSubnormal},
+		{bits: 0x000FFFFFFFFFFFFF, class: float.PositiveSubnormal},
+		{bits: 0x0010000000000000, class: float.PositiveNormal},
+		{bits: 0x4110000000000000, class: float.PositiveNormal},
+		{bits: 0x7FFFFFFFFFFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewLongFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestClassExtended(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		a     uint64
+		b     uint64
+		class float.Class
+	}{
+		{a: 0xFFFFFFFFFFFFFFFF, b: 0x00FFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{a: 0xC110000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x8010000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x8000000000000000, b: 0x0000000000000001, class: float.NegativeSubnormal},
+		{a: 0x8000000000000000, b: 0x0000000000000000, class: float.NegativeZero},
+		{a: 0x0000000000000000, b: 0x0000000000000000, class: float.PositiveZero},
+		{a: 0x0000000000000000, b: 0x0000000000000001, class: float.PositiveSubnormal},
+		{a: 0x000FFFFFFFFFFFFF, b: 0x00FFFFFFFFFFFFFF, class: float.PositiveSubnormal},
+		{a: 0x0010000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x4110000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x7FFFFFFFFFFFFFFF, b: 0x00FFFFFFFFFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewExtendedFromBits(a.a, a.b)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X 0x%016X: class mismatch; expected %v, got %v", a.a, a.b, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X 0x%016X: predicate mismatch for class %v", a.a, a.b, a.class)
+		}
+	}
+}
+
+func TestCmpUnnormalized(t *testing.T) {
+	golden := []struct {
+		f, g  Short
+		class float.Class
+	}{
+		// 1
+		{f: NewShortFromBits(0x42010000), g: NewShortFromBits(0x41100000), class: float.PositiveNormal},
+		// 16^-65
+		{f: NewShortFromBits(0x01010000), g: NewShortFromBits(0x00100000), class: float.PositiveNormal},
+		// 16^-69
+		{f: NewShortFromBits(0x81000001), g: NewShortFromBits(0x80000010), class: float.NegativeSubnormal},
+	}
+	for _, g := range golden {
+		if got := g.f.Class(); g.class != got {
+			t.Errorf("0x%08X: class mismatch; expected %v, got %v", g.f.Bits(), g.class, got)
+		}
+		if !g.f.Equal(g.g) || !g.f.TotalOrder(g.g) || !g.g.TotalOrder(g.f) {
+			t.Errorf("0x%08X: expected equal to 0x%08X", g.f.Bits(), g.g.Bits())
+		}
+	}
+}
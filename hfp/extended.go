@@ -0,0 +1,195 @@
+package hfp
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Positive and negative largest finite number and zero of the extended format.
+var (
+	// +0x0.FFFFFFFFFFFFFFFFFFFFFFFFFFFFp252 (about 7.237e+75)
+	ExtendedMax = Extended{a: 0x7FFFFFFFFFFFFFFF, b: 0x71FFFFFFFFFFFFFF}
+	// -0x0.FFFFFFFFFFFFFFFFFFFFFFFFFFFFp252
+	ExtendedNegMax = Extended{a: 0xFFFFFFFFFFFFFFFF, b: 0xF1FFFFFFFFFFFFFF}
+	// +zero
+	ExtendedZero = Extended{a: 0x0000000000000000, b: 0x0000000000000000}
+	// -zero
+	ExtendedNegZero = Extended{a: 0x8000000000000000, b: 0x8000000000000000}
+)
+
+// Extended is a floating-point number in IBM HFP extended format.
+//
+// The extended format consists of two long format numbers. The high-order part
+// holds the sign, the characteristic and the 14 most significant hexadecimal
+// digits of the fraction, and the low-order part the 14 least significant
+// digits. The sign and characteristic of the low-order part are ignored on
+// decoding, and encoded as the sign of the high-order part and its
+// characteristic minus 14 (modulo 128), except for zero.
+type Extended struct {
+	// High-order part.
+	//
+	//    1 bit:   sign
+	//    7 bits:  characteristic
+	//    56 bits: fraction (high-order digits)
+	a uint64
+	// Low-order part.
+	//
+	//    1 bit:   sign (ignored)
+	//    7 bits:  characteristic (ignored)
+	//    56 bits: fraction (low-order digits)
+	b uint64
+}
+
+// NewExtendedFromBits returns the floating-point number corresponding to the
+// HFP extended binary representation, given as the high-order and low-order
+// parts.
+func NewExtendedFromBits(a, b uint64) Extended {
+	return Extended{a: a, b: b}
+}
+
+// NewExtendedFromFloat32 returns the nearest HFP extended floating-point number
+// for x and the accuracy of the conversion, as for NewExtendedFromBig.
+func NewExtendedFromFloat32(x float32) (Extended, big.Accuracy, error) {
+	return new(Context).NewExtendedFromFloat32(x)
+}
+
+// NewExtendedFromFloat64 returns the nearest HFP extended floating-point number
+// for x and the accuracy of the conversion, as for NewExtendedFromBig. If x is
+// NaN, the result is +0 and the error is ErrNaN.
+func NewExtendedFromFloat64(x float64) (Extended, big.Accuracy, error) {
+	return new(Context).NewExtendedFromFloat64(x)
+}
+
+// NewExtendedFromBig returns the nearest normalized HFP extended floating-point
+// number for x and the accuracy of the conversion, using round half to even.
+//
+// If x is too large in magnitude, including +-Inf, the result is the largest
+// finite number of the same sign and the error is ErrOverflow. If x is too small
+// in magnitude, the result is the nearest of zero and the smallest normalized
+// number.
+func NewExtendedFromBig(x *big.Float) (Extended, big.Accuracy, error) {
+	return new(Context).NewExtendedFromBig(x)
+}
+
+// NewExtendedFromFloat32 returns the nearest HFP extended floating-point number
+// for x and the accuracy of the conversion, as for NewExtendedFromFloat32. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) NewExtendedFromFloat32(x float32) (Extended, big.Accuracy, error) {
+	return ctx.NewExtendedFromFloat64(float64(x))
+}
+
+// NewExtendedFromFloat64 returns the nearest HFP extended floating-point number
+// for x and the accuracy of the conversion, as for NewExtendedFromFloat64. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) NewExtendedFromFloat64(x float64) (Extended, big.Accuracy, error) {
+	if ctx.nan(x) {
+		return ExtendedZero, big.Exact, ErrNaN
+	}
+	return ctx.NewExtendedFromBig(big.NewFloat(x))
+}
+
+// NewExtendedFromBig returns the nearest normalized HFP extended floating-point
+// number for x and the accuracy of the conversion, as for NewExtendedFromBig.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) NewExtendedFromBig(x *big.Float) (Extended, big.Accuracy, error) {
+	neg, char, frac, acc, err := ctx.encode(extended, x)
+	lo := new(big.Int).SetUint64(0x00FFFFFFFFFFFFFF)
+	lo.And(lo, frac)
+	hi := frac.Rsh(frac, 56)
+	var a, b uint64
+	if hi.Sign() != 0 || lo.Sign() != 0 {
+		a = uint64(char)<<56 | hi.Uint64()
+		b = uint64(char-14)&0x7F<<56 | lo.Uint64()
+	}
+	if neg {
+		a |= 0x8000000000000000
+		b |= 0x8000000000000000
+	}
+	return Extended{a: a, b: b}, acc, err
+}
+
+// Bits returns the HFP extended binary representation of f, as the high-order
+// and low-order parts.
+func (f Extended) Bits() (a, b uint64) {
+	return f.a, f.b
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion.
+func (f Extended) Float32() (float32, big.Accuracy) {
+	return new(Context).ExtendedFloat32(f)
+}
+
+// ExtendedFloat32 returns the float32 value nearest to f and the accuracy of
+// the conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) ExtendedFloat32(f Extended) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion.
+func (f Extended) Float64() (float64, big.Accuracy) {
+	return new(Context).ExtendedFloat64(f)
+}
+
+// ExtendedFloat64 returns the float64 value nearest to f and the accuracy of
+// the conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) ExtendedFloat64(f Extended) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f.
+func (f Extended) Big() *big.Float {
+	return extended.decode(f.Signbit(), f.Exp(), f.frac())
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f Extended) Signbit() bool {
+	// first bit is sign bit
+	return f.a&0x8000000000000000 != 0
+}
+
+// Exp returns the characteristic of f; i.e. the base 16 exponent biased by 64.
+func (f Extended) Exp() int {
+	// 7 bit characteristic
+	return int(f.a & 0x7F00000000000000 >> 56)
+}
+
+// Frac returns the fraction of f, as its high-order and low-order 56 bits.
+func (f Extended) Frac() (uint64, uint64) {
+	// 2x 56 bit fraction
+	return f.a & 0x00FFFFFFFFFFFFFF, f.b & 0x00FFFFFFFFFFFFFF
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary for NewExtendedFromBig to return the value of f.
+func (f Extended) Text(fmt byte, prec int) string {
+	return extended.text(f.Big(), f.Exp(), f.frac(), fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which converts back
+// to f, as for f.Text('g', -1).
+func (f Extended) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Extended) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// frac returns the 112-bit fraction of f.
+func (f Extended) frac() *big.Int {
+	hi, lo := f.Frac()
+	frac := new(big.Int).SetUint64(hi)
+	frac.Lsh(frac, 56)
+	return frac.Or(frac, new(big.Int).SetUint64(lo))
+}
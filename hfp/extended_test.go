@@ -0,0 +1,81 @@
+package hfp
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewExtendedFromBig(t *testing.T) {
+	golden := []struct {
+		in   string
+		a, b uint64
+		acc  big.Accuracy
+		err  error
+	}{
+		{in: "0", a: 0x0000000000000000, b: 0x0000000000000000, acc: big.Exact},
+		{in: "-0", a: 0x8000000000000000, b: 0x8000000000000000, acc: big.Exact},
+		{in: "1", a: 0x4110000000000000, b: 0x3300000000000000, acc: big.Exact},
+		{in: "-118.625", a: 0xC276A00000000000, b: 0xB400000000000000, acc: big.Exact},
+		// 0.1 = 0x0.1999999999999999999999999999999...p0
+		{in: "0.1", a: 0x4019999999999999, b: 0x329999999999999A, acc: big.Above},
+		// pi = 0x3.243F6A8885A308D313198A2E0370734...p0
+		{in: "3.14159265358979323846264338327950288", a: 0x413243F6A8885A30, b: 0x338D313198A2E037, acc: big.Below},
+		// Characteristic of the low-order part modulo 128.
+		{in: "0x1p-240", a: 0x0510000000000000, b: 0x7700000000000000, acc: big.Exact},
+		{in: "1e76", a: 0x7FFFFFFFFFFFFFFF, b: 0x71FFFFFFFFFFFFFF, acc: big.Below, err: ErrOverflow},
+		{in: "-1e76", a: 0xFFFFFFFFFFFFFFFF, b: 0xF1FFFFFFFFFFFFFF, acc: big.Above, err: ErrOverflow},
+	}
+	for _, g := range golden {
+		x, _, err := big.ParseFloat(g.in, 0, 1000, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, acc, err := NewExtendedFromBig(x)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%v: error mismatch; expected %v, got %v", g.in, g.err, err)
+		}
+		if a, b := f.Bits(); a != g.a || b != g.b {
+			t.Errorf("%v: bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.in, g.a, g.b, a, b)
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+	// The sign and characteristic of the low-order part are ignored.
+	f := NewExtendedFromBits(0x4110000000000000, 0xFF00000000000001)
+	want := "0x1.000000000000000000000000001p+00"
+	if got := f.Text('x', -1); got != want {
+		t.Errorf("text mismatch; expected %q, got %q", want, got)
+	}
+	if _, _, err := NewExtendedFromFloat64(math.NaN()); err != ErrNaN {
+		t.Errorf("NaN: error mismatch; expected %v, got %v", ErrNaN, err)
+	}
+}
+
+func TestExtendedRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Random normalized numbers.
+		a := r.Uint64() | 0x0010000000000000
+		char := a >> 56 & 0x7F
+		b := a&0x8000000000000000 | (char-14)&0x7F<<56 | r.Uint64()&0x00FFFFFFFFFFFFFF
+		f := NewExtendedFromBits(a, b)
+		got, acc, err := NewExtendedFromBig(f.Big())
+		if got != f || acc != big.Exact || err != nil {
+			t.Errorf("0x%016X%016X: round-trip mismatch; got 0x%016X%016X (%v, %v)", a, b, got.a, got.b, acc, err)
+		}
+		// The shortest decimal representation converts back to f.
+		s := f.String()
+		x, _, err := big.ParseFloat(s, 10, 1000, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if got, _, _ := NewExtendedFromBig(x); got != f {
+			t.Errorf("%q: string round-trip mismatch; expected 0x%016X%016X, got 0x%016X%016X", s, a, b, got.a, got.b)
+		}
+	}
+}
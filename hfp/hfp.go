@@ -0,0 +1,247 @@
+// Package hfp implements encoding and decoding of IBM System/360 hexadecimal
+// floating-point (HFP) numbers, in short (32-bit), long (64-bit) and extended
+// (128-bit) format.
+//
+// An HFP number is (-1)^sign * 0.frac * 16^(char-64), with a 7-bit
+// characteristic and a fraction of 6, 14 or 28 hexadecimal digits. As the
+// fraction is normalized to a non-zero leading hexadecimal digit, up to three
+// leading bits of the fraction are zero and the precision varies between 21
+// and 24 bits, 53 and 56 bits, and 109 and 112 bits, respectively.
+//
+// HFP has neither infinities nor NaN. Conversions of values too large in
+// magnitude report ErrOverflow, and conversions of NaN report ErrNaN. The IEEE
+// 754 exception flags raised by conversions are recorded in a Context.
+//
+// https://en.wikipedia.org/wiki/IBM_hexadecimal_floating-point
+package hfp
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Errors returned by conversions to HFP numbers.
+var (
+	// ErrOverflow is returned on conversion of values too large in magnitude,
+	// including +-Inf; the result is the largest finite number of the same
+	// sign.
+	ErrOverflow = errors.New("hfp: value out of range")
+	// ErrNaN is returned on conversion of NaN; the result is +0.
+	ErrNaN = errors.New("hfp: NaN has no representation")
+)
+
+const (
+	// bias of the characteristic.
+	bias = 64
+	// maxChar specifies the largest characteristic.
+	maxChar = 1<<7 - 1
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+//
+// A non-zero result is tiny if below the smallest normalized number, 16^-65, in
+// magnitude; values too small in magnitude are converted to the nearest of zero
+// and the smallest normalized number. Values too large in magnitude raise the
+// overflow exception, and infinities and NaN the invalid operation exception.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// nan records in ctx the invalid operation exception if x is NaN, and reports
+// whether x is NaN.
+func (ctx *Context) nan(x float64) bool {
+	if math.IsNaN(x) {
+		ctx.Flags |= fenv.Invalid
+		return true
+	}
+	return false
+}
+
+// float32 returns the float32 value nearest to the HFP number x and the
+// accuracy of the conversion. The exception flags raised are recorded in ctx.
+func (ctx *Context) float32(x *big.Float) (float32, big.Accuracy) {
+	y, acc := x.Float32()
+	if x.Sign() != 0 {
+		ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+	}
+	return y, acc
+}
+
+// float64 returns the float64 value nearest to the HFP number x and the
+// accuracy of the conversion. The exception flags raised are recorded in ctx.
+func (ctx *Context) float64(x *big.Float) (float64, big.Accuracy) {
+	y, acc := x.Float64()
+	if x.Sign() != 0 {
+		ctx.Flags |= rounding.Flags(x, acc, 53, -1022, 1023, big.ToNearestEven, ctx.Tininess)
+	}
+	return y, acc
+}
+
+// format is an HFP format.
+type format struct {
+	// Number of hexadecimal digits of the fraction.
+	digits uint
+}
+
+// HFP formats.
+var (
+	// short format.
+	short = format{digits: 6}
+	// long format.
+	long = format{digits: 14}
+	// extended format.
+	extended = format{digits: 28}
+)
+
+// fracBits returns the number of bits of the fraction.
+func (f format) fracBits() uint {
+	return 4 * f.digits
+}
+
+// encode returns the sign, characteristic and fraction of the normalized HFP
+// number nearest to the non-NaN x, using round half to even, and the accuracy
+// of the conversion. The exception flags raised are recorded in ctx.
+//
+// Values too large in magnitude are converted to the largest finite number of
+// the same sign, and the error is ErrOverflow. Values too small in magnitude
+// are converted to the nearest of zero and the smallest normalized number.
+func (ctx *Context) encode(f format, x *big.Float) (neg bool, char int, frac *big.Int, acc big.Accuracy, err error) {
+	neg = x.Signbit()
+	frac = new(big.Int)
+	switch {
+	case x.IsInf():
+		ctx.Flags |= fenv.Invalid
+		return f.overflow(neg)
+	case x.Sign() == 0:
+		return neg, 0, frac, big.Exact, nil
+	}
+	// |x| < 2^be = 16^e, with e the exponent of 0.frac.
+	abs := new(big.Float).Abs(x)
+	be := abs.MantExp(nil)
+	e := be / 4
+	if be%4 > 0 {
+		e++
+	}
+	if e+bias < 0 {
+		// Underflow; round to zero unless above half of the smallest
+		// normalized number, 16^-65.
+		ctx.Flags |= fenv.Inexact
+		if ctx.Tininess == fenv.BeforeRounding || f.tiny(abs) {
+			ctx.Flags |= fenv.Underflow
+		}
+		half := new(big.Float).SetMantExp(big.NewFloat(1), -4*(bias+1)-1)
+		if abs.Cmp(half) > 0 {
+			frac.Lsh(big.NewInt(1), f.fracBits()-4)
+			return neg, 0, frac, signAcc(neg, big.Above), nil
+		}
+		return neg, 0, frac, signAcc(neg, big.Below), nil
+	}
+	// Round to the bits of the fraction following the leading zero bits.
+	prec := f.fracBits() - uint(4*e-be)
+	y := new(big.Float).SetMode(big.ToNearestEven).SetPrec(prec).Set(abs)
+	acc = signAcc(neg, y.Acc())
+	if y.MantExp(nil) > 4*e {
+		// Carry into a new leading hexadecimal digit.
+		e++
+	}
+	if e+bias > maxChar {
+		ctx.Flags |= fenv.Overflow | fenv.Inexact
+		return f.overflow(neg)
+	}
+	if acc != big.Exact {
+		ctx.Flags |= fenv.Inexact
+	}
+	frac, _ = y.SetMantExp(y, int(f.fracBits())-4*e).Int(frac)
+	return neg, e + bias, frac, acc, nil
+}
+
+// tiny reports whether the magnitude abs, below the smallest normalized number
+// 16^-65, remains below it after rounding with an unbounded exponent range.
+func (f format) tiny(abs *big.Float) bool {
+	// Only values with a leading hexadecimal digit of 8 to F below 16^-65 may
+	// round to it, using the full precision of the fraction.
+	y := new(big.Float).SetMode(big.ToNearestEven).SetPrec(f.fracBits()).Set(abs)
+	return y.MantExp(nil) <= -4*(bias+1)
+}
+
+// overflow returns the sign, characteristic and fraction of the largest finite
+// number of the given sign, the accuracy of its conversion from a value too
+// large in magnitude, and ErrOverflow.
+func (f format) overflow(neg bool) (bool, int, *big.Int, big.Accuracy, error) {
+	frac := new(big.Int).Lsh(big.NewInt(1), f.fracBits())
+	frac.Sub(frac, big.NewInt(1))
+	return neg, maxChar, frac, signAcc(neg, big.Below), ErrOverflow
+}
+
+// decode returns the value of the HFP number with the given sign,
+// characteristic and fraction; the fraction need not be normalized.
+func (f format) decode(neg bool, char int, frac *big.Int) *big.Float {
+	// (-1)^sign * frac * 16^(char-64-digits)
+	x := new(big.Float).SetPrec(f.fracBits()).SetMode(big.ToNearestEven)
+	x.SetInt(frac)
+	x.SetMantExp(x, 4*(char-bias)-int(f.fracBits()))
+	if neg {
+		x.Neg(x)
+	}
+	return x
+}
+
+// text converts the HFP number x, with the given characteristic and fraction,
+// to a string, as for strconv.FormatFloat. The special precision -1 uses the
+// smallest number of digits necessary to convert back to x.
+func (f format) text(x *big.Float, char int, frac *big.Int, fmt byte, prec int) string {
+	// Normalize the fraction.
+	frac = new(big.Int).Set(frac)
+	for frac.Sign() != 0 && frac.BitLen() <= int(f.fracBits())-4 && char > 0 {
+		frac.Lsh(frac, 4)
+		char--
+	}
+	if frac.Sign() == 0 {
+		return strconv.FormatBigRange(x, x, x, true, fmt, prec)
+	}
+	// Halfway points to the neighbouring numbers; the lower neighbour of a
+	// fraction of 0x100..0 has a 16 times smaller unit in the last place.
+	lsb := 4*(char-bias) - int(f.fracBits())
+	half := new(big.Float).SetMantExp(big.NewFloat(1), lsb-1)
+	lowerHalf := half
+	if frac.BitLen() == int(f.fracBits())-3 && isPow2(frac) && char > 0 {
+		lowerHalf = new(big.Float).SetMantExp(half, -4)
+	}
+	lower := new(big.Float).SetPrec(f.fracBits() + 8)
+	upper := new(big.Float).SetPrec(f.fracBits() + 8)
+	if x.Signbit() {
+		lower.Sub(x, half)
+		upper.Add(x, lowerHalf)
+	} else {
+		lower.Sub(x, lowerHalf)
+		upper.Add(x, half)
+	}
+	// The bounds round to x only if the fraction is even, using round half to
+	// even.
+	inclusive := frac.Bit(0) == 0
+	return strconv.FormatBigRange(x, lower, upper, inclusive, fmt, prec)
+}
+
+// isPow2 reports whether x is a power of two.
+func isPow2(x *big.Int) bool {
+	return x.Sign() > 0 && x.TrailingZeroBits() == uint(x.BitLen()-1)
+}
+
+// signAcc returns the accuracy acc of the magnitude of a number, adjusted for
+// the sign of the number.
+func signAcc(neg bool, acc big.Accuracy) big.Accuracy {
+	if neg {
+		return -acc
+	}
+	return acc
+}
@@ -0,0 +1,50 @@
+package hfp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestFlags(t *testing.T) {
+	tenth, _, _ := NewLongFromFloat64(0.1)
+	golden := []struct {
+		name          string
+		op            func(ctx *Context)
+		after, before fenv.Flags
+	}{
+		{name: "NewShortFromFloat64(0.5)", op: func(ctx *Context) { ctx.NewShortFromFloat64(0.5) }},
+		{name: "NewShortFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewShortFromFloat64(0.1) }, after: fenv.Inexact, before: fenv.Inexact},
+		// The leading hexadecimal digit of 0.1 has three leading zero bits.
+		{name: "NewLongFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewLongFromFloat64(0.1) }},
+		// Values too large in magnitude are saturated.
+		{name: "NewShortFromFloat64(0x1p252)", op: func(ctx *Context) { ctx.NewShortFromFloat64(0x1p252) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "NewLongFromFloat64(-Inf)", op: func(ctx *Context) { ctx.NewLongFromFloat64(math.Inf(-1)) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "NewExtendedFromFloat32(NaN)", op: func(ctx *Context) { ctx.NewExtendedFromFloat32(float32(math.NaN())) }, after: fenv.Invalid, before: fenv.Invalid},
+		// Values below the smallest normalized number, 16^-65.
+		{name: "NewShortFromFloat64(0x1p-270)", op: func(ctx *Context) { ctx.NewShortFromFloat64(0x1p-270) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "NewShortFromFloat64(0x1.FFFFFCp-261)", op: func(ctx *Context) { ctx.NewShortFromFloat64(0x1.FFFFFCp-261) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 16^-65 - 2^-287 rounds to 16^-65, and is tiny only before rounding.
+		{name: "NewShortFromFloat64(0x1.FFFFFF8p-261)", op: func(ctx *Context) { ctx.NewShortFromFloat64(0x1.FFFFFF8p-261) }, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "NewLongFromFloat64(0x1.FFFFFF8p-261)", op: func(ctx *Context) { ctx.NewLongFromFloat64(0x1.FFFFFF8p-261) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// Conversions to float32 and float64.
+		{name: "ShortFloat64(max)", op: func(ctx *Context) { ctx.ShortFloat64(ShortMax) }},
+		{name: "ShortFloat32(max)", op: func(ctx *Context) { ctx.ShortFloat32(ShortMax) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "LongFloat32(0.1)", op: func(ctx *Context) { ctx.LongFloat32(tenth) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "LongFloat64(0.1)", op: func(ctx *Context) { ctx.LongFloat64(tenth) }},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			g.op(ctx)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
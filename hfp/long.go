@@ -0,0 +1,164 @@
+package hfp
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Positive and negative largest finite number and zero of the long format.
+var (
+	// +0x0.FFFFFFFFFFFFFFp252 (about 7.237e+75)
+	LongMax = Long{bits: 0x7FFFFFFFFFFFFFFF}
+	// -0x0.FFFFFFFFFFFFFFp252
+	LongNegMax = Long{bits: 0xFFFFFFFFFFFFFFFF}
+	// +zero
+	LongZero = Long{bits: 0x0000000000000000}
+	// -zero
+	LongNegZero = Long{bits: 0x8000000000000000}
+)
+
+// Long is a floating-point number in IBM HFP long format.
+type Long struct {
+	// Sign, characteristic and fraction.
+	//
+	//    1 bit:   sign
+	//    7 bits:  characteristic
+	//    56 bits: fraction
+	bits uint64
+}
+
+// NewLongFromBits returns the floating-point number corresponding to the HFP
+// long binary representation.
+func NewLongFromBits(bits uint64) Long {
+	return Long{bits: bits}
+}
+
+// NewLongFromFloat32 returns the nearest HFP long floating-point number for x
+// and the accuracy of the conversion, as for NewLongFromBig.
+func NewLongFromFloat32(x float32) (Long, big.Accuracy, error) {
+	return new(Context).NewLongFromFloat32(x)
+}
+
+// NewLongFromFloat64 returns the nearest HFP long floating-point number for x
+// and the accuracy of the conversion, as for NewLongFromBig. If x is NaN, the
+// result is +0 and the error is ErrNaN.
+func NewLongFromFloat64(x float64) (Long, big.Accuracy, error) {
+	return new(Context).NewLongFromFloat64(x)
+}
+
+// NewLongFromBig returns the nearest normalized HFP long floating-point number
+// for x and the accuracy of the conversion, using round half to even.
+//
+// If x is too large in magnitude, including +-Inf, the result is the largest
+// finite number of the same sign and the error is ErrOverflow. If x is too small
+// in magnitude, the result is the nearest of zero and the smallest normalized
+// number.
+func NewLongFromBig(x *big.Float) (Long, big.Accuracy, error) {
+	return new(Context).NewLongFromBig(x)
+}
+
+// NewLongFromFloat32 returns the nearest HFP long floating-point number for x
+// and the accuracy of the conversion, as for NewLongFromFloat32. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) NewLongFromFloat32(x float32) (Long, big.Accuracy, error) {
+	return ctx.NewLongFromFloat64(float64(x))
+}
+
+// NewLongFromFloat64 returns the nearest HFP long floating-point number for x
+// and the accuracy of the conversion, as for NewLongFromFloat64. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) NewLongFromFloat64(x float64) (Long, big.Accuracy, error) {
+	if ctx.nan(x) {
+		return LongZero, big.Exact, ErrNaN
+	}
+	return ctx.NewLongFromBig(big.NewFloat(x))
+}
+
+// NewLongFromBig returns the nearest normalized HFP long floating-point number
+// for x and the accuracy of the conversion, as for NewLongFromBig. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) NewLongFromBig(x *big.Float) (Long, big.Accuracy, error) {
+	neg, char, frac, acc, err := ctx.encode(long, x)
+	bits := uint64(char)<<56 | frac.Uint64()
+	if neg {
+		bits |= 0x8000000000000000
+	}
+	return Long{bits: bits}, acc, err
+}
+
+// Bits returns the HFP long binary representation of f.
+func (f Long) Bits() uint64 {
+	return f.bits
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion.
+func (f Long) Float32() (float32, big.Accuracy) {
+	return new(Context).LongFloat32(f)
+}
+
+// LongFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) LongFloat32(f Long) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion.
+func (f Long) Float64() (float64, big.Accuracy) {
+	return new(Context).LongFloat64(f)
+}
+
+// LongFloat64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) LongFloat64(f Long) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f.
+func (f Long) Big() *big.Float {
+	return long.decode(f.Signbit(), f.Exp(), new(big.Int).SetUint64(f.Frac()))
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f Long) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x8000000000000000 != 0
+}
+
+// Exp returns the characteristic of f; i.e. the base 16 exponent biased by 64.
+func (f Long) Exp() int {
+	// 7 bit characteristic
+	return int(f.bits & 0x7F00000000000000 >> 56)
+}
+
+// Frac returns the fraction of f.
+func (f Long) Frac() uint64 {
+	// 56 bit fraction
+	return f.bits & 0x00FFFFFFFFFFFFFF
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary for NewLongFromBig to return the value of f.
+func (f Long) Text(fmt byte, prec int) string {
+	return long.text(f.Big(), f.Exp(), new(big.Int).SetUint64(f.Frac()), fmt, prec)
+}
+
+// String returns the longest decimal representation of f which converts back
+// to f, as for f.Text('g', -1).
+func (f Long) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Long) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
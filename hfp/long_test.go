@@ -0,0 +1,103 @@
+package hfp
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewLongFromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint64
+		acc  big.Accuracy
+		err  error
+	}{
+		{in: 0, want: 0x0000000000000000, acc: big.Exact},
+		{in: math.Copysign(0, -1), want: 0x8000000000000000, acc: big.Exact},
+		{in: 1, want: 0x4110000000000000, acc: big.Exact},
+		{in: -118.625, want: 0xC276A00000000000, acc: big.Exact},
+		// 0.1 = 0x0.1999999999999Ap0
+		{in: 0.1, want: 0x401999999999999A, acc: big.Exact},
+		// pi = 0x3.243F6A8885A3p0
+		{in: math.Pi, want: 0x413243F6A8885A30, acc: big.Exact},
+		{in: math.MaxFloat64, want: 0x7FFFFFFFFFFFFFFF, acc: big.Below, err: ErrOverflow},
+		{in: math.Inf(-1), want: 0xFFFFFFFFFFFFFFFF, acc: big.Above, err: ErrOverflow},
+		{in: math.NaN(), want: 0x0000000000000000, acc: big.Exact, err: ErrNaN},
+		{in: math.SmallestNonzeroFloat64, want: 0x0000000000000000, acc: big.Below},
+	}
+	for _, g := range golden {
+		f, acc, err := NewLongFromFloat64(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%v: error mismatch; expected %v, got %v", g.in, g.err, err)
+		}
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestNewLongFromBig(t *testing.T) {
+	golden := []struct {
+		in   string
+		want uint64
+		acc  big.Accuracy
+	}{
+		// 0.1 = 0x0.1999999999999999...p0
+		{in: "0.1", want: 0x401999999999999A, acc: big.Above},
+		// pi = 0x3.243F6A8885A308D3...p0
+		{in: "3.14159265358979323846264338327950288", want: 0x413243F6A8885A31, acc: big.Above},
+		// 1 + 2^-53 = 1 (tie, round to even)
+		{in: "0x1.00000000000008p0", want: 0x4110000000000000, acc: big.Below},
+		// -(8 + 2^-53) = -8 (tie, round to even)
+		{in: "-0x8.00000000000008p0", want: 0xC180000000000000, acc: big.Above},
+		// -(8 + 3*2^-53) = -(8 + 2^-51) (tie, round to even)
+		{in: "-0x8.00000000000018p0", want: 0xC180000000000002, acc: big.Below},
+	}
+	for _, g := range golden {
+		x, _, err := big.ParseFloat(g.in, 0, 1000, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, acc, err := NewLongFromBig(x)
+		if err != nil {
+			t.Errorf("%v: unexpected error; %v", g.in, err)
+		}
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestLongRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		f := NewLongFromBits(r.Uint64())
+		if f.Frac()&0x00F0000000000000 == 0 {
+			// Unnormalized number.
+			continue
+		}
+		got, acc, err := NewLongFromBig(f.Big())
+		if got != f || acc != big.Exact || err != nil {
+			t.Errorf("0x%016X: round-trip mismatch; got 0x%016X (%v, %v)", f.Bits(), got.Bits(), acc, err)
+		}
+		// The shortest decimal representation converts back to f.
+		s := f.String()
+		x, _, err := big.ParseFloat(s, 10, 1000, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if got, _, _ := NewLongFromBig(x); got != f {
+			t.Errorf("%q: string round-trip mismatch; expected 0x%016X, got 0x%016X", s, f.Bits(), got.Bits())
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package hfp
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Positive and negative largest finite number and zero of the short format.
+var (
+	// +0x0.FFFFFFp252 (about 7.237e+75)
+	ShortMax = Short{bits: 0x7FFFFFFF}
+	// -0x0.FFFFFFp252
+	ShortNegMax = Short{bits: 0xFFFFFFFF}
+	// +zero
+	ShortZero = Short{bits: 0x00000000}
+	// -zero
+	ShortNegZero = Short{bits: 0x80000000}
+)
+
+// Short is a floating-point number in IBM HFP short format.
+type Short struct {
+	// Sign, characteristic and fraction.
+	//
+	//    1 bit:   sign
+	//    7 bits:  characteristic
+	//    24 bits: fraction
+	bits uint32
+}
+
+// NewShortFromBits returns the floating-point number corresponding to the HFP
+// short binary representation.
+func NewShortFromBits(bits uint32) Short {
+	return Short{bits: bits}
+}
+
+// NewShortFromFloat32 returns the nearest HFP short floating-point number for x
+// and the accuracy of the conversion, as for NewShortFromBig.
+func NewShortFromFloat32(x float32) (Short, big.Accuracy, error) {
+	return new(Context).NewShortFromFloat32(x)
+}
+
+// NewShortFromFloat64 returns the nearest HFP short floating-point number for x
+// and the accuracy of the conversion, as for NewShortFromBig. If x is NaN, the
+// result is +0 and the error is ErrNaN.
+func NewShortFromFloat64(x float64) (Short, big.Accuracy, error) {
+	return new(Context).NewShortFromFloat64(x)
+}
+
+// NewShortFromBig returns the nearest normalized HFP short floating-point number
+// for x and the accuracy of the conversion, using round half to even.
+//
+// If x is too large in magnitude, including +-Inf, the result is the largest
+// finite number of the same sign and the error is ErrOverflow. If x is too small
+// in magnitude, the result is the nearest of zero and the smallest normalized
+// number.
+func NewShortFromBig(x *big.Float) (Short, big.Accuracy, error) {
+	return new(Context).NewShortFromBig(x)
+}
+
+// NewShortFromFloat32 returns the nearest HFP short floating-point number for x
+// and the accuracy of the conversion, as for NewShortFromFloat32. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) NewShortFromFloat32(x float32) (Short, big.Accuracy, error) {
+	return ctx.NewShortFromFloat64(float64(x))
+}
+
+// NewShortFromFloat64 returns the nearest HFP short floating-point number for x
+// and the accuracy of the conversion, as for NewShortFromFloat64. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) NewShortFromFloat64(x float64) (Short, big.Accuracy, error) {
+	if ctx.nan(x) {
+		return ShortZero, big.Exact, ErrNaN
+	}
+	return ctx.NewShortFromBig(big.NewFloat(x))
+}
+
+// NewShortFromBig returns the nearest normalized HFP short floating-point
+// number for x and the accuracy of the conversion, as for NewShortFromBig. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) NewShortFromBig(x *big.Float) (Short, big.Accuracy, error) {
+	neg, char, frac, acc, err := ctx.encode(short, x)
+	bits := uint32(char)<<24 | uint32(frac.Uint64())
+	if neg {
+		bits |= 0x80000000
+	}
+	return Short{bits: bits}, acc, err
+}
+
+// Bits returns the HFP short binary representation of f.
+func (f Short) Bits() uint32 {
+	return f.bits
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion.
+func (f Short) Float32() (float32, big.Accuracy) {
+	return new(Context).ShortFloat32(f)
+}
+
+// ShortFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) ShortFloat32(f Short) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value of f. The conversion is always exact.
+func (f Short) Float64() (float64, big.Accuracy) {
+	return new(Context).ShortFloat64(f)
+}
+
+// ShortFloat64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) ShortFloat64(f Short) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f.
+func (f Short) Big() *big.Float {
+	return short.decode(f.Signbit(), f.Exp(), big.NewInt(int64(f.Frac())))
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f Short) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x80000000 != 0
+}
+
+// Exp returns the characteristic of f; i.e. the base 16 exponent biased by 64.
+func (f Short) Exp() int {
+	// 7 bit characteristic
+	return int(f.bits & 0x7F000000 >> 24)
+}
+
+// Frac returns the fraction of f.
+func (f Short) Frac() uint32 {
+	// 24 bit fraction
+	return f.bits & 0x00FFFFFF
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary for NewShortFromBig to return the value of f.
+func (f Short) Text(fmt byte, prec int) string {
+	return short.text(f.Big(), f.Exp(), big.NewInt(int64(f.Frac())), fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which converts back
+// to f, as for f.Text('g', -1).
+func (f Short) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Short) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
@@ -0,0 +1,158 @@
+package hfp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewShortFromBits(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		want float64
+	}{
+		// 0 0000000 000000 = 0
+		{bits: 0x00000000, want: 0},
+		// 1 0000000 000000 = -0
+		{bits: 0x80000000, want: math.Copysign(0, -1)},
+		// 0 1000001 100000 = 1
+		{bits: 0x41100000, want: 1},
+		// from: https://en.wikipedia.org/wiki/IBM_hexadecimal_floating-point
+		// 1 1000010 76A000 = -118.625
+		{bits: 0xC276A000, want: -118.625},
+		// 0 1111111 FFFFFF = 0x0.FFFFFFp252 (largest number)
+		{bits: 0x7FFFFFFF, want: 0x0.FFFFFFp252},
+		// 0 0000000 100000 = 16^-65 (smallest normalized number)
+		{bits: 0x00100000, want: 0x1p-260},
+		// 0 0000000 000001 = 16^-70 (smallest unnormalized number)
+		{bits: 0x00000001, want: 0x1p-280},
+		// 0 1000000 010000 = 16^-2 (unnormalized number)
+		{bits: 0x40010000, want: 0x1p-8},
+	}
+	for _, g := range golden {
+		f := NewShortFromBits(g.bits)
+		got, acc := f.Float64()
+		if math.Float64bits(got) != math.Float64bits(g.want) || acc != big.Exact {
+			t.Errorf("0x%08X: number mismatch; expected %v, got %v (%v)", g.bits, g.want, got, acc)
+		}
+	}
+}
+
+func TestNewShortFromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint32
+		acc  big.Accuracy
+		err  error
+	}{
+		{in: 0, want: 0x00000000, acc: big.Exact},
+		{in: math.Copysign(0, -1), want: 0x80000000, acc: big.Exact},
+		{in: 1, want: 0x41100000, acc: big.Exact},
+		{in: -118.625, want: 0xC276A000, acc: big.Exact},
+		// 0.1 = 0x0.1999999999999Ap0
+		{in: 0.1, want: 0x4019999A, acc: big.Above},
+		// pi = 0x3.243F6A8885A3p0
+		{in: math.Pi, want: 0x413243F7, acc: big.Above},
+		// -1/3 = -0x0.55555555555554p0
+		{in: -1. / 3, want: 0xC0555555, acc: big.Above},
+		// 1 + 2^-21 = 1 (tie, round to even)
+		{in: 1 + 0x1p-21, want: 0x41100000, acc: big.Below},
+		// 1 + 3*2^-21 = 1 + 2^-19 (tie, round to even)
+		{in: 1 + 0x3p-21, want: 0x41100002, acc: big.Above},
+		// 0x0.FFFFFF8p4 = 16 (carry into a new hexadecimal digit)
+		{in: 0x0.FFFFFF8p4, want: 0x42100000, acc: big.Above},
+		// 0x0.FFFFFF7p4 has a precision of 24 bits.
+		{in: 0x0.FFFFFF7p4, want: 0x41FFFFFF, acc: big.Below},
+		// 0x0.1000008p4 has a precision of 21 bits.
+		{in: 0x0.1000008p4, want: 0x41100000, acc: big.Below},
+
+		// Overflow.
+		{in: 0x0.FFFFFF8p252, want: 0x7FFFFFFF, acc: big.Below, err: ErrOverflow},
+		{in: -1e76, want: 0xFFFFFFFF, acc: big.Above, err: ErrOverflow},
+		{in: math.Inf(1), want: 0x7FFFFFFF, acc: big.Below, err: ErrOverflow},
+		{in: math.Inf(-1), want: 0xFFFFFFFF, acc: big.Above, err: ErrOverflow},
+		{in: math.NaN(), want: 0x00000000, acc: big.Exact, err: ErrNaN},
+		// 0x0.FFFFFF7p252 is the largest value which does not overflow.
+		{in: 0x0.FFFFFF7p252, want: 0x7FFFFFFF, acc: big.Below},
+
+		// Underflow.
+		// 2^-261 = 0 (tie, round to even)
+		{in: 0x1p-261, want: 0x00000000, acc: big.Below},
+		{in: -0x1.000001p-261, want: 0x80100000, acc: big.Below},
+		{in: 0x1p-300, want: 0x00000000, acc: big.Below},
+		// 0x0.FFFFFF8p-260 rounds up to the smallest normalized number.
+		{in: 0x0.FFFFFF8p-260, want: 0x00100000, acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc, err := NewShortFromFloat64(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%v: error mismatch; expected %v, got %v", g.in, g.err, err)
+		}
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestShortRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		f := NewShortFromBits(r.Uint32())
+		if f.Frac()&0xF00000 == 0 {
+			// Unnormalized number.
+			continue
+		}
+		got, acc, err := NewShortFromBig(f.Big())
+		if got != f || acc != big.Exact || err != nil {
+			t.Errorf("0x%08X: round-trip mismatch; got 0x%08X (%v, %v)", f.Bits(), got.Bits(), acc, err)
+		}
+		// The shortest decimal representation converts back to f.
+		s := f.String()
+		x, _, err := big.ParseFloat(s, 10, 1000, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if got, _, _ := NewShortFromBig(x); got != f {
+			t.Errorf("%q: string round-trip mismatch; expected 0x%08X, got 0x%08X", s, f.Bits(), got.Bits())
+		}
+	}
+}
+
+func TestShortText(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		fmt  byte
+		prec int
+		want string
+	}{
+		{bits: 0x00000000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x80000000, fmt: 'g', prec: -1, want: "-0"},
+		{bits: 0x41100000, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0xC276A000, fmt: 'g', prec: -1, want: "-118.625"},
+		{bits: 0x4019999A, fmt: 'g', prec: -1, want: "0.1"},
+		{bits: 0x4019999A, fmt: 'e', prec: 10, want: "1.0000002384e-01"},
+		{bits: 0x413243F7, fmt: 'g', prec: -1, want: "3.141593"},
+		{bits: 0x413243F7, fmt: 'x', prec: -1, want: "0x1.921fb8p+01"},
+		{bits: 0x7FFFFFFF, fmt: 'g', prec: -1, want: "7.237005e+75"},
+		{bits: 0x00100000, fmt: 'g', prec: -1, want: "5.397605e-79"},
+		// Unnormalized numbers below the smallest normalized number.
+		{bits: 0x00000001, fmt: 'g', prec: -1, want: "5e-85"},
+		{bits: 0x00000001, fmt: 'e', prec: 5, want: "5.14756e-85"},
+	}
+	for _, g := range golden {
+		f := NewShortFromBits(g.bits)
+		if got := f.Text(g.fmt, g.prec); got != g.want {
+			t.Errorf("0x%08X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+	if got, want := fmt.Sprintf("%8.3f|", NewShortFromBits(0xC276A000)), "-118.625|"; got != want {
+		t.Errorf("output mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,402 @@
+// Package ieee754 implements encoding and decoding of floating-point numbers in
+// binary interchange formats of IEEE 754 style, with parameterised exponent
+// and fraction widths, exponent bias and encoding of special values.
+//
+// https://en.wikipedia.org/wiki/IEEE_754#Basic_and_interchange_formats
+package ieee754
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/order"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+// Special specifies the encoding of infinities and Not-a-Number values.
+type Special uint8
+
+// Encodings of infinities and Not-a-Number values.
+const (
+	// InfNaN encodes +-Inf and NaN using the maximum exponent, with a zero
+	// and a non-zero fraction respectively, as in IEEE 754.
+	InfNaN Special = iota
+	// NaNOnly encodes NaN using the maximum exponent and fraction, and has no
+	// infinities. All other encodings are finite (e.g. OCP FP8 E4M3).
+	NaNOnly
+	// NoSpecial has neither infinities nor NaN; all encodings are finite
+	// (e.g. OCP MX FP6 and FP4).
+	NoSpecial
+)
+
+// Format describes a binary floating-point format of IEEE 754 style. A
+// floating-point number consists of, from the most significant bit:
+//
+//	1 bit:            sign
+//	ExpBits bits:     exponent
+//	1 bit:            lead bit of the significand (if ExplicitLead is set)
+//	FracBits bits:    fraction
+//
+// Normalized numbers have an exponent between 1 and the maximum exponent,
+// and represent the value (-1)^sign * 2^(exponent-Bias) * 1.fraction_2.
+// Denormalized numbers have a zero exponent and represent the value
+// (-1)^sign * 2^(1-Bias) * 0.fraction_2.
+//
+// If ExplicitLead is set, the lead bit is stored rather than implied by the
+// exponent, as in the x86 extended precision format, and numbers with a lead
+// bit inconsistent with the exponent are decoded according to the stored lead
+// bit.
+type Format struct {
+	// Number of exponent bits.
+	ExpBits uint
+	// Number of fraction bits (excluding the lead bit of the significand).
+	FracBits uint
+	// Lead bit of the significand is stored explicitly.
+	ExplicitLead bool
+	// Exponent bias.
+	Bias int
+	// Encoding of infinities and Not-a-Number values.
+	Special Special
+}
+
+// Binary returns the IEEE 754 binary interchange format with the given number
+// of exponent and fraction bits; i.e. with an implicit lead bit, an exponent
+// bias of 2^(expBits-1)-1 and infinities and NaN.
+func Binary(expBits, fracBits uint) Format {
+	return Format{
+		ExpBits:  expBits,
+		FracBits: fracBits,
+		Bias:     1<<(expBits-1) - 1,
+		Special:  InfNaN,
+	}
+}
+
+// Floating-point formats.
+var (
+	// IEEE 754 half precision.
+	Binary16 = Binary(5, 10)
+	// IEEE 754 single precision.
+	Binary32 = Binary(8, 23)
+	// IEEE 754 double precision.
+	Binary64 = Binary(11, 52)
+	// IEEE 754 quadruple precision.
+	Binary128 = Binary(15, 112)
+	// IEEE 754 octuple precision.
+	Binary256 = Binary(19, 236)
+	// bfloat16.
+	BFloat16 = Binary(8, 7)
+	// x86 extended precision.
+	X86Extended = Format{ExpBits: 15, FracBits: 63, ExplicitLead: true, Bias: 16383, Special: InfNaN}
+)
+
+// Width returns the number of bits of floating-point numbers in the format f.
+func (f Format) Width() uint {
+	return 1 + f.ExpBits + f.sigBits()
+}
+
+// Precision returns the number of bits of the significand, including the lead
+// bit, of normalized numbers in the format f.
+func (f Format) Precision() uint {
+	return f.FracBits + 1
+}
+
+// Emin returns the exponent of the smallest normalized number in the format f.
+func (f Format) Emin() int {
+	return 1 - f.Bias
+}
+
+// Emax returns the exponent of the largest finite number in the format f.
+func (f Format) Emax() int {
+	return f.maxExp() - f.Bias
+}
+
+// MaxValue returns the largest finite number in the format f.
+func (f Format) MaxValue() *big.Float {
+	// 2^(emax+1) - 2^(emax+1-precision), or one unit in the last place less if
+	// the largest fraction is NaN.
+	p := f.Precision()
+	m := new(big.Int).Lsh(big.NewInt(1), p)
+	m.Sub(m, big.NewInt(1))
+	if f.Special == NaNOnly {
+		m.Sub(m, big.NewInt(1))
+	}
+	x := new(big.Float).SetPrec(p).SetMode(big.ToNearestEven).SetInt(m)
+	return x.SetMantExp(x, f.Emax()-int(p-1))
+}
+
+// SmallestNonzero returns the smallest positive denormalized number in the
+// format f.
+func (f Format) SmallestNonzero() *big.Float {
+	x := new(big.Float).SetPrec(f.Precision()).SetMode(big.ToNearestEven).SetInt64(1)
+	return x.SetMantExp(x, f.Emin()-int(f.FracBits))
+}
+
+// Inf returns the binary representation of +Inf if sign >= 0 and -Inf
+// otherwise, and a boolean indicating whether the format f has infinities.
+func (f Format) Inf(sign int) (bits *big.Int, ok bool) {
+	if f.Special != InfNaN {
+		return nil, false
+	}
+	bits = new(big.Int).Lsh(big.NewInt(int64(f.maxExp()+1)), f.sigBits())
+	if f.ExplicitLead {
+		bits.SetBit(bits, int(f.FracBits), 1)
+	}
+	return f.setSign(bits, sign < 0), true
+}
+
+// NaN returns the binary representation of a quiet NaN, with the sign bit set
+// if neg is set, and a boolean indicating whether the format f has NaN. For
+// formats with infinities, the fraction of the quiet NaN has only the most
+// significant bit set.
+func (f Format) NaN(neg bool) (bits *big.Int, ok bool) {
+	switch f.Special {
+	case InfNaN:
+		bits = new(big.Int).Lsh(big.NewInt(int64(f.maxExp()+1)), f.sigBits())
+		if f.ExplicitLead {
+			bits.SetBit(bits, int(f.FracBits), 1)
+		}
+		bits.SetBit(bits, int(f.FracBits)-1, 1)
+	case NaNOnly:
+		bits = new(big.Int).Lsh(big.NewInt(1), f.Width()-1)
+		bits.Sub(bits, big.NewInt(1))
+	default:
+		return nil, false
+	}
+	return f.setSign(bits, neg), true
+}
+
+// Decode returns the multi-precision floating-point number representation of
+// the binary representation bits in the format f, and a boolean indicating
+// whether it is Not-a-Number. The sign of a NaN is given by the sign of x.
+func (f Format) Decode(bits *big.Int) (x *big.Float, nan bool) {
+	x = big.NewFloat(0)
+	x.SetPrec(f.Precision())
+	x.SetMode(big.ToNearestEven)
+
+	neg := bits.Bit(int(f.Width()-1)) != 0
+	exp := int(new(big.Int).Rsh(bits, f.sigBits()).Uint64() & (1<<f.ExpBits - 1))
+	mant := new(big.Int).Set(bits)
+	mant.And(mant, mask(f.sigBits()))
+	frac := new(big.Int).And(mant, mask(f.FracBits))
+	if !f.ExplicitLead && exp != 0 {
+		// Add the implicit lead bit of normalized numbers.
+		mant.SetBit(mant, int(f.FracBits), 1)
+	}
+
+	switch {
+	case f.Special == InfNaN && exp == f.maxExp()+1:
+		// Inf or NaN; with the lead bit set if explicit.
+		if frac.Sign() == 0 && mant.Bit(int(f.FracBits)) != 0 {
+			x.SetInf(neg)
+			return x, false
+		}
+		if neg {
+			x.Neg(x)
+		}
+		return x, true
+	case f.Special == NaNOnly && exp == f.maxExp() && frac.Cmp(mask(f.FracBits)) == 0 && (!f.ExplicitLead || mant.Bit(int(f.FracBits)) != 0):
+		if neg {
+			x.Neg(x)
+		}
+		return x, true
+	case exp == 0:
+		// Zero or denormalized number; with the exponent of the smallest
+		// normalized number.
+		exp = 1
+	}
+
+	// (-1)^sign * 2^(exp-bias) * mant_2 / 2^fracbits
+	x.SetInt(mant)
+	x.SetMantExp(x, exp-f.Bias-int(f.FracBits))
+	if neg {
+		x.Neg(x)
+	}
+	return x, false
+}
+
+// Class returns the class of the floating-point number with the binary
+// representation bits in the format f. For formats with infinities, NaNs with
+// the most significant bit of the fraction set are quiet and other NaNs are
+// signaling; NaNs of formats without infinities are quiet.
+func (f Format) Class(bits *big.Int) float.Class {
+	x, nan := f.Decode(bits)
+	if nan {
+		if f.Special == InfNaN && bits.Bit(int(f.FracBits)-1) == 0 {
+			return float.SignalingNaN
+		}
+		return float.QuietNaN
+	}
+	return order.Class(x, f.Emin())
+}
+
+// Encode returns the binary representation in the format f of the number
+// nearest to x, using round half to even, and the accuracy of the conversion.
+//
+// Values too large in magnitude for the format f are encoded as +-Inf; or as
+// NaN if the format has no infinities; or as the largest finite number of the
+// appropriate sign if the format has neither infinities nor NaN. NaN results
+// are reported as exact.
+func (f Format) Encode(x *big.Float) (bits *big.Int, acc big.Accuracy) {
+	return new(Context).Encode(f, x)
+}
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the encodings performed in the context. The zero
+// value detects tininess after rounding and has no flags raised. Decoding is
+// exact, and is performed without a context.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// Encode returns the binary representation in the format f of the number
+// nearest to x and the accuracy of the conversion, as for f.Encode. The
+// exception flags raised are recorded in ctx; an infinity encoded in a format
+// without infinities raises the invalid operation exception.
+func (ctx *Context) Encode(f Format, x *big.Float) (bits *big.Int, acc big.Accuracy) {
+	bits, acc, flags := f.encode(x, ctx.Tininess)
+	ctx.Flags |= flags
+	return bits, acc
+}
+
+// encode returns the binary representation in the format f of the number
+// nearest to x, the accuracy of the conversion and the exception flags raised,
+// with tininess detected as specified by tininess.
+func (f Format) encode(x *big.Float, tininess fenv.Tininess) (*big.Int, big.Accuracy, fenv.Flags) {
+	neg := x.Signbit()
+	switch {
+	case x.IsInf():
+		bits, acc := f.overflow(neg, big.Exact)
+		if f.Special != InfNaN {
+			return bits, acc, fenv.Invalid
+		}
+		return bits, acc, 0
+	case x.Sign() == 0:
+		return f.setSign(new(big.Int), neg), big.Exact, 0
+	}
+
+	// Round to the precision available at the exponent of x; denormalized
+	// numbers have fewer significant bits.
+	p := int(f.Precision())
+	emin := f.Emin()
+	exp := x.MantExp(nil) - 1
+	prec := p
+	if exp < emin {
+		prec -= emin - exp
+	}
+	abs := new(big.Float).Abs(x)
+	if prec < 1 {
+		// |x| is less than the smallest denormalized number; round to zero
+		// unless |x| is above half of it.
+		bits := new(big.Int)
+		acc := signAcc(neg, big.Below)
+		half := f.SmallestNonzero()
+		half.SetMantExp(half, -1)
+		if abs.Cmp(half) > 0 {
+			bits.SetInt64(1)
+			acc = signAcc(neg, big.Above)
+		}
+		return f.setSign(bits, neg), acc, f.flags(x, acc, tininess)
+	}
+	y := new(big.Float).SetMode(big.ToNearestEven).SetPrec(uint(prec)).Set(abs)
+	acc := signAcc(neg, y.Acc())
+	if y.Cmp(f.MaxValue()) > 0 {
+		// Overflow.
+		bits, acc := f.overflow(neg, signAcc(neg, big.Above))
+		return bits, acc, fenv.Overflow | fenv.Inexact
+	}
+
+	// Exponent and significand.
+	exp = y.MantExp(nil) - 1
+	denormal := exp < emin
+	if denormal {
+		// Denormalized number; with the exponent of the smallest normalized
+		// number.
+		exp = emin
+	}
+	mant, _ := y.SetMantExp(y, int(f.FracBits)-exp).Int(nil)
+	if denormal {
+		exp = 0
+	} else {
+		exp += f.Bias
+		if !f.ExplicitLead {
+			// Remove the implicit lead bit.
+			mant.SetBit(mant, int(f.FracBits), 0)
+		}
+	}
+	bits := new(big.Int).Lsh(big.NewInt(int64(exp)), f.sigBits())
+	bits.Or(bits, mant)
+	return f.setSign(bits, neg), acc, f.flags(x, acc, tininess)
+}
+
+// flags returns the inexact and underflow exception flags raised by rounding
+// the finite x with the accuracy acc to the precision of the format f, with
+// tininess detected as specified by tininess. Overflow is detected by the
+// caller, as the largest finite number of formats without infinities is not
+// always the largest number of its exponent.
+func (f Format) flags(x *big.Float, acc big.Accuracy, tininess fenv.Tininess) fenv.Flags {
+	return rounding.Flags(x, acc, f.Precision(), f.Emin(), math.MaxInt32, big.ToNearestEven, tininess)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// sigBits returns the number of stored bits of the significand.
+func (f Format) sigBits() uint {
+	if f.ExplicitLead {
+		return f.FracBits + 1
+	}
+	return f.FracBits
+}
+
+// maxExp returns the maximum exponent of finite numbers.
+func (f Format) maxExp() int {
+	if f.Special == InfNaN {
+		return 1<<f.ExpBits - 2
+	}
+	return 1<<f.ExpBits - 1
+}
+
+// overflow returns the encoding of a value too large in magnitude for the
+// format f, with the sign bit set if neg is set, and the given accuracy.
+func (f Format) overflow(neg bool, acc big.Accuracy) (*big.Int, big.Accuracy) {
+	if bits, ok := f.Inf(1); ok {
+		return f.setSign(bits, neg), acc
+	}
+	if bits, ok := f.NaN(neg); ok {
+		return bits, big.Exact
+	}
+	bits, _ := f.Encode(f.MaxValue())
+	if neg {
+		acc = big.Above
+	} else {
+		acc = big.Below
+	}
+	return f.setSign(bits, neg), acc
+}
+
+// setSign sets the sign bit of bits if neg is set, and returns bits.
+func (f Format) setSign(bits *big.Int, neg bool) *big.Int {
+	if neg {
+		bits.SetBit(bits, int(f.Width()-1), 1)
+	}
+	return bits
+}
+
+// mask returns 2^n - 1.
+func mask(n uint) *big.Int {
+	m := new(big.Int).Lsh(big.NewInt(1), n)
+	return m.Sub(m, big.NewInt(1))
+}
+
+// signAcc returns the accuracy acc of a conversion of a value with the given
+// sign, which is acc of the magnitude negated if neg is set.
+func signAcc(neg bool, acc big.Accuracy) big.Accuracy {
+	if neg {
+		return -acc
+	}
+	return acc
+}
@@ -0,0 +1,367 @@
+package ieee754
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/bfloat"
+	"github.com/mewmew/float/binary128"
+	"github.com/mewmew/float/binary16"
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/float80x86"
+)
+
+func TestDecodeBinary16(t *testing.T) {
+	for bits := 0; bits <= math.MaxUint16; bits++ {
+		got, gotNaN := Binary16.Decode(big.NewInt(int64(bits)))
+		want, wantNaN := binary16.NewFromBits(uint16(bits)).Big()
+		if !sameBig(got, gotNaN, want, wantNaN) {
+			t.Errorf("0x%04X: number mismatch; expected %v (NaN=%v), got %v (NaN=%v)", bits, want, wantNaN, got, gotNaN)
+		}
+	}
+}
+
+func TestEncodeBinary16(t *testing.T) {
+	// Binary32 values within and around the binary16 range of exponents.
+	for bits := uint32(0x32000000); bits <= 0x48000000; bits += 0x1FF {
+		for _, sign := range []uint32{0, 0x80000000} {
+			x := big.NewFloat(float64(math.Float32frombits(sign | bits)))
+			testEncodeBinary16(t, x)
+		}
+	}
+	// Halfway cases between binary16 numbers, and overflow.
+	for bits := 0; bits < 0x7C00; bits++ {
+		x, _ := binary16.NewFromBits(uint16(bits)).Big()
+		y, _ := binary16.NewFromBits(uint16(bits + 1)).Big()
+		if y.IsInf() {
+			y = big.NewFloat(65536)
+		}
+		x.SetPrec(64).Add(x, y).Quo(x, big.NewFloat(2))
+		testEncodeBinary16(t, x)
+		testEncodeBinary16(t, x.Neg(x))
+	}
+}
+
+func testEncodeBinary16(t *testing.T, x *big.Float) {
+	got, gotAcc := Binary16.Encode(x)
+	want, wantAcc := binary16.NewFromBig(x)
+	if got.Uint64() != uint64(want.Bits()) || gotAcc != wantAcc {
+		t.Errorf("%v: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, want.Bits(), wantAcc, got.Uint64(), gotAcc)
+	}
+	for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+		ctx := &Context{Tininess: tininess}
+		ctx.Encode(Binary16, x)
+		want := &binary16.Context{Tininess: tininess}
+		want.NewFromBig(x)
+		if ctx.Flags != want.Flags {
+			t.Errorf("%v (%v): flags mismatch; expected %v, got %v", x, tininess, want.Flags, ctx.Flags)
+		}
+	}
+}
+
+func TestEncodeFlags(t *testing.T) {
+	// OCP FP8 E4M3 (NaNOnly), and a variant without special values.
+	e4m3 := Format{ExpBits: 4, FracBits: 3, Bias: 7, Special: NaNOnly}
+	e4m3none := e4m3
+	e4m3none.Special = NoSpecial
+	golden := []struct {
+		f             Format
+		x             float64
+		after, before fenv.Flags
+	}{
+		{f: e4m3, x: 448},
+		{f: e4m3, x: 450, after: fenv.Inexact, before: fenv.Inexact},
+		// Overflow below the next power of two.
+		{f: e4m3, x: 465, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{f: e4m3none, x: 1000, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// Infinity without infinities.
+		{f: e4m3, x: math.Inf(1), after: fenv.Invalid, before: fenv.Invalid},
+		{f: e4m3none, x: math.Inf(-1), after: fenv.Invalid, before: fenv.Invalid},
+		{f: Binary16, x: math.Inf(-1)},
+		// Underflow.
+		{f: e4m3, x: 0x1p-10, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{f: e4m3, x: 0x1p-9},
+		// 2^(-6) - 2^(-11), tiny only before rounding.
+		{f: e4m3, x: 0x1p-6 - 0x1p-11, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			ctx.Encode(g.f, big.NewFloat(g.x))
+			if want != ctx.Flags {
+				t.Errorf("%v (%v, %v): flags mismatch; expected %v, got %v", g.x, g.f.Special, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
+
+func TestBFloat16(t *testing.T) {
+	for bits := 0; bits <= math.MaxUint16; bits++ {
+		got, gotNaN := BFloat16.Decode(big.NewInt(int64(bits)))
+		want, wantNaN := bfloat.NewFromBits(uint16(bits)).Big()
+		if !sameBig(got, gotNaN, want, wantNaN) {
+			t.Errorf("0x%04X: number mismatch; expected %v (NaN=%v), got %v (NaN=%v)", bits, want, wantNaN, got, gotNaN)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		f := float64(math.Float32frombits(r.Uint32()))
+		if math.IsNaN(f) {
+			continue
+		}
+		x := big.NewFloat(f)
+		got, gotAcc := BFloat16.Encode(x)
+		want, wantAcc := bfloat.NewFromBig(x)
+		if got.Uint64() != uint64(want.Bits()) || gotAcc != wantAcc {
+			t.Errorf("%v: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, want.Bits(), wantAcc, got.Uint64(), gotAcc)
+		}
+	}
+}
+
+func TestBinary32(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		bits := r.Uint32()
+		want := math.Float32frombits(bits)
+		got, nan := Binary32.Decode(new(big.Int).SetUint64(uint64(bits)))
+		if nan != math.IsNaN(float64(want)) {
+			t.Errorf("0x%08X: NaN mismatch; expected %v, got %v", bits, !nan, nan)
+			continue
+		}
+		if nan {
+			continue
+		}
+		if f, _ := got.Float32(); math.Float32bits(f) != bits {
+			t.Errorf("0x%08X: number mismatch; expected %v, got %v", bits, want, f)
+		}
+		// Encode the float64 value nearest to a random value near want.
+		x := float64(want) * (1 + r.Float64()*0x1p-20)
+		enc, acc := Binary32.Encode(big.NewFloat(x))
+		wantEnc, wantAcc := big.NewFloat(x).Float32()
+		if enc.Uint64() != uint64(math.Float32bits(wantEnc)) || acc != wantAcc {
+			t.Errorf("%v: mismatch; expected 0x%08X (%v), got 0x%08X (%v)", x, math.Float32bits(wantEnc), wantAcc, enc.Uint64(), acc)
+		}
+	}
+}
+
+func TestBinary64(t *testing.T) {
+	golden := []struct {
+		x    float64
+		bits uint64
+	}{
+		{x: 1, bits: 0x3FF0000000000000},
+		{x: -2, bits: 0xC000000000000000},
+		{x: math.MaxFloat64, bits: 0x7FEFFFFFFFFFFFFF},
+		{x: math.SmallestNonzeroFloat64, bits: 0x0000000000000001},
+		{x: 0x1p-1022, bits: 0x0010000000000000},
+		{x: 0x1.ffffffffffffep-1023, bits: 0x000FFFFFFFFFFFFF},
+		{x: math.Inf(-1), bits: 0xFFF0000000000000},
+		{x: math.Copysign(0, -1), bits: 0x8000000000000000},
+	}
+	for _, g := range golden {
+		bits, acc := Binary64.Encode(big.NewFloat(g.x))
+		if bits.Uint64() != g.bits || acc != big.Exact {
+			t.Errorf("%v: encoding mismatch; expected 0x%016X (Exact), got 0x%016X (%v)", g.x, g.bits, bits.Uint64(), acc)
+		}
+		x, nan := Binary64.Decode(new(big.Int).SetUint64(g.bits))
+		if got, _ := x.Float64(); nan || math.Float64bits(got) != g.bits {
+			t.Errorf("0x%016X: decoding mismatch; expected %v, got %v", g.bits, g.x, x)
+		}
+	}
+}
+
+func TestBinary128(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		a, b := r.Uint64(), r.Uint64()
+		bits := new(big.Int).SetUint64(a)
+		bits.Lsh(bits, 64).Or(bits, new(big.Int).SetUint64(b))
+		got, gotNaN := Binary128.Decode(bits)
+		want, wantNaN := binary128.NewFromBits(a, b).Big()
+		if !sameBig(got, gotNaN, want, wantNaN) {
+			t.Errorf("0x%016X%016X: number mismatch; expected %v (NaN=%v), got %v (NaN=%v)", a, b, want, wantNaN, got, gotNaN)
+			continue
+		}
+		if gotNaN {
+			continue
+		}
+		// Round a value with more precision than binary128.
+		x := new(big.Float).SetPrec(200).Set(got)
+		x.Mul(x, new(big.Float).SetPrec(200).SetFloat64(1-r.Float64()*0x1p-100))
+		enc, acc := Binary128.Encode(x)
+		f, wantAcc := binary128.NewFromBig(x)
+		wa, wb := f.Bits()
+		wantEnc := new(big.Int).SetUint64(wa)
+		wantEnc.Lsh(wantEnc, 64).Or(wantEnc, new(big.Int).SetUint64(wb))
+		if enc.Cmp(wantEnc) != 0 || acc != wantAcc {
+			t.Errorf("%v: mismatch; expected 0x%032X (%v), got 0x%032X (%v)", x, wantEnc, wantAcc, enc, acc)
+		}
+	}
+}
+
+func TestX86Extended(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		se, m := uint16(r.Uint32()), r.Uint64()
+		if se&0x7FFF != 0 {
+			// Normalized numbers only; the lead bit is set.
+			m |= 1 << 63
+		} else {
+			m &^= 1 << 63
+		}
+		bits := new(big.Int).SetUint64(uint64(se))
+		bits.Lsh(bits, 64).Or(bits, new(big.Int).SetUint64(m))
+		got, gotNaN := X86Extended.Decode(bits)
+		want, wantNaN := float80x86.NewFromBits(se, m).Big()
+		if !sameBig(got, gotNaN, want, wantNaN) {
+			t.Errorf("0x%04X%016X: number mismatch; expected %v (NaN=%v), got %v (NaN=%v)", se, m, want, wantNaN, got, gotNaN)
+			continue
+		}
+		if gotNaN {
+			continue
+		}
+		enc, acc := X86Extended.Encode(got)
+		if enc.Cmp(bits) != 0 || acc != big.Exact {
+			t.Errorf("%v: mismatch; expected 0x%020X (Exact), got 0x%020X (%v)", got, bits, enc, acc)
+		}
+	}
+}
+
+func TestSpecial(t *testing.T) {
+	// OCP FP8 E4M3 (NaNOnly), and a variant without special values.
+	e4m3 := Format{ExpBits: 4, FracBits: 3, Bias: 7, Special: NaNOnly}
+	e4m3none := e4m3
+	e4m3none.Special = NoSpecial
+	golden := []struct {
+		f    Format
+		x    float64
+		bits uint64
+		acc  big.Accuracy
+	}{
+		{f: e4m3, x: 448, bits: 0x7E, acc: big.Exact},
+		{f: e4m3, x: 464, bits: 0x7E, acc: big.Below},
+		{f: e4m3, x: 465, bits: 0x7F, acc: big.Exact},
+		{f: e4m3, x: -1000, bits: 0xFF, acc: big.Exact},
+		{f: e4m3, x: math.Inf(1), bits: 0x7F, acc: big.Exact},
+		{f: e4m3, x: 0x1p-9, bits: 0x01, acc: big.Exact},
+		{f: e4m3, x: 0x1p-10, bits: 0x00, acc: big.Below},
+		{f: e4m3, x: -0x1.8p-10, bits: 0x81, acc: big.Below},
+		{f: e4m3, x: 0x1.ep-7, bits: 0x08, acc: big.Above},
+		{f: e4m3none, x: 480, bits: 0x7F, acc: big.Exact},
+		{f: e4m3none, x: 1000, bits: 0x7F, acc: big.Below},
+		{f: e4m3none, x: math.Inf(-1), bits: 0xFF, acc: big.Above},
+	}
+	for _, g := range golden {
+		bits, acc := g.f.Encode(big.NewFloat(g.x))
+		if bits.Uint64() != g.bits || acc != g.acc {
+			t.Errorf("%v (%v): encoding mismatch; expected 0x%02X (%v), got 0x%02X (%v)", g.x, g.f.Special, g.bits, g.acc, bits.Uint64(), acc)
+		}
+	}
+	if x, nan := e4m3.Decode(big.NewInt(0x7F)); !nan || x.Signbit() {
+		t.Errorf("0x7F: expected +NaN, got %v (NaN=%v)", x, nan)
+	}
+	if x, nan := e4m3none.Decode(big.NewInt(0xFF)); nan || x.String() != "-480" {
+		t.Errorf("0xFF: expected -480, got %v (NaN=%v)", x, nan)
+	}
+	if _, ok := e4m3.Inf(1); ok {
+		t.Errorf("expected no infinities in %v format", e4m3.Special)
+	}
+	if _, ok := e4m3none.NaN(false); ok {
+		t.Errorf("expected no NaN in %v format", e4m3none.Special)
+	}
+	if bits, _ := Binary16.NaN(true); bits.Uint64() != 0xFE00 {
+		t.Errorf("binary16 NaN mismatch; expected 0xFE00, got 0x%04X", bits)
+	}
+	if bits, _ := X86Extended.Inf(-1); bits.Text(16) != "ffff8000000000000000" {
+		t.Errorf("x86 extended -Inf mismatch; expected 0xFFFF8000000000000000, got 0x%X", bits)
+	}
+}
+
+func TestWide(t *testing.T) {
+	// Formats wider than 128 bits.
+	golden := []struct {
+		f    Format
+		max  string
+		bits string
+	}{
+		{f: Binary256, max: "0x1." + strings.Repeat("f", 59) + "p+262143", bits: "7fffe" + strings.Repeat("f", 59)},
+		{f: Binary(20, 491), max: "0x1." + strings.Repeat("f", 122) + "ep+524287", bits: "7ffff7" + strings.Repeat("f", 122)},
+	}
+	for _, g := range golden {
+		max := g.f.MaxValue()
+		if got := max.Text('p', 0); got != new(big.Float).SetPrec(g.f.Precision()).Set(mustParse(g.max)).Text('p', 0) {
+			t.Errorf("binary%d: max mismatch; expected %v, got %v", g.f.Width(), g.max, max.Text('x', -1))
+		}
+		bits, acc := g.f.Encode(max)
+		if bits.Text(16) != g.bits || acc != big.Exact {
+			t.Errorf("binary%d: encoding mismatch; expected 0x%s (Exact), got 0x%x (%v)", g.f.Width(), g.bits, bits, acc)
+		}
+		x, nan := g.f.Decode(bits)
+		if nan || x.Cmp(max) != 0 {
+			t.Errorf("binary%d: decoding mismatch; expected %v, got %v", g.f.Width(), max, x)
+		}
+		min := g.f.SmallestNonzero()
+		if bits, _ := g.f.Encode(min); bits.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("binary%d: smallest nonzero encoding mismatch; expected 0x1, got 0x%x", g.f.Width(), bits)
+		}
+	}
+}
+
+func TestClass(t *testing.T) {
+	e4m3 := Format{ExpBits: 4, FracBits: 3, Bias: 7, Special: NaNOnly}
+	golden := []struct {
+		f    Format
+		bits string
+		want float.Class
+	}{
+		{f: Binary16, bits: "7e00", want: float.QuietNaN},
+		{f: Binary16, bits: "fd00", want: float.SignalingNaN},
+		{f: Binary16, bits: "fc00", want: float.NegativeInfinity},
+		{f: Binary16, bits: "bc00", want: float.NegativeNormal},
+		{f: Binary16, bits: "83ff", want: float.NegativeSubnormal},
+		{f: Binary16, bits: "8000", want: float.NegativeZero},
+		{f: Binary16, bits: "0000", want: float.PositiveZero},
+		{f: Binary16, bits: "0001", want: float.PositiveSubnormal},
+		{f: Binary16, bits: "0400", want: float.PositiveNormal},
+		{f: Binary16, bits: "7c00", want: float.PositiveInfinity},
+		{f: e4m3, bits: "7f", want: float.QuietNaN},
+		{f: e4m3, bits: "7e", want: float.PositiveNormal},
+		// Pseudo-denormal and unnormal numbers, classified by value.
+		{f: X86Extended, bits: "00008000000000000000", want: float.PositiveNormal},
+		{f: X86Extended, bits: "00024000000000000000", want: float.PositiveNormal},
+		{f: X86Extended, bits: "00012000000000000000", want: float.PositiveSubnormal},
+		{f: X86Extended, bits: "7fffc000000000000000", want: float.QuietNaN},
+		{f: X86Extended, bits: "7fff8000000000000001", want: float.SignalingNaN},
+	}
+	for _, g := range golden {
+		bits, _ := new(big.Int).SetString(g.bits, 16)
+		if got := g.f.Class(bits); g.want != got {
+			t.Errorf("0x%s: class mismatch; expected %v, got %v", g.bits, g.want, got)
+		}
+	}
+}
+
+// sameBig reports whether the results x and y of Decode or Big are identical,
+// including the sign of zero and NaN.
+func sameBig(x *big.Float, xnan bool, y *big.Float, ynan bool) bool {
+	if xnan != ynan || x.Signbit() != y.Signbit() || x.Prec() != y.Prec() {
+		return false
+	}
+	return xnan || x.Cmp(y) == 0
+}
+
+func mustParse(s string) *big.Float {
+	x, _, err := big.ParseFloat(s, 0, 1000, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
@@ -0,0 +1,82 @@
+package decimal
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// IsNaN reports whether d is a quiet or signaling Not-a-Number value.
+func (d Decimal) IsNaN() bool {
+	return d.Kind == NaN || d.Kind == SNaN
+}
+
+// Cmp compares the values of the numbers d and e, which are not NaN, and
+// returns -1 if d < e, 0 if d == e and +1 if d > e; members of a cohort are
+// equal, and so are -0 and +0.
+func (d Decimal) Cmp(e Decimal) int {
+	switch {
+	case d.Kind == Inf && e.Kind == Inf && d.Neg == e.Neg:
+		return 0
+	case d.Kind == Inf:
+		if d.Neg {
+			return -1
+		}
+		return +1
+	case e.Kind == Inf:
+		if e.Neg {
+			return +1
+		}
+		return -1
+	}
+	return d.Rat().Cmp(e.Rat())
+}
+
+// Class returns the class of the number d of the format f. Finite non-zero
+// numbers are subnormal if their magnitude is below 10^(1-Emax).
+func (f Format) Class(d Decimal) float.Class {
+	var c float.Class
+	switch {
+	case d.Kind == SNaN:
+		return float.SignalingNaN
+	case d.Kind == NaN:
+		return float.QuietNaN
+	case d.Kind == Inf:
+		c = float.PositiveInfinity
+	case d.Coeff.Sign() == 0:
+		c = float.PositiveZero
+	case d.Exp+numDigits(d.Coeff)-1 < 1-f.Emax:
+		// Adjusted exponent, of the number with a single digit before the
+		// decimal point, below the exponent of the smallest normal number.
+		c = float.PositiveSubnormal
+	default:
+		c = float.PositiveNormal
+	}
+	return order.Signed(d.Neg, c)
+}
+
+// TotalOrder reports whether d is ordered before or equal to e in the total
+// order of IEEE 754 (totalOrder), for numbers of the format f. NaNs of the same
+// kind and sign are ordered by payload, in reverse for negative NaNs. Members
+// of a cohort are ordered by exponent; in increasing order for positive
+// numbers, and in decreasing order for negative numbers.
+func (f Format) TotalOrder(d, e Decimal) bool {
+	cmp := 0
+	switch {
+	case d.IsNaN() && e.IsNaN():
+		cmp = d.Coeff.Cmp(e.Coeff)
+	case !d.IsNaN() && !e.IsNaN():
+		cmp = d.Cmp(e)
+		if cmp == 0 && d.Kind == Finite && e.Kind == Finite {
+			switch {
+			case d.Exp < e.Exp:
+				cmp = -1
+			case d.Exp > e.Exp:
+				cmp = +1
+			}
+			if d.Neg {
+				cmp = -cmp
+			}
+		}
+	}
+	return order.Total(f.Class(d), f.Class(e), d.Neg, e.Neg, cmp)
+}
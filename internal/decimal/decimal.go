@@ -0,0 +1,388 @@
+// Package decimal implements the IEEE 754 decimal interchange formats, shared
+// by the decimal32, decimal64 and decimal128 packages.
+//
+// A finite decimal floating-point number is (-1)^sign * coeff * 10^exp, with an
+// integer coefficient of at most p digits. Numbers with the same value but
+// different exponents (e.g. 1.0 and 1.00) are members of the same cohort, and
+// are kept distinct.
+package decimal
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+)
+
+// Format is a decimal interchange format.
+type Format struct {
+	// Width of the binary representation in bits; a multiple of 32.
+	Width uint
+	// Number of digits of the coefficient (precision).
+	Digits int
+	// Exponent of the largest finite number, as a number with a single digit
+	// before the decimal point.
+	Emax int
+}
+
+// Decimal interchange formats.
+var (
+	// decimal32
+	Decimal32 = Format{Width: 32, Digits: 7, Emax: 96}
+	// decimal64
+	Decimal64 = Format{Width: 64, Digits: 16, Emax: 384}
+	// decimal128
+	Decimal128 = Format{Width: 128, Digits: 34, Emax: 6144}
+)
+
+// Bias returns the exponent bias of the binary representation.
+func (f Format) Bias() int {
+	return f.Emax + f.Digits - 2
+}
+
+// MinExp returns the smallest exponent of the coefficient, of subnormal numbers.
+func (f Format) MinExp() int {
+	return -f.Bias()
+}
+
+// MaxExp returns the largest exponent of the coefficient.
+func (f Format) MaxExp() int {
+	return f.Emax - f.Digits + 1
+}
+
+// expBits returns the number of exponent continuation bits.
+func (f Format) expBits() uint {
+	return f.Width/16 + 4
+}
+
+// trailingBits returns the number of bits of the trailing significand field.
+func (f Format) trailingBits() uint {
+	return 15*f.Width/16 - 10
+}
+
+// Kind specifies the kind of a decimal floating-point number.
+type Kind uint8
+
+// Kinds of decimal floating-point numbers.
+const (
+	// Finite number.
+	Finite Kind = iota
+	// Infinity.
+	Inf
+	// Quiet Not-a-Number.
+	NaN
+	// Signaling Not-a-Number.
+	SNaN
+)
+
+// Decimal is a decimal floating-point number.
+type Decimal struct {
+	// Kind of number.
+	Kind Kind
+	// Sign.
+	Neg bool
+	// Coefficient of finite numbers, and payload of NaN.
+	Coeff *big.Int
+	// Exponent of finite numbers.
+	Exp int
+}
+
+// Round returns the number (-1)^neg * coeff * 10^exp rounded to the format f
+// (round half to even), and the accuracy of the conversion.
+//
+// The exponent of the result is exp if possible, and otherwise the exponent
+// closest to exp for which the result is representable; e.g. 12345 * 10^-1
+// rounds to 1234 * 10^0 in a format with 4 digits. Values too large in
+// magnitude are rounded to +-Inf.
+func (f Format) Round(neg bool, coeff *big.Int, exp int) (Decimal, big.Accuracy) {
+	return new(Context).Round(f, neg, coeff, exp)
+}
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// Round returns the number (-1)^neg * coeff * 10^exp rounded to the format f
+// and the accuracy of the conversion, as for f.Round. The exception flags
+// raised are recorded in ctx.
+func (ctx *Context) Round(f Format, neg bool, coeff *big.Int, exp int) (Decimal, big.Accuracy) {
+	d := Decimal{Neg: neg, Coeff: new(big.Int).Set(coeff), Exp: exp}
+	if d.Coeff.Sign() == 0 {
+		// Zeros of any exponent in range are exact.
+		switch {
+		case exp < f.MinExp():
+			d.Exp = f.MinExp()
+		case exp > f.MaxExp():
+			d.Exp = f.MaxExp()
+		}
+		return d, big.Exact
+	}
+	acc := big.Exact
+	n := numDigits(d.Coeff)
+	drop := n - f.Digits
+	if f.MinExp()-exp > drop {
+		// Subnormal number.
+		drop = f.MinExp() - exp
+	}
+	if drop > 0 {
+		if drop > n+1 {
+			// Less than half a unit in the last place.
+			d.Coeff.SetInt64(0)
+			acc = big.Below
+		} else {
+			den := pow10(drop)
+			r := new(big.Int)
+			d.Coeff.QuoRem(d.Coeff, den, r)
+			if r.Sign() != 0 {
+				acc = big.Below
+				c := r.Lsh(r, 1).Cmp(den)
+				if c > 0 || c == 0 && d.Coeff.Bit(0) != 0 {
+					d.Coeff.Add(d.Coeff, big.NewInt(1))
+					acc = big.Above
+				}
+			}
+		}
+		d.Exp += drop
+		if numDigits(d.Coeff) > f.Digits {
+			// Carry out of the most significant digit.
+			d.Coeff.Quo(d.Coeff, big.NewInt(10))
+			d.Exp++
+		}
+	}
+	if d.Exp > f.MaxExp() {
+		n := numDigits(d.Coeff)
+		if d.Coeff.Sign() != 0 && n+d.Exp-f.MaxExp() > f.Digits {
+			// Overflow.
+			ctx.Flags |= fenv.Overflow | fenv.Inexact
+			return Decimal{Kind: Inf, Neg: neg, Coeff: new(big.Int)}, signAcc(neg, big.Above)
+		}
+		// Pad the coefficient with zeros.
+		d.Coeff.Mul(d.Coeff, pow10(d.Exp-f.MaxExp()))
+		d.Exp = f.MaxExp()
+	}
+	if acc != big.Exact {
+		ctx.Flags |= fenv.Inexact
+		if f.tiny(coeff, exp, ctx.Tininess) {
+			ctx.Flags |= fenv.Underflow
+		}
+	}
+	return d, signAcc(neg, acc)
+}
+
+// tiny reports whether the non-zero number coeff * 10^exp is below the
+// smallest normal number 10^(1-Emax) of the format f in magnitude, before
+// rounding or after rounding to the precision of f with an unbounded exponent
+// range, as specified by tininess.
+func (f Format) tiny(coeff *big.Int, exp int, tininess fenv.Tininess) bool {
+	emin := 1 - f.Emax
+	n := numDigits(coeff)
+	// Exponent of the number with a single digit before the decimal point.
+	adjusted := exp + n - 1
+	switch {
+	case adjusted >= emin:
+		return false
+	case tininess == fenv.BeforeRounding, adjusted < emin-1, n <= f.Digits:
+		return true
+	}
+	// A number just below 10^emin is not tiny after rounding if it rounds up to
+	// 10^emin; i.e. with a carry out of the most significant digit.
+	den := pow10(n - f.Digits)
+	q, r := new(big.Int).QuoRem(coeff, den, new(big.Int))
+	if c := r.Lsh(r, 1).Cmp(den); c > 0 || c == 0 && q.Bit(0) != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return numDigits(q) <= f.Digits
+}
+
+// FromBig returns the number nearest to x in the format f (round half to even)
+// and the accuracy of the conversion. Exact results use the exponent closest to
+// zero; e.g. 0.5 is 5 * 10^-1 and 100 is 100 * 10^0.
+func (f Format) FromBig(x *big.Float) (Decimal, big.Accuracy) {
+	return new(Context).FromBig(f, x)
+}
+
+// FromBig returns the number nearest to x in the format f and the accuracy of
+// the conversion, as for f.FromBig. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) FromBig(f Format, x *big.Float) (Decimal, big.Accuracy) {
+	neg := x.Signbit()
+	switch {
+	case x.IsInf():
+		return Decimal{Kind: Inf, Neg: neg, Coeff: new(big.Int)}, big.Exact
+	case x.Sign() == 0:
+		return Decimal{Neg: neg, Coeff: new(big.Int)}, big.Exact
+	}
+	// Values outside of the range of the format; 2^4 > 10.
+	e := x.MantExp(nil)
+	switch {
+	case e-1 > 4*(f.Emax+1):
+		ctx.Flags |= fenv.Overflow | fenv.Inexact
+		return Decimal{Kind: Inf, Neg: neg, Coeff: new(big.Int)}, signAcc(neg, big.Above)
+	case e < 4*(f.MinExp()-2):
+		ctx.Flags |= fenv.Underflow | fenv.Inexact
+		return Decimal{Neg: neg, Coeff: new(big.Int), Exp: f.MinExp()}, signAcc(neg, big.Below)
+	}
+	// |x| = mant * 2^exp, with odd mant.
+	prec := int(x.MinPrec())
+	exp := e - prec
+	mant, _ := new(big.Float).SetMantExp(new(big.Float).Abs(x), -exp).Int(nil)
+	if exp >= 0 {
+		return ctx.Round(f, neg, mant.Lsh(mant, uint(exp)), 0)
+	}
+	// mant * 2^exp = mant * 5^-exp * 10^exp, without trailing zeros.
+	five := new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(-exp)), nil)
+	return ctx.Round(f, neg, mant.Mul(mant, five), exp)
+}
+
+// Rat returns the exact value of the finite number d.
+func (d Decimal) Rat() *big.Rat {
+	x := new(big.Rat)
+	if d.Exp >= 0 {
+		x.SetInt(new(big.Int).Mul(d.Coeff, pow10(d.Exp)))
+	} else {
+		x.SetFrac(d.Coeff, pow10(-d.Exp))
+	}
+	if d.Neg {
+		x.Neg(x)
+	}
+	return x
+}
+
+// Big returns the value of d rounded to prec bits (round half to even) and a
+// boolean indicating whether d is Not-a-Number. The accuracy of the conversion
+// is given by x.Acc().
+func (d Decimal) Big(prec uint) (x *big.Float, nan bool) {
+	x = new(big.Float).SetPrec(prec).SetMode(big.ToNearestEven)
+	switch d.Kind {
+	case Inf:
+		return x.SetInf(d.Neg), false
+	case NaN, SNaN:
+		if d.Neg {
+			x.Neg(x)
+		}
+		return x, true
+	}
+	x.SetRat(d.Rat())
+	if d.Neg && x.Sign() == 0 {
+		// -0
+		x.Neg(x)
+	}
+	return x, false
+}
+
+// Float32 returns the float32 value nearest to the number d, which is not NaN,
+// and the accuracy of the conversion.
+func (d Decimal) Float32() (float32, big.Accuracy) {
+	return new(Context).Float32(d)
+}
+
+// Float64 returns the float64 value nearest to the number d, which is not NaN,
+// and the accuracy of the conversion.
+func (d Decimal) Float64() (float64, big.Accuracy) {
+	return new(Context).Float64(d)
+}
+
+// Float32 returns the float32 value nearest to the number d, which is not NaN,
+// and the accuracy of the conversion. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) Float32(d Decimal) (float32, big.Accuracy) {
+	x, _ := d.Big(24)
+	if x.IsInf() || x.Sign() == 0 {
+		return x.Float32()
+	}
+	r := d.Rat()
+	y, _ := r.Float32()
+	acc := signAcc(d.Neg, big.Above)
+	if !math.IsInf(float64(y), 0) {
+		acc = ratAcc(new(big.Rat).SetFloat64(float64(y)), r)
+	}
+	ctx.Flags |= binaryFlags(r, acc, 24, -126, 127, ctx.Tininess)
+	return y, acc
+}
+
+// Float64 returns the float64 value nearest to the number d, which is not NaN,
+// and the accuracy of the conversion. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) Float64(d Decimal) (float64, big.Accuracy) {
+	x, _ := d.Big(53)
+	if x.IsInf() || x.Sign() == 0 {
+		return x.Float64()
+	}
+	r := d.Rat()
+	y, _ := r.Float64()
+	acc := signAcc(d.Neg, big.Above)
+	if !math.IsInf(y, 0) {
+		acc = ratAcc(new(big.Rat).SetFloat64(y), r)
+	}
+	ctx.Flags |= binaryFlags(r, acc, 53, -1022, 1023, ctx.Tininess)
+	return y, acc
+}
+
+// binaryFlags returns the exception flags raised by rounding the non-zero exact
+// value x to a binary floating-point format with prec-bit significands and the
+// exponents of normalized numbers ranging from emin to emax; acc is the
+// accuracy of the rounded result. Tininess is detected as specified by
+// tininess.
+func binaryFlags(x *big.Rat, acc big.Accuracy, prec uint, emin, emax int, tininess fenv.Tininess) fenv.Flags {
+	if acc == big.Exact {
+		return 0
+	}
+	flags := fenv.Inexact
+	// Exponent of the most significant bit of x rounded to prec bits with an
+	// unbounded exponent range.
+	y := new(big.Float).SetPrec(prec).SetMode(big.ToNearestEven).SetRat(x)
+	exp := y.MantExp(nil) - 1
+	switch {
+	case exp > emax:
+		return flags | fenv.Overflow
+	case tininess == fenv.BeforeRounding:
+		// 2^emin
+		min := new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), uint(-emin)))
+		if new(big.Rat).Abs(x).Cmp(min) < 0 {
+			flags |= fenv.Underflow
+		}
+	case exp < emin:
+		flags |= fenv.Underflow
+	}
+	return flags
+}
+
+// numDigits returns the number of decimal digits of the non-negative x; zero
+// for 0.
+func numDigits(x *big.Int) int {
+	if x.Sign() == 0 {
+		return 0
+	}
+	return len(x.Text(10))
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// signAcc returns the accuracy acc of the magnitude of a number, adjusted for
+// the sign of the number.
+func signAcc(neg bool, acc big.Accuracy) big.Accuracy {
+	if neg {
+		return -acc
+	}
+	return acc
+}
+
+// ratAcc returns the accuracy of the approximation y of the exact value x.
+func ratAcc(y, x *big.Rat) big.Accuracy {
+	switch c := y.Cmp(x); {
+	case c < 0:
+		return big.Below
+	case c > 0:
+		return big.Above
+	}
+	return big.Exact
+}
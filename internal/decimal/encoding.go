@@ -0,0 +1,277 @@
+package decimal
+
+import (
+	"math/big"
+)
+
+// The binary representation of a decimal floating-point number consists of,
+// from the most significant bit:
+//
+//    1 bit:               sign
+//    w+5 bits:            combination field
+//    t bits:              trailing significand field
+//
+// The five most significant bits of the combination field are 11110 for
+// infinities and 11111 for NaN, in which case the sixth bit is set for
+// signaling NaN and the trailing significand field holds the payload.
+//
+// The coefficient and exponent are encoded either as a binary integer (BID) or
+// using densely packed decimal (DPD).
+
+// DecodeBID returns the decimal floating-point number of the binary integer
+// decimal (BID) encoding bits in the format f. Non-canonical coefficients and
+// payloads are decoded as zero.
+func (f Format) DecodeBID(bits *big.Int) Decimal {
+	d, ok := f.decodeSpecial(bits)
+	if ok {
+		if d.Kind != Inf {
+			d.Coeff = field(bits, 0, f.trailingBits())
+			if numDigits(d.Coeff) >= f.Digits {
+				d.Coeff.SetInt64(0)
+			}
+		}
+		return d
+	}
+	w, t := f.expBits(), f.trailingBits()
+	var exp int
+	if field(bits, f.Width-3, 2).Int64() != 3 {
+		// Exponent in the w+2 bits following the sign and coefficient in the
+		// remaining t+3 bits.
+		exp = int(field(bits, t+3, w+2).Int64())
+		d.Coeff = field(bits, 0, t+3)
+	} else {
+		// Exponent in the w+2 bits following 11 and coefficient of 100 followed
+		// by the remaining t+1 bits.
+		exp = int(field(bits, t+1, w+2).Int64())
+		d.Coeff = field(bits, 0, t+1)
+		d.Coeff.SetBit(d.Coeff, int(t+3), 1)
+	}
+	if numDigits(d.Coeff) > f.Digits {
+		d.Coeff.SetInt64(0)
+	}
+	d.Exp = exp - f.Bias()
+	return d
+}
+
+// EncodeBID returns the binary integer decimal (BID) encoding of the decimal
+// floating-point number d, which must be representable in the format f.
+func (f Format) EncodeBID(d Decimal) *big.Int {
+	bits, ok := f.encodeSpecial(d)
+	if ok {
+		if d.Kind != Inf {
+			bits.Or(bits, d.Coeff)
+		}
+		return bits
+	}
+	t := f.trailingBits()
+	exp := big.NewInt(int64(d.Exp + f.Bias()))
+	if d.Coeff.BitLen() <= int(t+3) {
+		bits.Lsh(exp, t+3)
+		bits.Or(bits, d.Coeff)
+	} else {
+		bits.Lsh(exp, t+1)
+		bits.Or(bits, field(d.Coeff, 0, t+1))
+		bits.SetBit(bits, int(f.Width-2), 1)
+		bits.SetBit(bits, int(f.Width-3), 1)
+	}
+	return f.setSign(bits, d.Neg)
+}
+
+// DecodeDPD returns the decimal floating-point number of the densely packed
+// decimal (DPD) encoding bits in the format f. Non-canonical declets are
+// decoded to their value, and non-canonical payloads as zero.
+func (f Format) DecodeDPD(bits *big.Int) Decimal {
+	t := f.trailingBits()
+	trailing := decodeDeclets(field(bits, 0, t), t/10)
+	d, ok := f.decodeSpecial(bits)
+	if ok {
+		if d.Kind != Inf {
+			d.Coeff = trailing
+			if numDigits(d.Coeff) >= f.Digits {
+				d.Coeff.SetInt64(0)
+			}
+		}
+		return d
+	}
+	w := f.expBits()
+	// Two most significant bits of the exponent and the leading digit of the
+	// coefficient in the five most significant bits of the combination field.
+	g := field(bits, f.Width-6, 5).Int64()
+	var msb, lead int64
+	if g>>3 != 3 {
+		msb, lead = g>>3, g&7
+	} else {
+		msb, lead = g>>1&3, 8+g&1
+	}
+	exp := int(msb<<w | field(bits, t, w).Int64())
+	d.Coeff = trailing.Add(trailing, new(big.Int).Mul(big.NewInt(lead), pow10(f.Digits-1)))
+	d.Exp = exp - f.Bias()
+	return d
+}
+
+// EncodeDPD returns the densely packed decimal (DPD) encoding of the decimal
+// floating-point number d, which must be representable in the format f.
+func (f Format) EncodeDPD(d Decimal) *big.Int {
+	t := f.trailingBits()
+	bits, ok := f.encodeSpecial(d)
+	if ok {
+		if d.Kind != Inf {
+			bits.Or(bits, encodeDeclets(d.Coeff, t/10))
+		}
+		return bits
+	}
+	w := f.expBits()
+	lead, trailing := new(big.Int).QuoRem(d.Coeff, pow10(f.Digits-1), new(big.Int))
+	exp := int64(d.Exp + f.Bias())
+	msb, l := exp>>w, lead.Int64()
+	var g int64
+	if l < 8 {
+		g = msb<<3 | l
+	} else {
+		g = 3<<3 | msb<<1 | l&1
+	}
+	bits.SetInt64(g<<w | exp&(1<<w-1))
+	bits.Lsh(bits, t)
+	bits.Or(bits, encodeDeclets(trailing, t/10))
+	return f.setSign(bits, d.Neg)
+}
+
+// decodeSpecial returns the infinity or NaN of the binary representation bits,
+// without payload, and a boolean indicating whether bits is an infinity or NaN.
+// Otherwise, it returns a finite number with the sign of bits.
+func (f Format) decodeSpecial(bits *big.Int) (Decimal, bool) {
+	d := Decimal{Neg: bits.Bit(int(f.Width-1)) != 0, Coeff: new(big.Int)}
+	switch field(bits, f.Width-6, 5).Int64() {
+	case 0x1E:
+		d.Kind = Inf
+	case 0x1F:
+		d.Kind = NaN
+		if bits.Bit(int(f.Width-7)) != 0 {
+			d.Kind = SNaN
+		}
+	default:
+		return d, false
+	}
+	return d, true
+}
+
+// encodeSpecial returns the binary representation of the infinity or NaN d,
+// without payload, and a boolean indicating whether d is an infinity or NaN.
+// Otherwise, it returns zero.
+func (f Format) encodeSpecial(d Decimal) (*big.Int, bool) {
+	bits := new(big.Int)
+	switch d.Kind {
+	case Inf:
+		bits.SetInt64(0x1E)
+	case NaN:
+		bits.SetInt64(0x1F)
+	case SNaN:
+		bits.SetInt64(0x3F)
+		bits.Lsh(bits, f.Width-7)
+		return f.setSign(bits, d.Neg), true
+	default:
+		return bits, false
+	}
+	bits.Lsh(bits, f.Width-6)
+	return f.setSign(bits, d.Neg), true
+}
+
+// setSign sets the sign bit of bits if neg is set, and returns bits.
+func (f Format) setSign(bits *big.Int, neg bool) *big.Int {
+	if neg {
+		bits.SetBit(bits, int(f.Width-1), 1)
+	}
+	return bits
+}
+
+// field returns the n bits of x starting at bit lo.
+func field(x *big.Int, lo, n uint) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), n)
+	mask.Sub(mask, big.NewInt(1))
+	return mask.And(mask, new(big.Int).Rsh(x, lo))
+}
+
+// ### [ Densely packed decimal ] ##############################################
+
+// dpdEncode maps three digit numbers to their canonical declet.
+var dpdEncode [1000]uint16
+
+func init() {
+	// The canonical declets are the declets without don't-care bits set; the
+	// non-canonical declets 11x11x111x encode the same numbers as 00x11x111x.
+	for declet := 1<<10 - 1; declet >= 0; declet-- {
+		dpdEncode[decodeDeclet(uint16(declet))] = uint16(declet)
+	}
+}
+
+// decodeDeclet returns the three digit number of the declet, as specified by
+// table 3.3 of IEEE 754-2008.
+func decodeDeclet(declet uint16) int {
+	// b[0] is the most significant bit.
+	var b [10]int
+	for i := range b {
+		b[i] = int(declet>>(9-i)) & 1
+	}
+	var d1, d2, d3 int
+	switch {
+	case b[6] == 0:
+		d1 = 4*b[0] + 2*b[1] + b[2]
+		d2 = 4*b[3] + 2*b[4] + b[5]
+		d3 = 4*b[7] + 2*b[8] + b[9]
+	case b[7] == 0 && b[8] == 0:
+		d1 = 4*b[0] + 2*b[1] + b[2]
+		d2 = 4*b[3] + 2*b[4] + b[5]
+		d3 = 8 + b[9]
+	case b[7] == 0 && b[8] == 1:
+		d1 = 4*b[0] + 2*b[1] + b[2]
+		d2 = 8 + b[5]
+		d3 = 4*b[3] + 2*b[4] + b[9]
+	case b[7] == 1 && b[8] == 0:
+		d1 = 8 + b[2]
+		d2 = 4*b[3] + 2*b[4] + b[5]
+		d3 = 4*b[0] + 2*b[1] + b[9]
+	case b[3] == 0 && b[4] == 0:
+		d1 = 8 + b[2]
+		d2 = 8 + b[5]
+		d3 = 4*b[0] + 2*b[1] + b[9]
+	case b[3] == 0 && b[4] == 1:
+		d1 = 8 + b[2]
+		d2 = 4*b[0] + 2*b[1] + b[5]
+		d3 = 8 + b[9]
+	case b[3] == 1 && b[4] == 0:
+		d1 = 4*b[0] + 2*b[1] + b[2]
+		d2 = 8 + b[5]
+		d3 = 8 + b[9]
+	default:
+		d1 = 8 + b[2]
+		d2 = 8 + b[5]
+		d3 = 8 + b[9]
+	}
+	return 100*d1 + 10*d2 + d3
+}
+
+// decodeDeclets returns the number of the n declets of x.
+func decodeDeclets(x *big.Int, n uint) *big.Int {
+	v := new(big.Int)
+	thousand := big.NewInt(1000)
+	for i := int(n) - 1; i >= 0; i-- {
+		declet := field(x, uint(i)*10, 10).Int64()
+		v.Mul(v, thousand)
+		v.Add(v, big.NewInt(int64(decodeDeclet(uint16(declet)))))
+	}
+	return v
+}
+
+// encodeDeclets returns the n canonical declets of the number x, which has at
+// most 3*n digits.
+func encodeDeclets(x *big.Int, n uint) *big.Int {
+	v := new(big.Int)
+	q, r := new(big.Int).Set(x), new(big.Int)
+	thousand := big.NewInt(1000)
+	for i := uint(0); i < n; i++ {
+		q.QuoRem(q, thousand, r)
+		declet := big.NewInt(int64(dpdEncode[r.Int64()]))
+		v.Or(v, declet.Lsh(declet, i*10))
+	}
+	return v
+}
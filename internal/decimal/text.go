@@ -0,0 +1,187 @@
+package decimal
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// maxExp bounds the exponent of parsed numbers; numbers of larger magnitude
+// overflow and numbers of smaller magnitude underflow in every format.
+const maxExp = 100000
+
+// String returns the exact decimal representation of d, in scientific notation
+// if the exponent is positive or the number is small, as specified by the
+// to-scientific-string conversion of the General Decimal Arithmetic
+// Specification; e.g. "123", "1.50", "0.000012", "1.2E+3" and "1.23E-9". The
+// exponent of d is preserved, so that members of a cohort are distinct.
+//
+// Infinities are represented as "+Inf" and "-Inf", and NaN as "NaN" or "sNaN",
+// followed by the payload if non-zero and preceded by a minus sign if negative.
+func (d Decimal) String() string {
+	var buf []byte
+	if d.Neg {
+		buf = append(buf, '-')
+	}
+	switch d.Kind {
+	case Inf:
+		if !d.Neg {
+			buf = append(buf, '+')
+		}
+		return string(append(buf, "Inf"...))
+	case NaN, SNaN:
+		if d.Kind == SNaN {
+			buf = append(buf, 's')
+		}
+		buf = append(buf, "NaN"...)
+		if d.Coeff.Sign() != 0 {
+			buf = d.Coeff.Append(buf, 10)
+		}
+		return string(buf)
+	}
+	digits := d.Coeff.Text(10)
+	// Exponent of the number with a single digit before the decimal point.
+	adjusted := d.Exp + len(digits) - 1
+	switch {
+	case d.Exp <= 0 && adjusted >= -6:
+		// Plain notation.
+		point := len(digits) + d.Exp
+		switch {
+		case d.Exp == 0:
+			buf = append(buf, digits...)
+		case point > 0:
+			buf = append(buf, digits[:point]...)
+			buf = append(buf, '.')
+			buf = append(buf, digits[point:]...)
+		default:
+			buf = append(buf, "0."...)
+			buf = append(buf, strings.Repeat("0", -point)...)
+			buf = append(buf, digits...)
+		}
+	default:
+		// Scientific notation.
+		buf = append(buf, digits[0])
+		if len(digits) > 1 {
+			buf = append(buf, '.')
+			buf = append(buf, digits[1:]...)
+		}
+		buf = append(buf, 'E')
+		if adjusted >= 0 {
+			buf = append(buf, '+')
+		}
+		buf = big.NewInt(int64(adjusted)).Append(buf, 10)
+	}
+	return string(buf)
+}
+
+// Parse parses s as a decimal floating-point number in the format f, and
+// returns the nearest number (round half to even) and the accuracy of the
+// conversion. s is an optionally signed decimal literal with an optional
+// exponent, e.g. "1.50" or "-12E-3", or a case-insensitive "inf", "infinity",
+// "nan" or "snan", optionally followed by the decimal payload of NaN.
+//
+// The exponent of the result is the exponent of the literal if possible, as for
+// Round; e.g. "1.50" is 150 * 10^-2.
+//
+// If s is syntactically invalid, the error is strconv.ErrSyntax. If s is too
+// large in magnitude, the result is +-Inf and the error is strconv.ErrRange.
+func (f Format) Parse(s string) (Decimal, big.Accuracy, error) {
+	return new(Context).Parse(f, s)
+}
+
+// Parse parses s as a decimal floating-point number in the format f, and
+// returns the nearest number and the accuracy of the conversion, as for
+// f.Parse. The exception flags raised are recorded in ctx.
+func (ctx *Context) Parse(f Format, s string) (Decimal, big.Accuracy, error) {
+	// Sign.
+	neg := false
+	t := s
+	if len(t) > 0 && (t[0] == '+' || t[0] == '-') {
+		neg = t[0] == '-'
+		t = t[1:]
+	}
+
+	// Special values.
+	lower := strings.ToLower(t)
+	switch {
+	case lower == "inf" || lower == "infinity":
+		return Decimal{Kind: Inf, Neg: neg, Coeff: new(big.Int)}, big.Exact, nil
+	case strings.HasPrefix(lower, "nan") || strings.HasPrefix(lower, "snan"):
+		d := Decimal{Kind: NaN, Neg: neg, Coeff: new(big.Int)}
+		payload := lower[len("nan"):]
+		if lower[0] == 's' {
+			d.Kind = SNaN
+			payload = lower[len("snan"):]
+		}
+		if len(payload) > 0 {
+			if !isDigits(payload) || len(strings.TrimLeft(payload, "0")) >= f.Digits {
+				return Decimal{}, big.Exact, strconv.ErrSyntax
+			}
+			d.Coeff.SetString(payload, 10)
+		}
+		return d, big.Exact, nil
+	}
+
+	// Coefficient.
+	mant := t
+	exp := 0
+	if i := strings.IndexAny(t, "eE"); i >= 0 {
+		mant = t[:i]
+		e, ok := parseExp(t[i+1:])
+		if !ok {
+			return Decimal{}, big.Exact, strconv.ErrSyntax
+		}
+		exp = e
+	}
+	digits := mant
+	if i := strings.IndexByte(mant, '.'); i >= 0 {
+		digits = mant[:i] + mant[i+1:]
+		exp -= len(mant) - i - 1
+	}
+	if len(digits) == 0 || !isDigits(digits) {
+		return Decimal{}, big.Exact, strconv.ErrSyntax
+	}
+	coeff, _ := new(big.Int).SetString(digits, 10)
+	d, acc := ctx.Round(f, neg, coeff, exp)
+	if d.Kind == Inf {
+		return d, acc, strconv.ErrRange
+	}
+	return d, acc, nil
+}
+
+// parseExp parses s as an optionally signed decimal exponent, clamped to
+// [-maxExp, maxExp].
+func parseExp(s string) (int, bool) {
+	neg := false
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if len(s) == 0 || !isDigits(s) {
+		return 0, false
+	}
+	e := 0
+	for i := 0; i < len(s); i++ {
+		if e < maxExp {
+			e = 10*e + int(s[i]-'0')
+		}
+	}
+	if e > maxExp {
+		e = maxExp
+	}
+	if neg {
+		return -e, true
+	}
+	return e, true
+}
+
+// isDigits reports whether s consists of decimal digits only.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package order
+
+import (
+	"github.com/mewmew/float"
+)
+
+// Binary is a number of an IEEE 754 binary interchange format, accessed
+// through its binary representation.
+type Binary interface {
+	// Words returns the binary representation as words from the most
+	// significant. The most significant word holds the sign bit, the exponent
+	// and the most significant bits of the fraction.
+	Words() []uint64
+}
+
+// Layout specifies the position of the sign bit and exponent in the most
+// significant word of the binary representation of a Binary number; the
+// fraction fills the remaining bits.
+type Layout struct {
+	// Sign bit of the most significant word.
+	Sign uint64
+	// Number of exponent bits, following the sign bit.
+	ExpBits uint
+}
+
+// Cmp returns the comparison of x and y; -1 if x < y, 0 if x == y and +1 if x
+// > y. -0 is equal to +0, and a NaN is less than any non-NaN and equal to a
+// NaN.
+func (l Layout) Cmp(x, y Binary) int {
+	xw, yw := x.Words(), y.Words()
+	xnan, ynan := l.isNaN(xw), l.isNaN(yw)
+	switch {
+	case xnan || ynan:
+		return NaN(xnan, ynan)
+	case l.isZero(xw) && l.isZero(yw):
+		return 0
+	}
+	return Bits(xw, yw, l.Sign)
+}
+
+// TotalOrder reports whether x is ordered before or equal to y in the total
+// order of IEEE 754. The quiet bit is the most significant bit of the fraction,
+// so the binary representation orders NaNs by kind and payload.
+func (l Layout) TotalOrder(x, y Binary) bool {
+	return Bits(x.Words(), y.Words(), l.Sign) <= 0
+}
+
+// Class returns the class of x. NaNs with the most significant bit of the
+// fraction set are quiet and other NaNs signaling.
+func (l Layout) Class(x Binary) float.Class {
+	w := x.Words()
+	var c float.Class
+	switch {
+	case l.isNaN(w):
+		if w[0]&l.quiet() == 0 {
+			return float.SignalingNaN
+		}
+		return float.QuietNaN
+	case l.isInf(w):
+		c = float.PositiveInfinity
+	case l.isZero(w):
+		c = float.PositiveZero
+	case w[0]&l.exp() == 0:
+		c = float.PositiveSubnormal
+	default:
+		c = float.PositiveNormal
+	}
+	return Signed(w[0]&l.Sign != 0, c)
+}
+
+// IsNaN reports whether x is Not-a-Number.
+func (l Layout) IsNaN(x Binary) bool {
+	return l.isNaN(x.Words())
+}
+
+// IsInf reports whether x is +Inf or -Inf.
+func (l Layout) IsInf(x Binary) bool {
+	return l.isInf(x.Words())
+}
+
+// IsSubnormal reports whether x is a subnormal number.
+func (l Layout) IsSubnormal(x Binary) bool {
+	w := x.Words()
+	return w[0]&l.exp() == 0 && !l.isZero(w)
+}
+
+// IsFinite reports whether x is neither infinite nor NaN.
+func (l Layout) IsFinite(x Binary) bool {
+	return x.Words()[0]&l.exp() != l.exp()
+}
+
+// IsZero reports whether x is +0 or -0.
+func (l Layout) IsZero(x Binary) bool {
+	return l.isZero(x.Words())
+}
+
+// isNaN reports whether the binary representation w is Not-a-Number.
+func (l Layout) isNaN(w []uint64) bool {
+	return w[0]&l.exp() == l.exp() && !l.fracZero(w)
+}
+
+// isInf reports whether the binary representation w is +Inf or -Inf.
+func (l Layout) isInf(w []uint64) bool {
+	return w[0]&l.exp() == l.exp() && l.fracZero(w)
+}
+
+// isZero reports whether the binary representation w is +0 or -0.
+func (l Layout) isZero(w []uint64) bool {
+	return w[0]&l.exp() == 0 && l.fracZero(w)
+}
+
+// fracZero reports whether the fraction of the binary representation w is
+// zero.
+func (l Layout) fracZero(w []uint64) bool {
+	if w[0]&l.frac() != 0 {
+		return false
+	}
+	for _, v := range w[1:] {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// exp returns the mask of the exponent in the most significant word.
+func (l Layout) exp() uint64 {
+	return l.Sign - 1 - l.frac()
+}
+
+// frac returns the mask of the fraction in the most significant word.
+func (l Layout) frac() uint64 {
+	return l.Sign>>l.ExpBits - 1
+}
+
+// quiet returns the quiet bit of NaNs in the most significant word.
+func (l Layout) quiet() uint64 {
+	return l.Sign >> (l.ExpBits + 1)
+}
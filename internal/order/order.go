@@ -0,0 +1,153 @@
+// Package order implements the comparison, total order and classification of
+// floating-point numbers, shared by the floating-point formats of this module.
+//
+// The Cmp methods of the formats order numbers as cmp.Compare orders float64
+// values; a NaN is less than any number and equal to a NaN, and -0 is equal to
+// +0. The TotalOrder methods implement the totalOrder predicate of IEEE 754:
+//
+//	-qNaN < -sNaN < -Inf < -x < -0 < +0 < +x < +Inf < +sNaN < +qNaN
+//
+// where NaNs of the same kind and sign are ordered by payload, in reverse for
+// negative NaNs.
+package order
+
+import (
+	"math/big"
+
+	"github.com/mewmew/float"
+)
+
+// Signed returns the class of a number which is not NaN, with the sign bit neg
+// and the class c of its magnitude; one of float.PositiveZero,
+// float.PositiveSubnormal, float.PositiveNormal and float.PositiveInfinity.
+func Signed(neg bool, c float.Class) float.Class {
+	if neg {
+		// The classes of negative numbers mirror those of positive numbers.
+		return float.NegativeInfinity + float.PositiveInfinity - c
+	}
+	return c
+}
+
+// Class returns the class of the number x, which is not NaN, in a format whose
+// smallest positive normal number is 2^emin.
+func Class(x *big.Float, emin int) float.Class {
+	var c float.Class
+	switch {
+	case x.IsInf():
+		c = float.PositiveInfinity
+	case x.Sign() == 0:
+		c = float.PositiveZero
+	case x.MantExp(nil) <= emin:
+		// 0.5 <= mant < 1
+		c = float.PositiveSubnormal
+	default:
+		c = float.PositiveNormal
+	}
+	return Signed(x.Signbit(), c)
+}
+
+// NaN returns the comparison of x and y, of which at least one is NaN as
+// reported by xnan and ynan; -1 if only x is NaN, +1 if only y is NaN and 0
+// otherwise.
+func NaN(xnan, ynan bool) int {
+	switch {
+	case xnan && ynan:
+		return 0
+	case xnan:
+		return -1
+	}
+	return +1
+}
+
+// Cmp returns the comparison of x and y, where xnan and ynan report whether x and
+// y are NaN; -1 if x < y, 0 if x == y and +1 if x > y.
+func Cmp(x *big.Float, xnan bool, y *big.Float, ynan bool) int {
+	if xnan || ynan {
+		return NaN(xnan, ynan)
+	}
+	return x.Cmp(y)
+}
+
+// Bits returns the comparison in the total order of the numbers with the
+// sign-magnitude binary representations x and y; -1 if x is ordered before y,
+// 0 if x and y are identical and +1 otherwise. The representations are given as
+// words from the most significant, and sign is the sign bit of the most
+// significant word.
+func Bits(x, y []uint64, sign uint64) int {
+	xneg, yneg := x[0]&sign != 0, y[0]&sign != 0
+	switch {
+	case xneg && !yneg:
+		return -1
+	case !xneg && yneg:
+		return +1
+	}
+	c := 0
+	for i := range x {
+		a, b := x[i], y[i]
+		if i == 0 {
+			a &^= sign
+			b &^= sign
+		}
+		if a != b {
+			c = +1
+			if a < b {
+				c = -1
+			}
+			break
+		}
+	}
+	if xneg {
+		// Negative numbers are ordered in reverse of their magnitude.
+		return -c
+	}
+	return c
+}
+
+// Payload returns the comparison of the payloads x and y of NaN values; -1 if
+// x < y, 0 if x == y and +1 if x > y.
+func Payload(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return +1
+	}
+	return 0
+}
+
+// Total reports whether x is ordered before or equal to y in the total order,
+// for numbers x and y of the classes cx and cy with the sign bits negx and negy.
+// If x and y are both NaN, cmp is the comparison of their payloads, and if
+// neither is NaN, the comparison of their values; with members of a cohort
+// ordered by exponent for decimal formats.
+func Total(cx, cy float.Class, negx, negy bool, cmp int) bool {
+	rx, ry := rank(cx, negx), rank(cy, negy)
+	switch {
+	case rx != ry:
+		return rx < ry
+	case rx == 0 && negx != negy:
+		// -0 < +0
+		return negx
+	case negx && rx != 0:
+		// Negative NaNs are ordered in reverse of their payload.
+		return cmp >= 0
+	}
+	return cmp <= 0
+}
+
+// rank returns the rank of a number of the class c with the sign bit neg in the
+// total order; -3 for -qNaN, -2 for -sNaN, 0 for numbers, +2 for +sNaN and +3
+// for +qNaN.
+func rank(c float.Class, neg bool) int {
+	r := 0
+	switch c {
+	case float.SignalingNaN:
+		r = 2
+	case float.QuietNaN:
+		r = 3
+	}
+	if neg {
+		return -r
+	}
+	return r
+}
@@ -0,0 +1,246 @@
+package order
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestSigned(t *testing.T) {
+	golden := []struct {
+		neg  bool
+		c    float.Class
+		want float.Class
+	}{
+		{neg: false, c: float.PositiveZero, want: float.PositiveZero},
+		{neg: false, c: float.PositiveSubnormal, want: float.PositiveSubnormal},
+		{neg: false, c: float.PositiveNormal, want: float.PositiveNormal},
+		{neg: false, c: float.PositiveInfinity, want: float.PositiveInfinity},
+		{neg: true, c: float.PositiveZero, want: float.NegativeZero},
+		{neg: true, c: float.PositiveSubnormal, want: float.NegativeSubnormal},
+		{neg: true, c: float.PositiveNormal, want: float.NegativeNormal},
+		{neg: true, c: float.PositiveInfinity, want: float.NegativeInfinity},
+	}
+	for _, g := range golden {
+		if got := Signed(g.neg, g.c); g.want != got {
+			t.Errorf("%v (neg %v): class mismatch; expected %v, got %v", g.c, g.neg, g.want, got)
+		}
+	}
+}
+
+func TestClass(t *testing.T) {
+	// Classes in a format whose smallest positive normal number is 2^-14.
+	const emin = -14
+	golden := []struct {
+		x    *big.Float
+		want float.Class
+	}{
+		{x: big.NewFloat(math.Inf(-1)), want: float.NegativeInfinity},
+		{x: big.NewFloat(-1), want: float.NegativeNormal},
+		{x: big.NewFloat(-0x1p-14), want: float.NegativeNormal},
+		{x: big.NewFloat(-0x1.ffcp-15), want: float.NegativeSubnormal},
+		{x: big.NewFloat(-0x1p-24), want: float.NegativeSubnormal},
+		{x: big.NewFloat(math.Copysign(0, -1)), want: float.NegativeZero},
+		{x: big.NewFloat(0), want: float.PositiveZero},
+		{x: big.NewFloat(0x1p-24), want: float.PositiveSubnormal},
+		{x: big.NewFloat(0x1.ffcp-15), want: float.PositiveSubnormal},
+		{x: big.NewFloat(0x1p-14), want: float.PositiveNormal},
+		{x: big.NewFloat(65504), want: float.PositiveNormal},
+		{x: big.NewFloat(math.Inf(1)), want: float.PositiveInfinity},
+	}
+	for _, g := range golden {
+		if got := Class(g.x, emin); g.want != got {
+			t.Errorf("%v: class mismatch; expected %v, got %v", g.x, g.want, got)
+		}
+	}
+}
+
+func TestNaN(t *testing.T) {
+	golden := []struct {
+		xnan, ynan bool
+		want       int
+	}{
+		{xnan: true, ynan: true, want: 0},
+		{xnan: true, ynan: false, want: -1},
+		{xnan: false, ynan: true, want: +1},
+	}
+	for _, g := range golden {
+		if got := NaN(g.xnan, g.ynan); g.want != got {
+			t.Errorf("NaN(%v, %v): mismatch; expected %d, got %d", g.xnan, g.ynan, g.want, got)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	golden := []struct {
+		x, y       float64
+		xnan, ynan bool
+		want       int
+	}{
+		{x: -1, y: 1, want: -1},
+		{x: 1, y: -1, want: +1},
+		{x: 1, y: 1, want: 0},
+		{x: math.Copysign(0, -1), y: 0, want: 0},
+		{x: 0, y: math.Copysign(0, -1), want: 0},
+		{x: math.Inf(-1), y: -1, want: -1},
+		{x: math.Inf(1), y: math.Inf(1), want: 0},
+		// The value of a NaN operand is ignored.
+		{x: 0, xnan: true, y: math.Inf(-1), want: -1},
+		{x: math.Inf(-1), y: 0, ynan: true, want: +1},
+		{x: 1, xnan: true, y: -1, ynan: true, want: 0},
+	}
+	for _, g := range golden {
+		x, y := big.NewFloat(g.x), big.NewFloat(g.y)
+		if got := Cmp(x, g.xnan, y, g.ynan); g.want != got {
+			t.Errorf("Cmp(%v (NaN %v), %v (NaN %v)): mismatch; expected %d, got %d", g.x, g.xnan, g.y, g.ynan, g.want, got)
+		}
+	}
+}
+
+func TestBits(t *testing.T) {
+	const sign = 1 << 63
+	golden := []struct {
+		x, y []uint64
+		want int
+	}{
+		// Same sign; compared by magnitude from the most significant word.
+		{x: []uint64{0x1, 0x0}, y: []uint64{0x1, 0x0}, want: 0},
+		{x: []uint64{0x1, 0x0}, y: []uint64{0x1, 0x1}, want: -1},
+		{x: []uint64{0x2, 0x0}, y: []uint64{0x1, 0xFFFFFFFFFFFFFFFF}, want: +1},
+		// Different sign.
+		{x: []uint64{sign, 0x0}, y: []uint64{0x0, 0x0}, want: -1},
+		{x: []uint64{0x0, 0x0}, y: []uint64{sign, 0x0}, want: +1},
+		{x: []uint64{sign | 0x1, 0x0}, y: []uint64{0x0, 0x1}, want: -1},
+		// Negative; compared in reverse of magnitude.
+		{x: []uint64{sign, 0x0}, y: []uint64{sign, 0x0}, want: 0},
+		{x: []uint64{sign, 0x1}, y: []uint64{sign, 0x0}, want: -1},
+		{x: []uint64{sign | 0x1, 0x0}, y: []uint64{sign, 0xFFFFFFFFFFFFFFFF}, want: -1},
+		{x: []uint64{sign, 0x0}, y: []uint64{sign | 0x1, 0x0}, want: +1},
+	}
+	for _, g := range golden {
+		if got := Bits(g.x, g.y, sign); g.want != got {
+			t.Errorf("Bits(%#x, %#x): mismatch; expected %d, got %d", g.x, g.y, g.want, got)
+		}
+	}
+}
+
+func TestPayload(t *testing.T) {
+	golden := []struct {
+		x, y uint64
+		want int
+	}{
+		{x: 0, y: 0, want: 0},
+		{x: 1, y: 2, want: -1},
+		{x: 2, y: 1, want: +1},
+		{x: math.MaxUint64, y: math.MaxUint64, want: 0},
+	}
+	for _, g := range golden {
+		if got := Payload(g.x, g.y); g.want != got {
+			t.Errorf("Payload(%d, %d): mismatch; expected %d, got %d", g.x, g.y, g.want, got)
+		}
+	}
+}
+
+func TestTotal(t *testing.T) {
+	// Numbers in increasing total order; for NaNs, v is the payload.
+	golden := []struct {
+		c   float.Class
+		neg bool
+		v   float64
+	}{
+		{c: float.QuietNaN, neg: true, v: 2},
+		{c: float.QuietNaN, neg: true, v: 1},
+		{c: float.SignalingNaN, neg: true, v: 2},
+		{c: float.SignalingNaN, neg: true, v: 1},
+		{c: float.NegativeInfinity, neg: true, v: math.Inf(-1)},
+		{c: float.NegativeNormal, neg: true, v: -1},
+		{c: float.NegativeSubnormal, neg: true, v: -0x1p-1074},
+		{c: float.NegativeZero, neg: true, v: 0},
+		{c: float.PositiveZero, neg: false, v: 0},
+		{c: float.PositiveSubnormal, neg: false, v: 0x1p-1074},
+		{c: float.PositiveNormal, neg: false, v: 1},
+		{c: float.PositiveInfinity, neg: false, v: math.Inf(1)},
+		{c: float.SignalingNaN, neg: false, v: 1},
+		{c: float.SignalingNaN, neg: false, v: 2},
+		{c: float.QuietNaN, neg: false, v: 1},
+		{c: float.QuietNaN, neg: false, v: 2},
+	}
+	for i, a := range golden {
+		for j, b := range golden {
+			var cmp int
+			switch {
+			case a.c.IsNaN() && b.c.IsNaN():
+				cmp = Payload(uint64(a.v), uint64(b.v))
+			case !a.c.IsNaN() && !b.c.IsNaN():
+				cmp = big.NewFloat(a.v).Cmp(big.NewFloat(b.v))
+			}
+			want := i <= j
+			if got := Total(a.c, b.c, a.neg, b.neg, cmp); want != got {
+				t.Errorf("Total(%v %v, %v %v): mismatch; expected %v, got %v", a.c, a.v, b.c, b.v, want, got)
+			}
+		}
+	}
+}
+
+// words is a binary representation accessed by Layout.
+type words []uint64
+
+// Words returns w.
+func (w words) Words() []uint64 {
+	return w
+}
+
+func TestLayout(t *testing.T) {
+	// Binary representations of a format with a 16-bit most significant word, 5
+	// exponent bits and a second fraction word.
+	l := Layout{Sign: 0x8000, ExpBits: 5}
+	golden := []struct {
+		x     words
+		class float.Class
+	}{
+		{x: words{0xFE00, 0x0000}, class: float.QuietNaN},
+		{x: words{0xFC00, 0x0001}, class: float.SignalingNaN},
+		{x: words{0xFC00, 0x0000}, class: float.NegativeInfinity},
+		{x: words{0xFBFF, 0xFFFF}, class: float.NegativeNormal},
+		{x: words{0x8400, 0x0000}, class: float.NegativeNormal},
+		{x: words{0x83FF, 0xFFFF}, class: float.NegativeSubnormal},
+		{x: words{0x8000, 0x0001}, class: float.NegativeSubnormal},
+		{x: words{0x8000, 0x0000}, class: float.NegativeZero},
+		{x: words{0x0000, 0x0000}, class: float.PositiveZero},
+		{x: words{0x0000, 0x0001}, class: float.PositiveSubnormal},
+		{x: words{0x0400, 0x0000}, class: float.PositiveNormal},
+		{x: words{0x7C00, 0x0000}, class: float.PositiveInfinity},
+		{x: words{0x7DFF, 0xFFFF}, class: float.SignalingNaN},
+		{x: words{0x7E00, 0x0000}, class: float.QuietNaN},
+	}
+	for i, a := range golden {
+		if got := l.Class(a.x); a.class != got {
+			t.Errorf("%#x: class mismatch; expected %v, got %v", a.x, a.class, got)
+		}
+		if l.IsNaN(a.x) != a.class.IsNaN() || l.IsInf(a.x) != a.class.IsInf() || l.IsSubnormal(a.x) != a.class.IsSubnormal() || l.IsFinite(a.x) != a.class.IsFinite() || l.IsZero(a.x) != a.class.IsZero() {
+			t.Errorf("%#x: predicate mismatch for class %v", a.x, a.class)
+		}
+		// The list is in increasing total order, and in increasing order of
+		// value except for NaNs and zeros.
+		for j, b := range golden {
+			want := 0
+			switch {
+			case a.class.IsNaN() || b.class.IsNaN():
+				want = NaN(a.class.IsNaN(), b.class.IsNaN())
+			case a.class.IsZero() && b.class.IsZero():
+			case i < j:
+				want = -1
+			case i > j:
+				want = +1
+			}
+			if got := l.Cmp(a.x, b.x); want != got {
+				t.Errorf("Cmp(%#x, %#x): mismatch; expected %d, got %d", a.x, b.x, want, got)
+			}
+			if got := l.TotalOrder(a.x, b.x); got != (i <= j) {
+				t.Errorf("TotalOrder(%#x, %#x): mismatch; expected %v, got %v", a.x, b.x, i <= j, got)
+			}
+		}
+	}
+}
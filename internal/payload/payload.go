@@ -0,0 +1,59 @@
+// Package payload implements the conversion of Not-a-Number values between
+// float32, float64 and the binary floating-point formats of this module.
+//
+// Payloads are converted as by hardware (e.g. x86 and ARM); the fraction of the
+// NaN, including the quiet bit, is aligned at the most significant bit, then
+// truncated or extended with zero bits to the width of the target format, and
+// the quiet bit is set.
+//
+// The payload and quiet bit of float32 NaN values are not preserved by the
+// conversion to float64 on all architectures; float32 NaN values are therefore
+// inspected through their binary representation, using FromFloat32 and
+// Signaling32.
+package payload
+
+import (
+	"math"
+)
+
+// Quiet is the quiet bit of a fraction aligned at the most significant bit.
+const Quiet = 1 << 63
+
+// FromFloat32 returns the sign of the float32 NaN x, and its fraction aligned at
+// the most significant bit.
+func FromFloat32(x float32) (sign bool, frac uint64) {
+	bits := math.Float32bits(x)
+	return bits&0x80000000 != 0, uint64(bits&0x007FFFFF) << 41
+}
+
+// Signaling32 reports whether x is a signaling float32 NaN.
+func Signaling32(x float32) bool {
+	return x != x && math.Float32bits(x)&0x00400000 == 0
+}
+
+// FromFloat64 returns the sign of the float64 NaN x, and its fraction aligned at
+// the most significant bit.
+func FromFloat64(x float64) (sign bool, frac uint64) {
+	bits := math.Float64bits(x)
+	return bits&0x8000000000000000 != 0, bits << 12
+}
+
+// Float32 returns the quiet float32 NaN with the given sign and the fraction
+// frac aligned at the most significant bit.
+func Float32(sign bool, frac uint64) float32 {
+	bits := 0x7FC00000 | uint32(frac>>41)
+	if sign {
+		bits |= 0x80000000
+	}
+	return math.Float32frombits(bits)
+}
+
+// Float64 returns the quiet float64 NaN with the given sign and the fraction
+// frac aligned at the most significant bit.
+func Float64(sign bool, frac uint64) float64 {
+	bits := 0x7FF8000000000000 | frac>>12
+	if sign {
+		bits |= 0x8000000000000000
+	}
+	return math.Float64frombits(bits)
+}
@@ -0,0 +1,90 @@
+// Package rounding implements the rounding decisions of the rounding modes of
+// big.Float for floating-point formats of fixed precision.
+package rounding
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+)
+
+// Up reports whether an inexact magnitude, truncated towards zero to the
+// precision of the result, is to be incremented by one unit in the last place
+// to round it in the given mode. neg specifies the sign of the number, odd
+// whether the truncated magnitude is odd, and half the comparison of the
+// truncated remainder with half a unit in the last place (-1, 0 or +1).
+func Up(mode big.RoundingMode, neg, odd bool, half int) bool {
+	switch mode {
+	case big.ToNearestEven:
+		return half > 0 || half == 0 && odd
+	case big.ToNearestAway:
+		return half >= 0
+	case big.ToZero:
+		return false
+	case big.AwayFromZero:
+		return true
+	case big.ToNegativeInf:
+		return neg
+	case big.ToPositiveInf:
+		return !neg
+	}
+	panic(fmt.Errorf("rounding: invalid rounding mode %v", mode))
+}
+
+// Inf reports whether a magnitude too large for the largest finite number is
+// rounded to infinity in the given mode, rather than to the largest finite
+// number. neg specifies the sign of the number.
+func Inf(mode big.RoundingMode, neg bool) bool {
+	switch mode {
+	case big.ToNearestEven, big.ToNearestAway, big.AwayFromZero:
+		return true
+	case big.ToZero:
+		return false
+	case big.ToNegativeInf:
+		return neg
+	case big.ToPositiveInf:
+		return !neg
+	}
+	panic(fmt.Errorf("rounding: invalid rounding mode %v", mode))
+}
+
+// Mode returns the rounding mode which rounds the magnitude of a number the
+// same way as mode rounds the number itself, for a number of the given sign;
+// the directed modes ToNegativeInf and ToPositiveInf round the magnitude
+// towards zero or away from zero depending on the sign.
+func Mode(mode big.RoundingMode, neg bool) big.RoundingMode {
+	switch {
+	case mode == big.ToNegativeInf && !neg, mode == big.ToPositiveInf && neg:
+		return big.ToZero
+	case mode == big.ToNegativeInf && neg, mode == big.ToPositiveInf && !neg:
+		return big.AwayFromZero
+	}
+	return mode
+}
+
+// Flags returns the exception flags raised by rounding the finite x in the
+// given mode to a floating-point format with prec-bit significands and the
+// exponents of normalized numbers, i.e. of their most significant bit, ranging
+// from emin to emax; acc is the accuracy of the rounded result. Tininess is
+// detected as specified by tininess.
+func Flags(x *big.Float, acc big.Accuracy, prec uint, emin, emax int, mode big.RoundingMode, tininess fenv.Tininess) fenv.Flags {
+	if acc == big.Exact {
+		return 0
+	}
+	flags := fenv.Inexact
+	// Exponent of the most significant bit of x rounded to prec bits with an
+	// unbounded exponent range.
+	y := new(big.Float).SetPrec(prec).SetMode(mode).Set(x)
+	exp := y.MantExp(nil) - 1
+	if exp > emax {
+		return flags | fenv.Overflow
+	}
+	if tininess == fenv.BeforeRounding {
+		exp = x.MantExp(nil) - 1
+	}
+	if exp < emin {
+		flags |= fenv.Underflow
+	}
+	return flags
+}
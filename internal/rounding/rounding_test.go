@@ -0,0 +1,66 @@
+package rounding
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestFlags(t *testing.T) {
+	// Flags raised by rounding to binary16, whose smallest positive normal
+	// number is 0x1p-14 and largest subnormal number 0x1.ff8p-15.
+	const (
+		prec = 11
+		emin = -14
+		emax = 15
+	)
+	golden := []struct {
+		x             string
+		mode          big.RoundingMode
+		acc           big.Accuracy
+		after, before fenv.Flags
+	}{
+		// Exact results.
+		{x: "0x1p-14", mode: big.ToNearestEven, acc: big.Exact},
+		{x: "0x1.ff8p-15", mode: big.ToNearestEven, acc: big.Exact},
+		{x: "0x1p-24", mode: big.ToNearestEven, acc: big.Exact},
+		// Normalized numbers.
+		{x: "0x1.001p0", mode: big.ToNearestEven, acc: big.Below, after: fenv.Inexact, before: fenv.Inexact},
+		{x: "0x1.0018p-14", mode: big.ToNearestEven, acc: big.Below, after: fenv.Inexact, before: fenv.Inexact},
+		// Rounded to 0x1p-14, which is tiny only before rounding; rounding to
+		// 11 bits with an unbounded exponent range gives 0x1p-14 as well.
+		{x: "0x1.ffep-15", mode: big.ToNearestEven, acc: big.Above, after: fenv.Inexact, before: fenv.Inexact | fenv.Underflow},
+		{x: "-0x1.ffep-15", mode: big.ToNearestEven, acc: big.Below, after: fenv.Inexact, before: fenv.Inexact | fenv.Underflow},
+		{x: "0x1.ffdp-15", mode: big.ToPositiveInf, acc: big.Above, after: fenv.Inexact, before: fenv.Inexact | fenv.Underflow},
+		// Rounded to 0x1p-14, which is tiny after rounding as well; rounding
+		// to 11 bits with an unbounded exponent range gives 0x1.ffcp-15 and
+		// 0x1.ffap-15 respectively.
+		{x: "0x1.ffcp-15", mode: big.ToNearestEven, acc: big.Above, after: fenv.Inexact | fenv.Underflow, before: fenv.Inexact | fenv.Underflow},
+		{x: "0x1.ff9p-15", mode: big.ToPositiveInf, acc: big.Above, after: fenv.Inexact | fenv.Underflow, before: fenv.Inexact | fenv.Underflow},
+		// Rounded to the largest subnormal number.
+		{x: "0x1.ff9p-15", mode: big.ToNearestEven, acc: big.Below, after: fenv.Inexact | fenv.Underflow, before: fenv.Inexact | fenv.Underflow},
+		{x: "0x1.ffep-15", mode: big.ToZero, acc: big.Below, after: fenv.Inexact | fenv.Underflow, before: fenv.Inexact | fenv.Underflow},
+		// Rounded to zero.
+		{x: "0x1p-26", mode: big.ToNearestEven, acc: big.Below, after: fenv.Inexact | fenv.Underflow, before: fenv.Inexact | fenv.Underflow},
+		// Overflow.
+		{x: "0x1.ffep15", mode: big.ToNearestEven, acc: big.Above, after: fenv.Inexact | fenv.Overflow, before: fenv.Inexact | fenv.Overflow},
+		{x: "0x1.ffep15", mode: big.ToZero, acc: big.Below, after: fenv.Inexact, before: fenv.Inexact},
+		{x: "-0x1p16", mode: big.ToZero, acc: big.Above, after: fenv.Inexact | fenv.Overflow, before: fenv.Inexact | fenv.Overflow},
+	}
+	for _, g := range golden {
+		x, _, err := big.ParseFloat(g.x, 0, 64, big.ToNearestEven)
+		if err != nil {
+			panic(err)
+		}
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			if got := Flags(x, g.acc, prec, emin, emax, g.mode, tininess); want != got {
+				t.Errorf("%s %v (tininess %v): flags mismatch; expected %v, got %v", g.x, g.mode, tininess, want, got)
+			}
+		}
+	}
+}
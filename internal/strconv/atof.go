@@ -0,0 +1,205 @@
+// Decimal and hexadecimal to binary floating point conversion.
+// Algorithm:
+//   1) scan mantissa digits into a multiprecision integer
+//   2) scale by the exponent, exactly for powers of two and integer results
+//   3) otherwise divide, keeping a sticky bit for the discarded remainder
+
+package strconv
+
+import (
+	"math/big"
+	stdstrconv "strconv"
+	"strings"
+)
+
+var (
+	// ErrRange indicates that a value is out of range for the target type.
+	ErrRange = stdstrconv.ErrRange
+	// ErrSyntax indicates that a value does not have the right syntax for the
+	// target type.
+	ErrSyntax = stdstrconv.ErrSyntax
+)
+
+// maxExp10 bounds the decimal exponent of parsed values; numbers of larger
+// magnitude overflow and numbers of smaller magnitude underflow in every
+// supported floating-point format.
+const maxExp10 = 100000
+
+// ParseBig parses s as a floating-point number, with the syntax accepted by
+// strconv.ParseFloat: an optionally signed decimal or hexadecimal
+// floating-point literal, or a case-insensitive "inf", "infinity" or "nan" with
+// an optional sign.
+//
+// The result is exact if the value may be represented using at least prec
+// bits. Otherwise it is rounded to odd: truncated to at least prec bits, with
+// the least significant bit set. Rounding the result to prec-2 bits or fewer
+// therefore gives the correctly rounded value of s. If s is a NaN, x holds its
+// sign and nan is true.
+//
+// Values of too large or too small magnitude for any supported format are
+// replaced by finite values which still overflow or underflow, respectively.
+func ParseBig(s string, prec uint) (x *big.Float, nan bool, err error) {
+	// Sign.
+	neg := false
+	t := s
+	if len(t) > 0 && (t[0] == '+' || t[0] == '-') {
+		neg = t[0] == '-'
+		t = t[1:]
+	}
+	x = new(big.Float)
+
+	// Special values.
+	switch strings.ToLower(t) {
+	case "inf", "infinity":
+		return x.SetInf(neg), false, nil
+	case "nan":
+		if neg {
+			x.Neg(x)
+		}
+		return x, true, nil
+	}
+
+	// Base prefix.
+	base := 10
+	if len(t) >= 2 && t[0] == '0' && (t[1] == 'x' || t[1] == 'X') {
+		base = 16
+		t = t[2:]
+	}
+
+	// Mantissa digits.
+	var digits []byte
+	sawdot, sawdigits := false, false
+	frac := 0
+	i := 0
+loop:
+	for ; i < len(t); i++ {
+		c := t[i]
+		switch {
+		case c == '.':
+			if sawdot {
+				break loop
+			}
+			sawdot = true
+		case '0' <= c && c <= '9', base == 16 && ('a' <= lower(c) && lower(c) <= 'f'):
+			sawdigits = true
+			if c == '0' && len(digits) == 0 {
+				// Ignore leading zeros.
+			} else {
+				digits = append(digits, c)
+			}
+			if sawdot {
+				frac++
+			}
+		default:
+			break loop
+		}
+	}
+	if !sawdigits {
+		return nil, false, ErrSyntax
+	}
+
+	// Exponent; required for hexadecimal floating-point literals.
+	exp := 0
+	expChar := byte('e')
+	if base == 16 {
+		expChar = 'p'
+	}
+	if i < len(t) && lower(t[i]) == expChar {
+		i++
+		esign := 1
+		if i < len(t) && (t[i] == '+' || t[i] == '-') {
+			if t[i] == '-' {
+				esign = -1
+			}
+			i++
+		}
+		if i >= len(t) || t[i] < '0' || t[i] > '9' {
+			return nil, false, ErrSyntax
+		}
+		for ; i < len(t) && '0' <= t[i] && t[i] <= '9'; i++ {
+			if exp < 10*maxExp10 {
+				exp = 10*exp + int(t[i]-'0')
+			}
+		}
+		exp *= esign
+	} else if base == 16 {
+		return nil, false, ErrSyntax
+	}
+	if i != len(t) {
+		return nil, false, ErrSyntax
+	}
+
+	// Zero.
+	if len(digits) == 0 {
+		if neg {
+			x.Neg(x)
+		}
+		return x, false, nil
+	}
+	mant, _ := new(big.Int).SetString(string(digits), base)
+
+	// Hexadecimal literals are exact.
+	if base == 16 {
+		exp -= 4 * frac
+		// Clamp the binary exponent to the range of big.Float.
+		const maxExp2 = 4 * maxExp10
+		switch {
+		case exp+mant.BitLen() > maxExp2:
+			exp = maxExp2
+		case exp < -maxExp2:
+			exp = -maxExp2
+		}
+		x.SetInt(mant)
+		x.SetMantExp(x, exp)
+		if neg {
+			x.Neg(x)
+		}
+		return x, false, nil
+	}
+
+	// Decimal literals are exact if integral, and otherwise rounded to odd.
+	exp -= frac
+	switch {
+	case len(digits)+exp > maxExp10:
+		// Overflow.
+		mant.SetInt64(1)
+		exp = maxExp10
+	case len(digits)+exp < -maxExp10:
+		// Underflow.
+		mant.SetInt64(1)
+		exp = -maxExp10
+	}
+	if exp >= 0 {
+		mant.Mul(mant, pow10(exp))
+		x.SetInt(mant)
+	} else {
+		// Shift the mantissa so that the quotient has at least prec bits.
+		d := pow10(-exp)
+		shift := int(prec) + d.BitLen() - mant.BitLen() + 1
+		if shift < 0 {
+			shift = 0
+		}
+		q, r := new(big.Int).QuoRem(mant.Lsh(mant, uint(shift)), d, new(big.Int))
+		if r.Sign() != 0 {
+			// Sticky bit.
+			q.SetBit(q, 0, 1)
+		}
+		x.SetInt(q)
+		x.SetMantExp(x, -shift)
+	}
+	if neg {
+		x.Neg(x)
+	}
+	return x, false, nil
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// lower returns the lower-case ASCII letter of c, or c if c is not an upper-case
+// ASCII letter.
+func lower(c byte) byte {
+	return c | ('x' - 'X')
+}
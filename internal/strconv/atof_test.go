@@ -0,0 +1,127 @@
+package strconv
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseBig(t *testing.T) {
+	golden := []struct {
+		in   string
+		prec uint
+		// Exact value of the result.
+		want string
+		// Result rounded to prec-2 bits using round half to even.
+		round string
+		nan   bool
+		err   error
+	}{
+		// Special values.
+		{in: "0", prec: 8, want: "0x0p0", round: "0x0p0"},
+		{in: "-0.000", prec: 8, want: "-0x0p0", round: "-0x0p0"},
+		{in: "-Inf", prec: 8, want: "-Inf", round: "-Inf"},
+		{in: "infinity", prec: 8, want: "+Inf", round: "+Inf"},
+		{in: "NaN", prec: 8, want: "0x0p0", nan: true},
+		{in: "-nan", prec: 8, want: "-0x0p0", nan: true},
+
+		// Exact values.
+		{in: "1e3", prec: 8, want: "0x3e8p0", round: "0x3e0p0"},
+		{in: "0.5", prec: 8, want: "0x1p-1", round: "0x1p-1"},
+		{in: "-0x1.8p1", prec: 8, want: "-0x3p0", round: "-0x3p0"},
+		{in: "0x1.fffp0", prec: 8, want: "0x1fffp-12", round: "0x2p0"},
+		// Inexact values are truncated, with the sticky bit set; 0.1 is
+		// 0x1.999...p-4, truncated to 0x666p-14.
+		{in: "0.1", prec: 10, want: "0x667p-14", round: "0xcdp-11"},
+		// Halfway values are exact, and rounded to even.
+		{in: "1.25", prec: 4, want: "0x5p-2", round: "0x1p0"},
+		{in: "1.75", prec: 4, want: "0x7p-2", round: "0x2p0"},
+		{in: "-1.25", prec: 4, want: "-0x5p-2", round: "-0x1p0"},
+		// Values just above and below halfway values have the sticky bit set.
+		{in: "1.2500000000000000001", prec: 4, want: "0x29p-5", round: "0x3p-1"},
+		{in: "1.2499999999999999999", prec: 4, want: "0x27p-5", round: "0x1p0"},
+		{in: "-1.7500000000000000001", prec: 4, want: "-0x39p-5", round: "-0x2p0"},
+		{in: "1.7499999999999999999", prec: 4, want: "0x37p-5", round: "0x3p-1"},
+
+		// Invalid syntax.
+		{in: "", prec: 8, err: ErrSyntax},
+		{in: "+", prec: 8, err: ErrSyntax},
+		{in: ".", prec: 8, err: ErrSyntax},
+		{in: "1e", prec: 8, err: ErrSyntax},
+		{in: "1e+", prec: 8, err: ErrSyntax},
+		{in: "0x1.8", prec: 8, err: ErrSyntax},
+		{in: "0x1g", prec: 8, err: ErrSyntax},
+		{in: "1.2.3", prec: 8, err: ErrSyntax},
+		{in: "1p3", prec: 8, err: ErrSyntax},
+		{in: "infinit", prec: 8, err: ErrSyntax},
+	}
+	for _, g := range golden {
+		x, nan, err := ParseBig(g.in, g.prec)
+		if err != g.err {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if nan != g.nan {
+			t.Errorf("%q: NaN mismatch; expected %v, got %v", g.in, g.nan, nan)
+		}
+		want := parseHex(g.want)
+		if x.Cmp(want) != 0 || x.Signbit() != want.Signbit() {
+			t.Errorf("%q: mismatch; expected %v, got %v", g.in, want.Text('p', 0), x.Text('p', 0))
+		}
+		if nan {
+			continue
+		}
+		round := new(big.Float).SetPrec(g.prec - 2).Set(x)
+		if want := parseHex(g.round); round.Cmp(want) != 0 || round.Signbit() != want.Signbit() {
+			t.Errorf("%q: rounding mismatch; expected %v, got %v", g.in, want.Text('p', 0), round.Text('p', 0))
+		}
+	}
+}
+
+func TestParseBigRange(t *testing.T) {
+	// Exponents of values too large or too small in magnitude for any supported
+	// format are clamped; the result is x = 0.1xxx_2 * 2^exp.
+	golden := []struct {
+		in  string
+		exp int
+	}{
+		// 10^100000
+		{in: "1e100000", exp: 332193},
+		{in: "1e200000", exp: 332193},
+		{in: "-123e99998", exp: 332193},
+		{in: "1e99999999999999999999", exp: 332193},
+		// 10^-100000
+		{in: "1e-100000", exp: -332192},
+		{in: "1e-200000", exp: -332192},
+		{in: "-0.0123e-100000", exp: -332192},
+		{in: "1e-99999999999999999999", exp: -332192},
+		// 2^400000 and 2^-400000
+		{in: "0x1p400000", exp: 400001},
+		{in: "0x1p999999", exp: 400001},
+		{in: "-0x0.1p999999", exp: 400001},
+		{in: "0x1p-400000", exp: -399999},
+		{in: "0x1p-999999", exp: -399999},
+	}
+	for _, g := range golden {
+		x, _, err := ParseBig(g.in, 8)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", g.in, err)
+			continue
+		}
+		if exp := x.MantExp(nil); g.exp != exp {
+			t.Errorf("%q: exponent mismatch; expected %d, got %d", g.in, g.exp, exp)
+		}
+	}
+}
+
+// parseHex returns the value of the hexadecimal floating-point literal s, or
+// an infinity if s is "+Inf" or "-Inf".
+func parseHex(s string) *big.Float {
+	x, _, err := big.ParseFloat(s, 0, 1000, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
@@ -0,0 +1,164 @@
+package strconv
+
+import "fmt"
+
+// Format implements fmt.Formatter for the floating-point number x, in the same
+// way that package fmt formats float64 values, using x.Text to convert x to a
+// string.
+//
+// The verbs 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v' ('g') are
+// supported, as are width, precision and the '+', '-', ' ', '0' and '#' flags.
+// As for float64 values, the default precision is 6 for 'e', 'E', 'f' and 'F',
+// and the smallest number of digits necessary to represent x uniquely for the
+// other verbs.
+func Format(s fmt.State, verb rune, x interface{ Text(byte, int) string }) {
+	sharp := s.Flag('#')
+	prec := -1
+	switch verb {
+	case 'v':
+		// %#v does not force a decimal point.
+		verb = 'g'
+		sharp = false
+	case 'b', 'g', 'G', 'x', 'X':
+	case 'e', 'E', 'f':
+		prec = 6
+	case 'F':
+		verb = 'f'
+		prec = 6
+	default:
+		fmt.Fprintf(s, "%%!%c(%T=%s)", verb, x, x.Text('g', -1))
+		return
+	}
+	// Explicit precision in format specifier overrules default precision.
+	if p, ok := s.Precision(); ok {
+		prec = p
+	}
+	plus, space := s.Flag('+'), s.Flag(' ')
+	// Zero padding is allowed only to the left.
+	zero := s.Flag('0') && !s.Flag('-')
+
+	// Format number, reserving space for leading + sign if needed.
+	num := append([]byte{'+'}, x.Text(byte(verb), prec)...)
+	if num[1] == '-' || num[1] == '+' {
+		num = num[1:]
+	}
+	// space means to add a leading space instead of a "+" sign unless the
+	// sign is explicitly asked for by plus.
+	if space && num[0] == '+' && !plus {
+		num[0] = ' '
+	}
+	// Special handling for infinities and NaN, which don't look like a number
+	// so shouldn't be padded with zeros.
+	if num[1] == 'I' || num[1] == 'N' {
+		// Remove sign before NaN if not asked for.
+		if num[1] == 'N' && !space && !plus {
+			num = num[1:]
+		}
+		pad(s, num, false)
+		return
+	}
+	// The sharp flag forces printing a decimal point for non-binary formats
+	// and retains trailing zeros, which we may need to restore.
+	if sharp && verb != 'b' {
+		digits := 0
+		switch verb {
+		case 'g', 'G', 'x':
+			digits = prec
+			// If no precision is set explicitly use a precision of 6.
+			if digits == -1 {
+				digits = 6
+			}
+		}
+
+		var tail []byte
+		hasDecimalPoint := false
+		sawNonzeroDigit := false
+		// Starting from i = 1 to skip sign at num[0].
+		for i := 1; i < len(num); i++ {
+			switch num[i] {
+			case '.':
+				hasDecimalPoint = true
+			case 'p', 'P':
+				tail = append(tail, num[i:]...)
+				num = num[:i]
+			case 'e', 'E':
+				if verb != 'x' && verb != 'X' {
+					tail = append(tail, num[i:]...)
+					num = num[:i]
+					break
+				}
+				fallthrough
+			default:
+				if num[i] != '0' {
+					sawNonzeroDigit = true
+				}
+				// Count significant digits after the first non-zero digit.
+				if sawNonzeroDigit {
+					digits--
+				}
+			}
+		}
+		if !hasDecimalPoint {
+			// Leading digit 0 should contribute once to digits.
+			if len(num) == 2 && num[1] == '0' {
+				digits--
+			}
+			num = append(num, '.')
+		}
+		for digits > 0 {
+			num = append(num, '0')
+			digits--
+		}
+		num = append(num, tail...)
+	}
+	// We want a sign if asked for and if the sign is not positive.
+	if plus || num[0] != '+' {
+		// If we're zero padding to the left we want the sign before the
+		// leading zeros. Achieve this by writing the sign out and then padding
+		// the unsigned number.
+		if width, ok := s.Width(); ok && zero && width > len(num) {
+			s.Write(num[:1])
+			writePadding(s, width-len(num), zero)
+			s.Write(num[1:])
+			return
+		}
+		pad(s, num, zero)
+		return
+	}
+	// No sign to show and the number is positive; just print the unsigned
+	// number.
+	pad(s, num[1:], zero)
+}
+
+// pad writes b to s, padded to the width of s on the left, with zeros if zero
+// is set, or on the right if the '-' flag is set.
+func pad(s fmt.State, b []byte, zero bool) {
+	width, ok := s.Width()
+	if !ok || width <= len(b) {
+		s.Write(b)
+		return
+	}
+	if !s.Flag('-') {
+		// left padding
+		writePadding(s, width-len(b), zero)
+		s.Write(b)
+	} else {
+		// right padding
+		s.Write(b)
+		writePadding(s, width-len(b), false)
+	}
+}
+
+// writePadding writes n bytes of padding to s; zeros if zero is set and spaces
+// otherwise.
+func writePadding(s fmt.State, n int, zero bool) {
+	padByte := byte(' ')
+	if zero {
+		padByte = '0'
+	}
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = padByte
+	}
+	s.Write(buf)
+}
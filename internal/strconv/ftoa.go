@@ -10,11 +10,7 @@
 
 package strconv
 
-import (
-	"math"
-
-	"github.com/mewmew/float/binary16"
-)
+import "math"
 
 var optimize = true // can change for testing
 
@@ -59,14 +55,58 @@ func AppendFloat(dst []byte, f float64, fmt byte, prec, bitSize int) []byte {
 	return genericFtoa(dst, f, fmt, prec, bitSize)
 }
 
+// float16bits returns the IEEE 754 half precision binary representation of the
+// half precision floating-point number nearest to f (round half to even).
+func float16bits(f float64) uint64 {
+	b := math.Float64bits(f)
+	sign := b >> 48 & 0x8000
+	exp := int(b>>52) & 0x7FF
+	mant := b & (1<<52 - 1)
+	switch exp {
+	case 0x7FF:
+		// Inf, NaN
+		if mant != 0 {
+			return sign | 0x7E00
+		}
+		return sign | 0x7C00
+	case 0:
+		// Denormalized float64 numbers round to zero.
+		return sign
+	}
+	mant |= 1 << 52
+	// Biased half precision exponent, and the number of mantissa bits to
+	// discard; denormalized numbers discard more.
+	exp += 15 - 1023
+	shift := uint(52 - 10)
+	if exp < 1 {
+		shift += uint(1 - exp)
+		if shift > 63 {
+			shift = 63
+		}
+		exp = 1
+	}
+	// Round to nearest; ties to even.
+	m := mant >> shift
+	rem := mant & (1<<shift - 1)
+	half := uint64(1) << (shift - 1)
+	if rem > half || (rem == half && m&1 != 0) {
+		m++
+	}
+	// The lead bit of normalized numbers is added to the exponent, and a carry
+	// out of the mantissa overflows to +-Inf.
+	v := uint64(exp-1)<<10 + m
+	if v >= 0x7C00 {
+		return sign | 0x7C00
+	}
+	return sign | v
+}
+
 func genericFtoa(dst []byte, val float64, fmt byte, prec, bitSize int) []byte {
 	var bits uint64
 	var flt *floatInfo
 	switch bitSize {
 	case 16:
-		f, acc := binary16.NewFromFloat64(val)
-		_ = acc
-		bits = uint64(f.Bits())
+		bits = float16bits(val)
 		flt = &float16info
 	case 32:
 		bits = uint64(math.Float32bits(float32(val)))
@@ -382,14 +422,14 @@ func fmtE(dst []byte, neg bool, d decimalSlice, prec int, fmt byte) []byte {
 	}
 	dst = append(dst, ch)
 
-	// dd or ddd
+	// dd, ddd or more
 	switch {
 	case exp < 10:
 		dst = append(dst, '0', byte(exp)+'0')
 	case exp < 100:
 		dst = append(dst, byte(exp/10)+'0', byte(exp%10)+'0')
 	default:
-		dst = append(dst, byte(exp/100)+'0', byte(exp/10)%10+'0', byte(exp%10)+'0')
+		dst, _ = formatBits(dst, uint64(exp), 10, false, true)
 	}
 
 	return dst
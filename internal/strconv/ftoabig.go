@@ -0,0 +1,325 @@
+// Binary to decimal conversion of multi-precision floating-point numbers.
+// Algorithm:
+//   1) scale the exact value, and the bounds of its rounding interval, by a
+//      power of ten into integer quotients with a common denominator
+//   2) decrease the power of ten until a quotient, rounded down or up, lies
+//      within the bounds (shortest), or round the quotient of the requested
+//      power of ten to nearest even (fixed precision)
+//   3) read digits out & format
+
+package strconv
+
+import (
+	"math/big"
+	"strings"
+)
+
+// FormatBig converts the floating-point number x to a string, according to the
+// format fmt and precision prec, as for FormatFloat. x is a value of a binary
+// floating-point format with mantbits explicit mantissa bits and minimum
+// exponent minexp; i.e. |x| = mant * 2^(exp-mantbits) with mant < 2^(mantbits+1)
+// and exp >= minexp. The special precision -1 uses the smallest number of digits
+// necessary to identify x uniquely in this format.
+//
+// In addition to the formats of FormatFloat, fmt may be 'x' (-0xd.ddddp±dd, a
+// hexadecimal mantissa and a binary exponent) or 'X' (upper-case 'x').
+func FormatBig(x *big.Float, fmt byte, prec int, mantbits uint, minexp int) string {
+	if x.IsInf() {
+		return infString(x)
+	}
+	mant, exp := bigMantExp(x)
+	// Exponent of the least significant mantissa bit.
+	lsb := minexp - int(mantbits)
+	if mant.Sign() != 0 && exp+mant.BitLen()-1-int(mantbits) > lsb {
+		lsb = exp + mant.BitLen() - 1 - int(mantbits)
+	}
+	mant.Lsh(mant, uint(exp-lsb))
+	switch fmt {
+	case 'b':
+		return string(fmtBigB(nil, x.Signbit(), mant, lsb))
+	case 'x', 'X':
+		return string(fmtBigX(nil, x, fmt, prec))
+	}
+	if prec >= 0 || mant.Sign() == 0 {
+		return string(bigFtoaDigits(nil, x, nil, nil, false, fmt, prec))
+	}
+
+	// |x| = mant << lsb
+	// Next highest floating point number is mant+1 << lsb.
+	// Our upper bound is halfway between, mant*2+1 << lsb-1.
+	upper := new(big.Int).Lsh(mant, 1)
+	upper.SetBit(upper, 0, 1)
+	// Next lowest floating point number is mant-1 << lsb, unless mant-1 drops
+	// the significant bit and lsb is not the minimum exponent, in which case
+	// the next lowest is mant*2-1 << lsb-1. Our lower bound is halfway between.
+	lower := new(big.Int).Lsh(mant, 1)
+	explo := lsb - 1
+	if mant.BitLen()-1 == int(mantbits) && lsb > minexp-int(mantbits) && isPow2(mant) {
+		lower.Lsh(lower, 1)
+		explo--
+	}
+	lower.Sub(lower, big.NewInt(1))
+	lo := new(big.Float).SetInt(lower)
+	lo.SetMantExp(lo, explo)
+	hi := new(big.Float).SetInt(upper)
+	hi.SetMantExp(hi, lsb-1)
+	// The bounds are possible outputs only if the mantissa is even, so that
+	// round half to even would round to x and not to its neighbours.
+	inclusive := mant.Bit(0) == 0
+	return string(bigFtoaDigits(nil, x, lo, hi, inclusive, fmt, prec))
+}
+
+// FormatBigRange converts the floating-point number x to a string, according to
+// the format fmt and precision prec, as for FormatBig. The special precision -1
+// uses the smallest number of digits necessary to give a decimal number between
+// lower and upper, the bounds of the values that round to x; the bounds are
+// included if inclusive is set. The 'b' format uses the smallest integer
+// mantissa representing x exactly.
+func FormatBigRange(x, lower, upper *big.Float, inclusive bool, fmt byte, prec int) string {
+	if x.IsInf() {
+		return infString(x)
+	}
+	switch fmt {
+	case 'b':
+		mant, exp := bigMantExp(x)
+		return string(fmtBigB(nil, x.Signbit(), mant, exp))
+	case 'x', 'X':
+		return string(fmtBigX(nil, x, fmt, prec))
+	}
+	if x.Signbit() {
+		// The bounds of |x|.
+		lower, upper = upper, lower
+	}
+	return string(bigFtoaDigits(nil, x, lower, upper, inclusive, fmt, prec))
+}
+
+// infString returns the string representation of the infinity x.
+func infString(x *big.Float) string {
+	if x.Signbit() {
+		return "-Inf"
+	}
+	return "+Inf"
+}
+
+// bigFtoaDigits formats the finite x in the decimal format fmt. If prec is
+// negative, the shortest decimal between the absolute values of lower and upper
+// is used.
+func bigFtoaDigits(dst []byte, x, lower, upper *big.Float, inclusive bool, fmt byte, prec int) []byte {
+	var digs decimalSlice
+	shortest := prec < 0
+	if shortest {
+		digs = shortestBigDecimal(x, lower, upper, inclusive)
+		// Precision for shortest representation mode.
+		switch fmt {
+		case 'e', 'E':
+			prec = max(digs.nd-1, 0)
+		case 'f':
+			prec = max(digs.nd-digs.dp, 0)
+		case 'g', 'G':
+			prec = digs.nd
+		}
+	} else {
+		// Round appropriately.
+		switch fmt {
+		case 'e', 'E':
+			digs = roundBigDecimal(x, prec+1, false)
+		case 'f':
+			digs = roundBigDecimal(x, prec, true)
+		case 'g', 'G':
+			if prec == 0 {
+				prec = 1
+			}
+			digs = roundBigDecimal(x, prec, false)
+		}
+	}
+	return formatDigits(dst, shortest, x.Signbit(), digs, prec, fmt)
+}
+
+// shortestBigDecimal returns the shortest decimal representation of |x| which
+// lies between the absolute values of lower and upper (inclusive if inclusive
+// is set). Of the two candidates of a given length, the one nearest to |x| is
+// used (round half to even).
+func shortestBigDecimal(x, lower, upper *big.Float, inclusive bool) decimalSlice {
+	mx, ex := bigMantExp(x)
+	if mx.Sign() == 0 {
+		return decimalSlice{}
+	}
+	ml, el := bigMantExp(lower)
+	mu, eu := bigMantExp(upper)
+	// Use a common binary exponent.
+	e2 := min(ex, min(el, eu))
+	mx.Lsh(mx, uint(ex-e2))
+	ml.Lsh(ml, uint(el-e2))
+	mu.Lsh(mu, uint(eu-e2))
+	// Start with a power of ten greater than x, so that the first candidate
+	// has a single digit.
+	e10 := (e2+mx.BitLen())*30103/100000 + 1
+	// m/den = |v| / 10^e10, for v in x, lower and upper.
+	den := big.NewInt(1)
+	if e2 < 0 {
+		den.Lsh(den, uint(-e2))
+	} else {
+		mx.Lsh(mx, uint(e2))
+		ml.Lsh(ml, uint(e2))
+		mu.Lsh(mu, uint(e2))
+	}
+	if e10 > 0 {
+		den.Mul(den, pow10(e10))
+	} else {
+		p := pow10(-e10)
+		mx.Mul(mx, p)
+		ml.Mul(ml, p)
+		mu.Mul(mu, p)
+	}
+	ten := big.NewInt(10)
+	q, r := new(big.Int), new(big.Int)
+	down, up := new(big.Int), new(big.Int)
+	for ; ; e10-- {
+		q.QuoRem(mx, den, r)
+		if r.Sign() == 0 {
+			// Exact.
+			return bigDecimal(q, e10)
+		}
+		// Candidates q * 10^e10 and (q+1) * 10^e10.
+		down.Sub(mx, r)
+		up.Add(down, den)
+		c := down.Cmp(ml)
+		okdown := q.Sign() != 0 && (c > 0 || inclusive && c == 0)
+		c = up.Cmp(mu)
+		okup := c < 0 || inclusive && c == 0
+		if okdown && okup {
+			// Round to nearest; ties to even.
+			c = r.Lsh(r, 1).Cmp(den)
+			okdown = c < 0 || c == 0 && q.Bit(0) == 0
+		}
+		switch {
+		case okdown:
+			return bigDecimal(q, e10)
+		case okup:
+			return bigDecimal(q.Add(q, big.NewInt(1)), e10)
+		}
+		if e10 > 0 {
+			den.Quo(den, ten)
+		} else {
+			mx.Mul(mx, ten)
+			ml.Mul(ml, ten)
+			mu.Mul(mu, ten)
+		}
+	}
+}
+
+// roundBigDecimal returns the decimal representation of |x| rounded to n
+// significant digits or, if fixed is set, to n digits after the decimal point
+// (round half to even).
+func roundBigDecimal(x *big.Float, n int, fixed bool) decimalSlice {
+	mant, exp := bigMantExp(x)
+	if mant.Sign() == 0 {
+		return decimalSlice{}
+	}
+	e10 := -n
+	if !fixed {
+		e10 += bigDecimalPoint(mant, exp)
+	}
+	num, den := scaleBig(mant, exp, e10)
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if c := r.Lsh(r, 1).Cmp(den); c > 0 || c == 0 && q.Bit(0) != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return bigDecimal(q, e10)
+}
+
+// bigDecimalPoint returns the position of the decimal point of mant * 2^exp;
+// i.e. the exponent dp such that 10^(dp-1) <= mant * 2^exp < 10^dp.
+func bigDecimalPoint(mant *big.Int, exp int) int {
+	dp := (exp + mant.BitLen() - 1) * 30103 / 100000
+	for {
+		num, den := scaleBig(mant, exp, dp-1)
+		switch {
+		case num.Cmp(den) < 0:
+			dp--
+		case num.Cmp(den.Mul(den, big.NewInt(10))) >= 0:
+			dp++
+		default:
+			return dp
+		}
+	}
+}
+
+// scaleBig returns integers num and den such that num/den = mant * 2^exp / 10^e10.
+func scaleBig(mant *big.Int, exp, e10 int) (num, den *big.Int) {
+	num = new(big.Int).Set(mant)
+	den = big.NewInt(1)
+	if exp > 0 {
+		num.Lsh(num, uint(exp))
+	} else {
+		den.Lsh(den, uint(-exp))
+	}
+	if e10 > 0 {
+		den.Mul(den, pow10(e10))
+	} else {
+		num.Mul(num, pow10(-e10))
+	}
+	return num, den
+}
+
+// bigDecimal returns the decimal representation of q * 10^e10, with trailing
+// zeros removed.
+func bigDecimal(q *big.Int, e10 int) decimalSlice {
+	if q.Sign() == 0 {
+		return decimalSlice{}
+	}
+	s := q.String()
+	nd := len(strings.TrimRight(s, "0"))
+	return decimalSlice{d: []byte(s[:nd]), nd: nd, dp: len(s) + e10}
+}
+
+// bigMantExp returns the integer mantissa and exponent of the exact value of |x|,
+// such that |x| = mant * 2^exp.
+func bigMantExp(x *big.Float) (mant *big.Int, exp int) {
+	if x.Sign() == 0 {
+		return new(big.Int), 0
+	}
+	prec := int(x.MinPrec())
+	exp = x.MantExp(nil) - prec
+	y := new(big.Float).Abs(x)
+	mant, _ = y.SetMantExp(y, -exp).Int(nil)
+	return mant, exp
+}
+
+// isPow2 reports whether the positive x is a power of two.
+func isPow2(x *big.Int) bool {
+	return x.TrailingZeroBits() == uint(x.BitLen()-1)
+}
+
+// %b: -ddddddddp±ddd
+func fmtBigB(dst []byte, neg bool, mant *big.Int, exp int) []byte {
+	// sign
+	if neg {
+		dst = append(dst, '-')
+	}
+
+	// mantissa
+	dst = mant.Append(dst, 10)
+
+	// p
+	dst = append(dst, 'p')
+
+	// ±exponent
+	if exp >= 0 {
+		dst = append(dst, '+')
+	}
+	dst, _ = formatBits(dst, uint64(exp), 10, exp < 0, true)
+
+	return dst
+}
+
+// %x: -0x1.yyyyyyyyp±ddd
+func fmtBigX(dst []byte, x *big.Float, fmt byte, prec int) []byte {
+	// Round half to even.
+	y := new(big.Float).Copy(x).SetMode(big.ToNearestEven)
+	s := y.Text('x', prec)
+	if fmt == 'X' {
+		s = strings.ToUpper(s)
+	}
+	return append(dst, s...)
+}
@@ -0,0 +1,36 @@
+package strconv
+
+// ParseLLVMHex parses s as an LLVM IR hexadecimal floating-point literal of the
+// given kind; "0x" followed by the kind letter ('H', 'K', 'L', 'M' or 'R') and
+// the hexadecimal digits of each word, of the given widths in bits, in order.
+// The digits of each word are upper- or lower-case and must all be present.
+func ParseLLVMHex(s string, kind byte, widths ...uint) ([]uint64, error) {
+	if len(s) < 3 || s[0] != '0' || s[1] != 'x' || s[2] != kind {
+		return nil, ErrSyntax
+	}
+	t := s[3:]
+	words := make([]uint64, len(widths))
+	for i, width := range widths {
+		n := int(width+3) / 4
+		if len(t) < n {
+			return nil, ErrSyntax
+		}
+		for _, c := range []byte(t[:n]) {
+			var d byte
+			switch {
+			case '0' <= c && c <= '9':
+				d = c - '0'
+			case 'a' <= lower(c) && lower(c) <= 'f':
+				d = lower(c) - 'a' + 10
+			default:
+				return nil, ErrSyntax
+			}
+			words[i] = words[i]<<4 | uint64(d)
+		}
+		t = t[n:]
+	}
+	if len(t) != 0 {
+		return nil, ErrSyntax
+	}
+	return words, nil
+}
@@ -0,0 +1,52 @@
+package strconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLLVMHex(t *testing.T) {
+	golden := []struct {
+		in     string
+		kind   byte
+		widths []uint
+		want   []uint64
+		err    error
+	}{
+		// half 1.0
+		{in: "0xH3C00", kind: 'H', widths: []uint{16}, want: []uint64{0x3C00}},
+		// bfloat -2.0
+		{in: "0xRc000", kind: 'R', widths: []uint{16}, want: []uint64{0xC000}},
+		// x86_fp80 1.0; sign and exponent, then significand.
+		{in: "0xK3FFF8000000000000000", kind: 'K', widths: []uint{16, 64}, want: []uint64{0x3FFF, 0x8000000000000000}},
+		// fp128 1.0; least significant 64 bits first.
+		{in: "0xL00000000000000003FFF000000000000", kind: 'L', widths: []uint{64, 64}, want: []uint64{0x0000000000000000, 0x3FFF000000000000}},
+		// ppc_fp128 1.0 and 1.0 - 2^-60; high part first.
+		{in: "0xM3FF00000000000000000000000000000", kind: 'M', widths: []uint{64, 64}, want: []uint64{0x3FF0000000000000, 0x0000000000000000}},
+		{in: "0xM3ff0000000000000bc30000000000000", kind: 'M', widths: []uint{64, 64}, want: []uint64{0x3FF0000000000000, 0xBC30000000000000}},
+
+		// Invalid syntax.
+		{in: "", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0x", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0xH", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0X3C00", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0xh3C00", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0xR3C00", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0x3C00", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0xH3C0", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0xH3C000", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0xH3G00", kind: 'H', widths: []uint{16}, err: ErrSyntax},
+		{in: "0xK3FFF800000000000000", kind: 'K', widths: []uint{16, 64}, err: ErrSyntax},
+		{in: "0xL3FFF0000000000000000000000000000", kind: 'M', widths: []uint{64, 64}, err: ErrSyntax},
+	}
+	for _, g := range golden {
+		got, err := ParseLLVMHex(g.in, g.kind, g.widths...)
+		if err != g.err {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if !reflect.DeepEqual(g.want, got) {
+			t.Errorf("%q: words mismatch; expected %#x, got %#x", g.in, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package wideint implements 128-bit and 256-bit unsigned integer arithmetic.
+package wideint
+
+import "math/bits"
+
+// Uint128 is a 128-bit unsigned integer.
+type Uint128 struct {
+	Hi, Lo uint64
+}
+
+// Add returns the sum x+y.
+func (x Uint128) Add(y Uint128) Uint128 {
+	lo, carry := bits.Add64(x.Lo, y.Lo, 0)
+	hi, _ := bits.Add64(x.Hi, y.Hi, carry)
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// Sub returns the difference x-y.
+func (x Uint128) Sub(y Uint128) Uint128 {
+	lo, borrow := bits.Sub64(x.Lo, y.Lo, 0)
+	hi, _ := bits.Sub64(x.Hi, y.Hi, borrow)
+	return Uint128{Hi: hi, Lo: lo}
+}
+
+// Cmp compares x and y and returns -1 if x < y, 0 if x == y and +1 if x > y.
+func (x Uint128) Cmp(y Uint128) int {
+	switch {
+	case x.Hi < y.Hi:
+		return -1
+	case x.Hi > y.Hi:
+		return +1
+	case x.Lo < y.Lo:
+		return -1
+	case x.Lo > y.Lo:
+		return +1
+	}
+	return 0
+}
+
+// Lsh returns x shifted left by s bits.
+func (x Uint128) Lsh(s uint) Uint128 {
+	switch {
+	case s >= 128:
+		return Uint128{}
+	case s >= 64:
+		return Uint128{Hi: x.Lo << (s - 64)}
+	case s == 0:
+		return x
+	}
+	return Uint128{Hi: x.Hi<<s | x.Lo>>(64-s), Lo: x.Lo << s}
+}
+
+// Rsh returns x shifted right by s bits.
+func (x Uint128) Rsh(s uint) Uint128 {
+	switch {
+	case s >= 128:
+		return Uint128{}
+	case s >= 64:
+		return Uint128{Lo: x.Hi >> (s - 64)}
+	case s == 0:
+		return x
+	}
+	return Uint128{Hi: x.Hi >> s, Lo: x.Lo>>s | x.Hi<<(64-s)}
+}
+
+// Jam returns x shifted right by s bits, with the bits shifted out jammed into
+// the least significant bit of the result.
+func (x Uint128) Jam(s uint) Uint128 {
+	z := x.Rsh(s)
+	if z.Lsh(s) != x {
+		z.Lo |= 1
+	}
+	return z
+}
+
+// BitLen returns the minimum number of bits required to represent x.
+func (x Uint128) BitLen() int {
+	if x.Hi != 0 {
+		return 64 + bits.Len64(x.Hi)
+	}
+	return bits.Len64(x.Lo)
+}
+
+// IsZero reports whether x is zero.
+func (x Uint128) IsZero() bool {
+	return x.Hi == 0 && x.Lo == 0
+}
+
+// Mul returns the 256-bit product x*y.
+func (x Uint128) Mul(y Uint128) Uint256 {
+	// Schoolbook multiplication of 64-bit words.
+	var z Uint256
+	for i, xi := range [2]uint64{x.Lo, x.Hi} {
+		var carry uint64
+		for j, yj := range [2]uint64{y.Lo, y.Hi} {
+			hi, lo := bits.Mul64(xi, yj)
+			lo, c := bits.Add64(lo, z[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			z[i+j] = lo
+			carry = hi
+		}
+		z[i+2] = carry
+	}
+	return z
+}
+
+// Uint256 is a 256-bit unsigned integer, stored as 64-bit words in order of
+// increasing significance.
+type Uint256 [4]uint64
+
+// Wide returns x as a 256-bit unsigned integer.
+func (x Uint128) Wide() Uint256 {
+	return Uint256{x.Lo, x.Hi, 0, 0}
+}
+
+// Add returns the sum x+y.
+func (x Uint256) Add(y Uint256) Uint256 {
+	var z Uint256
+	var carry uint64
+	for i := range z {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	return z
+}
+
+// Sub returns the difference x-y.
+func (x Uint256) Sub(y Uint256) Uint256 {
+	var z Uint256
+	var borrow uint64
+	for i := range z {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	return z
+}
+
+// Cmp compares x and y and returns -1 if x < y, 0 if x == y and +1 if x > y.
+func (x Uint256) Cmp(y Uint256) int {
+	for i := len(x) - 1; i >= 0; i-- {
+		switch {
+		case x[i] < y[i]:
+			return -1
+		case x[i] > y[i]:
+			return +1
+		}
+	}
+	return 0
+}
+
+// Lsh returns x shifted left by s bits.
+func (x Uint256) Lsh(s uint) Uint256 {
+	var z Uint256
+	n, r := int(s/64), s%64
+	for i := len(z) - 1; i >= n; i-- {
+		z[i] = x[i-n] << r
+		if r != 0 && i-n-1 >= 0 {
+			z[i] |= x[i-n-1] >> (64 - r)
+		}
+	}
+	return z
+}
+
+// Rsh returns x shifted right by s bits.
+func (x Uint256) Rsh(s uint) Uint256 {
+	var z Uint256
+	n, r := int(s/64), s%64
+	for i := 0; i+n < len(x); i++ {
+		z[i] = x[i+n] >> r
+		if r != 0 && i+n+1 < len(x) {
+			z[i] |= x[i+n+1] << (64 - r)
+		}
+	}
+	return z
+}
+
+// Jam returns x shifted right by s bits, with the bits shifted out jammed into
+// the least significant bit of the result.
+func (x Uint256) Jam(s uint) Uint256 {
+	z := x.Rsh(s)
+	if z.Lsh(s) != x {
+		z[0] |= 1
+	}
+	return z
+}
+
+// BitLen returns the minimum number of bits required to represent x.
+func (x Uint256) BitLen() int {
+	for i := len(x) - 1; i >= 0; i-- {
+		if x[i] != 0 {
+			return 64*i + bits.Len64(x[i])
+		}
+	}
+	return 0
+}
+
+// IsZero reports whether x is zero.
+func (x Uint256) IsZero() bool {
+	return x == Uint256{}
+}
+
+// Narrow returns x as a 128-bit unsigned integer, with the bits that do not fit
+// shifted out and jammed into the least significant bit of the result, and the
+// number of bits shifted out.
+func (x Uint256) Narrow() (Uint128, int) {
+	s := x.BitLen() - 128
+	if s < 0 {
+		s = 0
+	}
+	z := x.Jam(uint(s))
+	return Uint128{Hi: z[1], Lo: z[0]}, s
+}
@@ -0,0 +1,122 @@
+package mbf
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// ### [ Single ] ##############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g.
+func (f Single) Cmp(g Single) int {
+	x, _ := f.Big()
+	y, _ := g.Big()
+	return x.Cmp(y)
+}
+
+// Less reports whether f < g.
+func (f Single) Less(g Single) bool {
+	return f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. All zeros are equal.
+func (f Single) Equal(g Single) bool {
+	return f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with all zeros ordered as equal.
+func (f Single) TotalOrder(g Single) bool {
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), f.Cmp(g))
+}
+
+// Class returns the class of f.
+func (f Single) Class() float.Class {
+	x, _ := f.Big()
+	return order.Class(x, emin)
+}
+
+// IsNaN reports whether f is Not-a-Number; always false, as MBF has no NaN.
+func (f Single) IsNaN() bool {
+	return false
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as MBF has no
+// infinities.
+func (f Single) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as MBF has
+// no denormalized numbers.
+func (f Single) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is neither infinite nor NaN; always true.
+func (f Single) IsFinite() bool {
+	return true
+}
+
+// IsZero reports whether f is zero; i.e. has an exponent of zero.
+func (f Single) IsZero() bool {
+	return f.Exp() == 0
+}
+
+// ### [ Double ] ##############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g.
+func (f Double) Cmp(g Double) int {
+	x, _ := f.Big()
+	y, _ := g.Big()
+	return x.Cmp(y)
+}
+
+// Less reports whether f < g.
+func (f Double) Less(g Double) bool {
+	return f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. All zeros are equal.
+func (f Double) Equal(g Double) bool {
+	return f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with all zeros ordered as equal.
+func (f Double) TotalOrder(g Double) bool {
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), f.Cmp(g))
+}
+
+// Class returns the class of f.
+func (f Double) Class() float.Class {
+	x, _ := f.Big()
+	return order.Class(x, emin)
+}
+
+// IsNaN reports whether f is Not-a-Number; always false, as MBF has no NaN.
+func (f Double) IsNaN() bool {
+	return false
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as MBF has no
+// infinities.
+func (f Double) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as MBF has
+// no denormalized numbers.
+func (f Double) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is neither infinite nor NaN; always true.
+func (f Double) IsFinite() bool {
+	return true
+}
+
+// IsZero reports whether f is zero; i.e. has an exponent of zero.
+func (f Double) IsZero() bool {
+	return f.Exp() == 0
+}
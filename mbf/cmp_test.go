@@ -0,0 +1,99 @@
+package mbf
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClassSingle(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint32
+		class float.Class
+	}{
+		{bits: 0xFFFFFFFF, class: float.NegativeNormal},
+		{bits: 0x81800000, class: float.NegativeNormal},
+		{bits: 0x01800000, class: float.NegativeNormal},
+		{bits: 0x00000000, class: float.PositiveZero},
+		{bits: 0x01000000, class: float.PositiveNormal},
+		{bits: 0x81000000, class: float.PositiveNormal},
+		{bits: 0xFF7FFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewSingleFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%08X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%08X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestClassDouble(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint64
+		class float.Class
+	}{
+		{bits: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{bits: 0x8180000000000000, class: float.NegativeNormal},
+		{bits: 0x0180000000000000, class: float.NegativeNormal},
+		{bits: 0x0000000000000000, class: float.PositiveZero},
+		{bits: 0x0100000000000000, class: float.PositiveNormal},
+		{bits: 0x8100000000000000, class: float.PositiveNormal},
+		{bits: 0xFF7FFFFFFFFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewDoubleFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestCmpSingle(t *testing.T) {
+	// Comparison of numbers with the sign bit between the exponent and the
+	// fraction.
+	golden := []struct {
+		x, y  uint32
+		cmp   int
+		total bool
+	}{
+		// -1 and 1.
+		{x: 0x81800000, y: 0x81000000, cmp: -1, total: true},
+		// -2 and -1.
+		{x: 0x82800000, y: 0x81800000, cmp: -1, total: true},
+		// 1 and 2.
+		{x: 0x81000000, y: 0x82000000, cmp: -1, total: true},
+		// Smallest negative number and zero.
+		{x: 0x01800000, y: 0x00000000, cmp: -1, total: true},
+		{x: 0x00000000, y: 0x01800000, cmp: +1, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewSingleFromBits(g.x), NewSingleFromBits(g.y)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%08X.Cmp(0x%08X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%08X.TotalOrder(0x%08X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
+
+func TestCmpZero(t *testing.T) {
+	// Zero with any sign and fraction.
+	for _, bits := range []uint32{0x00800000, 0x00000001, 0x00FFFFFF} {
+		f := NewSingleFromBits(bits)
+		if got := f.Class(); got != float.PositiveZero {
+			t.Errorf("0x%08X: class mismatch; expected %v, got %v", bits, float.PositiveZero, got)
+		}
+		if !f.Equal(SingleZero) || !f.TotalOrder(SingleZero) || !SingleZero.TotalOrder(f) {
+			t.Errorf("0x%08X: expected equal to zero", bits)
+		}
+	}
+}
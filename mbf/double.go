@@ -0,0 +1,128 @@
+package mbf
+
+import (
+	"math/big"
+)
+
+// Positive and negative largest number and zero of the double precision
+// format.
+var (
+	// +0x1.FFFFFFFFFFFFFEp126 (about 1.701412e+38)
+	DoubleMax = Double{bits: 0xFF7FFFFFFFFFFFFF}
+	// -0x1.FFFFFFFFFFFFFEp126
+	DoubleNegMax = Double{bits: 0xFFFFFFFFFFFFFFFF}
+	// zero
+	DoubleZero = Double{bits: 0x0000000000000000}
+)
+
+// Double is a floating-point number in MBF double precision format.
+type Double struct {
+	// Exponent, sign and fraction.
+	//
+	//    8 bits:  exponent
+	//    1 bit:   sign
+	//    55 bits: fraction
+	bits uint64
+}
+
+// NewDoubleFromBits returns the floating-point number corresponding to the MBF
+// double precision binary representation.
+func NewDoubleFromBits(bits uint64) Double {
+	return Double{bits: bits}
+}
+
+// NewDoubleFromFloat32 returns the nearest MBF double precision floating-point
+// number for x and the accuracy of the conversion, as for NewDoubleFromBig. NaN
+// is converted to zero.
+func NewDoubleFromFloat32(x float32) (Double, big.Accuracy) {
+	return new(Context).NewDoubleFromFloat32(x)
+}
+
+// NewDoubleFromFloat64 returns the nearest MBF double precision floating-point
+// number for x and the accuracy of the conversion, as for NewDoubleFromBig. NaN
+// is converted to zero.
+func NewDoubleFromFloat64(x float64) (Double, big.Accuracy) {
+	return new(Context).NewDoubleFromFloat64(x)
+}
+
+// NewDoubleFromBig returns the nearest MBF double precision floating-point
+// number for x and the accuracy of the conversion, using round half to even.
+// Values too large in magnitude, including +-Inf, are converted to the largest
+// number of the same sign, and values too small in magnitude to zero.
+func NewDoubleFromBig(x *big.Float) (Double, big.Accuracy) {
+	return new(Context).NewDoubleFromBig(x)
+}
+
+// NewDoubleFromFloat32 returns the nearest MBF double precision floating-point
+// number for x and the accuracy of the conversion, as for NewDoubleFromFloat32.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) NewDoubleFromFloat32(x float32) (Double, big.Accuracy) {
+	return ctx.NewDoubleFromFloat64(float64(x))
+}
+
+// NewDoubleFromFloat64 returns the nearest MBF double precision floating-point
+// number for x and the accuracy of the conversion, as for NewDoubleFromFloat64.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) NewDoubleFromFloat64(x float64) (Double, big.Accuracy) {
+	if ctx.nan(x) {
+		return DoubleZero, big.Exact
+	}
+	return ctx.NewDoubleFromBig(big.NewFloat(x))
+}
+
+// NewDoubleFromBig returns the nearest MBF double precision floating-point
+// number for x and the accuracy of the conversion, as for NewDoubleFromBig. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) NewDoubleFromBig(x *big.Float) (Double, big.Accuracy) {
+	bits, acc, _ := ctx.encode(double, x)
+	return Double{bits: bits}, acc
+}
+
+// Bits returns the MBF double precision binary representation of f.
+func (f Double) Bits() uint64 {
+	return f.bits
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion.
+func (f Double) Float32() (float32, big.Accuracy) {
+	return new(Context).DoubleFloat32(f)
+}
+
+// DoubleFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in ctx.
+func (ctx *Context) DoubleFloat32(f Double) (float32, big.Accuracy) {
+	x, _ := f.Big()
+	return ctx.float32(x)
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion.
+func (f Double) Float64() (float64, big.Accuracy) {
+	x, _ := f.Big()
+	return x.Float64()
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number; always false, as MBF has no
+// NaN.
+func (f Double) Big() (x *big.Float, nan bool) {
+	return double.decode(f.bits), false
+}
+
+// Signbit reports whether f is negative. Zero is never negative.
+func (f Double) Signbit() bool {
+	return f.Exp() != 0 && f.bits&0x0080000000000000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f Double) Exp() int {
+	// 8 bit exponent
+	return int(f.bits >> 56)
+}
+
+// Frac returns the fraction of f.
+func (f Double) Frac() uint64 {
+	// 55 bit fraction
+	return f.bits & 0x007FFFFFFFFFFFFF
+}
@@ -0,0 +1,61 @@
+package mbf
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewDoubleFromBig(t *testing.T) {
+	pi, _, _ := big.ParseFloat("3.14159265358979323846264338327950288", 10, 200, big.ToNearestEven)
+	golden := []struct {
+		in   *big.Float
+		want uint64
+		acc  big.Accuracy
+	}{
+		{in: big.NewFloat(0), want: 0x0000000000000000, acc: big.Exact},
+		{in: big.NewFloat(1), want: 0x8100000000000000, acc: big.Exact},
+		{in: big.NewFloat(-0.5), want: 0x8080000000000000, acc: big.Exact},
+		{in: big.NewFloat(math.Pi), want: 0x82490FDAA22168C0, acc: big.Exact},
+		{in: pi, want: 0x82490FDAA22168C2, acc: big.Below},
+		{in: new(big.Float).SetInf(true), want: 0xFFFFFFFFFFFFFFFF, acc: big.Above},
+		{in: big.NewFloat(0x1p-128), want: 0x0100000000000000, acc: big.Exact},
+		{in: big.NewFloat(0x1p-129), want: 0x0000000000000000, acc: big.Below},
+	}
+	for _, g := range golden {
+		f, acc := NewDoubleFromBig(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+	if x, acc := NewDoubleFromBits(0x82490FDAA22168C2).Float64(); x != math.Pi || acc != big.Below {
+		t.Errorf("float64 mismatch; expected %v, got %v (%v)", math.Pi, x, acc)
+	}
+}
+
+func TestDoubleRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		f := NewDoubleFromBits(r.Uint64())
+		if f.Exp() == 0 {
+			continue
+		}
+		x, _ := f.Big()
+		if x.Signbit() != f.Signbit() {
+			t.Errorf("0x%016X: sign mismatch", f.Bits())
+		}
+		if got, acc := NewDoubleFromBig(x); got != f || acc != big.Exact {
+			t.Errorf("0x%016X: round-trip mismatch; got 0x%016X (%v)", f.Bits(), got.Bits(), acc)
+		}
+		// Every float64 within range is converted exactly.
+		y, _ := f.Float64()
+		g, acc := NewDoubleFromFloat64(y)
+		if z, _ := g.Float64(); z != y || acc != big.Exact {
+			t.Errorf("%v: float64 round-trip mismatch; got %v (%v)", y, z, acc)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package mbf
+
+import (
+	"fmt"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary for ParseSingle to return f exactly.
+func (f Single) Text(fmt byte, prec int) string {
+	x, _ := f.Big()
+	return single.text(x, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which ParseSingle
+// converts back to f, as for f.Text('g', -1).
+func (f Single) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Single) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary for ParseDouble to return f exactly.
+func (f Double) Text(fmt byte, prec int) string {
+	x, _ := f.Big()
+	return double.text(x, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which ParseDouble
+// converts back to f, as for f.Text('g', -1).
+func (f Double) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Double) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
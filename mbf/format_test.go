@@ -0,0 +1,123 @@
+package mbf
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSingleText(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		fmt  byte
+		prec int
+		want string
+	}{
+		{bits: 0x00000000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x00800001, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x81000000, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0x81000000, fmt: 'b', prec: -1, want: "8388608p-23"},
+		{bits: 0x7D4CCCCD, fmt: 'g', prec: -1, want: "0.1"},
+		{bits: 0x7D4CCCCD, fmt: 'x', prec: -1, want: "0x1.99999ap-04"},
+		{bits: 0x82C90FDB, fmt: 'g', prec: -1, want: "-3.1415927"},
+		{bits: 0x82C90FDB, fmt: 'e', prec: 3, want: "-3.142e+00"},
+		// max
+		{bits: 0xFF7FFFFF, fmt: 'g', prec: -1, want: "1.7014117e+38"},
+		// min
+		{bits: 0x01000000, fmt: 'g', prec: -1, want: "2.938736e-39"},
+	}
+	for _, g := range golden {
+		f := NewSingleFromBits(g.bits)
+		if got := f.Text(g.fmt, g.prec); got != g.want {
+			t.Errorf("0x%08X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestDoubleText(t *testing.T) {
+	golden := []struct {
+		bits uint64
+		fmt  byte
+		prec int
+		want string
+	}{
+		{bits: 0x0000000000000000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x8100000000000000, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0x7D4CCCCCCCCCCCCD, fmt: 'g', prec: -1, want: "0.1"},
+		{bits: 0x82490FDAA22168C2, fmt: 'g', prec: -1, want: "3.1415926535897932"},
+		{bits: 0x82490FDAA22168C2, fmt: 'f', prec: 20, want: "3.14159265358979322702"},
+	}
+	for _, g := range golden {
+		f := NewDoubleFromBits(g.bits)
+		if got := f.Text(g.fmt, g.prec); got != g.want {
+			t.Errorf("0x%016X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		bits := r.Uint32()
+		if i%2 == 1 {
+			// Smallest exponents.
+			bits &= 0x01FFFFFF
+		}
+		f := NewSingleFromBits(bits)
+		if f.Exp() == 0 {
+			continue
+		}
+		s := f.String()
+		got, _, err := ParseSingle(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if f != got {
+			t.Errorf("%q: round-trip mismatch; expected 0x%08X, got 0x%08X", s, f.Bits(), got.Bits())
+			continue
+		}
+		// The nearest decimal with one digit less does not round-trip.
+		e := f.Text('e', -1)
+		nd := strings.IndexByte(e, 'e') - 1
+		if f.Signbit() {
+			nd--
+		}
+		if nd < 2 {
+			continue
+		}
+		if got, _, _ := ParseSingle(f.Text('e', nd-2)); got == f {
+			t.Errorf("%q: not shortest; %q also round-trips", e, f.Text('e', nd-2))
+		}
+	}
+	for i := 0; i < 2000; i++ {
+		f := NewDoubleFromBits(r.Uint64())
+		if f.Exp() == 0 {
+			continue
+		}
+		s := f.String()
+		if got, _, err := ParseDouble(s); err != nil || f != got {
+			t.Errorf("%q: round-trip mismatch; expected 0x%016X, got 0x%016X (%v)", s, f.Bits(), got.Bits(), err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		in     fmt.Formatter
+		want   string
+	}{
+		{format: "%v", in: NewSingleFromBits(0x81000000), want: "1"},
+		{format: "%+.3e", in: NewSingleFromBits(0x81000000), want: "+1.000e+00"},
+		{format: "%8.2f|", in: NewSingleFromBits(0x81800000), want: "   -1.00|"},
+		{format: "%.20g", in: NewDoubleFromBits(0x7D4CCCCCCCCCCCCD), want: "0.10000000000000000035"},
+		{format: "%s", in: NewDoubleFromBits(0x8100000000000000), want: "%!s(mbf.Double=1)"},
+	}
+	for _, g := range golden {
+		if got := fmt.Sprintf(g.format, g.in); got != g.want {
+			t.Errorf("%q: output mismatch; expected %q, got %q", g.format, g.want, got)
+		}
+	}
+}
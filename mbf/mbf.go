@@ -0,0 +1,174 @@
+// Package mbf implements encoding and decoding of Microsoft Binary Format (MBF)
+// single (32-bit) and double (64-bit) precision floating-point numbers, as used
+// by Microsoft BASIC interpreters and compilers prior to QuickBASIC 4.0.
+//
+// An MBF number is (-1)^sign * 1.frac * 2^(exp-129), with an 8-bit exponent in
+// the most significant bits, followed by the sign bit and a fraction of 23 or
+// 55 bits. An exponent of zero encodes zero, regardless of the sign and
+// fraction. There are neither denormalized numbers, infinities nor NaN.
+//
+// The binary representation is stored in memory in little-endian byte order;
+// e.g. as read by binary.LittleEndian.Uint32 from a data file.
+//
+// https://en.wikipedia.org/wiki/Microsoft_Binary_Format
+package mbf
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+const (
+	// exponent bias.
+	bias = 129
+	// emin specifies the exponent of the smallest number, 1.0 * 2^emin.
+	emin = 1 - bias
+	// emax specifies the exponent of the largest number, 1.11...1 * 2^emax.
+	emax = 0xFF - bias
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+//
+// MBF has neither denormalized numbers, infinities nor NaN. Values too large in
+// magnitude raise the overflow exception, and values too small in magnitude
+// flushed to zero the underflow exception. Infinities converted to the largest
+// number and NaN converted to zero raise the invalid operation exception.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// nan records in ctx the invalid operation exception if x is NaN, and reports
+// whether x is NaN.
+func (ctx *Context) nan(x float64) bool {
+	if math.IsNaN(x) {
+		ctx.Flags |= fenv.Invalid
+		return true
+	}
+	return false
+}
+
+// float32 returns the float32 value nearest to the MBF number x and the
+// accuracy of the conversion. The exception flags raised are recorded in ctx.
+func (ctx *Context) float32(x *big.Float) (float32, big.Accuracy) {
+	y, acc := x.Float32()
+	if x.Sign() != 0 {
+		// Numbers of magnitude below 2^-126 are denormalized float32 values.
+		ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+	}
+	return y, acc
+}
+
+// format is an MBF format.
+type format struct {
+	// Number of fraction bits, excluding the hidden bit.
+	fracBits uint
+}
+
+// MBF formats.
+var (
+	// single precision format.
+	single = format{fracBits: 23}
+	// double precision format.
+	double = format{fracBits: 55}
+)
+
+// precision returns the number of bits of the significand, including the
+// hidden bit.
+func (f format) precision() uint {
+	return f.fracBits + 1
+}
+
+// signBit returns the sign bit of the binary representation.
+func (f format) signBit() uint64 {
+	return 1 << f.fracBits
+}
+
+// max returns the binary representation of the largest number.
+func (f format) max() uint64 {
+	return 0xFF<<(f.fracBits+1) | (f.signBit() - 1)
+}
+
+// decode returns the value of the binary representation bits.
+func (f format) decode(bits uint64) *big.Float {
+	x := new(big.Float).SetPrec(f.precision()).SetMode(big.ToNearestEven)
+	exp := int(bits >> (f.fracBits + 1))
+	if exp == 0 {
+		// Zero, with any sign and fraction.
+		return x
+	}
+	// (-1)^sign * 2^(exp-129) * 1.frac_2
+	mant := bits&(f.signBit()-1) | f.signBit()
+	x.SetUint64(mant)
+	x.SetMantExp(x, exp-bias-int(f.fracBits))
+	if bits&f.signBit() != 0 {
+		x.Neg(x)
+	}
+	return x
+}
+
+// encode returns the binary representation of the number nearest to x (round
+// half to even), the accuracy of the conversion, and a boolean indicating
+// whether x is too large in magnitude, including +-Inf. The exception flags
+// raised are recorded in ctx.
+//
+// Values too large in magnitude are converted to the largest number of the same
+// sign, and values too small in magnitude after rounding to zero.
+func (ctx *Context) encode(f format, x *big.Float) (bits uint64, acc big.Accuracy, overflow bool) {
+	neg := x.Signbit()
+	if x.Sign() == 0 {
+		return 0, big.Exact, false
+	}
+	if neg {
+		bits = f.signBit()
+	}
+	if x.IsInf() {
+		ctx.Flags |= fenv.Invalid
+		return bits | f.max(), signAcc(neg, big.Below), true
+	}
+	y := new(big.Float).SetPrec(f.precision()).SetMode(big.ToNearestEven).Set(x)
+	acc = y.Acc()
+	// |y| = 1.frac_2 * 2^exp
+	exp := y.MantExp(nil) - 1
+	switch {
+	case exp > emax:
+		// Overflow.
+		ctx.Flags |= fenv.Overflow | fenv.Inexact
+		return bits | f.max(), signAcc(neg, big.Below), true
+	case exp < emin:
+		// Underflow.
+		ctx.Flags |= fenv.Underflow | fenv.Inexact
+		return 0, signAcc(neg, big.Below), false
+	}
+	ctx.Flags |= rounding.Flags(x, acc, f.precision(), emin, emax, big.ToNearestEven, ctx.Tininess)
+	mant, _ := y.SetMantExp(y.Abs(y), int(f.fracBits)-exp).Int(nil)
+	bits |= uint64(exp+bias)<<(f.fracBits+1) | mant.Uint64()&(f.signBit()-1)
+	return bits, acc, false
+}
+
+// text converts the MBF number x to a string, as for strconv.FormatFloat. The
+// special precision -1 uses the smallest number of digits necessary to convert
+// back to x.
+func (f format) text(x *big.Float, fmt byte, prec int) string {
+	// There are no denormalized numbers; the values rounding to the smallest
+	// number lie within a quarter of its unit in the last place below it, as for
+	// powers of two of a larger exponent.
+	return strconv.FormatBig(x, fmt, prec, f.fracBits, emin-1)
+}
+
+// signAcc returns the accuracy acc of the magnitude of a number, adjusted for
+// the sign of the number.
+func signAcc(neg bool, acc big.Accuracy) big.Accuracy {
+	if neg {
+		return -acc
+	}
+	return acc
+}
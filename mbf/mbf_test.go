@@ -0,0 +1,53 @@
+package mbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestFlags(t *testing.T) {
+	golden := []struct {
+		name          string
+		op            func(ctx *Context)
+		after, before fenv.Flags
+	}{
+		{name: "NewSingleFromFloat64(1)", op: func(ctx *Context) { ctx.NewSingleFromFloat64(1) }},
+		{name: "NewSingleFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewSingleFromFloat64(0.1) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "NewDoubleFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewDoubleFromFloat64(0.1) }},
+		// Values too large in magnitude are saturated.
+		{name: "NewSingleFromFloat64(0x1p127)", op: func(ctx *Context) { ctx.NewSingleFromFloat64(0x1p127) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "NewDoubleFromFloat64(-0x1.FFFFFFFFFFFFFp126)", op: func(ctx *Context) { ctx.NewDoubleFromFloat64(-0x1.FFFFFFFFFFFFFp126) }},
+		{name: "NewSingleFromFloat64(-Inf)", op: func(ctx *Context) { ctx.NewSingleFromFloat64(math.Inf(-1)) }, after: fenv.Invalid, before: fenv.Invalid},
+		// Values too small in magnitude are flushed to zero.
+		{name: "NewSingleFromFloat64(0x1p-129)", op: func(ctx *Context) { ctx.NewSingleFromFloat64(0x1p-129) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^-128 - 2^-154 rounds to 2^-128, and is tiny only before rounding.
+		{name: "NewSingleFromFloat64(0x1.FFFFFFCp-129)", op: func(ctx *Context) { ctx.NewSingleFromFloat64(0x1.FFFFFFCp-129) }, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// NaN is converted to zero.
+		{name: "NewSingleFromFloat64(NaN)", op: func(ctx *Context) { ctx.NewSingleFromFloat64(math.NaN()) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "NewDoubleFromFloat32(NaN)", op: func(ctx *Context) { ctx.NewDoubleFromFloat32(float32(math.NaN())) }, after: fenv.Invalid, before: fenv.Invalid},
+		// Conversions to float32.
+		{name: "SingleFloat32(0x1p-126)", op: func(ctx *Context) { ctx.SingleFloat32(NewSingleFromBits(0x03000000)) }},
+		{name: "SingleFloat32(0x1.000002p-128)", op: func(ctx *Context) { ctx.SingleFloat32(NewSingleFromBits(0x01000001)) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "DoubleFloat32(0.1)", op: func(ctx *Context) { ctx.DoubleFloat32(NewDoubleFromBits(0x7D4CCCCCCCCCCCCD)) }, after: fenv.Inexact, before: fenv.Inexact},
+		// Parsing.
+		{name: `ParseSingle("0.5")`, op: func(ctx *Context) { ctx.ParseSingle("0.5") }},
+		{name: `ParseDouble("1e39")`, op: func(ctx *Context) { ctx.ParseDouble("1e39") }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: `ParseSingle("nan")`, op: func(ctx *Context) { ctx.ParseSingle("nan") }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: `ParseSingle("x")`, op: func(ctx *Context) { ctx.ParseSingle("x") }},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			g.op(ctx)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package mbf
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// ParseSingle returns the nearest MBF single precision floating-point number to
+// the value of s (round half to even) and the accuracy of the conversion. s may
+// be a decimal or hexadecimal floating-point literal, or "inf" or "infinity"
+// (case-insensitive), with an optional sign.
+//
+// If s is syntactically invalid or NaN, the error wraps strconv.ErrSyntax. If s
+// is too large in magnitude, the result is the largest number of the same sign
+// and the error wraps strconv.ErrRange.
+func ParseSingle(s string) (Single, big.Accuracy, error) {
+	return new(Context).ParseSingle(s)
+}
+
+// ParseSingle returns the nearest MBF single precision floating-point number to
+// the value of s and the accuracy of the conversion, as for ParseSingle. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) ParseSingle(s string) (Single, big.Accuracy, error) {
+	bits, acc, err := ctx.parse(single, s)
+	if err != nil {
+		err = fmt.Errorf("mbf.ParseSingle: parsing %q: %w", s, err)
+	}
+	return Single{bits: uint32(bits)}, acc, err
+}
+
+// ParseDouble returns the nearest MBF double precision floating-point number to
+// the value of s (round half to even) and the accuracy of the conversion. s may
+// be a decimal or hexadecimal floating-point literal, or "inf" or "infinity"
+// (case-insensitive), with an optional sign.
+//
+// If s is syntactically invalid or NaN, the error wraps strconv.ErrSyntax. If s
+// is too large in magnitude, the result is the largest number of the same sign
+// and the error wraps strconv.ErrRange.
+func ParseDouble(s string) (Double, big.Accuracy, error) {
+	return new(Context).ParseDouble(s)
+}
+
+// ParseDouble returns the nearest MBF double precision floating-point number to
+// the value of s and the accuracy of the conversion, as for ParseDouble. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) ParseDouble(s string) (Double, big.Accuracy, error) {
+	bits, acc, err := ctx.parse(double, s)
+	if err != nil {
+		err = fmt.Errorf("mbf.ParseDouble: parsing %q: %w", s, err)
+	}
+	return Double{bits: bits}, acc, err
+}
+
+// parse returns the binary representation of the number nearest to the value
+// of s and the accuracy of the conversion. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) parse(f format, s string) (uint64, big.Accuracy, error) {
+	x, nan, err := strconv.ParseBig(s, f.precision()+2)
+	if err != nil {
+		return 0, big.Exact, err
+	}
+	if nan {
+		// MBF has no representation of NaN.
+		ctx.Flags |= fenv.Invalid
+		return 0, big.Exact, strconv.ErrSyntax
+	}
+	bits, acc, overflow := ctx.encode(f, x)
+	if overflow {
+		return bits, acc, strconv.ErrRange
+	}
+	return bits, acc, nil
+}
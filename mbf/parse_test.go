@@ -0,0 +1,86 @@
+package mbf
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestParseSingle(t *testing.T) {
+	golden := []struct {
+		in   string
+		want uint32
+		acc  big.Accuracy
+		err  error
+	}{
+		{in: "0", want: 0x00000000, acc: big.Exact},
+		{in: "-0.0e10", want: 0x00000000, acc: big.Exact},
+		{in: "1", want: 0x81000000, acc: big.Exact},
+		{in: "0.1", want: 0x7D4CCCCD, acc: big.Above},
+		{in: "-3.14159265358979323846", want: 0x82C90FDB, acc: big.Below},
+		{in: "0x1.8p-3", want: 0x7E400000, acc: big.Exact},
+		// max
+		{in: "1.7014117e38", want: 0xFF7FFFFF, acc: big.Above},
+		// max + half ulp = max (overflow)
+		{in: "0x1.ffffffp126", want: 0xFF7FFFFF, acc: big.Below, err: strconv.ErrRange},
+		{in: "-1e39", want: 0xFFFFFFFF, acc: big.Above, err: strconv.ErrRange},
+		{in: "inf", want: 0xFF7FFFFF, acc: big.Below, err: strconv.ErrRange},
+		// min
+		{in: "2.938736e-39", want: 0x01000000, acc: big.Below},
+		{in: "1e-39", want: 0x00000000, acc: big.Below},
+
+		// Invalid syntax.
+		{in: "", err: strconv.ErrSyntax},
+		{in: "1e", err: strconv.ErrSyntax},
+		{in: "nan", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := ParseSingle(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if got.Bits() != g.want {
+			t.Errorf("%q: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, got.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestParseDouble(t *testing.T) {
+	golden := []struct {
+		in   string
+		want uint64
+		acc  big.Accuracy
+		err  error
+	}{
+		{in: "1", want: 0x8100000000000000, acc: big.Exact},
+		{in: "0.1", want: 0x7D4CCCCCCCCCCCCD, acc: big.Above},
+		{in: "3.14159265358979323846", want: 0x82490FDAA22168C2, acc: big.Below},
+		{in: "-Infinity", want: 0xFFFFFFFFFFFFFFFF, acc: big.Above, err: strconv.ErrRange},
+		{in: "0x1p-129", want: 0x0000000000000000, acc: big.Below},
+		{in: "1.2.3", err: strconv.ErrSyntax},
+	}
+	for _, g := range golden {
+		got, acc, err := ParseDouble(g.in)
+		if !errors.Is(err, g.err) {
+			t.Errorf("%q: error mismatch; expected %v, got %v", g.in, g.err, err)
+			continue
+		}
+		if g.err == strconv.ErrSyntax {
+			continue
+		}
+		if got.Bits() != g.want {
+			t.Errorf("%q: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, got.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%q: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
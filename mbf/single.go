@@ -0,0 +1,127 @@
+package mbf
+
+import (
+	"math/big"
+)
+
+// Positive and negative largest number and zero of the single precision
+// format.
+var (
+	// +0x1.FFFFFEp126 (about 1.701412e+38)
+	SingleMax = Single{bits: 0xFF7FFFFF}
+	// -0x1.FFFFFEp126
+	SingleNegMax = Single{bits: 0xFFFFFFFF}
+	// zero
+	SingleZero = Single{bits: 0x00000000}
+)
+
+// Single is a floating-point number in MBF single precision format.
+type Single struct {
+	// Exponent, sign and fraction.
+	//
+	//    8 bits:  exponent
+	//    1 bit:   sign
+	//    23 bits: fraction
+	bits uint32
+}
+
+// NewSingleFromBits returns the floating-point number corresponding to the MBF
+// single precision binary representation.
+func NewSingleFromBits(bits uint32) Single {
+	return Single{bits: bits}
+}
+
+// NewSingleFromFloat32 returns the nearest MBF single precision floating-point
+// number for x and the accuracy of the conversion, as for NewSingleFromBig. NaN
+// is converted to zero.
+func NewSingleFromFloat32(x float32) (Single, big.Accuracy) {
+	return new(Context).NewSingleFromFloat32(x)
+}
+
+// NewSingleFromFloat64 returns the nearest MBF single precision floating-point
+// number for x and the accuracy of the conversion, as for NewSingleFromBig. NaN
+// is converted to zero.
+func NewSingleFromFloat64(x float64) (Single, big.Accuracy) {
+	return new(Context).NewSingleFromFloat64(x)
+}
+
+// NewSingleFromBig returns the nearest MBF single precision floating-point
+// number for x and the accuracy of the conversion, using round half to even.
+// Values too large in magnitude, including +-Inf, are converted to the largest
+// number of the same sign, and values too small in magnitude to zero.
+func NewSingleFromBig(x *big.Float) (Single, big.Accuracy) {
+	return new(Context).NewSingleFromBig(x)
+}
+
+// NewSingleFromFloat32 returns the nearest MBF single precision floating-point
+// number for x and the accuracy of the conversion, as for NewSingleFromFloat32.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) NewSingleFromFloat32(x float32) (Single, big.Accuracy) {
+	return ctx.NewSingleFromFloat64(float64(x))
+}
+
+// NewSingleFromFloat64 returns the nearest MBF single precision floating-point
+// number for x and the accuracy of the conversion, as for NewSingleFromFloat64.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) NewSingleFromFloat64(x float64) (Single, big.Accuracy) {
+	if ctx.nan(x) {
+		return SingleZero, big.Exact
+	}
+	return ctx.NewSingleFromBig(big.NewFloat(x))
+}
+
+// NewSingleFromBig returns the nearest MBF single precision floating-point
+// number for x and the accuracy of the conversion, as for NewSingleFromBig. The
+// exception flags raised are recorded in ctx.
+func (ctx *Context) NewSingleFromBig(x *big.Float) (Single, big.Accuracy) {
+	bits, acc, _ := ctx.encode(single, x)
+	return Single{bits: uint32(bits)}, acc
+}
+
+// Bits returns the MBF single precision binary representation of f.
+func (f Single) Bits() uint32 {
+	return f.bits
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion; numbers of magnitude below 2^-126 are denormalized float32 values.
+func (f Single) Float32() (float32, big.Accuracy) {
+	return new(Context).SingleFloat32(f)
+}
+
+// SingleFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in ctx.
+func (ctx *Context) SingleFloat32(f Single) (float32, big.Accuracy) {
+	x, _ := f.Big()
+	return ctx.float32(x)
+}
+
+// Float64 returns the float64 value of f. The conversion is always exact.
+func (f Single) Float64() (float64, big.Accuracy) {
+	x, _ := f.Big()
+	return x.Float64()
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number; always false, as MBF has no
+// NaN.
+func (f Single) Big() (x *big.Float, nan bool) {
+	return single.decode(uint64(f.bits)), false
+}
+
+// Signbit reports whether f is negative. Zero is never negative.
+func (f Single) Signbit() bool {
+	return f.Exp() != 0 && f.bits&0x00800000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f Single) Exp() int {
+	// 8 bit exponent
+	return int(f.bits >> 24)
+}
+
+// Frac returns the fraction of f.
+func (f Single) Frac() uint32 {
+	// 23 bit fraction
+	return f.bits & 0x007FFFFF
+}
@@ -0,0 +1,117 @@
+package mbf
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewSingleFromBits(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		want float64
+	}{
+		// Special numbers.
+		// 00000000 0 00000000000000000000000 = 0
+		{bits: 0x00000000, want: 0},
+		// 00000000 1 00000000000000000000000 = 0
+		{bits: 0x00800000, want: 0},
+		// 00000000 0 00000000000000000000001 = 0
+		{bits: 0x00000001, want: 0},
+
+		// 10000001 0 00000000000000000000000 = 1
+		{bits: 0x81000000, want: 1},
+		// 10000001 1 00000000000000000000000 = -1
+		{bits: 0x81800000, want: -1},
+		// 10000000 0 00000000000000000000000 = 0.5
+		{bits: 0x80000000, want: 0.5},
+		// 10000100 0 01000000000000000000000 = 10
+		{bits: 0x84200000, want: 10},
+		// 01111101 0 10011001100110011001101 = 0.1 (rounded)
+		{bits: 0x7D4CCCCD, want: 0x1.99999Ap-4},
+		// 10000010 0 10010010000111111011011 = pi (rounded)
+		{bits: 0x82490FDB, want: 0x1.921FB6p1},
+		// 11111111 0 11111111111111111111111 = largest number
+		{bits: 0xFF7FFFFF, want: 0x1.FFFFFEp126},
+		// 00000001 0 00000000000000000000000 = smallest number
+		{bits: 0x01000000, want: 0x1p-128},
+	}
+	for _, g := range golden {
+		f := NewSingleFromBits(g.bits)
+		got, acc := f.Float64()
+		if got != g.want || math.Signbit(got) != math.Signbit(g.want) || acc != big.Exact {
+			t.Errorf("0x%08X: number mismatch; expected %v, got %v (%v)", g.bits, g.want, got, acc)
+		}
+	}
+}
+
+func TestNewSingleFromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint32
+		acc  big.Accuracy
+	}{
+		// Special numbers.
+		{in: 0, want: 0x00000000, acc: big.Exact},
+		{in: math.Copysign(0, -1), want: 0x00000000, acc: big.Exact},
+		{in: math.NaN(), want: 0x00000000, acc: big.Exact},
+		{in: math.Inf(1), want: 0xFF7FFFFF, acc: big.Below},
+		{in: math.Inf(-1), want: 0xFFFFFFFF, acc: big.Above},
+
+		{in: 1, want: 0x81000000, acc: big.Exact},
+		{in: -10, want: 0x84A00000, acc: big.Exact},
+		{in: 0.1, want: 0x7D4CCCCD, acc: big.Above},
+		{in: math.Pi, want: 0x82490FDB, acc: big.Above},
+		// 1 + 2^-24 = 1 (tie, round to even)
+		{in: 1 + 0x1p-24, want: 0x81000000, acc: big.Below},
+		// 1 + 3*2^-24 = 1 + 2^-22 (tie, round to even)
+		{in: 1 + 0x3p-24, want: 0x81000002, acc: big.Above},
+
+		// Overflow.
+		{in: 0x1p127, want: 0xFF7FFFFF, acc: big.Below},
+		{in: -0x1.FFFFFFp126, want: 0xFFFFFFFF, acc: big.Above},
+		{in: 0x1.FFFFFEFp126, want: 0xFF7FFFFF, acc: big.Below},
+
+		// Underflow.
+		{in: 0x1p-129, want: 0x00000000, acc: big.Below},
+		{in: -0x1p-200, want: 0x00000000, acc: big.Above},
+		{in: 0x1.FFFFFFp-129, want: 0x01000000, acc: big.Above},
+		{in: 0x1.FFFFFEp-129, want: 0x00000000, acc: big.Below},
+	}
+	for _, g := range golden {
+		f, acc := NewSingleFromFloat64(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestSingleFloat32(t *testing.T) {
+	// Normal float32 numbers within range are MBF single precision numbers with
+	// the sign moved and an exponent larger by 2.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		bits := r.Uint32()
+		exp := bits >> 23 & 0xFF
+		if exp == 0 || exp > 0xFD {
+			continue
+		}
+		x := math.Float32frombits(bits)
+		want := (exp+2)<<24 | bits>>31<<23 | bits&0x007FFFFF
+		f, acc := NewSingleFromFloat32(x)
+		if f.Bits() != want || acc != big.Exact {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X (%v)", x, want, f.Bits(), acc)
+		}
+		if y, acc := f.Float32(); y != x || acc != big.Exact {
+			t.Errorf("0x%08X: float32 mismatch; expected %v, got %v (%v)", want, x, y, acc)
+		}
+	}
+	// The smallest numbers are denormalized float32 numbers.
+	if x, acc := NewSingleFromBits(0x01000001).Float32(); x != 0x1p-128 || acc != big.Below {
+		t.Errorf("float32 mismatch; expected %v, got %v (%v)", 0x1p-128, x, acc)
+	}
+}
@@ -0,0 +1,87 @@
+package minifloat
+
+import (
+	"math/big"
+
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/ieee754"
+	"github.com/mewmew/float/internal/order"
+)
+
+// Cmp compares f and g by value and returns -1 if f < g, 0 if f == g and +1 if
+// f > g; -0 is equal to +0. A NaN is considered less than any non-NaN and equal
+// to a NaN, as by cmp.Compare. The formats of f and g may differ.
+func (f Float) Cmp(g Float) int {
+	x, xnan := f.Big()
+	y, ynan := g.Big()
+	return order.Cmp(x, xnan, y, ynan)
+}
+
+// Less reports whether f < g. It is false if f or g is NaN.
+func (f Float) Less(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is NaN, and true for -0
+// and +0.
+func (f Float) Equal(g Float) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder):
+//
+//	-qNaN < -sNaN < -Inf < -x < -0 < +0 < +x < +Inf < +sNaN < +qNaN
+//
+// NaNs of the same kind and sign are ordered by mantissa, in reverse for
+// negative NaNs. Zeros of the same sign but different mantissas, in formats
+// without denormalized numbers, are ordered as equal. The formats of f and g
+// may differ.
+func (f Float) TotalOrder(g Float) bool {
+	x, xnan := f.Big()
+	y, ynan := g.Big()
+	cmp := 0
+	switch {
+	case xnan && ynan:
+		cmp = order.Payload(uint64(f.Frac()), uint64(g.Frac()))
+	case !xnan && !ynan:
+		cmp = x.Cmp(y)
+	}
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), cmp)
+}
+
+// Class returns the class of f. In formats with infinities, NaNs with the most
+// significant bit of the mantissa set are quiet and other NaNs signaling, as in
+// IEEE 754; NaNs of other formats are quiet.
+func (f Float) Class() float.Class {
+	if f.format.NoSubnormals && f.Exp() == 0 {
+		return order.Signed(f.Signbit(), float.PositiveZero)
+	}
+	return f.format.ieee().Class(big.NewInt(int64(f.bits)))
+}
+
+// IsNaN reports whether f is Not-a-Number.
+func (f Float) IsNaN() bool {
+	_, nan := f.Big()
+	return nan
+}
+
+// IsInf reports whether f is +Inf or -Inf.
+func (f Float) IsInf() bool {
+	return f.format.Special == ieee754.InfNaN && f.Exp() == 1<<f.format.ExpBits-1 && f.Frac() == 0
+}
+
+// IsSubnormal reports whether f is a denormalized number.
+func (f Float) IsSubnormal() bool {
+	return !f.format.NoSubnormals && f.Exp() == 0 && f.Frac() != 0
+}
+
+// IsFinite reports whether f is neither infinite nor NaN.
+func (f Float) IsFinite() bool {
+	return !f.IsNaN() && !f.IsInf()
+}
+
+// IsZero reports whether f is +0 or -0.
+func (f Float) IsZero() bool {
+	return f.Exp() == 0 && (f.format.NoSubnormals || f.Frac() == 0)
+}
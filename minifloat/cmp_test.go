@@ -0,0 +1,92 @@
+package minifloat
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/bfloat"
+	"github.com/mewmew/float/fp8"
+)
+
+// classifier is a floating-point number with classification.
+type classifier interface {
+	Class() float.Class
+	IsNaN() bool
+	IsInf() bool
+	IsSubnormal() bool
+	IsFinite() bool
+	IsZero() bool
+}
+
+func TestCmpFP8(t *testing.T) {
+	for _, f := range E4M3.Values() {
+		want := fp8.NewE4M3FromBits(uint8(f.Bits()))
+		testSameClass(t, f, want)
+		for _, g := range E4M3.Values() {
+			h := fp8.NewE4M3FromBits(uint8(g.Bits()))
+			if f.Cmp(g) != want.Cmp(h) || f.Less(g) != want.Less(h) || f.Equal(g) != want.Equal(h) || f.TotalOrder(g) != want.TotalOrder(h) {
+				t.Errorf("0x%02X, 0x%02X: comparison mismatch", f.Bits(), g.Bits())
+			}
+		}
+	}
+	for _, f := range E5M2.Values() {
+		want := fp8.NewE5M2FromBits(uint8(f.Bits()))
+		testSameClass(t, f, want)
+		for _, g := range E5M2.Values() {
+			h := fp8.NewE5M2FromBits(uint8(g.Bits()))
+			if f.Cmp(g) != want.Cmp(h) || f.Less(g) != want.Less(h) || f.Equal(g) != want.Equal(h) || f.TotalOrder(g) != want.TotalOrder(h) {
+				t.Errorf("0x%02X, 0x%02X: comparison mismatch", f.Bits(), g.Bits())
+			}
+		}
+	}
+}
+
+func TestClassBFloat16(t *testing.T) {
+	for _, f := range BFloat16.Values() {
+		testSameClass(t, f, bfloat.NewFromBits(f.Bits()))
+	}
+}
+
+func TestCmpFormats(t *testing.T) {
+	// E2M1 without denormalized numbers.
+	e2m1 := E2M1
+	e2m1.NoSubnormals = true
+	for _, f := range e2m1.Values() {
+		if f.Exp() != 0 {
+			continue
+		}
+		want := float.PositiveZero
+		if f.Signbit() {
+			want = float.NegativeZero
+		}
+		if got := f.Class(); want != got || !f.IsZero() || f.IsSubnormal() {
+			t.Errorf("0x%X: class mismatch; expected %v, got %v", f.Bits(), want, got)
+		}
+		zero := e2m1.NewFromBits(0)
+		if !f.Equal(zero) || !f.TotalOrder(zero) || zero.TotalOrder(f) == f.Signbit() {
+			t.Errorf("0x%X: total order mismatch with zero", f.Bits())
+		}
+	}
+	// Numbers of different formats.
+	one, _ := E2M1.NewFromFloat64(1)
+	half, _ := Binary16.NewFromFloat64(0.5)
+	six, _ := E3M2.NewFromFloat64(6)
+	nan := E4M3.NewFromBits(0xFF)
+	if one.Cmp(half) != 1 || !half.Less(one) || !one.Equal(E2M1.NewFromBits(0x2)) || !one.TotalOrder(six) || six.TotalOrder(one) {
+		t.Errorf("comparison mismatch between formats")
+	}
+	if nan.Cmp(one) != -1 || !nan.TotalOrder(one) || one.TotalOrder(nan) {
+		t.Errorf("comparison mismatch between formats for NaN")
+	}
+}
+
+// testSameClass checks that f has the same class as want.
+func testSameClass(t *testing.T, f Float, want classifier) {
+	t.Helper()
+	if got, want := f.Class(), want.Class(); want != got {
+		t.Errorf("0x%04X: class mismatch; expected %v, got %v", f.Bits(), want, got)
+	}
+	if f.IsNaN() != want.IsNaN() || f.IsInf() != want.IsInf() || f.IsSubnormal() != want.IsSubnormal() || f.IsFinite() != want.IsFinite() || f.IsZero() != want.IsZero() {
+		t.Errorf("0x%04X: predicate mismatch", f.Bits())
+	}
+}
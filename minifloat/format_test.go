@@ -0,0 +1,92 @@
+package minifloat
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/mewmew/float/ieee754"
+)
+
+func TestText(t *testing.T) {
+	noSub := Format{ExpBits: 3, MantBits: 2, Bias: 3, Special: ieee754.InfNaN, NoSubnormals: true}
+	golden := []struct {
+		format Format
+		in     uint16
+		fmt    byte
+		prec   int
+		want   string
+	}{
+		// Special numbers.
+		{format: E2M1, in: 0x8, fmt: 'g', prec: -1, want: "-0"},
+		{format: E5M2, in: 0x7C, fmt: 'g', prec: -1, want: "+Inf"},
+		{format: E5M2, in: 0xFC, fmt: 'f', prec: 2, want: "-Inf"},
+		{format: E4M3, in: 0x7F, fmt: 'g', prec: -1, want: "NaN"},
+
+		{format: E2M1, in: 0x1, fmt: 'g', prec: -1, want: "0.5"},
+		{format: E2M1, in: 0x7, fmt: 'e', prec: 3, want: "6.000e+00"},
+		{format: E2M1, in: 0x7, fmt: 'b', prec: -1, want: "3p+1"},
+		{format: E2M3, in: 0x1F, fmt: 'g', prec: -1, want: "7.5"},
+		{format: E3M2, in: 0x1F, fmt: 'x', prec: -1, want: "0x1.cp+04"},
+		{format: E4M3, in: 0x01, fmt: 'g', prec: -1, want: "0.002"},
+		{format: E4M3, in: 0x01, fmt: 'g', prec: 10, want: "0.001953125"},
+		{format: E4M3, in: 0x64, fmt: 'g', prec: -1, want: "50"},
+		// Without denormalized numbers; the smallest number is 0.25.
+		{format: noSub, in: 0x04, fmt: 'g', prec: -1, want: "0.25"},
+		{format: noSub, in: 0x01, fmt: 'g', prec: -1, want: "0"},
+		{format: noSub, in: 0x1B, fmt: 'g', prec: -1, want: "14"},
+		{format: noSub, in: 0x1C, fmt: 'g', prec: -1, want: "+Inf"},
+	}
+	for _, g := range golden {
+		f := g.format.NewFromBits(g.in)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("%v: 0x%X %c %d: text mismatch; expected %q, got %q", g.format, g.in, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	formats := []Format{
+		E2M1, E2M3, E3M2, E4M3, E5M2,
+		{ExpBits: 4, MantBits: 7, Bias: 4, Special: ieee754.InfNaN, NoSubnormals: true},
+		{ExpBits: 6, MantBits: 5, Bias: 40, Special: ieee754.NaNOnly},
+	}
+	for _, format := range formats {
+		for _, f := range format.Values() {
+			x, nan := f.Big()
+			if nan || x.Sign() == 0 {
+				continue
+			}
+			s := f.String()
+			y, _, err := new(big.Float).SetPrec(100).Parse(s, 10)
+			if err != nil {
+				t.Errorf("%v: %q: unexpected error; %v", format, s, err)
+				continue
+			}
+			if got, _ := format.NewFromBig(y); got != f {
+				t.Errorf("%v: %q: round-trip mismatch; expected 0x%X, got 0x%X", format, s, f.Bits(), got.Bits())
+			}
+		}
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	golden := []struct {
+		format string
+		in     uint16
+		want   string
+	}{
+		{format: "%v", in: 0x3, want: "1.5"},
+		{format: "%+.3e", in: 0x3, want: "+1.500e+00"},
+		{format: "%6.2f|", in: 0xD, want: " -3.00|"},
+		{format: "%-6v|", in: 0x7, want: "6     |"},
+		{format: "%s", in: 0x2, want: "%!s(minifloat.Float=1)"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, E2M1.NewFromBits(g.in))
+		if g.want != got {
+			t.Errorf("%q 0x%X: output mismatch; expected %q, got %q", g.format, g.in, g.want, got)
+		}
+	}
+}
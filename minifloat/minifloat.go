@@ -0,0 +1,327 @@
+// Package minifloat implements encoding and decoding of small binary
+// floating-point formats of up to 16 bits, parameterised by the number of
+// exponent and mantissa bits, the exponent bias, the encoding of infinities
+// and NaN, and the presence of denormalized numbers; e.g. the FP4 and FP6
+// formats of OCP Microscaling, or formats used for teaching and experiments.
+//
+// Each format has few enough numbers to enumerate them all, which allows small
+// formats to be checked exhaustively.
+//
+// https://en.wikipedia.org/wiki/Minifloat
+package minifloat
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/ieee754"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+// Format describes a minifloat format. A floating-point number consists of,
+// from the most significant bit:
+//
+//	1 bit:            sign
+//	ExpBits bits:     exponent
+//	MantBits bits:    mantissa (fraction)
+//
+// Normalized numbers have an exponent between 1 and the maximum exponent, and
+// represent the value (-1)^sign * 2^(exponent-Bias) * 1.mantissa_2.
+// Denormalized numbers have a zero exponent and represent the value
+// (-1)^sign * 2^(1-Bias) * 0.mantissa_2. If NoSubnormals is set, numbers with
+// a zero exponent are zero, regardless of the mantissa.
+//
+// The maximum exponent encodes infinities and NaN as specified by Special; see
+// ieee754.Special.
+type Format struct {
+	// Number of exponent bits.
+	ExpBits uint
+	// Number of mantissa bits (excluding the implicit lead bit).
+	MantBits uint
+	// Exponent bias.
+	Bias int
+	// Encoding of infinities and Not-a-Number values.
+	Special ieee754.Special
+	// Numbers with a zero exponent are zero rather than denormalized.
+	NoSubnormals bool
+}
+
+// Minifloat formats.
+var (
+	// OCP MX FP4 E2M1; largest value 6.
+	E2M1 = Format{ExpBits: 2, MantBits: 1, Bias: 1, Special: ieee754.NoSpecial}
+	// OCP MX FP6 E2M3; largest value 7.5.
+	E2M3 = Format{ExpBits: 2, MantBits: 3, Bias: 1, Special: ieee754.NoSpecial}
+	// OCP MX FP6 E3M2; largest value 28.
+	E3M2 = Format{ExpBits: 3, MantBits: 2, Bias: 3, Special: ieee754.NoSpecial}
+	// OCP FP8 E4M3; largest value 448.
+	E4M3 = Format{ExpBits: 4, MantBits: 3, Bias: 7, Special: ieee754.NaNOnly}
+	// OCP FP8 E5M2; largest value 57344.
+	E5M2 = Format{ExpBits: 5, MantBits: 2, Bias: 15, Special: ieee754.InfNaN}
+	// IEEE 754 half precision.
+	Binary16 = Format{ExpBits: 5, MantBits: 10, Bias: 15, Special: ieee754.InfNaN}
+	// bfloat16.
+	BFloat16 = Format{ExpBits: 8, MantBits: 7, Bias: 127, Special: ieee754.InfNaN}
+)
+
+// Width returns the number of bits of floating-point numbers in the format f.
+func (f Format) Width() uint {
+	return 1 + f.ExpBits + f.MantBits
+}
+
+// Precision returns the number of bits of the significand, including the lead
+// bit, of normalized numbers in the format f.
+func (f Format) Precision() uint {
+	return f.MantBits + 1
+}
+
+// Emin returns the exponent of the smallest normalized number in the format f.
+func (f Format) Emin() int {
+	return 1 - f.Bias
+}
+
+// Emax returns the exponent of the largest finite number in the format f.
+func (f Format) Emax() int {
+	return f.ieee().Emax()
+}
+
+// MaxValue returns the largest finite number in the format f.
+func (f Format) MaxValue() *big.Float {
+	return f.ieee().MaxValue()
+}
+
+// SmallestNonzero returns the smallest positive number in the format f; a
+// denormalized number, or the smallest normalized number if f has no
+// denormalized numbers.
+func (f Format) SmallestNonzero() *big.Float {
+	if f.NoSubnormals {
+		return f.minNormal()
+	}
+	return f.ieee().SmallestNonzero()
+}
+
+// String returns a short description of the format f; e.g. "E4M3 (bias 7,
+// NaN)".
+func (f Format) String() string {
+	var special string
+	switch f.Special {
+	case ieee754.InfNaN:
+		special = ", Inf, NaN"
+	case ieee754.NaNOnly:
+		special = ", NaN"
+	}
+	if f.NoSubnormals {
+		special += ", no subnormals"
+	}
+	return fmt.Sprintf("E%dM%d (bias %d%s)", f.ExpBits, f.MantBits, f.Bias, special)
+}
+
+// Values returns all floating-point numbers of the format f, ordered by their
+// binary representation.
+func (f Format) Values() []Float {
+	f.validate()
+	n := 1 << f.Width()
+	fs := make([]Float, n)
+	for i := range fs {
+		fs[i] = Float{format: f, bits: uint16(i)}
+	}
+	return fs
+}
+
+// NewFromBits returns the floating-point number of the format f corresponding
+// to the binary representation bits; bits above the width of f are ignored.
+func (f Format) NewFromBits(bits uint16) Float {
+	f.validate()
+	return Float{format: f, bits: bits & f.mask()}
+}
+
+// NewFromFloat64 returns the nearest floating-point number of the format f for
+// x and the accuracy of the conversion, as for NewFromBig. NaN is converted to
+// NaN, reported as exact. If f has no NaN, e.g. E2M1, E2M3 and E3M2, NaN is
+// converted to +0 instead, reported as Below to tell it apart from an exact
+// conversion of zero.
+func (f Format) NewFromFloat64(x float64) (Float, big.Accuracy) {
+	return new(Context).NewFromFloat64(f, x)
+}
+
+// NewFromBig returns the nearest floating-point number of the format f for x
+// and the accuracy of the conversion, using round half to even.
+//
+// Values too large in magnitude are converted to +-Inf; or to NaN if the format
+// has no infinities; or to the largest finite number of the appropriate sign if
+// the format has neither infinities nor NaN. If f has no denormalized numbers,
+// values smaller in magnitude than the smallest normalized number are converted
+// to zero or to the smallest normalized number of the same sign, whichever is
+// nearest; halfway values are converted to zero. NaN results are reported as
+// exact.
+func (f Format) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return new(Context).NewFromBig(f, x)
+}
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// NewFromFloat64 returns the nearest floating-point number of the format f for
+// x and the accuracy of the conversion, as for f.NewFromFloat64. The exception
+// flags raised are recorded in ctx; a signaling NaN, or NaN converted to a
+// format without NaN, raises the invalid operation exception.
+func (ctx *Context) NewFromFloat64(f Format, x float64) (Float, big.Accuracy) {
+	if math.IsNaN(x) {
+		bits, ok := f.ieee().NaN(false)
+		if !ok || math.Float64bits(x)&0x0008000000000000 == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		if !ok {
+			// No representation of NaN.
+			return f.NewFromBits(0), big.Below
+		}
+		return f.NewFromBits(uint16(bits.Uint64())), big.Exact
+	}
+	return ctx.NewFromBig(f, big.NewFloat(x))
+}
+
+// NewFromBig returns the nearest floating-point number of the format f for x
+// and the accuracy of the conversion, as for f.NewFromBig. The exception flags
+// raised are recorded in ctx; an infinity converted to a format without
+// infinities raises the invalid operation exception.
+func (ctx *Context) NewFromBig(f Format, x *big.Float) (Float, big.Accuracy) {
+	f.validate()
+	if f.NoSubnormals && x.Sign() != 0 && !x.IsInf() {
+		abs := new(big.Float).Abs(x)
+		min := f.minNormal()
+		if abs.Cmp(min) < 0 {
+			var bits uint16
+			acc := big.Below
+			if abs.SetMantExp(abs, 1).Cmp(min) > 0 {
+				bits = 1 << f.MantBits
+				acc = big.Above
+			}
+			if x.Signbit() {
+				bits |= f.signBit()
+				acc = -acc
+			}
+			ctx.Flags |= rounding.Flags(x, acc, f.Precision(), f.Emin(), math.MaxInt32, big.ToNearestEven, ctx.Tininess)
+			return Float{format: f, bits: bits}, acc
+		}
+	}
+	c := &ieee754.Context{Tininess: ctx.Tininess}
+	bits, acc := c.Encode(f.ieee(), x)
+	ctx.Flags |= c.Flags
+	return Float{format: f, bits: uint16(bits.Uint64())}, acc
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in ctx;
+// a signaling NaN raises the invalid operation exception.
+func (ctx *Context) Float64(f Float) (float64, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if f.Class() == float.SignalingNaN {
+			ctx.Flags |= fenv.Invalid
+		}
+		return f.Float64()
+	}
+	y, acc := x.Float64()
+	ctx.Flags |= rounding.Flags(x, acc, 53, -1022, 1023, big.ToNearestEven, ctx.Tininess)
+	return y, acc
+}
+
+// Float is a floating-point number of a minifloat format.
+type Float struct {
+	// Format of the floating-point number.
+	format Format
+	// Sign, exponent and mantissa; see Format.
+	bits uint16
+}
+
+// Bits returns the binary representation of f.
+func (f Float) Bits() uint16 {
+	return f.bits
+}
+
+// Float64 returns the float64 value of f and the accuracy of the conversion.
+// NaN is converted to NaN.
+func (f Float) Float64() (float64, big.Accuracy) {
+	x, nan := f.Big()
+	if nan {
+		if x.Signbit() {
+			return -math.NaN(), big.Exact
+		}
+		return math.NaN(), big.Exact
+	}
+	return x.Float64()
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number.
+func (f Float) Big() (x *big.Float, nan bool) {
+	if f.format.NoSubnormals && f.Exp() == 0 {
+		x = new(big.Float).SetPrec(f.format.Precision()).SetMode(big.ToNearestEven)
+		if f.Signbit() {
+			x.Neg(x)
+		}
+		return x, false
+	}
+	return f.format.ieee().Decode(big.NewInt(int64(f.bits)))
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f Float) Signbit() bool {
+	return f.bits&f.format.signBit() != 0
+}
+
+// Exp returns the exponent of f.
+func (f Float) Exp() int {
+	return int(f.bits>>f.format.MantBits) & (1<<f.format.ExpBits - 1)
+}
+
+// Frac returns the fraction of f.
+func (f Float) Frac() uint16 {
+	return f.bits & (1<<f.format.MantBits - 1)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// ieee returns the IEEE 754 style format of f, which has denormalized numbers.
+func (f Format) ieee() ieee754.Format {
+	return ieee754.Format{
+		ExpBits:  f.ExpBits,
+		FracBits: f.MantBits,
+		Bias:     f.Bias,
+		Special:  f.Special,
+	}
+}
+
+// minNormal returns the smallest positive normalized number in the format f.
+func (f Format) minNormal() *big.Float {
+	x := new(big.Float).SetPrec(f.Precision()).SetMode(big.ToNearestEven).SetInt64(1)
+	return x.SetMantExp(x, f.Emin())
+}
+
+// signBit returns the sign bit of the binary representation.
+func (f Format) signBit() uint16 {
+	return 1 << (f.Width() - 1)
+}
+
+// mask returns the mask of the binary representation.
+func (f Format) mask() uint16 {
+	return uint16(1<<f.Width() - 1)
+}
+
+// validate panics if the format f has no exponent bits or is wider than 16
+// bits.
+func (f Format) validate() {
+	if f.ExpBits < 1 || f.Width() > 16 {
+		panic(fmt.Errorf("minifloat: invalid format %v", f))
+	}
+}
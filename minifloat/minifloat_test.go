@@ -0,0 +1,286 @@
+package minifloat
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/float/bfloat"
+	"github.com/mewmew/float/binary16"
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/fp8"
+	"github.com/mewmew/float/ieee754"
+)
+
+func TestBinary16(t *testing.T) {
+	for _, f := range Binary16.Values() {
+		want := binary16.NewFromBits(f.Bits())
+		testSame(t, f, want)
+		if got, want := f.String(), want.String(); got != want {
+			t.Errorf("0x%04X: string mismatch; expected %q, got %q", f.Bits(), want, got)
+		}
+	}
+	// Halfway cases between binary16 numbers, and overflow.
+	for bits := 0; bits < 0x7C00; bits++ {
+		x, _ := binary16.NewFromBits(uint16(bits)).Big()
+		y, _ := binary16.NewFromBits(uint16(bits + 1)).Big()
+		if y.IsInf() {
+			y = big.NewFloat(65536)
+		}
+		x.SetPrec(64).Add(x, y).Quo(x, big.NewFloat(2))
+		for _, x := range []*big.Float{x, new(big.Float).Neg(x)} {
+			got, gotAcc := Binary16.NewFromBig(x)
+			want, wantAcc := binary16.NewFromBig(x)
+			if got.Bits() != want.Bits() || gotAcc != wantAcc {
+				t.Errorf("%v: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, want.Bits(), wantAcc, got.Bits(), gotAcc)
+			}
+		}
+	}
+}
+
+func TestBFloat16(t *testing.T) {
+	for _, f := range BFloat16.Values() {
+		want := bfloat.NewFromBits(f.Bits())
+		testSame(t, f, want)
+		if got, want := f.String(), want.String(); got != want {
+			t.Errorf("0x%04X: string mismatch; expected %q, got %q", f.Bits(), want, got)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		x := float64(math.Float32frombits(r.Uint32()))
+		if math.IsNaN(x) {
+			continue
+		}
+		got, gotAcc := BFloat16.NewFromFloat64(x)
+		want, wantAcc := bfloat.NewFromFloat64(x)
+		if got.Bits() != want.Bits() || gotAcc != wantAcc {
+			t.Errorf("%v: mismatch; expected 0x%04X (%v), got 0x%04X (%v)", x, want.Bits(), wantAcc, got.Bits(), gotAcc)
+		}
+	}
+}
+
+func TestFP8(t *testing.T) {
+	for _, f := range E4M3.Values() {
+		want := fp8.NewE4M3FromBits(uint8(f.Bits()))
+		testSame(t, f, want)
+		if got, want := f.String(), want.String(); got != want {
+			t.Errorf("0x%02X: string mismatch; expected %q, got %q", f.Bits(), want, got)
+		}
+	}
+	for _, f := range E5M2.Values() {
+		want := fp8.NewE5M2FromBits(uint8(f.Bits()))
+		testSame(t, f, want)
+		if got, want := f.String(), want.String(); got != want {
+			t.Errorf("0x%02X: string mismatch; expected %q, got %q", f.Bits(), want, got)
+		}
+	}
+}
+
+// testSame checks that f has the same value as want.
+func testSame(t *testing.T, f Float, want interface {
+	Big() (*big.Float, bool)
+}) {
+	t.Helper()
+	x, xnan := f.Big()
+	y, ynan := want.Big()
+	if xnan != ynan || x.Signbit() != y.Signbit() || !xnan && x.Cmp(y) != 0 {
+		t.Errorf("0x%04X: number mismatch; expected %v (NaN=%v), got %v (NaN=%v)", f.Bits(), y, ynan, x, xnan)
+	}
+}
+
+func TestValues(t *testing.T) {
+	golden := []struct {
+		format Format
+		want   []float64
+	}{
+		{
+			format: E2M1,
+			want:   []float64{0, 0.5, 1, 1.5, 2, 3, 4, 6},
+		},
+		{
+			format: E3M2,
+			want:   []float64{0, 0.0625, 0.125, 0.1875, 0.25, 0.3125, 0.375, 0.4375, 0.5, 0.625, 0.75, 0.875, 1, 1.25, 1.5, 1.75, 2, 2.5, 3, 3.5, 4, 5, 6, 7, 8, 10, 12, 14, 16, 20, 24, 28},
+		},
+		// Without denormalized numbers.
+		{
+			format: Format{ExpBits: 2, MantBits: 1, Bias: 1, Special: ieee754.NoSpecial, NoSubnormals: true},
+			want:   []float64{0, 0, 1, 1.5, 2, 3, 4, 6},
+		},
+		// With infinities and NaN.
+		{
+			format: Format{ExpBits: 2, MantBits: 1, Bias: 1, Special: ieee754.InfNaN},
+			want:   []float64{0, 0.5, 1, 1.5, 2, 3, math.Inf(1), math.NaN()},
+		},
+		// With NaN, a bias of 2 and no denormalized numbers.
+		{
+			format: Format{ExpBits: 2, MantBits: 1, Bias: 2, Special: ieee754.NaNOnly, NoSubnormals: true},
+			want:   []float64{0, 0, 0.5, 0.75, 1, 1.5, 2, math.NaN()},
+		},
+	}
+	for _, g := range golden {
+		values := g.format.Values()
+		if len(values) != 2*len(g.want) {
+			t.Errorf("%v: number of values mismatch; expected %d, got %d", g.format, 2*len(g.want), len(values))
+			continue
+		}
+		for i, f := range values {
+			if f.Bits() != uint16(i) {
+				t.Errorf("%v: bits mismatch; expected 0x%X, got 0x%X", g.format, i, f.Bits())
+			}
+			want := g.want[i%len(g.want)]
+			if i >= len(g.want) {
+				want = -want
+			}
+			got, _ := f.Float64()
+			if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+				t.Errorf("%v: 0x%X: value mismatch; expected %v, got %v", g.format, i, want, got)
+			}
+		}
+	}
+}
+
+func TestNewFromBig(t *testing.T) {
+	noSub := Format{ExpBits: 3, MantBits: 2, Bias: 3, Special: ieee754.NoSpecial, NoSubnormals: true}
+	golden := []struct {
+		format Format
+		in     float64
+		want   uint16
+		acc    big.Accuracy
+		flags  fenv.Flags
+	}{
+		{format: E2M1, in: 0, want: 0x0, acc: big.Exact},
+		{format: E2M1, in: math.Copysign(0, -1), want: 0x8, acc: big.Exact},
+		{format: E2M1, in: 2.5, want: 0x4, acc: big.Below, flags: fenv.Inexact},
+		{format: E2M1, in: 3.5, want: 0x6, acc: big.Above, flags: fenv.Inexact},
+		{format: E2M1, in: -5, want: 0xE, acc: big.Above, flags: fenv.Inexact},
+		{format: E2M1, in: 0.25, want: 0x0, acc: big.Below, flags: fenv.Underflow | fenv.Inexact},
+		{format: E2M1, in: 0.26, want: 0x1, acc: big.Above, flags: fenv.Underflow | fenv.Inexact},
+		// Saturation, as there are neither infinities nor NaN.
+		{format: E2M1, in: 7, want: 0x7, acc: big.Below, flags: fenv.Overflow | fenv.Inexact},
+		{format: E2M1, in: math.Inf(-1), want: 0xF, acc: big.Above, flags: fenv.Invalid},
+		// NaN.
+		{format: E4M3, in: 480, want: 0x7F, acc: big.Exact, flags: fenv.Overflow | fenv.Inexact},
+		{format: E4M3, in: math.NaN(), want: 0x7F, acc: big.Exact},
+		{format: E5M2, in: math.Float64frombits(0x7FF0000000000001), want: 0x7E, acc: big.Exact, flags: fenv.Invalid},
+		{format: E5M2, in: 61440, want: 0x7C, acc: big.Above, flags: fenv.Overflow | fenv.Inexact},
+		// NaN in formats without NaN.
+		{format: E2M1, in: math.NaN(), want: 0x0, acc: big.Below, flags: fenv.Invalid},
+		{format: E2M3, in: -math.NaN(), want: 0x00, acc: big.Below, flags: fenv.Invalid},
+		{format: E3M2, in: math.NaN(), want: 0x00, acc: big.Below, flags: fenv.Invalid},
+		// Values below the smallest normalized number, 0.25.
+		{format: noSub, in: 0.25, want: 0x04, acc: big.Exact},
+		{format: noSub, in: 0.2, want: 0x04, acc: big.Above, flags: fenv.Underflow | fenv.Inexact},
+		{format: noSub, in: -0.126, want: 0x24, acc: big.Below, flags: fenv.Underflow | fenv.Inexact},
+		{format: noSub, in: 0.125, want: 0x00, acc: big.Below, flags: fenv.Underflow | fenv.Inexact},
+		{format: noSub, in: -0.1, want: 0x20, acc: big.Above, flags: fenv.Underflow | fenv.Inexact},
+		{format: noSub, in: 0.24, want: 0x04, acc: big.Above, flags: fenv.Inexact},
+		{format: noSub, in: 0.3, want: 0x05, acc: big.Above, flags: fenv.Inexact},
+	}
+	for _, g := range golden {
+		ctx := new(Context)
+		got, acc := ctx.NewFromFloat64(g.format, g.in)
+		if g.want != got.Bits() {
+			t.Errorf("%v: %v: bits mismatch; expected 0x%X, got 0x%X", g.format, g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%v: %v: accuracy mismatch; expected %v, got %v", g.format, g.in, g.acc, acc)
+		}
+		if g.flags != ctx.Flags {
+			t.Errorf("%v: %v: flags mismatch; expected %v, got %v", g.format, g.in, g.flags, ctx.Flags)
+		}
+		if f, facc := g.format.NewFromFloat64(g.in); f != got || facc != acc {
+			t.Errorf("%v: %v: mismatch between context and format; expected 0x%X (%v), got 0x%X (%v)", g.format, g.in, got.Bits(), acc, f.Bits(), facc)
+		}
+	}
+}
+
+func TestFloat64Flags(t *testing.T) {
+	// Exponents beyond the range of float64.
+	wide := Format{ExpBits: 14, MantBits: 1, Bias: 8191, Special: ieee754.NoSpecial}
+	golden := []struct {
+		x     Float
+		flags fenv.Flags
+	}{
+		{x: wide.NewFromBits(0x3FFE)},
+		{x: wide.NewFromBits(0x7FFF), flags: fenv.Overflow | fenv.Inexact},
+		{x: wide.NewFromBits(0x0001), flags: fenv.Underflow | fenv.Inexact},
+		// Signaling and quiet NaN.
+		{x: E5M2.NewFromBits(0x7D), flags: fenv.Invalid},
+		{x: E5M2.NewFromBits(0x7E)},
+	}
+	for _, g := range golden {
+		ctx := new(Context)
+		ctx.Float64(g.x)
+		if g.flags != ctx.Flags {
+			t.Errorf("0x%04X: flags mismatch; expected %v, got %v", g.x.Bits(), g.flags, ctx.Flags)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	formats := []Format{
+		E2M1, E2M3, E3M2, E4M3, E5M2,
+		{ExpBits: 1, MantBits: 3, Bias: 0, Special: ieee754.NoSpecial},
+		{ExpBits: 4, MantBits: 7, Bias: 4, Special: ieee754.InfNaN, NoSubnormals: true},
+		{ExpBits: 6, MantBits: 5, Bias: 40, Special: ieee754.NaNOnly},
+	}
+	for _, format := range formats {
+		for _, f := range format.Values() {
+			x, nan := f.Big()
+			if nan || format.NoSubnormals && f.Exp() == 0 && f.Frac() != 0 {
+				continue
+			}
+			got, acc := format.NewFromBig(x)
+			if got != f || acc != big.Exact {
+				t.Errorf("%v: 0x%X: round-trip mismatch; expected 0x%X, got 0x%X (%v)", format, f.Bits(), f.Bits(), got.Bits(), acc)
+			}
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format Format
+		want   string
+		width  uint
+		max    float64
+		min    float64
+	}{
+		{format: E2M1, want: "E2M1 (bias 1)", width: 4, max: 6, min: 0.5},
+		{format: E4M3, want: "E4M3 (bias 7, NaN)", width: 8, max: 448, min: 0x1p-9},
+		{format: Binary16, want: "E5M10 (bias 15, Inf, NaN)", width: 16, max: 65504, min: 0x1p-24},
+		{format: Format{ExpBits: 3, MantBits: 2, Bias: 3, Special: ieee754.InfNaN, NoSubnormals: true}, want: "E3M2 (bias 3, Inf, NaN, no subnormals)", width: 6, max: 14, min: 0.25},
+	}
+	for _, g := range golden {
+		if got := g.format.String(); g.want != got {
+			t.Errorf("format mismatch; expected %q, got %q", g.want, got)
+		}
+		if got := g.format.Width(); g.width != got {
+			t.Errorf("%v: width mismatch; expected %d, got %d", g.format, g.width, got)
+		}
+		if got, _ := g.format.MaxValue().Float64(); g.max != got {
+			t.Errorf("%v: max mismatch; expected %v, got %v", g.format, g.max, got)
+		}
+		if got, _ := g.format.SmallestNonzero().Float64(); g.min != got {
+			t.Errorf("%v: min mismatch; expected %v, got %v", g.format, g.min, got)
+		}
+	}
+}
+
+func TestNewFromBits(t *testing.T) {
+	f := E2M1.NewFromBits(0xFF)
+	if got := f.Bits(); got != 0xF {
+		t.Errorf("bits mismatch; expected 0xF, got 0x%X", got)
+	}
+	if !f.Signbit() || f.Exp() != 3 || f.Frac() != 1 {
+		t.Errorf("0xF: fields mismatch; expected (true, 3, 1), got (%v, %d, %d)", f.Signbit(), f.Exp(), f.Frac())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for format wider than 16 bits")
+		}
+	}()
+	Format{ExpBits: 8, MantBits: 8, Bias: 127}.NewFromBits(0)
+}
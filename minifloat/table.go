@@ -0,0 +1,66 @@
+package minifloat
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// WriteTable writes a table of all floating-point numbers of the format f to w,
+// ordered by their binary representation. Each row lists the sign, exponent and
+// mantissa bits, the binary representation in hexadecimal, the class of the
+// number, its shortest decimal representation and its exact decimal value.
+func (f Format) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "# %v\n", f)
+	fmt.Fprintf(tw, "bits\thex\tclass\tvalue\texact\n")
+	for _, x := range f.Values() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%s\n", x.binary(), x.hex(), x.class(), x, x.exact())
+	}
+	return tw.Flush()
+}
+
+// binary returns the binary representation of f, with the sign, exponent and
+// mantissa separated by spaces.
+func (f Float) binary() string {
+	s := fmt.Sprintf("%0*b", f.format.Width(), f.bits)
+	e := 1 + f.format.ExpBits
+	if f.format.MantBits == 0 {
+		return s[:1] + " " + s[1:]
+	}
+	return s[:1] + " " + s[1:e] + " " + s[e:]
+}
+
+// hex returns the binary representation of f in hexadecimal.
+func (f Float) hex() string {
+	return fmt.Sprintf("0x%0*X", (f.format.Width()+3)/4, f.bits)
+}
+
+// class returns the class of f; one of "zero", "subnormal", "normal", "inf" or
+// "nan".
+func (f Float) class() string {
+	x, nan := f.Big()
+	switch {
+	case nan:
+		return "nan"
+	case x.IsInf():
+		return "inf"
+	case x.Sign() == 0:
+		return "zero"
+	case f.Exp() == 0:
+		return "subnormal"
+	}
+	return "normal"
+}
+
+// exact returns the exact decimal value of f.
+func (f Float) exact() string {
+	x, nan := f.Big()
+	if nan {
+		return "NaN"
+	}
+	// The shortest decimal between x and x inclusive.
+	return strconv.FormatBigRange(x, x, x, true, 'g', -1)
+}
@@ -0,0 +1,86 @@
+package minifloat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mewmew/float/ieee754"
+)
+
+func TestWriteTable(t *testing.T) {
+	golden := []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: E2M1,
+			want: `# E2M1 (bias 1)
+bits    hex  class      value  exact
+0 00 0  0x0  zero       0      0
+0 00 1  0x1  subnormal  0.5    0.5
+0 01 0  0x2  normal     1      1
+0 01 1  0x3  normal     1.5    1.5
+0 10 0  0x4  normal     2      2
+0 10 1  0x5  normal     3      3
+0 11 0  0x6  normal     4      4
+0 11 1  0x7  normal     6      6
+1 00 0  0x8  zero       -0     -0
+1 00 1  0x9  subnormal  -0.5   -0.5
+1 01 0  0xA  normal     -1     -1
+1 01 1  0xB  normal     -1.5   -1.5
+1 10 0  0xC  normal     -2     -2
+1 10 1  0xD  normal     -3     -3
+1 11 0  0xE  normal     -4     -4
+1 11 1  0xF  normal     -6     -6
+`,
+		},
+		{
+			format: Format{ExpBits: 2, MantBits: 2, Bias: 3, Special: ieee754.InfNaN},
+			want: `# E2M2 (bias 3, Inf, NaN)
+bits     hex   class      value  exact
+0 00 00  0x00  zero       0      0
+0 00 01  0x01  subnormal  0.06   0.0625
+0 00 10  0x02  subnormal  0.1    0.125
+0 00 11  0x03  subnormal  0.2    0.1875
+0 01 00  0x04  normal     0.25   0.25
+0 01 01  0x05  normal     0.3    0.3125
+0 01 10  0x06  normal     0.4    0.375
+0 01 11  0x07  normal     0.44   0.4375
+0 10 00  0x08  normal     0.5    0.5
+0 10 01  0x09  normal     0.6    0.625
+0 10 10  0x0A  normal     0.8    0.75
+0 10 11  0x0B  normal     0.9    0.875
+0 11 00  0x0C  inf        +Inf   +Inf
+0 11 01  0x0D  nan        NaN    NaN
+0 11 10  0x0E  nan        NaN    NaN
+0 11 11  0x0F  nan        NaN    NaN
+1 00 00  0x10  zero       -0     -0
+1 00 01  0x11  subnormal  -0.06  -0.0625
+1 00 10  0x12  subnormal  -0.1   -0.125
+1 00 11  0x13  subnormal  -0.2   -0.1875
+1 01 00  0x14  normal     -0.25  -0.25
+1 01 01  0x15  normal     -0.3   -0.3125
+1 01 10  0x16  normal     -0.4   -0.375
+1 01 11  0x17  normal     -0.44  -0.4375
+1 10 00  0x18  normal     -0.5   -0.5
+1 10 01  0x19  normal     -0.6   -0.625
+1 10 10  0x1A  normal     -0.8   -0.75
+1 10 11  0x1B  normal     -0.9   -0.875
+1 11 00  0x1C  inf        -Inf   -Inf
+1 11 01  0x1D  nan        NaN    NaN
+1 11 10  0x1E  nan        NaN    NaN
+1 11 11  0x1F  nan        NaN    NaN
+`,
+		},
+	}
+	for _, g := range golden {
+		buf := &strings.Builder{}
+		if err := g.format.WriteTable(buf); err != nil {
+			t.Errorf("%v: unexpected error; %v", g.format, err)
+			continue
+		}
+		if got := buf.String(); g.want != got {
+			t.Errorf("%v: table mismatch; expected\n%s\ngot\n%s", g.format, g.want, got)
+		}
+	}
+}
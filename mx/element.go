@@ -0,0 +1,167 @@
+package mx
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/fp8"
+	"github.com/mewmew/float/ieee754"
+)
+
+// FP6 and FP4 element formats, without infinities and NaN.
+var (
+	// FP6 E2M3; largest value 7.5.
+	e2m3 = ieee754.Format{ExpBits: 2, FracBits: 3, Bias: 1, Special: ieee754.NoSpecial}
+	// FP6 E3M2; largest value 28.
+	e3m2 = ieee754.Format{ExpBits: 3, FracBits: 2, Bias: 3, Special: ieee754.NoSpecial}
+	// FP4 E2M1; largest value 6.
+	e2m1 = ieee754.Format{ExpBits: 2, FracBits: 1, Bias: 1, Special: ieee754.NoSpecial}
+)
+
+// hasNaN reports whether the element format f has NaN.
+func (f Format) hasNaN() bool {
+	return f == MXFP8E4M3 || f == MXFP8E5M2
+}
+
+// emax returns the exponent of the largest normal number of the element format
+// f.
+func (f Format) emax() int {
+	switch f {
+	case MXFP8E4M3:
+		return 8
+	case MXFP8E5M2:
+		return 15
+	case MXFP6E2M3:
+		return e2m3.Emax()
+	case MXFP6E3M2:
+		return e3m2.Emax()
+	case MXFP4E2M1:
+		return e2m1.Emax()
+	case MXINT8:
+		return 0
+	}
+	panic(fmt.Errorf("mx: invalid element format %v", f))
+}
+
+// maxValue returns the largest value of the element format f.
+func (f Format) maxValue() *big.Float {
+	switch f {
+	case MXFP8E4M3:
+		x, _ := fp8.E4M3Max.Big()
+		return x
+	case MXFP8E5M2:
+		x, _ := fp8.E5M2Max.Big()
+		return x
+	case MXFP6E2M3:
+		return e2m3.MaxValue()
+	case MXFP6E3M2:
+		return e3m2.MaxValue()
+	case MXFP4E2M1:
+		return e2m1.MaxValue()
+	case MXINT8:
+		// 127 * 2^-6
+		return big.NewFloat(0x1.fcp0)
+	}
+	panic(fmt.Errorf("mx: invalid element format %v", f))
+}
+
+// encode returns the binary representation of the element of format f nearest
+// to x / 2^exp, rounded to nearest even with saturation. The exception flags
+// raised are recorded in ctx.
+func (ctx *Context) encode(f Format, x float64, exp int) uint8 {
+	c8 := &fp8.Context{Tininess: ctx.Tininess}
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		// Infinities are NaN in E4M3 and Inf in E5M2.
+		switch f {
+		case MXFP8E4M3:
+			e, _ := c8.NewE4M3FromFloat64(x, fp8.NonSaturating)
+			ctx.Flags |= c8.Flags
+			return e.Bits()
+		case MXFP8E5M2:
+			e, _ := c8.NewE5M2FromFloat64(x, fp8.NonSaturating)
+			ctx.Flags |= c8.Flags
+			return e.Bits()
+		}
+		panic(fmt.Errorf("mx: no NaN in element format %v", f))
+	}
+	v := big.NewFloat(x)
+	v.SetMantExp(v, -exp)
+	switch f {
+	case MXFP8E4M3:
+		e, _ := c8.NewE4M3FromBig(v, fp8.Saturating)
+		ctx.Flags |= c8.Flags
+		return e.Bits()
+	case MXFP8E5M2:
+		e, _ := c8.NewE5M2FromBig(v, fp8.Saturating)
+		ctx.Flags |= c8.Flags
+		return e.Bits()
+	case MXFP6E2M3:
+		return ctx.encodeIEEE(e2m3, v)
+	case MXFP6E3M2:
+		return ctx.encodeIEEE(e3m2, v)
+	case MXFP4E2M1:
+		return ctx.encodeIEEE(e2m1, v)
+	case MXINT8:
+		// Round v * 2^6 to the nearest even integer, with saturation.
+		v.SetMantExp(v, 6)
+		i, acc := v.Int64()
+		if acc != big.Exact {
+			ctx.Flags |= fenv.Inexact
+			frac := new(big.Float).Sub(v, new(big.Float).SetInt64(i))
+			switch c := frac.Abs(frac).Cmp(big.NewFloat(0.5)); {
+			case c > 0, c == 0 && i&1 != 0:
+				// Int64 truncates towards zero.
+				if v.Sign() > 0 {
+					i++
+				} else {
+					i--
+				}
+			}
+		}
+		switch {
+		case i < -128:
+			ctx.Flags |= fenv.Overflow | fenv.Inexact
+			i = -128
+		case i > 127:
+			ctx.Flags |= fenv.Overflow | fenv.Inexact
+			i = 127
+		}
+		return uint8(int8(i))
+	}
+	panic(fmt.Errorf("mx: invalid element format %v", f))
+}
+
+// encodeIEEE returns the binary representation of the element of the FP6 or FP4
+// format f nearest to the finite x, rounded to nearest even with saturation.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) encodeIEEE(f ieee754.Format, x *big.Float) uint8 {
+	c := &ieee754.Context{Tininess: ctx.Tininess}
+	bits, _ := c.Encode(f, x)
+	ctx.Flags |= c.Flags
+	return uint8(bits.Uint64())
+}
+
+// decode returns the multi-precision floating-point number representation of
+// the element bits of format f, and a boolean indicating whether it is
+// Not-a-Number.
+func (f Format) decode(bits uint8) (x *big.Float, nan bool) {
+	switch f {
+	case MXFP8E4M3:
+		return fp8.NewE4M3FromBits(bits).Big()
+	case MXFP8E5M2:
+		return fp8.NewE5M2FromBits(bits).Big()
+	case MXFP6E2M3:
+		return e2m3.Decode(big.NewInt(int64(bits & 0x3F)))
+	case MXFP6E3M2:
+		return e3m2.Decode(big.NewInt(int64(bits & 0x3F)))
+	case MXFP4E2M1:
+		return e2m1.Decode(big.NewInt(int64(bits & 0x0F)))
+	case MXINT8:
+		// int8 * 2^-6
+		x = new(big.Float).SetPrec(8).SetInt64(int64(int8(bits)))
+		return x.SetMantExp(x, -6), false
+	}
+	panic(fmt.Errorf("mx: invalid element format %v", f))
+}
@@ -0,0 +1,288 @@
+// Package mx implements encoding and decoding of the block floating-point
+// formats of the OCP Microscaling Formats (MX) Specification; MXFP8, MXFP6,
+// MXFP4 and MXINT8.
+//
+// An MX block encodes 32 numbers as 32 private elements of a scalar format and
+// a shared scale; a power of two in E8M0 format. The value of each number is the
+// product of the shared scale and its element. The IEEE 754 exception flags
+// raised by packing and unpacking are recorded in a Context.
+//
+// https://www.opencompute.org/documents/ocp-microscaling-formats-mx-v1-0-spec-final-pdf
+package mx
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+// BlockSize specifies the number of elements of an MX block.
+const BlockSize = 32
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+//
+// The flags of a block are those raised by the conversion of each number to its
+// element, relative to the shared scale. Numbers saturated to the largest
+// element value raise the overflow exception, and infinities encoded as NaN the
+// invalid operation exception. MXINT8 elements are fixed-point numbers, which
+// raise the inexact and overflow exceptions only.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// Format specifies the format of the elements of an MX block.
+type Format uint8
+
+// Element formats.
+const (
+	// MXFP8 with FP8 E4M3 elements.
+	MXFP8E4M3 Format = iota
+	// MXFP8 with FP8 E5M2 elements.
+	MXFP8E5M2
+	// MXFP6 with FP6 E2M3 elements.
+	MXFP6E2M3
+	// MXFP6 with FP6 E3M2 elements.
+	MXFP6E3M2
+	// MXFP4 with FP4 E2M1 elements.
+	MXFP4E2M1
+	// MXINT8 with 8-bit two's complement integer elements with an implicit
+	// scale of 2^-6.
+	MXINT8
+)
+
+// String returns the name of the format f.
+func (f Format) String() string {
+	switch f {
+	case MXFP8E4M3:
+		return "MXFP8 (E4M3)"
+	case MXFP8E5M2:
+		return "MXFP8 (E5M2)"
+	case MXFP6E2M3:
+		return "MXFP6 (E2M3)"
+	case MXFP6E3M2:
+		return "MXFP6 (E3M2)"
+	case MXFP4E2M1:
+		return "MXFP4 (E2M1)"
+	case MXINT8:
+		return "MXINT8"
+	}
+	return fmt.Sprintf("Format(%d)", uint8(f))
+}
+
+// Scale is a shared scale of an MX block, in E8M0 format; an unsigned 8-bit
+// exponent with bias 127, representing 2^-127 through 2^127, and NaN.
+type Scale uint8
+
+// ScaleNaN is the Not-a-Number shared scale. All numbers of a block with a NaN
+// scale are NaN.
+const ScaleNaN Scale = 0xFF
+
+// NewScale returns the shared scale 2^exp, with exp clamped to the range of
+// E8M0 exponents, [-127, 127].
+func NewScale(exp int) Scale {
+	switch {
+	case exp < -127:
+		exp = -127
+	case exp > 127:
+		exp = 127
+	}
+	return Scale(exp + 127)
+}
+
+// Exp returns the exponent of the shared scale s; i.e. s = 2^exp.
+func (s Scale) Exp() int {
+	return int(s) - 127
+}
+
+// Big returns the multi-precision floating-point number representation of s
+// and a boolean indicating whether s is Not-a-Number.
+func (s Scale) Big() (x *big.Float, nan bool) {
+	x = big.NewFloat(0)
+	if s == ScaleNaN {
+		return x, true
+	}
+	return x.SetMantExp(big.NewFloat(1), s.Exp()), false
+}
+
+// ScaleRounding specifies the selection of the shared scale of an MX block from
+// the largest magnitude of its numbers.
+type ScaleRounding uint8
+
+// Scale selection methods.
+const (
+	// RoundFloor selects the shared exponent floor(log2(amax)) - emax, where
+	// amax is the largest magnitude of the numbers of the block and emax the
+	// exponent of the largest normal number of the element format, as in
+	// section 6.3 of the MX specification. The largest numbers of a block may
+	// saturate to the largest element value.
+	RoundFloor ScaleRounding = iota
+	// RoundCeil selects the shared exponent ceil(log2(amax/max)), where max is
+	// the largest element value; the smallest scale for which no number of the
+	// block saturates.
+	RoundCeil
+)
+
+// Block is an MX block.
+type Block struct {
+	// Format of elements.
+	Format Format
+	// Shared scale.
+	Scale Scale
+	// Binary representation of elements; in the least significant bits for
+	// elements narrower than 8 bits.
+	Elements [BlockSize]uint8
+}
+
+// Pack encodes the numbers xs into MX blocks of the element format f, using the
+// shared scale selection method r. Elements are rounded to nearest even, and
+// saturate to the largest element value of the same sign. The last block is
+// padded with zeros.
+//
+// Not-a-Number values and infinities are encoded as element NaN and Inf if the
+// element format has them, and as NaN otherwise; a NaN element of E4M3 or a
+// NaN scale.
+func Pack(f Format, xs []float32, r ScaleRounding) []Block {
+	return new(Context).Pack(f, xs, r)
+}
+
+// Pack encodes the numbers xs into MX blocks of the element format f, as for
+// Pack. The exception flags raised are recorded in ctx.
+func (ctx *Context) Pack(f Format, xs []float32, r ScaleRounding) []Block {
+	blocks := make([]Block, (len(xs)+BlockSize-1)/BlockSize)
+	for i := range blocks {
+		end := (i + 1) * BlockSize
+		if end > len(xs) {
+			end = len(xs)
+		}
+		blocks[i] = ctx.PackBlock(f, xs[i*BlockSize:end], r)
+	}
+	return blocks
+}
+
+// PackBlock encodes the numbers xs, at most BlockSize, into an MX block of the
+// element format f, as for Pack.
+func PackBlock(f Format, xs []float32, r ScaleRounding) Block {
+	return new(Context).PackBlock(f, xs, r)
+}
+
+// PackBlock encodes the numbers xs, at most BlockSize, into an MX block of the
+// element format f, as for PackBlock. The exception flags raised are recorded
+// in ctx; a signaling NaN raises the invalid operation exception.
+func (ctx *Context) PackBlock(f Format, xs []float32, r ScaleRounding) Block {
+	if len(xs) > BlockSize {
+		panic(fmt.Errorf("mx.PackBlock: %d numbers exceed block size %d", len(xs), BlockSize))
+	}
+	b := Block{Format: f}
+	// Largest magnitude of finite numbers.
+	var amax float64
+	for _, x := range xs {
+		v := math.Abs(float64(x))
+		switch {
+		case math.IsNaN(v):
+			if payload.Signaling32(x) {
+				ctx.Flags |= fenv.Invalid
+			}
+			if !f.hasNaN() {
+				b.Scale = ScaleNaN
+				return b
+			}
+		case math.IsInf(v, 0):
+			if !f.hasNaN() {
+				ctx.Flags |= fenv.Invalid
+				b.Scale = ScaleNaN
+				return b
+			}
+		case v > amax:
+			amax = v
+		}
+	}
+	b.Scale = f.scale(amax, r)
+	for i, x := range xs {
+		b.Elements[i] = ctx.encode(f, float64(x), b.Scale.Exp())
+	}
+	return b
+}
+
+// Unpack decodes the numbers of the MX blocks, as for Block.Float32s. The
+// result has BlockSize numbers for every block, including the padding of the
+// last block.
+func Unpack(blocks []Block) []float32 {
+	return new(Context).Unpack(blocks)
+}
+
+// Unpack decodes the numbers of the MX blocks, as for Unpack. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) Unpack(blocks []Block) []float32 {
+	xs := make([]float32, 0, len(blocks)*BlockSize)
+	for _, b := range blocks {
+		xs = append(xs, ctx.Float32s(b)...)
+	}
+	return xs
+}
+
+// Float32s returns the numbers of the MX block b, rounded to nearest even
+// float32 values.
+func (b Block) Float32s() []float32 {
+	return new(Context).Float32s(b)
+}
+
+// Float32s returns the numbers of the MX block b, rounded to nearest even
+// float32 values, as for b.Float32s. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) Float32s(b Block) []float32 {
+	xs := make([]float32, BlockSize)
+	for i := range xs {
+		x, nan := b.Big(i)
+		if nan {
+			xs[i] = float32(math.NaN())
+			continue
+		}
+		var acc big.Accuracy
+		xs[i], acc = x.Float32()
+		if x.Sign() != 0 && !x.IsInf() {
+			ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+		}
+	}
+	return xs
+}
+
+// Big returns the exact value of the i:th number of the MX block b, as a
+// multi-precision floating-point number, and a boolean indicating whether it
+// is Not-a-Number.
+func (b Block) Big(i int) (x *big.Float, nan bool) {
+	if b.Scale == ScaleNaN {
+		return big.NewFloat(0), true
+	}
+	x, nan = b.Format.decode(b.Elements[i])
+	if nan || x.IsInf() {
+		return x, nan
+	}
+	return x.SetMantExp(x, b.Scale.Exp()), false
+}
+
+// scale returns the shared scale of a block of element format f with largest
+// magnitude amax, using the scale selection method r.
+func (f Format) scale(amax float64, r ScaleRounding) Scale {
+	if amax == 0 {
+		return NewScale(-127)
+	}
+	// amax = frac * 2^exp, 0.5 <= frac < 1
+	_, exp := math.Frexp(amax)
+	e := exp - 1 - f.emax()
+	if r == RoundCeil {
+		max, _ := f.maxValue().Float64()
+		if amax > math.Ldexp(max, e) {
+			e++
+		}
+	}
+	return NewScale(e)
+}
@@ -0,0 +1,272 @@
+package mx
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestPackBlock(t *testing.T) {
+	golden := []struct {
+		f     Format
+		r     ScaleRounding
+		in    []float32
+		scale int
+		elems []uint8
+		want  []float32
+	}{
+		// amax = 32 = 2^5; scale 2^(5-8); elements 8, 16, ..., 256.
+		{
+			f:     MXFP8E4M3,
+			r:     RoundFloor,
+			in:    []float32{1, -2, 0.5, 32},
+			scale: -3,
+			elems: []uint8{0x50, 0xD8, 0x48, 0x78},
+			want:  []float32{1, -2, 0.5, 32},
+		},
+		// amax = 57344 * 2^-10; scale 2^(5-15).
+		{
+			f:     MXFP8E5M2,
+			r:     RoundFloor,
+			in:    []float32{56, 0.25, float32(math.Inf(-1))},
+			scale: -10,
+			elems: []uint8{0x7B, 0x5C, 0xFC},
+			want:  []float32{56, 0.25, float32(math.Inf(-1))},
+		},
+		// amax = 7; scale 2^(2-2); 7 saturates to 6.
+		{
+			f:     MXFP4E2M1,
+			r:     RoundFloor,
+			in:    []float32{7, -0.5, 1.25, 0.2},
+			scale: 0,
+			elems: []uint8{0x07, 0x09, 0x02, 0x00},
+			want:  []float32{6, -0.5, 1, 0},
+		},
+		// amax = 7 > 6; scale 2^1; 7/2 = 3.5 rounds to 4 and -0.5/2 to -0 (ties
+		// to even).
+		{
+			f:     MXFP4E2M1,
+			r:     RoundCeil,
+			in:    []float32{7, -0.5, 1.25, 0.2},
+			scale: 1,
+			elems: []uint8{0x06, 0x08, 0x01, 0x00},
+			want:  []float32{8, 0, 1, 0},
+		},
+		// amax = 30; scale 2^(4-2); 30/4 = 7.5.
+		{
+			f:     MXFP6E2M3,
+			r:     RoundFloor,
+			in:    []float32{30, 1, -3},
+			scale: 2,
+			elems: []uint8{0x1F, 0x02, 0x26},
+			want:  []float32{30, 1, -3},
+		},
+		// amax = 100; scale 2^(6-4); 100/4 = 25 rounds to 24 (ties to even).
+		{
+			f:     MXFP6E3M2,
+			r:     RoundFloor,
+			in:    []float32{100, -0.25},
+			scale: 2,
+			elems: []uint8{0x1E, 0x21},
+			want:  []float32{96, -0.25},
+		},
+		// amax = 2; scale 2^1; elements 48, -64 and 3.2 rounds to 3.
+		{
+			f:     MXINT8,
+			r:     RoundFloor,
+			in:    []float32{1.5, -2, 0.1},
+			scale: 1,
+			elems: []uint8{48, 0xC0, 3},
+			want:  []float32{1.5, -2, 0.09375},
+		},
+		// amax = 1.99 > 127 * 2^-6; scale 2^1; elements 63.68 rounds to 64, -32
+		// and 3.2 rounds to 3.
+		{
+			f:     MXINT8,
+			r:     RoundCeil,
+			in:    []float32{1.99, -1, 0.1},
+			scale: 1,
+			elems: []uint8{64, 0xE0, 3},
+			want:  []float32{2, -1, 0.09375},
+		},
+		// All zeros.
+		{
+			f:     MXFP8E4M3,
+			r:     RoundCeil,
+			in:    []float32{0, 0},
+			scale: -127,
+			elems: []uint8{0, 0},
+			want:  []float32{0, 0},
+		},
+	}
+	for _, g := range golden {
+		b := PackBlock(g.f, g.in, g.r)
+		if got := b.Scale.Exp(); got != g.scale {
+			t.Errorf("%v %v: scale mismatch; expected 2^%d, got 2^%d", g.f, g.in, g.scale, got)
+			continue
+		}
+		got := b.Float32s()
+		for i := range g.in {
+			if b.Elements[i] != g.elems[i] {
+				t.Errorf("%v %v: element %d mismatch; expected 0x%02X, got 0x%02X", g.f, g.in, i, g.elems[i], b.Elements[i])
+			}
+			if got[i] != g.want[i] {
+				t.Errorf("%v %v: number %d mismatch; expected %v, got %v", g.f, g.in, i, g.want[i], got[i])
+			}
+		}
+		for i := len(g.in); i < BlockSize; i++ {
+			if got[i] != 0 {
+				t.Errorf("%v %v: padding %d mismatch; expected 0, got %v", g.f, g.in, i, got[i])
+			}
+		}
+	}
+}
+
+func TestPackNaN(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	for _, f := range []Format{MXFP6E2M3, MXFP6E3M2, MXFP4E2M1, MXINT8} {
+		for _, x := range []float32{nan, inf} {
+			b := PackBlock(f, []float32{1, x}, RoundFloor)
+			if b.Scale != ScaleNaN {
+				t.Errorf("%v %v: expected NaN scale, got 2^%d", f, x, b.Scale.Exp())
+			}
+			for i, y := range b.Float32s() {
+				if y == y {
+					t.Errorf("%v %v: number %d mismatch; expected NaN, got %v", f, x, i, y)
+				}
+			}
+		}
+	}
+	// Infinities are NaN in E4M3.
+	b := PackBlock(MXFP8E4M3, []float32{1, nan, inf}, RoundFloor)
+	if b.Scale.Exp() != -8 || b.Elements[1] != 0x7F || b.Elements[2] != 0x7F {
+		t.Errorf("E4M3: mismatch; expected scale 2^-8 and NaN elements, got scale 2^%d and elements 0x%02X, 0x%02X", b.Scale.Exp(), b.Elements[1], b.Elements[2])
+	}
+}
+
+func TestFlags(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	snan := math.Float32frombits(0x7F800001)
+	golden := []struct {
+		name          string
+		f             Format
+		r             ScaleRounding
+		in            []float32
+		after, before fenv.Flags
+	}{
+		{name: "exact", f: MXFP8E4M3, r: RoundFloor, in: []float32{1, -2, 0.5, 32}},
+		// 0.1 * 2^2 rounds to the subnormal 0.5 of E2M1.
+		{name: "subnormal", f: MXFP4E2M1, r: RoundFloor, in: []float32{1, 0.1}, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 1.875 * 2^8 = 480 saturates to 448, unless the scale is rounded up.
+		{name: "saturated", f: MXFP8E4M3, r: RoundFloor, in: []float32{1.875}, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "ceil", f: MXFP8E4M3, r: RoundCeil, in: []float32{1.875}},
+		// MXINT8 elements are fixed-point numbers.
+		{name: "int8", f: MXINT8, r: RoundFloor, in: []float32{1, 0.01}, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "int8 saturated", f: MXINT8, r: RoundFloor, in: []float32{1.999}, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// Infinities are NaN in E4M3 and formats without NaN.
+		{name: "E4M3 Inf", f: MXFP8E4M3, r: RoundFloor, in: []float32{1, inf}, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "E5M2 Inf", f: MXFP8E5M2, r: RoundFloor, in: []float32{1, -inf}},
+		{name: "E2M3 Inf", f: MXFP6E2M3, r: RoundFloor, in: []float32{1, inf}, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "E2M3 NaN", f: MXFP6E2M3, r: RoundFloor, in: []float32{1, nan}},
+		{name: "E5M2 sNaN", f: MXFP8E5M2, r: RoundFloor, in: []float32{1, snan}, after: fenv.Invalid, before: fenv.Invalid},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			ctx.PackBlock(g.f, g.in, g.r)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+	// Numbers of blocks are exact float32 values, unless too large in
+	// magnitude.
+	ctx := new(Context)
+	ctx.Float32s(Block{Format: MXFP8E5M2, Scale: NewScale(-127), Elements: [BlockSize]uint8{0x01}})
+	if ctx.Flags != 0 {
+		t.Errorf("2^-143: flags mismatch; expected %v, got %v", fenv.Flags(0), ctx.Flags)
+	}
+	ctx.Unpack([]Block{{Format: MXFP8E5M2, Scale: NewScale(127), Elements: [BlockSize]uint8{0x7B}}})
+	if want := fenv.Overflow | fenv.Inexact; ctx.Flags != want {
+		t.Errorf("57344 * 2^127: flags mismatch; expected %v, got %v", want, ctx.Flags)
+	}
+}
+
+func TestPackUnpack(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	xs := make([]float32, 1000)
+	for i := range xs {
+		xs[i] = float32(r.NormFloat64() * math.Exp2(float64(r.Intn(40)-20)))
+	}
+	formats := []struct {
+		f Format
+		// Number of significant bits of elements.
+		prec int
+		// Exponent of the smallest positive element.
+		min int
+	}{
+		{f: MXFP8E4M3, prec: 4, min: -9},
+		{f: MXFP8E5M2, prec: 3, min: -16},
+		{f: MXFP6E2M3, prec: 4, min: -3},
+		{f: MXFP6E3M2, prec: 3, min: -4},
+		{f: MXFP4E2M1, prec: 2, min: -1},
+		{f: MXINT8, prec: 7, min: -6},
+	}
+	for _, format := range formats {
+		blocks := Pack(format.f, xs, RoundCeil)
+		if len(blocks) != 32 {
+			t.Fatalf("%v: number of blocks mismatch; expected 32, got %d", format.f, len(blocks))
+		}
+		ys := Unpack(blocks)
+		for i, x := range xs {
+			// No saturation with RoundCeil; the error of normal elements is
+			// at most half a unit in the last place, and of others at most
+			// half the smallest element of the block.
+			b := blocks[i/BlockSize]
+			tiny := math.Ldexp(1, b.Scale.Exp()+format.min-1)
+			ulp := math.Ldexp(math.Abs(float64(x)), 1-format.prec)
+			if diff := math.Abs(float64(ys[i] - x)); diff > ulp/2 && diff > tiny {
+				t.Errorf("%v: number %d mismatch; expected %v, got %v", format.f, i, x, ys[i])
+			}
+		}
+		for _, y := range ys[len(xs):] {
+			if y != 0 {
+				t.Errorf("%v: padding mismatch; expected 0, got %v", format.f, y)
+			}
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	golden := []struct {
+		exp  int
+		want Scale
+	}{
+		{exp: 0, want: 127},
+		{exp: -127, want: 0},
+		{exp: 127, want: 254},
+		{exp: -200, want: 0},
+		{exp: 200, want: 254},
+	}
+	for _, g := range golden {
+		s := NewScale(g.exp)
+		if s != g.want {
+			t.Errorf("2^%d: scale mismatch; expected 0x%02X, got 0x%02X", g.exp, g.want, s)
+		}
+		x, nan := s.Big()
+		if got, _ := x.Float64(); nan || got != math.Ldexp(1, g.want.Exp()) {
+			t.Errorf("0x%02X: value mismatch; expected 2^%d, got %v", s, g.want.Exp(), x)
+		}
+	}
+	if _, nan := ScaleNaN.Big(); !nan {
+		t.Errorf("0xFF: expected NaN")
+	}
+}
@@ -0,0 +1,286 @@
+package posit
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// ### [ Posit8 ] ##############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. NaR
+// is less than any real number and equal to NaR, as by the posit standard; i.e.
+// posits are ordered as their binary representations in two's complement.
+func (f Posit8) Cmp(g Posit8) int {
+	switch x, y := int8(f.bits), int8(g.bits); {
+	case x < y:
+		return -1
+	case x > y:
+		return +1
+	}
+	return 0
+}
+
+// Less reports whether f < g. NaR is less than any real number, as by the posit
+// standard.
+func (f Posit8) Less(g Posit8) bool {
+	return int8(f.bits) < int8(g.bits)
+}
+
+// Equal reports whether f == g. NaR is equal to NaR, as by the posit standard.
+func (f Posit8) Equal(g Posit8) bool {
+	return f.bits == g.bits
+}
+
+// TotalOrder reports whether f <= g; the order of Cmp is total.
+func (f Posit8) TotalOrder(g Posit8) bool {
+	return int8(f.bits) <= int8(g.bits)
+}
+
+// Class returns the class of f; NaR is a quiet NaN, and non-zero real numbers
+// are normal.
+func (f Posit8) Class() float.Class {
+	switch {
+	case f.IsNaR():
+		return float.QuietNaN
+	case f.IsZero():
+		return float.PositiveZero
+	}
+	return order.Signed(f.Signbit(), float.PositiveNormal)
+}
+
+// IsNaN reports whether f is NaR, as IsNaR.
+func (f Posit8) IsNaN() bool {
+	return f.IsNaR()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as posits have no
+// infinities.
+func (f Posit8) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as posits
+// have no subnormal numbers.
+func (f Posit8) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is a real number; i.e. not NaR.
+func (f Posit8) IsFinite() bool {
+	return !f.IsNaR()
+}
+
+// IsZero reports whether f is zero.
+func (f Posit8) IsZero() bool {
+	return f.bits == 0
+}
+
+// ### [ Posit16 ] #############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. NaR
+// is less than any real number and equal to NaR, as by the posit standard; i.e.
+// posits are ordered as their binary representations in two's complement.
+func (f Posit16) Cmp(g Posit16) int {
+	switch x, y := int16(f.bits), int16(g.bits); {
+	case x < y:
+		return -1
+	case x > y:
+		return +1
+	}
+	return 0
+}
+
+// Less reports whether f < g. NaR is less than any real number, as by the posit
+// standard.
+func (f Posit16) Less(g Posit16) bool {
+	return int16(f.bits) < int16(g.bits)
+}
+
+// Equal reports whether f == g. NaR is equal to NaR, as by the posit standard.
+func (f Posit16) Equal(g Posit16) bool {
+	return f.bits == g.bits
+}
+
+// TotalOrder reports whether f <= g; the order of Cmp is total.
+func (f Posit16) TotalOrder(g Posit16) bool {
+	return int16(f.bits) <= int16(g.bits)
+}
+
+// Class returns the class of f; NaR is a quiet NaN, and non-zero real numbers
+// are normal.
+func (f Posit16) Class() float.Class {
+	switch {
+	case f.IsNaR():
+		return float.QuietNaN
+	case f.IsZero():
+		return float.PositiveZero
+	}
+	return order.Signed(f.Signbit(), float.PositiveNormal)
+}
+
+// IsNaN reports whether f is NaR, as IsNaR.
+func (f Posit16) IsNaN() bool {
+	return f.IsNaR()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as posits have no
+// infinities.
+func (f Posit16) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as posits
+// have no subnormal numbers.
+func (f Posit16) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is a real number; i.e. not NaR.
+func (f Posit16) IsFinite() bool {
+	return !f.IsNaR()
+}
+
+// IsZero reports whether f is zero.
+func (f Posit16) IsZero() bool {
+	return f.bits == 0
+}
+
+// ### [ Posit32 ] #############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. NaR
+// is less than any real number and equal to NaR, as by the posit standard; i.e.
+// posits are ordered as their binary representations in two's complement.
+func (f Posit32) Cmp(g Posit32) int {
+	switch x, y := int32(f.bits), int32(g.bits); {
+	case x < y:
+		return -1
+	case x > y:
+		return +1
+	}
+	return 0
+}
+
+// Less reports whether f < g. NaR is less than any real number, as by the posit
+// standard.
+func (f Posit32) Less(g Posit32) bool {
+	return int32(f.bits) < int32(g.bits)
+}
+
+// Equal reports whether f == g. NaR is equal to NaR, as by the posit standard.
+func (f Posit32) Equal(g Posit32) bool {
+	return f.bits == g.bits
+}
+
+// TotalOrder reports whether f <= g; the order of Cmp is total.
+func (f Posit32) TotalOrder(g Posit32) bool {
+	return int32(f.bits) <= int32(g.bits)
+}
+
+// Class returns the class of f; NaR is a quiet NaN, and non-zero real numbers
+// are normal.
+func (f Posit32) Class() float.Class {
+	switch {
+	case f.IsNaR():
+		return float.QuietNaN
+	case f.IsZero():
+		return float.PositiveZero
+	}
+	return order.Signed(f.Signbit(), float.PositiveNormal)
+}
+
+// IsNaN reports whether f is NaR, as IsNaR.
+func (f Posit32) IsNaN() bool {
+	return f.IsNaR()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as posits have no
+// infinities.
+func (f Posit32) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as posits
+// have no subnormal numbers.
+func (f Posit32) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is a real number; i.e. not NaR.
+func (f Posit32) IsFinite() bool {
+	return !f.IsNaR()
+}
+
+// IsZero reports whether f is zero.
+func (f Posit32) IsZero() bool {
+	return f.bits == 0
+}
+
+// ### [ Posit64 ] #############################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. NaR
+// is less than any real number and equal to NaR, as by the posit standard; i.e.
+// posits are ordered as their binary representations in two's complement.
+func (f Posit64) Cmp(g Posit64) int {
+	switch x, y := int64(f.bits), int64(g.bits); {
+	case x < y:
+		return -1
+	case x > y:
+		return +1
+	}
+	return 0
+}
+
+// Less reports whether f < g. NaR is less than any real number, as by the posit
+// standard.
+func (f Posit64) Less(g Posit64) bool {
+	return int64(f.bits) < int64(g.bits)
+}
+
+// Equal reports whether f == g. NaR is equal to NaR, as by the posit standard.
+func (f Posit64) Equal(g Posit64) bool {
+	return f.bits == g.bits
+}
+
+// TotalOrder reports whether f <= g; the order of Cmp is total.
+func (f Posit64) TotalOrder(g Posit64) bool {
+	return int64(f.bits) <= int64(g.bits)
+}
+
+// Class returns the class of f; NaR is a quiet NaN, and non-zero real numbers
+// are normal.
+func (f Posit64) Class() float.Class {
+	switch {
+	case f.IsNaR():
+		return float.QuietNaN
+	case f.IsZero():
+		return float.PositiveZero
+	}
+	return order.Signed(f.Signbit(), float.PositiveNormal)
+}
+
+// IsNaN reports whether f is NaR, as IsNaR.
+func (f Posit64) IsNaN() bool {
+	return f.IsNaR()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as posits have no
+// infinities.
+func (f Posit64) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as posits
+// have no subnormal numbers.
+func (f Posit64) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is a real number; i.e. not NaR.
+func (f Posit64) IsFinite() bool {
+	return !f.IsNaR()
+}
+
+// IsZero reports whether f is zero.
+func (f Posit64) IsZero() bool {
+	return f.bits == 0
+}
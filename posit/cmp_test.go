@@ -0,0 +1,83 @@
+package posit
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestPosit8Cmp(t *testing.T) {
+	for i := 0; i < 1<<8; i++ {
+		f := NewPosit8FromBits(uint8(i))
+		x, xnar := f.Big()
+		want := float.PositiveNormal
+		switch {
+		case xnar:
+			want = float.QuietNaN
+		case x.Sign() == 0:
+			want = float.PositiveZero
+		case x.Sign() < 0:
+			want = float.NegativeNormal
+		}
+		if got := f.Class(); want != got {
+			t.Errorf("0x%02X: class mismatch; expected %v, got %v", f.Bits(), want, got)
+		}
+		if f.IsNaN() != want.IsNaN() || f.IsInf() || f.IsSubnormal() || f.IsFinite() != want.IsFinite() || f.IsZero() != want.IsZero() {
+			t.Errorf("0x%02X: predicate mismatch for class %v", f.Bits(), want)
+		}
+		for j := 0; j < 1<<8; j++ {
+			g := NewPosit8FromBits(uint8(j))
+			y, ynar := g.Big()
+			// NaR is less than any real number.
+			var want int
+			switch {
+			case xnar && ynar:
+				want = 0
+			case xnar:
+				want = -1
+			case ynar:
+				want = +1
+			default:
+				want = x.Cmp(y)
+			}
+			if got := f.Cmp(g); want != got {
+				t.Errorf("0x%02X.Cmp(0x%02X): mismatch; expected %d, got %d", f.Bits(), g.Bits(), want, got)
+			}
+			if f.Less(g) != (want < 0) || f.Equal(g) != (want == 0) || f.TotalOrder(g) != (want <= 0) {
+				t.Errorf("0x%02X, 0x%02X: comparison mismatch", f.Bits(), g.Bits())
+			}
+		}
+	}
+}
+
+func TestPositCmp(t *testing.T) {
+	// Numbers in increasing order; NaR, -maxpos, -1, -minpos, 0, minpos, 1 and
+	// maxpos.
+	p16 := []Posit16{Posit16NaR, Posit16MaxPos.Neg(), Posit16One.Neg(), Posit16MinPos.Neg(), Posit16Zero, Posit16MinPos, Posit16One, Posit16MaxPos}
+	p32 := []Posit32{Posit32NaR, Posit32MaxPos.Neg(), Posit32One.Neg(), Posit32MinPos.Neg(), Posit32Zero, Posit32MinPos, Posit32One, Posit32MaxPos}
+	p64 := []Posit64{Posit64NaR, Posit64MaxPos.Neg(), Posit64One.Neg(), Posit64MinPos.Neg(), Posit64Zero, Posit64MinPos, Posit64One, Posit64MaxPos}
+	classes := []float.Class{float.QuietNaN, float.NegativeNormal, float.NegativeNormal, float.NegativeNormal, float.PositiveZero, float.PositiveNormal, float.PositiveNormal, float.PositiveNormal}
+	for i := range classes {
+		if p16[i].Class() != classes[i] || p32[i].Class() != classes[i] || p64[i].Class() != classes[i] {
+			t.Errorf("%d: class mismatch; expected %v, got %v, %v and %v", i, classes[i], p16[i].Class(), p32[i].Class(), p64[i].Class())
+		}
+		for j := range classes {
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = +1
+			}
+			if p16[i].Cmp(p16[j]) != want || p16[i].Less(p16[j]) != (i < j) || p16[i].Equal(p16[j]) != (i == j) || p16[i].TotalOrder(p16[j]) != (i <= j) {
+				t.Errorf("posit16 %d, %d: comparison mismatch", i, j)
+			}
+			if p32[i].Cmp(p32[j]) != want || p32[i].Less(p32[j]) != (i < j) || p32[i].Equal(p32[j]) != (i == j) || p32[i].TotalOrder(p32[j]) != (i <= j) {
+				t.Errorf("posit32 %d, %d: comparison mismatch", i, j)
+			}
+			if p64[i].Cmp(p64[j]) != want || p64[i].Less(p64[j]) != (i < j) || p64[i].Equal(p64[j]) != (i == j) || p64[i].TotalOrder(p64[j]) != (i <= j) {
+				t.Errorf("posit64 %d, %d: comparison mismatch", i, j)
+			}
+		}
+	}
+}
@@ -0,0 +1,492 @@
+// Package posit implements encoding, decoding and arithmetic of posit numbers
+// (type III unums) of 8, 16, 32 and 64 bits, with two exponent bits as
+// specified by the 2022 Standard for Posit Arithmetic.
+//
+// A posit<n,2> number is encoded in n bits as, from the most significant bit:
+//
+//	1 bit:         sign
+//	2-(n-1) bits:  regime; a run of identical bits terminated by the
+//	               opposite bit
+//	0-2 bits:      exponent
+//	0-(n-5) bits:  fraction
+//
+// The value of a positive posit is 2^(4*k+exp) * 1.frac_2, where k is the
+// number of ones of the regime minus one, or minus the number of zeros of the
+// regime. Exponent bits cut off by the regime are zero. Negative posits are the
+// two's complement of the positive posit of the same magnitude. The pattern
+// 100...0 is Not-a-Real (NaR), and 000...0 is zero.
+//
+// Conversions and arithmetic operations round to nearest on the binary
+// representation, with ties to even. Non-zero values never round to zero and
+// finite values never round to NaR; values too small or too large in magnitude
+// are rounded to the smallest or largest positive posit (minpos or maxpos) of
+// the same sign. The IEEE 754 exception flags raised by conversions and
+// arithmetic are recorded in a Context.
+//
+// A quire is an accumulator holding exact sums of products of posits, which are
+// rounded once on conversion to a posit; e.g. to compute correctly rounded dot
+// products.
+//
+// https://posithub.org/docs/posit_standard-2.pdf
+package posit
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/rounding"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+const (
+	// es specifies the number of exponent bits.
+	es = 2
+	// exactPrec specifies a precision sufficient to hold the exact sum and
+	// product of any two posits of at most 64 bits.
+	exactPrec = 1024
+)
+
+// format is a posit format.
+type format struct {
+	// Number of bits.
+	n uint
+}
+
+// Posit formats.
+var (
+	// posit<8,2>
+	posit8 = format{n: 8}
+	// posit<16,2>
+	posit16 = format{n: 16}
+	// posit<32,2>
+	posit32 = format{n: 32}
+	// posit<64,2>
+	posit64 = format{n: 64}
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions and arithmetic performed in the
+// context. The zero value detects tininess after rounding and has no flags
+// raised.
+//
+// Posits neither overflow nor underflow; results rounded to minpos or maxpos
+// raise only the inexact exception, and the detection of tininess applies to
+// conversions to float32 and float64. NaR takes the role of NaN, and operations
+// on NaR raise no exception. A NaR result of real operands raises the invalid
+// operation exception, as for +-Inf converted to NaR, the square root of a
+// negative number and 0/0, except for the division of a non-zero number by
+// zero, which raises the division by zero exception.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// inexact records in ctx the inexact exception if acc is not exact, and
+// returns bits and acc.
+func (ctx *Context) inexact(bits uint64, acc big.Accuracy) (uint64, big.Accuracy) {
+	if acc != big.Exact {
+		ctx.Flags |= fenv.Inexact
+	}
+	return bits, acc
+}
+
+// signaling32 records in ctx the invalid operation exception if x is a
+// signaling NaN.
+func (ctx *Context) signaling32(x float32) {
+	if payload.Signaling32(x) {
+		ctx.Flags |= fenv.Invalid
+	}
+}
+
+// signaling64 records in ctx the invalid operation exception if x is a
+// signaling NaN.
+func (ctx *Context) signaling64(x float64) {
+	if math.IsNaN(x) && math.Float64bits(x)&0x0008000000000000 == 0 {
+		ctx.Flags |= fenv.Invalid
+	}
+}
+
+// fromFloat64 returns the binary representation of the posit nearest to x, and
+// the accuracy of the conversion. NaN is converted to NaR. The exception flags
+// raised by rounding are recorded in ctx.
+func (ctx *Context) fromFloat64(f format, x float64) (uint64, big.Accuracy) {
+	if math.IsNaN(x) {
+		return f.nar(), big.Exact
+	}
+	return ctx.encode(f, big.NewFloat(x))
+}
+
+// float32 returns the float32 value nearest to the posit x and the accuracy of
+// the conversion. NaR is converted to NaN. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) float32(x *big.Float, nar bool) (float32, big.Accuracy) {
+	if nar {
+		return float32(math.NaN()), big.Exact
+	}
+	y, acc := x.Float32()
+	if x.Sign() != 0 {
+		ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+	}
+	return y, acc
+}
+
+// float64 returns the float64 value nearest to the posit x and the accuracy of
+// the conversion. NaR is converted to NaN. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) float64(x *big.Float, nar bool) (float64, big.Accuracy) {
+	if nar {
+		return math.NaN(), big.Exact
+	}
+	y, acc := x.Float64()
+	if x.Sign() != 0 {
+		ctx.Flags |= rounding.Flags(x, acc, 53, -1022, 1023, big.ToNearestEven, ctx.Tininess)
+	}
+	return y, acc
+}
+
+// mask returns the mask of the n bits of the format.
+func (f format) mask() uint64 {
+	return ^uint64(0) >> (64 - f.n)
+}
+
+// nar returns the binary representation of NaR.
+func (f format) nar() uint64 {
+	return 1 << (f.n - 1)
+}
+
+// maxpos returns the binary representation of the largest positive posit.
+func (f format) maxpos() uint64 {
+	return f.nar() - 1
+}
+
+// maxScale returns the exponent of the largest positive posit, 2^(4*(n-2)).
+func (f format) maxScale() int {
+	return 4 * (int(f.n) - 2)
+}
+
+// neg returns the binary representation of the negation of the posit bits.
+func (f format) neg(bits uint64) uint64 {
+	return -bits & f.mask()
+}
+
+// abs returns the binary representation of the absolute value of the posit
+// bits, which is not NaR, and a boolean indicating whether bits is negative.
+func (f format) abs(bits uint64) (uint64, bool) {
+	if bits&f.nar() != 0 {
+		return f.neg(bits), true
+	}
+	return bits, false
+}
+
+// decode returns the value of the posit bits, and a boolean indicating whether
+// bits is NaR.
+func (f format) decode(bits uint64) (x *big.Float, nar bool) {
+	bits &= f.mask()
+	switch bits {
+	case 0:
+		return new(big.Float), false
+	case f.nar():
+		return new(big.Float), true
+	}
+	p, neg := f.abs(bits)
+	x = magnitude(new(big.Int).SetUint64(p), f.n)
+	if neg {
+		x.Neg(x)
+	}
+	return x, false
+}
+
+// magnitude returns the value of the positive n-bit posit p.
+func magnitude(p *big.Int, n uint) *big.Float {
+	// Regime.
+	i := int(n) - 2
+	r := p.Bit(i)
+	run := 0
+	for ; i >= 0 && p.Bit(i) == r; i-- {
+		run++
+	}
+	// Skip the terminating bit.
+	i--
+	k := -run
+	if r == 1 {
+		k = run - 1
+	}
+	// Exponent; bits cut off by the regime are zero.
+	exp := 0
+	for j := 0; j < es; j++ {
+		exp <<= 1
+		if i >= 0 {
+			exp |= int(p.Bit(i))
+			i--
+		}
+	}
+	// Fraction, with the hidden bit.
+	fracBits := i + 1
+	if fracBits < 0 {
+		fracBits = 0
+	}
+	mant := new(big.Int).SetBit(p, fracBits, 1)
+	mant.And(mant, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(fracBits+1)), big.NewInt(1)))
+	x := new(big.Float).SetPrec(n).SetInt(mant)
+	return x.SetMantExp(x, 4*k+exp-fracBits)
+}
+
+// encode returns the binary representation of the posit nearest to x, and the
+// accuracy of the conversion. +-Inf is converted to NaR. The exception flags
+// raised are recorded in ctx.
+func (ctx *Context) encode(f format, x *big.Float) (uint64, big.Accuracy) {
+	if x.IsInf() {
+		ctx.Flags |= fenv.Invalid
+		return f.nar(), big.Exact
+	}
+	return ctx.inexact(f.round(x, false))
+}
+
+// round returns the binary representation of the posit nearest to the finite
+// x, and the accuracy of the conversion. If sticky is set, x is the value of an
+// inexact result truncated toward zero, which lies strictly between x and the
+// next number of larger magnitude and the precision of x.
+func (f format) round(x *big.Float, sticky bool) (uint64, big.Accuracy) {
+	if x.Sign() == 0 {
+		return 0, big.Exact
+	}
+	neg := x.Signbit()
+	abs := new(big.Float).Abs(x)
+	// |x| = 1.frac_2 * 2^scale
+	scale := abs.MantExp(nil) - 1
+	var bits uint64
+	switch {
+	case scale >= f.maxScale():
+		bits = f.maxpos()
+	case scale < -f.maxScale():
+		// minpos
+		bits = 1
+	default:
+		bits = f.roundBits(abs, scale, sticky)
+	}
+	// Values never round to zero or NaR.
+	switch {
+	case bits == 0:
+		bits = 1
+	case bits > f.maxpos():
+		bits = f.maxpos()
+	}
+	y, _ := f.decode(bits)
+	acc := big.Exact
+	switch y.Cmp(abs) {
+	case -1:
+		acc = big.Below
+	case +1:
+		acc = big.Above
+	case 0:
+		if sticky {
+			acc = big.Below
+		}
+	}
+	if neg {
+		return f.neg(bits), -acc
+	}
+	return bits, acc
+}
+
+// roundBits returns the binary representation of the posit nearest to the
+// positive x = 1.frac_2 * 2^scale, within the range of the format, rounded to
+// nearest on the binary representation with ties to even.
+func (f format) roundBits(x *big.Float, scale int, sticky bool) uint64 {
+	k, exp := scale>>es, scale&(1<<es-1)
+	// Regime.
+	enc := new(big.Int)
+	var length int
+	if k >= 0 {
+		// k+1 ones followed by a zero.
+		enc.Lsh(big.NewInt(1), uint(k+1))
+		enc.Sub(enc, big.NewInt(1))
+		enc.Lsh(enc, 1)
+		length = k + 2
+	} else {
+		// -k zeros followed by a one.
+		enc.SetInt64(1)
+		length = -k + 1
+	}
+	// Exponent.
+	enc.Lsh(enc, es)
+	enc.Or(enc, big.NewInt(int64(exp)))
+	length += es
+	// Fraction, without the hidden bit.
+	prec := int(x.MinPrec())
+	mant, _ := new(big.Float).SetMantExp(x, prec-1-scale).Int(nil)
+	mant.SetBit(mant, prec-1, 0)
+	enc.Lsh(enc, uint(prec-1))
+	enc.Or(enc, mant)
+	length += prec - 1
+
+	// Round to n-1 bits; ties to even.
+	if length <= int(f.n)-1 {
+		return enc.Lsh(enc, uint(int(f.n)-1-length)).Uint64()
+	}
+	s := uint(length - (int(f.n) - 1))
+	q := new(big.Int).Rsh(enc, s)
+	rem := enc.Sub(enc, new(big.Int).Lsh(q, s))
+	half := new(big.Int).Lsh(big.NewInt(1), s-1)
+	c := rem.Cmp(half)
+	if c > 0 || c == 0 && (sticky || q.Bit(0) == 1) {
+		q.Add(q, big.NewInt(1))
+	}
+	return q.Uint64()
+}
+
+// text converts the posit bits to a string, as for strconv.FormatFloat. The
+// special precision -1 uses the smallest number of digits necessary to convert
+// back to bits. NaR is represented as "NaR".
+func (f format) text(bits uint64, fmt byte, prec int) string {
+	x, nar := f.decode(bits)
+	if nar {
+		return "NaR"
+	}
+	if x.Sign() == 0 {
+		return strconv.FormatBigRange(x, x, x, true, fmt, prec)
+	}
+	// The values rounding to the posit p lie between the posits of n+1 bits
+	// 2p-1 and 2p+1, ties to even.
+	p, neg := f.abs(bits & f.mask())
+	mid := new(big.Int).Lsh(new(big.Int).SetUint64(p), 1)
+	lower := magnitude(new(big.Int).Sub(mid, big.NewInt(1)), f.n+1)
+	upper := magnitude(new(big.Int).Add(mid, big.NewInt(1)), f.n+1)
+	if neg {
+		lower.Neg(lower)
+		upper.Neg(upper)
+	}
+	inclusive := p&1 == 0
+	return strconv.FormatBigRange(x, lower, upper, inclusive, fmt, prec)
+}
+
+// ### [ Arithmetic ] ##########################################################
+
+// add returns the sum a+b of the posits a and b, and the accuracy of the
+// result. The exception flags raised are recorded in ctx.
+func (ctx *Context) add(f format, a, b uint64) (uint64, big.Accuracy) {
+	x, nar1 := f.decode(a)
+	y, nar2 := f.decode(b)
+	if nar1 || nar2 {
+		return f.nar(), big.Exact
+	}
+	return ctx.inexact(f.round(new(big.Float).SetPrec(exactPrec).Add(x, y), false))
+}
+
+// mul returns the product a*b of the posits a and b, and the accuracy of the
+// result. The exception flags raised are recorded in ctx.
+func (ctx *Context) mul(f format, a, b uint64) (uint64, big.Accuracy) {
+	x, nar1 := f.decode(a)
+	y, nar2 := f.decode(b)
+	if nar1 || nar2 {
+		return f.nar(), big.Exact
+	}
+	return ctx.inexact(f.round(new(big.Float).SetPrec(exactPrec).Mul(x, y), false))
+}
+
+// div returns the quotient a/b of the posits a and b, and the accuracy of the
+// result. Division by zero is NaR. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) div(f format, a, b uint64) (uint64, big.Accuracy) {
+	x, nar1 := f.decode(a)
+	y, nar2 := f.decode(b)
+	switch {
+	case nar1 || nar2:
+		return f.nar(), big.Exact
+	case y.Sign() == 0:
+		if x.Sign() == 0 {
+			ctx.Flags |= fenv.Invalid
+		} else {
+			ctx.Flags |= fenv.DivByZero
+		}
+		return f.nar(), big.Exact
+	}
+	z := new(big.Float).SetPrec(exactPrec).SetMode(big.ToZero).Quo(x, y)
+	return ctx.inexact(f.round(z, z.Acc() != big.Exact))
+}
+
+// sqrt returns the square root of the posit a, and the accuracy of the result.
+// The square root of a negative number is NaR. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) sqrt(f format, a uint64) (uint64, big.Accuracy) {
+	x, nar := f.decode(a)
+	switch {
+	case nar:
+		return f.nar(), big.Exact
+	case x.Sign() < 0:
+		ctx.Flags |= fenv.Invalid
+		return f.nar(), big.Exact
+	case x.Sign() == 0:
+		return 0, big.Exact
+	}
+	// The accuracy of big.Float.Sqrt is undefined; the square root is inexact
+	// if its square differs from x, and it is then far from a tie as x has
+	// few bits.
+	z := new(big.Float).SetPrec(exactPrec).SetMode(big.ToZero).Sqrt(x)
+	sq := new(big.Float).SetPrec(2*exactPrec).Mul(z, z)
+	bits, _ := f.round(z, sq.Cmp(x) != 0)
+	y, _ := f.decode(bits)
+	switch sq.SetPrec(2*exactPrec).Mul(y, y).Cmp(x) {
+	case -1:
+		return ctx.inexact(bits, big.Below)
+	case +1:
+		return ctx.inexact(bits, big.Above)
+	}
+	return bits, big.Exact
+}
+
+// ### [ Quire ] ###############################################################
+
+// quire is an exact accumulator of sums of products of posits.
+type quire struct {
+	// Sum in units of the square of minpos, 2^(-8*(n-2)).
+	sum big.Int
+	// NaR.
+	nar bool
+}
+
+// mulAdd adds the product a*b of the posits a and b to q, or subtracts it if
+// sub is set.
+func (f format) mulAdd(q *quire, a, b uint64, sub bool) {
+	x, nar1 := f.decode(a)
+	y, nar2 := f.decode(b)
+	if nar1 || nar2 {
+		q.nar = true
+	}
+	if q.nar {
+		return
+	}
+	z := new(big.Float).SetPrec(exactPrec).Mul(x, y)
+	z.SetMantExp(z, 2*f.maxScale())
+	v, _ := z.Int(nil)
+	if sub {
+		q.sum.Sub(&q.sum, v)
+	} else {
+		q.sum.Add(&q.sum, v)
+	}
+}
+
+// quireBig returns the exact value of q, and a boolean indicating whether q is
+// NaR.
+func (f format) quireBig(q *quire) (x *big.Float, nar bool) {
+	if q.nar {
+		return new(big.Float), true
+	}
+	x = new(big.Float).SetInt(&q.sum)
+	return x.SetMantExp(x, -2*f.maxScale()), false
+}
+
+// quireRound returns the binary representation of the posit nearest to the
+// value of q, and the accuracy of the conversion. The exception flags raised
+// are recorded in ctx.
+func (ctx *Context) quireRound(f format, q *quire) (uint64, big.Accuracy) {
+	x, nar := f.quireBig(q)
+	if nar {
+		return f.nar(), big.Exact
+	}
+	return ctx.inexact(f.round(x, false))
+}
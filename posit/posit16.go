@@ -0,0 +1,278 @@
+package posit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Special numbers of the posit<16,2> format.
+var (
+	// Not-a-Real
+	Posit16NaR = Posit16{bits: 0x8000}
+	// zero
+	Posit16Zero = Posit16{bits: 0x0000}
+	// +1
+	Posit16One = Posit16{bits: 0x4000}
+	// largest positive posit; 2^56
+	Posit16MaxPos = Posit16{bits: 0x7FFF}
+	// smallest positive posit; 2^-56
+	Posit16MinPos = Posit16{bits: 0x0001}
+)
+
+// Posit16 is a posit<16,2> number.
+type Posit16 struct {
+	// Sign, regime, exponent and fraction.
+	bits uint16
+}
+
+// NewPosit16FromBits returns the posit corresponding to the posit<16,2> binary
+// representation.
+func NewPosit16FromBits(bits uint16) Posit16 {
+	return Posit16{bits: bits}
+}
+
+// NewPosit16FromFloat32 returns the nearest posit<16,2> number for x and the
+// accuracy of the conversion, as for NewPosit16FromBig. NaN is converted to
+// NaR.
+func NewPosit16FromFloat32(x float32) (Posit16, big.Accuracy) {
+	return new(Context).NewPosit16FromFloat32(x)
+}
+
+// NewPosit16FromFloat32 returns the nearest posit<16,2> number for x and the
+// accuracy of the conversion, as for NewPosit16FromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit16FromFloat32(x float32) (Posit16, big.Accuracy) {
+	ctx.signaling32(x)
+	bits, acc := ctx.fromFloat64(posit16, float64(x))
+	return Posit16{bits: uint16(bits)}, acc
+}
+
+// NewPosit16FromFloat64 returns the nearest posit<16,2> number for x and the
+// accuracy of the conversion, as for NewPosit16FromBig. NaN is converted to
+// NaR.
+func NewPosit16FromFloat64(x float64) (Posit16, big.Accuracy) {
+	return new(Context).NewPosit16FromFloat64(x)
+}
+
+// NewPosit16FromFloat64 returns the nearest posit<16,2> number for x and the
+// accuracy of the conversion, as for NewPosit16FromFloat64. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit16FromFloat64(x float64) (Posit16, big.Accuracy) {
+	ctx.signaling64(x)
+	bits, acc := ctx.fromFloat64(posit16, x)
+	return Posit16{bits: uint16(bits)}, acc
+}
+
+// NewPosit16FromBig returns the nearest posit<16,2> number for x and the
+// accuracy of the conversion, rounded to nearest with ties to even. Non-zero
+// values are never rounded to zero, and +-Inf is converted to NaR.
+func NewPosit16FromBig(x *big.Float) (Posit16, big.Accuracy) {
+	return new(Context).NewPosit16FromBig(x)
+}
+
+// NewPosit16FromBig returns the nearest posit<16,2> number for x and the
+// accuracy of the conversion, as for NewPosit16FromBig. The exception flags
+// raised are recorded in ctx.
+func (ctx *Context) NewPosit16FromBig(x *big.Float) (Posit16, big.Accuracy) {
+	bits, acc := ctx.encode(posit16, x)
+	return Posit16{bits: uint16(bits)}, acc
+}
+
+// Bits returns the posit<16,2> binary representation of f.
+func (f Posit16) Bits() uint16 {
+	return f.bits
+}
+
+// Float32 returns the float32 value of f. The conversion is always exact. NaR is
+// converted to NaN.
+func (f Posit16) Float32() (float32, big.Accuracy) {
+	return new(Context).Posit16Float32(f)
+}
+
+// Posit16Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit16Float32(f Posit16) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value of f. The conversion is always exact. NaR
+// is converted to NaN.
+func (f Posit16) Float64() (float64, big.Accuracy) {
+	return new(Context).Posit16Float64(f)
+}
+
+// Posit16Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit16Float64(f Posit16) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f
+// and a boolean indicating whether f is NaR.
+func (f Posit16) Big() (x *big.Float, nar bool) {
+	return posit16.decode(uint64(f.bits))
+}
+
+// IsNaR reports whether f is Not-a-Real.
+func (f Posit16) IsNaR() bool {
+	return f == Posit16NaR
+}
+
+// Signbit reports whether f is negative or NaR.
+func (f Posit16) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x8000 != 0
+}
+
+// Neg returns the negation -f. The negation of NaR is NaR.
+func (f Posit16) Neg() Posit16 {
+	return Posit16{bits: uint16(posit16.neg(uint64(f.bits)))}
+}
+
+// Add returns the sum f+g, rounded to nearest, and the accuracy of the result.
+func (f Posit16) Add(g Posit16) (Posit16, big.Accuracy) {
+	return new(Context).Posit16Add(f, g)
+}
+
+// Posit16Add returns the sum f+g, rounded to nearest, and the accuracy of the
+// result, as for f.Add. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit16Add(f, g Posit16) (Posit16, big.Accuracy) {
+	bits, acc := ctx.add(posit16, uint64(f.bits), uint64(g.bits))
+	return Posit16{bits: uint16(bits)}, acc
+}
+
+// Sub returns the difference f-g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit16) Sub(g Posit16) (Posit16, big.Accuracy) {
+	return f.Add(g.Neg())
+}
+
+// Posit16Sub returns the difference f-g, rounded to nearest, and the accuracy
+// of the result, as for f.Sub. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit16Sub(f, g Posit16) (Posit16, big.Accuracy) {
+	return ctx.Posit16Add(f, g.Neg())
+}
+
+// Mul returns the product f*g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit16) Mul(g Posit16) (Posit16, big.Accuracy) {
+	return new(Context).Posit16Mul(f, g)
+}
+
+// Posit16Mul returns the product f*g, rounded to nearest, and the accuracy of
+// the result, as for f.Mul. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit16Mul(f, g Posit16) (Posit16, big.Accuracy) {
+	bits, acc := ctx.mul(posit16, uint64(f.bits), uint64(g.bits))
+	return Posit16{bits: uint16(bits)}, acc
+}
+
+// Div returns the quotient f/g, rounded to nearest, and the accuracy of the
+// result. Division by zero is NaR.
+func (f Posit16) Div(g Posit16) (Posit16, big.Accuracy) {
+	return new(Context).Posit16Div(f, g)
+}
+
+// Posit16Div returns the quotient f/g, rounded to nearest, and the accuracy of
+// the result, as for f.Div. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit16Div(f, g Posit16) (Posit16, big.Accuracy) {
+	bits, acc := ctx.div(posit16, uint64(f.bits), uint64(g.bits))
+	return Posit16{bits: uint16(bits)}, acc
+}
+
+// Sqrt returns the square root of f, rounded to nearest, and the accuracy of
+// the result. The square root of a negative number is NaR.
+func (f Posit16) Sqrt() (Posit16, big.Accuracy) {
+	return new(Context).Posit16Sqrt(f)
+}
+
+// Posit16Sqrt returns the square root of f, rounded to nearest, and the
+// accuracy of the result, as for f.Sqrt. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) Posit16Sqrt(f Posit16) (Posit16, big.Accuracy) {
+	bits, acc := ctx.sqrt(posit16, uint64(f.bits))
+	return Posit16{bits: uint16(bits)}, acc
+}
+
+// Text converts the posit f to a string, according to the format fmt and
+// precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e', 'E', 'f',
+// 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest number of
+// digits necessary for NewPosit16FromBig to return f. NaR is represented as
+// "NaR".
+func (f Posit16) Text(fmt byte, prec int) string {
+	return posit16.text(uint64(f.bits), fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which converts back
+// to f, as for f.Text('g', -1).
+func (f Posit16) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Posit16) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// Quire16 is a quire of the posit<16,2> format; an accumulator of exact sums of
+// products of posits. The zero value is a quire holding zero.
+type Quire16 struct {
+	q quire
+}
+
+// MulAdd adds the exact product a*b to q. If a or b is NaR, q becomes NaR.
+func (q *Quire16) MulAdd(a, b Posit16) {
+	posit16.mulAdd(&q.q, uint64(a.bits), uint64(b.bits), false)
+}
+
+// MulSub subtracts the exact product a*b from q. If a or b is NaR, q becomes
+// NaR.
+func (q *Quire16) MulSub(a, b Posit16) {
+	posit16.mulAdd(&q.q, uint64(a.bits), uint64(b.bits), true)
+}
+
+// Add adds a to q. If a is NaR, q becomes NaR.
+func (q *Quire16) Add(a Posit16) {
+	q.MulAdd(a, Posit16One)
+}
+
+// Sub subtracts a from q. If a is NaR, q becomes NaR.
+func (q *Quire16) Sub(a Posit16) {
+	q.MulSub(a, Posit16One)
+}
+
+// Reset sets q to zero.
+func (q *Quire16) Reset() {
+	q.q = quire{}
+}
+
+// IsNaR reports whether q is Not-a-Real.
+func (q *Quire16) IsNaR() bool {
+	return q.q.nar
+}
+
+// Big returns the exact value of q and a boolean indicating whether q is NaR.
+func (q *Quire16) Big() (x *big.Float, nar bool) {
+	return posit16.quireBig(&q.q)
+}
+
+// Posit returns the posit nearest to the value of q, rounded to nearest, and the
+// accuracy of the conversion.
+func (q *Quire16) Posit() (Posit16, big.Accuracy) {
+	return new(Context).Quire16Posit(q)
+}
+
+// Quire16Posit returns the posit nearest to the value of q, rounded to
+// nearest, and the accuracy of the conversion, as for q.Posit. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) Quire16Posit(q *Quire16) (Posit16, big.Accuracy) {
+	bits, acc := ctx.quireRound(posit16, &q.q)
+	return Posit16{bits: uint16(bits)}, acc
+}
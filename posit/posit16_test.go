@@ -0,0 +1,118 @@
+package posit
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewPosit16FromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint16
+		acc  big.Accuracy
+	}{
+		{in: 0, want: 0x0000, acc: big.Exact},
+		{in: math.NaN(), want: 0x8000, acc: big.Exact},
+		{in: 1, want: 0x4000, acc: big.Exact},
+		{in: -1, want: 0xC000, acc: big.Exact},
+		// 0 10 01 10010010001
+		{in: math.Pi, want: 0x4C91, acc: big.Above},
+		// 0 01 10 01010101011
+		{in: 1. / 3, want: 0x32AB, acc: big.Above},
+		{in: 0x1p56, want: 0x7FFF, acc: big.Exact},
+		{in: 0x1p-56, want: 0x0001, acc: big.Exact},
+		{in: 0x1p57, want: 0x7FFF, acc: big.Below},
+		{in: -0x1p-57, want: 0xFFFF, acc: big.Below},
+	}
+	for _, g := range golden {
+		f, acc := NewPosit16FromFloat64(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%04X, got 0x%04X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestPosit16RoundTrip(t *testing.T) {
+	for i := 0; i < 1<<16; i++ {
+		f := NewPosit16FromBits(uint16(i))
+		x, nar := f.Big()
+		if nar {
+			continue
+		}
+		if got, acc := NewPosit16FromBig(x); got != f || acc != big.Exact {
+			t.Errorf("0x%04X: round-trip mismatch; got 0x%04X (%v)", f.Bits(), got.Bits(), acc)
+		}
+		y, _ := f.Float32()
+		if got, acc := NewPosit16FromFloat32(y); got != f || acc != big.Exact {
+			t.Errorf("0x%04X: float32 round-trip mismatch; got 0x%04X (%v)", f.Bits(), got.Bits(), acc)
+		}
+		s := f.String()
+		z, _, err := big.ParseFloat(s, 10, 100, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("0x%04X: unable to parse %q; %v", f.Bits(), s, err)
+			continue
+		}
+		if got, _ := NewPosit16FromBig(z); got != f {
+			t.Errorf("0x%04X: text round-trip mismatch; %q is 0x%04X", f.Bits(), s, got.Bits())
+		}
+	}
+}
+
+func TestPosit16Arith(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		f, g := NewPosit16FromBits(uint16(r.Uint32())), NewPosit16FromBits(uint16(r.Uint32()))
+		x, nar1 := f.Big()
+		y, nar2 := g.Big()
+		nar := nar1 || nar2
+		z := new(big.Float).SetPrec(exactPrec)
+		got, acc := f.Add(g)
+		checkRounded(t, posit16, "+", uint64(f.bits), uint64(g.bits), uint64(got.bits), acc, z.Add(x, y), nar)
+		got, acc = f.Mul(g)
+		checkRounded(t, posit16, "*", uint64(f.bits), uint64(g.bits), uint64(got.bits), acc, z.Mul(x, y), nar)
+		got, acc = f.Div(g)
+		if y.Sign() != 0 {
+			z.Quo(x, y)
+		}
+		checkRounded(t, posit16, "/", uint64(f.bits), uint64(g.bits), uint64(got.bits), acc, z, nar || y.Sign() == 0)
+		got, acc = f.Sqrt()
+		if x.Sign() >= 0 {
+			z.Sqrt(x)
+		}
+		checkRounded(t, posit16, "sqrt", uint64(f.bits), 0, uint64(got.bits), acc, z, nar1 || x.Sign() < 0)
+	}
+}
+
+func TestQuire16(t *testing.T) {
+	var q Quire16
+	if got, acc := q.Posit(); got != Posit16Zero || acc != big.Exact {
+		t.Errorf("zero quire mismatch; got 0x%04X (%v)", got.Bits(), acc)
+	}
+	// The products of minpos and maxpos are held exactly.
+	q.MulAdd(Posit16MaxPos, Posit16MaxPos)
+	q.MulAdd(Posit16MinPos, Posit16MinPos)
+	q.MulSub(Posit16MaxPos, Posit16MaxPos)
+	x, nar := q.Big()
+	if want := new(big.Float).SetMantExp(big.NewFloat(1), -112); nar || x.Cmp(want) != 0 {
+		t.Errorf("quire mismatch; expected %v, got %v", want, x)
+	}
+	if got, acc := q.Posit(); got != Posit16MinPos || acc != big.Above {
+		t.Errorf("posit mismatch; expected 0x0001, got 0x%04X (%v)", got.Bits(), acc)
+	}
+	q.Add(Posit16NaR)
+	if !q.IsNaR() {
+		t.Errorf("NaR mismatch; expected NaR quire")
+	}
+	if got, acc := q.Posit(); got != Posit16NaR || acc != big.Exact {
+		t.Errorf("posit mismatch; expected NaR, got 0x%04X (%v)", got.Bits(), acc)
+	}
+	q.Reset()
+	if q.IsNaR() {
+		t.Errorf("NaR mismatch; expected zero quire")
+	}
+}
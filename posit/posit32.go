@@ -0,0 +1,278 @@
+package posit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Special numbers of the posit<32,2> format.
+var (
+	// Not-a-Real
+	Posit32NaR = Posit32{bits: 0x80000000}
+	// zero
+	Posit32Zero = Posit32{bits: 0x00000000}
+	// +1
+	Posit32One = Posit32{bits: 0x40000000}
+	// largest positive posit; 2^120
+	Posit32MaxPos = Posit32{bits: 0x7FFFFFFF}
+	// smallest positive posit; 2^-120
+	Posit32MinPos = Posit32{bits: 0x00000001}
+)
+
+// Posit32 is a posit<32,2> number.
+type Posit32 struct {
+	// Sign, regime, exponent and fraction.
+	bits uint32
+}
+
+// NewPosit32FromBits returns the posit corresponding to the posit<32,2> binary
+// representation.
+func NewPosit32FromBits(bits uint32) Posit32 {
+	return Posit32{bits: bits}
+}
+
+// NewPosit32FromFloat32 returns the nearest posit<32,2> number for x and the
+// accuracy of the conversion, as for NewPosit32FromBig. NaN is converted to
+// NaR.
+func NewPosit32FromFloat32(x float32) (Posit32, big.Accuracy) {
+	return new(Context).NewPosit32FromFloat32(x)
+}
+
+// NewPosit32FromFloat32 returns the nearest posit<32,2> number for x and the
+// accuracy of the conversion, as for NewPosit32FromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit32FromFloat32(x float32) (Posit32, big.Accuracy) {
+	ctx.signaling32(x)
+	bits, acc := ctx.fromFloat64(posit32, float64(x))
+	return Posit32{bits: uint32(bits)}, acc
+}
+
+// NewPosit32FromFloat64 returns the nearest posit<32,2> number for x and the
+// accuracy of the conversion, as for NewPosit32FromBig. NaN is converted to
+// NaR.
+func NewPosit32FromFloat64(x float64) (Posit32, big.Accuracy) {
+	return new(Context).NewPosit32FromFloat64(x)
+}
+
+// NewPosit32FromFloat64 returns the nearest posit<32,2> number for x and the
+// accuracy of the conversion, as for NewPosit32FromFloat64. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit32FromFloat64(x float64) (Posit32, big.Accuracy) {
+	ctx.signaling64(x)
+	bits, acc := ctx.fromFloat64(posit32, x)
+	return Posit32{bits: uint32(bits)}, acc
+}
+
+// NewPosit32FromBig returns the nearest posit<32,2> number for x and the
+// accuracy of the conversion, rounded to nearest with ties to even. Non-zero
+// values are never rounded to zero, and +-Inf is converted to NaR.
+func NewPosit32FromBig(x *big.Float) (Posit32, big.Accuracy) {
+	return new(Context).NewPosit32FromBig(x)
+}
+
+// NewPosit32FromBig returns the nearest posit<32,2> number for x and the
+// accuracy of the conversion, as for NewPosit32FromBig. The exception flags
+// raised are recorded in ctx.
+func (ctx *Context) NewPosit32FromBig(x *big.Float) (Posit32, big.Accuracy) {
+	bits, acc := ctx.encode(posit32, x)
+	return Posit32{bits: uint32(bits)}, acc
+}
+
+// Bits returns the posit<32,2> binary representation of f.
+func (f Posit32) Bits() uint32 {
+	return f.bits
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion. NaR is converted to NaN.
+func (f Posit32) Float32() (float32, big.Accuracy) {
+	return new(Context).Posit32Float32(f)
+}
+
+// Posit32Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit32Float32(f Posit32) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value of f. The conversion is always exact. NaR
+// is converted to NaN.
+func (f Posit32) Float64() (float64, big.Accuracy) {
+	return new(Context).Posit32Float64(f)
+}
+
+// Posit32Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit32Float64(f Posit32) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f
+// and a boolean indicating whether f is NaR.
+func (f Posit32) Big() (x *big.Float, nar bool) {
+	return posit32.decode(uint64(f.bits))
+}
+
+// IsNaR reports whether f is Not-a-Real.
+func (f Posit32) IsNaR() bool {
+	return f == Posit32NaR
+}
+
+// Signbit reports whether f is negative or NaR.
+func (f Posit32) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x80000000 != 0
+}
+
+// Neg returns the negation -f. The negation of NaR is NaR.
+func (f Posit32) Neg() Posit32 {
+	return Posit32{bits: uint32(posit32.neg(uint64(f.bits)))}
+}
+
+// Add returns the sum f+g, rounded to nearest, and the accuracy of the result.
+func (f Posit32) Add(g Posit32) (Posit32, big.Accuracy) {
+	return new(Context).Posit32Add(f, g)
+}
+
+// Posit32Add returns the sum f+g, rounded to nearest, and the accuracy of the
+// result, as for f.Add. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit32Add(f, g Posit32) (Posit32, big.Accuracy) {
+	bits, acc := ctx.add(posit32, uint64(f.bits), uint64(g.bits))
+	return Posit32{bits: uint32(bits)}, acc
+}
+
+// Sub returns the difference f-g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit32) Sub(g Posit32) (Posit32, big.Accuracy) {
+	return f.Add(g.Neg())
+}
+
+// Posit32Sub returns the difference f-g, rounded to nearest, and the accuracy
+// of the result, as for f.Sub. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit32Sub(f, g Posit32) (Posit32, big.Accuracy) {
+	return ctx.Posit32Add(f, g.Neg())
+}
+
+// Mul returns the product f*g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit32) Mul(g Posit32) (Posit32, big.Accuracy) {
+	return new(Context).Posit32Mul(f, g)
+}
+
+// Posit32Mul returns the product f*g, rounded to nearest, and the accuracy of
+// the result, as for f.Mul. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit32Mul(f, g Posit32) (Posit32, big.Accuracy) {
+	bits, acc := ctx.mul(posit32, uint64(f.bits), uint64(g.bits))
+	return Posit32{bits: uint32(bits)}, acc
+}
+
+// Div returns the quotient f/g, rounded to nearest, and the accuracy of the
+// result. Division by zero is NaR.
+func (f Posit32) Div(g Posit32) (Posit32, big.Accuracy) {
+	return new(Context).Posit32Div(f, g)
+}
+
+// Posit32Div returns the quotient f/g, rounded to nearest, and the accuracy of
+// the result, as for f.Div. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit32Div(f, g Posit32) (Posit32, big.Accuracy) {
+	bits, acc := ctx.div(posit32, uint64(f.bits), uint64(g.bits))
+	return Posit32{bits: uint32(bits)}, acc
+}
+
+// Sqrt returns the square root of f, rounded to nearest, and the accuracy of
+// the result. The square root of a negative number is NaR.
+func (f Posit32) Sqrt() (Posit32, big.Accuracy) {
+	return new(Context).Posit32Sqrt(f)
+}
+
+// Posit32Sqrt returns the square root of f, rounded to nearest, and the
+// accuracy of the result, as for f.Sqrt. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) Posit32Sqrt(f Posit32) (Posit32, big.Accuracy) {
+	bits, acc := ctx.sqrt(posit32, uint64(f.bits))
+	return Posit32{bits: uint32(bits)}, acc
+}
+
+// Text converts the posit f to a string, according to the format fmt and
+// precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e', 'E', 'f',
+// 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest number of
+// digits necessary for NewPosit32FromBig to return f. NaR is represented as
+// "NaR".
+func (f Posit32) Text(fmt byte, prec int) string {
+	return posit32.text(uint64(f.bits), fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which converts back
+// to f, as for f.Text('g', -1).
+func (f Posit32) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Posit32) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// Quire32 is a quire of the posit<32,2> format; an accumulator of exact sums of
+// products of posits. The zero value is a quire holding zero.
+type Quire32 struct {
+	q quire
+}
+
+// MulAdd adds the exact product a*b to q. If a or b is NaR, q becomes NaR.
+func (q *Quire32) MulAdd(a, b Posit32) {
+	posit32.mulAdd(&q.q, uint64(a.bits), uint64(b.bits), false)
+}
+
+// MulSub subtracts the exact product a*b from q. If a or b is NaR, q becomes
+// NaR.
+func (q *Quire32) MulSub(a, b Posit32) {
+	posit32.mulAdd(&q.q, uint64(a.bits), uint64(b.bits), true)
+}
+
+// Add adds a to q. If a is NaR, q becomes NaR.
+func (q *Quire32) Add(a Posit32) {
+	q.MulAdd(a, Posit32One)
+}
+
+// Sub subtracts a from q. If a is NaR, q becomes NaR.
+func (q *Quire32) Sub(a Posit32) {
+	q.MulSub(a, Posit32One)
+}
+
+// Reset sets q to zero.
+func (q *Quire32) Reset() {
+	q.q = quire{}
+}
+
+// IsNaR reports whether q is Not-a-Real.
+func (q *Quire32) IsNaR() bool {
+	return q.q.nar
+}
+
+// Big returns the exact value of q and a boolean indicating whether q is NaR.
+func (q *Quire32) Big() (x *big.Float, nar bool) {
+	return posit32.quireBig(&q.q)
+}
+
+// Posit returns the posit nearest to the value of q, rounded to nearest, and the
+// accuracy of the conversion.
+func (q *Quire32) Posit() (Posit32, big.Accuracy) {
+	return new(Context).Quire32Posit(q)
+}
+
+// Quire32Posit returns the posit nearest to the value of q, rounded to
+// nearest, and the accuracy of the conversion, as for q.Posit. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) Quire32Posit(q *Quire32) (Posit32, big.Accuracy) {
+	bits, acc := ctx.quireRound(posit32, &q.q)
+	return Posit32{bits: uint32(bits)}, acc
+}
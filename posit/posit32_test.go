@@ -0,0 +1,136 @@
+package posit
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestNewPosit32FromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint32
+		acc  big.Accuracy
+	}{
+		{in: 0, want: 0x00000000, acc: big.Exact},
+		{in: math.Inf(1), want: 0x80000000, acc: big.Exact},
+		{in: 1, want: 0x40000000, acc: big.Exact},
+		{in: -2, want: 0xB8000000, acc: big.Exact},
+		// 0 10 01 100100100001111110110101010
+		{in: math.Pi, want: 0x4C90FDAA, acc: big.Below},
+		{in: -math.Pi, want: 0xB36F0256, acc: big.Above},
+		// 1 + 2^-27 = 1 (tie, round to even)
+		{in: 1 + 0x1p-28, want: 0x40000000, acc: big.Below},
+		{in: 1 + 0x3p-28, want: 0x40000002, acc: big.Above},
+		{in: 0x1p120, want: 0x7FFFFFFF, acc: big.Exact},
+		{in: math.MaxFloat64, want: 0x7FFFFFFF, acc: big.Below},
+		{in: 0x1p-120, want: 0x00000001, acc: big.Exact},
+	}
+	for _, g := range golden {
+		f, acc := NewPosit32FromFloat64(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+		if f.IsNaR() {
+			continue
+		}
+		// Every posit<32,2> number is a float64.
+		x, _ := f.Big()
+		if y, acc := f.Float64(); y != f64(x) || acc != big.Exact {
+			t.Errorf("0x%08X: float64 mismatch; expected %v, got %v (%v)", f.Bits(), x, y, acc)
+		}
+	}
+}
+
+func TestPosit32Arith(t *testing.T) {
+	one, third := Posit32One, NewPosit32FromBits(0x32AAAAAB)
+	golden := []struct {
+		op   string
+		got  Posit32
+		acc  big.Accuracy
+		want uint32
+		wacc big.Accuracy
+	}{}
+	add := func(op string, got Posit32, acc big.Accuracy, want uint32, wacc big.Accuracy) {
+		golden = append(golden, struct {
+			op   string
+			got  Posit32
+			acc  big.Accuracy
+			want uint32
+			wacc big.Accuracy
+		}{op, got, acc, want, wacc})
+	}
+	got, acc := one.Div(NewPosit32FromBits(0x4C000000))
+	add("1/3", got, acc, 0x32AAAAAB, big.Above)
+	got, acc = third.Add(third)
+	add("1/3+1/3", got, acc, 0x3AAAAAAB, big.Exact)
+	got, acc = third.Sub(third)
+	add("1/3-1/3", got, acc, 0x00000000, big.Exact)
+	got, acc = NewPosit32FromBits(0x48000000).Sqrt()
+	// sqrt(2) = 0 10 00 011010100000100111100110011|0011...
+	add("sqrt(2)", got, acc, 0x43504F33, big.Below)
+	got, acc = one.Div(Posit32Zero)
+	add("1/0", got, acc, 0x80000000, big.Exact)
+	got, acc = Posit32MaxPos.Mul(Posit32MaxPos)
+	add("maxpos*maxpos", got, acc, 0x7FFFFFFF, big.Below)
+	got, acc = Posit32MinPos.Mul(Posit32MinPos.Neg())
+	add("minpos*-minpos", got, acc, 0xFFFFFFFF, big.Below)
+	got, acc = Posit32NaR.Add(one)
+	add("NaR+1", got, acc, 0x80000000, big.Exact)
+	for _, g := range golden {
+		if g.got.Bits() != g.want || g.acc != g.wacc {
+			t.Errorf("%s: result mismatch; expected 0x%08X (%v), got 0x%08X (%v)", g.op, g.want, g.wacc, g.got.Bits(), g.acc)
+		}
+	}
+}
+
+func TestQuire32(t *testing.T) {
+	// 2^100 + 1 - 2^100 = 1 using a quire, but 0 using posit arithmetic.
+	huge := NewPosit32FromBits(0x7FFFFFE0)
+	x, _ := huge.Big()
+	if f64(x) != 0x1p100 {
+		t.Fatalf("0x7FFFFFE0: expected 2^100, got %v", x)
+	}
+	var q Quire32
+	q.Add(huge)
+	q.Add(Posit32One)
+	q.Sub(huge)
+	if got, _ := q.Posit(); got != Posit32One {
+		t.Errorf("quire mismatch; expected 0x40000000, got 0x%08X", got.Bits())
+	}
+	sum, _ := huge.Add(Posit32One)
+	if got, _ := sum.Sub(huge); got != Posit32Zero {
+		t.Errorf("sum mismatch; expected 0x00000000, got 0x%08X", got.Bits())
+	}
+}
+
+func TestPosit32Text(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		fmt  byte
+		prec int
+		want string
+	}{
+		{bits: 0x00000000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x80000000, fmt: 'g', prec: -1, want: "NaR"},
+		{bits: 0x40000000, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0x4C90FDAA, fmt: 'g', prec: -1, want: "3.14159265"},
+		{bits: 0xB36F0256, fmt: 'e', prec: 3, want: "-3.142e+00"},
+		{bits: 0x32AAAAAB, fmt: 'g', prec: -1, want: "0.333333334"},
+		{bits: 0x7FFFFFFF, fmt: 'g', prec: -1, want: "1e+37"},
+		{bits: 0x00000001, fmt: 'g', prec: -1, want: "1e-36"},
+	}
+	for _, g := range golden {
+		f := NewPosit32FromBits(g.bits)
+		if got := f.Text(g.fmt, g.prec); got != g.want {
+			t.Errorf("0x%08X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+	if got, want := fmt.Sprintf("%8.3f", Posit32One), "   1.000"; got != want {
+		t.Errorf("output mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,278 @@
+package posit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Special numbers of the posit<64,2> format.
+var (
+	// Not-a-Real
+	Posit64NaR = Posit64{bits: 0x8000000000000000}
+	// zero
+	Posit64Zero = Posit64{bits: 0x0000000000000000}
+	// +1
+	Posit64One = Posit64{bits: 0x4000000000000000}
+	// largest positive posit; 2^248
+	Posit64MaxPos = Posit64{bits: 0x7FFFFFFFFFFFFFFF}
+	// smallest positive posit; 2^-248
+	Posit64MinPos = Posit64{bits: 0x0000000000000001}
+)
+
+// Posit64 is a posit<64,2> number.
+type Posit64 struct {
+	// Sign, regime, exponent and fraction.
+	bits uint64
+}
+
+// NewPosit64FromBits returns the posit corresponding to the posit<64,2> binary
+// representation.
+func NewPosit64FromBits(bits uint64) Posit64 {
+	return Posit64{bits: bits}
+}
+
+// NewPosit64FromFloat32 returns the nearest posit<64,2> number for x and the
+// accuracy of the conversion, as for NewPosit64FromBig. NaN is converted to
+// NaR.
+func NewPosit64FromFloat32(x float32) (Posit64, big.Accuracy) {
+	return new(Context).NewPosit64FromFloat32(x)
+}
+
+// NewPosit64FromFloat32 returns the nearest posit<64,2> number for x and the
+// accuracy of the conversion, as for NewPosit64FromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit64FromFloat32(x float32) (Posit64, big.Accuracy) {
+	ctx.signaling32(x)
+	bits, acc := ctx.fromFloat64(posit64, float64(x))
+	return Posit64{bits: bits}, acc
+}
+
+// NewPosit64FromFloat64 returns the nearest posit<64,2> number for x and the
+// accuracy of the conversion, as for NewPosit64FromBig. NaN is converted to
+// NaR.
+func NewPosit64FromFloat64(x float64) (Posit64, big.Accuracy) {
+	return new(Context).NewPosit64FromFloat64(x)
+}
+
+// NewPosit64FromFloat64 returns the nearest posit<64,2> number for x and the
+// accuracy of the conversion, as for NewPosit64FromFloat64. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit64FromFloat64(x float64) (Posit64, big.Accuracy) {
+	ctx.signaling64(x)
+	bits, acc := ctx.fromFloat64(posit64, x)
+	return Posit64{bits: bits}, acc
+}
+
+// NewPosit64FromBig returns the nearest posit<64,2> number for x and the
+// accuracy of the conversion, rounded to nearest with ties to even. Non-zero
+// values are never rounded to zero, and +-Inf is converted to NaR.
+func NewPosit64FromBig(x *big.Float) (Posit64, big.Accuracy) {
+	return new(Context).NewPosit64FromBig(x)
+}
+
+// NewPosit64FromBig returns the nearest posit<64,2> number for x and the
+// accuracy of the conversion, as for NewPosit64FromBig. The exception flags
+// raised are recorded in ctx.
+func (ctx *Context) NewPosit64FromBig(x *big.Float) (Posit64, big.Accuracy) {
+	bits, acc := ctx.encode(posit64, x)
+	return Posit64{bits: bits}, acc
+}
+
+// Bits returns the posit<64,2> binary representation of f.
+func (f Posit64) Bits() uint64 {
+	return f.bits
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion. NaR is converted to NaN.
+func (f Posit64) Float32() (float32, big.Accuracy) {
+	return new(Context).Posit64Float32(f)
+}
+
+// Posit64Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit64Float32(f Posit64) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion. NaR is converted to NaN.
+func (f Posit64) Float64() (float64, big.Accuracy) {
+	return new(Context).Posit64Float64(f)
+}
+
+// Posit64Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit64Float64(f Posit64) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f
+// and a boolean indicating whether f is NaR.
+func (f Posit64) Big() (x *big.Float, nar bool) {
+	return posit64.decode(f.bits)
+}
+
+// IsNaR reports whether f is Not-a-Real.
+func (f Posit64) IsNaR() bool {
+	return f == Posit64NaR
+}
+
+// Signbit reports whether f is negative or NaR.
+func (f Posit64) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x8000000000000000 != 0
+}
+
+// Neg returns the negation -f. The negation of NaR is NaR.
+func (f Posit64) Neg() Posit64 {
+	return Posit64{bits: posit64.neg(f.bits)}
+}
+
+// Add returns the sum f+g, rounded to nearest, and the accuracy of the result.
+func (f Posit64) Add(g Posit64) (Posit64, big.Accuracy) {
+	return new(Context).Posit64Add(f, g)
+}
+
+// Posit64Add returns the sum f+g, rounded to nearest, and the accuracy of the
+// result, as for f.Add. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit64Add(f, g Posit64) (Posit64, big.Accuracy) {
+	bits, acc := ctx.add(posit64, f.bits, g.bits)
+	return Posit64{bits: bits}, acc
+}
+
+// Sub returns the difference f-g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit64) Sub(g Posit64) (Posit64, big.Accuracy) {
+	return f.Add(g.Neg())
+}
+
+// Posit64Sub returns the difference f-g, rounded to nearest, and the accuracy
+// of the result, as for f.Sub. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit64Sub(f, g Posit64) (Posit64, big.Accuracy) {
+	return ctx.Posit64Add(f, g.Neg())
+}
+
+// Mul returns the product f*g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit64) Mul(g Posit64) (Posit64, big.Accuracy) {
+	return new(Context).Posit64Mul(f, g)
+}
+
+// Posit64Mul returns the product f*g, rounded to nearest, and the accuracy of
+// the result, as for f.Mul. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit64Mul(f, g Posit64) (Posit64, big.Accuracy) {
+	bits, acc := ctx.mul(posit64, f.bits, g.bits)
+	return Posit64{bits: bits}, acc
+}
+
+// Div returns the quotient f/g, rounded to nearest, and the accuracy of the
+// result. Division by zero is NaR.
+func (f Posit64) Div(g Posit64) (Posit64, big.Accuracy) {
+	return new(Context).Posit64Div(f, g)
+}
+
+// Posit64Div returns the quotient f/g, rounded to nearest, and the accuracy of
+// the result, as for f.Div. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit64Div(f, g Posit64) (Posit64, big.Accuracy) {
+	bits, acc := ctx.div(posit64, f.bits, g.bits)
+	return Posit64{bits: bits}, acc
+}
+
+// Sqrt returns the square root of f, rounded to nearest, and the accuracy of
+// the result. The square root of a negative number is NaR.
+func (f Posit64) Sqrt() (Posit64, big.Accuracy) {
+	return new(Context).Posit64Sqrt(f)
+}
+
+// Posit64Sqrt returns the square root of f, rounded to nearest, and the
+// accuracy of the result, as for f.Sqrt. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) Posit64Sqrt(f Posit64) (Posit64, big.Accuracy) {
+	bits, acc := ctx.sqrt(posit64, f.bits)
+	return Posit64{bits: bits}, acc
+}
+
+// Text converts the posit f to a string, according to the format fmt and
+// precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e', 'E', 'f',
+// 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest number of
+// digits necessary for NewPosit64FromBig to return f. NaR is represented as
+// "NaR".
+func (f Posit64) Text(fmt byte, prec int) string {
+	return posit64.text(f.bits, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which converts back
+// to f, as for f.Text('g', -1).
+func (f Posit64) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Posit64) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// Quire64 is a quire of the posit<64,2> format; an accumulator of exact sums of
+// products of posits. The zero value is a quire holding zero.
+type Quire64 struct {
+	q quire
+}
+
+// MulAdd adds the exact product a*b to q. If a or b is NaR, q becomes NaR.
+func (q *Quire64) MulAdd(a, b Posit64) {
+	posit64.mulAdd(&q.q, a.bits, b.bits, false)
+}
+
+// MulSub subtracts the exact product a*b from q. If a or b is NaR, q becomes
+// NaR.
+func (q *Quire64) MulSub(a, b Posit64) {
+	posit64.mulAdd(&q.q, a.bits, b.bits, true)
+}
+
+// Add adds a to q. If a is NaR, q becomes NaR.
+func (q *Quire64) Add(a Posit64) {
+	q.MulAdd(a, Posit64One)
+}
+
+// Sub subtracts a from q. If a is NaR, q becomes NaR.
+func (q *Quire64) Sub(a Posit64) {
+	q.MulSub(a, Posit64One)
+}
+
+// Reset sets q to zero.
+func (q *Quire64) Reset() {
+	q.q = quire{}
+}
+
+// IsNaR reports whether q is Not-a-Real.
+func (q *Quire64) IsNaR() bool {
+	return q.q.nar
+}
+
+// Big returns the exact value of q and a boolean indicating whether q is NaR.
+func (q *Quire64) Big() (x *big.Float, nar bool) {
+	return posit64.quireBig(&q.q)
+}
+
+// Posit returns the posit nearest to the value of q, rounded to nearest, and the
+// accuracy of the conversion.
+func (q *Quire64) Posit() (Posit64, big.Accuracy) {
+	return new(Context).Quire64Posit(q)
+}
+
+// Quire64Posit returns the posit nearest to the value of q, rounded to
+// nearest, and the accuracy of the conversion, as for q.Posit. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) Quire64Posit(q *Quire64) (Posit64, big.Accuracy) {
+	bits, acc := ctx.quireRound(posit64, &q.q)
+	return Posit64{bits: bits}, acc
+}
@@ -0,0 +1,80 @@
+package posit
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewPosit64FromBig(t *testing.T) {
+	pi, _, _ := big.ParseFloat("3.14159265358979323846264338327950288", 10, 200, big.ToNearestEven)
+	golden := []struct {
+		in   *big.Float
+		want uint64
+		acc  big.Accuracy
+	}{
+		{in: big.NewFloat(1), want: 0x4000000000000000, acc: big.Exact},
+		// 0 10 01 10010010000111111011010101000100010000101101000110000100011
+		{in: pi, want: 0x4C90FDAA22168C23, acc: big.Below},
+		{in: new(big.Float).SetInf(true), want: 0x8000000000000000, acc: big.Exact},
+		{in: new(big.Float).SetMantExp(big.NewFloat(1), 248), want: 0x7FFFFFFFFFFFFFFF, acc: big.Exact},
+		{in: new(big.Float).SetMantExp(big.NewFloat(-1), -248), want: 0xFFFFFFFFFFFFFFFF, acc: big.Exact},
+	}
+	for _, g := range golden {
+		f, acc := NewPosit64FromBig(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+	if x, acc := NewPosit64FromBits(0x4C90FDAA22168C23).Float64(); x != math.Pi || acc != big.Below {
+		t.Errorf("float64 mismatch; expected %v, got %v (%v)", math.Pi, x, acc)
+	}
+}
+
+func TestPosit64RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		f := NewPosit64FromBits(r.Uint64())
+		x, nar := f.Big()
+		if nar {
+			continue
+		}
+		if got, acc := NewPosit64FromBig(x); got != f || acc != big.Exact {
+			t.Errorf("0x%016X: round-trip mismatch; got 0x%016X (%v)", f.Bits(), got.Bits(), acc)
+		}
+		s := f.String()
+		y, _, err := big.ParseFloat(s, 10, 200, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("0x%016X: unable to parse %q; %v", f.Bits(), s, err)
+			continue
+		}
+		if got, _ := NewPosit64FromBig(y); got != f {
+			t.Errorf("0x%016X: text round-trip mismatch; %q is 0x%016X", f.Bits(), s, got.Bits())
+		}
+		g := NewPosit64FromBits(r.Uint64())
+		y, nar = g.Big()
+		if nar {
+			continue
+		}
+		z := new(big.Float).SetPrec(exactPrec)
+		got, acc := f.Add(g)
+		checkRounded(t, posit64, "+", f.bits, g.bits, got.bits, acc, z.Add(x, y), false)
+		got, acc = f.Mul(g)
+		checkRounded(t, posit64, "*", f.bits, g.bits, got.bits, acc, z.Mul(x, y), false)
+	}
+}
+
+func TestQuire64(t *testing.T) {
+	// Dot product of (maxpos, 1, -maxpos) and (maxpos, minpos, maxpos).
+	var q Quire64
+	q.MulAdd(Posit64MaxPos, Posit64MaxPos)
+	q.MulAdd(Posit64One, Posit64MinPos)
+	q.MulAdd(Posit64MaxPos.Neg(), Posit64MaxPos)
+	if got, acc := q.Posit(); got != Posit64MinPos || acc != big.Exact {
+		t.Errorf("quire mismatch; expected 0x0000000000000001, got 0x%016X (%v)", got.Bits(), acc)
+	}
+}
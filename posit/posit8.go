@@ -0,0 +1,277 @@
+package posit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Special numbers of the posit<8,2> format.
+var (
+	// Not-a-Real
+	Posit8NaR = Posit8{bits: 0x80}
+	// zero
+	Posit8Zero = Posit8{bits: 0x00}
+	// +1
+	Posit8One = Posit8{bits: 0x40}
+	// largest positive posit; 2^24
+	Posit8MaxPos = Posit8{bits: 0x7F}
+	// smallest positive posit; 2^-24
+	Posit8MinPos = Posit8{bits: 0x01}
+)
+
+// Posit8 is a posit<8,2> number.
+type Posit8 struct {
+	// Sign, regime, exponent and fraction.
+	bits uint8
+}
+
+// NewPosit8FromBits returns the posit corresponding to the posit<8,2> binary
+// representation.
+func NewPosit8FromBits(bits uint8) Posit8 {
+	return Posit8{bits: bits}
+}
+
+// NewPosit8FromFloat32 returns the nearest posit<8,2> number for x and the
+// accuracy of the conversion, as for NewPosit8FromBig. NaN is converted to
+// NaR.
+func NewPosit8FromFloat32(x float32) (Posit8, big.Accuracy) {
+	return new(Context).NewPosit8FromFloat32(x)
+}
+
+// NewPosit8FromFloat32 returns the nearest posit<8,2> number for x and the
+// accuracy of the conversion, as for NewPosit8FromFloat32. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit8FromFloat32(x float32) (Posit8, big.Accuracy) {
+	ctx.signaling32(x)
+	bits, acc := ctx.fromFloat64(posit8, float64(x))
+	return Posit8{bits: uint8(bits)}, acc
+}
+
+// NewPosit8FromFloat64 returns the nearest posit<8,2> number for x and the
+// accuracy of the conversion, as for NewPosit8FromBig. NaN is converted to
+// NaR.
+func NewPosit8FromFloat64(x float64) (Posit8, big.Accuracy) {
+	return new(Context).NewPosit8FromFloat64(x)
+}
+
+// NewPosit8FromFloat64 returns the nearest posit<8,2> number for x and the
+// accuracy of the conversion, as for NewPosit8FromFloat64. The exception flags
+// raised are recorded in ctx; a signaling NaN raises the invalid operation
+// exception.
+func (ctx *Context) NewPosit8FromFloat64(x float64) (Posit8, big.Accuracy) {
+	ctx.signaling64(x)
+	bits, acc := ctx.fromFloat64(posit8, x)
+	return Posit8{bits: uint8(bits)}, acc
+}
+
+// NewPosit8FromBig returns the nearest posit<8,2> number for x and the
+// accuracy of the conversion, rounded to nearest with ties to even. Non-zero
+// values are never rounded to zero, and +-Inf is converted to NaR.
+func NewPosit8FromBig(x *big.Float) (Posit8, big.Accuracy) {
+	return new(Context).NewPosit8FromBig(x)
+}
+
+// NewPosit8FromBig returns the nearest posit<8,2> number for x and the accuracy
+// of the conversion, as for NewPosit8FromBig. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewPosit8FromBig(x *big.Float) (Posit8, big.Accuracy) {
+	bits, acc := ctx.encode(posit8, x)
+	return Posit8{bits: uint8(bits)}, acc
+}
+
+// Bits returns the posit<8,2> binary representation of f.
+func (f Posit8) Bits() uint8 {
+	return f.bits
+}
+
+// Float32 returns the float32 value of f. The conversion is always exact. NaR is
+// converted to NaN.
+func (f Posit8) Float32() (float32, big.Accuracy) {
+	return new(Context).Posit8Float32(f)
+}
+
+// Posit8Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit8Float32(f Posit8) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value of f. The conversion is always exact. NaR
+// is converted to NaN.
+func (f Posit8) Float64() (float64, big.Accuracy) {
+	return new(Context).Posit8Float64(f)
+}
+
+// Posit8Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx.
+func (ctx *Context) Posit8Float64(f Posit8) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f
+// and a boolean indicating whether f is NaR.
+func (f Posit8) Big() (x *big.Float, nar bool) {
+	return posit8.decode(uint64(f.bits))
+}
+
+// IsNaR reports whether f is Not-a-Real.
+func (f Posit8) IsNaR() bool {
+	return f == Posit8NaR
+}
+
+// Signbit reports whether f is negative or NaR.
+func (f Posit8) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x80 != 0
+}
+
+// Neg returns the negation -f. The negation of NaR is NaR.
+func (f Posit8) Neg() Posit8 {
+	return Posit8{bits: uint8(posit8.neg(uint64(f.bits)))}
+}
+
+// Add returns the sum f+g, rounded to nearest, and the accuracy of the result.
+func (f Posit8) Add(g Posit8) (Posit8, big.Accuracy) {
+	return new(Context).Posit8Add(f, g)
+}
+
+// Posit8Add returns the sum f+g, rounded to nearest, and the accuracy of the
+// result, as for f.Add. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit8Add(f, g Posit8) (Posit8, big.Accuracy) {
+	bits, acc := ctx.add(posit8, uint64(f.bits), uint64(g.bits))
+	return Posit8{bits: uint8(bits)}, acc
+}
+
+// Sub returns the difference f-g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit8) Sub(g Posit8) (Posit8, big.Accuracy) {
+	return f.Add(g.Neg())
+}
+
+// Posit8Sub returns the difference f-g, rounded to nearest, and the accuracy of
+// the result, as for f.Sub. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit8Sub(f, g Posit8) (Posit8, big.Accuracy) {
+	return ctx.Posit8Add(f, g.Neg())
+}
+
+// Mul returns the product f*g, rounded to nearest, and the accuracy of the
+// result.
+func (f Posit8) Mul(g Posit8) (Posit8, big.Accuracy) {
+	return new(Context).Posit8Mul(f, g)
+}
+
+// Posit8Mul returns the product f*g, rounded to nearest, and the accuracy of
+// the result, as for f.Mul. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit8Mul(f, g Posit8) (Posit8, big.Accuracy) {
+	bits, acc := ctx.mul(posit8, uint64(f.bits), uint64(g.bits))
+	return Posit8{bits: uint8(bits)}, acc
+}
+
+// Div returns the quotient f/g, rounded to nearest, and the accuracy of the
+// result. Division by zero is NaR.
+func (f Posit8) Div(g Posit8) (Posit8, big.Accuracy) {
+	return new(Context).Posit8Div(f, g)
+}
+
+// Posit8Div returns the quotient f/g, rounded to nearest, and the accuracy of
+// the result, as for f.Div. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit8Div(f, g Posit8) (Posit8, big.Accuracy) {
+	bits, acc := ctx.div(posit8, uint64(f.bits), uint64(g.bits))
+	return Posit8{bits: uint8(bits)}, acc
+}
+
+// Sqrt returns the square root of f, rounded to nearest, and the accuracy of
+// the result. The square root of a negative number is NaR.
+func (f Posit8) Sqrt() (Posit8, big.Accuracy) {
+	return new(Context).Posit8Sqrt(f)
+}
+
+// Posit8Sqrt returns the square root of f, rounded to nearest, and the accuracy
+// of the result, as for f.Sqrt. The exception flags raised are recorded in ctx.
+func (ctx *Context) Posit8Sqrt(f Posit8) (Posit8, big.Accuracy) {
+	bits, acc := ctx.sqrt(posit8, uint64(f.bits))
+	return Posit8{bits: uint8(bits)}, acc
+}
+
+// Text converts the posit f to a string, according to the format fmt and
+// precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e', 'E', 'f',
+// 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest number of
+// digits necessary for NewPosit8FromBig to return f. NaR is represented as
+// "NaR".
+func (f Posit8) Text(fmt byte, prec int) string {
+	return posit8.text(uint64(f.bits), fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which converts back
+// to f, as for f.Text('g', -1).
+func (f Posit8) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f Posit8) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// Quire8 is a quire of the posit<8,2> format; an accumulator of exact sums of
+// products of posits. The zero value is a quire holding zero.
+type Quire8 struct {
+	q quire
+}
+
+// MulAdd adds the exact product a*b to q. If a or b is NaR, q becomes NaR.
+func (q *Quire8) MulAdd(a, b Posit8) {
+	posit8.mulAdd(&q.q, uint64(a.bits), uint64(b.bits), false)
+}
+
+// MulSub subtracts the exact product a*b from q. If a or b is NaR, q becomes
+// NaR.
+func (q *Quire8) MulSub(a, b Posit8) {
+	posit8.mulAdd(&q.q, uint64(a.bits), uint64(b.bits), true)
+}
+
+// Add adds a to q. If a is NaR, q becomes NaR.
+func (q *Quire8) Add(a Posit8) {
+	q.MulAdd(a, Posit8One)
+}
+
+// Sub subtracts a from q. If a is NaR, q becomes NaR.
+func (q *Quire8) Sub(a Posit8) {
+	q.MulSub(a, Posit8One)
+}
+
+// Reset sets q to zero.
+func (q *Quire8) Reset() {
+	q.q = quire{}
+}
+
+// IsNaR reports whether q is Not-a-Real.
+func (q *Quire8) IsNaR() bool {
+	return q.q.nar
+}
+
+// Big returns the exact value of q and a boolean indicating whether q is NaR.
+func (q *Quire8) Big() (x *big.Float, nar bool) {
+	return posit8.quireBig(&q.q)
+}
+
+// Posit returns the posit nearest to the value of q, rounded to nearest, and the
+// accuracy of the conversion.
+func (q *Quire8) Posit() (Posit8, big.Accuracy) {
+	return new(Context).Quire8Posit(q)
+}
+
+// Quire8Posit returns the posit nearest to the value of q, rounded to
+// nearest, and the accuracy of the conversion, as for q.Posit. The exception
+// flags raised are recorded in ctx.
+func (ctx *Context) Quire8Posit(q *Quire8) (Posit8, big.Accuracy) {
+	bits, acc := ctx.quireRound(posit8, &q.q)
+	return Posit8{bits: uint8(bits)}, acc
+}
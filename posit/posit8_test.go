@@ -0,0 +1,229 @@
+package posit
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestNewPosit8FromBits(t *testing.T) {
+	golden := []struct {
+		bits uint8
+		want float64
+		nar  bool
+	}{
+		// Special numbers.
+		// 0 0000000 = 0
+		{bits: 0x00, want: 0},
+		// 1 0000000 = NaR
+		{bits: 0x80, nar: true},
+
+		// 0 10 00 000 = 1
+		{bits: 0x40, want: 1},
+		// 1 10 00 000 = -1
+		{bits: 0xC0, want: -1},
+		// 0 10 01 000 = 2
+		{bits: 0x48, want: 2},
+		// 0 10 00 100 = 1.5
+		{bits: 0x44, want: 1.5},
+		// 0 110 00 00 = 16
+		{bits: 0x60, want: 16},
+		// 0 01 11 111 = 0.9375
+		{bits: 0x3F, want: 0.9375},
+		// 0 001 10 00 = 2^-6
+		{bits: 0x18, want: 0x1p-6},
+		// 0 1111110 = 2^20 (exponent bits cut off)
+		{bits: 0x7E, want: 0x1p20},
+		// 0 1111111 = maxpos
+		{bits: 0x7F, want: 0x1p24},
+		// 0 0000001 = minpos
+		{bits: 0x01, want: 0x1p-24},
+		// 1 0000001 = -maxpos
+		{bits: 0x81, want: -0x1p24},
+	}
+	for _, g := range golden {
+		f := NewPosit8FromBits(g.bits)
+		x, nar := f.Big()
+		if nar != g.nar {
+			t.Errorf("0x%02X: NaR mismatch; expected %v, got %v", g.bits, g.nar, nar)
+			continue
+		}
+		if nar {
+			continue
+		}
+		if got, acc := x.Float64(); got != g.want || acc != big.Exact {
+			t.Errorf("0x%02X: number mismatch; expected %v, got %v (%v)", g.bits, g.want, got, acc)
+		}
+	}
+}
+
+func TestNewPosit8FromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint8
+		acc  big.Accuracy
+	}{
+		// Special numbers.
+		{in: 0, want: 0x00, acc: big.Exact},
+		{in: math.Copysign(0, -1), want: 0x00, acc: big.Exact},
+		{in: math.NaN(), want: 0x80, acc: big.Exact},
+		{in: math.Inf(1), want: 0x80, acc: big.Exact},
+		{in: math.Inf(-1), want: 0x80, acc: big.Exact},
+
+		{in: 1, want: 0x40, acc: big.Exact},
+		{in: -1.5, want: 0xBC, acc: big.Exact},
+		// pi = 0 10 01 101 (1.625 * 2)
+		{in: math.Pi, want: 0x4D, acc: big.Above},
+		// 1 + 1/16 = 1 (tie, round to even)
+		{in: 1 + 1./16, want: 0x40, acc: big.Below},
+		// 1 + 3/16 = 1.25 (tie, round to even)
+		{in: 1 + 3./16, want: 0x42, acc: big.Above},
+		// 2^22 lies halfway between 2^20 and 2^24 on the binary representation
+		// (tie, round to even).
+		{in: 0x1p22, want: 0x7E, acc: big.Below},
+		{in: 0x1.000001p22, want: 0x7F, acc: big.Above},
+
+		// Values never round to zero or NaR.
+		{in: 0x1p30, want: 0x7F, acc: big.Below},
+		{in: -0x1p1000, want: 0x81, acc: big.Above},
+		{in: 0x1p-30, want: 0x01, acc: big.Above},
+		{in: -math.SmallestNonzeroFloat64, want: 0xFF, acc: big.Below},
+	}
+	for _, g := range golden {
+		f, acc := NewPosit8FromFloat64(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%02X, got 0x%02X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestPosit8RoundTrip(t *testing.T) {
+	var prev *big.Float
+	for i := 0; i < 256; i++ {
+		// In order of the binary representation as signed integer.
+		f := NewPosit8FromBits(uint8(i - 128))
+		x, nar := f.Big()
+		if nar != (f == Posit8NaR) || nar != f.IsNaR() {
+			t.Errorf("0x%02X: NaR mismatch", f.Bits())
+		}
+		if nar {
+			continue
+		}
+		if prev != nil && prev.Cmp(x) >= 0 {
+			t.Errorf("0x%02X: posits out of order; %v >= %v", f.Bits(), prev, x)
+		}
+		prev = x
+		if got, acc := NewPosit8FromBig(x); got != f || acc != big.Exact {
+			t.Errorf("0x%02X: round-trip mismatch; got 0x%02X (%v)", f.Bits(), got.Bits(), acc)
+		}
+		if got, acc := f.Float32(); float64(got) != f64(x) || acc != big.Exact {
+			t.Errorf("0x%02X: float32 mismatch; expected %v, got %v (%v)", f.Bits(), x, got, acc)
+		}
+		s := f.Text('g', -1)
+		y, _, err := big.ParseFloat(s, 10, 100, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("0x%02X: unable to parse %q; %v", f.Bits(), s, err)
+			continue
+		}
+		if got, _ := NewPosit8FromBig(y); got != f {
+			t.Errorf("0x%02X: text round-trip mismatch; %q is 0x%02X", f.Bits(), s, got.Bits())
+		}
+		if got := f.Neg().Neg(); got != f {
+			t.Errorf("0x%02X: negation mismatch; got 0x%02X", f.Bits(), got.Bits())
+		}
+	}
+}
+
+func TestPosit8Arith(t *testing.T) {
+	// Every result is the exact result rounded on the binary representation.
+	for i := 0; i < 256; i++ {
+		for j := 0; j < 256; j++ {
+			f, g := NewPosit8FromBits(uint8(i)), NewPosit8FromBits(uint8(j))
+			x, nar1 := f.Big()
+			y, nar2 := g.Big()
+			nar := nar1 || nar2
+			z := new(big.Float).SetPrec(exactPrec)
+			got, acc := f.Add(g)
+			checkRounded(t, posit8, "+", uint64(f.bits), uint64(g.bits), uint64(got.bits), acc, z.Add(x, y), nar)
+			got, acc = f.Sub(g)
+			checkRounded(t, posit8, "-", uint64(f.bits), uint64(g.bits), uint64(got.bits), acc, z.Sub(x, y), nar)
+			got, acc = f.Mul(g)
+			checkRounded(t, posit8, "*", uint64(f.bits), uint64(g.bits), uint64(got.bits), acc, z.Mul(x, y), nar)
+			got, acc = f.Div(g)
+			if y.Sign() == 0 {
+				nar = true
+			} else {
+				z.Quo(x, y)
+			}
+			checkRounded(t, posit8, "/", uint64(f.bits), uint64(g.bits), uint64(got.bits), acc, z, nar)
+		}
+		f := NewPosit8FromBits(uint8(i))
+		x, nar := f.Big()
+		z := new(big.Float).SetPrec(exactPrec)
+		if x.Sign() >= 0 {
+			z.Sqrt(x)
+		}
+		got, acc := f.Sqrt()
+		checkRounded(t, posit8, "sqrt", uint64(f.bits), 0, uint64(got.bits), acc, z, nar || x.Sign() < 0)
+	}
+}
+
+// checkRounded checks that the posit bits is the result of the operation op on
+// a and b, with the exact (or 1024-bit) result x, rounded on the binary
+// representation with ties to even, and that acc is the accuracy of bits.
+func checkRounded(t *testing.T, f format, op string, a, b, bits uint64, acc big.Accuracy, x *big.Float, nar bool) {
+	t.Helper()
+	if nar {
+		if bits != f.nar() || acc != big.Exact {
+			t.Errorf("0x%X %s 0x%X: expected NaR, got 0x%X (%v)", a, op, b, bits, acc)
+		}
+		return
+	}
+	y, isNaR := f.decode(bits)
+	if isNaR {
+		t.Errorf("0x%X %s 0x%X: unexpected NaR", a, op, b)
+		return
+	}
+	if want := big.Accuracy(y.Cmp(x)); acc != want {
+		t.Errorf("0x%X %s 0x%X: accuracy mismatch; expected %v, got %v", a, op, b, want, acc)
+	}
+	if x.Sign() == 0 {
+		if bits != 0 {
+			t.Errorf("0x%X %s 0x%X: expected 0, got 0x%X", a, op, b, bits)
+		}
+		return
+	}
+	p, neg := f.abs(bits)
+	if p == 0 || neg != x.Signbit() {
+		t.Errorf("0x%X %s 0x%X: sign mismatch; %v rounded to 0x%X", a, op, b, x, bits)
+		return
+	}
+	// The values rounding to p lie between the posits of n+1 bits 2p-1 and
+	// 2p+1, ties to even, except below minpos and above maxpos.
+	abs := new(big.Float).Abs(x)
+	mid := new(big.Int).Lsh(new(big.Int).SetUint64(p), 1)
+	lower := magnitude(new(big.Int).Sub(mid, big.NewInt(1)), f.n+1)
+	upper := magnitude(new(big.Int).Add(mid, big.NewInt(1)), f.n+1)
+	lo, hi := abs.Cmp(lower), abs.Cmp(upper)
+	if p&1 != 0 {
+		// Ties round to the even neighbours.
+		if lo == 0 {
+			lo = -1
+		}
+		if hi == 0 {
+			hi = 1
+		}
+	}
+	if p != 1 && lo < 0 || p != f.maxpos() && hi > 0 {
+		t.Errorf("0x%X %s 0x%X: %v rounded to 0x%X (%v)", a, op, b, x, bits, y)
+	}
+}
+
+// f64 returns the float64 value of x.
+func f64(x *big.Float) float64 {
+	v, _ := x.Float64()
+	return v
+}
@@ -0,0 +1,77 @@
+package posit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestFlags(t *testing.T) {
+	two, _ := NewPosit16FromFloat64(2)
+	three, _ := NewPosit64FromFloat64(3)
+	third, _ := Posit64One.Div(three)
+	// 2^-126 - 2^-152 rounds to 2^-126 as float32, and is tiny only before
+	// rounding.
+	tiny, _ := NewPosit64FromFloat64(0x1.FFFFFF8p-127)
+	golden := []struct {
+		name          string
+		op            func(ctx *Context)
+		after, before fenv.Flags
+	}{
+		// Conversions to posits.
+		{name: "NewPosit16FromFloat64(1)", op: func(ctx *Context) { ctx.NewPosit16FromFloat64(1) }},
+		{name: "NewPosit16FromFloat64(0.1)", op: func(ctx *Context) { ctx.NewPosit16FromFloat64(0.1) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "NewPosit8FromFloat64(1e10)", op: func(ctx *Context) { ctx.NewPosit8FromFloat64(1e10) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "NewPosit8FromFloat64(-1e-10)", op: func(ctx *Context) { ctx.NewPosit8FromFloat64(-1e-10) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "NewPosit32FromFloat64(Inf)", op: func(ctx *Context) { ctx.NewPosit32FromFloat64(math.Inf(1)) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "NewPosit32FromFloat64(NaN)", op: func(ctx *Context) { ctx.NewPosit32FromFloat64(math.NaN()) }},
+		{name: "NewPosit32FromFloat64(sNaN)", op: func(ctx *Context) { ctx.NewPosit32FromFloat64(math.Float64frombits(0x7FF0000000000001)) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "NewPosit64FromFloat32(sNaN)", op: func(ctx *Context) { ctx.NewPosit64FromFloat32(math.Float32frombits(0x7F800001)) }, after: fenv.Invalid, before: fenv.Invalid},
+		// Arithmetic.
+		{name: "Posit16Add(1, 1)", op: func(ctx *Context) { ctx.Posit16Add(Posit16One, Posit16One) }},
+		{name: "Posit8Add(1, minpos)", op: func(ctx *Context) { ctx.Posit8Add(Posit8One, Posit8MinPos) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "Posit8Sub(minpos, maxpos)", op: func(ctx *Context) { ctx.Posit8Sub(Posit8MinPos, Posit8MaxPos) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "Posit32Mul(maxpos, maxpos)", op: func(ctx *Context) { ctx.Posit32Mul(Posit32MaxPos, Posit32MaxPos) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "Posit32Mul(NaR, 1)", op: func(ctx *Context) { ctx.Posit32Mul(Posit32NaR, Posit32One) }},
+		{name: "Posit16Div(1, 2)", op: func(ctx *Context) { ctx.Posit16Div(Posit16One, two) }},
+		{name: "Posit16Div(1, 0)", op: func(ctx *Context) { ctx.Posit16Div(Posit16One, Posit16Zero) }, after: fenv.DivByZero, before: fenv.DivByZero},
+		{name: "Posit16Div(0, 0)", op: func(ctx *Context) { ctx.Posit16Div(Posit16Zero, Posit16Zero) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "Posit16Div(NaR, 0)", op: func(ctx *Context) { ctx.Posit16Div(Posit16NaR, Posit16Zero) }},
+		{name: "Posit16Sqrt(2)", op: func(ctx *Context) { ctx.Posit16Sqrt(two) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "Posit16Sqrt(-1)", op: func(ctx *Context) { ctx.Posit16Sqrt(Posit16One.Neg()) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "Posit16Sqrt(NaR)", op: func(ctx *Context) { ctx.Posit16Sqrt(Posit16NaR) }},
+		// Quires.
+		{name: "Quire16Posit(1+1)", op: func(ctx *Context) {
+			var q Quire16
+			q.Add(Posit16One)
+			q.Add(Posit16One)
+			ctx.Quire16Posit(&q)
+		}},
+		{name: "Quire16Posit(1+minpos)", op: func(ctx *Context) {
+			var q Quire16
+			q.Add(Posit16One)
+			q.Add(Posit16MinPos)
+			ctx.Quire16Posit(&q)
+		}, after: fenv.Inexact, before: fenv.Inexact},
+		// Conversions from posits.
+		{name: "Posit64Float64(1/3)", op: func(ctx *Context) { ctx.Posit64Float64(third) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "Posit64Float32(maxpos)", op: func(ctx *Context) { ctx.Posit64Float32(Posit64MaxPos) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "Posit64Float32(minpos)", op: func(ctx *Context) { ctx.Posit64Float32(Posit64MinPos) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "Posit64Float32(0x1.FFFFFF8p-127)", op: func(ctx *Context) { ctx.Posit64Float32(tiny) }, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "Posit8Float32(NaR)", op: func(ctx *Context) { ctx.Posit8Float32(Posit8NaR) }},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			g.op(ctx)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}
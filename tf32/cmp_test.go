@@ -0,0 +1,74 @@
+package tf32
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClass(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint32
+		class float.Class
+	}{
+		{bits: 0xFFFFE000, class: float.QuietNaN},
+		{bits: 0xFFC00000, class: float.QuietNaN},
+		{bits: 0xFFBFE000, class: float.SignalingNaN},
+		{bits: 0xFF802000, class: float.SignalingNaN},
+		{bits: 0xFF800000, class: float.NegativeInfinity},
+		{bits: 0xFF7FE000, class: float.NegativeNormal},
+		{bits: 0xBF800000, class: float.NegativeNormal},
+		{bits: 0x80800000, class: float.NegativeNormal},
+		{bits: 0x807FE000, class: float.NegativeSubnormal},
+		{bits: 0x80002000, class: float.NegativeSubnormal},
+		{bits: 0x80000000, class: float.NegativeZero},
+		{bits: 0x00000000, class: float.PositiveZero},
+		{bits: 0x00002000, class: float.PositiveSubnormal},
+		{bits: 0x007FE000, class: float.PositiveSubnormal},
+		{bits: 0x00800000, class: float.PositiveNormal},
+		{bits: 0x3F800000, class: float.PositiveNormal},
+		{bits: 0x7F7FE000, class: float.PositiveNormal},
+		{bits: 0x7F800000, class: float.PositiveInfinity},
+		{bits: 0x7F802000, class: float.SignalingNaN},
+		{bits: 0x7FBFE000, class: float.SignalingNaN},
+		{bits: 0x7FC00000, class: float.QuietNaN},
+		{bits: 0x7FFFE000, class: float.QuietNaN},
+	}
+	for _, a := range golden {
+		f := NewFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%08X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%08X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	// Comparison of numbers at the boundaries of the encoding.
+	golden := []struct {
+		x, y  uint32
+		cmp   int
+		total bool
+	}{
+		// Largest subnormal and smallest normal number.
+		{x: 0x007FE000, y: 0x00800000, cmp: -1, total: true},
+		{x: 0x80800000, y: 0x807FE000, cmp: -1, total: true},
+		// Positive and negative zero.
+		{x: 0x00000000, y: 0x80000000, cmp: 0, total: false},
+		// Signaling and quiet NaN.
+		{x: 0x7FBFE000, y: 0x7FC00000, cmp: 0, total: true},
+		{x: 0xFFBFE000, y: 0xFFC00000, cmp: 0, total: false},
+	}
+	for _, g := range golden {
+		x, y := NewFromBits(g.x), NewFromBits(g.y)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%08X.Cmp(0x%08X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%08X.TotalOrder(0x%08X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package tf32
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		fmt  byte
+		prec int
+		want string
+	}{
+		// Special numbers.
+		{bits: 0x00000000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x80000000, fmt: 'e', prec: 3, want: "-0.000e+00"},
+		{bits: 0x7F800000, fmt: 'g', prec: -1, want: "+Inf"},
+		{bits: 0xFF800000, fmt: 'f', prec: 2, want: "-Inf"},
+		{bits: 0x7FC00000, fmt: 'g', prec: -1, want: "NaN"},
+
+		{bits: 0x3F800000, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0x3F800000, fmt: 'b', prec: -1, want: "1024p-10"},
+		{bits: 0x40490000, fmt: 'g', prec: -1, want: "3.14"},
+		{bits: 0x40490000, fmt: 'f', prec: 6, want: "3.140625"},
+		{bits: 0x3EAAA000, fmt: 'g', prec: -1, want: "0.3333"},
+		{bits: 0x40490000, fmt: 'x', prec: -1, want: "0x1.92p+01"},
+		// max
+		{bits: 0x7F7FE000, fmt: 'g', prec: -1, want: "3.401e+38"},
+		// min normal
+		{bits: 0x00800000, fmt: 'g', prec: -1, want: "1.175e-38"},
+		// min denormal
+		{bits: 0x00002000, fmt: 'g', prec: -1, want: "1e-41"},
+		{bits: 0x80002000, fmt: 'b', prec: -1, want: "-1p-136"},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.bits)
+		got := f.Text(g.fmt, g.prec)
+		if g.want != got {
+			t.Errorf("0x%08X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	for i := uint32(0); i < 1<<19; i++ {
+		f := NewFromBits(i << 13)
+		if f.Exp() == 0xFF && f.Frac() != 0 {
+			// NaN
+			continue
+		}
+		s := f.String()
+		x, _, err := big.ParseFloat(s, 10, 1000, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("%q: unexpected error; %v", s, err)
+			continue
+		}
+		if got, _ := NewFromBig(x); f != got {
+			t.Errorf("%q: round-trip mismatch; expected 0x%08X, got 0x%08X", s, f.Bits(), got.Bits())
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	golden := []struct {
+		format string
+		bits   uint32
+		want   string
+	}{
+		{format: "%v", bits: 0x40490000, want: "3.14"},
+		{format: "%.3f", bits: 0x40490000, want: "3.141"},
+		{format: "%+10.2e", bits: 0xC0490000, want: " -3.14e+00"},
+		{format: "%08g", bits: 0xFF800000, want: "    -Inf"},
+	}
+	for _, g := range golden {
+		got := fmt.Sprintf(g.format, NewFromBits(g.bits))
+		if g.want != got {
+			t.Errorf("%q 0x%08X: format mismatch; expected %q, got %q", g.format, g.bits, g.want, got)
+		}
+	}
+}
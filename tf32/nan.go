@@ -0,0 +1,58 @@
+package tf32
+
+// quiet specifies the quiet bit of Not-a-Number values.
+const quiet = 0x00400000
+
+// NewNaN returns a Not-a-Number value with the given payload, which is quiet
+// unless signaling is set. The payload is the fraction without the quiet bit,
+// truncated to 9 bits. A signaling NaN with a zero payload has payload 1, as
+// its encoding would otherwise be infinity.
+func NewNaN(signaling bool, payload uint16) Float {
+	bits := uint32(payload&0x01FF) << unused
+	if !signaling {
+		return Float{bits: 0x7F800000 | quiet | bits}
+	}
+	if bits == 0 {
+		bits = 1 << unused
+	}
+	return Float{bits: 0x7F800000 | bits}
+}
+
+// IsSignaling reports whether f is a signaling Not-a-Number value.
+func (f Float) IsSignaling() bool {
+	return f.isNaN() && f.bits&quiet == 0
+}
+
+// Payload returns the payload of the Not-a-Number value f, i.e. its fraction
+// without the quiet bit, or 0 if f is not NaN.
+func (f Float) Payload() uint16 {
+	if !f.isNaN() {
+		return 0
+	}
+	return f.Frac() & 0x01FF
+}
+
+// Quiet returns f with the quiet bit set if f is a signaling Not-a-Number value,
+// and f otherwise. The sign and payload are preserved.
+func (f Float) Quiet() Float {
+	if !f.isNaN() {
+		return f
+	}
+	return Float{bits: f.bits | quiet}
+}
+
+// newNaN returns the quiet NaN with the given sign and the fraction frac aligned
+// at the most significant bit, keeping the 10 most significant bits of the
+// fraction.
+func newNaN(sign bool, frac uint64) Float {
+	f := Float{bits: 0x7F800000 | quiet | uint32(frac>>54)<<unused}
+	if sign {
+		f.bits |= 0x80000000
+	}
+	return f
+}
+
+// isNaN reports whether f is Not-a-Number.
+func (f Float) isNaN() bool {
+	return f.Exp() == 0xFF && f.Frac() != 0
+}
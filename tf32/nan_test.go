@@ -0,0 +1,92 @@
+package tf32
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewNaN(t *testing.T) {
+	golden := []struct {
+		signaling bool
+		payload   uint16
+		want      uint32
+	}{
+		{signaling: false, payload: 0, want: 0x7FC00000},
+		{signaling: false, payload: 0x0155, want: 0x7FEAA000},
+		{signaling: true, payload: 0x0155, want: 0x7FAAA000},
+		// Zero payload of signaling NaN.
+		{signaling: true, payload: 0, want: 0x7F802000},
+		// Payload truncated to 9 bits.
+		{signaling: false, payload: 0xFFFF, want: 0x7FFFE000},
+		{signaling: true, payload: 0x0200, want: 0x7F802000},
+	}
+	for _, g := range golden {
+		f := NewNaN(g.signaling, g.payload)
+		if g.want != f.Bits() {
+			t.Errorf("NewNaN(%v, 0x%04X): bits mismatch; expected 0x%08X, got 0x%08X", g.signaling, g.payload, g.want, f.Bits())
+			continue
+		}
+		if f.IsSignaling() != g.signaling {
+			t.Errorf("0x%08X: signaling mismatch; expected %v, got %v", f.Bits(), g.signaling, f.IsSignaling())
+		}
+		if want := uint16(g.want>>unused) & 0x01FF; want != f.Payload() {
+			t.Errorf("0x%08X: payload mismatch; expected 0x%04X, got 0x%04X", f.Bits(), want, f.Payload())
+		}
+		if q := f.Quiet(); q.Bits() != g.want|0x00400000 || q.IsSignaling() {
+			t.Errorf("0x%08X: quiet mismatch; expected 0x%08X, got 0x%08X", f.Bits(), g.want|0x00400000, q.Bits())
+		}
+	}
+	// Numbers other than NaN.
+	for _, f := range []Float{Inf, NegInf, Zero, NegZero, NewFromBits(0x3F800000)} {
+		if f.IsSignaling() || f.Payload() != 0 || f.Quiet() != f {
+			t.Errorf("0x%08X: unexpected NaN", f.Bits())
+		}
+	}
+}
+
+func TestNaNConversion(t *testing.T) {
+	golden32 := []struct {
+		in   uint32
+		want uint32
+	}{
+		// The 10 most significant bits of the fraction are kept.
+		{in: 0x7FC00000, want: 0x7FC00000},
+		{in: 0xFFD55555, want: 0xFFD54000},
+		// Signaling NaN is quieted.
+		{in: 0x7F800001, want: 0x7FC00000},
+		{in: 0x7F955555, want: 0x7FD54000},
+	}
+	for _, g := range golden32 {
+		if f, _ := NewFromFloat32(math.Float32frombits(g.in)); g.want != f.Bits() {
+			t.Errorf("0x%08X: float32 conversion mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, f.Bits())
+		}
+	}
+	golden64 := []struct {
+		in   uint64
+		want uint32
+	}{
+		{in: 0x7FF8000000000001, want: 0x7FC00000},
+		{in: 0xFFFAAAAAAAAAAAAA, want: 0xFFD54000},
+		{in: 0x7FF0000000000001, want: 0x7FC00000},
+	}
+	for _, g := range golden64 {
+		if f, _ := NewFromFloat64(math.Float64frombits(g.in)); g.want != f.Bits() {
+			t.Errorf("0x%016X: float64 conversion mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, f.Bits())
+		}
+	}
+	// The payload is extended with zero bits.
+	golden := []struct {
+		in   uint32
+		want uint64
+	}{
+		{in: 0x7FC00000, want: 0x7FF8000000000000},
+		{in: 0xFFD54000, want: 0xFFFAA80000000000},
+		{in: 0x7F802000, want: 0x7FF8040000000000},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.in)
+		if x, _ := f.Float64(); math.Float64bits(x) != g.want {
+			t.Errorf("0x%08X: Float64 mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, math.Float64bits(x))
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package tf32 implements encoding and decoding of NVIDIA TensorFloat-32
+// floating-point numbers.
+//
+// TensorFloat-32 has the 8-bit exponent of single precision and a 10-bit
+// fraction, and is stored as a float32 with the lower 13 bits of the fraction
+// cleared.
+//
+// https://en.wikipedia.org/wiki/TensorFloat-32
+package tf32
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/ieee754"
+	"github.com/mewmew/float/internal/payload"
+	"github.com/mewmew/float/internal/rounding"
+)
+
+const (
+	// precision specifies the number of bits in the mantissa (including the
+	// implicit lead bit).
+	precision = 11
+	// exponent bias.
+	bias = 127
+	// unused specifies the number of unused lower bits of the float32
+	// representation.
+	unused = 13
+)
+
+// format is the TensorFloat-32 format, without the unused lower bits.
+var format = ieee754.Format{ExpBits: 8, FracBits: precision - 1, Bias: bias, Special: ieee754.InfNaN}
+
+// Positive and negative Not-a-Number, infinity and zero.
+var (
+	// +NaN
+	NaN = Float{bits: 0x7FC00000}
+	// -NaN
+	NegNaN = Float{bits: 0xFFC00000}
+	// +Inf
+	Inf = Float{bits: 0x7F800000}
+	// -Inf
+	NegInf = Float{bits: 0xFF800000}
+	// +zero
+	Zero = Float{bits: 0x00000000}
+	// -zero
+	NegZero = Float{bits: 0x80000000}
+)
+
+// Float is a floating-point number in TensorFloat-32 format.
+type Float struct {
+	// Sign, exponent and fraction.
+	//
+	//    1 bit:   sign
+	//    8 bits:  exponent
+	//    10 bits: fraction
+	//    13 bits: unused (zero)
+	bits uint32
+}
+
+// NewFromBits returns the floating-point number corresponding to the
+// TensorFloat-32 binary representation. The lower 13 bits are cleared; use
+// NewFromFloat32 to convert float32 representations, as for the float32 NaN
+// 0x7F800001, which would otherwise become +Inf.
+func NewFromBits(bits uint32) Float {
+	return Float{bits: bits &^ (1<<unused - 1)}
+}
+
+// NewFromFloat32 returns the nearest TensorFloat-32 floating-point number for x
+// and the accuracy of the conversion, using round half to even. Not-a-Number
+// values are converted to a quiet NaN with the same sign, keeping the most
+// significant bits of the payload.
+func NewFromFloat32(x float32) (Float, big.Accuracy) {
+	// +-NaN
+	if x != x {
+		return newNaN(payload.FromFloat32(x)), big.Exact
+	}
+	// TensorFloat-32 has the same exponent range as float32, so rounding the
+	// upper 19 bits of the float32 representation also handles denormalized
+	// numbers, and a carry out of the fraction into the exponent overflows to
+	// +-Inf.
+	bits := math.Float32bits(x)
+	lsb := bits >> unused & 1
+	rounded := (bits + 1<<(unused-1) - 1 + lsb) &^ (1<<unused - 1)
+	return Float{bits: rounded}, accuracy(bits, rounded)
+}
+
+// accuracy returns the accuracy of rounding the non-NaN float32 representation
+// bits to result.
+func accuracy(bits, result uint32) big.Accuracy {
+	acc := big.Exact
+	switch {
+	case result&0x7FFFFFFF > bits&0x7FFFFFFF:
+		acc = big.Above
+	case result&0x7FFFFFFF < bits&0x7FFFFFFF:
+		acc = big.Below
+	}
+	if bits&0x80000000 != 0 {
+		return -acc
+	}
+	return acc
+}
+
+// NewFromFloat64 returns the nearest TensorFloat-32 floating-point number for x
+// and the accuracy of the conversion, using round half to even. Not-a-Number
+// values are converted to a quiet NaN with the same sign, keeping the most
+// significant bits of the payload.
+func NewFromFloat64(x float64) (Float, big.Accuracy) {
+	// +-NaN
+	if math.IsNaN(x) {
+		return newNaN(payload.FromFloat64(x)), big.Exact
+	}
+	// Rounding through float32 would round twice, so use big.Float.
+	return NewFromBig(big.NewFloat(x))
+}
+
+// NewFromBig returns the nearest TensorFloat-32 floating-point number for x and
+// the accuracy of the conversion, using round half to even.
+func NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	return new(Context).NewFromBig(x)
+}
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised. Conversions
+// from TensorFloat-32 are exact, and are performed without a context.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// NewFromFloat32 returns the nearest TensorFloat-32 floating-point number for x
+// and the accuracy of the conversion, as for NewFromFloat32. The exception
+// flags raised are recorded in ctx; a signaling NaN raises the invalid
+// operation exception.
+func (ctx *Context) NewFromFloat32(x float32) (Float, big.Accuracy) {
+	f, acc := NewFromFloat32(x)
+	switch {
+	case x != x:
+		if payload.Signaling32(x) {
+			ctx.Flags |= fenv.Invalid
+		}
+	case acc != big.Exact:
+		ctx.Flags |= rounding.Flags(big.NewFloat(float64(x)), acc, precision, 1-bias, bias, big.ToNearestEven, ctx.Tininess)
+	}
+	return f, acc
+}
+
+// NewFromFloat64 returns the nearest TensorFloat-32 floating-point number for x
+// and the accuracy of the conversion, as for NewFromFloat64. The exception
+// flags raised are recorded in ctx; a signaling NaN raises the invalid
+// operation exception.
+func (ctx *Context) NewFromFloat64(x float64) (Float, big.Accuracy) {
+	if math.IsNaN(x) {
+		if math.Float64bits(x)&0x0008000000000000 == 0 {
+			ctx.Flags |= fenv.Invalid
+		}
+		return NewFromFloat64(x)
+	}
+	return ctx.NewFromBig(big.NewFloat(x))
+}
+
+// NewFromBig returns the nearest TensorFloat-32 floating-point number for x and
+// the accuracy of the conversion, using round half to even. The exception flags
+// raised are recorded in ctx.
+func (ctx *Context) NewFromBig(x *big.Float) (Float, big.Accuracy) {
+	c := &ieee754.Context{Tininess: ctx.Tininess}
+	bits, acc := c.Encode(format, x)
+	ctx.Flags |= c.Flags
+	return Float{bits: uint32(bits.Uint64()) << unused}, acc
+}
+
+// Bits returns the TensorFloat-32 binary representation of f, with the lower
+// 13 bits cleared.
+func (f Float) Bits() uint32 {
+	return f.bits
+}
+
+// Float32 returns the float32 representation of f. The conversion is always
+// exact, as TensorFloat-32 numbers are float32 numbers with the lower 13 bits
+// of the fraction cleared; Not-a-Number values keep their sign and payload.
+func (f Float) Float32() (float32, big.Accuracy) {
+	return math.Float32frombits(f.bits), big.Exact
+}
+
+// Float64 returns the float64 representation of f. The conversion is always
+// exact; Not-a-Number values are converted to a quiet NaN with the same sign
+// and payload.
+func (f Float) Float64() (float64, big.Accuracy) {
+	if f.isNaN() {
+		return payload.Float64(f.Signbit(), uint64(f.Frac())<<54), big.Exact
+	}
+	x, _ := f.Float32()
+	return float64(x), big.Exact
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is Not-a-Number. The conversion is exact.
+func (f Float) Big() (x *big.Float, nan bool) {
+	return format.Decode(big.NewInt(int64(f.bits >> unused)))
+}
+
+// Signbit reports whether f is negative or negative 0.
+func (f Float) Signbit() bool {
+	// first bit is sign bit: 0x80000000
+	return f.bits&0x80000000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f Float) Exp() int {
+	// 8 bit exponent: 0x7F800000
+	return int(f.bits & 0x7F800000 >> 23)
+}
+
+// Frac returns the fraction of f.
+func (f Float) Frac() uint16 {
+	// 10 bit fraction: 0x007FE000
+	return uint16(f.bits & 0x007FE000 >> unused)
+}
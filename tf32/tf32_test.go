@@ -0,0 +1,219 @@
+package tf32
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestNewFromBits(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		want float64
+	}{
+		// Special numbers.
+		// 0 00000000 0000000000 = 0
+		{bits: 0, want: 0},
+		// 1 00000000 0000000000 = -0
+		{bits: 0x80000000, want: 1. / math.Inf(-1)},
+		// 0 11111111 0000000000 = +Inf
+		{bits: 0x7F800000, want: math.Inf(1)},
+		// 1 11111111 0000000000 = -Inf
+		{bits: 0xFF800000, want: math.Inf(-1)},
+		// 0 11111111 0000000001 = +NaN
+		{bits: 0x7F802000, want: math.NaN()},
+		// 1 11111111 0000000001 = -NaN
+		{bits: 0xFF802000, want: -math.NaN()},
+
+		{bits: 0x3F800000, want: 1},
+		{bits: 0xC0000000, want: -2},
+		// 0 10000000 1001001000 = 3.140625
+		{bits: 0x40490000, want: 3.140625},
+		// 0 01111101 0101010101 = 0.333251953125
+		{bits: 0x3EAAA000, want: 0.333251953125},
+		// Lower 13 bits are cleared.
+		{bits: 0x3F801FFF, want: 1},
+		// 0 11111110 1111111111 = largest normal number
+		{bits: 0x7F7FE000, want: 0x1.ffcp127},
+		// 0 00000000 0000000001 = smallest denormalized number
+		{bits: 0x00002000, want: 0x1p-136},
+	}
+	for _, g := range golden {
+		f := NewFromBits(g.bits)
+		b, isNan := f.Big()
+		got, _ := b.Float64()
+		if isNan {
+			got = g.want
+		}
+		wantBits := math.Float64bits(g.want)
+		gotBits := math.Float64bits(got)
+		if wantBits != gotBits {
+			t.Errorf("0x%08X: number mismatch; expected 0x%016X (%v), got 0x%016X (%v)", g.bits, wantBits, g.want, gotBits, got)
+		}
+	}
+}
+
+func TestNewFromFloat32(t *testing.T) {
+	golden := []struct {
+		in   float32
+		want uint32
+		acc  big.Accuracy
+	}{
+		// Special numbers.
+		{in: float32(math.NaN()), want: 0x7FC00000, acc: big.Exact},
+		{in: float32(math.Inf(1)), want: 0x7F800000, acc: big.Exact},
+		{in: float32(math.Inf(-1)), want: 0xFF800000, acc: big.Exact},
+		{in: float32(math.Copysign(0, -1)), want: 0x80000000, acc: big.Exact},
+		// 1 = 0x3F800000
+		{in: 1, want: 0x3F800000, acc: big.Exact},
+		// pi = 0x40490FDB
+		{in: math.Pi, want: 0x40490000, acc: big.Below},
+		// -1/3 = 0xBEAAAAAB
+		{in: -1. / 3, want: 0xBEAAA000, acc: big.Above},
+		// 1 + 2^(-11) = 0x3F801000 (tie, round to even)
+		{in: 1 + 1./2048, want: 0x3F800000, acc: big.Below},
+		// 1 + 3*2^(-11) = 0x3F803000 (tie, round to even)
+		{in: 1 + 3./2048, want: 0x3F804000, acc: big.Above},
+		// max float32 = 0x7F7FFFFF
+		{in: math.MaxFloat32, want: 0x7F800000, acc: big.Above},
+		// -max float32 = 0xFF7FFFFF
+		{in: -math.MaxFloat32, want: 0xFF800000, acc: big.Below},
+		// smallest denormalized float32 = 0x00000001
+		{in: math.SmallestNonzeroFloat32, want: 0x00000000, acc: big.Below},
+		// largest denormalized float32 = 0x007FFFFF
+		{in: math.Float32frombits(0x007FFFFF), want: 0x00800000, acc: big.Above},
+		// 2^(-136) = 0x00002000 (smallest denormalized TensorFloat-32)
+		{in: math.Float32frombits(0x00002000), want: 0x00002000, acc: big.Exact},
+	}
+	for _, g := range golden {
+		got, acc := NewFromFloat32(g.in)
+		if g.want != got.Bits() {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestNewFromFloat64(t *testing.T) {
+	// Every float32 is converted the same way from float32, from float64 and
+	// from big.Float.
+	for bits := uint64(0); bits <= math.MaxUint32; bits += 0x1235 {
+		x := math.Float32frombits(uint32(bits))
+		if x != x {
+			continue
+		}
+		want, wantAcc := NewFromFloat32(x)
+		got, acc := NewFromFloat64(float64(x))
+		if want != got || wantAcc != acc {
+			t.Errorf("%v: mismatch; expected 0x%08X (%v), got 0x%08X (%v)", x, want.Bits(), wantAcc, got.Bits(), acc)
+		}
+	}
+	golden := []struct {
+		in   float64
+		want uint32
+		acc  big.Accuracy
+	}{
+		// 1 + 2^(-11) + 2^(-40) rounds up, but would round to even through
+		// float32.
+		{in: 1 + 1./2048 + math.Ldexp(1, -40), want: 0x3F802000, acc: big.Above},
+		// 2^(-137) = +0 (tie, round to even)
+		{in: math.Ldexp(1, -137), want: 0x00000000, acc: big.Below},
+		// -2^(-137) * 1.5 = -2^(-136)
+		{in: -math.Ldexp(1.5, -137), want: 0x80002000, acc: big.Below},
+		// 2^(-200) = +0
+		{in: math.Ldexp(1, -200), want: 0x00000000, acc: big.Below},
+		// 2^128 = +Inf
+		{in: math.Ldexp(1, 128), want: 0x7F800000, acc: big.Above},
+	}
+	for _, g := range golden {
+		got, acc := NewFromFloat64(g.in)
+		if g.want != got.Bits() {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, got.Bits())
+		}
+		if g.acc != acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestConversionFlags(t *testing.T) {
+	golden := []struct {
+		in            float32
+		want          uint32
+		after, before fenv.Flags
+	}{
+		{in: 1, want: 0x3F800000},
+		{in: 1 + 0x1p-11, want: 0x3F800000, after: fenv.Inexact, before: fenv.Inexact},
+		// Overflow.
+		{in: math.MaxFloat32, want: 0x7F800000, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		// Underflow.
+		{in: 0x1p-137, want: 0x00000000, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{in: 0x1p-136, want: 0x00002000},
+		// 2^(-126) - 2^(-138), tiny only before rounding.
+		{in: 0x1p-126 - 0x1p-138, want: 0x00800000, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// Signaling and quiet NaN.
+		{in: math.Float32frombits(0x7F800001), want: 0x7FC00000, after: fenv.Invalid, before: fenv.Invalid},
+		{in: math.Float32frombits(0x7FC00000), want: 0x7FC00000},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			got, _ := ctx.NewFromFloat32(g.in)
+			if g.want != got.Bits() {
+				t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, got.Bits())
+			}
+			if want != ctx.Flags {
+				t.Errorf("%v (%v): flags mismatch; expected %v, got %v", g.in, tininess, want, ctx.Flags)
+			}
+		}
+	}
+	// The fast conversion of float32 agrees with the conversion of float64, for
+	// which the quiet bit of NaN values is not preserved on all architectures.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x := math.Float32frombits(r.Uint32())
+		if x != x {
+			continue
+		}
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			ctx32 := &Context{Tininess: tininess}
+			ctx32.NewFromFloat32(x)
+			ctx64 := &Context{Tininess: tininess}
+			ctx64.NewFromFloat64(float64(x))
+			if ctx32.Flags != ctx64.Flags {
+				t.Errorf("%v (%v): flags mismatch; expected %v, got %v", x, tininess, ctx64.Flags, ctx32.Flags)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for i := uint32(0); i < 1<<19; i++ {
+		f := NewFromBits(i << 13)
+		x, _ := f.Float64()
+		if math.IsNaN(x) {
+			continue
+		}
+		y, nan := f.Big()
+		if nan {
+			t.Errorf("0x%08X: unexpected NaN", f.Bits())
+			continue
+		}
+		if z, _ := y.Float64(); z != x || math.Signbit(z) != math.Signbit(x) {
+			t.Errorf("0x%08X: Big and Float64 mismatch; %v != %v", f.Bits(), z, x)
+		}
+		got, acc := NewFromBig(y)
+		if f != got || acc != big.Exact {
+			t.Errorf("0x%08X: round-trip mismatch; got 0x%08X (%v)", f.Bits(), got.Bits(), acc)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package vax
+
+import (
+	"github.com/mewmew/float"
+	"github.com/mewmew/float/internal/order"
+)
+
+// ### [ F ] ###################################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. The
+// reserved operand is considered less than any number and equal to the reserved
+// operand, as NaN by cmp.Compare.
+func (f F) Cmp(g F) int {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	return order.Cmp(x, xres, y, yres)
+}
+
+// Less reports whether f < g. It is false if f or g is the reserved operand.
+func (f F) Less(g F) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is the reserved operand.
+func (f F) Equal(g F) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with zeros of any fraction ordered
+// as equal. The reserved operand is ordered as a negative signaling NaN; before
+// any number, and by fraction in reverse.
+func (f F) TotalOrder(g F) bool {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	cmp := 0
+	switch {
+	case xres && yres:
+		cmp = order.Payload(uint64(f.Frac()), uint64(g.Frac()))
+	case !xres && !yres:
+		cmp = x.Cmp(y)
+	}
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), cmp)
+}
+
+// Class returns the class of f. The reserved operand, which traps on use, is a
+// signaling NaN.
+func (f F) Class() float.Class {
+	x, reserved := f.Big()
+	if reserved {
+		return float.SignalingNaN
+	}
+	return order.Class(x, fFormat.minExp()-1)
+}
+
+// IsNaN reports whether f is the reserved operand.
+func (f F) IsNaN() bool {
+	return f.Exp() == 0 && f.Signbit()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as VAX has no
+// infinities.
+func (f F) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as VAX has
+// no subnormal numbers.
+func (f F) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is not the reserved operand.
+func (f F) IsFinite() bool {
+	return !f.IsNaN()
+}
+
+// IsZero reports whether f is zero, with any fraction.
+func (f F) IsZero() bool {
+	return f.Exp() == 0 && !f.Signbit()
+}
+
+// ### [ D ] ###################################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. The
+// reserved operand is considered less than any number and equal to the reserved
+// operand, as NaN by cmp.Compare.
+func (f D) Cmp(g D) int {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	return order.Cmp(x, xres, y, yres)
+}
+
+// Less reports whether f < g. It is false if f or g is the reserved operand.
+func (f D) Less(g D) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is the reserved operand.
+func (f D) Equal(g D) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with zeros of any fraction ordered
+// as equal. The reserved operand is ordered as a negative signaling NaN; before
+// any number, and by fraction in reverse.
+func (f D) TotalOrder(g D) bool {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	cmp := 0
+	switch {
+	case xres && yres:
+		cmp = order.Payload(f.Frac(), g.Frac())
+	case !xres && !yres:
+		cmp = x.Cmp(y)
+	}
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), cmp)
+}
+
+// Class returns the class of f. The reserved operand, which traps on use, is a
+// signaling NaN.
+func (f D) Class() float.Class {
+	x, reserved := f.Big()
+	if reserved {
+		return float.SignalingNaN
+	}
+	return order.Class(x, dFormat.minExp()-1)
+}
+
+// IsNaN reports whether f is the reserved operand.
+func (f D) IsNaN() bool {
+	return f.Exp() == 0 && f.Signbit()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as VAX has no
+// infinities.
+func (f D) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as VAX has
+// no subnormal numbers.
+func (f D) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is not the reserved operand.
+func (f D) IsFinite() bool {
+	return !f.IsNaN()
+}
+
+// IsZero reports whether f is zero, with any fraction.
+func (f D) IsZero() bool {
+	return f.Exp() == 0 && !f.Signbit()
+}
+
+// ### [ G ] ###################################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. The
+// reserved operand is considered less than any number and equal to the reserved
+// operand, as NaN by cmp.Compare.
+func (f G) Cmp(g G) int {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	return order.Cmp(x, xres, y, yres)
+}
+
+// Less reports whether f < g. It is false if f or g is the reserved operand.
+func (f G) Less(g G) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is the reserved operand.
+func (f G) Equal(g G) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with zeros of any fraction ordered
+// as equal. The reserved operand is ordered as a negative signaling NaN; before
+// any number, and by fraction in reverse.
+func (f G) TotalOrder(g G) bool {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	cmp := 0
+	switch {
+	case xres && yres:
+		cmp = order.Payload(f.Frac(), g.Frac())
+	case !xres && !yres:
+		cmp = x.Cmp(y)
+	}
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), cmp)
+}
+
+// Class returns the class of f. The reserved operand, which traps on use, is a
+// signaling NaN.
+func (f G) Class() float.Class {
+	x, reserved := f.Big()
+	if reserved {
+		return float.SignalingNaN
+	}
+	return order.Class(x, gFormat.minExp()-1)
+}
+
+// IsNaN reports whether f is the reserved operand.
+func (f G) IsNaN() bool {
+	return f.Exp() == 0 && f.Signbit()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as VAX has no
+// infinities.
+func (f G) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as VAX has
+// no subnormal numbers.
+func (f G) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is not the reserved operand.
+func (f G) IsFinite() bool {
+	return !f.IsNaN()
+}
+
+// IsZero reports whether f is zero, with any fraction.
+func (f G) IsZero() bool {
+	return f.Exp() == 0 && !f.Signbit()
+}
+
+// ### [ H ] ###################################################################
+
+// Cmp compares f and g and returns -1 if f < g, 0 if f == g and +1 if f > g. The
+// reserved operand is considered less than any number and equal to the reserved
+// operand, as NaN by cmp.Compare.
+func (f H) Cmp(g H) int {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	return order.Cmp(x, xres, y, yres)
+}
+
+// Less reports whether f < g. It is false if f or g is the reserved operand.
+func (f H) Less(g H) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) < 0
+}
+
+// Equal reports whether f == g. It is false if f or g is the reserved operand.
+func (f H) Equal(g H) bool {
+	return !f.IsNaN() && !g.IsNaN() && f.Cmp(g) == 0
+}
+
+// TotalOrder reports whether f is ordered before or equal to g in the total
+// order of IEEE 754 (totalOrder); by value, with zeros of any fraction ordered
+// as equal. The reserved operand is ordered as a negative signaling NaN; before
+// any number, and by fraction in reverse.
+func (f H) TotalOrder(g H) bool {
+	x, xres := f.Big()
+	y, yres := g.Big()
+	cmp := 0
+	switch {
+	case xres && yres:
+		fhi, flo := f.Frac()
+		ghi, glo := g.Frac()
+		if cmp = order.Payload(fhi, ghi); cmp == 0 {
+			cmp = order.Payload(flo, glo)
+		}
+	case !xres && !yres:
+		cmp = x.Cmp(y)
+	}
+	return order.Total(f.Class(), g.Class(), f.Signbit(), g.Signbit(), cmp)
+}
+
+// Class returns the class of f. The reserved operand, which traps on use, is a
+// signaling NaN.
+func (f H) Class() float.Class {
+	x, reserved := f.Big()
+	if reserved {
+		return float.SignalingNaN
+	}
+	return order.Class(x, hFormat.minExp()-1)
+}
+
+// IsNaN reports whether f is the reserved operand.
+func (f H) IsNaN() bool {
+	return f.Exp() == 0 && f.Signbit()
+}
+
+// IsInf reports whether f is +Inf or -Inf; always false, as VAX has no
+// infinities.
+func (f H) IsInf() bool {
+	return false
+}
+
+// IsSubnormal reports whether f is a subnormal number; always false, as VAX has
+// no subnormal numbers.
+func (f H) IsSubnormal() bool {
+	return false
+}
+
+// IsFinite reports whether f is not the reserved operand.
+func (f H) IsFinite() bool {
+	return !f.IsNaN()
+}
+
+// IsZero reports whether f is zero, with any fraction.
+func (f H) IsZero() bool {
+	return f.Exp() == 0 && !f.Signbit()
+}
@@ -0,0 +1,156 @@
+package vax
+
+import (
+	"testing"
+
+	"github.com/mewmew/float"
+)
+
+func TestClassF(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint32
+		class float.Class
+	}{
+		{bits: 0x807FFFFF, class: float.SignalingNaN},
+		{bits: 0x80000000, class: float.SignalingNaN},
+		{bits: 0xFFFFFFFF, class: float.NegativeNormal},
+		{bits: 0xC0800000, class: float.NegativeNormal},
+		{bits: 0x80800000, class: float.NegativeNormal},
+		{bits: 0x00000000, class: float.PositiveZero},
+		{bits: 0x00800000, class: float.PositiveNormal},
+		{bits: 0x40800000, class: float.PositiveNormal},
+		{bits: 0x7FFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewFFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%08X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%08X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestClassD(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint64
+		class float.Class
+	}{
+		{bits: 0x807FFFFFFFFFFFFF, class: float.SignalingNaN},
+		{bits: 0x8000000000000000, class: float.SignalingNaN},
+		{bits: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{bits: 0xC080000000000000, class: float.NegativeNormal},
+		{bits: 0x8080000000000000, class: float.NegativeNormal},
+		{bits: 0x0000000000000000, class: float.PositiveZero},
+		{bits: 0x0080000000000000, class: float.PositiveNormal},
+		{bits: 0x4080000000000000, class: float.PositiveNormal},
+		{bits: 0x7FFFFFFFFFFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewDFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestClassG(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		bits  uint64
+		class float.Class
+	}{
+		{bits: 0x800FFFFFFFFFFFFF, class: float.SignalingNaN},
+		{bits: 0x8000000000000000, class: float.SignalingNaN},
+		{bits: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{bits: 0xC010000000000000, class: float.NegativeNormal},
+		{bits: 0x8010000000000000, class: float.NegativeNormal},
+		{bits: 0x0000000000000000, class: float.PositiveZero},
+		{bits: 0x0010000000000000, class: float.PositiveNormal},
+		{bits: 0x4010000000000000, class: float.PositiveNormal},
+		{bits: 0x7FFFFFFFFFFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewGFromBits(a.bits)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X: class mismatch; expected %v, got %v", a.bits, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X: predicate mismatch for class %v", a.bits, a.class)
+		}
+	}
+}
+
+func TestClassH(t *testing.T) {
+	// Encodings at the boundaries of each class.
+	golden := []struct {
+		a     uint64
+		b     uint64
+		class float.Class
+	}{
+		{a: 0x8000FFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.SignalingNaN},
+		{a: 0x8000000000000000, b: 0x0000000000000000, class: float.SignalingNaN},
+		{a: 0xFFFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.NegativeNormal},
+		{a: 0xC001000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x8001000000000000, b: 0x0000000000000000, class: float.NegativeNormal},
+		{a: 0x0000000000000000, b: 0x0000000000000000, class: float.PositiveZero},
+		{a: 0x0001000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x4001000000000000, b: 0x0000000000000000, class: float.PositiveNormal},
+		{a: 0x7FFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF, class: float.PositiveNormal},
+	}
+	for _, a := range golden {
+		f := NewHFromBits(a.a, a.b)
+		if got := f.Class(); a.class != got {
+			t.Errorf("0x%016X 0x%016X: class mismatch; expected %v, got %v", a.a, a.b, a.class, got)
+		}
+		if f.IsNaN() != a.class.IsNaN() || f.IsInf() != a.class.IsInf() || f.IsSubnormal() != a.class.IsSubnormal() || f.IsFinite() != a.class.IsFinite() || f.IsZero() != a.class.IsZero() {
+			t.Errorf("0x%016X 0x%016X: predicate mismatch for class %v", a.a, a.b, a.class)
+		}
+	}
+}
+
+func TestCmpF(t *testing.T) {
+	// Comparison of reserved operands, which have the sign bit set, and of the
+	// numbers next to them.
+	golden := []struct {
+		x, y  uint32
+		cmp   int
+		total bool
+	}{
+		// Reserved operand and the smallest number.
+		{x: 0x80000000, y: 0xFFFFFFFF, cmp: -1, total: true},
+		{x: 0xFFFFFFFF, y: 0x80000000, cmp: +1, total: false},
+		// Reserved operands; ordered in reverse of their payload.
+		{x: 0x807FFFFF, y: 0x80000000, cmp: 0, total: true},
+		{x: 0x80000000, y: 0x807FFFFF, cmp: 0, total: false},
+		// Smallest negative and positive number and zero.
+		{x: 0x80800000, y: 0x00000000, cmp: -1, total: true},
+		{x: 0x00000000, y: 0x00800000, cmp: -1, total: true},
+	}
+	for _, g := range golden {
+		x, y := NewFFromBits(g.x), NewFFromBits(g.y)
+		if got := x.Cmp(y); g.cmp != got {
+			t.Errorf("0x%08X.Cmp(0x%08X): mismatch; expected %d, got %d", g.x, g.y, g.cmp, got)
+		}
+		if got := x.TotalOrder(y); g.total != got {
+			t.Errorf("0x%08X.TotalOrder(0x%08X): mismatch; expected %v, got %v", g.x, g.y, g.total, got)
+		}
+	}
+}
+
+func TestCmpDirtyZero(t *testing.T) {
+	// Zero with a non-zero fraction.
+	f := NewFFromBits(0x00000001)
+	if got := f.Class(); got != float.PositiveZero {
+		t.Errorf("0x%08X: class mismatch; expected %v, got %v", f.Bits(), float.PositiveZero, got)
+	}
+	if !f.Equal(FZero) || !f.TotalOrder(FZero) || !FZero.TotalOrder(f) {
+		t.Errorf("0x%08X: expected equal to zero", f.Bits())
+	}
+}
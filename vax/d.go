@@ -0,0 +1,179 @@
+package vax
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Positive and negative largest number, zero and reserved operand of the
+// D_floating format.
+var (
+	// +0x0.FFFFFFFFFFFFFFp127 (about 1.701412e+38)
+	DMax = D{bits: 0x7FFFFFFFFFFFFFFF}
+	// -0x0.FFFFFFFFFFFFFFp127
+	DNegMax = D{bits: 0xFFFFFFFFFFFFFFFF}
+	// zero
+	DZero = D{bits: 0x0000000000000000}
+	// reserved operand
+	DReserved = D{bits: 0x8000000000000000}
+)
+
+// D is a floating-point number in VAX D_floating format.
+type D struct {
+	// Sign, exponent and fraction, in logical order.
+	//
+	//    1 bit:   sign
+	//    8 bits:  exponent
+	//    55 bits: fraction
+	bits uint64
+}
+
+// NewDFromBits returns the floating-point number corresponding to the
+// D_floating binary representation, in logical order.
+func NewDFromBits(bits uint64) D {
+	return D{bits: bits}
+}
+
+// NewDFromBytes returns the floating-point number corresponding to the
+// D_floating memory layout; four 16-bit little-endian words, starting with the
+// word holding the sign and exponent.
+func NewDFromBytes(b [8]byte) D {
+	return D{bits: fromMemory(b[:]).Uint64()}
+}
+
+// NewDFromFloat32 returns the nearest D_floating number for x and the accuracy
+// of the conversion, as for NewDFromBig. NaN is converted to the reserved
+// operand.
+func NewDFromFloat32(x float32) (D, big.Accuracy) {
+	return new(Context).NewDFromFloat32(x)
+}
+
+// NewDFromFloat64 returns the nearest D_floating number for x and the accuracy
+// of the conversion, as for NewDFromBig. NaN is converted to the reserved
+// operand.
+func NewDFromFloat64(x float64) (D, big.Accuracy) {
+	return new(Context).NewDFromFloat64(x)
+}
+
+// NewDFromBig returns the nearest D_floating number for x and the accuracy of
+// the conversion, using round half away from zero as VAX hardware. Values too
+// small in magnitude are converted to zero or to the smallest number of the same
+// sign, whichever is nearest, and values too large in magnitude, including
+// +-Inf, to the reserved operand (reported as Above for positive and Below for
+// negative values).
+func NewDFromBig(x *big.Float) (D, big.Accuracy) {
+	return new(Context).NewDFromBig(x)
+}
+
+// NewDFromFloat32 returns the nearest D_floating number for x and the accuracy
+// of the conversion, as for NewDFromFloat32. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewDFromFloat32(x float32) (D, big.Accuracy) {
+	return ctx.NewDFromFloat64(float64(x))
+}
+
+// NewDFromFloat64 returns the nearest D_floating number for x and the accuracy
+// of the conversion, as for NewDFromFloat64. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewDFromFloat64(x float64) (D, big.Accuracy) {
+	if ctx.nan(x) {
+		return DReserved, big.Exact
+	}
+	return ctx.NewDFromBig(big.NewFloat(x))
+}
+
+// NewDFromBig returns the nearest D_floating number for x and the accuracy of
+// the conversion, as for NewDFromBig. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) NewDFromBig(x *big.Float) (D, big.Accuracy) {
+	bits, acc := ctx.encode(dFormat, x)
+	return D{bits: bits.Uint64()}, acc
+}
+
+// Bits returns the D_floating binary representation of f, in logical order.
+func (f D) Bits() uint64 {
+	return f.bits
+}
+
+// Bytes returns the D_floating memory layout of f; four 16-bit little-endian
+// words, starting with the word holding the sign and exponent.
+func (f D) Bytes() [8]byte {
+	var b [8]byte
+	copy(b[:], toMemory(new(big.Int).SetUint64(f.bits), 64))
+	return b
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion. The reserved operand is converted to NaN.
+func (f D) Float32() (float32, big.Accuracy) {
+	return new(Context).DFloat32(f)
+}
+
+// DFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) DFloat32(f D) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion. The reserved operand is converted to NaN.
+func (f D) Float64() (float64, big.Accuracy) {
+	return new(Context).DFloat64(f)
+}
+
+// DFloat64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) DFloat64(f D) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is the reserved operand.
+func (f D) Big() (x *big.Float, reserved bool) {
+	return dFormat.decode(new(big.Int).SetUint64(f.bits))
+}
+
+// Signbit reports whether f is negative or the reserved operand.
+func (f D) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x8000000000000000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f D) Exp() int {
+	// 8 bit exponent
+	return int(f.bits & 0x7F80000000000000 >> 55)
+}
+
+// Frac returns the fraction of f.
+func (f D) Frac() uint64 {
+	// 55 bit fraction
+	return f.bits & 0x007FFFFFFFFFFFFF
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary to identify f uniquely. The reserved operand is
+// represented as "NaN".
+func (f D) Text(fmt byte, prec int) string {
+	x, reserved := f.Big()
+	return dFormat.text(x, reserved, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which identifies f
+// uniquely, as for f.Text('g', -1).
+func (f D) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f D) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
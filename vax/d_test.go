@@ -0,0 +1,74 @@
+package vax
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewDFromBig(t *testing.T) {
+	pi, _, _ := big.ParseFloat("3.14159265358979323846264338327950288", 10, 200, big.ToNearestEven)
+	golden := []struct {
+		in   *big.Float
+		want uint64
+		acc  big.Accuracy
+	}{
+		{in: big.NewFloat(1), want: 0x4080000000000000, acc: big.Exact},
+		{in: big.NewFloat(-1), want: 0xC080000000000000, acc: big.Exact},
+		{in: pi, want: 0x41490FDAA22168C2, acc: big.Below},
+		// 1 + 2^-56 = 1 + 2^-55 (tie, round half away from zero)
+		{in: new(big.Float).SetMantExp(new(big.Float).SetInt64(0x100000000000001), -56), want: 0x4080000000000001, acc: big.Above},
+		{in: big.NewFloat(0x1p127), want: 0x8000000000000000, acc: big.Above},
+		{in: big.NewFloat(0x1p-129), want: 0x0080000000000000, acc: big.Above},
+		{in: big.NewFloat(0x1.FFFFFFFFFFFFFp-130), want: 0x0000000000000000, acc: big.Below},
+	}
+	for _, g := range golden {
+		f, acc := NewDFromBig(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+	if x, acc := NewDFromBits(0x41490FDAA22168C2).Float64(); x != math.Pi || acc != big.Below {
+		t.Errorf("float64 mismatch; expected %v, got %v (%v)", math.Pi, x, acc)
+	}
+}
+
+func TestDFloat64(t *testing.T) {
+	// float64 numbers within range are D_floating numbers with three more
+	// fraction bits.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		bits := r.Uint64()
+		exp := bits >> 52 & 0x7FF
+		if exp < 895 || exp > 1149 {
+			continue
+		}
+		x := math.Float64frombits(bits)
+		want := bits&0x8000000000000000 | (exp-894)<<55 | bits&0xFFFFFFFFFFFFF<<3
+		f, acc := NewDFromFloat64(x)
+		if f.Bits() != want || acc != big.Exact {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X (%v)", x, want, f.Bits(), acc)
+		}
+		if y, acc := f.Float64(); y != x || acc != big.Exact {
+			t.Errorf("0x%016X: float64 mismatch; expected %v, got %v (%v)", want, x, y, acc)
+		}
+	}
+}
+
+func TestDBytes(t *testing.T) {
+	f := NewDFromBits(0x41490FDAA22168C2)
+	mem := [8]byte{0x49, 0x41, 0xDA, 0x0F, 0x21, 0xA2, 0xC2, 0x68}
+	if got := f.Bytes(); got != mem {
+		t.Errorf("memory layout mismatch; expected % X, got % X", mem, got)
+	}
+	if got := NewDFromBytes(mem); got != f {
+		t.Errorf("bits mismatch; expected 0x%016X, got 0x%016X", f.Bits(), got.Bits())
+	}
+	if got, want := f.Text('g', -1), "3.1415926535897932"; got != want {
+		t.Errorf("text mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,179 @@
+package vax
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Positive and negative largest number, zero and reserved operand of the
+// F_floating format.
+var (
+	// +0x0.FFFFFFp127 (about 1.701412e+38)
+	FMax = F{bits: 0x7FFFFFFF}
+	// -0x0.FFFFFFp127
+	FNegMax = F{bits: 0xFFFFFFFF}
+	// zero
+	FZero = F{bits: 0x00000000}
+	// reserved operand
+	FReserved = F{bits: 0x80000000}
+)
+
+// F is a floating-point number in VAX F_floating format.
+type F struct {
+	// Sign, exponent and fraction, in logical order.
+	//
+	//    1 bit:   sign
+	//    8 bits:  exponent
+	//    23 bits: fraction
+	bits uint32
+}
+
+// NewFFromBits returns the floating-point number corresponding to the
+// F_floating binary representation, in logical order.
+func NewFFromBits(bits uint32) F {
+	return F{bits: bits}
+}
+
+// NewFFromBytes returns the floating-point number corresponding to the
+// F_floating memory layout; two 16-bit little-endian words, starting with the
+// word holding the sign and exponent.
+func NewFFromBytes(b [4]byte) F {
+	return F{bits: uint32(fromMemory(b[:]).Uint64())}
+}
+
+// NewFFromFloat32 returns the nearest F_floating number for x and the accuracy
+// of the conversion, as for NewFFromBig. NaN is converted to the reserved
+// operand.
+func NewFFromFloat32(x float32) (F, big.Accuracy) {
+	return new(Context).NewFFromFloat32(x)
+}
+
+// NewFFromFloat64 returns the nearest F_floating number for x and the accuracy
+// of the conversion, as for NewFFromBig. NaN is converted to the reserved
+// operand.
+func NewFFromFloat64(x float64) (F, big.Accuracy) {
+	return new(Context).NewFFromFloat64(x)
+}
+
+// NewFFromBig returns the nearest F_floating number for x and the accuracy of
+// the conversion, using round half away from zero as VAX hardware. Values too
+// small in magnitude are converted to zero or to the smallest number of the same
+// sign, whichever is nearest, and values too large in magnitude, including
+// +-Inf, to the reserved operand (reported as Above for positive and Below for
+// negative values).
+func NewFFromBig(x *big.Float) (F, big.Accuracy) {
+	return new(Context).NewFFromBig(x)
+}
+
+// NewFFromFloat32 returns the nearest F_floating number for x and the accuracy
+// of the conversion, as for NewFFromFloat32. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewFFromFloat32(x float32) (F, big.Accuracy) {
+	return ctx.NewFFromFloat64(float64(x))
+}
+
+// NewFFromFloat64 returns the nearest F_floating number for x and the accuracy
+// of the conversion, as for NewFFromFloat64. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewFFromFloat64(x float64) (F, big.Accuracy) {
+	if ctx.nan(x) {
+		return FReserved, big.Exact
+	}
+	return ctx.NewFFromBig(big.NewFloat(x))
+}
+
+// NewFFromBig returns the nearest F_floating number for x and the accuracy of
+// the conversion, as for NewFFromBig. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) NewFFromBig(x *big.Float) (F, big.Accuracy) {
+	bits, acc := ctx.encode(fFormat, x)
+	return F{bits: uint32(bits.Uint64())}, acc
+}
+
+// Bits returns the F_floating binary representation of f, in logical order.
+func (f F) Bits() uint32 {
+	return f.bits
+}
+
+// Bytes returns the F_floating memory layout of f; two 16-bit little-endian
+// words, starting with the word holding the sign and exponent.
+func (f F) Bytes() [4]byte {
+	var b [4]byte
+	copy(b[:], toMemory(big.NewInt(int64(f.bits)), 32))
+	return b
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion. The reserved operand is converted to NaN.
+func (f F) Float32() (float32, big.Accuracy) {
+	return new(Context).FFloat32(f)
+}
+
+// FFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) FFloat32(f F) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value of f. The conversion is always exact; the
+// reserved operand is converted to NaN.
+func (f F) Float64() (float64, big.Accuracy) {
+	return new(Context).FFloat64(f)
+}
+
+// FFloat64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) FFloat64(f F) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is the reserved operand.
+func (f F) Big() (x *big.Float, reserved bool) {
+	return fFormat.decode(big.NewInt(int64(f.bits)))
+}
+
+// Signbit reports whether f is negative or the reserved operand.
+func (f F) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x80000000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f F) Exp() int {
+	// 8 bit exponent
+	return int(f.bits & 0x7F800000 >> 23)
+}
+
+// Frac returns the fraction of f.
+func (f F) Frac() uint32 {
+	// 23 bit fraction
+	return f.bits & 0x007FFFFF
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary to identify f uniquely. The reserved operand is
+// represented as "NaN".
+func (f F) Text(fmt byte, prec int) string {
+	x, reserved := f.Big()
+	return fFormat.text(x, reserved, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which identifies f
+// uniquely, as for f.Text('g', -1).
+func (f F) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f F) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
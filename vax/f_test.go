@@ -0,0 +1,195 @@
+package vax
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewFFromBits(t *testing.T) {
+	golden := []struct {
+		bits     uint32
+		want     float64
+		reserved bool
+	}{
+		// Special numbers.
+		// 0 00000000 00000000000000000000000 = 0
+		{bits: 0x00000000, want: 0},
+		// 0 00000000 00000000000000000000001 = 0 (dirty zero)
+		{bits: 0x00000001, want: 0},
+		// 1 00000000 00000000000000000000000 = reserved operand
+		{bits: 0x80000000, reserved: true},
+		// 1 00000000 00000000001001000110100 = reserved operand
+		{bits: 0x80001234, reserved: true},
+
+		// 0 10000001 00000000000000000000000 = 1
+		{bits: 0x40800000, want: 1},
+		// 1 10000001 00000000000000000000000 = -1
+		{bits: 0xC0800000, want: -1},
+		// 0 10000010 11000000000000000000000 = 3.5
+		{bits: 0x41600000, want: 3.5},
+		// 0 10000010 10010010000111111011011 = pi (rounded)
+		{bits: 0x41490FDB, want: 0x1.921FB6p1},
+		// 0 11111111 11111111111111111111111 = largest number
+		{bits: 0x7FFFFFFF, want: 0x0.FFFFFFp127},
+		// 0 00000001 00000000000000000000000 = smallest number
+		{bits: 0x00800000, want: 0x1p-128},
+	}
+	for _, g := range golden {
+		f := NewFFromBits(g.bits)
+		x, reserved := f.Big()
+		if reserved != g.reserved {
+			t.Errorf("0x%08X: reserved operand mismatch; expected %v, got %v", g.bits, g.reserved, reserved)
+			continue
+		}
+		if reserved {
+			continue
+		}
+		got, acc := x.Float64()
+		if got != g.want || acc != big.Exact {
+			t.Errorf("0x%08X: number mismatch; expected %v, got %v (%v)", g.bits, g.want, got, acc)
+		}
+	}
+}
+
+func TestNewFFromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint32
+		acc  big.Accuracy
+	}{
+		// Special numbers.
+		{in: 0, want: 0x00000000, acc: big.Exact},
+		{in: math.Copysign(0, -1), want: 0x00000000, acc: big.Exact},
+		{in: math.NaN(), want: 0x80000000, acc: big.Exact},
+		{in: math.Inf(1), want: 0x80000000, acc: big.Above},
+		{in: math.Inf(-1), want: 0x80000000, acc: big.Below},
+
+		{in: 1, want: 0x40800000, acc: big.Exact},
+		{in: -3.5, want: 0xC1600000, acc: big.Exact},
+		{in: math.Pi, want: 0x41490FDB, acc: big.Above},
+		// 1 + 2^-24 = 1 + 2^-23 (tie, round half away from zero)
+		{in: 1 + 0x1p-24, want: 0x40800001, acc: big.Above},
+		{in: -1 - 0x1p-24, want: 0xC0800001, acc: big.Below},
+		// 1 + 3*2^-24 = 1 + 2^-22 (tie, round half away from zero)
+		{in: 1 + 0x3p-24, want: 0x40800002, acc: big.Above},
+
+		// Overflow.
+		{in: 0x1p127, want: 0x80000000, acc: big.Above},
+		{in: -0x0.FFFFFF8p127, want: 0x80000000, acc: big.Below},
+		{in: 0x0.FFFFFF7p127, want: 0x7FFFFFFF, acc: big.Below},
+
+		// Underflow; 2^-129 is halfway between zero and the smallest number,
+		// 2^-128.
+		{in: 0x1p-129, want: 0x00800000, acc: big.Above},
+		{in: -0x1p-129, want: 0x80800000, acc: big.Below},
+		{in: 0x0.FFFFFFFFFFFFF8p-129, want: 0x00000000, acc: big.Below},
+		{in: -0x1p-200, want: 0x00000000, acc: big.Above},
+		{in: 0x0.FFFFFF8p-128, want: 0x00800000, acc: big.Above},
+		{in: 0x0.FFFFFF7p-128, want: 0x00800000, acc: big.Above},
+		{in: 0x0.Cp-128, want: 0x00800000, acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc := NewFFromFloat64(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestFFloat32(t *testing.T) {
+	// Normal float32 numbers within range are F_floating numbers with an
+	// exponent larger by 2.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		bits := r.Uint32()
+		if exp := bits >> 23 & 0xFF; exp == 0 || exp > 0xFD {
+			continue
+		}
+		x := math.Float32frombits(bits)
+		want := bits + 2<<23
+		f, acc := NewFFromFloat32(x)
+		if f.Bits() != want || acc != big.Exact {
+			t.Errorf("%v: bits mismatch; expected 0x%08X, got 0x%08X (%v)", x, want, f.Bits(), acc)
+		}
+		if y, acc := f.Float32(); y != x || acc != big.Exact {
+			t.Errorf("0x%08X: float32 mismatch; expected %v, got %v (%v)", want, x, y, acc)
+		}
+	}
+}
+
+func TestFBytes(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		mem  [4]byte
+	}{
+		{bits: 0x40800000, mem: [4]byte{0x80, 0x40, 0x00, 0x00}},
+		{bits: 0xC0800000, mem: [4]byte{0x80, 0xC0, 0x00, 0x00}},
+		{bits: 0x41490FDB, mem: [4]byte{0x49, 0x41, 0xDB, 0x0F}},
+		{bits: 0x00000001, mem: [4]byte{0x00, 0x00, 0x01, 0x00}},
+	}
+	for _, g := range golden {
+		f := NewFFromBits(g.bits)
+		if got := f.Bytes(); got != g.mem {
+			t.Errorf("0x%08X: memory layout mismatch; expected % X, got % X", g.bits, g.mem, got)
+		}
+		if got := NewFFromBytes(g.mem); got != f {
+			t.Errorf("% X: bits mismatch; expected 0x%08X, got 0x%08X", g.mem, g.bits, got.Bits())
+		}
+	}
+}
+
+func TestFText(t *testing.T) {
+	golden := []struct {
+		bits uint32
+		fmt  byte
+		prec int
+		want string
+	}{
+		{bits: 0x00000000, fmt: 'g', prec: -1, want: "0"},
+		{bits: 0x80000000, fmt: 'g', prec: -1, want: "NaN"},
+		{bits: 0x40800000, fmt: 'g', prec: -1, want: "1"},
+		{bits: 0x41490FDB, fmt: 'g', prec: -1, want: "3.1415927"},
+		{bits: 0x41490FDB, fmt: 'x', prec: -1, want: "0x1.921fb6p+01"},
+		{bits: 0x7FFFFFFF, fmt: 'g', prec: -1, want: "1.7014117e+38"},
+		{bits: 0x00800000, fmt: 'g', prec: -1, want: "2.938736e-39"},
+		{bits: 0x00800000, fmt: 'b', prec: -1, want: "1p-128"},
+	}
+	for _, g := range golden {
+		f := NewFFromBits(g.bits)
+		if got := f.Text(g.fmt, g.prec); got != g.want {
+			t.Errorf("0x%08X %c %d: text mismatch; expected %q, got %q", g.bits, g.fmt, g.prec, g.want, got)
+		}
+	}
+	if got, want := fmt.Sprintf("%+.2f", NewFFromBits(0x41600000)), "+3.50"; got != want {
+		t.Errorf("output mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestFRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		f := NewFFromBits(r.Uint32())
+		x, reserved := f.Big()
+		if reserved || x.Sign() == 0 {
+			continue
+		}
+		if got, acc := NewFFromBig(x); got != f || acc != big.Exact {
+			t.Errorf("0x%08X: round-trip mismatch; got 0x%08X (%v)", f.Bits(), got.Bits(), acc)
+		}
+		s := f.Text('g', -1)
+		y, _, err := big.ParseFloat(s, 10, 1000, big.ToNearestEven)
+		if err != nil {
+			t.Errorf("0x%08X: unable to parse %q; %v", f.Bits(), s, err)
+			continue
+		}
+		if got, _ := NewFFromBig(y); got != f {
+			t.Errorf("0x%08X: text round-trip mismatch; %q is 0x%08X", f.Bits(), s, got.Bits())
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package vax
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Positive and negative largest number, zero and reserved operand of the
+// G_floating format.
+var (
+	// +0x0.FFFFFFFFFFFFF8p1023 (about 8.988466e+307)
+	GMax = G{bits: 0x7FFFFFFFFFFFFFFF}
+	// -0x0.FFFFFFFFFFFFF8p1023
+	GNegMax = G{bits: 0xFFFFFFFFFFFFFFFF}
+	// zero
+	GZero = G{bits: 0x0000000000000000}
+	// reserved operand
+	GReserved = G{bits: 0x8000000000000000}
+)
+
+// G is a floating-point number in VAX G_floating format.
+type G struct {
+	// Sign, exponent and fraction, in logical order.
+	//
+	//    1 bit:   sign
+	//    11 bits: exponent
+	//    52 bits: fraction
+	bits uint64
+}
+
+// NewGFromBits returns the floating-point number corresponding to the
+// G_floating binary representation, in logical order.
+func NewGFromBits(bits uint64) G {
+	return G{bits: bits}
+}
+
+// NewGFromBytes returns the floating-point number corresponding to the
+// G_floating memory layout; four 16-bit little-endian words, starting with the
+// word holding the sign and exponent.
+func NewGFromBytes(b [8]byte) G {
+	return G{bits: fromMemory(b[:]).Uint64()}
+}
+
+// NewGFromFloat32 returns the nearest G_floating number for x and the accuracy
+// of the conversion, as for NewGFromBig. NaN is converted to the reserved
+// operand.
+func NewGFromFloat32(x float32) (G, big.Accuracy) {
+	return new(Context).NewGFromFloat32(x)
+}
+
+// NewGFromFloat64 returns the nearest G_floating number for x and the accuracy
+// of the conversion, as for NewGFromBig. NaN is converted to the reserved
+// operand.
+func NewGFromFloat64(x float64) (G, big.Accuracy) {
+	return new(Context).NewGFromFloat64(x)
+}
+
+// NewGFromBig returns the nearest G_floating number for x and the accuracy of
+// the conversion, using round half away from zero as VAX hardware. Values too
+// small in magnitude are converted to zero or to the smallest number of the same
+// sign, whichever is nearest, and values too large in magnitude, including
+// +-Inf, to the reserved operand (reported as Above for positive and Below for
+// negative values).
+func NewGFromBig(x *big.Float) (G, big.Accuracy) {
+	return new(Context).NewGFromBig(x)
+}
+
+// NewGFromFloat32 returns the nearest G_floating number for x and the accuracy
+// of the conversion, as for NewGFromFloat32. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewGFromFloat32(x float32) (G, big.Accuracy) {
+	return ctx.NewGFromFloat64(float64(x))
+}
+
+// NewGFromFloat64 returns the nearest G_floating number for x and the accuracy
+// of the conversion, as for NewGFromFloat64. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewGFromFloat64(x float64) (G, big.Accuracy) {
+	if ctx.nan(x) {
+		return GReserved, big.Exact
+	}
+	return ctx.NewGFromBig(big.NewFloat(x))
+}
+
+// NewGFromBig returns the nearest G_floating number for x and the accuracy of
+// the conversion, as for NewGFromBig. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) NewGFromBig(x *big.Float) (G, big.Accuracy) {
+	bits, acc := ctx.encode(gFormat, x)
+	return G{bits: bits.Uint64()}, acc
+}
+
+// Bits returns the G_floating binary representation of f, in logical order.
+func (f G) Bits() uint64 {
+	return f.bits
+}
+
+// Bytes returns the G_floating memory layout of f; four 16-bit little-endian
+// words, starting with the word holding the sign and exponent.
+func (f G) Bytes() [8]byte {
+	var b [8]byte
+	copy(b[:], toMemory(new(big.Int).SetUint64(f.bits), 64))
+	return b
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion. The reserved operand is converted to NaN.
+func (f G) Float32() (float32, big.Accuracy) {
+	return new(Context).GFloat32(f)
+}
+
+// GFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) GFloat32(f G) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion, which is exact unless f is smaller in magnitude than the smallest
+// normal float64. The reserved operand is converted to NaN.
+func (f G) Float64() (float64, big.Accuracy) {
+	return new(Context).GFloat64(f)
+}
+
+// GFloat64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) GFloat64(f G) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is the reserved operand.
+func (f G) Big() (x *big.Float, reserved bool) {
+	return gFormat.decode(new(big.Int).SetUint64(f.bits))
+}
+
+// Signbit reports whether f is negative or the reserved operand.
+func (f G) Signbit() bool {
+	// first bit is sign bit
+	return f.bits&0x8000000000000000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f G) Exp() int {
+	// 11 bit exponent
+	return int(f.bits & 0x7FF0000000000000 >> 52)
+}
+
+// Frac returns the fraction of f.
+func (f G) Frac() uint64 {
+	// 52 bit fraction
+	return f.bits & 0x000FFFFFFFFFFFFF
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary to identify f uniquely. The reserved operand is
+// represented as "NaN".
+func (f G) Text(fmt byte, prec int) string {
+	x, reserved := f.Big()
+	return gFormat.text(x, reserved, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which identifies f
+// uniquely, as for f.Text('g', -1).
+func (f G) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f G) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
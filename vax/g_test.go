@@ -0,0 +1,80 @@
+package vax
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewGFromFloat64(t *testing.T) {
+	golden := []struct {
+		in   float64
+		want uint64
+		acc  big.Accuracy
+	}{
+		// Special numbers.
+		{in: 0, want: 0x0000000000000000, acc: big.Exact},
+		{in: math.NaN(), want: 0x8000000000000000, acc: big.Exact},
+		{in: math.Inf(-1), want: 0x8000000000000000, acc: big.Below},
+
+		{in: 1, want: 0x4010000000000000, acc: big.Exact},
+		{in: -math.Pi, want: 0xC02921FB54442D18, acc: big.Exact},
+		// Overflow.
+		{in: 0x1p1023, want: 0x8000000000000000, acc: big.Above},
+		{in: 0x1.FFFFFFFFFFFFFp1022, want: 0x7FFFFFFFFFFFFFFF, acc: big.Exact},
+		// Underflow.
+		{in: 0x1p-1024, want: 0x0010000000000000, acc: big.Exact},
+		{in: 0x1p-1025, want: 0x0010000000000000, acc: big.Above},
+		{in: 0x1.FFFFFFFFFFFFp-1026, want: 0x0000000000000000, acc: big.Below},
+		{in: -math.SmallestNonzeroFloat64, want: 0x0000000000000000, acc: big.Above},
+	}
+	for _, g := range golden {
+		f, acc := NewGFromFloat64(g.in)
+		if f.Bits() != g.want {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X", g.in, g.want, f.Bits())
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestGFloat64(t *testing.T) {
+	// Normal float64 numbers within range are G_floating numbers with an
+	// exponent larger by 2.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		bits := r.Uint64()
+		if exp := bits >> 52 & 0x7FF; exp == 0 || exp > 0x7FD {
+			continue
+		}
+		x := math.Float64frombits(bits)
+		want := bits + 2<<52
+		f, acc := NewGFromFloat64(x)
+		if f.Bits() != want || acc != big.Exact {
+			t.Errorf("%v: bits mismatch; expected 0x%016X, got 0x%016X (%v)", x, want, f.Bits(), acc)
+		}
+		if y, acc := f.Float64(); y != x || acc != big.Exact {
+			t.Errorf("0x%016X: float64 mismatch; expected %v, got %v (%v)", want, x, y, acc)
+		}
+	}
+	// The smallest G_floating numbers are denormalized float64 numbers.
+	if x, acc := NewGFromBits(0x0010000000000001).Float64(); x != 0x1p-1024 || acc != big.Below {
+		t.Errorf("float64 mismatch; expected %v, got %v (%v)", 0x1p-1024, x, acc)
+	}
+}
+
+func TestGBytes(t *testing.T) {
+	f := NewGFromBits(0x402921FB54442D18)
+	mem := [8]byte{0x29, 0x40, 0xFB, 0x21, 0x44, 0x54, 0x18, 0x2D}
+	if got := f.Bytes(); got != mem {
+		t.Errorf("memory layout mismatch; expected % X, got % X", mem, got)
+	}
+	if got := NewGFromBytes(mem); got != f {
+		t.Errorf("bits mismatch; expected 0x%016X, got 0x%016X", f.Bits(), got.Bits())
+	}
+	if got, want := f.Text('g', -1), "3.141592653589793"; got != want {
+		t.Errorf("text mismatch; expected %q, got %q", want, got)
+	}
+}
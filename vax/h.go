@@ -0,0 +1,195 @@
+package vax
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Positive and negative largest number, zero and reserved operand of the
+// H_floating format.
+var (
+	// +0x0.FFFFFFFFFFFFFFFFFFFFFFFFFFFF8p16383 (about 5.948657e+4931)
+	HMax = H{a: 0x7FFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF}
+	// -0x0.FFFFFFFFFFFFFFFFFFFFFFFFFFFF8p16383
+	HNegMax = H{a: 0xFFFFFFFFFFFFFFFF, b: 0xFFFFFFFFFFFFFFFF}
+	// zero
+	HZero = H{a: 0x0000000000000000, b: 0x0000000000000000}
+	// reserved operand
+	HReserved = H{a: 0x8000000000000000, b: 0x0000000000000000}
+)
+
+// H is a floating-point number in VAX H_floating format.
+type H struct {
+	// Sign, exponent and fraction, in logical order.
+	//
+	//    1 bit:    sign
+	//    15 bits:  exponent
+	//    112 bits: fraction
+	a uint64
+	b uint64
+}
+
+// NewHFromBits returns the floating-point number corresponding to the
+// H_floating binary representation, in logical order.
+func NewHFromBits(a, b uint64) H {
+	return H{a: a, b: b}
+}
+
+// NewHFromBytes returns the floating-point number corresponding to the
+// H_floating memory layout; eight 16-bit little-endian words, starting with the
+// word holding the sign and exponent.
+func NewHFromBytes(b [16]byte) H {
+	return newHFromInt(fromMemory(b[:]))
+}
+
+// NewHFromFloat32 returns the nearest H_floating number for x and the accuracy
+// of the conversion, as for NewHFromBig. NaN is converted to the reserved
+// operand.
+func NewHFromFloat32(x float32) (H, big.Accuracy) {
+	return new(Context).NewHFromFloat32(x)
+}
+
+// NewHFromFloat64 returns the nearest H_floating number for x and the accuracy
+// of the conversion, as for NewHFromBig. NaN is converted to the reserved
+// operand.
+func NewHFromFloat64(x float64) (H, big.Accuracy) {
+	return new(Context).NewHFromFloat64(x)
+}
+
+// NewHFromBig returns the nearest H_floating number for x and the accuracy of
+// the conversion, using round half away from zero as VAX hardware. Values too
+// small in magnitude are converted to zero or to the smallest number of the same
+// sign, whichever is nearest, and values too large in magnitude, including
+// +-Inf, to the reserved operand (reported as Above for positive and Below for
+// negative values).
+func NewHFromBig(x *big.Float) (H, big.Accuracy) {
+	return new(Context).NewHFromBig(x)
+}
+
+// NewHFromFloat32 returns the nearest H_floating number for x and the accuracy
+// of the conversion, as for NewHFromFloat32. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewHFromFloat32(x float32) (H, big.Accuracy) {
+	return ctx.NewHFromFloat64(float64(x))
+}
+
+// NewHFromFloat64 returns the nearest H_floating number for x and the accuracy
+// of the conversion, as for NewHFromFloat64. The exception flags raised are
+// recorded in ctx.
+func (ctx *Context) NewHFromFloat64(x float64) (H, big.Accuracy) {
+	if ctx.nan(x) {
+		return HReserved, big.Exact
+	}
+	return ctx.NewHFromBig(big.NewFloat(x))
+}
+
+// NewHFromBig returns the nearest H_floating number for x and the accuracy of
+// the conversion, as for NewHFromBig. The exception flags raised are recorded
+// in ctx.
+func (ctx *Context) NewHFromBig(x *big.Float) (H, big.Accuracy) {
+	bits, acc := ctx.encode(hFormat, x)
+	return newHFromInt(bits), acc
+}
+
+// Bits returns the H_floating binary representation of f, in logical order.
+func (f H) Bits() (a, b uint64) {
+	return f.a, f.b
+}
+
+// Bytes returns the H_floating memory layout of f; eight 16-bit little-endian
+// words, starting with the word holding the sign and exponent.
+func (f H) Bytes() [16]byte {
+	var b [16]byte
+	copy(b[:], toMemory(f.toInt(), 128))
+	return b
+}
+
+// Float32 returns the float32 value nearest to f and the accuracy of the
+// conversion. The reserved operand is converted to NaN.
+func (f H) Float32() (float32, big.Accuracy) {
+	return new(Context).HFloat32(f)
+}
+
+// HFloat32 returns the float32 value nearest to f and the accuracy of the
+// conversion, as for f.Float32. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) HFloat32(f H) (float32, big.Accuracy) {
+	return ctx.float32(f.Big())
+}
+
+// Float64 returns the float64 value nearest to f and the accuracy of the
+// conversion. The reserved operand is converted to NaN.
+func (f H) Float64() (float64, big.Accuracy) {
+	return new(Context).HFloat64(f)
+}
+
+// HFloat64 returns the float64 value nearest to f and the accuracy of the
+// conversion, as for f.Float64. The exception flags raised are recorded in
+// ctx; the reserved operand raises the invalid operation exception.
+func (ctx *Context) HFloat64(f H) (float64, big.Accuracy) {
+	return ctx.float64(f.Big())
+}
+
+// Big returns the multi-precision floating-point number representation of f and
+// a boolean indicating whether f is the reserved operand.
+func (f H) Big() (x *big.Float, reserved bool) {
+	return hFormat.decode(f.toInt())
+}
+
+// Signbit reports whether f is negative or the reserved operand.
+func (f H) Signbit() bool {
+	// first bit is sign bit
+	return f.a&0x8000000000000000 != 0
+}
+
+// Exp returns the exponent of f.
+func (f H) Exp() int {
+	// 15 bit exponent
+	return int(f.a & 0x7FFF000000000000 >> 48)
+}
+
+// Frac returns the fraction of f.
+func (f H) Frac() (uint64, uint64) {
+	// 112 bit fraction; 48 bits in a and 64 bits in b
+	return f.a & 0x0000FFFFFFFFFFFF, f.b
+}
+
+// Text converts the floating-point number f to a string, according to the format
+// fmt and precision prec, as for strconv.FormatFloat; fmt is one of 'b', 'e',
+// 'E', 'f', 'g', 'G', 'x' or 'X'. The special precision -1 uses the smallest
+// number of digits necessary to identify f uniquely. The reserved operand is
+// represented as "NaN".
+func (f H) Text(fmt byte, prec int) string {
+	x, reserved := f.Big()
+	return hFormat.text(x, reserved, fmt, prec)
+}
+
+// String returns the shortest decimal representation of f which identifies f
+// uniquely, as for f.Text('g', -1).
+func (f H) String() string {
+	return f.Text('g', -1)
+}
+
+// Format implements fmt.Formatter. It accepts the verbs and flags of float64
+// values; 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v', with width,
+// precision and the '+', '-', ' ', '0' and '#' flags.
+func (f H) Format(s fmt.State, verb rune) {
+	strconv.Format(s, verb, f)
+}
+
+// toInt returns the binary representation of f as an integer.
+func (f H) toInt() *big.Int {
+	x := new(big.Int).SetUint64(f.a)
+	x.Lsh(x, 64)
+	return x.Or(x, new(big.Int).SetUint64(f.b))
+}
+
+// newHFromInt returns the floating-point number of the binary representation
+// bits.
+func newHFromInt(bits *big.Int) H {
+	b := new(big.Int).And(bits, mask(64)).Uint64()
+	a := new(big.Int).Rsh(bits, 64).Uint64()
+	return H{a: a, b: b}
+}
@@ -0,0 +1,68 @@
+package vax
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewHFromBig(t *testing.T) {
+	pi, _, _ := big.ParseFloat("3.14159265358979323846264338327950288", 10, 200, big.ToNearestEven)
+	golden := []struct {
+		in   *big.Float
+		a, b uint64
+		acc  big.Accuracy
+	}{
+		{in: big.NewFloat(1), a: 0x4001000000000000, b: 0x0000000000000000, acc: big.Exact},
+		{in: big.NewFloat(-2), a: 0xC002000000000000, b: 0x0000000000000000, acc: big.Exact},
+		{in: pi, a: 0x4002921FB54442D1, b: 0x8469898CC51701B8, acc: big.Below},
+		{in: new(big.Float).SetInf(false), a: 0x8000000000000000, b: 0x0000000000000000, acc: big.Above},
+		{in: new(big.Float).SetMantExp(big.NewFloat(-1), -16385), a: 0x8001000000000000, b: 0x0000000000000000, acc: big.Below},
+		{in: new(big.Float).SetMantExp(big.NewFloat(1), -16386), a: 0x0000000000000000, b: 0x0000000000000000, acc: big.Below},
+		{in: new(big.Float).SetMantExp(big.NewFloat(1), -16384), a: 0x0001000000000000, b: 0x0000000000000000, acc: big.Exact},
+	}
+	for _, g := range golden {
+		f, acc := NewHFromBig(g.in)
+		if a, b := f.Bits(); a != g.a || b != g.b {
+			t.Errorf("%v: bits mismatch; expected 0x%016X%016X, got 0x%016X%016X", g.in, g.a, g.b, a, b)
+		}
+		if acc != g.acc {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", g.in, g.acc, acc)
+		}
+	}
+}
+
+func TestHFloat64(t *testing.T) {
+	// Every finite float64 is a H_floating number.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		x := math.Float64frombits(r.Uint64())
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			continue
+		}
+		f, acc := NewHFromFloat64(x)
+		if acc != big.Exact {
+			t.Errorf("%v: accuracy mismatch; expected %v, got %v", x, big.Exact, acc)
+		}
+		if y, acc := f.Float64(); y != x || acc != big.Exact {
+			a, b := f.Bits()
+			t.Errorf("0x%016X%016X: float64 mismatch; expected %v, got %v (%v)", a, b, x, y, acc)
+		}
+	}
+}
+
+func TestHBytes(t *testing.T) {
+	f := NewHFromBits(0x4001000000000000, 0x0000000000000001)
+	mem := [16]byte{0x01, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00}
+	if got := f.Bytes(); got != mem {
+		t.Errorf("memory layout mismatch; expected % X, got % X", mem, got)
+	}
+	if got := NewHFromBytes(mem); got != f {
+		a, b := got.Bits()
+		t.Errorf("bits mismatch; expected 0x4001000000000000_0000000000000001, got 0x%016X_%016X", a, b)
+	}
+	if got, want := HReserved.Text('g', -1), "NaN"; got != want {
+		t.Errorf("text mismatch; expected %q, got %q", want, got)
+	}
+}
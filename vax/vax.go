@@ -0,0 +1,277 @@
+// Package vax implements encoding and decoding of the DEC VAX F_floating,
+// D_floating, G_floating and H_floating floating-point formats.
+//
+// A VAX floating-point number is (-1)^sign * 0.1frac * 2^(exp-bias), with a
+// hidden leading fraction bit and an exponent bias of 128, 1024 or 16384. There
+// are neither subnormal numbers nor infinities; an exponent of zero encodes zero
+// if the sign bit is clear, and a reserved operand, which traps on use, if the
+// sign bit is set.
+//
+// VAX floating-point numbers are stored in memory as a sequence of 16-bit
+// little-endian words, starting with the word which holds the sign and exponent
+// (PDP-endian). The Bits methods use the logical order of the bits, with the
+// sign in the most significant bit followed by the exponent and fraction, and
+// the Bytes methods use the memory layout.
+//
+// https://nssdc.gsfc.nasa.gov/nssdc/formats/VAXFloatingPoint.htm
+package vax
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/mewmew/float/fenv"
+	"github.com/mewmew/float/internal/rounding"
+	"github.com/mewmew/float/internal/strconv"
+)
+
+// Context specifies the detection of tininess, and accumulates the IEEE 754
+// exception flags raised by the conversions performed in the context. The zero
+// value detects tininess after rounding and has no flags raised.
+//
+// Values too large in magnitude, converted to the reserved operand on floating
+// overflow, raise the overflow exception, and values too small in magnitude,
+// converted to zero or to the smallest number, the underflow exception. Infinities and NaN converted to the
+// reserved operand, and the reserved operand converted to NaN, raise the
+// invalid operation exception.
+type Context struct {
+	// Detection of tininess for the underflow exception.
+	Tininess fenv.Tininess
+	// Exception flags raised; flags are never cleared by operations.
+	Flags fenv.Flags
+}
+
+// nan records in ctx the invalid operation exception if x is NaN, and reports
+// whether x is NaN.
+func (ctx *Context) nan(x float64) bool {
+	if math.IsNaN(x) {
+		ctx.Flags |= fenv.Invalid
+		return true
+	}
+	return false
+}
+
+// float32 returns the float32 value nearest to the VAX floating-point number x
+// and the accuracy of the conversion. The reserved operand is converted to NaN.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) float32(x *big.Float, reserved bool) (float32, big.Accuracy) {
+	if reserved {
+		ctx.Flags |= fenv.Invalid
+		return float32(math.NaN()), big.Exact
+	}
+	y, acc := x.Float32()
+	if x.Sign() != 0 {
+		ctx.Flags |= rounding.Flags(x, acc, 24, -126, 127, big.ToNearestEven, ctx.Tininess)
+	}
+	return y, acc
+}
+
+// float64 returns the float64 value nearest to the VAX floating-point number x
+// and the accuracy of the conversion. The reserved operand is converted to NaN.
+// The exception flags raised are recorded in ctx.
+func (ctx *Context) float64(x *big.Float, reserved bool) (float64, big.Accuracy) {
+	if reserved {
+		ctx.Flags |= fenv.Invalid
+		return math.NaN(), big.Exact
+	}
+	y, acc := x.Float64()
+	if x.Sign() != 0 {
+		ctx.Flags |= rounding.Flags(x, acc, 53, -1022, 1023, big.ToNearestEven, ctx.Tininess)
+	}
+	return y, acc
+}
+
+// format is a VAX floating-point format.
+type format struct {
+	// Number of exponent bits.
+	expBits uint
+	// Number of fraction bits, excluding the hidden bit.
+	fracBits uint
+	// Exponent bias.
+	bias int
+}
+
+// VAX floating-point formats.
+var (
+	// F_floating format.
+	fFormat = format{expBits: 8, fracBits: 23, bias: 128}
+	// D_floating format.
+	dFormat = format{expBits: 8, fracBits: 55, bias: 128}
+	// G_floating format.
+	gFormat = format{expBits: 11, fracBits: 52, bias: 1024}
+	// H_floating format.
+	hFormat = format{expBits: 15, fracBits: 112, bias: 16384}
+)
+
+// width returns the number of bits of the format.
+func (f format) width() uint {
+	return 1 + f.expBits + f.fracBits
+}
+
+// precision returns the number of bits of the significand, including the
+// hidden bit.
+func (f format) precision() uint {
+	return f.fracBits + 1
+}
+
+// minExp returns the exponent of the smallest number, 0.1 * 2^minExp.
+func (f format) minExp() int {
+	return 1 - f.bias
+}
+
+// maxExp returns the exponent of the largest number, 0.11...1 * 2^maxExp.
+func (f format) maxExp() int {
+	return 1<<f.expBits - 1 - f.bias
+}
+
+// reserved returns the binary representation of the reserved operand.
+func (f format) reserved() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), f.width()-1)
+}
+
+// decode returns the multi-precision floating-point number representation of
+// the binary representation bits, and a boolean indicating whether it is the
+// reserved operand.
+func (f format) decode(bits *big.Int) (x *big.Float, reserved bool) {
+	x = new(big.Float).SetPrec(f.precision()).SetMode(big.ToNearestAway)
+	neg := bits.Bit(int(f.width()-1)) != 0
+	exp := int(new(big.Int).Rsh(bits, f.fracBits).Uint64() & (1<<f.expBits - 1))
+	if exp == 0 {
+		// Zero, with any fraction, or reserved operand.
+		return x, neg
+	}
+	// (-1)^sign * 2^(exp-bias) * 0.1frac_2
+	mant := new(big.Int).And(bits, mask(f.fracBits))
+	mant.SetBit(mant, int(f.fracBits), 1)
+	x.SetInt(mant)
+	x.SetMantExp(x, exp-f.bias-int(f.precision()))
+	if neg {
+		x.Neg(x)
+	}
+	return x, false
+}
+
+// encode returns the binary representation of the number nearest to x, using
+// round half away from zero as VAX hardware, and the accuracy of the
+// conversion. The exception flags raised are recorded in ctx.
+//
+// Values too small in magnitude after rounding are converted to zero or to the
+// smallest number of the same sign, whichever is nearest; halfway values are
+// converted to the smallest number. Values too large in magnitude after
+// rounding, including +-Inf, are converted to the reserved operand, as on
+// floating overflow; with an accuracy of Above for positive and Below for
+// negative values.
+func (ctx *Context) encode(f format, x *big.Float) (bits *big.Int, acc big.Accuracy) {
+	overflow := big.Above
+	if x.Signbit() {
+		overflow = big.Below
+	}
+	switch {
+	case x.IsInf():
+		ctx.Flags |= fenv.Invalid
+		return f.reserved(), overflow
+	case x.Sign() == 0:
+		return new(big.Int), big.Exact
+	}
+	y := new(big.Float).SetPrec(f.precision()).SetMode(big.ToNearestAway).Set(x)
+	acc = y.Acc()
+	exp := y.MantExp(nil)
+	switch {
+	case exp > f.maxExp():
+		// Overflow.
+		ctx.Flags |= fenv.Overflow | fenv.Inexact
+		return f.reserved(), overflow
+	case exp < f.minExp():
+		// Underflow; |x| is rounded to 0.1 * 2^minExp if at least half of it,
+		// i.e. 0.1 * 2^(minExp-1), and to zero otherwise.
+		bits, acc = new(big.Int), big.Below
+		if x.MantExp(nil) >= f.minExp()-1 {
+			bits.SetInt64(int64(f.minExp() + f.bias))
+			bits.Lsh(bits, f.fracBits)
+			acc = big.Above
+		}
+		if x.Signbit() {
+			if bits.Sign() != 0 {
+				bits.SetBit(bits, int(f.width()-1), 1)
+			}
+			acc = -acc
+		}
+		ctx.Flags |= rounding.Flags(x, acc, f.precision(), f.minExp()-1, f.maxExp()-1, big.ToNearestAway, ctx.Tininess)
+		return bits, acc
+	}
+	// The most significant bit of 0.1frac_2 * 2^exp has exponent exp-1.
+	ctx.Flags |= rounding.Flags(x, acc, f.precision(), f.minExp()-1, f.maxExp()-1, big.ToNearestAway, ctx.Tininess)
+	// 0.1frac_2 * 2^exp
+	mant, _ := y.SetMantExp(new(big.Float).Abs(y), int(f.precision())-exp).Int(nil)
+	mant.SetBit(mant, int(f.fracBits), 0)
+	bits = big.NewInt(int64(exp + f.bias))
+	bits.Lsh(bits, f.fracBits)
+	bits.Or(bits, mant)
+	if x.Signbit() {
+		bits.SetBit(bits, int(f.width()-1), 1)
+	}
+	return bits, acc
+}
+
+// text converts the VAX floating-point number x to a string, as for
+// strconv.FormatFloat. The special precision -1 uses the smallest number of
+// digits necessary to convert back to x. The reserved operand is represented as
+// "NaN".
+func (f format) text(x *big.Float, reserved bool, fmt byte, prec int) string {
+	if reserved {
+		return "NaN"
+	}
+	if x.Sign() == 0 {
+		return strconv.FormatBigRange(x, x, x, true, fmt, prec)
+	}
+	// Halfway points to the neighbouring numbers; the smaller neighbour of a
+	// power of two has a 2 times smaller unit in the last place, and there are
+	// no denormalized numbers below the smallest number.
+	lsb := x.MantExp(nil) - int(f.precision())
+	half := new(big.Float).SetMantExp(big.NewFloat(1), lsb-1)
+	lowerHalf := half
+	if x.MinPrec() == 1 {
+		lowerHalf = new(big.Float).SetMantExp(half, -1)
+	}
+	lower := new(big.Float).SetPrec(f.precision() + 8)
+	upper := new(big.Float).SetPrec(f.precision() + 8)
+	if x.Signbit() {
+		lower.Sub(x, half)
+		upper.Add(x, lowerHalf)
+	} else {
+		lower.Sub(x, lowerHalf)
+		upper.Add(x, half)
+	}
+	// Round half away from zero rounds the bound nearer to zero to x, but the
+	// other bound to the neighbouring number; both are excluded.
+	return strconv.FormatBigRange(x, lower, upper, false, fmt, prec)
+}
+
+// toMemory returns the memory layout of the n-bit logical representation bits;
+// 16-bit little-endian words, starting with the most significant word.
+func toMemory(bits *big.Int, n uint) []byte {
+	words := bits.Bytes()
+	b := make([]byte, n/8)
+	// Big-endian words, right-aligned.
+	copy(b[len(b)-len(words):], words)
+	for i := 0; i < len(b); i += 2 {
+		b[i], b[i+1] = b[i+1], b[i]
+	}
+	return b
+}
+
+// fromMemory returns the logical representation of the memory layout b; 16-bit
+// little-endian words, starting with the most significant word.
+func fromMemory(b []byte) *big.Int {
+	words := make([]byte, len(b))
+	for i := 0; i < len(b); i += 2 {
+		words[i], words[i+1] = b[i+1], b[i]
+	}
+	return new(big.Int).SetBytes(words)
+}
+
+// mask returns 2^n - 1.
+func mask(n uint) *big.Int {
+	m := new(big.Int).Lsh(big.NewInt(1), n)
+	return m.Sub(m, big.NewInt(1))
+}
@@ -0,0 +1,52 @@
+package vax
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mewmew/float/fenv"
+)
+
+func TestFlags(t *testing.T) {
+	tenth, _ := NewDFromFloat64(0.1)
+	golden := []struct {
+		name          string
+		op            func(ctx *Context)
+		after, before fenv.Flags
+	}{
+		{name: "NewFFromFloat64(0.5)", op: func(ctx *Context) { ctx.NewFFromFloat64(0.5) }},
+		{name: "NewFFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewFFromFloat64(0.1) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "NewGFromFloat64(0.1)", op: func(ctx *Context) { ctx.NewGFromFloat64(0.1) }},
+		// Values too large in magnitude are converted to the reserved operand.
+		{name: "NewFFromFloat64(0x1p127)", op: func(ctx *Context) { ctx.NewFFromFloat64(0x1p127) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "NewDFromFloat32(-Inf)", op: func(ctx *Context) { ctx.NewDFromFloat32(float32(math.Inf(-1))) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "NewHFromFloat64(NaN)", op: func(ctx *Context) { ctx.NewHFromFloat64(math.NaN()) }, after: fenv.Invalid, before: fenv.Invalid},
+		// Values too small in magnitude are converted to zero or to the
+		// smallest number, 2^-128.
+		{name: "NewFFromFloat64(0x1p-129)", op: func(ctx *Context) { ctx.NewFFromFloat64(0x1p-129) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "NewFFromFloat64(0x1p-130)", op: func(ctx *Context) { ctx.NewFFromFloat64(0x1p-130) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		// 2^-128 - 2^-153 rounds to 2^-128, and is tiny only before rounding.
+		{name: "NewFFromFloat64(0x1.FFFFFFp-129)", op: func(ctx *Context) { ctx.NewFFromFloat64(0x1.FFFFFFp-129) }, after: fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "NewGFromFloat64(0x1p-1024)", op: func(ctx *Context) { ctx.NewGFromFloat64(0x1p-1024) }},
+		// Conversions to float32 and float64.
+		{name: "DFloat32(0.1)", op: func(ctx *Context) { ctx.DFloat32(tenth) }, after: fenv.Inexact, before: fenv.Inexact},
+		{name: "GFloat64(0x1p-1024)", op: func(ctx *Context) { ctx.GFloat64(NewGFromBits(0x0010000000000000)) }},
+		{name: "GFloat64(0x1.0000000000001p-1024)", op: func(ctx *Context) { ctx.GFloat64(NewGFromBits(0x0010000000000001)) }, after: fenv.Underflow | fenv.Inexact, before: fenv.Underflow | fenv.Inexact},
+		{name: "HFloat64(0x0.8p16383)", op: func(ctx *Context) { ctx.HFloat64(NewHFromBits(0x7FFF000000000000, 0)) }, after: fenv.Overflow | fenv.Inexact, before: fenv.Overflow | fenv.Inexact},
+		{name: "FFloat32(reserved)", op: func(ctx *Context) { ctx.FFloat32(FReserved) }, after: fenv.Invalid, before: fenv.Invalid},
+		{name: "FFloat64(reserved)", op: func(ctx *Context) { ctx.FFloat64(FReserved) }, after: fenv.Invalid, before: fenv.Invalid},
+	}
+	for _, g := range golden {
+		for _, tininess := range []fenv.Tininess{fenv.AfterRounding, fenv.BeforeRounding} {
+			want := g.after
+			if tininess == fenv.BeforeRounding {
+				want = g.before
+			}
+			ctx := &Context{Tininess: tininess}
+			g.op(ctx)
+			if want != ctx.Flags {
+				t.Errorf("%s (%v): flags mismatch; expected %v, got %v", g.name, tininess, want, ctx.Flags)
+			}
+		}
+	}
+}